@@ -72,6 +72,10 @@ func NewApp() *App {
 	// 注入任务完成处理器：AckJob 成功且 Source=workflow 时，回调 Workflow.ReportNodeCompleted
 	executorModule.RegisterJobCompletionHandler("workflow", workflowModule.GetJobCompletionHandler())
 
+	// 注入 http 节点请求执行器：由 executor 内部 worker 领取并发起请求；header 密钥引用从配置中心读取
+	executorModule.RegisterInternalJobHandler(workflow.HTTPNodeMethod, workflowModule.GetHTTPNodeHandler())
+	workflowModule.SetConfigReader(configModule.Client)
+
 	return &App{
 		ConfigModule:    configModule,
 		RegistryModule:  registryModule,
@@ -125,6 +125,9 @@ func main() {
 		appRoot.ExecutorModule.StopInternalWorker()
 	})
 
+	// 启动工作流定时调度轮询：每个实例都轮询，同一触发点由调度行锁保证只触发一次
+	appRoot.WorkflowModule.StartScheduleTicker()
+
 	// 初始化默认超级管理员（当 user_admin 表为空时自动创建 admin/admin）
 	if err := appRoot.UserModule.EnsureBootstrapSuperAdmin(context.Background()); err != nil {
 		configures.Logger.Panic(fmt.Sprintf("初始化默认超级管理员失败: %v", err))
@@ -187,6 +190,37 @@ func main() {
 	}
 	base.Logger.Info("已注册任务执行器清理任务，每天凌晨 3:00 执行")
 
+	// 注册工作流实例保留任务（每天凌晨 3:30 执行）：按保留策略归档或导出终态实例后清理。
+	// 分布式模式只在一个实例上执行；即便多个实例同时执行，批次也按 SKIP LOCKED 错开
+	workflowRetentionTask, err := scheduler.NewCronTask(
+		"工作流实例保留",
+		"30 3 * * *", // 每天凌晨 3:30
+		scheduler.TaskExecuteModeDistributed,
+		2*time.Hour,
+		func(ctx context.Context) error {
+			reports, err := appRoot.WorkflowModule.RunRetention(ctx, time.Now())
+			if err != nil {
+				base.Logger.WithErr(err).Error("工作流实例保留任务执行失败")
+				return err
+			}
+			var instances, checkpoints int64
+			for _, r := range reports {
+				instances += r.Instances
+				checkpoints += r.Checkpoints
+			}
+			base.Logger.WithField("policies", len(reports)).WithField("instances", instances).
+				WithField("checkpoints", checkpoints).Info("工作流实例保留任务执行完成")
+			return nil
+		},
+	)
+	if err != nil {
+		configures.Logger.Panic(fmt.Sprintf("创建工作流实例保留任务失败: %v", err))
+	}
+	if err := base.Scheduler.AddTask(workflowRetentionTask); err != nil {
+		configures.Logger.Panic(fmt.Sprintf("添加工作流实例保留任务失败: %v", err))
+	}
+	base.Logger.Info("已注册工作流实例保留任务，每天凌晨 3:30 执行")
+
 	// 创建 Fiber 应用
 	fiberApp := fiber_handle.GetApp()
 
@@ -1,6 +1,6 @@
 // 本文件定义工作流模块的进程配置。
 //
-// 职责：承载回调投递方式等 workflow 配置。
+// 职责：承载回调投递方式、http 节点密钥放行范围等 workflow 配置。
 // 边界：只描述配置结构，不执行回调或改变调度行为。
 package config
 
@@ -18,4 +18,21 @@ type WorkflowConfig struct {
 	//
 	// 这是过渡开关，稳定运行一个版本后应连同 sync 分支一并删除。
 	CallbackMode string `yaml:"callback-mode" json:"callback-mode"`
+
+	// HTTPSecrets http 节点在 header 中引用配置中心密钥（${config:key#path}）的放行规则。
+	// 每个引用都须命中至少一条规则；未配置时任何引用都被拒绝，定义创建与请求派发均会失败。
+	HTTPSecrets []HTTPSecretRule `yaml:"http-secrets" json:"http-secrets"`
+}
+
+// HTTPSecretRule 一条密钥放行规则：在 Env 环境、DefCodes 定义中，允许把 KeyPrefixes 开头的配置
+// 发往 Hosts 中的主机。KeyPrefixes 与 Hosts 为空的规则不放行任何引用。
+type HTTPSecretRule struct {
+	// Env 生效的环境，空表示所有环境
+	Env string `yaml:"env" json:"env"`
+	// DefCodes 生效的定义编码，空表示所有定义
+	DefCodes []string `yaml:"def-codes" json:"def-codes"`
+	// KeyPrefixes 允许引用的配置 key 前缀
+	KeyPrefixes []string `yaml:"key-prefixes" json:"key-prefixes"`
+	// Hosts 允许携带密钥的请求主机（不含端口），"*.example.com" 匹配其任意子域名
+	Hosts []string `yaml:"hosts" json:"hosts"`
 }
@@ -367,6 +367,7 @@ func TestWorkflowClientMethods(t *testing.T) {
 
 	// 验证方法存在（编译期检查）
 	_ = client.CreateDef
+	_ = client.ValidateDef
 	_ = client.StartWorkflow
 	_ = client.StartWorkflowWithJSON
 	_ = client.ReportNodeCompleted
@@ -374,6 +375,22 @@ func TestWorkflowClientMethods(t *testing.T) {
 	_ = client.RollbackToNode
 	_ = client.GetExecutionTrail
 	_ = client.GetExecutionTrailWithOptions
+	_ = client.Watch
+	_ = client.WatchDef
+	_ = client.CompensateInstance
+	_ = client.ForkInstance
+	_ = client.PauseInstance
+	_ = client.ResumeInstance
+	_ = client.PauseInstancesByDef
+	_ = client.ResumeInstancesByDef
+	_ = client.MigrateInstances
+	_ = client.ListInstanceMigrations
+	_ = client.RenderDef
+	_ = client.RenderInstance
+	_ = client.SendSignal
+	_ = client.SendSignalByKey
+	_ = client.StartWorkflowWithBusinessKey
+	_ = client.GetInstanceByBusinessKey
 
 	// 验证 ExecutionTrail 结构
 	trail := &ExecutionTrail{
@@ -48,6 +48,47 @@ func (c *WorkflowClient) CreateDef(ctx context.Context, code, name, dagJSON stri
 	return resp.DefId, nil
 }
 
+// DefLintIssue 定义校验问题。EdgeIndex 为 -1 表示问题不在边上
+type DefLintIssue struct {
+	Severity  string `json:"severity"` // error / warning
+	Code      string `json:"code"`
+	NodeID    string `json:"nodeId"`
+	EdgeIndex int32  `json:"edgeIndex"`
+	EdgeFrom  string `json:"edgeFrom"`
+	EdgeTo    string `json:"edgeTo"`
+	Field     string `json:"field"`
+	Message   string `json:"message"`
+}
+
+// DefLintResult 定义校验结果，Valid 表示没有 error 级问题
+type DefLintResult struct {
+	Valid  bool            `json:"valid"`
+	Issues []*DefLintIssue `json:"issues"`
+}
+
+// ValidateDef 校验 DAG 而不创建定义：按节点类型检查配置、预编译边条件、
+// 找出不可达与无法结束的节点。CreateDef 遇到 error 级问题同样会拒绝
+func (c *WorkflowClient) ValidateDef(ctx context.Context, dagJSON string) (*DefLintResult, error) {
+	resp, err := c.service.ValidateDef(ctx, &workflowpb.ValidateDefRequest{DagJson: dagJSON})
+	if err != nil {
+		return nil, WrapError(err, "validate workflow def failed")
+	}
+	result := &DefLintResult{Valid: resp.Valid, Issues: make([]*DefLintIssue, 0, len(resp.Issues))}
+	for _, issue := range resp.Issues {
+		result.Issues = append(result.Issues, &DefLintIssue{
+			Severity:  issue.Severity,
+			Code:      issue.Code,
+			NodeID:    issue.NodeId,
+			EdgeIndex: issue.EdgeIndex,
+			EdgeFrom:  issue.EdgeFrom,
+			EdgeTo:    issue.EdgeTo,
+			Field:     issue.Field,
+			Message:   issue.Message,
+		})
+	}
+	return result, nil
+}
+
 // StartWorkflow 启动工作流
 func (c *WorkflowClient) StartWorkflow(ctx context.Context, defCode string, initialData map[string]interface{}) (int64, error) {
 	initialDataJSON := "{}"
@@ -70,6 +111,30 @@ func (c *WorkflowClient) StartWorkflow(ctx context.Context, defCode string, init
 	return resp.InstanceId, nil
 }
 
+// StartWorkflowWithBusinessKey 以业务键启动工作流，可安全重试：该键在当前环境与定义编码下
+// 已有未结束的实例时返回其 ID（existed=true），不会再创建
+func (c *WorkflowClient) StartWorkflowWithBusinessKey(ctx context.Context, defCode, businessKey string, initialData map[string]interface{}) (instanceID int64, existed bool, err error) {
+	initialDataJSON := "{}"
+	if initialData != nil {
+		data, err := json.Marshal(initialData)
+		if err != nil {
+			return 0, false, WrapError(err, "marshal initial data failed")
+		}
+		initialDataJSON = string(data)
+	}
+
+	resp, err := c.service.StartWorkflow(ctx, &workflowpb.StartWorkflowRequest{
+		DefCode:         defCode,
+		InitialDataJson: initialDataJSON,
+		Env:             c.env,
+		BusinessKey:     businessKey,
+	})
+	if err != nil {
+		return 0, false, WrapError(err, "start workflow failed")
+	}
+	return resp.InstanceId, resp.Existed, nil
+}
+
 // StartWorkflowWithJSON 启动工作流（传入已序列化的初始数据 JSON）
 func (c *WorkflowClient) StartWorkflowWithJSON(ctx context.Context, defCode, initialDataJSON string) (int64, error) {
 	if initialDataJSON == "" {
@@ -157,14 +222,27 @@ type ExecutionTrail struct {
 	CurrentState  string                     `json:"currentState"`  // 当前状态 JSON
 	ActiveNodeIDs string                     `json:"activeNodeIds"` // 活动节点 ID 列表（JSON 字符串）
 	Checkpoints   []ExecutionTrailCheckpoint `json:"checkpoints"`
+	// ParentInstanceID / ParentNodeID 非零表示本实例由父实例的 subworkflow 节点拉起
+	ParentInstanceID int64                 `json:"parentInstanceId,omitempty"`
+	ParentNodeID     string                `json:"parentNodeId,omitempty"`
+	Children         []ExecutionTrailChild `json:"children,omitempty"` // subworkflow 节点拉起的子实例
+}
+
+// ExecutionTrailChild 子工作流实例摘要
+type ExecutionTrailChild struct {
+	InstanceID int64  `json:"instanceId"`
+	NodeID     string `json:"nodeId"` // 拉起该子实例的父节点 ID
+	Status     string `json:"status"`
+	CreatedAt  string `json:"createdAt"`
 }
 
 // ExecutionTrailCheckpoint 轨迹中的单步快照
 type ExecutionTrailCheckpoint struct {
-	NodeID     string                 `json:"nodeId"`
-	NodeOutput map[string]interface{} `json:"nodeOutput"`           // 节点输出
-	StateAfter map[string]interface{} `json:"stateAfter,omitempty"` // 节点执行后状态
-	CreatedAt  string                 `json:"createdAt"`
+	CheckpointID int64                  `json:"checkpointId"` // 可作为 ForkInstance 的分叉点
+	NodeID       string                 `json:"nodeId"`
+	NodeOutput   map[string]interface{} `json:"nodeOutput"`           // 节点输出
+	StateAfter   map[string]interface{} `json:"stateAfter,omitempty"` // 节点执行后状态
+	CreatedAt    string                 `json:"createdAt"`
 }
 
 type ExecutionTrailOptions struct {
@@ -202,19 +280,33 @@ func (c *WorkflowClient) GetExecutionTrailWithOptions(ctx context.Context, insta
 			stateAfter = make(map[string]interface{})
 		}
 		checkpoints[i] = ExecutionTrailCheckpoint{
-			NodeID:     cp.NodeId,
-			NodeOutput: nodeOutput,
-			StateAfter: stateAfter,
-			CreatedAt:  cp.CreatedAt,
+			CheckpointID: cp.CheckpointId,
+			NodeID:       cp.NodeId,
+			NodeOutput:   nodeOutput,
+			StateAfter:   stateAfter,
+			CreatedAt:    cp.CreatedAt,
+		}
+	}
+
+	children := make([]ExecutionTrailChild, len(resp.Children))
+	for i, ch := range resp.Children {
+		children[i] = ExecutionTrailChild{
+			InstanceID: ch.InstanceId,
+			NodeID:     ch.NodeId,
+			Status:     ch.Status,
+			CreatedAt:  ch.CreatedAt,
 		}
 	}
 
 	return &ExecutionTrail{
-		InstanceID:    resp.InstanceId,
-		Status:        resp.Status,
-		CurrentState:  resp.CurrentState,
-		ActiveNodeIDs: resp.ActiveNodeIds,
-		Checkpoints:   checkpoints,
+		InstanceID:       resp.InstanceId,
+		Status:           resp.Status,
+		CurrentState:     resp.CurrentState,
+		ActiveNodeIDs:    resp.ActiveNodeIds,
+		Checkpoints:      checkpoints,
+		ParentInstanceID: resp.ParentInstanceId,
+		ParentNodeID:     resp.ParentNodeId,
+		Children:         children,
 	}, nil
 }
 
@@ -247,12 +339,14 @@ func (c *WorkflowClient) GetExecutionState(ctx context.Context, instanceID int64
 
 // WorkflowDef 工作流定义（SDK 友好版）
 type WorkflowDef struct {
-	ID      int64  `json:"id"`
-	Env     string `json:"env"`
-	Code    string `json:"code"`
-	Version int32  `json:"version"`
-	Name    string `json:"name"`
-	DAGJSON string `json:"dagJson"`
+	ID          int64  `json:"id"`
+	Env         string `json:"env"`
+	Code        string `json:"code"`
+	Version     int32  `json:"version"`
+	Name        string `json:"name"`
+	DAGJSON     string `json:"dagJson"`
+	InputSchema string `json:"inputSchema"` // 初始输入 JSON Schema，未声明为空
+	StateSchema string `json:"stateSchema"` // 状态 data 区 JSON Schema，未声明为空
 }
 
 // GetDef 查询工作流定义，version=0 表示最新版本
@@ -269,12 +363,14 @@ func (c *WorkflowClient) GetDef(ctx context.Context, code string, version int32)
 		return nil, nil
 	}
 	return &WorkflowDef{
-		ID:      resp.DefId,
-		Env:     resp.Env,
-		Code:    resp.Code,
-		Version: resp.Version,
-		Name:    resp.Name,
-		DAGJSON: resp.DagJson,
+		ID:          resp.DefId,
+		Env:         resp.Env,
+		Code:        resp.Code,
+		Version:     resp.Version,
+		Name:        resp.Name,
+		DAGJSON:     resp.DagJson,
+		InputSchema: resp.InputSchema,
+		StateSchema: resp.StateSchema,
 	}, nil
 }
 
@@ -298,12 +394,14 @@ func (c *WorkflowClient) ListDefs(ctx context.Context, codeLike string, pageNum,
 	items := make([]*WorkflowDef, len(resp.Items))
 	for i, d := range resp.Items {
 		items[i] = &WorkflowDef{
-			ID:      d.DefId,
-			Env:     d.Env,
-			Code:    d.Code,
-			Version: d.Version,
-			Name:    d.Name,
-			DAGJSON: d.DagJson,
+			ID:          d.DefId,
+			Env:         d.Env,
+			Code:        d.Code,
+			Version:     d.Version,
+			Name:        d.Name,
+			DAGJSON:     d.DagJson,
+			InputSchema: d.InputSchema,
+			StateSchema: d.StateSchema,
 		}
 	}
 	return items, resp.Total, nil
@@ -335,10 +433,17 @@ type WorkflowInstance struct {
 	DefVersion    int32  `json:"defVersion"`
 	Env           string `json:"env"`
 	Status        string `json:"status"`
-	InitialState  string `json:"initialState"`   // 初始状态 JSON
-	CurrentState  string `json:"currentState"`   // 当前状态 JSON
-	ActiveNodeIDs string `json:"activeNodeIds"`  // 活动节点 ID 列表（JSON 字符串）
+	InitialState  string `json:"initialState"`  // 初始状态 JSON
+	CurrentState  string `json:"currentState"`  // 当前状态 JSON
+	ActiveNodeIDs string `json:"activeNodeIds"` // 活动节点 ID 列表（JSON 字符串）
 	CreatedAt     string `json:"createdAt"`
+	// ParentInstanceID / ParentNodeID 非零表示本实例由父实例的 subworkflow 节点拉起
+	ParentInstanceID int64  `json:"parentInstanceId,omitempty"`
+	ParentNodeID     string `json:"parentNodeId,omitempty"`
+	// ForkedFromInstanceID / ForkedFromCheckpointID 非零表示本实例由 ForkInstance 从检查点分叉创建
+	ForkedFromInstanceID   int64  `json:"forkedFromInstanceId,omitempty"`
+	ForkedFromCheckpointID int64  `json:"forkedFromCheckpointId,omitempty"`
+	BusinessKey            string `json:"businessKey,omitempty"`
 }
 
 // GetInstance 获取工作流实例详情
@@ -352,18 +457,43 @@ func (c *WorkflowClient) GetInstance(ctx context.Context, instanceID int64) (*Wo
 	if resp.NotFound {
 		return nil, nil
 	}
+	return workflowInstanceFromPB(resp), nil
+}
+
+// GetInstanceByBusinessKey 按业务键获取当前环境下最近一个实例（可能已结束），不存在时返回 nil
+func (c *WorkflowClient) GetInstanceByBusinessKey(ctx context.Context, defCode, businessKey string) (*WorkflowInstance, error) {
+	resp, err := c.service.GetInstanceByBusinessKey(ctx, &workflowpb.GetInstanceByBusinessKeyRequest{
+		Env:         c.env,
+		DefCode:     defCode,
+		BusinessKey: businessKey,
+	})
+	if err != nil {
+		return nil, WrapError(err, "get workflow instance by business key failed")
+	}
+	if resp.NotFound {
+		return nil, nil
+	}
+	return workflowInstanceFromPB(resp), nil
+}
+
+func workflowInstanceFromPB(resp *workflowpb.GetInstanceResponse) *WorkflowInstance {
 	return &WorkflowInstance{
-		ID:            resp.InstanceId,
-		DefID:         resp.DefId,
-		DefCode:       resp.DefCode,
-		DefVersion:    resp.DefVersion,
-		Env:           resp.Env,
-		Status:        resp.Status,
-		InitialState:  resp.InitialState,
-		CurrentState:  resp.CurrentState,
-		ActiveNodeIDs: resp.ActiveNodeIds,
-		CreatedAt:     resp.CreatedAt,
-	}, nil
+		ID:                     resp.InstanceId,
+		DefID:                  resp.DefId,
+		DefCode:                resp.DefCode,
+		DefVersion:             resp.DefVersion,
+		Env:                    resp.Env,
+		Status:                 resp.Status,
+		InitialState:           resp.InitialState,
+		CurrentState:           resp.CurrentState,
+		ActiveNodeIDs:          resp.ActiveNodeIds,
+		CreatedAt:              resp.CreatedAt,
+		ParentInstanceID:       resp.ParentInstanceId,
+		ParentNodeID:           resp.ParentNodeId,
+		ForkedFromInstanceID:   resp.ForkedFromInstanceId,
+		ForkedFromCheckpointID: resp.ForkedFromCheckpointId,
+		BusinessKey:            resp.BusinessKey,
+	}
 }
 
 // GetInstanceStatus 轻量查询实例状态
@@ -384,6 +514,7 @@ func (c *WorkflowClient) GetInstanceStatus(ctx context.Context, instanceID int64
 type ListInstancesFilter struct {
 	DefCode       string
 	Status        string
+	BusinessKey   string
 	CreatedAfter  int64 // Unix 毫秒
 	CreatedBefore int64 // Unix 毫秒
 }
@@ -403,6 +534,7 @@ func (c *WorkflowClient) ListInstances(ctx context.Context, filter *ListInstance
 	if filter != nil {
 		req.DefCode = filter.DefCode
 		req.Status = filter.Status
+		req.BusinessKey = filter.BusinessKey
 		req.CreatedAfter = filter.CreatedAfter
 		req.CreatedBefore = filter.CreatedBefore
 	}
@@ -423,6 +555,7 @@ func (c *WorkflowClient) ListInstances(ctx context.Context, filter *ListInstance
 			CurrentState:  inst.CurrentState,
 			ActiveNodeIDs: inst.ActiveNodeIds,
 			CreatedAt:     inst.CreatedAt,
+			BusinessKey:   inst.BusinessKey,
 		}
 	}
 	return items, resp.Total, nil
@@ -456,3 +589,288 @@ func (c *WorkflowClient) RetryNode(ctx context.Context, instanceID int64, nodeID
 	}
 	return nil
 }
+
+// CompensateInstance 触发实例补偿：按完成顺序的逆序执行各节点声明的 compensate 任务，
+// 实例经 COMPENSATING 进入 COMPENSATED
+func (c *WorkflowClient) CompensateInstance(ctx context.Context, instanceID int64) error {
+	resp, err := c.service.CompensateInstance(ctx, &workflowpb.CompensateInstanceRequest{
+		InstanceId: instanceID,
+	})
+	if err != nil {
+		return WrapError(err, "compensate workflow instance failed")
+	}
+	if !resp.Success {
+		return WrapError(status.Error(codes.FailedPrecondition, resp.Message), "compensate rejected")
+	}
+	return nil
+}
+
+// ForkInstance 从来源实例的某个检查点（取自执行轨迹的 CheckpointID）分叉出新实例。
+// patch 为可选的 JSON Merge Patch，值为 nil 的键会被删除；env 为空时沿用来源实例的环境。
+func (c *WorkflowClient) ForkInstance(ctx context.Context, instanceID, checkpointID int64, patch map[string]interface{}, env string) (int64, error) {
+	var patchJSON string
+	if len(patch) > 0 {
+		b, err := json.Marshal(patch)
+		if err != nil {
+			return 0, WrapError(err, "marshal fork patch failed")
+		}
+		patchJSON = string(b)
+	}
+	resp, err := c.service.ForkInstance(ctx, &workflowpb.ForkInstanceRequest{
+		InstanceId:   instanceID,
+		CheckpointId: checkpointID,
+		PatchJson:    patchJSON,
+		Env:          env,
+	})
+	if err != nil {
+		return 0, WrapError(err, "fork workflow instance failed")
+	}
+	return resp.InstanceId, nil
+}
+
+// PauseInstance 暂停工作流实例：照常接收节点回调，恢复前不派发后继节点
+func (c *WorkflowClient) PauseInstance(ctx context.Context, instanceID int64) error {
+	resp, err := c.service.PauseInstance(ctx, &workflowpb.PauseInstanceRequest{
+		InstanceId: instanceID,
+	})
+	if err != nil {
+		return WrapError(err, "pause workflow instance failed")
+	}
+	if !resp.Success {
+		return WrapError(status.Error(codes.FailedPrecondition, resp.Message), "pause rejected")
+	}
+	return nil
+}
+
+// ResumeInstance 恢复已暂停的工作流实例
+func (c *WorkflowClient) ResumeInstance(ctx context.Context, instanceID int64) error {
+	resp, err := c.service.ResumeInstance(ctx, &workflowpb.ResumeInstanceRequest{
+		InstanceId: instanceID,
+	})
+	if err != nil {
+		return WrapError(err, "resume workflow instance failed")
+	}
+	if !resp.Success {
+		return WrapError(status.Error(codes.FailedPrecondition, resp.Message), "resume rejected")
+	}
+	return nil
+}
+
+// PauseInstancesByDef 批量暂停某定义编码（全部版本）在指定环境下运行中/等待中的实例，返回暂停数量
+func (c *WorkflowClient) PauseInstancesByDef(ctx context.Context, defCode, env string) (int64, error) {
+	resp, err := c.service.PauseInstancesByDef(ctx, &workflowpb.PauseInstancesByDefRequest{
+		DefCode: defCode,
+		Env:     env,
+	})
+	if err != nil {
+		return 0, WrapError(err, "pause workflow instances failed")
+	}
+	return resp.Affected, nil
+}
+
+// ResumeInstancesByDef 批量恢复某定义编码（全部版本）在指定环境下已暂停的实例，返回恢复数量
+func (c *WorkflowClient) ResumeInstancesByDef(ctx context.Context, defCode, env string) (int64, error) {
+	resp, err := c.service.ResumeInstancesByDef(ctx, &workflowpb.ResumeInstancesByDefRequest{
+		DefCode: defCode,
+		Env:     env,
+	})
+	if err != nil {
+		return 0, WrapError(err, "resume workflow instances failed")
+	}
+	return resp.Affected, nil
+}
+
+// MigrateInstancesRequest 实例迁移请求
+type MigrateInstancesRequest struct {
+	DefCode       string            `json:"defCode"`
+	Env           string            `json:"env"`           // 为空时使用服务端默认环境
+	TargetVersion int32             `json:"targetVersion"` // 0 表示最新版本
+	InstanceIDs   []int64           `json:"instanceIds"`   // 为空时迁移该定义下全部运行中/等待中/已暂停的实例
+	FromVersion   int32             `json:"fromVersion"`   // 未指定实例时只迁移该版本的实例，0 表示不限
+	NodeMapping   map[string]string `json:"nodeMapping"`   // 活跃节点改名：旧 ID -> 新 ID
+	DryRun        bool              `json:"dryRun"`        // 只校验并报告，不迁移
+}
+
+// InstanceMigrationResult 单个实例的迁移结果
+type InstanceMigrationResult struct {
+	InstanceID    int64    `json:"instanceId"`
+	FromVersion   int32    `json:"fromVersion"`
+	Migrated      bool     `json:"migrated"`                // DryRun 时表示可以迁移
+	Reason        string   `json:"reason,omitempty"`        // 不能迁移的原因
+	ActiveNodeIDs []string `json:"activeNodeIds,omitempty"` // 迁移后的活跃节点
+}
+
+// MigrationReport 迁移报告
+type MigrationReport struct {
+	TargetDefID   int64                      `json:"targetDefId"`
+	TargetVersion int32                      `json:"targetVersion"`
+	DryRun        bool                       `json:"dryRun"`
+	Migrated      int32                      `json:"migrated"`
+	Rejected      int32                      `json:"rejected"`
+	Results       []*InstanceMigrationResult `json:"results"`
+}
+
+// MigrateInstances 把实例迁移到同一定义编码的目标版本。活跃节点必须都存在于目标版本
+// （可用 NodeMapping 改名）；单个实例不能迁移不影响其他实例，原因见结果中的 Reason
+func (c *WorkflowClient) MigrateInstances(ctx context.Context, req *MigrateInstancesRequest) (*MigrationReport, error) {
+	if req == nil {
+		return nil, WrapError(status.Error(codes.InvalidArgument, "request is nil"), "migrate workflow instances failed")
+	}
+	resp, err := c.service.MigrateInstances(ctx, &workflowpb.MigrateInstancesRequest{
+		DefCode:       req.DefCode,
+		Env:           req.Env,
+		TargetVersion: req.TargetVersion,
+		InstanceIds:   req.InstanceIDs,
+		FromVersion:   req.FromVersion,
+		NodeMapping:   req.NodeMapping,
+		DryRun:        req.DryRun,
+	})
+	if err != nil {
+		return nil, WrapError(err, "migrate workflow instances failed")
+	}
+	results := make([]*InstanceMigrationResult, len(resp.Results))
+	for i, r := range resp.Results {
+		results[i] = &InstanceMigrationResult{
+			InstanceID:    r.InstanceId,
+			FromVersion:   r.FromVersion,
+			Migrated:      r.Migrated,
+			Reason:        r.Reason,
+			ActiveNodeIDs: r.ActiveNodeIds,
+		}
+	}
+	return &MigrationReport{
+		TargetDefID:   resp.TargetDefId,
+		TargetVersion: resp.TargetVersion,
+		DryRun:        resp.DryRun,
+		Migrated:      resp.Migrated,
+		Rejected:      resp.Rejected,
+		Results:       results,
+	}, nil
+}
+
+// InstanceMigrationRecord 实例迁移审计记录
+type InstanceMigrationRecord struct {
+	ID               int64  `json:"id"`
+	FromVersion      int32  `json:"fromVersion"`
+	ToVersion        int32  `json:"toVersion"`
+	NodeMappingJSON  string `json:"nodeMappingJson"`
+	ActiveBeforeJSON string `json:"activeBeforeJson"`
+	ActiveAfterJSON  string `json:"activeAfterJson"`
+	CreatedAt        string `json:"createdAt"`
+}
+
+// ListInstanceMigrations 按迁移先后列出实例的迁移审计记录
+func (c *WorkflowClient) ListInstanceMigrations(ctx context.Context, instanceID int64) ([]*InstanceMigrationRecord, error) {
+	resp, err := c.service.ListInstanceMigrations(ctx, &workflowpb.ListInstanceMigrationsRequest{
+		InstanceId: instanceID,
+	})
+	if err != nil {
+		return nil, WrapError(err, "list instance migrations failed")
+	}
+	items := make([]*InstanceMigrationRecord, len(resp.Items))
+	for i, m := range resp.Items {
+		items[i] = &InstanceMigrationRecord{
+			ID:               m.Id,
+			FromVersion:      m.FromVersion,
+			ToVersion:        m.ToVersion,
+			NodeMappingJSON:  m.NodeMappingJson,
+			ActiveBeforeJSON: m.ActiveBeforeJson,
+			ActiveAfterJSON:  m.ActiveAfterJson,
+			CreatedAt:        m.CreatedAt,
+		}
+	}
+	return items, nil
+}
+
+// 渲染格式
+const (
+	GraphFormatMermaid = "mermaid"
+	GraphFormatDOT     = "dot"
+)
+
+// RenderedGraph DAG 渲染结果；NodeStatus 仅实例视图返回：节点 ID -> completed/active/waiting/failed
+type RenderedGraph struct {
+	Format     string            `json:"format"`
+	Content    string            `json:"content"`
+	NodeStatus map[string]string `json:"nodeStatus,omitempty"`
+}
+
+// RenderDef 把定义的 DAG 渲染为 Mermaid flowchart 或 Graphviz DOT 文本，便于在 PR 中审阅；
+// version=0 表示最新版本，format 为空时使用 mermaid
+func (c *WorkflowClient) RenderDef(ctx context.Context, code string, version int32, format string) (*RenderedGraph, error) {
+	resp, err := c.service.RenderDef(ctx, &workflowpb.RenderDefRequest{
+		Env:     c.env,
+		Code:    code,
+		Version: version,
+		Format:  format,
+	})
+	if err != nil {
+		return nil, WrapError(err, "render workflow def failed")
+	}
+	return &RenderedGraph{Format: resp.Format, Content: resp.Content}, nil
+}
+
+// RenderInstance 渲染实例所用定义的 DAG，并按执行进度给节点着色
+func (c *WorkflowClient) RenderInstance(ctx context.Context, instanceID int64, format string) (*RenderedGraph, error) {
+	resp, err := c.service.RenderInstance(ctx, &workflowpb.RenderInstanceRequest{
+		InstanceId: instanceID,
+		Format:     format,
+	})
+	if err != nil {
+		return nil, WrapError(err, "render workflow instance failed")
+	}
+	return &RenderedGraph{Format: resp.Format, Content: resp.Content, NodeStatus: resp.NodeStatus}, nil
+}
+
+// SignalDelivery 按关联键投递信号的结果
+type SignalDelivery struct {
+	Delivered   int     `json:"delivered"` // 收到信号并推进的等待数，0 表示没有匹配的等待
+	InstanceIDs []int64 `json:"instanceIds"`
+}
+
+// SendSignal 向实例发送信号，payload 合并入状态。wakeupNode 非空时唤醒该节点；为空且实例有
+// wait_signal 节点在等待该信号时，由这些节点接收并推进
+func (c *WorkflowClient) SendSignal(ctx context.Context, instanceID int64, signalName string, payload map[string]interface{}, wakeupNode string) error {
+	payloadJSON := "{}"
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return WrapError(err, "marshal signal payload failed")
+		}
+		payloadJSON = string(data)
+	}
+	_, err := c.service.SendSignal(ctx, &workflowpb.SendSignalRequest{
+		InstanceId:  instanceID,
+		SignalName:  signalName,
+		PayloadJson: payloadJSON,
+		WakeupNode:  wakeupNode,
+		Env:         c.env,
+	})
+	if err != nil {
+		return WrapError(err, "send signal failed")
+	}
+	return nil
+}
+
+// SendSignalByKey 按关联键投递信号，推进当前环境内所有以该关联键等待该信号的 wait_signal 节点，
+// 调用方无需知道实例 ID
+func (c *WorkflowClient) SendSignalByKey(ctx context.Context, signalName, correlationKey string, payload map[string]interface{}) (*SignalDelivery, error) {
+	payloadJSON := "{}"
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, WrapError(err, "marshal signal payload failed")
+		}
+		payloadJSON = string(data)
+	}
+	resp, err := c.service.SendSignalByKey(ctx, &workflowpb.SendSignalByKeyRequest{
+		Env:            c.env,
+		SignalName:     signalName,
+		CorrelationKey: correlationKey,
+		PayloadJson:    payloadJSON,
+	})
+	if err != nil {
+		return nil, WrapError(err, "send signal by key failed")
+	}
+	return &SignalDelivery{Delivered: int(resp.Delivered), InstanceIDs: resp.InstanceIds}, nil
+}
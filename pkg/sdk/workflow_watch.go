@@ -0,0 +1,159 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	workflowpb "github.com/xsxdot/aio/system/workflow/api/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// 工作流实例事件类型
+const (
+	WorkflowEventNodeTriggered  = "node_triggered"
+	WorkflowEventNodeCompleted  = "node_completed"
+	WorkflowEventStateChanged   = "state_changed"
+	WorkflowEventWaiting        = "waiting"
+	WorkflowEventSignalReceived = "signal_received"
+	WorkflowEventTerminal       = "terminal"
+	WorkflowEventCompensation   = "compensation"
+	WorkflowEventPaused         = "paused"
+	WorkflowEventResumed        = "resumed"
+	WorkflowEventMigrated       = "migrated"
+)
+
+// WorkflowEvent 工作流实例事件（SDK 友好版）
+type WorkflowEvent struct {
+	EventID     int64                  `json:"eventId"` // 全局递增，WatchDef 的续传游标
+	InstanceID  int64                  `json:"instanceId"`
+	Seq         int64                  `json:"seq"` // 实例内连续递增，Watch 的续传游标
+	DefID       int64                  `json:"defId"`
+	Type        string                 `json:"type"`
+	NodeID      string                 `json:"nodeId"`
+	Status      string                 `json:"status"` // 事件发生后的实例状态
+	Payload     map[string]interface{} `json:"payload,omitempty"`
+	PayloadJSON string                 `json:"payloadJson"`
+	CreatedAt   string                 `json:"createdAt"`
+}
+
+const (
+	watchRetryMinDelay = time.Second
+	watchRetryMaxDelay = 30 * time.Second
+)
+
+// Watch 订阅实例事件，afterSeq=0 表示从头回放。
+//
+// 返回的事件 channel 在实例进入终态且事件推送完毕、ctx 取消或遇到不可重试错误时关闭；
+// 不可重试错误（如实例不存在）会在关闭前写入错误 channel。连接中断时以最后收到的
+// seq 自动重连，不会重复或遗漏事件。
+func (c *WorkflowClient) Watch(ctx context.Context, instanceID, afterSeq int64) (<-chan *WorkflowEvent, <-chan error) {
+	events := make(chan *WorkflowEvent, 16)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(events)
+		cursor := afterSeq
+		err := watchWithRetry(ctx, true, func(ctx context.Context) (grpc.ServerStreamingClient[workflowpb.InstanceEvent], error) {
+			return c.service.WatchInstance(ctx, &workflowpb.WatchInstanceRequest{InstanceId: instanceID, AfterSeq: cursor})
+		}, func(e *WorkflowEvent) { cursor = e.Seq }, events)
+		if err != nil {
+			errs <- WrapError(err, "watch workflow instance failed")
+		}
+	}()
+	return events, errs
+}
+
+// WatchDef 订阅当前环境下某定义编码（全部版本）所有实例的事件，afterEventID=0 表示从订阅时刻开始。
+//
+// 事件 channel 在 ctx 取消或遇到不可重试错误时关闭；连接中断时以最后收到的 EventID 自动重连。
+func (c *WorkflowClient) WatchDef(ctx context.Context, defCode string, afterEventID int64) (<-chan *WorkflowEvent, <-chan error) {
+	events := make(chan *WorkflowEvent, 16)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(events)
+		cursor := afterEventID
+		err := watchWithRetry(ctx, false, func(ctx context.Context) (grpc.ServerStreamingClient[workflowpb.InstanceEvent], error) {
+			return c.service.WatchDef(ctx, &workflowpb.WatchDefRequest{DefCode: defCode, Env: c.env, AfterEventId: cursor})
+		}, func(e *WorkflowEvent) { cursor = e.EventID }, events)
+		if err != nil {
+			errs <- WrapError(err, "watch workflow def failed")
+		}
+	}()
+	return events, errs
+}
+
+// watchWithRetry 打开事件流并转发到 out，可重试错误按指数退避重连。
+// endOnEOF 为 true 时服务端正常结束流即返回（实例订阅），否则视为断线重连（定义订阅）。
+func watchWithRetry(ctx context.Context, endOnEOF bool, open func(context.Context) (grpc.ServerStreamingClient[workflowpb.InstanceEvent], error), advance func(*WorkflowEvent), out chan<- *WorkflowEvent) error {
+	delay := watchRetryMinDelay
+	for {
+		stream, err := open(ctx)
+		for err == nil {
+			var pbEvent *workflowpb.InstanceEvent
+			pbEvent, err = stream.Recv()
+			if err != nil {
+				break
+			}
+			e := workflowEventFromPB(pbEvent)
+			select {
+			case out <- e:
+			case <-ctx.Done():
+				return nil
+			}
+			advance(e)
+			delay = watchRetryMinDelay
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err == io.EOF {
+			if endOnEOF {
+				return nil
+			}
+		} else if !isRetryableWatchError(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > watchRetryMaxDelay {
+			delay = watchRetryMaxDelay
+		}
+	}
+}
+
+func isRetryableWatchError(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.Aborted, codes.Internal, codes.DeadlineExceeded, codes.ResourceExhausted:
+		return true
+	}
+	return false
+}
+
+func workflowEventFromPB(e *workflowpb.InstanceEvent) *WorkflowEvent {
+	event := &WorkflowEvent{
+		EventID:     e.EventId,
+		InstanceID:  e.InstanceId,
+		Seq:         e.Seq,
+		DefID:       e.DefId,
+		Type:        e.Type,
+		NodeID:      e.NodeId,
+		Status:      e.Status,
+		PayloadJSON: e.PayloadJson,
+		CreatedAt:   e.CreatedAt,
+	}
+	if e.PayloadJson != "" {
+		_ = json.Unmarshal([]byte(e.PayloadJson), &event.Payload)
+	}
+	return event
+}
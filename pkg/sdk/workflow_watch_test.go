@@ -0,0 +1,87 @@
+package sdk
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	workflowpb "github.com/xsxdot/aio/system/workflow/api/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeEventStream 按顺序返回预置事件，耗尽后返回 end
+type fakeEventStream struct {
+	grpc.ClientStream
+	events []*workflowpb.InstanceEvent
+	end    error
+}
+
+func (s *fakeEventStream) Recv() (*workflowpb.InstanceEvent, error) {
+	if len(s.events) == 0 {
+		return nil, s.end
+	}
+	e := s.events[0]
+	s.events = s.events[1:]
+	return e, nil
+}
+
+type mockWatchServiceClient struct {
+	workflowpb.WorkflowServiceClient
+	streams  []*fakeEventStream
+	afterSeq []int64
+}
+
+func (m *mockWatchServiceClient) WatchInstance(ctx context.Context, in *workflowpb.WatchInstanceRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[workflowpb.InstanceEvent], error) {
+	m.afterSeq = append(m.afterSeq, in.AfterSeq)
+	s := m.streams[0]
+	m.streams = m.streams[1:]
+	return s, nil
+}
+
+// 连接中断后以最后收到的 seq 重连，服务端正常结束流时关闭 channel 且不报错。
+func TestWorkflowWatchResumesFromLastSeq(t *testing.T) {
+	mock := &mockWatchServiceClient{streams: []*fakeEventStream{
+		{events: []*workflowpb.InstanceEvent{{Seq: 1, Type: WorkflowEventNodeTriggered}, {Seq: 2, Type: WorkflowEventWaiting}}, end: status.Error(codes.Unavailable, "conn reset")},
+		{events: []*workflowpb.InstanceEvent{{Seq: 3, Type: WorkflowEventTerminal, PayloadJson: `{"k":"v"}`}}, end: io.EOF},
+	}}
+	client := &WorkflowClient{env: "test", service: mock}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	events, errs := client.Watch(ctx, 7, 0)
+	var seqs []int64
+	var last *WorkflowEvent
+	for e := range events {
+		seqs = append(seqs, e.Seq)
+		last = e
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("watch err = %v", err)
+	}
+	if len(seqs) != 3 || seqs[2] != 3 {
+		t.Fatalf("seqs = %v, want [1 2 3]", seqs)
+	}
+	if len(mock.afterSeq) != 2 || mock.afterSeq[1] != 2 {
+		t.Fatalf("重连 after_seq = %v, want [0 2]", mock.afterSeq)
+	}
+	if last.Payload["k"] != "v" {
+		t.Fatalf("payload = %v", last.Payload)
+	}
+}
+
+// 不可重试的错误（如实例不存在）直接结束订阅并通过错误 channel 返回。
+func TestWorkflowWatchStopsOnNotFound(t *testing.T) {
+	mock := &mockWatchServiceClient{streams: []*fakeEventStream{
+		{end: status.Error(codes.NotFound, "实例不存在")},
+	}}
+	client := &WorkflowClient{env: "test", service: mock}
+	events, errs := client.Watch(context.Background(), 7, 0)
+	for range events {
+	}
+	if err := <-errs; !IsNotFound(err) {
+		t.Fatalf("err = %v, want NotFound", err)
+	}
+}
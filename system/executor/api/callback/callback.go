@@ -1,18 +1,25 @@
 // Package callback 定义任务完成回调的对外契约。
 //
-// 职责：声明回调处理器接口，以及承载回调的 outbox 任务的固定字段与载荷结构。
+// 职责：声明回调处理器接口、进程内任务处理器接口，以及承载回调的 outbox 任务的
+// 固定字段与载荷结构。
 //
 // 边界：不含任何实现，仅为 executor 内部服务与 worker 消费者共享的契约，
 // 放在 api/ 下以避免 service 与 worker 互相 import 形成环。
 package callback
 
-import "context"
+import (
+	"context"
+	"errors"
+)
 
 const (
 	// InternalTargetService outbox 回调任务的目标服务名，固定为 aio 自身。
 	InternalTargetService = "aio"
 	// MethodJobCompletedCallback outbox 回调任务的方法名。
 	MethodJobCompletedCallback = "internal.job_completed_callback"
+	// MethodTimerFired 定时唤醒任务的方法名：不执行任何逻辑，按 RunAt 到期被内部 worker
+	// 领取即以成功确认，由 Source 对应的完成回调继续推进（如 workflow 的 timer 节点）。
+	MethodTimerFired = "internal.timer_fired"
 	// OutboxDedupKeyPrefix outbox 回调任务的幂等键前缀，完整形式为 {prefix}{jobID}。
 	OutboxDedupKeyPrefix = "jobcb_"
 	// OutboxMaxAttempts outbox 回调任务的最大尝试次数，超过后转死信、Admin 可见。
@@ -36,3 +43,37 @@ type CallbackPayload struct {
 type JobCompletionHandler interface {
 	OnJobCompleted(ctx context.Context, jobID uint64, callbackData, resultJSON string) error
 }
+
+// InternalJobHandler 进程内任务处理器（按 method 注册）：内部 worker 领取
+// target_service=aio 且 method 已注册的任务后调用，结果按常规任务确认，
+// 因此同样享有租约、重试与完成回调 outbox 的保障。
+//
+// 注意：返回的 resultJSON 作为任务结果；返回错误表示本次尝试失败，按任务的
+// 重试策略重试，错误经 Permanent 包装时不再重试、直接转死信。
+// 任务可能因租约到期被重投，实现方必须容忍同一任务被执行多次。
+type InternalJobHandler interface {
+	HandleInternalJob(ctx context.Context, argsJSON string) (resultJSON string, err error)
+}
+
+// PermanentError 不可重试的处理失败
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string { return e.Err.Error() }
+
+func (e *PermanentError) Unwrap() error { return e.Err }
+
+// Permanent 把错误标记为不可重试
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+// IsPermanent 判断错误是否被标记为不可重试
+func IsPermanent(err error) bool {
+	var p *PermanentError
+	return errors.As(err, &p)
+}
@@ -57,6 +57,11 @@ func (c *ExecutorClient) CancelActiveJobsByDedupKeyPrefix(ctx context.Context, e
 	return c.app.JobService.CancelActiveJobsByDedupKeyPrefix(ctx, env, dedupKeyPrefix)
 }
 
+// ListJobAttempts 列出任务的全部尝试记录（按尝试次数倒序，用于工作流节点耗时统计）
+func (c *ExecutorClient) ListJobAttempts(ctx context.Context, jobID uint64) ([]*model.ExecutorJobAttemptModel, error) {
+	return c.app.JobAttemptService.ListByJobID(ctx, jobID)
+}
+
 // RequeueJob 重新入队任务
 func (c *ExecutorClient) RequeueJob(ctx context.Context, jobID uint64, runAt int64) error {
 	return c.app.JobService.RequeueJob(ctx, jobID, runAt)
@@ -2,6 +2,7 @@ package dao
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/xsxdot/aio/base"
@@ -53,12 +54,16 @@ func (d *ExecutorJobDAO) GetByDedupKey(ctx context.Context, env, dedupKey string
 	return &job, nil
 }
 
+// likePrefixEscaper 转义 LIKE 模式中的通配符，配合 ESCAPE '!' 使用
+var likePrefixEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
 // ListActiveByDedupKeyPrefix 列出 env 下 dedup_key 以 prefix 开头且仍为待执行/执行中的任务（用于工作流取消/回滚）
+// prefix 按字面匹配：其中的 %、_ 会被转义，不作通配符
 func (d *ExecutorJobDAO) ListActiveByDedupKeyPrefix(ctx context.Context, env, dedupKeyPrefix string) ([]*model.ExecutorJobModel, error) {
 	var jobs []*model.ExecutorJobModel
 	err := mvc.ExtractDB(ctx, d.db).Where(
-		"env = ? AND dedup_key LIKE ? AND status IN ?",
-		env, dedupKeyPrefix+"%", []model.JobStatus{model.JobStatusPending, model.JobStatusRunning},
+		"env = ? AND dedup_key LIKE ? ESCAPE '!' AND status IN ?",
+		env, likePrefixEscaper.Replace(dedupKeyPrefix)+"%", []model.JobStatus{model.JobStatusPending, model.JobStatusRunning},
 	).Find(&jobs).Error
 	if err != nil {
 		return nil, err
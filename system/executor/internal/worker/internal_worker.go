@@ -1,9 +1,12 @@
 // Package worker 提供 aio 进程内的回调消费者。
 //
 // 职责：消费 executor 中 target_service=aio、method=internal.job_completed_callback
-// 的 outbox 任务，按 source 路由到已注册的 JobCompletionHandler。
+// 的 outbox 任务，按 source 路由到已注册的 JobCompletionHandler；method=
+// internal.timer_fired 的定时唤醒任务，到期即以成功确认；以及 method 已注册
+// InternalJobHandler 的进程内任务，调用处理器并按结果确认。
 //
-// 边界：不承载任何业务逻辑，不处理其他 method；只消费本进程 env 的任务——
+// 边界：不承载任何业务逻辑，只处理上述 method；进程内任务使用独立的 slot 并发
+// 执行，慢处理器不会挤占回调任务的领取。只消费本进程 env 的任务——
 // 跨 env 的回调需由对应 env 的 aio 实例消费。直调 service 而非绕 gRPC：同进程
 // 无需网络层，也避免自连自身的启动顺序依赖。
 package worker
@@ -12,6 +15,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -45,6 +49,9 @@ type InternalCallbackWorker struct {
 	consumerID string
 	log        *logger.Log
 
+	handlersMu sync.RWMutex
+	handlers   map[string]callback.InternalJobHandler
+
 	stopCtx    context.Context
 	stopCancel context.CancelFunc
 	wg         sync.WaitGroup
@@ -65,11 +72,19 @@ func NewInternalCallbackWorker(runner JobRunner, env, consumerID string, log *lo
 		env:        env,
 		consumerID: consumerID,
 		log:        log,
+		handlers:   make(map[string]callback.InternalJobHandler),
 		stopCtx:    ctx,
 		stopCancel: cancel,
 	}
 }
 
+// RegisterHandler 注册进程内任务处理器，同一 method 重复注册时后者覆盖前者。
+func (w *InternalCallbackWorker) RegisterHandler(method string, h callback.InternalJobHandler) {
+	w.handlersMu.Lock()
+	defer w.handlersMu.Unlock()
+	w.handlers[method] = h
+}
+
 // Start 启动轮询循环。重复调用无副作用。
 func (w *InternalCallbackWorker) Start() {
 	w.startOnce.Do(func() {
@@ -112,34 +127,103 @@ func (w *InternalCallbackWorker) pollOnce(ctx context.Context) {
 	jobs, err := w.runner.AcquireJobs(ctx, service.AcquireJobsRequest{
 		Env:           w.env,
 		TargetService: callback.InternalTargetService,
-		Methods:       []string{callback.MethodJobCompletedCallback},
+		Methods:       []string{callback.MethodJobCompletedCallback, callback.MethodTimerFired},
 		ConsumerIDs:   consumerIDs,
 		LeaseDuration: leaseDuration,
 		Mode:          dao.AcquireJobsModeFillSlots,
 	})
 	if err != nil {
 		w.log.WithErr(err).WithField("env", w.env).Error("内部回调 worker 领取任务失败")
-		return
+	} else if len(jobs) > 0 {
+		w.log.WithField("count", len(jobs)).Debug("内部回调 worker 领到任务")
+		for _, j := range jobs {
+			w.handle(ctx, j)
+		}
 	}
-	if len(jobs) == 0 {
+	w.pollHandlerJobs(ctx)
+}
+
+// pollHandlerJobs 领取已注册处理器的进程内任务，每个任务在独立 goroutine 中执行，
+// 占用的 slot 在确认前保持租约，因此并发上限同样为 maxConcurrent。
+func (w *InternalCallbackWorker) pollHandlerJobs(ctx context.Context) {
+	w.handlersMu.RLock()
+	methods := make([]string, 0, len(w.handlers))
+	for m := range w.handlers {
+		methods = append(methods, m)
+	}
+	w.handlersMu.RUnlock()
+	if len(methods) == 0 {
 		return
 	}
-	w.log.WithField("count", len(jobs)).Debug("内部回调 worker 领到任务")
+	sort.Strings(methods)
 
+	consumerIDs := make([]string, 0, maxConcurrent)
+	for i := 0; i < maxConcurrent; i++ {
+		consumerIDs = append(consumerIDs, fmt.Sprintf("%s-job-slot-%d", w.consumerID, i))
+	}
+	jobs, err := w.runner.AcquireJobs(ctx, service.AcquireJobsRequest{
+		Env:           w.env,
+		TargetService: callback.InternalTargetService,
+		Methods:       methods,
+		ConsumerIDs:   consumerIDs,
+		LeaseDuration: leaseDuration,
+		Mode:          dao.AcquireJobsModeFillSlots,
+	})
+	if err != nil {
+		w.log.WithErr(err).WithField("env", w.env).Error("内部 worker 领取进程内任务失败")
+		return
+	}
 	for _, j := range jobs {
-		w.handle(ctx, j)
+		w.wg.Add(1)
+		go func(j *service.AcquiredJobResult) {
+			defer w.wg.Done()
+			w.runHandler(ctx, j)
+		}(j)
 	}
 }
 
+// runHandler 调用处理器执行一条进程内任务并确认结果
+func (w *InternalCallbackWorker) runHandler(ctx context.Context, j *service.AcquiredJobResult) {
+	jobID := uint64(j.Job.ID)
+	w.handlersMu.RLock()
+	h := w.handlers[j.Job.Method]
+	w.handlersMu.RUnlock()
+	if h == nil {
+		w.ack(ctx, jobID, j, model.JobStatusFailed, "未注册的进程内任务 method: "+j.Job.Method, "", false)
+		return
+	}
+
+	started := time.Now()
+	resultJSON, err := h.HandleInternalJob(ctx, j.Job.ArgsJSON)
+	if err != nil {
+		permanent := callback.IsPermanent(err)
+		w.log.WithErr(err).WithField("job_id", jobID).WithField("method", j.Job.Method).
+			WithField("permanent", permanent).Warn("进程内任务执行失败")
+		w.ack(ctx, jobID, j, model.JobStatusFailed, err.Error(), "", permanent)
+		return
+	}
+	w.log.WithField("job_id", jobID).WithField("method", j.Job.Method).
+		WithField("cost_ms", time.Since(started).Milliseconds()).Debug("进程内任务执行成功")
+	w.ack(ctx, jobID, j, model.JobStatusSucceeded, "", resultJSON, false)
+}
+
 func (w *InternalCallbackWorker) handle(ctx context.Context, j *service.AcquiredJobResult) {
 	jobID := uint64(j.Job.ID)
 	started := time.Now()
 
+	// 定时唤醒任务能被领取即说明 RunAt 已到期，直接成功确认；
+	// 后续推进由 AckJob 按 Source 投递的完成回调负责。
+	if j.Job.Method == callback.MethodTimerFired {
+		w.log.WithField("job_id", jobID).WithField("source", j.Job.Source).Debug("定时唤醒任务到期")
+		w.ack(ctx, jobID, j, model.JobStatusSucceeded, "", "", false)
+		return
+	}
+
 	var payload callback.CallbackPayload
 	if err := json.Unmarshal([]byte(j.Job.ArgsJSON), &payload); err != nil {
 		w.log.WithErr(err).WithField("outbox_job_id", jobID).
 			Error("解析回调载荷失败，标记失败等待重试")
-		w.ack(ctx, jobID, j, model.JobStatusFailed, "解析回调载荷失败: "+err.Error(), "")
+		w.ack(ctx, jobID, j, model.JobStatusFailed, "解析回调载荷失败: "+err.Error(), "", false)
 		return
 	}
 
@@ -155,7 +239,7 @@ func (w *InternalCallbackWorker) handle(ctx context.Context, j *service.Acquired
 			WithField("origin_job_id", payload.JobID).
 			WithField("source", payload.Source).
 			Error("派发完成回调失败，标记失败等待重试")
-		w.ack(ctx, jobID, j, model.JobStatusFailed, err.Error(), "")
+		w.ack(ctx, jobID, j, model.JobStatusFailed, err.Error(), "", false)
 		return
 	}
 
@@ -164,13 +248,13 @@ func (w *InternalCallbackWorker) handle(ctx context.Context, j *service.Acquired
 		WithField("source", payload.Source).
 		WithField("cost_ms", time.Since(started).Milliseconds()).
 		Info("完成回调派发成功")
-	w.ack(ctx, jobID, j, model.JobStatusSucceeded, "", "")
+	w.ack(ctx, jobID, j, model.JobStatusSucceeded, "", "", false)
 }
 
 func (w *InternalCallbackWorker) ack(ctx context.Context, jobID uint64,
-	j *service.AcquiredJobResult, status model.JobStatus, errMsg, resultJSON string) {
+	j *service.AcquiredJobResult, status model.JobStatus, errMsg, resultJSON string, stopRetry bool) {
 	if err := w.runner.AckJob(ctx, jobID, j.AttemptNo, j.ConsumerID,
-		status, errMsg, resultJSON, 0, false, 0, ""); err != nil {
+		status, errMsg, resultJSON, 0, stopRetry, 0, ""); err != nil {
 		// 确认失败不重试：租约到期后 executor 会重投，重复派发由 workflow 侧幂等表拦截。
 		w.log.WithErr(err).WithField("outbox_job_id", jobID).WithField("status", status).
 			Error("内部回调 worker 确认任务失败，等待租约到期重投")
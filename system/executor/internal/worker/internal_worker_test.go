@@ -16,12 +16,26 @@ type fakeRunner struct {
 	acquired    []*service.AcquiredJobResult
 	dispatched  []callback.CallbackPayload
 	acked       []model.JobStatus
+	stopRetry   []bool
+	results     []string
 	dispatchErr error
 }
 
 func (f *fakeRunner) AcquireJobs(ctx context.Context, req service.AcquireJobsRequest) ([]*service.AcquiredJobResult, error) {
-	out := f.acquired
-	f.acquired = nil // 只投递一轮，避免测试无限循环
+	// 只投递请求的 method，且每条只投递一次，避免测试无限循环
+	var out, rest []*service.AcquiredJobResult
+	for _, j := range f.acquired {
+		matched := false
+		for _, m := range req.Methods {
+			matched = matched || j.Job.Method == m
+		}
+		if matched {
+			out = append(out, j)
+		} else {
+			rest = append(rest, j)
+		}
+	}
+	f.acquired = rest
 	return out, nil
 }
 
@@ -29,6 +43,8 @@ func (f *fakeRunner) AckJob(ctx context.Context, jobID uint64, attemptNo int32,
 	status model.JobStatus, errorMsg, resultJSON string, retryAfter int32,
 	stopRetry bool, addMaxAttempts int32, errorType string) error {
 	f.acked = append(f.acked, status)
+	f.stopRetry = append(f.stopRetry, stopRetry)
+	f.results = append(f.results, resultJSON)
 	return nil
 }
 
@@ -120,3 +136,79 @@ func TestWorkerAcksFailedOnBadPayload(t *testing.T) {
 		t.Fatalf("acked = %v, want [failed]", f.acked)
 	}
 }
+
+// 定时唤醒任务不带回调载荷，领到即以 succeeded 确认，不得走派发分支。
+// 推进由 AckJob 按 Source 投递的 outbox 回调完成。
+func TestWorkerAcksTimerFiredWithoutDispatch(t *testing.T) {
+	timerJob := &model.ExecutorJobModel{
+		Env:           "dev",
+		TargetService: callback.InternalTargetService,
+		Method:        callback.MethodTimerFired,
+		ArgsJSON:      `{"instance_id":7,"node_id":"WAIT"}`,
+		Source:        "workflow",
+	}
+	timerJob.ID = 558
+	f := &fakeRunner{acquired: []*service.AcquiredJobResult{{
+		Job: timerJob, AttemptNo: 1, ConsumerID: "aio-1-slot-0",
+	}}}
+	w := NewInternalCallbackWorker(f, "dev", "aio-1", logger.GetLogger())
+
+	w.pollOnce(context.Background())
+
+	if len(f.dispatched) != 0 {
+		t.Fatalf("dispatched = %d, want 0", len(f.dispatched))
+	}
+	if len(f.acked) != 1 || f.acked[0] != model.JobStatusSucceeded {
+		t.Fatalf("acked = %v, want [succeeded]", f.acked)
+	}
+}
+
+type fakeInternalHandler struct {
+	result string
+	err    error
+	args   []string
+}
+
+func (h *fakeInternalHandler) HandleInternalJob(ctx context.Context, argsJSON string) (string, error) {
+	h.args = append(h.args, argsJSON)
+	return h.result, h.err
+}
+
+func newInternalJob(id int64, method, args string) *service.AcquiredJobResult {
+	job := &model.ExecutorJobModel{Env: "dev", TargetService: callback.InternalTargetService, Method: method, ArgsJSON: args}
+	job.ID = id
+	return &service.AcquiredJobResult{Job: job, AttemptNo: 1, ConsumerID: "aio-1-job-slot-0"}
+}
+
+// 进程内任务按 method 路由到处理器：成功时结果随确认写回；标记为不可重试的失败
+// 以 stopRetry 确认直接转死信，普通失败仍按重试策略重试。
+func TestWorkerRunsInternalJobHandlers(t *testing.T) {
+	h := &fakeInternalHandler{result: `{"status":200}`}
+	for _, tc := range []struct {
+		err       error
+		status    model.JobStatus
+		stopRetry bool
+	}{
+		{nil, model.JobStatusSucceeded, false},
+		{errors.New("upstream 503"), model.JobStatusFailed, false},
+		{callback.Permanent(errors.New("upstream 400")), model.JobStatusFailed, true},
+	} {
+		h.err = tc.err
+		f := &fakeRunner{acquired: []*service.AcquiredJobResult{newInternalJob(700, "internal.ok", `{"url":"x"}`)}}
+		w := NewInternalCallbackWorker(f, "dev", "aio-1", logger.GetLogger())
+		w.RegisterHandler("internal.ok", h)
+
+		w.pollOnce(context.Background())
+		w.wg.Wait()
+
+		if len(f.dispatched) != 0 || len(f.acked) != 1 || f.acked[0] != tc.status || f.stopRetry[0] != tc.stopRetry {
+			t.Fatalf("err=%v: dispatched=%d acked=%v stopRetry=%v", tc.err, len(f.dispatched), f.acked, f.stopRetry)
+		}
+		if tc.err == nil && f.results[0] != `{"status":200}` {
+			t.Fatalf("结果未随确认写回: %q", f.results[0])
+		}
+	}
+	if len(h.args) != 3 || h.args[0] != `{"url":"x"}` {
+		t.Fatalf("处理器收到的参数 = %v", h.args)
+	}
+}
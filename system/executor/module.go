@@ -16,6 +16,8 @@ type Module struct {
 	Client         *client.ExecutorClient
 	GRPCService    *grpcsvc.ExecutorService
 	internalWorker *worker.InternalCallbackWorker
+	// internalHandlers 进程内任务处理器，StartInternalWorker 时注册到 worker
+	internalHandlers map[string]callback.InternalJobHandler
 }
 
 // NewModule 创建任务执行器模块实例
@@ -25,9 +27,10 @@ func NewModule() *Module {
 	grpcService := grpcsvc.NewExecutorService(executorClient, internalApp, base.Logger)
 
 	return &Module{
-		internalApp: internalApp,
-		Client:      executorClient,
-		GRPCService: grpcService,
+		internalApp:      internalApp,
+		Client:           executorClient,
+		GRPCService:      grpcService,
+		internalHandlers: make(map[string]callback.InternalJobHandler),
 	}
 }
 
@@ -36,6 +39,12 @@ func (m *Module) RegisterJobCompletionHandler(source string, h callback.JobCompl
 	m.internalApp.JobService.RegisterJobCompletionHandler(source, h)
 }
 
+// RegisterInternalJobHandler 注册进程内任务处理器（target_service=aio 且 method 匹配的任务
+// 由内部 worker 调用处理器执行），须在 StartInternalWorker 之前调用
+func (m *Module) RegisterInternalJobHandler(method string, h callback.InternalJobHandler) {
+	m.internalHandlers[method] = h
+}
+
 // StartInternalWorker 启动进程内 outbox 回调消费者。
 //
 // 参数：
@@ -47,6 +56,9 @@ func (m *Module) StartInternalWorker(env, consumerID string) {
 		m.internalApp.JobService, env, consumerID,
 		base.Logger.WithEntryName("InternalCallbackWorker"),
 	)
+	for method, h := range m.internalHandlers {
+		m.internalWorker.RegisterHandler(method, h)
+	}
 	m.internalWorker.Start()
 }
 
@@ -22,11 +22,21 @@ func (c *WorkflowClient) CreateDef(ctx context.Context, env, code, name, dagJSON
 	return c.app.CreateDef(ctx, env, code, name, dagJSON, version)
 }
 
+// ValidateDef 校验 DAG 而不创建定义
+func (c *WorkflowClient) ValidateDef(dagJSON string) *app.DefLintResult {
+	return c.app.ValidateDef(dagJSON)
+}
+
 // StartWorkflow 启动工作流，env 用于 Executor 任务隔离
 func (c *WorkflowClient) StartWorkflow(ctx context.Context, defCode string, initialData map[string]interface{}, env string) (int64, error) {
 	return c.app.StartWorkflow(ctx, defCode, initialData, env)
 }
 
+// StartWorkflowWithBusinessKey 以业务键启动工作流，该键已有未结束的实例时返回其 ID（existed=true）
+func (c *WorkflowClient) StartWorkflowWithBusinessKey(ctx context.Context, defCode, businessKey string, initialData map[string]interface{}, env string) (int64, bool, error) {
+	return c.app.StartWorkflowWithBusinessKey(ctx, defCode, businessKey, initialData, env)
+}
+
 // ReportNodeCompleted 报告节点执行完成，env 用于后续节点 Executor 任务隔离
 func (c *WorkflowClient) ReportNodeCompleted(ctx context.Context, instanceID int64, nodeID string, output map[string]interface{}, env string) error {
 	return c.app.ReportNodeCompleted(ctx, instanceID, nodeID, output, env)
@@ -95,6 +105,11 @@ func (c *WorkflowClient) GetInstance(ctx context.Context, instanceID int64) (*ap
 	return inst, defCode, nil
 }
 
+// GetInstanceByBusinessKey 按业务键获取最近一个实例
+func (c *WorkflowClient) GetInstanceByBusinessKey(ctx context.Context, env, defCode, businessKey string) (*app.WorkflowInstanceModel, error) {
+	return c.app.GetInstanceByBusinessKey(ctx, env, defCode, businessKey)
+}
+
 // GetInstanceStatus 获取实例状态
 func (c *WorkflowClient) GetInstanceStatus(ctx context.Context, instanceID int64) (string, error) {
 	inst, err := c.app.GetInstance(ctx, instanceID)
@@ -122,11 +137,66 @@ func (c *WorkflowClient) RetryNode(ctx context.Context, instanceID int64, nodeID
 	return c.app.RetryNode(ctx, instanceID, nodeID)
 }
 
+// CompensateInstance 触发实例补偿
+func (c *WorkflowClient) CompensateInstance(ctx context.Context, instanceID int64) error {
+	return c.app.CompensateInstance(ctx, instanceID)
+}
+
+// ForkInstance 从来源实例的检查点分叉新实例
+func (c *WorkflowClient) ForkInstance(ctx context.Context, sourceInstanceID, checkpointID int64, patch map[string]interface{}, env string) (int64, error) {
+	return c.app.ForkInstance(ctx, sourceInstanceID, checkpointID, patch, env)
+}
+
+// PauseInstance 暂停实例
+func (c *WorkflowClient) PauseInstance(ctx context.Context, instanceID int64) error {
+	return c.app.PauseInstance(ctx, instanceID)
+}
+
+// ResumeInstance 恢复实例
+func (c *WorkflowClient) ResumeInstance(ctx context.Context, instanceID int64) error {
+	return c.app.ResumeInstance(ctx, instanceID)
+}
+
+// PauseInstancesByDef 按定义编码与环境批量暂停实例，返回暂停数量
+func (c *WorkflowClient) PauseInstancesByDef(ctx context.Context, defCode, env string) (int64, error) {
+	return c.app.PauseInstancesByDef(ctx, defCode, env)
+}
+
+// ResumeInstancesByDef 按定义编码与环境批量恢复实例，返回恢复数量
+func (c *WorkflowClient) ResumeInstancesByDef(ctx context.Context, defCode, env string) (int64, error) {
+	return c.app.ResumeInstancesByDef(ctx, defCode, env)
+}
+
+// MigrateInstances 把实例迁移到同一定义编码的目标版本
+func (c *WorkflowClient) MigrateInstances(ctx context.Context, in *app.MigrateInstancesInput) (*app.MigrationReport, error) {
+	return c.app.MigrateInstances(ctx, in)
+}
+
+// ListInstanceMigrations 列出实例的迁移审计记录
+func (c *WorkflowClient) ListInstanceMigrations(ctx context.Context, instanceID int64) ([]*app.WorkflowInstanceMigrationModel, error) {
+	return c.app.ListInstanceMigrations(ctx, instanceID)
+}
+
+// RenderDef 把定义的 DAG 渲染为 Mermaid 或 DOT 文本，version=0 表示最新版本
+func (c *WorkflowClient) RenderDef(ctx context.Context, env, code string, version int32, format string) (*app.RenderedGraph, error) {
+	return c.app.RenderDef(ctx, env, code, version, format)
+}
+
+// RenderInstance 渲染实例的 DAG 并按执行进度给节点着色
+func (c *WorkflowClient) RenderInstance(ctx context.Context, instanceID int64, format string) (*app.RenderedGraph, error) {
+	return c.app.RenderInstance(ctx, instanceID, format)
+}
+
 // SendSignal 发送信号（Human-in-the-loop），合并 Payload 入状态，可选唤醒指定节点
 func (c *WorkflowClient) SendSignal(ctx context.Context, instanceID int64, signalName string, payload map[string]interface{}, wakeupNode string, env string) error {
 	return c.app.SendSignal(ctx, instanceID, signalName, payload, wakeupNode, env)
 }
 
+// SendSignalByKey 按 env + 信号名 + 关联键投递信号，推进所有命中的 wait_signal 节点
+func (c *WorkflowClient) SendSignalByKey(ctx context.Context, env, signalName, correlationKey string, payload map[string]interface{}) (*app.SignalDelivery, error) {
+	return c.app.SendSignalByKey(ctx, env, signalName, correlationKey, payload)
+}
+
 func toDTO(t *app.ExecutionTrail) *dto.ExecutionTrail {
 	if t == nil {
 		return nil
@@ -134,18 +204,31 @@ func toDTO(t *app.ExecutionTrail) *dto.ExecutionTrail {
 	checkpoints := make([]dto.ExecutionTrailCheckpoint, len(t.Checkpoints))
 	for i, cp := range t.Checkpoints {
 		checkpoints[i] = dto.ExecutionTrailCheckpoint{
-			NodeID:     cp.NodeID,
-			NodeOutput: cp.NodeOutput,
-			StateAfter: cp.StateAfter,
-			CreatedAt:  cp.CreatedAt,
+			CheckpointID: cp.CheckpointID,
+			NodeID:       cp.NodeID,
+			NodeOutput:   cp.NodeOutput,
+			StateAfter:   cp.StateAfter,
+			CreatedAt:    cp.CreatedAt,
+		}
+	}
+	children := make([]dto.ExecutionTrailChild, len(t.Children))
+	for i, c := range t.Children {
+		children[i] = dto.ExecutionTrailChild{
+			InstanceID: c.InstanceID,
+			NodeID:     c.NodeID,
+			Status:     c.Status,
+			CreatedAt:  c.CreatedAt,
 		}
 	}
 	return &dto.ExecutionTrail{
-		InstanceID:    t.InstanceID,
-		Status:        t.Status,
-		CurrentState:  t.CurrentState,
-		ActiveNodeIDs: t.ActiveNodeIDs,
-		Checkpoints:   checkpoints,
+		InstanceID:       t.InstanceID,
+		Status:           t.Status,
+		CurrentState:     t.CurrentState,
+		ActiveNodeIDs:    t.ActiveNodeIDs,
+		Checkpoints:      checkpoints,
+		ParentInstanceID: t.ParentInstanceID,
+		ParentNodeID:     t.ParentNodeID,
+		Children:         children,
 	}
 }
 
@@ -161,3 +244,58 @@ func toStateDTO(s *app.ExecutionState) *dto.ExecutionState {
 		NotFound:   s.NotFound,
 	}
 }
+
+// CreateSchedule 创建定时调度
+func (c *WorkflowClient) CreateSchedule(ctx context.Context, in *app.ScheduleInput) (*app.WorkflowScheduleModel, error) {
+	return c.app.CreateSchedule(ctx, in)
+}
+
+// UpdateSchedule 整体更新定时调度
+func (c *WorkflowClient) UpdateSchedule(ctx context.Context, id int64, in *app.ScheduleInput) (*app.WorkflowScheduleModel, error) {
+	return c.app.UpdateSchedule(ctx, id, in)
+}
+
+// DeleteSchedule 删除定时调度
+func (c *WorkflowClient) DeleteSchedule(ctx context.Context, id int64) error {
+	return c.app.DeleteSchedule(ctx, id)
+}
+
+// GetSchedule 获取定时调度
+func (c *WorkflowClient) GetSchedule(ctx context.Context, id int64) (*app.WorkflowScheduleModel, error) {
+	return c.app.GetSchedule(ctx, id)
+}
+
+// ListSchedules 分页列出定时调度
+func (c *WorkflowClient) ListSchedules(ctx context.Context, env, defCode string, pageNum, pageSize int32) ([]*app.WorkflowScheduleModel, int64, error) {
+	return c.app.ListSchedules(ctx, env, defCode, pageNum, pageSize)
+}
+
+// PauseSchedule 暂停定时调度
+func (c *WorkflowClient) PauseSchedule(ctx context.Context, id int64) error {
+	return c.app.PauseSchedule(ctx, id)
+}
+
+// ResumeSchedule 恢复定时调度
+func (c *WorkflowClient) ResumeSchedule(ctx context.Context, id int64) error {
+	return c.app.ResumeSchedule(ctx, id)
+}
+
+// RunScheduleNow 立即按调度配置拉起一个实例，返回实例 ID
+func (c *WorkflowClient) RunScheduleNow(ctx context.Context, id int64) (int64, error) {
+	return c.app.RunScheduleNow(ctx, id)
+}
+
+// ListScheduleRuns 分页列出调度的运行历史
+func (c *WorkflowClient) ListScheduleRuns(ctx context.Context, scheduleID int64, pageNum, pageSize int32) ([]*app.WorkflowScheduleRunItem, int64, error) {
+	return c.app.ListScheduleRuns(ctx, scheduleID, pageNum, pageSize)
+}
+
+// WatchInstance 按序号推送实例事件，实例进入终态且事件推送完毕后返回
+func (c *WorkflowClient) WatchInstance(ctx context.Context, instanceID, afterSeq int64, fn func(*app.WorkflowInstanceEventModel) error) error {
+	return c.app.WatchInstance(ctx, instanceID, afterSeq, fn)
+}
+
+// WatchDef 推送某定义编码下所有实例的事件，直到 ctx 结束
+func (c *WorkflowClient) WatchDef(ctx context.Context, env, defCode string, afterEventID int64, fn func(*app.WorkflowInstanceEventModel) error) error {
+	return c.app.WatchDef(ctx, env, defCode, afterEventID, fn)
+}
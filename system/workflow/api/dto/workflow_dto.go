@@ -9,6 +9,31 @@ type CreateDefRequest struct {
 	DAGJSON string `json:"dag_json" validate:"required"`
 }
 
+// ValidateDefRequest 校验工作流定义请求
+type ValidateDefRequest struct {
+	DAGJSON string `json:"dag_json" validate:"required"`
+}
+
+// SimulateRequest 离线模拟请求：dag_json 与 def_code 二选一，模拟在内存中运行，不写库
+type SimulateRequest struct {
+	Env         string                   `json:"env"`      // 环境标识，空则用进程默认环境；def_code 按此环境查找定义
+	DAGJSON     string                   `json:"dag_json"` // 待模拟的 DAG，未保存的修改可直接模拟
+	DefCode     string                   `json:"def_code"` // 模拟已保存的定义
+	Version     int32                    `json:"version"`  // 0 表示最新版本，仅与 def_code 一起使用
+	InitialData map[string]interface{}   `json:"initial"`  // 初始业务数据
+	Mocks       map[string]*SimulateMock `json:"mocks"`    // 节点 ID → 模拟结果，未配置的节点输出空对象
+}
+
+// SimulateMock 单个节点的模拟结果：按调用次序（从 1 开始）命中 fail_on 或只配置了 error 时失败，
+// 否则依次取 outputs（用完后重复最后一项），再否则取 output；timeout 为 true 时触发节点超时
+type SimulateMock struct {
+	Output  map[string]interface{}   `json:"output"`
+	Outputs []map[string]interface{} `json:"outputs"`
+	Error   string                   `json:"error"`
+	FailOn  []int                    `json:"fail_on"`
+	Timeout bool                     `json:"timeout"`
+}
+
 // StartWorkflowRequest 启动工作流请求
 type StartWorkflowRequest struct {
 	DefCode string                 `json:"def_code" validate:"required"`
@@ -35,6 +60,13 @@ type RollbackRequest struct {
 	Env          string `json:"env"` // 环境标识（如 dev/prod/test），空则用进程默认环境
 }
 
+// ForkInstanceRequest 从检查点分叉请求（来源 instance_id 来自 URL 路径）
+type ForkInstanceRequest struct {
+	CheckpointID int64                  `json:"checkpoint_id" validate:"required"` // 分叉点，取自执行轨迹
+	Patch        map[string]interface{} `json:"patch"`                             // JSON Merge Patch，叠加在检查点状态上
+	Env          string                 `json:"env"`                               // 新实例环境，空则沿用来源实例
+}
+
 // SignalRequest 信号请求（Human-in-the-loop 热更新干预，instance_id 来自 URL 路径）
 type SignalRequest struct {
 	SignalName string                 `json:"signal_name" validate:"required"` // 如 "patch_product_image"
@@ -43,6 +75,14 @@ type SignalRequest struct {
 	Env        string                 `json:"env"`                             // 环境标识，空则用实例 env
 }
 
+// SignalByKeyRequest 按关联键投递信号请求，推进所有以该关联键等待该信号的 wait_signal 节点
+type SignalByKeyRequest struct {
+	SignalName     string                 `json:"signal_name" validate:"required"`
+	CorrelationKey string                 `json:"correlation_key" validate:"required"` // 业务关联键，见 wait_signal 节点的 correlation_key
+	Payload        map[string]interface{} `json:"payload"`                             // 作为节点输出合并入 state
+	Env            string                 `json:"env"`                                 // 环境标识，空则用进程默认环境
+}
+
 // ExecutionTrail 执行轨迹（用于前端绘制执行过程）
 type ExecutionTrail struct {
 	InstanceID    int64                      `json:"instance_id"`
@@ -50,14 +90,27 @@ type ExecutionTrail struct {
 	CurrentState  string                     `json:"current_state"`
 	ActiveNodeIDs string                     `json:"active_node_ids"`
 	Checkpoints   []ExecutionTrailCheckpoint `json:"checkpoints"`
+	// ParentInstanceID / ParentNodeID 非零表示本实例由父实例的 subworkflow 节点拉起
+	ParentInstanceID int64                 `json:"parent_instance_id,omitempty"`
+	ParentNodeID     string                `json:"parent_node_id,omitempty"`
+	Children         []ExecutionTrailChild `json:"children,omitempty"`
+}
+
+// ExecutionTrailChild 本实例 subworkflow 节点拉起的子实例
+type ExecutionTrailChild struct {
+	InstanceID int64  `json:"instance_id"`
+	NodeID     string `json:"node_id"`
+	Status     string `json:"status"`
+	CreatedAt  string `json:"created_at"`
 }
 
 // ExecutionTrailCheckpoint 轨迹中的单步快照
 type ExecutionTrailCheckpoint struct {
-	NodeID     string                 `json:"node_id"`
-	NodeOutput map[string]interface{} `json:"node_output"`
-	StateAfter map[string]interface{} `json:"state_after,omitempty"`
-	CreatedAt  string                 `json:"created_at"`
+	CheckpointID int64                  `json:"checkpoint_id"`
+	NodeID       string                 `json:"node_id"`
+	NodeOutput   map[string]interface{} `json:"node_output"`
+	StateAfter   map[string]interface{} `json:"state_after,omitempty"`
+	CreatedAt    string                 `json:"created_at"`
 }
 
 // ExecutionState 按需状态详情。
@@ -68,3 +121,56 @@ type ExecutionState struct {
 	CreatedAt  string `json:"created_at,omitempty"`
 	NotFound   bool   `json:"not_found"`
 }
+
+// ScheduleRequest 创建/更新定时调度请求（更新时为整体覆盖，id 来自 URL 路径）
+type ScheduleRequest struct {
+	Env                 string `json:"env"` // 环境标识，空则用进程默认环境
+	Name                string `json:"name" validate:"required"`
+	DefCode             string `json:"def_code" validate:"required"`
+	DefVersion          int32  `json:"def_version"` // 0 表示每次触发时取最新版本
+	CronExpr            string `json:"cron_expr" validate:"required"`
+	Timezone            string `json:"timezone"`              // IANA 时区名，如 Asia/Shanghai；空表示服务器本地时区
+	InitialDataTemplate string `json:"initial_data_template"` // text/template，可引用 .ScheduledAt/.ScheduleID/.ScheduleName/.Trigger
+	OverlapPolicy       string `json:"overlap_policy" validate:"omitempty,oneof=skip queue allow"`
+}
+
+// RetentionPolicyRequest 创建/更新实例保留策略请求（更新时为整体覆盖，id 来自 URL 路径）
+type RetentionPolicyRequest struct {
+	Env        string `json:"env"`      // 环境标识，空则用进程默认环境
+	DefCode    string `json:"def_code"` // 空表示 env 下所有没有单独策略的定义
+	RetainDays int    `json:"retain_days" validate:"required,min=1"`
+	Action     string `json:"action" validate:"omitempty,oneof=archive export"` // 空表示 archive
+}
+
+// TriggerRequest 创建/更新 Webhook 触发器请求（更新时 code 不可修改，secret 为空表示保留原值）
+type TriggerRequest struct {
+	Code            string                 `json:"code" validate:"required"` // 公开地址 /api/workflow/webhooks/{code}
+	Name            string                 `json:"name" validate:"required"`
+	Env             string                 `json:"env"`
+	DefCode         string                 `json:"def_code" validate:"required"`
+	Disabled        bool                   `json:"disabled"`
+	AuthType        string                 `json:"auth_type" validate:"required,oneof=hmac token"`
+	Secret          string                 `json:"secret"`
+	SignatureHeader string                 `json:"signature_header" validate:"required"` // 如 X-Hub-Signature-256
+	SignaturePrefix string                 `json:"signature_prefix"`                     // 如 sha256=
+	BodyMapping     map[string]interface{} `json:"body_mapping"`                         // 状态路径 -> 表达式，可引用 body/headers/query
+	DedupHeader     string                 `json:"dedup_header"`                         // 如 X-GitHub-Delivery
+	DedupPath       string                 `json:"dedup_path"`                           // 如 data.id
+}
+
+// ApprovalDecisionRequest 审批决定请求（approval_id 来自 URL 路径，审批人取当前登录管理员）
+type ApprovalDecisionRequest struct {
+	Decision string                 `json:"decision" validate:"required,oneof=approve reject request_changes"`
+	Comment  string                 `json:"comment"`
+	Form     map[string]interface{} `json:"form"` // 按审批节点 form_schema 校验
+}
+
+// MigrateInstancesRequest 实例迁移请求（def_code 来自 URL 路径）
+type MigrateInstancesRequest struct {
+	Env           string            `json:"env"`            // 环境标识，空则用进程默认环境
+	TargetVersion int32             `json:"target_version"` // 目标版本，0 表示最新版本
+	InstanceIDs   []int64           `json:"instance_ids"`   // 为空时迁移该定义下全部运行中/等待中/已暂停的实例
+	FromVersion   int32             `json:"from_version"`   // 未指定实例时只迁移该版本的实例，0 表示不限
+	NodeMapping   map[string]string `json:"node_mapping"`   // 活跃节点改名：旧 ID -> 新 ID
+	DryRun        bool              `json:"dry_run"`        // 只校验并报告，不迁移
+}
@@ -141,18 +141,217 @@ func (x *CreateDefResponse) GetDefId() int64 {
 	return 0
 }
 
+// ValidateDefRequest 校验 DAG 而不创建定义
+type ValidateDefRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DagJson       string                 `protobuf:"bytes,1,opt,name=dag_json,json=dagJson,proto3" json:"dag_json,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateDefRequest) Reset() {
+	*x = ValidateDefRequest{}
+	mi := &file_workflow_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateDefRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateDefRequest) ProtoMessage() {}
+
+func (x *ValidateDefRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateDefRequest.ProtoReflect.Descriptor instead.
+func (*ValidateDefRequest) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ValidateDefRequest) GetDagJson() string {
+	if x != nil {
+		return x.DagJson
+	}
+	return ""
+}
+
+// DefLintIssue 定义校验问题；edge_index 为 -1 表示问题不在边上
+type DefLintIssue struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Severity      string                 `protobuf:"bytes,1,opt,name=severity,proto3" json:"severity,omitempty"` // error / warning
+	Code          string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	NodeId        string                 `protobuf:"bytes,3,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	EdgeIndex     int32                  `protobuf:"varint,4,opt,name=edge_index,json=edgeIndex,proto3" json:"edge_index,omitempty"`
+	EdgeFrom      string                 `protobuf:"bytes,5,opt,name=edge_from,json=edgeFrom,proto3" json:"edge_from,omitempty"`
+	EdgeTo        string                 `protobuf:"bytes,6,opt,name=edge_to,json=edgeTo,proto3" json:"edge_to,omitempty"`
+	Field         string                 `protobuf:"bytes,7,opt,name=field,proto3" json:"field,omitempty"`
+	Message       string                 `protobuf:"bytes,8,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DefLintIssue) Reset() {
+	*x = DefLintIssue{}
+	mi := &file_workflow_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DefLintIssue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DefLintIssue) ProtoMessage() {}
+
+func (x *DefLintIssue) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DefLintIssue.ProtoReflect.Descriptor instead.
+func (*DefLintIssue) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *DefLintIssue) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *DefLintIssue) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *DefLintIssue) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *DefLintIssue) GetEdgeIndex() int32 {
+	if x != nil {
+		return x.EdgeIndex
+	}
+	return 0
+}
+
+func (x *DefLintIssue) GetEdgeFrom() string {
+	if x != nil {
+		return x.EdgeFrom
+	}
+	return ""
+}
+
+func (x *DefLintIssue) GetEdgeTo() string {
+	if x != nil {
+		return x.EdgeTo
+	}
+	return ""
+}
+
+func (x *DefLintIssue) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *DefLintIssue) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ValidateDefResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Valid         bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	Issues        []*DefLintIssue        `protobuf:"bytes,2,rep,name=issues,proto3" json:"issues,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateDefResponse) Reset() {
+	*x = ValidateDefResponse{}
+	mi := &file_workflow_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateDefResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateDefResponse) ProtoMessage() {}
+
+func (x *ValidateDefResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateDefResponse.ProtoReflect.Descriptor instead.
+func (*ValidateDefResponse) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ValidateDefResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *ValidateDefResponse) GetIssues() []*DefLintIssue {
+	if x != nil {
+		return x.Issues
+	}
+	return nil
+}
+
 type StartWorkflowRequest struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
 	DefCode         string                 `protobuf:"bytes,1,opt,name=def_code,json=defCode,proto3" json:"def_code,omitempty"`
 	InitialDataJson string                 `protobuf:"bytes,2,opt,name=initial_data_json,json=initialDataJson,proto3" json:"initial_data_json,omitempty"`
 	Env             string                 `protobuf:"bytes,3,opt,name=env,proto3" json:"env,omitempty"`
+	BusinessKey     string                 `protobuf:"bytes,4,opt,name=business_key,json=businessKey,proto3" json:"business_key,omitempty"` // 可选，同一 env + def_code 下未结束的实例唯一，重复启动返回已有实例
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
 
 func (x *StartWorkflowRequest) Reset() {
 	*x = StartWorkflowRequest{}
-	mi := &file_workflow_proto_msgTypes[2]
+	mi := &file_workflow_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -164,7 +363,7 @@ func (x *StartWorkflowRequest) String() string {
 func (*StartWorkflowRequest) ProtoMessage() {}
 
 func (x *StartWorkflowRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_workflow_proto_msgTypes[2]
+	mi := &file_workflow_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -177,7 +376,7 @@ func (x *StartWorkflowRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StartWorkflowRequest.ProtoReflect.Descriptor instead.
 func (*StartWorkflowRequest) Descriptor() ([]byte, []int) {
-	return file_workflow_proto_rawDescGZIP(), []int{2}
+	return file_workflow_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *StartWorkflowRequest) GetDefCode() string {
@@ -201,16 +400,24 @@ func (x *StartWorkflowRequest) GetEnv() string {
 	return ""
 }
 
+func (x *StartWorkflowRequest) GetBusinessKey() string {
+	if x != nil {
+		return x.BusinessKey
+	}
+	return ""
+}
+
 type StartWorkflowResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	InstanceId    int64                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	Existed       bool                   `protobuf:"varint,2,opt,name=existed,proto3" json:"existed,omitempty"` // true 表示业务键已有未结束的实例，未新建
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *StartWorkflowResponse) Reset() {
 	*x = StartWorkflowResponse{}
-	mi := &file_workflow_proto_msgTypes[3]
+	mi := &file_workflow_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -222,7 +429,7 @@ func (x *StartWorkflowResponse) String() string {
 func (*StartWorkflowResponse) ProtoMessage() {}
 
 func (x *StartWorkflowResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_workflow_proto_msgTypes[3]
+	mi := &file_workflow_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -235,7 +442,7 @@ func (x *StartWorkflowResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StartWorkflowResponse.ProtoReflect.Descriptor instead.
 func (*StartWorkflowResponse) Descriptor() ([]byte, []int) {
-	return file_workflow_proto_rawDescGZIP(), []int{3}
+	return file_workflow_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *StartWorkflowResponse) GetInstanceId() int64 {
@@ -245,6 +452,13 @@ func (x *StartWorkflowResponse) GetInstanceId() int64 {
 	return 0
 }
 
+func (x *StartWorkflowResponse) GetExisted() bool {
+	if x != nil {
+		return x.Existed
+	}
+	return false
+}
+
 type ReportNodeCompletedRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	InstanceId    int64                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
@@ -257,7 +471,7 @@ type ReportNodeCompletedRequest struct {
 
 func (x *ReportNodeCompletedRequest) Reset() {
 	*x = ReportNodeCompletedRequest{}
-	mi := &file_workflow_proto_msgTypes[4]
+	mi := &file_workflow_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -269,7 +483,7 @@ func (x *ReportNodeCompletedRequest) String() string {
 func (*ReportNodeCompletedRequest) ProtoMessage() {}
 
 func (x *ReportNodeCompletedRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_workflow_proto_msgTypes[4]
+	mi := &file_workflow_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -282,7 +496,7 @@ func (x *ReportNodeCompletedRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReportNodeCompletedRequest.ProtoReflect.Descriptor instead.
 func (*ReportNodeCompletedRequest) Descriptor() ([]byte, []int) {
-	return file_workflow_proto_rawDescGZIP(), []int{4}
+	return file_workflow_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *ReportNodeCompletedRequest) GetInstanceId() int64 {
@@ -323,7 +537,7 @@ type ReportNodeCompletedResponse struct {
 
 func (x *ReportNodeCompletedResponse) Reset() {
 	*x = ReportNodeCompletedResponse{}
-	mi := &file_workflow_proto_msgTypes[5]
+	mi := &file_workflow_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -335,7 +549,7 @@ func (x *ReportNodeCompletedResponse) String() string {
 func (*ReportNodeCompletedResponse) ProtoMessage() {}
 
 func (x *ReportNodeCompletedResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_workflow_proto_msgTypes[5]
+	mi := &file_workflow_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -348,7 +562,7 @@ func (x *ReportNodeCompletedResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReportNodeCompletedResponse.ProtoReflect.Descriptor instead.
 func (*ReportNodeCompletedResponse) Descriptor() ([]byte, []int) {
-	return file_workflow_proto_rawDescGZIP(), []int{5}
+	return file_workflow_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *ReportNodeCompletedResponse) GetSuccess() bool {
@@ -376,7 +590,7 @@ type RollbackToNodeRequest struct {
 
 func (x *RollbackToNodeRequest) Reset() {
 	*x = RollbackToNodeRequest{}
-	mi := &file_workflow_proto_msgTypes[6]
+	mi := &file_workflow_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -388,7 +602,7 @@ func (x *RollbackToNodeRequest) String() string {
 func (*RollbackToNodeRequest) ProtoMessage() {}
 
 func (x *RollbackToNodeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_workflow_proto_msgTypes[6]
+	mi := &file_workflow_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -401,7 +615,7 @@ func (x *RollbackToNodeRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RollbackToNodeRequest.ProtoReflect.Descriptor instead.
 func (*RollbackToNodeRequest) Descriptor() ([]byte, []int) {
-	return file_workflow_proto_rawDescGZIP(), []int{6}
+	return file_workflow_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *RollbackToNodeRequest) GetInstanceId() int64 {
@@ -435,7 +649,7 @@ type RollbackToNodeResponse struct {
 
 func (x *RollbackToNodeResponse) Reset() {
 	*x = RollbackToNodeResponse{}
-	mi := &file_workflow_proto_msgTypes[7]
+	mi := &file_workflow_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -447,7 +661,7 @@ func (x *RollbackToNodeResponse) String() string {
 func (*RollbackToNodeResponse) ProtoMessage() {}
 
 func (x *RollbackToNodeResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_workflow_proto_msgTypes[7]
+	mi := &file_workflow_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -460,7 +674,7 @@ func (x *RollbackToNodeResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RollbackToNodeResponse.ProtoReflect.Descriptor instead.
 func (*RollbackToNodeResponse) Descriptor() ([]byte, []int) {
-	return file_workflow_proto_rawDescGZIP(), []int{7}
+	return file_workflow_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *RollbackToNodeResponse) GetSuccess() bool {
@@ -487,7 +701,7 @@ type GetExecutionTrailRequest struct {
 
 func (x *GetExecutionTrailRequest) Reset() {
 	*x = GetExecutionTrailRequest{}
-	mi := &file_workflow_proto_msgTypes[8]
+	mi := &file_workflow_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -499,7 +713,7 @@ func (x *GetExecutionTrailRequest) String() string {
 func (*GetExecutionTrailRequest) ProtoMessage() {}
 
 func (x *GetExecutionTrailRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_workflow_proto_msgTypes[8]
+	mi := &file_workflow_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -512,7 +726,7 @@ func (x *GetExecutionTrailRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetExecutionTrailRequest.ProtoReflect.Descriptor instead.
 func (*GetExecutionTrailRequest) Descriptor() ([]byte, []int) {
-	return file_workflow_proto_rawDescGZIP(), []int{8}
+	return file_workflow_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *GetExecutionTrailRequest) GetInstanceId() int64 {
@@ -530,19 +744,22 @@ func (x *GetExecutionTrailRequest) GetIncludeStateAfter() bool {
 }
 
 type GetExecutionTrailResponse struct {
-	state         protoimpl.MessageState      `protogen:"open.v1"`
-	InstanceId    int64                       `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
-	Status        string                      `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
-	CurrentState  string                      `protobuf:"bytes,3,opt,name=current_state,json=currentState,proto3" json:"current_state,omitempty"`
-	ActiveNodeIds string                      `protobuf:"bytes,4,opt,name=active_node_ids,json=activeNodeIds,proto3" json:"active_node_ids,omitempty"`
-	Checkpoints   []*ExecutionTrailCheckpoint `protobuf:"bytes,5,rep,name=checkpoints,proto3" json:"checkpoints,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state            protoimpl.MessageState      `protogen:"open.v1"`
+	InstanceId       int64                       `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	Status           string                      `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	CurrentState     string                      `protobuf:"bytes,3,opt,name=current_state,json=currentState,proto3" json:"current_state,omitempty"`
+	ActiveNodeIds    string                      `protobuf:"bytes,4,opt,name=active_node_ids,json=activeNodeIds,proto3" json:"active_node_ids,omitempty"`
+	Checkpoints      []*ExecutionTrailCheckpoint `protobuf:"bytes,5,rep,name=checkpoints,proto3" json:"checkpoints,omitempty"`
+	ParentInstanceId int64                       `protobuf:"varint,6,opt,name=parent_instance_id,json=parentInstanceId,proto3" json:"parent_instance_id,omitempty"` // 由父实例 subworkflow 节点拉起时非 0
+	ParentNodeId     string                      `protobuf:"bytes,7,opt,name=parent_node_id,json=parentNodeId,proto3" json:"parent_node_id,omitempty"`
+	Children         []*ExecutionTrailChild      `protobuf:"bytes,8,rep,name=children,proto3" json:"children,omitempty"` // 本实例 subworkflow 节点拉起的子实例
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *GetExecutionTrailResponse) Reset() {
 	*x = GetExecutionTrailResponse{}
-	mi := &file_workflow_proto_msgTypes[9]
+	mi := &file_workflow_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -554,7 +771,7 @@ func (x *GetExecutionTrailResponse) String() string {
 func (*GetExecutionTrailResponse) ProtoMessage() {}
 
 func (x *GetExecutionTrailResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_workflow_proto_msgTypes[9]
+	mi := &file_workflow_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -567,7 +784,7 @@ func (x *GetExecutionTrailResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetExecutionTrailResponse.ProtoReflect.Descriptor instead.
 func (*GetExecutionTrailResponse) Descriptor() ([]byte, []int) {
-	return file_workflow_proto_rawDescGZIP(), []int{9}
+	return file_workflow_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *GetExecutionTrailResponse) GetInstanceId() int64 {
@@ -605,31 +822,52 @@ func (x *GetExecutionTrailResponse) GetCheckpoints() []*ExecutionTrailCheckpoint
 	return nil
 }
 
-type ExecutionTrailCheckpoint struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	NodeId         string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
-	NodeOutputJson string                 `protobuf:"bytes,2,opt,name=node_output_json,json=nodeOutputJson,proto3" json:"node_output_json,omitempty"`
-	StateAfterJson string                 `protobuf:"bytes,3,opt,name=state_after_json,json=stateAfterJson,proto3" json:"state_after_json,omitempty"`
-	CreatedAt      string                 `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+func (x *GetExecutionTrailResponse) GetParentInstanceId() int64 {
+	if x != nil {
+		return x.ParentInstanceId
+	}
+	return 0
 }
 
-func (x *ExecutionTrailCheckpoint) Reset() {
-	*x = ExecutionTrailCheckpoint{}
-	mi := &file_workflow_proto_msgTypes[10]
+func (x *GetExecutionTrailResponse) GetParentNodeId() string {
+	if x != nil {
+		return x.ParentNodeId
+	}
+	return ""
+}
+
+func (x *GetExecutionTrailResponse) GetChildren() []*ExecutionTrailChild {
+	if x != nil {
+		return x.Children
+	}
+	return nil
+}
+
+type ExecutionTrailChild struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId    int64                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	NodeId        string                 `protobuf:"bytes,2,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecutionTrailChild) Reset() {
+	*x = ExecutionTrailChild{}
+	mi := &file_workflow_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ExecutionTrailCheckpoint) String() string {
+func (x *ExecutionTrailChild) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ExecutionTrailCheckpoint) ProtoMessage() {}
+func (*ExecutionTrailChild) ProtoMessage() {}
 
-func (x *ExecutionTrailCheckpoint) ProtoReflect() protoreflect.Message {
-	mi := &file_workflow_proto_msgTypes[10]
+func (x *ExecutionTrailChild) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -640,21 +878,90 @@ func (x *ExecutionTrailCheckpoint) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ExecutionTrailCheckpoint.ProtoReflect.Descriptor instead.
-func (*ExecutionTrailCheckpoint) Descriptor() ([]byte, []int) {
-	return file_workflow_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use ExecutionTrailChild.ProtoReflect.Descriptor instead.
+func (*ExecutionTrailChild) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *ExecutionTrailCheckpoint) GetNodeId() string {
+func (x *ExecutionTrailChild) GetInstanceId() int64 {
+	if x != nil {
+		return x.InstanceId
+	}
+	return 0
+}
+
+func (x *ExecutionTrailChild) GetNodeId() string {
 	if x != nil {
 		return x.NodeId
 	}
 	return ""
 }
 
-func (x *ExecutionTrailCheckpoint) GetNodeOutputJson() string {
+func (x *ExecutionTrailChild) GetStatus() string {
 	if x != nil {
-		return x.NodeOutputJson
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ExecutionTrailChild) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type ExecutionTrailCheckpoint struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	NodeId         string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	NodeOutputJson string                 `protobuf:"bytes,2,opt,name=node_output_json,json=nodeOutputJson,proto3" json:"node_output_json,omitempty"`
+	StateAfterJson string                 `protobuf:"bytes,3,opt,name=state_after_json,json=stateAfterJson,proto3" json:"state_after_json,omitempty"`
+	CreatedAt      string                 `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	CheckpointId   int64                  `protobuf:"varint,5,opt,name=checkpoint_id,json=checkpointId,proto3" json:"checkpoint_id,omitempty"` // 可用作 ForkInstance 的分叉点
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ExecutionTrailCheckpoint) Reset() {
+	*x = ExecutionTrailCheckpoint{}
+	mi := &file_workflow_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecutionTrailCheckpoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecutionTrailCheckpoint) ProtoMessage() {}
+
+func (x *ExecutionTrailCheckpoint) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecutionTrailCheckpoint.ProtoReflect.Descriptor instead.
+func (*ExecutionTrailCheckpoint) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ExecutionTrailCheckpoint) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *ExecutionTrailCheckpoint) GetNodeOutputJson() string {
+	if x != nil {
+		return x.NodeOutputJson
 	}
 	return ""
 }
@@ -673,6 +980,13 @@ func (x *ExecutionTrailCheckpoint) GetCreatedAt() string {
 	return ""
 }
 
+func (x *ExecutionTrailCheckpoint) GetCheckpointId() int64 {
+	if x != nil {
+		return x.CheckpointId
+	}
+	return 0
+}
+
 type GetExecutionStateRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	InstanceId    int64                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
@@ -683,7 +997,7 @@ type GetExecutionStateRequest struct {
 
 func (x *GetExecutionStateRequest) Reset() {
 	*x = GetExecutionStateRequest{}
-	mi := &file_workflow_proto_msgTypes[11]
+	mi := &file_workflow_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -695,7 +1009,7 @@ func (x *GetExecutionStateRequest) String() string {
 func (*GetExecutionStateRequest) ProtoMessage() {}
 
 func (x *GetExecutionStateRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_workflow_proto_msgTypes[11]
+	mi := &file_workflow_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -708,7 +1022,7 @@ func (x *GetExecutionStateRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetExecutionStateRequest.ProtoReflect.Descriptor instead.
 func (*GetExecutionStateRequest) Descriptor() ([]byte, []int) {
-	return file_workflow_proto_rawDescGZIP(), []int{11}
+	return file_workflow_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *GetExecutionStateRequest) GetInstanceId() int64 {
@@ -738,7 +1052,7 @@ type GetExecutionStateResponse struct {
 
 func (x *GetExecutionStateResponse) Reset() {
 	*x = GetExecutionStateResponse{}
-	mi := &file_workflow_proto_msgTypes[12]
+	mi := &file_workflow_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -750,7 +1064,7 @@ func (x *GetExecutionStateResponse) String() string {
 func (*GetExecutionStateResponse) ProtoMessage() {}
 
 func (x *GetExecutionStateResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_workflow_proto_msgTypes[12]
+	mi := &file_workflow_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -763,7 +1077,7 @@ func (x *GetExecutionStateResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetExecutionStateResponse.ProtoReflect.Descriptor instead.
 func (*GetExecutionStateResponse) Descriptor() ([]byte, []int) {
-	return file_workflow_proto_rawDescGZIP(), []int{12}
+	return file_workflow_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *GetExecutionStateResponse) GetInstanceId() int64 {
@@ -812,7 +1126,7 @@ type GetDefRequest struct {
 
 func (x *GetDefRequest) Reset() {
 	*x = GetDefRequest{}
-	mi := &file_workflow_proto_msgTypes[13]
+	mi := &file_workflow_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -824,7 +1138,7 @@ func (x *GetDefRequest) String() string {
 func (*GetDefRequest) ProtoMessage() {}
 
 func (x *GetDefRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_workflow_proto_msgTypes[13]
+	mi := &file_workflow_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -837,7 +1151,7 @@ func (x *GetDefRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetDefRequest.ProtoReflect.Descriptor instead.
 func (*GetDefRequest) Descriptor() ([]byte, []int) {
-	return file_workflow_proto_rawDescGZIP(), []int{13}
+	return file_workflow_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *GetDefRequest) GetEnv() string {
@@ -869,14 +1183,16 @@ type GetDefResponse struct {
 	Name          string                 `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
 	DagJson       string                 `protobuf:"bytes,5,opt,name=dag_json,json=dagJson,proto3" json:"dag_json,omitempty"`
 	NotFound      bool                   `protobuf:"varint,6,opt,name=not_found,json=notFound,proto3" json:"not_found,omitempty"`
-	Env           string                 `protobuf:"bytes,7,opt,name=env,proto3" json:"env,omitempty"` // 环境标识
+	Env           string                 `protobuf:"bytes,7,opt,name=env,proto3" json:"env,omitempty"`                                    // 环境标识
+	InputSchema   string                 `protobuf:"bytes,8,opt,name=input_schema,json=inputSchema,proto3" json:"input_schema,omitempty"` // 初始输入 JSON Schema，未声明为空
+	StateSchema   string                 `protobuf:"bytes,9,opt,name=state_schema,json=stateSchema,proto3" json:"state_schema,omitempty"` // 状态 data 区 JSON Schema，未声明为空
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetDefResponse) Reset() {
 	*x = GetDefResponse{}
-	mi := &file_workflow_proto_msgTypes[14]
+	mi := &file_workflow_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -888,7 +1204,7 @@ func (x *GetDefResponse) String() string {
 func (*GetDefResponse) ProtoMessage() {}
 
 func (x *GetDefResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_workflow_proto_msgTypes[14]
+	mi := &file_workflow_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -901,7 +1217,7 @@ func (x *GetDefResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetDefResponse.ProtoReflect.Descriptor instead.
 func (*GetDefResponse) Descriptor() ([]byte, []int) {
-	return file_workflow_proto_rawDescGZIP(), []int{14}
+	return file_workflow_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *GetDefResponse) GetDefId() int64 {
@@ -953,6 +1269,20 @@ func (x *GetDefResponse) GetEnv() string {
 	return ""
 }
 
+func (x *GetDefResponse) GetInputSchema() string {
+	if x != nil {
+		return x.InputSchema
+	}
+	return ""
+}
+
+func (x *GetDefResponse) GetStateSchema() string {
+	if x != nil {
+		return x.StateSchema
+	}
+	return ""
+}
+
 type ListDefsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Env           string                 `protobuf:"bytes,4,opt,name=env,proto3" json:"env,omitempty"` // 环境标识
@@ -965,7 +1295,7 @@ type ListDefsRequest struct {
 
 func (x *ListDefsRequest) Reset() {
 	*x = ListDefsRequest{}
-	mi := &file_workflow_proto_msgTypes[15]
+	mi := &file_workflow_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -977,7 +1307,7 @@ func (x *ListDefsRequest) String() string {
 func (*ListDefsRequest) ProtoMessage() {}
 
 func (x *ListDefsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_workflow_proto_msgTypes[15]
+	mi := &file_workflow_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -990,7 +1320,7 @@ func (x *ListDefsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListDefsRequest.ProtoReflect.Descriptor instead.
 func (*ListDefsRequest) Descriptor() ([]byte, []int) {
-	return file_workflow_proto_rawDescGZIP(), []int{15}
+	return file_workflow_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *ListDefsRequest) GetEnv() string {
@@ -1031,7 +1361,7 @@ type ListDefsResponse struct {
 
 func (x *ListDefsResponse) Reset() {
 	*x = ListDefsResponse{}
-	mi := &file_workflow_proto_msgTypes[16]
+	mi := &file_workflow_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1043,7 +1373,7 @@ func (x *ListDefsResponse) String() string {
 func (*ListDefsResponse) ProtoMessage() {}
 
 func (x *ListDefsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_workflow_proto_msgTypes[16]
+	mi := &file_workflow_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1056,7 +1386,7 @@ func (x *ListDefsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListDefsResponse.ProtoReflect.Descriptor instead.
 func (*ListDefsResponse) Descriptor() ([]byte, []int) {
-	return file_workflow_proto_rawDescGZIP(), []int{16}
+	return file_workflow_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *ListDefsResponse) GetItems() []*GetDefResponse {
@@ -1086,7 +1416,7 @@ type CreateIfNotExistsRequest struct {
 
 func (x *CreateIfNotExistsRequest) Reset() {
 	*x = CreateIfNotExistsRequest{}
-	mi := &file_workflow_proto_msgTypes[17]
+	mi := &file_workflow_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1098,7 +1428,7 @@ func (x *CreateIfNotExistsRequest) String() string {
 func (*CreateIfNotExistsRequest) ProtoMessage() {}
 
 func (x *CreateIfNotExistsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_workflow_proto_msgTypes[17]
+	mi := &file_workflow_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1111,7 +1441,7 @@ func (x *CreateIfNotExistsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateIfNotExistsRequest.ProtoReflect.Descriptor instead.
 func (*CreateIfNotExistsRequest) Descriptor() ([]byte, []int) {
-	return file_workflow_proto_rawDescGZIP(), []int{17}
+	return file_workflow_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *CreateIfNotExistsRequest) GetEnv() string {
@@ -1159,7 +1489,7 @@ type CreateIfNotExistsResponse struct {
 
 func (x *CreateIfNotExistsResponse) Reset() {
 	*x = CreateIfNotExistsResponse{}
-	mi := &file_workflow_proto_msgTypes[18]
+	mi := &file_workflow_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1171,7 +1501,7 @@ func (x *CreateIfNotExistsResponse) String() string {
 func (*CreateIfNotExistsResponse) ProtoMessage() {}
 
 func (x *CreateIfNotExistsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_workflow_proto_msgTypes[18]
+	mi := &file_workflow_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1184,7 +1514,7 @@ func (x *CreateIfNotExistsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateIfNotExistsResponse.ProtoReflect.Descriptor instead.
 func (*CreateIfNotExistsResponse) Descriptor() ([]byte, []int) {
-	return file_workflow_proto_rawDescGZIP(), []int{18}
+	return file_workflow_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *CreateIfNotExistsResponse) GetDefId() int64 {
@@ -1210,7 +1540,7 @@ type GetInstanceRequest struct {
 
 func (x *GetInstanceRequest) Reset() {
 	*x = GetInstanceRequest{}
-	mi := &file_workflow_proto_msgTypes[19]
+	mi := &file_workflow_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1222,7 +1552,7 @@ func (x *GetInstanceRequest) String() string {
 func (*GetInstanceRequest) ProtoMessage() {}
 
 func (x *GetInstanceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_workflow_proto_msgTypes[19]
+	mi := &file_workflow_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1235,7 +1565,7 @@ func (x *GetInstanceRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetInstanceRequest.ProtoReflect.Descriptor instead.
 func (*GetInstanceRequest) Descriptor() ([]byte, []int) {
-	return file_workflow_proto_rawDescGZIP(), []int{19}
+	return file_workflow_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *GetInstanceRequest) GetInstanceId() int64 {
@@ -1246,25 +1576,30 @@ func (x *GetInstanceRequest) GetInstanceId() int64 {
 }
 
 type GetInstanceResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	InstanceId    int64                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
-	DefId         int64                  `protobuf:"varint,2,opt,name=def_id,json=defId,proto3" json:"def_id,omitempty"`
-	DefCode       string                 `protobuf:"bytes,3,opt,name=def_code,json=defCode,proto3" json:"def_code,omitempty"`
-	DefVersion    int32                  `protobuf:"varint,4,opt,name=def_version,json=defVersion,proto3" json:"def_version,omitempty"`
-	Env           string                 `protobuf:"bytes,5,opt,name=env,proto3" json:"env,omitempty"`
-	Status        string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
-	InitialState  string                 `protobuf:"bytes,7,opt,name=initial_state,json=initialState,proto3" json:"initial_state,omitempty"`
-	CurrentState  string                 `protobuf:"bytes,8,opt,name=current_state,json=currentState,proto3" json:"current_state,omitempty"`
-	ActiveNodeIds string                 `protobuf:"bytes,9,opt,name=active_node_ids,json=activeNodeIds,proto3" json:"active_node_ids,omitempty"`
-	CreatedAt     string                 `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	NotFound      bool                   `protobuf:"varint,11,opt,name=not_found,json=notFound,proto3" json:"not_found,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId             int64                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	DefId                  int64                  `protobuf:"varint,2,opt,name=def_id,json=defId,proto3" json:"def_id,omitempty"`
+	DefCode                string                 `protobuf:"bytes,3,opt,name=def_code,json=defCode,proto3" json:"def_code,omitempty"`
+	DefVersion             int32                  `protobuf:"varint,4,opt,name=def_version,json=defVersion,proto3" json:"def_version,omitempty"`
+	Env                    string                 `protobuf:"bytes,5,opt,name=env,proto3" json:"env,omitempty"`
+	Status                 string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	InitialState           string                 `protobuf:"bytes,7,opt,name=initial_state,json=initialState,proto3" json:"initial_state,omitempty"`
+	CurrentState           string                 `protobuf:"bytes,8,opt,name=current_state,json=currentState,proto3" json:"current_state,omitempty"`
+	ActiveNodeIds          string                 `protobuf:"bytes,9,opt,name=active_node_ids,json=activeNodeIds,proto3" json:"active_node_ids,omitempty"`
+	CreatedAt              string                 `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	NotFound               bool                   `protobuf:"varint,11,opt,name=not_found,json=notFound,proto3" json:"not_found,omitempty"`
+	ParentInstanceId       int64                  `protobuf:"varint,12,opt,name=parent_instance_id,json=parentInstanceId,proto3" json:"parent_instance_id,omitempty"` // 由父实例 subworkflow 节点拉起时非 0
+	ParentNodeId           string                 `protobuf:"bytes,13,opt,name=parent_node_id,json=parentNodeId,proto3" json:"parent_node_id,omitempty"`
+	ForkedFromInstanceId   int64                  `protobuf:"varint,14,opt,name=forked_from_instance_id,json=forkedFromInstanceId,proto3" json:"forked_from_instance_id,omitempty"` // 由 ForkInstance 从检查点分叉创建时非 0
+	ForkedFromCheckpointId int64                  `protobuf:"varint,15,opt,name=forked_from_checkpoint_id,json=forkedFromCheckpointId,proto3" json:"forked_from_checkpoint_id,omitempty"`
+	BusinessKey            string                 `protobuf:"bytes,16,opt,name=business_key,json=businessKey,proto3" json:"business_key,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
 }
 
 func (x *GetInstanceResponse) Reset() {
 	*x = GetInstanceResponse{}
-	mi := &file_workflow_proto_msgTypes[20]
+	mi := &file_workflow_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1276,7 +1611,7 @@ func (x *GetInstanceResponse) String() string {
 func (*GetInstanceResponse) ProtoMessage() {}
 
 func (x *GetInstanceResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_workflow_proto_msgTypes[20]
+	mi := &file_workflow_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1289,7 +1624,7 @@ func (x *GetInstanceResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetInstanceResponse.ProtoReflect.Descriptor instead.
 func (*GetInstanceResponse) Descriptor() ([]byte, []int) {
-	return file_workflow_proto_rawDescGZIP(), []int{20}
+	return file_workflow_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *GetInstanceResponse) GetInstanceId() int64 {
@@ -1369,6 +1704,102 @@ func (x *GetInstanceResponse) GetNotFound() bool {
 	return false
 }
 
+func (x *GetInstanceResponse) GetParentInstanceId() int64 {
+	if x != nil {
+		return x.ParentInstanceId
+	}
+	return 0
+}
+
+func (x *GetInstanceResponse) GetParentNodeId() string {
+	if x != nil {
+		return x.ParentNodeId
+	}
+	return ""
+}
+
+func (x *GetInstanceResponse) GetForkedFromInstanceId() int64 {
+	if x != nil {
+		return x.ForkedFromInstanceId
+	}
+	return 0
+}
+
+func (x *GetInstanceResponse) GetForkedFromCheckpointId() int64 {
+	if x != nil {
+		return x.ForkedFromCheckpointId
+	}
+	return 0
+}
+
+func (x *GetInstanceResponse) GetBusinessKey() string {
+	if x != nil {
+		return x.BusinessKey
+	}
+	return ""
+}
+
+// GetInstanceByBusinessKeyRequest 按业务键查询最近一个实例（可能已结束）
+type GetInstanceByBusinessKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Env           string                 `protobuf:"bytes,1,opt,name=env,proto3" json:"env,omitempty"` // 空则用服务端默认环境
+	DefCode       string                 `protobuf:"bytes,2,opt,name=def_code,json=defCode,proto3" json:"def_code,omitempty"`
+	BusinessKey   string                 `protobuf:"bytes,3,opt,name=business_key,json=businessKey,proto3" json:"business_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetInstanceByBusinessKeyRequest) Reset() {
+	*x = GetInstanceByBusinessKeyRequest{}
+	mi := &file_workflow_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetInstanceByBusinessKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInstanceByBusinessKeyRequest) ProtoMessage() {}
+
+func (x *GetInstanceByBusinessKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInstanceByBusinessKeyRequest.ProtoReflect.Descriptor instead.
+func (*GetInstanceByBusinessKeyRequest) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetInstanceByBusinessKeyRequest) GetEnv() string {
+	if x != nil {
+		return x.Env
+	}
+	return ""
+}
+
+func (x *GetInstanceByBusinessKeyRequest) GetDefCode() string {
+	if x != nil {
+		return x.DefCode
+	}
+	return ""
+}
+
+func (x *GetInstanceByBusinessKeyRequest) GetBusinessKey() string {
+	if x != nil {
+		return x.BusinessKey
+	}
+	return ""
+}
+
 type GetInstanceStatusRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	InstanceId    int64                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
@@ -1378,7 +1809,7 @@ type GetInstanceStatusRequest struct {
 
 func (x *GetInstanceStatusRequest) Reset() {
 	*x = GetInstanceStatusRequest{}
-	mi := &file_workflow_proto_msgTypes[21]
+	mi := &file_workflow_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1390,7 +1821,7 @@ func (x *GetInstanceStatusRequest) String() string {
 func (*GetInstanceStatusRequest) ProtoMessage() {}
 
 func (x *GetInstanceStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_workflow_proto_msgTypes[21]
+	mi := &file_workflow_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1403,7 +1834,7 @@ func (x *GetInstanceStatusRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetInstanceStatusRequest.ProtoReflect.Descriptor instead.
 func (*GetInstanceStatusRequest) Descriptor() ([]byte, []int) {
-	return file_workflow_proto_rawDescGZIP(), []int{21}
+	return file_workflow_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *GetInstanceStatusRequest) GetInstanceId() int64 {
@@ -1423,7 +1854,7 @@ type GetInstanceStatusResponse struct {
 
 func (x *GetInstanceStatusResponse) Reset() {
 	*x = GetInstanceStatusResponse{}
-	mi := &file_workflow_proto_msgTypes[22]
+	mi := &file_workflow_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1435,7 +1866,7 @@ func (x *GetInstanceStatusResponse) String() string {
 func (*GetInstanceStatusResponse) ProtoMessage() {}
 
 func (x *GetInstanceStatusResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_workflow_proto_msgTypes[22]
+	mi := &file_workflow_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1448,7 +1879,7 @@ func (x *GetInstanceStatusResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetInstanceStatusResponse.ProtoReflect.Descriptor instead.
 func (*GetInstanceStatusResponse) Descriptor() ([]byte, []int) {
-	return file_workflow_proto_rawDescGZIP(), []int{22}
+	return file_workflow_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *GetInstanceStatusResponse) GetStatus() string {
@@ -1473,13 +1904,14 @@ type ListInstancesRequest struct {
 	CreatedBefore int64                  `protobuf:"varint,4,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`
 	PageNum       int32                  `protobuf:"varint,5,opt,name=page_num,json=pageNum,proto3" json:"page_num,omitempty"`
 	PageSize      int32                  `protobuf:"varint,6,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	BusinessKey   string                 `protobuf:"bytes,7,opt,name=business_key,json=businessKey,proto3" json:"business_key,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ListInstancesRequest) Reset() {
 	*x = ListInstancesRequest{}
-	mi := &file_workflow_proto_msgTypes[23]
+	mi := &file_workflow_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1491,7 +1923,7 @@ func (x *ListInstancesRequest) String() string {
 func (*ListInstancesRequest) ProtoMessage() {}
 
 func (x *ListInstancesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_workflow_proto_msgTypes[23]
+	mi := &file_workflow_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1504,7 +1936,7 @@ func (x *ListInstancesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListInstancesRequest.ProtoReflect.Descriptor instead.
 func (*ListInstancesRequest) Descriptor() ([]byte, []int) {
-	return file_workflow_proto_rawDescGZIP(), []int{23}
+	return file_workflow_proto_rawDescGZIP(), []int{28}
 }
 
 func (x *ListInstancesRequest) GetDefCode() string {
@@ -1549,6 +1981,13 @@ func (x *ListInstancesRequest) GetPageSize() int32 {
 	return 0
 }
 
+func (x *ListInstancesRequest) GetBusinessKey() string {
+	if x != nil {
+		return x.BusinessKey
+	}
+	return ""
+}
+
 type ListInstancesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Items         []*GetInstanceResponse `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
@@ -1559,7 +1998,7 @@ type ListInstancesResponse struct {
 
 func (x *ListInstancesResponse) Reset() {
 	*x = ListInstancesResponse{}
-	mi := &file_workflow_proto_msgTypes[24]
+	mi := &file_workflow_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1571,7 +2010,7 @@ func (x *ListInstancesResponse) String() string {
 func (*ListInstancesResponse) ProtoMessage() {}
 
 func (x *ListInstancesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_workflow_proto_msgTypes[24]
+	mi := &file_workflow_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1584,7 +2023,7 @@ func (x *ListInstancesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListInstancesResponse.ProtoReflect.Descriptor instead.
 func (*ListInstancesResponse) Descriptor() ([]byte, []int) {
-	return file_workflow_proto_rawDescGZIP(), []int{24}
+	return file_workflow_proto_rawDescGZIP(), []int{29}
 }
 
 func (x *ListInstancesResponse) GetItems() []*GetInstanceResponse {
@@ -1610,7 +2049,7 @@ type CancelInstanceRequest struct {
 
 func (x *CancelInstanceRequest) Reset() {
 	*x = CancelInstanceRequest{}
-	mi := &file_workflow_proto_msgTypes[25]
+	mi := &file_workflow_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1622,7 +2061,7 @@ func (x *CancelInstanceRequest) String() string {
 func (*CancelInstanceRequest) ProtoMessage() {}
 
 func (x *CancelInstanceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_workflow_proto_msgTypes[25]
+	mi := &file_workflow_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1635,7 +2074,7 @@ func (x *CancelInstanceRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CancelInstanceRequest.ProtoReflect.Descriptor instead.
 func (*CancelInstanceRequest) Descriptor() ([]byte, []int) {
-	return file_workflow_proto_rawDescGZIP(), []int{25}
+	return file_workflow_proto_rawDescGZIP(), []int{30}
 }
 
 func (x *CancelInstanceRequest) GetInstanceId() int64 {
@@ -1655,7 +2094,7 @@ type CancelInstanceResponse struct {
 
 func (x *CancelInstanceResponse) Reset() {
 	*x = CancelInstanceResponse{}
-	mi := &file_workflow_proto_msgTypes[26]
+	mi := &file_workflow_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1667,7 +2106,7 @@ func (x *CancelInstanceResponse) String() string {
 func (*CancelInstanceResponse) ProtoMessage() {}
 
 func (x *CancelInstanceResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_workflow_proto_msgTypes[26]
+	mi := &file_workflow_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1680,7 +2119,7 @@ func (x *CancelInstanceResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CancelInstanceResponse.ProtoReflect.Descriptor instead.
 func (*CancelInstanceResponse) Descriptor() ([]byte, []int) {
-	return file_workflow_proto_rawDescGZIP(), []int{26}
+	return file_workflow_proto_rawDescGZIP(), []int{31}
 }
 
 func (x *CancelInstanceResponse) GetSuccess() bool {
@@ -1707,7 +2146,7 @@ type RetryNodeRequest struct {
 
 func (x *RetryNodeRequest) Reset() {
 	*x = RetryNodeRequest{}
-	mi := &file_workflow_proto_msgTypes[27]
+	mi := &file_workflow_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1719,7 +2158,7 @@ func (x *RetryNodeRequest) String() string {
 func (*RetryNodeRequest) ProtoMessage() {}
 
 func (x *RetryNodeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_workflow_proto_msgTypes[27]
+	mi := &file_workflow_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1732,7 +2171,7 @@ func (x *RetryNodeRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RetryNodeRequest.ProtoReflect.Descriptor instead.
 func (*RetryNodeRequest) Descriptor() ([]byte, []int) {
-	return file_workflow_proto_rawDescGZIP(), []int{27}
+	return file_workflow_proto_rawDescGZIP(), []int{32}
 }
 
 func (x *RetryNodeRequest) GetInstanceId() int64 {
@@ -1759,7 +2198,7 @@ type RetryNodeResponse struct {
 
 func (x *RetryNodeResponse) Reset() {
 	*x = RetryNodeResponse{}
-	mi := &file_workflow_proto_msgTypes[28]
+	mi := &file_workflow_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1771,7 +2210,7 @@ func (x *RetryNodeResponse) String() string {
 func (*RetryNodeResponse) ProtoMessage() {}
 
 func (x *RetryNodeResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_workflow_proto_msgTypes[28]
+	mi := &file_workflow_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1784,7 +2223,7 @@ func (x *RetryNodeResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RetryNodeResponse.ProtoReflect.Descriptor instead.
 func (*RetryNodeResponse) Descriptor() ([]byte, []int) {
-	return file_workflow_proto_rawDescGZIP(), []int{28}
+	return file_workflow_proto_rawDescGZIP(), []int{33}
 }
 
 func (x *RetryNodeResponse) GetSuccess() bool {
@@ -1801,10 +2240,2878 @@ func (x *RetryNodeResponse) GetMessage() string {
 	return ""
 }
 
-var File_workflow_proto protoreflect.FileDescriptor
+// CompensateInstanceRequest 逆序执行已完成节点的 compensate 任务
+type CompensateInstanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId    int64                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_workflow_proto_rawDesc = "" +
-	"\n" +
+func (x *CompensateInstanceRequest) Reset() {
+	*x = CompensateInstanceRequest{}
+	mi := &file_workflow_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompensateInstanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompensateInstanceRequest) ProtoMessage() {}
+
+func (x *CompensateInstanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompensateInstanceRequest.ProtoReflect.Descriptor instead.
+func (*CompensateInstanceRequest) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *CompensateInstanceRequest) GetInstanceId() int64 {
+	if x != nil {
+		return x.InstanceId
+	}
+	return 0
+}
+
+type CompensateInstanceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompensateInstanceResponse) Reset() {
+	*x = CompensateInstanceResponse{}
+	mi := &file_workflow_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompensateInstanceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompensateInstanceResponse) ProtoMessage() {}
+
+func (x *CompensateInstanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompensateInstanceResponse.ProtoReflect.Descriptor instead.
+func (*CompensateInstanceResponse) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *CompensateInstanceResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *CompensateInstanceResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// ForkInstanceRequest 从来源实例的某个检查点分叉出同一定义版本的新实例
+type ForkInstanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId    int64                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`       // 来源实例
+	CheckpointId  int64                  `protobuf:"varint,2,opt,name=checkpoint_id,json=checkpointId,proto3" json:"checkpoint_id,omitempty"` // 分叉点，取自执行轨迹
+	PatchJson     string                 `protobuf:"bytes,3,opt,name=patch_json,json=patchJson,proto3" json:"patch_json,omitempty"`           // 可选，JSON Merge Patch，叠加在检查点状态上
+	Env           string                 `protobuf:"bytes,4,opt,name=env,proto3" json:"env,omitempty"`                                        // 可选，新实例环境，为空时沿用来源实例
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForkInstanceRequest) Reset() {
+	*x = ForkInstanceRequest{}
+	mi := &file_workflow_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForkInstanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForkInstanceRequest) ProtoMessage() {}
+
+func (x *ForkInstanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForkInstanceRequest.ProtoReflect.Descriptor instead.
+func (*ForkInstanceRequest) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ForkInstanceRequest) GetInstanceId() int64 {
+	if x != nil {
+		return x.InstanceId
+	}
+	return 0
+}
+
+func (x *ForkInstanceRequest) GetCheckpointId() int64 {
+	if x != nil {
+		return x.CheckpointId
+	}
+	return 0
+}
+
+func (x *ForkInstanceRequest) GetPatchJson() string {
+	if x != nil {
+		return x.PatchJson
+	}
+	return ""
+}
+
+func (x *ForkInstanceRequest) GetEnv() string {
+	if x != nil {
+		return x.Env
+	}
+	return ""
+}
+
+type ForkInstanceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId    int64                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForkInstanceResponse) Reset() {
+	*x = ForkInstanceResponse{}
+	mi := &file_workflow_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForkInstanceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForkInstanceResponse) ProtoMessage() {}
+
+func (x *ForkInstanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForkInstanceResponse.ProtoReflect.Descriptor instead.
+func (*ForkInstanceResponse) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ForkInstanceResponse) GetInstanceId() int64 {
+	if x != nil {
+		return x.InstanceId
+	}
+	return 0
+}
+
+// PauseInstanceRequest 暂停实例：照常接收回调，恢复前不派发后继节点
+type PauseInstanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId    int64                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseInstanceRequest) Reset() {
+	*x = PauseInstanceRequest{}
+	mi := &file_workflow_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseInstanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseInstanceRequest) ProtoMessage() {}
+
+func (x *PauseInstanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseInstanceRequest.ProtoReflect.Descriptor instead.
+func (*PauseInstanceRequest) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *PauseInstanceRequest) GetInstanceId() int64 {
+	if x != nil {
+		return x.InstanceId
+	}
+	return 0
+}
+
+type PauseInstanceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseInstanceResponse) Reset() {
+	*x = PauseInstanceResponse{}
+	mi := &file_workflow_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseInstanceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseInstanceResponse) ProtoMessage() {}
+
+func (x *PauseInstanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseInstanceResponse.ProtoReflect.Descriptor instead.
+func (*PauseInstanceResponse) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *PauseInstanceResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *PauseInstanceResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ResumeInstanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId    int64                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeInstanceRequest) Reset() {
+	*x = ResumeInstanceRequest{}
+	mi := &file_workflow_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeInstanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeInstanceRequest) ProtoMessage() {}
+
+func (x *ResumeInstanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeInstanceRequest.ProtoReflect.Descriptor instead.
+func (*ResumeInstanceRequest) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ResumeInstanceRequest) GetInstanceId() int64 {
+	if x != nil {
+		return x.InstanceId
+	}
+	return 0
+}
+
+type ResumeInstanceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeInstanceResponse) Reset() {
+	*x = ResumeInstanceResponse{}
+	mi := &file_workflow_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeInstanceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeInstanceResponse) ProtoMessage() {}
+
+func (x *ResumeInstanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeInstanceResponse.ProtoReflect.Descriptor instead.
+func (*ResumeInstanceResponse) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ResumeInstanceResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ResumeInstanceResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// PauseInstancesByDefRequest 按定义编码（全部版本）与环境批量暂停运行中/等待中的实例
+type PauseInstancesByDefRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DefCode       string                 `protobuf:"bytes,1,opt,name=def_code,json=defCode,proto3" json:"def_code,omitempty"`
+	Env           string                 `protobuf:"bytes,2,opt,name=env,proto3" json:"env,omitempty"` // 环境标识，为空时使用服务端默认环境
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseInstancesByDefRequest) Reset() {
+	*x = PauseInstancesByDefRequest{}
+	mi := &file_workflow_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseInstancesByDefRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseInstancesByDefRequest) ProtoMessage() {}
+
+func (x *PauseInstancesByDefRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseInstancesByDefRequest.ProtoReflect.Descriptor instead.
+func (*PauseInstancesByDefRequest) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *PauseInstancesByDefRequest) GetDefCode() string {
+	if x != nil {
+		return x.DefCode
+	}
+	return ""
+}
+
+func (x *PauseInstancesByDefRequest) GetEnv() string {
+	if x != nil {
+		return x.Env
+	}
+	return ""
+}
+
+type PauseInstancesByDefResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Affected      int64                  `protobuf:"varint,1,opt,name=affected,proto3" json:"affected,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseInstancesByDefResponse) Reset() {
+	*x = PauseInstancesByDefResponse{}
+	mi := &file_workflow_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseInstancesByDefResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseInstancesByDefResponse) ProtoMessage() {}
+
+func (x *PauseInstancesByDefResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseInstancesByDefResponse.ProtoReflect.Descriptor instead.
+func (*PauseInstancesByDefResponse) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *PauseInstancesByDefResponse) GetAffected() int64 {
+	if x != nil {
+		return x.Affected
+	}
+	return 0
+}
+
+// ResumeInstancesByDefRequest 按定义编码（全部版本）与环境批量恢复已暂停的实例
+type ResumeInstancesByDefRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DefCode       string                 `protobuf:"bytes,1,opt,name=def_code,json=defCode,proto3" json:"def_code,omitempty"`
+	Env           string                 `protobuf:"bytes,2,opt,name=env,proto3" json:"env,omitempty"` // 环境标识，为空时使用服务端默认环境
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeInstancesByDefRequest) Reset() {
+	*x = ResumeInstancesByDefRequest{}
+	mi := &file_workflow_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeInstancesByDefRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeInstancesByDefRequest) ProtoMessage() {}
+
+func (x *ResumeInstancesByDefRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeInstancesByDefRequest.ProtoReflect.Descriptor instead.
+func (*ResumeInstancesByDefRequest) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ResumeInstancesByDefRequest) GetDefCode() string {
+	if x != nil {
+		return x.DefCode
+	}
+	return ""
+}
+
+func (x *ResumeInstancesByDefRequest) GetEnv() string {
+	if x != nil {
+		return x.Env
+	}
+	return ""
+}
+
+type ResumeInstancesByDefResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Affected      int64                  `protobuf:"varint,1,opt,name=affected,proto3" json:"affected,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeInstancesByDefResponse) Reset() {
+	*x = ResumeInstancesByDefResponse{}
+	mi := &file_workflow_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeInstancesByDefResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeInstancesByDefResponse) ProtoMessage() {}
+
+func (x *ResumeInstancesByDefResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeInstancesByDefResponse.ProtoReflect.Descriptor instead.
+func (*ResumeInstancesByDefResponse) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *ResumeInstancesByDefResponse) GetAffected() int64 {
+	if x != nil {
+		return x.Affected
+	}
+	return 0
+}
+
+// MigrateInstancesRequest 把实例迁移到同一定义编码的目标版本
+type MigrateInstancesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DefCode       string                 `protobuf:"bytes,1,opt,name=def_code,json=defCode,proto3" json:"def_code,omitempty"`
+	Env           string                 `protobuf:"bytes,2,opt,name=env,proto3" json:"env,omitempty"`                                                                                                              // 环境标识，为空时使用服务端默认环境
+	TargetVersion int32                  `protobuf:"varint,3,opt,name=target_version,json=targetVersion,proto3" json:"target_version,omitempty"`                                                                    // 目标版本，0 表示最新版本
+	InstanceIds   []int64                `protobuf:"varint,4,rep,packed,name=instance_ids,json=instanceIds,proto3" json:"instance_ids,omitempty"`                                                                   // 指定实例；为空时迁移该定义下全部运行中/等待中/已暂停的实例
+	FromVersion   int32                  `protobuf:"varint,5,opt,name=from_version,json=fromVersion,proto3" json:"from_version,omitempty"`                                                                          // 未指定实例时只迁移该版本的实例，0 表示不限
+	NodeMapping   map[string]string      `protobuf:"bytes,6,rep,name=node_mapping,json=nodeMapping,proto3" json:"node_mapping,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // 活跃节点改名：旧 ID -> 新 ID
+	DryRun        bool                   `protobuf:"varint,7,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`                                                                                         // 只校验并报告，不迁移
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MigrateInstancesRequest) Reset() {
+	*x = MigrateInstancesRequest{}
+	mi := &file_workflow_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MigrateInstancesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MigrateInstancesRequest) ProtoMessage() {}
+
+func (x *MigrateInstancesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MigrateInstancesRequest.ProtoReflect.Descriptor instead.
+func (*MigrateInstancesRequest) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *MigrateInstancesRequest) GetDefCode() string {
+	if x != nil {
+		return x.DefCode
+	}
+	return ""
+}
+
+func (x *MigrateInstancesRequest) GetEnv() string {
+	if x != nil {
+		return x.Env
+	}
+	return ""
+}
+
+func (x *MigrateInstancesRequest) GetTargetVersion() int32 {
+	if x != nil {
+		return x.TargetVersion
+	}
+	return 0
+}
+
+func (x *MigrateInstancesRequest) GetInstanceIds() []int64 {
+	if x != nil {
+		return x.InstanceIds
+	}
+	return nil
+}
+
+func (x *MigrateInstancesRequest) GetFromVersion() int32 {
+	if x != nil {
+		return x.FromVersion
+	}
+	return 0
+}
+
+func (x *MigrateInstancesRequest) GetNodeMapping() map[string]string {
+	if x != nil {
+		return x.NodeMapping
+	}
+	return nil
+}
+
+func (x *MigrateInstancesRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type InstanceMigrationResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId    int64                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	FromVersion   int32                  `protobuf:"varint,2,opt,name=from_version,json=fromVersion,proto3" json:"from_version,omitempty"`
+	Migrated      bool                   `protobuf:"varint,3,opt,name=migrated,proto3" json:"migrated,omitempty"`                                 // dry_run 时表示可以迁移
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`                                      // 不能迁移的原因
+	ActiveNodeIds []string               `protobuf:"bytes,5,rep,name=active_node_ids,json=activeNodeIds,proto3" json:"active_node_ids,omitempty"` // 迁移后的活跃节点
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InstanceMigrationResult) Reset() {
+	*x = InstanceMigrationResult{}
+	mi := &file_workflow_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InstanceMigrationResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstanceMigrationResult) ProtoMessage() {}
+
+func (x *InstanceMigrationResult) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstanceMigrationResult.ProtoReflect.Descriptor instead.
+func (*InstanceMigrationResult) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *InstanceMigrationResult) GetInstanceId() int64 {
+	if x != nil {
+		return x.InstanceId
+	}
+	return 0
+}
+
+func (x *InstanceMigrationResult) GetFromVersion() int32 {
+	if x != nil {
+		return x.FromVersion
+	}
+	return 0
+}
+
+func (x *InstanceMigrationResult) GetMigrated() bool {
+	if x != nil {
+		return x.Migrated
+	}
+	return false
+}
+
+func (x *InstanceMigrationResult) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *InstanceMigrationResult) GetActiveNodeIds() []string {
+	if x != nil {
+		return x.ActiveNodeIds
+	}
+	return nil
+}
+
+type MigrateInstancesResponse struct {
+	state         protoimpl.MessageState     `protogen:"open.v1"`
+	TargetDefId   int64                      `protobuf:"varint,1,opt,name=target_def_id,json=targetDefId,proto3" json:"target_def_id,omitempty"`
+	TargetVersion int32                      `protobuf:"varint,2,opt,name=target_version,json=targetVersion,proto3" json:"target_version,omitempty"`
+	DryRun        bool                       `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	Migrated      int32                      `protobuf:"varint,4,opt,name=migrated,proto3" json:"migrated,omitempty"`
+	Rejected      int32                      `protobuf:"varint,5,opt,name=rejected,proto3" json:"rejected,omitempty"`
+	Results       []*InstanceMigrationResult `protobuf:"bytes,6,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MigrateInstancesResponse) Reset() {
+	*x = MigrateInstancesResponse{}
+	mi := &file_workflow_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MigrateInstancesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MigrateInstancesResponse) ProtoMessage() {}
+
+func (x *MigrateInstancesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MigrateInstancesResponse.ProtoReflect.Descriptor instead.
+func (*MigrateInstancesResponse) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *MigrateInstancesResponse) GetTargetDefId() int64 {
+	if x != nil {
+		return x.TargetDefId
+	}
+	return 0
+}
+
+func (x *MigrateInstancesResponse) GetTargetVersion() int32 {
+	if x != nil {
+		return x.TargetVersion
+	}
+	return 0
+}
+
+func (x *MigrateInstancesResponse) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *MigrateInstancesResponse) GetMigrated() int32 {
+	if x != nil {
+		return x.Migrated
+	}
+	return 0
+}
+
+func (x *MigrateInstancesResponse) GetRejected() int32 {
+	if x != nil {
+		return x.Rejected
+	}
+	return 0
+}
+
+func (x *MigrateInstancesResponse) GetResults() []*InstanceMigrationResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type ListInstanceMigrationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId    int64                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListInstanceMigrationsRequest) Reset() {
+	*x = ListInstanceMigrationsRequest{}
+	mi := &file_workflow_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListInstanceMigrationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListInstanceMigrationsRequest) ProtoMessage() {}
+
+func (x *ListInstanceMigrationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListInstanceMigrationsRequest.ProtoReflect.Descriptor instead.
+func (*ListInstanceMigrationsRequest) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *ListInstanceMigrationsRequest) GetInstanceId() int64 {
+	if x != nil {
+		return x.InstanceId
+	}
+	return 0
+}
+
+// InstanceMigrationRecord 实例迁移审计记录
+type InstanceMigrationRecord struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Id               int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	FromVersion      int32                  `protobuf:"varint,2,opt,name=from_version,json=fromVersion,proto3" json:"from_version,omitempty"`
+	ToVersion        int32                  `protobuf:"varint,3,opt,name=to_version,json=toVersion,proto3" json:"to_version,omitempty"`
+	NodeMappingJson  string                 `protobuf:"bytes,4,opt,name=node_mapping_json,json=nodeMappingJson,proto3" json:"node_mapping_json,omitempty"`
+	ActiveBeforeJson string                 `protobuf:"bytes,5,opt,name=active_before_json,json=activeBeforeJson,proto3" json:"active_before_json,omitempty"`
+	ActiveAfterJson  string                 `protobuf:"bytes,6,opt,name=active_after_json,json=activeAfterJson,proto3" json:"active_after_json,omitempty"`
+	CreatedAt        string                 `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *InstanceMigrationRecord) Reset() {
+	*x = InstanceMigrationRecord{}
+	mi := &file_workflow_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InstanceMigrationRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstanceMigrationRecord) ProtoMessage() {}
+
+func (x *InstanceMigrationRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstanceMigrationRecord.ProtoReflect.Descriptor instead.
+func (*InstanceMigrationRecord) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *InstanceMigrationRecord) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *InstanceMigrationRecord) GetFromVersion() int32 {
+	if x != nil {
+		return x.FromVersion
+	}
+	return 0
+}
+
+func (x *InstanceMigrationRecord) GetToVersion() int32 {
+	if x != nil {
+		return x.ToVersion
+	}
+	return 0
+}
+
+func (x *InstanceMigrationRecord) GetNodeMappingJson() string {
+	if x != nil {
+		return x.NodeMappingJson
+	}
+	return ""
+}
+
+func (x *InstanceMigrationRecord) GetActiveBeforeJson() string {
+	if x != nil {
+		return x.ActiveBeforeJson
+	}
+	return ""
+}
+
+func (x *InstanceMigrationRecord) GetActiveAfterJson() string {
+	if x != nil {
+		return x.ActiveAfterJson
+	}
+	return ""
+}
+
+func (x *InstanceMigrationRecord) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type ListInstanceMigrationsResponse struct {
+	state         protoimpl.MessageState     `protogen:"open.v1"`
+	Items         []*InstanceMigrationRecord `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListInstanceMigrationsResponse) Reset() {
+	*x = ListInstanceMigrationsResponse{}
+	mi := &file_workflow_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListInstanceMigrationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListInstanceMigrationsResponse) ProtoMessage() {}
+
+func (x *ListInstanceMigrationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListInstanceMigrationsResponse.ProtoReflect.Descriptor instead.
+func (*ListInstanceMigrationsResponse) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *ListInstanceMigrationsResponse) GetItems() []*InstanceMigrationRecord {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+// RenderDefRequest 渲染定义的 DAG；format 为 mermaid（默认）或 dot
+type RenderDefRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Env           string                 `protobuf:"bytes,1,opt,name=env,proto3" json:"env,omitempty"`
+	Code          string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	Version       int32                  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"` // 0=最新版本
+	Format        string                 `protobuf:"bytes,4,opt,name=format,proto3" json:"format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenderDefRequest) Reset() {
+	*x = RenderDefRequest{}
+	mi := &file_workflow_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenderDefRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenderDefRequest) ProtoMessage() {}
+
+func (x *RenderDefRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenderDefRequest.ProtoReflect.Descriptor instead.
+func (*RenderDefRequest) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *RenderDefRequest) GetEnv() string {
+	if x != nil {
+		return x.Env
+	}
+	return ""
+}
+
+func (x *RenderDefRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *RenderDefRequest) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *RenderDefRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+// RenderInstanceRequest 渲染实例所用定义的 DAG，并按执行进度给节点着色
+type RenderInstanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId    int64                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	Format        string                 `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenderInstanceRequest) Reset() {
+	*x = RenderInstanceRequest{}
+	mi := &file_workflow_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenderInstanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenderInstanceRequest) ProtoMessage() {}
+
+func (x *RenderInstanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenderInstanceRequest.ProtoReflect.Descriptor instead.
+func (*RenderInstanceRequest) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *RenderInstanceRequest) GetInstanceId() int64 {
+	if x != nil {
+		return x.InstanceId
+	}
+	return 0
+}
+
+func (x *RenderInstanceRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+type RenderedGraph struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Format        string                 `protobuf:"bytes,1,opt,name=format,proto3" json:"format,omitempty"`
+	Content       string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	NodeStatus    map[string]string      `protobuf:"bytes,3,rep,name=node_status,json=nodeStatus,proto3" json:"node_status,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // 仅实例视图：节点 ID -> completed/active/waiting/failed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenderedGraph) Reset() {
+	*x = RenderedGraph{}
+	mi := &file_workflow_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenderedGraph) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenderedGraph) ProtoMessage() {}
+
+func (x *RenderedGraph) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenderedGraph.ProtoReflect.Descriptor instead.
+func (*RenderedGraph) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *RenderedGraph) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *RenderedGraph) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *RenderedGraph) GetNodeStatus() map[string]string {
+	if x != nil {
+		return x.NodeStatus
+	}
+	return nil
+}
+
+// SendSignalRequest 向实例发送信号：payload 合并入 state；未指定 wakeup_node 且有 wait_signal 节点
+// 在等待该信号时，由这些节点接收并推进
+type SendSignalRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId    int64                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	SignalName    string                 `protobuf:"bytes,2,opt,name=signal_name,json=signalName,proto3" json:"signal_name,omitempty"`
+	PayloadJson   string                 `protobuf:"bytes,3,opt,name=payload_json,json=payloadJson,proto3" json:"payload_json,omitempty"`
+	WakeupNode    string                 `protobuf:"bytes,4,opt,name=wakeup_node,json=wakeupNode,proto3" json:"wakeup_node,omitempty"`
+	Env           string                 `protobuf:"bytes,5,opt,name=env,proto3" json:"env,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendSignalRequest) Reset() {
+	*x = SendSignalRequest{}
+	mi := &file_workflow_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendSignalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendSignalRequest) ProtoMessage() {}
+
+func (x *SendSignalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendSignalRequest.ProtoReflect.Descriptor instead.
+func (*SendSignalRequest) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *SendSignalRequest) GetInstanceId() int64 {
+	if x != nil {
+		return x.InstanceId
+	}
+	return 0
+}
+
+func (x *SendSignalRequest) GetSignalName() string {
+	if x != nil {
+		return x.SignalName
+	}
+	return ""
+}
+
+func (x *SendSignalRequest) GetPayloadJson() string {
+	if x != nil {
+		return x.PayloadJson
+	}
+	return ""
+}
+
+func (x *SendSignalRequest) GetWakeupNode() string {
+	if x != nil {
+		return x.WakeupNode
+	}
+	return ""
+}
+
+func (x *SendSignalRequest) GetEnv() string {
+	if x != nil {
+		return x.Env
+	}
+	return ""
+}
+
+type SendSignalResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendSignalResponse) Reset() {
+	*x = SendSignalResponse{}
+	mi := &file_workflow_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendSignalResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendSignalResponse) ProtoMessage() {}
+
+func (x *SendSignalResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendSignalResponse.ProtoReflect.Descriptor instead.
+func (*SendSignalResponse) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *SendSignalResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SendSignalResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// SendSignalByKeyRequest 按关联键投递信号，推进 env 内所有以该关联键等待该信号的 wait_signal 节点
+type SendSignalByKeyRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Env            string                 `protobuf:"bytes,1,opt,name=env,proto3" json:"env,omitempty"` // 空则用服务端默认环境
+	SignalName     string                 `protobuf:"bytes,2,opt,name=signal_name,json=signalName,proto3" json:"signal_name,omitempty"`
+	CorrelationKey string                 `protobuf:"bytes,3,opt,name=correlation_key,json=correlationKey,proto3" json:"correlation_key,omitempty"`
+	PayloadJson    string                 `protobuf:"bytes,4,opt,name=payload_json,json=payloadJson,proto3" json:"payload_json,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SendSignalByKeyRequest) Reset() {
+	*x = SendSignalByKeyRequest{}
+	mi := &file_workflow_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendSignalByKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendSignalByKeyRequest) ProtoMessage() {}
+
+func (x *SendSignalByKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendSignalByKeyRequest.ProtoReflect.Descriptor instead.
+func (*SendSignalByKeyRequest) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *SendSignalByKeyRequest) GetEnv() string {
+	if x != nil {
+		return x.Env
+	}
+	return ""
+}
+
+func (x *SendSignalByKeyRequest) GetSignalName() string {
+	if x != nil {
+		return x.SignalName
+	}
+	return ""
+}
+
+func (x *SendSignalByKeyRequest) GetCorrelationKey() string {
+	if x != nil {
+		return x.CorrelationKey
+	}
+	return ""
+}
+
+func (x *SendSignalByKeyRequest) GetPayloadJson() string {
+	if x != nil {
+		return x.PayloadJson
+	}
+	return ""
+}
+
+type SendSignalByKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Delivered     int32                  `protobuf:"varint,1,opt,name=delivered,proto3" json:"delivered,omitempty"` // 收到信号并推进的等待数，0 表示没有匹配的等待
+	InstanceIds   []int64                `protobuf:"varint,2,rep,packed,name=instance_ids,json=instanceIds,proto3" json:"instance_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendSignalByKeyResponse) Reset() {
+	*x = SendSignalByKeyResponse{}
+	mi := &file_workflow_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendSignalByKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendSignalByKeyResponse) ProtoMessage() {}
+
+func (x *SendSignalByKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendSignalByKeyResponse.ProtoReflect.Descriptor instead.
+func (*SendSignalByKeyResponse) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *SendSignalByKeyResponse) GetDelivered() int32 {
+	if x != nil {
+		return x.Delivered
+	}
+	return 0
+}
+
+func (x *SendSignalByKeyResponse) GetInstanceIds() []int64 {
+	if x != nil {
+		return x.InstanceIds
+	}
+	return nil
+}
+
+// ScheduleSpec 调度配置（创建与整体更新共用）
+type ScheduleSpec struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Env                 string                 `protobuf:"bytes,1,opt,name=env,proto3" json:"env,omitempty"` // 环境标识，空则用服务端默认环境
+	Name                string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	DefCode             string                 `protobuf:"bytes,3,opt,name=def_code,json=defCode,proto3" json:"def_code,omitempty"`
+	DefVersion          int32                  `protobuf:"varint,4,opt,name=def_version,json=defVersion,proto3" json:"def_version,omitempty"`                             // 0=每次触发时取最新版本
+	CronExpr            string                 `protobuf:"bytes,5,opt,name=cron_expr,json=cronExpr,proto3" json:"cron_expr,omitempty"`                                    // 5 段 cron 或 @every/@daily 等描述符
+	Timezone            string                 `protobuf:"bytes,6,opt,name=timezone,proto3" json:"timezone,omitempty"`                                                    // IANA 时区名，空表示服务端本地时区
+	InitialDataTemplate string                 `protobuf:"bytes,7,opt,name=initial_data_template,json=initialDataTemplate,proto3" json:"initial_data_template,omitempty"` // text/template，渲染结果须为 JSON 对象
+	OverlapPolicy       string                 `protobuf:"bytes,8,opt,name=overlap_policy,json=overlapPolicy,proto3" json:"overlap_policy,omitempty"`                     // skip/queue/allow，空为 skip
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *ScheduleSpec) Reset() {
+	*x = ScheduleSpec{}
+	mi := &file_workflow_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleSpec) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleSpec) ProtoMessage() {}
+
+func (x *ScheduleSpec) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduleSpec.ProtoReflect.Descriptor instead.
+func (*ScheduleSpec) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *ScheduleSpec) GetEnv() string {
+	if x != nil {
+		return x.Env
+	}
+	return ""
+}
+
+func (x *ScheduleSpec) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ScheduleSpec) GetDefCode() string {
+	if x != nil {
+		return x.DefCode
+	}
+	return ""
+}
+
+func (x *ScheduleSpec) GetDefVersion() int32 {
+	if x != nil {
+		return x.DefVersion
+	}
+	return 0
+}
+
+func (x *ScheduleSpec) GetCronExpr() string {
+	if x != nil {
+		return x.CronExpr
+	}
+	return ""
+}
+
+func (x *ScheduleSpec) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *ScheduleSpec) GetInitialDataTemplate() string {
+	if x != nil {
+		return x.InitialDataTemplate
+	}
+	return ""
+}
+
+func (x *ScheduleSpec) GetOverlapPolicy() string {
+	if x != nil {
+		return x.OverlapPolicy
+	}
+	return ""
+}
+
+type ScheduleInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ScheduleId    int64                  `protobuf:"varint,1,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	Spec          *ScheduleSpec          `protobuf:"bytes,2,opt,name=spec,proto3" json:"spec,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`                             // ACTIVE/PAUSED
+	NextFireAt    string                 `protobuf:"bytes,4,opt,name=next_fire_at,json=nextFireAt,proto3" json:"next_fire_at,omitempty"` // 暂停时为空
+	LastFireAt    string                 `protobuf:"bytes,5,opt,name=last_fire_at,json=lastFireAt,proto3" json:"last_fire_at,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduleInfo) Reset() {
+	*x = ScheduleInfo{}
+	mi := &file_workflow_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleInfo) ProtoMessage() {}
+
+func (x *ScheduleInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduleInfo.ProtoReflect.Descriptor instead.
+func (*ScheduleInfo) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *ScheduleInfo) GetScheduleId() int64 {
+	if x != nil {
+		return x.ScheduleId
+	}
+	return 0
+}
+
+func (x *ScheduleInfo) GetSpec() *ScheduleSpec {
+	if x != nil {
+		return x.Spec
+	}
+	return nil
+}
+
+func (x *ScheduleInfo) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ScheduleInfo) GetNextFireAt() string {
+	if x != nil {
+		return x.NextFireAt
+	}
+	return ""
+}
+
+func (x *ScheduleInfo) GetLastFireAt() string {
+	if x != nil {
+		return x.LastFireAt
+	}
+	return ""
+}
+
+func (x *ScheduleInfo) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type CreateScheduleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Spec          *ScheduleSpec          `protobuf:"bytes,1,opt,name=spec,proto3" json:"spec,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateScheduleRequest) Reset() {
+	*x = CreateScheduleRequest{}
+	mi := &file_workflow_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateScheduleRequest) ProtoMessage() {}
+
+func (x *CreateScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateScheduleRequest.ProtoReflect.Descriptor instead.
+func (*CreateScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *CreateScheduleRequest) GetSpec() *ScheduleSpec {
+	if x != nil {
+		return x.Spec
+	}
+	return nil
+}
+
+type CreateScheduleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Schedule      *ScheduleInfo          `protobuf:"bytes,1,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateScheduleResponse) Reset() {
+	*x = CreateScheduleResponse{}
+	mi := &file_workflow_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateScheduleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateScheduleResponse) ProtoMessage() {}
+
+func (x *CreateScheduleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateScheduleResponse.ProtoReflect.Descriptor instead.
+func (*CreateScheduleResponse) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *CreateScheduleResponse) GetSchedule() *ScheduleInfo {
+	if x != nil {
+		return x.Schedule
+	}
+	return nil
+}
+
+type UpdateScheduleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ScheduleId    int64                  `protobuf:"varint,1,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	Spec          *ScheduleSpec          `protobuf:"bytes,2,opt,name=spec,proto3" json:"spec,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateScheduleRequest) Reset() {
+	*x = UpdateScheduleRequest{}
+	mi := &file_workflow_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateScheduleRequest) ProtoMessage() {}
+
+func (x *UpdateScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateScheduleRequest.ProtoReflect.Descriptor instead.
+func (*UpdateScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *UpdateScheduleRequest) GetScheduleId() int64 {
+	if x != nil {
+		return x.ScheduleId
+	}
+	return 0
+}
+
+func (x *UpdateScheduleRequest) GetSpec() *ScheduleSpec {
+	if x != nil {
+		return x.Spec
+	}
+	return nil
+}
+
+type UpdateScheduleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Schedule      *ScheduleInfo          `protobuf:"bytes,1,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateScheduleResponse) Reset() {
+	*x = UpdateScheduleResponse{}
+	mi := &file_workflow_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateScheduleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateScheduleResponse) ProtoMessage() {}
+
+func (x *UpdateScheduleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateScheduleResponse.ProtoReflect.Descriptor instead.
+func (*UpdateScheduleResponse) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *UpdateScheduleResponse) GetSchedule() *ScheduleInfo {
+	if x != nil {
+		return x.Schedule
+	}
+	return nil
+}
+
+type DeleteScheduleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ScheduleId    int64                  `protobuf:"varint,1,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteScheduleRequest) Reset() {
+	*x = DeleteScheduleRequest{}
+	mi := &file_workflow_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteScheduleRequest) ProtoMessage() {}
+
+func (x *DeleteScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteScheduleRequest.ProtoReflect.Descriptor instead.
+func (*DeleteScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *DeleteScheduleRequest) GetScheduleId() int64 {
+	if x != nil {
+		return x.ScheduleId
+	}
+	return 0
+}
+
+type DeleteScheduleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteScheduleResponse) Reset() {
+	*x = DeleteScheduleResponse{}
+	mi := &file_workflow_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteScheduleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteScheduleResponse) ProtoMessage() {}
+
+func (x *DeleteScheduleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteScheduleResponse.ProtoReflect.Descriptor instead.
+func (*DeleteScheduleResponse) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *DeleteScheduleResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DeleteScheduleResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type GetScheduleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ScheduleId    int64                  `protobuf:"varint,1,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetScheduleRequest) Reset() {
+	*x = GetScheduleRequest{}
+	mi := &file_workflow_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetScheduleRequest) ProtoMessage() {}
+
+func (x *GetScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetScheduleRequest.ProtoReflect.Descriptor instead.
+func (*GetScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *GetScheduleRequest) GetScheduleId() int64 {
+	if x != nil {
+		return x.ScheduleId
+	}
+	return 0
+}
+
+type GetScheduleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Schedule      *ScheduleInfo          `protobuf:"bytes,1,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	NotFound      bool                   `protobuf:"varint,2,opt,name=not_found,json=notFound,proto3" json:"not_found,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetScheduleResponse) Reset() {
+	*x = GetScheduleResponse{}
+	mi := &file_workflow_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetScheduleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetScheduleResponse) ProtoMessage() {}
+
+func (x *GetScheduleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetScheduleResponse.ProtoReflect.Descriptor instead.
+func (*GetScheduleResponse) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *GetScheduleResponse) GetSchedule() *ScheduleInfo {
+	if x != nil {
+		return x.Schedule
+	}
+	return nil
+}
+
+func (x *GetScheduleResponse) GetNotFound() bool {
+	if x != nil {
+		return x.NotFound
+	}
+	return false
+}
+
+type ListSchedulesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Env           string                 `protobuf:"bytes,1,opt,name=env,proto3" json:"env,omitempty"`
+	DefCode       string                 `protobuf:"bytes,2,opt,name=def_code,json=defCode,proto3" json:"def_code,omitempty"`
+	PageNum       int32                  `protobuf:"varint,3,opt,name=page_num,json=pageNum,proto3" json:"page_num,omitempty"`
+	PageSize      int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSchedulesRequest) Reset() {
+	*x = ListSchedulesRequest{}
+	mi := &file_workflow_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSchedulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSchedulesRequest) ProtoMessage() {}
+
+func (x *ListSchedulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSchedulesRequest.ProtoReflect.Descriptor instead.
+func (*ListSchedulesRequest) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *ListSchedulesRequest) GetEnv() string {
+	if x != nil {
+		return x.Env
+	}
+	return ""
+}
+
+func (x *ListSchedulesRequest) GetDefCode() string {
+	if x != nil {
+		return x.DefCode
+	}
+	return ""
+}
+
+func (x *ListSchedulesRequest) GetPageNum() int32 {
+	if x != nil {
+		return x.PageNum
+	}
+	return 0
+}
+
+func (x *ListSchedulesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type ListSchedulesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*ScheduleInfo        `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	Total         int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSchedulesResponse) Reset() {
+	*x = ListSchedulesResponse{}
+	mi := &file_workflow_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSchedulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSchedulesResponse) ProtoMessage() {}
+
+func (x *ListSchedulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSchedulesResponse.ProtoReflect.Descriptor instead.
+func (*ListSchedulesResponse) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *ListSchedulesResponse) GetItems() []*ScheduleInfo {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *ListSchedulesResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type PauseScheduleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ScheduleId    int64                  `protobuf:"varint,1,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseScheduleRequest) Reset() {
+	*x = PauseScheduleRequest{}
+	mi := &file_workflow_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseScheduleRequest) ProtoMessage() {}
+
+func (x *PauseScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseScheduleRequest.ProtoReflect.Descriptor instead.
+func (*PauseScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *PauseScheduleRequest) GetScheduleId() int64 {
+	if x != nil {
+		return x.ScheduleId
+	}
+	return 0
+}
+
+type PauseScheduleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseScheduleResponse) Reset() {
+	*x = PauseScheduleResponse{}
+	mi := &file_workflow_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseScheduleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseScheduleResponse) ProtoMessage() {}
+
+func (x *PauseScheduleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseScheduleResponse.ProtoReflect.Descriptor instead.
+func (*PauseScheduleResponse) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *PauseScheduleResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *PauseScheduleResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ResumeScheduleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ScheduleId    int64                  `protobuf:"varint,1,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeScheduleRequest) Reset() {
+	*x = ResumeScheduleRequest{}
+	mi := &file_workflow_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeScheduleRequest) ProtoMessage() {}
+
+func (x *ResumeScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeScheduleRequest.ProtoReflect.Descriptor instead.
+func (*ResumeScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *ResumeScheduleRequest) GetScheduleId() int64 {
+	if x != nil {
+		return x.ScheduleId
+	}
+	return 0
+}
+
+type ResumeScheduleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeScheduleResponse) Reset() {
+	*x = ResumeScheduleResponse{}
+	mi := &file_workflow_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeScheduleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeScheduleResponse) ProtoMessage() {}
+
+func (x *ResumeScheduleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeScheduleResponse.ProtoReflect.Descriptor instead.
+func (*ResumeScheduleResponse) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *ResumeScheduleResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ResumeScheduleResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type RunScheduleNowRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ScheduleId    int64                  `protobuf:"varint,1,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunScheduleNowRequest) Reset() {
+	*x = RunScheduleNowRequest{}
+	mi := &file_workflow_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunScheduleNowRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunScheduleNowRequest) ProtoMessage() {}
+
+func (x *RunScheduleNowRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunScheduleNowRequest.ProtoReflect.Descriptor instead.
+func (*RunScheduleNowRequest) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *RunScheduleNowRequest) GetScheduleId() int64 {
+	if x != nil {
+		return x.ScheduleId
+	}
+	return 0
+}
+
+type RunScheduleNowResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId    int64                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunScheduleNowResponse) Reset() {
+	*x = RunScheduleNowResponse{}
+	mi := &file_workflow_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunScheduleNowResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunScheduleNowResponse) ProtoMessage() {}
+
+func (x *RunScheduleNowResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunScheduleNowResponse.ProtoReflect.Descriptor instead.
+func (*RunScheduleNowResponse) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *RunScheduleNowResponse) GetInstanceId() int64 {
+	if x != nil {
+		return x.InstanceId
+	}
+	return 0
+}
+
+type ListScheduleRunsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ScheduleId    int64                  `protobuf:"varint,1,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	PageNum       int32                  `protobuf:"varint,2,opt,name=page_num,json=pageNum,proto3" json:"page_num,omitempty"`
+	PageSize      int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListScheduleRunsRequest) Reset() {
+	*x = ListScheduleRunsRequest{}
+	mi := &file_workflow_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListScheduleRunsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListScheduleRunsRequest) ProtoMessage() {}
+
+func (x *ListScheduleRunsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[77]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListScheduleRunsRequest.ProtoReflect.Descriptor instead.
+func (*ListScheduleRunsRequest) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *ListScheduleRunsRequest) GetScheduleId() int64 {
+	if x != nil {
+		return x.ScheduleId
+	}
+	return 0
+}
+
+func (x *ListScheduleRunsRequest) GetPageNum() int32 {
+	if x != nil {
+		return x.PageNum
+	}
+	return 0
+}
+
+func (x *ListScheduleRunsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type ScheduleRunInfo struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	RunId          int64                  `protobuf:"varint,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	ScheduleId     int64                  `protobuf:"varint,2,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	Trigger        string                 `protobuf:"bytes,3,opt,name=trigger,proto3" json:"trigger,omitempty"` // cron/manual
+	Status         string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`   // STARTED/SKIPPED/QUEUED/FAILED
+	ScheduledAt    string                 `protobuf:"bytes,5,opt,name=scheduled_at,json=scheduledAt,proto3" json:"scheduled_at,omitempty"`
+	InstanceId     int64                  `protobuf:"varint,6,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`            // 未拉起实例时为 0
+	InstanceStatus string                 `protobuf:"bytes,7,opt,name=instance_status,json=instanceStatus,proto3" json:"instance_status,omitempty"` // 拉起实例的当前状态
+	Error          string                 `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`
+	CreatedAt      string                 `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ScheduleRunInfo) Reset() {
+	*x = ScheduleRunInfo{}
+	mi := &file_workflow_proto_msgTypes[78]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleRunInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleRunInfo) ProtoMessage() {}
+
+func (x *ScheduleRunInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[78]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduleRunInfo.ProtoReflect.Descriptor instead.
+func (*ScheduleRunInfo) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *ScheduleRunInfo) GetRunId() int64 {
+	if x != nil {
+		return x.RunId
+	}
+	return 0
+}
+
+func (x *ScheduleRunInfo) GetScheduleId() int64 {
+	if x != nil {
+		return x.ScheduleId
+	}
+	return 0
+}
+
+func (x *ScheduleRunInfo) GetTrigger() string {
+	if x != nil {
+		return x.Trigger
+	}
+	return ""
+}
+
+func (x *ScheduleRunInfo) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ScheduleRunInfo) GetScheduledAt() string {
+	if x != nil {
+		return x.ScheduledAt
+	}
+	return ""
+}
+
+func (x *ScheduleRunInfo) GetInstanceId() int64 {
+	if x != nil {
+		return x.InstanceId
+	}
+	return 0
+}
+
+func (x *ScheduleRunInfo) GetInstanceStatus() string {
+	if x != nil {
+		return x.InstanceStatus
+	}
+	return ""
+}
+
+func (x *ScheduleRunInfo) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ScheduleRunInfo) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type ListScheduleRunsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*ScheduleRunInfo     `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	Total         int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListScheduleRunsResponse) Reset() {
+	*x = ListScheduleRunsResponse{}
+	mi := &file_workflow_proto_msgTypes[79]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListScheduleRunsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListScheduleRunsResponse) ProtoMessage() {}
+
+func (x *ListScheduleRunsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[79]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListScheduleRunsResponse.ProtoReflect.Descriptor instead.
+func (*ListScheduleRunsResponse) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *ListScheduleRunsResponse) GetItems() []*ScheduleRunInfo {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *ListScheduleRunsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+// InstanceEvent 实例事件
+type InstanceEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventId       int64                  `protobuf:"varint,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"` // 全局递增，作为 WatchDef 的续传游标
+	InstanceId    int64                  `protobuf:"varint,2,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	Seq           int64                  `protobuf:"varint,3,opt,name=seq,proto3" json:"seq,omitempty"` // 实例内从 1 开始连续递增，作为 WatchInstance 的续传游标
+	DefId         int64                  `protobuf:"varint,4,opt,name=def_id,json=defId,proto3" json:"def_id,omitempty"`
+	Type          string                 `protobuf:"bytes,5,opt,name=type,proto3" json:"type,omitempty"` // node_triggered/node_completed/state_changed/waiting/signal_received/terminal
+	NodeId        string                 `protobuf:"bytes,6,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Status        string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"` // 事件发生后的实例状态
+	PayloadJson   string                 `protobuf:"bytes,8,opt,name=payload_json,json=payloadJson,proto3" json:"payload_json,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InstanceEvent) Reset() {
+	*x = InstanceEvent{}
+	mi := &file_workflow_proto_msgTypes[80]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InstanceEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstanceEvent) ProtoMessage() {}
+
+func (x *InstanceEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[80]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstanceEvent.ProtoReflect.Descriptor instead.
+func (*InstanceEvent) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *InstanceEvent) GetEventId() int64 {
+	if x != nil {
+		return x.EventId
+	}
+	return 0
+}
+
+func (x *InstanceEvent) GetInstanceId() int64 {
+	if x != nil {
+		return x.InstanceId
+	}
+	return 0
+}
+
+func (x *InstanceEvent) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *InstanceEvent) GetDefId() int64 {
+	if x != nil {
+		return x.DefId
+	}
+	return 0
+}
+
+func (x *InstanceEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *InstanceEvent) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *InstanceEvent) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *InstanceEvent) GetPayloadJson() string {
+	if x != nil {
+		return x.PayloadJson
+	}
+	return ""
+}
+
+func (x *InstanceEvent) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+// WatchInstanceRequest 订阅单个实例；实例进入终态且事件推送完毕后服务端结束流
+type WatchInstanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId    int64                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	AfterSeq      int64                  `protobuf:"varint,2,opt,name=after_seq,json=afterSeq,proto3" json:"after_seq,omitempty"` // 只推送 seq 大于该值的事件，0 表示从头回放
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchInstanceRequest) Reset() {
+	*x = WatchInstanceRequest{}
+	mi := &file_workflow_proto_msgTypes[81]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchInstanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchInstanceRequest) ProtoMessage() {}
+
+func (x *WatchInstanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[81]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchInstanceRequest.ProtoReflect.Descriptor instead.
+func (*WatchInstanceRequest) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{81}
+}
+
+func (x *WatchInstanceRequest) GetInstanceId() int64 {
+	if x != nil {
+		return x.InstanceId
+	}
+	return 0
+}
+
+func (x *WatchInstanceRequest) GetAfterSeq() int64 {
+	if x != nil {
+		return x.AfterSeq
+	}
+	return 0
+}
+
+// WatchDefRequest 订阅某定义编码（全部版本）下所有实例的事件，直到客户端断开
+type WatchDefRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DefCode       string                 `protobuf:"bytes,1,opt,name=def_code,json=defCode,proto3" json:"def_code,omitempty"`
+	Env           string                 `protobuf:"bytes,2,opt,name=env,proto3" json:"env,omitempty"`
+	AfterEventId  int64                  `protobuf:"varint,3,opt,name=after_event_id,json=afterEventId,proto3" json:"after_event_id,omitempty"` // 只推送 event_id 大于该值的事件，0 表示从订阅时刻开始
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchDefRequest) Reset() {
+	*x = WatchDefRequest{}
+	mi := &file_workflow_proto_msgTypes[82]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchDefRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchDefRequest) ProtoMessage() {}
+
+func (x *WatchDefRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_workflow_proto_msgTypes[82]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchDefRequest.ProtoReflect.Descriptor instead.
+func (*WatchDefRequest) Descriptor() ([]byte, []int) {
+	return file_workflow_proto_rawDescGZIP(), []int{82}
+}
+
+func (x *WatchDefRequest) GetDefCode() string {
+	if x != nil {
+		return x.DefCode
+	}
+	return ""
+}
+
+func (x *WatchDefRequest) GetEnv() string {
+	if x != nil {
+		return x.Env
+	}
+	return ""
+}
+
+func (x *WatchDefRequest) GetAfterEventId() int64 {
+	if x != nil {
+		return x.AfterEventId
+	}
+	return 0
+}
+
+var File_workflow_proto protoreflect.FileDescriptor
+
+const file_workflow_proto_rawDesc = "" +
+	"\n" +
 	"\x0eworkflow.proto\x12\x18xiaozhizhang.workflow.v1\"\x81\x01\n" +
 	"\x10CreateDefRequest\x12\x10\n" +
 	"\x03env\x18\x05 \x01(\tR\x03env\x12\x12\n" +
@@ -1813,14 +5120,31 @@ const file_workflow_proto_rawDesc = "" +
 	"\bdag_json\x18\x03 \x01(\tR\adagJson\x12\x18\n" +
 	"\aversion\x18\x04 \x01(\x05R\aversion\"*\n" +
 	"\x11CreateDefResponse\x12\x15\n" +
-	"\x06def_id\x18\x01 \x01(\x03R\x05defId\"o\n" +
+	"\x06def_id\x18\x01 \x01(\x03R\x05defId\"/\n" +
+	"\x12ValidateDefRequest\x12\x19\n" +
+	"\bdag_json\x18\x01 \x01(\tR\adagJson\"\xdc\x01\n" +
+	"\fDefLintIssue\x12\x1a\n" +
+	"\bseverity\x18\x01 \x01(\tR\bseverity\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12\x17\n" +
+	"\anode_id\x18\x03 \x01(\tR\x06nodeId\x12\x1d\n" +
+	"\n" +
+	"edge_index\x18\x04 \x01(\x05R\tedgeIndex\x12\x1b\n" +
+	"\tedge_from\x18\x05 \x01(\tR\bedgeFrom\x12\x17\n" +
+	"\aedge_to\x18\x06 \x01(\tR\x06edgeTo\x12\x14\n" +
+	"\x05field\x18\a \x01(\tR\x05field\x12\x18\n" +
+	"\amessage\x18\b \x01(\tR\amessage\"k\n" +
+	"\x13ValidateDefResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x12>\n" +
+	"\x06issues\x18\x02 \x03(\v2&.xiaozhizhang.workflow.v1.DefLintIssueR\x06issues\"\x92\x01\n" +
 	"\x14StartWorkflowRequest\x12\x19\n" +
 	"\bdef_code\x18\x01 \x01(\tR\adefCode\x12*\n" +
 	"\x11initial_data_json\x18\x02 \x01(\tR\x0finitialDataJson\x12\x10\n" +
-	"\x03env\x18\x03 \x01(\tR\x03env\"8\n" +
+	"\x03env\x18\x03 \x01(\tR\x03env\x12!\n" +
+	"\fbusiness_key\x18\x04 \x01(\tR\vbusinessKey\"R\n" +
 	"\x15StartWorkflowResponse\x12\x1f\n" +
 	"\vinstance_id\x18\x01 \x01(\x03R\n" +
-	"instanceId\"\x89\x01\n" +
+	"instanceId\x12\x18\n" +
+	"\aexisted\x18\x02 \x01(\bR\aexisted\"\x89\x01\n" +
 	"\x1aReportNodeCompletedRequest\x12\x1f\n" +
 	"\vinstance_id\x18\x01 \x01(\x03R\n" +
 	"instanceId\x12\x17\n" +
@@ -1842,20 +5166,31 @@ const file_workflow_proto_rawDesc = "" +
 	"\x18GetExecutionTrailRequest\x12\x1f\n" +
 	"\vinstance_id\x18\x01 \x01(\x03R\n" +
 	"instanceId\x12.\n" +
-	"\x13include_state_after\x18\x02 \x01(\bR\x11includeStateAfter\"\xf7\x01\n" +
+	"\x13include_state_after\x18\x02 \x01(\bR\x11includeStateAfter\"\x96\x03\n" +
 	"\x19GetExecutionTrailResponse\x12\x1f\n" +
 	"\vinstance_id\x18\x01 \x01(\x03R\n" +
 	"instanceId\x12\x16\n" +
 	"\x06status\x18\x02 \x01(\tR\x06status\x12#\n" +
 	"\rcurrent_state\x18\x03 \x01(\tR\fcurrentState\x12&\n" +
 	"\x0factive_node_ids\x18\x04 \x01(\tR\ractiveNodeIds\x12T\n" +
-	"\vcheckpoints\x18\x05 \x03(\v22.xiaozhizhang.workflow.v1.ExecutionTrailCheckpointR\vcheckpoints\"\xa6\x01\n" +
+	"\vcheckpoints\x18\x05 \x03(\v22.xiaozhizhang.workflow.v1.ExecutionTrailCheckpointR\vcheckpoints\x12,\n" +
+	"\x12parent_instance_id\x18\x06 \x01(\x03R\x10parentInstanceId\x12$\n" +
+	"\x0eparent_node_id\x18\a \x01(\tR\fparentNodeId\x12I\n" +
+	"\bchildren\x18\b \x03(\v2-.xiaozhizhang.workflow.v1.ExecutionTrailChildR\bchildren\"\x86\x01\n" +
+	"\x13ExecutionTrailChild\x12\x1f\n" +
+	"\vinstance_id\x18\x01 \x01(\x03R\n" +
+	"instanceId\x12\x17\n" +
+	"\anode_id\x18\x02 \x01(\tR\x06nodeId\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\tR\tcreatedAt\"\xcb\x01\n" +
 	"\x18ExecutionTrailCheckpoint\x12\x17\n" +
 	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12(\n" +
 	"\x10node_output_json\x18\x02 \x01(\tR\x0enodeOutputJson\x12(\n" +
 	"\x10state_after_json\x18\x03 \x01(\tR\x0estateAfterJson\x12\x1d\n" +
 	"\n" +
-	"created_at\x18\x04 \x01(\tR\tcreatedAt\"T\n" +
+	"created_at\x18\x04 \x01(\tR\tcreatedAt\x12#\n" +
+	"\rcheckpoint_id\x18\x05 \x01(\x03R\fcheckpointId\"T\n" +
 	"\x18GetExecutionStateRequest\x12\x1f\n" +
 	"\vinstance_id\x18\x01 \x01(\x03R\n" +
 	"instanceId\x12\x17\n" +
@@ -1872,7 +5207,7 @@ const file_workflow_proto_rawDesc = "" +
 	"\rGetDefRequest\x12\x10\n" +
 	"\x03env\x18\x03 \x01(\tR\x03env\x12\x12\n" +
 	"\x04code\x18\x01 \x01(\tR\x04code\x12\x18\n" +
-	"\aversion\x18\x02 \x01(\x05R\aversion\"\xb3\x01\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion\"\xf9\x01\n" +
 	"\x0eGetDefResponse\x12\x15\n" +
 	"\x06def_id\x18\x01 \x01(\x03R\x05defId\x12\x12\n" +
 	"\x04code\x18\x02 \x01(\tR\x04code\x12\x18\n" +
@@ -1880,7 +5215,9 @@ const file_workflow_proto_rawDesc = "" +
 	"\x04name\x18\x04 \x01(\tR\x04name\x12\x19\n" +
 	"\bdag_json\x18\x05 \x01(\tR\adagJson\x12\x1b\n" +
 	"\tnot_found\x18\x06 \x01(\bR\bnotFound\x12\x10\n" +
-	"\x03env\x18\a \x01(\tR\x03env\"x\n" +
+	"\x03env\x18\a \x01(\tR\x03env\x12!\n" +
+	"\finput_schema\x18\b \x01(\tR\vinputSchema\x12!\n" +
+	"\fstate_schema\x18\t \x01(\tR\vstateSchema\"x\n" +
 	"\x0fListDefsRequest\x12\x10\n" +
 	"\x03env\x18\x04 \x01(\tR\x03env\x12\x1b\n" +
 	"\tcode_like\x18\x01 \x01(\tR\bcodeLike\x12\x19\n" +
@@ -1900,7 +5237,7 @@ const file_workflow_proto_rawDesc = "" +
 	"\acreated\x18\x02 \x01(\bR\acreated\"5\n" +
 	"\x12GetInstanceRequest\x12\x1f\n" +
 	"\vinstance_id\x18\x01 \x01(\x03R\n" +
-	"instanceId\"\xe1\x02\n" +
+	"instanceId\"\xca\x04\n" +
 	"\x13GetInstanceResponse\x12\x1f\n" +
 	"\vinstance_id\x18\x01 \x01(\x03R\n" +
 	"instanceId\x12\x15\n" +
@@ -1916,20 +5253,30 @@ const file_workflow_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\n" +
 	" \x01(\tR\tcreatedAt\x12\x1b\n" +
-	"\tnot_found\x18\v \x01(\bR\bnotFound\";\n" +
+	"\tnot_found\x18\v \x01(\bR\bnotFound\x12,\n" +
+	"\x12parent_instance_id\x18\f \x01(\x03R\x10parentInstanceId\x12$\n" +
+	"\x0eparent_node_id\x18\r \x01(\tR\fparentNodeId\x125\n" +
+	"\x17forked_from_instance_id\x18\x0e \x01(\x03R\x14forkedFromInstanceId\x129\n" +
+	"\x19forked_from_checkpoint_id\x18\x0f \x01(\x03R\x16forkedFromCheckpointId\x12!\n" +
+	"\fbusiness_key\x18\x10 \x01(\tR\vbusinessKey\"q\n" +
+	"\x1fGetInstanceByBusinessKeyRequest\x12\x10\n" +
+	"\x03env\x18\x01 \x01(\tR\x03env\x12\x19\n" +
+	"\bdef_code\x18\x02 \x01(\tR\adefCode\x12!\n" +
+	"\fbusiness_key\x18\x03 \x01(\tR\vbusinessKey\";\n" +
 	"\x18GetInstanceStatusRequest\x12\x1f\n" +
 	"\vinstance_id\x18\x01 \x01(\x03R\n" +
 	"instanceId\"P\n" +
 	"\x19GetInstanceStatusResponse\x12\x16\n" +
 	"\x06status\x18\x01 \x01(\tR\x06status\x12\x1b\n" +
-	"\tnot_found\x18\x02 \x01(\bR\bnotFound\"\xcd\x01\n" +
+	"\tnot_found\x18\x02 \x01(\bR\bnotFound\"\xf0\x01\n" +
 	"\x14ListInstancesRequest\x12\x19\n" +
 	"\bdef_code\x18\x01 \x01(\tR\adefCode\x12\x16\n" +
 	"\x06status\x18\x02 \x01(\tR\x06status\x12#\n" +
 	"\rcreated_after\x18\x03 \x01(\x03R\fcreatedAfter\x12%\n" +
 	"\x0ecreated_before\x18\x04 \x01(\x03R\rcreatedBefore\x12\x19\n" +
 	"\bpage_num\x18\x05 \x01(\x05R\apageNum\x12\x1b\n" +
-	"\tpage_size\x18\x06 \x01(\x05R\bpageSize\"r\n" +
+	"\tpage_size\x18\x06 \x01(\x05R\bpageSize\x12!\n" +
+	"\fbusiness_key\x18\a \x01(\tR\vbusinessKey\"r\n" +
 	"\x15ListInstancesResponse\x12C\n" +
 	"\x05items\x18\x01 \x03(\v2-.xiaozhizhang.workflow.v1.GetInstanceResponseR\x05items\x12\x14\n" +
 	"\x05total\x18\x02 \x01(\x03R\x05total\"8\n" +
@@ -1945,9 +5292,236 @@ const file_workflow_proto_rawDesc = "" +
 	"\anode_id\x18\x02 \x01(\tR\x06nodeId\"G\n" +
 	"\x11RetryNodeResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage2\xd4\f\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"<\n" +
+	"\x19CompensateInstanceRequest\x12\x1f\n" +
+	"\vinstance_id\x18\x01 \x01(\x03R\n" +
+	"instanceId\"P\n" +
+	"\x1aCompensateInstanceResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x8c\x01\n" +
+	"\x13ForkInstanceRequest\x12\x1f\n" +
+	"\vinstance_id\x18\x01 \x01(\x03R\n" +
+	"instanceId\x12#\n" +
+	"\rcheckpoint_id\x18\x02 \x01(\x03R\fcheckpointId\x12\x1d\n" +
+	"\n" +
+	"patch_json\x18\x03 \x01(\tR\tpatchJson\x12\x10\n" +
+	"\x03env\x18\x04 \x01(\tR\x03env\"7\n" +
+	"\x14ForkInstanceResponse\x12\x1f\n" +
+	"\vinstance_id\x18\x01 \x01(\x03R\n" +
+	"instanceId\"7\n" +
+	"\x14PauseInstanceRequest\x12\x1f\n" +
+	"\vinstance_id\x18\x01 \x01(\x03R\n" +
+	"instanceId\"K\n" +
+	"\x15PauseInstanceResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"8\n" +
+	"\x15ResumeInstanceRequest\x12\x1f\n" +
+	"\vinstance_id\x18\x01 \x01(\x03R\n" +
+	"instanceId\"L\n" +
+	"\x16ResumeInstanceResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"I\n" +
+	"\x1aPauseInstancesByDefRequest\x12\x19\n" +
+	"\bdef_code\x18\x01 \x01(\tR\adefCode\x12\x10\n" +
+	"\x03env\x18\x02 \x01(\tR\x03env\"9\n" +
+	"\x1bPauseInstancesByDefResponse\x12\x1a\n" +
+	"\baffected\x18\x01 \x01(\x03R\baffected\"J\n" +
+	"\x1bResumeInstancesByDefRequest\x12\x19\n" +
+	"\bdef_code\x18\x01 \x01(\tR\adefCode\x12\x10\n" +
+	"\x03env\x18\x02 \x01(\tR\x03env\":\n" +
+	"\x1cResumeInstancesByDefResponse\x12\x1a\n" +
+	"\baffected\x18\x01 \x01(\x03R\baffected\"\xf3\x02\n" +
+	"\x17MigrateInstancesRequest\x12\x19\n" +
+	"\bdef_code\x18\x01 \x01(\tR\adefCode\x12\x10\n" +
+	"\x03env\x18\x02 \x01(\tR\x03env\x12%\n" +
+	"\x0etarget_version\x18\x03 \x01(\x05R\rtargetVersion\x12!\n" +
+	"\finstance_ids\x18\x04 \x03(\x03R\vinstanceIds\x12!\n" +
+	"\ffrom_version\x18\x05 \x01(\x05R\vfromVersion\x12e\n" +
+	"\fnode_mapping\x18\x06 \x03(\v2B.xiaozhizhang.workflow.v1.MigrateInstancesRequest.NodeMappingEntryR\vnodeMapping\x12\x17\n" +
+	"\adry_run\x18\a \x01(\bR\x06dryRun\x1a>\n" +
+	"\x10NodeMappingEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xb9\x01\n" +
+	"\x17InstanceMigrationResult\x12\x1f\n" +
+	"\vinstance_id\x18\x01 \x01(\x03R\n" +
+	"instanceId\x12!\n" +
+	"\ffrom_version\x18\x02 \x01(\x05R\vfromVersion\x12\x1a\n" +
+	"\bmigrated\x18\x03 \x01(\bR\bmigrated\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12&\n" +
+	"\x0factive_node_ids\x18\x05 \x03(\tR\ractiveNodeIds\"\x83\x02\n" +
+	"\x18MigrateInstancesResponse\x12\"\n" +
+	"\rtarget_def_id\x18\x01 \x01(\x03R\vtargetDefId\x12%\n" +
+	"\x0etarget_version\x18\x02 \x01(\x05R\rtargetVersion\x12\x17\n" +
+	"\adry_run\x18\x03 \x01(\bR\x06dryRun\x12\x1a\n" +
+	"\bmigrated\x18\x04 \x01(\x05R\bmigrated\x12\x1a\n" +
+	"\brejected\x18\x05 \x01(\x05R\brejected\x12K\n" +
+	"\aresults\x18\x06 \x03(\v21.xiaozhizhang.workflow.v1.InstanceMigrationResultR\aresults\"@\n" +
+	"\x1dListInstanceMigrationsRequest\x12\x1f\n" +
+	"\vinstance_id\x18\x01 \x01(\x03R\n" +
+	"instanceId\"\x90\x02\n" +
+	"\x17InstanceMigrationRecord\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12!\n" +
+	"\ffrom_version\x18\x02 \x01(\x05R\vfromVersion\x12\x1d\n" +
+	"\n" +
+	"to_version\x18\x03 \x01(\x05R\ttoVersion\x12*\n" +
+	"\x11node_mapping_json\x18\x04 \x01(\tR\x0fnodeMappingJson\x12,\n" +
+	"\x12active_before_json\x18\x05 \x01(\tR\x10activeBeforeJson\x12*\n" +
+	"\x11active_after_json\x18\x06 \x01(\tR\x0factiveAfterJson\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\tR\tcreatedAt\"i\n" +
+	"\x1eListInstanceMigrationsResponse\x12G\n" +
+	"\x05items\x18\x01 \x03(\v21.xiaozhizhang.workflow.v1.InstanceMigrationRecordR\x05items\"j\n" +
+	"\x10RenderDefRequest\x12\x10\n" +
+	"\x03env\x18\x01 \x01(\tR\x03env\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x05R\aversion\x12\x16\n" +
+	"\x06format\x18\x04 \x01(\tR\x06format\"P\n" +
+	"\x15RenderInstanceRequest\x12\x1f\n" +
+	"\vinstance_id\x18\x01 \x01(\x03R\n" +
+	"instanceId\x12\x16\n" +
+	"\x06format\x18\x02 \x01(\tR\x06format\"\xda\x01\n" +
+	"\rRenderedGraph\x12\x16\n" +
+	"\x06format\x18\x01 \x01(\tR\x06format\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\x12X\n" +
+	"\vnode_status\x18\x03 \x03(\v27.xiaozhizhang.workflow.v1.RenderedGraph.NodeStatusEntryR\n" +
+	"nodeStatus\x1a=\n" +
+	"\x0fNodeStatusEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xab\x01\n" +
+	"\x11SendSignalRequest\x12\x1f\n" +
+	"\vinstance_id\x18\x01 \x01(\x03R\n" +
+	"instanceId\x12\x1f\n" +
+	"\vsignal_name\x18\x02 \x01(\tR\n" +
+	"signalName\x12!\n" +
+	"\fpayload_json\x18\x03 \x01(\tR\vpayloadJson\x12\x1f\n" +
+	"\vwakeup_node\x18\x04 \x01(\tR\n" +
+	"wakeupNode\x12\x10\n" +
+	"\x03env\x18\x05 \x01(\tR\x03env\"H\n" +
+	"\x12SendSignalResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x97\x01\n" +
+	"\x16SendSignalByKeyRequest\x12\x10\n" +
+	"\x03env\x18\x01 \x01(\tR\x03env\x12\x1f\n" +
+	"\vsignal_name\x18\x02 \x01(\tR\n" +
+	"signalName\x12'\n" +
+	"\x0fcorrelation_key\x18\x03 \x01(\tR\x0ecorrelationKey\x12!\n" +
+	"\fpayload_json\x18\x04 \x01(\tR\vpayloadJson\"Z\n" +
+	"\x17SendSignalByKeyResponse\x12\x1c\n" +
+	"\tdelivered\x18\x01 \x01(\x05R\tdelivered\x12!\n" +
+	"\finstance_ids\x18\x02 \x03(\x03R\vinstanceIds\"\x84\x02\n" +
+	"\fScheduleSpec\x12\x10\n" +
+	"\x03env\x18\x01 \x01(\tR\x03env\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x19\n" +
+	"\bdef_code\x18\x03 \x01(\tR\adefCode\x12\x1f\n" +
+	"\vdef_version\x18\x04 \x01(\x05R\n" +
+	"defVersion\x12\x1b\n" +
+	"\tcron_expr\x18\x05 \x01(\tR\bcronExpr\x12\x1a\n" +
+	"\btimezone\x18\x06 \x01(\tR\btimezone\x122\n" +
+	"\x15initial_data_template\x18\a \x01(\tR\x13initialDataTemplate\x12%\n" +
+	"\x0eoverlap_policy\x18\b \x01(\tR\roverlapPolicy\"\xe6\x01\n" +
+	"\fScheduleInfo\x12\x1f\n" +
+	"\vschedule_id\x18\x01 \x01(\x03R\n" +
+	"scheduleId\x12:\n" +
+	"\x04spec\x18\x02 \x01(\v2&.xiaozhizhang.workflow.v1.ScheduleSpecR\x04spec\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12 \n" +
+	"\fnext_fire_at\x18\x04 \x01(\tR\n" +
+	"nextFireAt\x12 \n" +
+	"\flast_fire_at\x18\x05 \x01(\tR\n" +
+	"lastFireAt\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\tR\tcreatedAt\"S\n" +
+	"\x15CreateScheduleRequest\x12:\n" +
+	"\x04spec\x18\x01 \x01(\v2&.xiaozhizhang.workflow.v1.ScheduleSpecR\x04spec\"\\\n" +
+	"\x16CreateScheduleResponse\x12B\n" +
+	"\bschedule\x18\x01 \x01(\v2&.xiaozhizhang.workflow.v1.ScheduleInfoR\bschedule\"t\n" +
+	"\x15UpdateScheduleRequest\x12\x1f\n" +
+	"\vschedule_id\x18\x01 \x01(\x03R\n" +
+	"scheduleId\x12:\n" +
+	"\x04spec\x18\x02 \x01(\v2&.xiaozhizhang.workflow.v1.ScheduleSpecR\x04spec\"\\\n" +
+	"\x16UpdateScheduleResponse\x12B\n" +
+	"\bschedule\x18\x01 \x01(\v2&.xiaozhizhang.workflow.v1.ScheduleInfoR\bschedule\"8\n" +
+	"\x15DeleteScheduleRequest\x12\x1f\n" +
+	"\vschedule_id\x18\x01 \x01(\x03R\n" +
+	"scheduleId\"L\n" +
+	"\x16DeleteScheduleResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"5\n" +
+	"\x12GetScheduleRequest\x12\x1f\n" +
+	"\vschedule_id\x18\x01 \x01(\x03R\n" +
+	"scheduleId\"v\n" +
+	"\x13GetScheduleResponse\x12B\n" +
+	"\bschedule\x18\x01 \x01(\v2&.xiaozhizhang.workflow.v1.ScheduleInfoR\bschedule\x12\x1b\n" +
+	"\tnot_found\x18\x02 \x01(\bR\bnotFound\"{\n" +
+	"\x14ListSchedulesRequest\x12\x10\n" +
+	"\x03env\x18\x01 \x01(\tR\x03env\x12\x19\n" +
+	"\bdef_code\x18\x02 \x01(\tR\adefCode\x12\x19\n" +
+	"\bpage_num\x18\x03 \x01(\x05R\apageNum\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\"k\n" +
+	"\x15ListSchedulesResponse\x12<\n" +
+	"\x05items\x18\x01 \x03(\v2&.xiaozhizhang.workflow.v1.ScheduleInfoR\x05items\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x03R\x05total\"7\n" +
+	"\x14PauseScheduleRequest\x12\x1f\n" +
+	"\vschedule_id\x18\x01 \x01(\x03R\n" +
+	"scheduleId\"K\n" +
+	"\x15PauseScheduleResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"8\n" +
+	"\x15ResumeScheduleRequest\x12\x1f\n" +
+	"\vschedule_id\x18\x01 \x01(\x03R\n" +
+	"scheduleId\"L\n" +
+	"\x16ResumeScheduleResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"8\n" +
+	"\x15RunScheduleNowRequest\x12\x1f\n" +
+	"\vschedule_id\x18\x01 \x01(\x03R\n" +
+	"scheduleId\"9\n" +
+	"\x16RunScheduleNowResponse\x12\x1f\n" +
+	"\vinstance_id\x18\x01 \x01(\x03R\n" +
+	"instanceId\"r\n" +
+	"\x17ListScheduleRunsRequest\x12\x1f\n" +
+	"\vschedule_id\x18\x01 \x01(\x03R\n" +
+	"scheduleId\x12\x19\n" +
+	"\bpage_num\x18\x02 \x01(\x05R\apageNum\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\"\x9d\x02\n" +
+	"\x0fScheduleRunInfo\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\x03R\x05runId\x12\x1f\n" +
+	"\vschedule_id\x18\x02 \x01(\x03R\n" +
+	"scheduleId\x12\x18\n" +
+	"\atrigger\x18\x03 \x01(\tR\atrigger\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12!\n" +
+	"\fscheduled_at\x18\x05 \x01(\tR\vscheduledAt\x12\x1f\n" +
+	"\vinstance_id\x18\x06 \x01(\x03R\n" +
+	"instanceId\x12'\n" +
+	"\x0finstance_status\x18\a \x01(\tR\x0einstanceStatus\x12\x14\n" +
+	"\x05error\x18\b \x01(\tR\x05error\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\t \x01(\tR\tcreatedAt\"q\n" +
+	"\x18ListScheduleRunsResponse\x12?\n" +
+	"\x05items\x18\x01 \x03(\v2).xiaozhizhang.workflow.v1.ScheduleRunInfoR\x05items\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x03R\x05total\"\xfb\x01\n" +
+	"\rInstanceEvent\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\x03R\aeventId\x12\x1f\n" +
+	"\vinstance_id\x18\x02 \x01(\x03R\n" +
+	"instanceId\x12\x10\n" +
+	"\x03seq\x18\x03 \x01(\x03R\x03seq\x12\x15\n" +
+	"\x06def_id\x18\x04 \x01(\x03R\x05defId\x12\x12\n" +
+	"\x04type\x18\x05 \x01(\tR\x04type\x12\x17\n" +
+	"\anode_id\x18\x06 \x01(\tR\x06nodeId\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x12!\n" +
+	"\fpayload_json\x18\b \x01(\tR\vpayloadJson\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\t \x01(\tR\tcreatedAt\"T\n" +
+	"\x14WatchInstanceRequest\x12\x1f\n" +
+	"\vinstance_id\x18\x01 \x01(\x03R\n" +
+	"instanceId\x12\x1b\n" +
+	"\tafter_seq\x18\x02 \x01(\x03R\bafterSeq\"d\n" +
+	"\x0fWatchDefRequest\x12\x19\n" +
+	"\bdef_code\x18\x01 \x01(\tR\adefCode\x12\x10\n" +
+	"\x03env\x18\x02 \x01(\tR\x03env\x12$\n" +
+	"\x0eafter_event_id\x18\x03 \x01(\x03R\fafterEventId2\xc5#\n" +
 	"\x0fWorkflowService\x12d\n" +
-	"\tCreateDef\x12*.xiaozhizhang.workflow.v1.CreateDefRequest\x1a+.xiaozhizhang.workflow.v1.CreateDefResponse\x12p\n" +
+	"\tCreateDef\x12*.xiaozhizhang.workflow.v1.CreateDefRequest\x1a+.xiaozhizhang.workflow.v1.CreateDefResponse\x12j\n" +
+	"\vValidateDef\x12,.xiaozhizhang.workflow.v1.ValidateDefRequest\x1a-.xiaozhizhang.workflow.v1.ValidateDefResponse\x12p\n" +
 	"\rStartWorkflow\x12..xiaozhizhang.workflow.v1.StartWorkflowRequest\x1a/.xiaozhizhang.workflow.v1.StartWorkflowResponse\x12\x82\x01\n" +
 	"\x13ReportNodeCompleted\x124.xiaozhizhang.workflow.v1.ReportNodeCompletedRequest\x1a5.xiaozhizhang.workflow.v1.ReportNodeCompletedResponse\x12s\n" +
 	"\x0eRollbackToNode\x12/.xiaozhizhang.workflow.v1.RollbackToNodeRequest\x1a0.xiaozhizhang.workflow.v1.RollbackToNodeResponse\x12|\n" +
@@ -1956,11 +5530,36 @@ const file_workflow_proto_rawDesc = "" +
 	"\x06GetDef\x12'.xiaozhizhang.workflow.v1.GetDefRequest\x1a(.xiaozhizhang.workflow.v1.GetDefResponse\x12a\n" +
 	"\bListDefs\x12).xiaozhizhang.workflow.v1.ListDefsRequest\x1a*.xiaozhizhang.workflow.v1.ListDefsResponse\x12|\n" +
 	"\x11CreateIfNotExists\x122.xiaozhizhang.workflow.v1.CreateIfNotExistsRequest\x1a3.xiaozhizhang.workflow.v1.CreateIfNotExistsResponse\x12j\n" +
-	"\vGetInstance\x12,.xiaozhizhang.workflow.v1.GetInstanceRequest\x1a-.xiaozhizhang.workflow.v1.GetInstanceResponse\x12|\n" +
+	"\vGetInstance\x12,.xiaozhizhang.workflow.v1.GetInstanceRequest\x1a-.xiaozhizhang.workflow.v1.GetInstanceResponse\x12\x84\x01\n" +
+	"\x18GetInstanceByBusinessKey\x129.xiaozhizhang.workflow.v1.GetInstanceByBusinessKeyRequest\x1a-.xiaozhizhang.workflow.v1.GetInstanceResponse\x12|\n" +
 	"\x11GetInstanceStatus\x122.xiaozhizhang.workflow.v1.GetInstanceStatusRequest\x1a3.xiaozhizhang.workflow.v1.GetInstanceStatusResponse\x12p\n" +
 	"\rListInstances\x12..xiaozhizhang.workflow.v1.ListInstancesRequest\x1a/.xiaozhizhang.workflow.v1.ListInstancesResponse\x12s\n" +
 	"\x0eCancelInstance\x12/.xiaozhizhang.workflow.v1.CancelInstanceRequest\x1a0.xiaozhizhang.workflow.v1.CancelInstanceResponse\x12d\n" +
-	"\tRetryNode\x12*.xiaozhizhang.workflow.v1.RetryNodeRequest\x1a+.xiaozhizhang.workflow.v1.RetryNodeResponseB7Z5github.com/xsxdot/aio/system/workflow/api/proto;protob\x06proto3"
+	"\tRetryNode\x12*.xiaozhizhang.workflow.v1.RetryNodeRequest\x1a+.xiaozhizhang.workflow.v1.RetryNodeResponse\x12\x7f\n" +
+	"\x12CompensateInstance\x123.xiaozhizhang.workflow.v1.CompensateInstanceRequest\x1a4.xiaozhizhang.workflow.v1.CompensateInstanceResponse\x12m\n" +
+	"\fForkInstance\x12-.xiaozhizhang.workflow.v1.ForkInstanceRequest\x1a..xiaozhizhang.workflow.v1.ForkInstanceResponse\x12p\n" +
+	"\rPauseInstance\x12..xiaozhizhang.workflow.v1.PauseInstanceRequest\x1a/.xiaozhizhang.workflow.v1.PauseInstanceResponse\x12s\n" +
+	"\x0eResumeInstance\x12/.xiaozhizhang.workflow.v1.ResumeInstanceRequest\x1a0.xiaozhizhang.workflow.v1.ResumeInstanceResponse\x12\x82\x01\n" +
+	"\x13PauseInstancesByDef\x124.xiaozhizhang.workflow.v1.PauseInstancesByDefRequest\x1a5.xiaozhizhang.workflow.v1.PauseInstancesByDefResponse\x12\x85\x01\n" +
+	"\x14ResumeInstancesByDef\x125.xiaozhizhang.workflow.v1.ResumeInstancesByDefRequest\x1a6.xiaozhizhang.workflow.v1.ResumeInstancesByDefResponse\x12y\n" +
+	"\x10MigrateInstances\x121.xiaozhizhang.workflow.v1.MigrateInstancesRequest\x1a2.xiaozhizhang.workflow.v1.MigrateInstancesResponse\x12\x8b\x01\n" +
+	"\x16ListInstanceMigrations\x127.xiaozhizhang.workflow.v1.ListInstanceMigrationsRequest\x1a8.xiaozhizhang.workflow.v1.ListInstanceMigrationsResponse\x12`\n" +
+	"\tRenderDef\x12*.xiaozhizhang.workflow.v1.RenderDefRequest\x1a'.xiaozhizhang.workflow.v1.RenderedGraph\x12j\n" +
+	"\x0eRenderInstance\x12/.xiaozhizhang.workflow.v1.RenderInstanceRequest\x1a'.xiaozhizhang.workflow.v1.RenderedGraph\x12g\n" +
+	"\n" +
+	"SendSignal\x12+.xiaozhizhang.workflow.v1.SendSignalRequest\x1a,.xiaozhizhang.workflow.v1.SendSignalResponse\x12v\n" +
+	"\x0fSendSignalByKey\x120.xiaozhizhang.workflow.v1.SendSignalByKeyRequest\x1a1.xiaozhizhang.workflow.v1.SendSignalByKeyResponse\x12s\n" +
+	"\x0eCreateSchedule\x12/.xiaozhizhang.workflow.v1.CreateScheduleRequest\x1a0.xiaozhizhang.workflow.v1.CreateScheduleResponse\x12s\n" +
+	"\x0eUpdateSchedule\x12/.xiaozhizhang.workflow.v1.UpdateScheduleRequest\x1a0.xiaozhizhang.workflow.v1.UpdateScheduleResponse\x12s\n" +
+	"\x0eDeleteSchedule\x12/.xiaozhizhang.workflow.v1.DeleteScheduleRequest\x1a0.xiaozhizhang.workflow.v1.DeleteScheduleResponse\x12j\n" +
+	"\vGetSchedule\x12,.xiaozhizhang.workflow.v1.GetScheduleRequest\x1a-.xiaozhizhang.workflow.v1.GetScheduleResponse\x12p\n" +
+	"\rListSchedules\x12..xiaozhizhang.workflow.v1.ListSchedulesRequest\x1a/.xiaozhizhang.workflow.v1.ListSchedulesResponse\x12p\n" +
+	"\rPauseSchedule\x12..xiaozhizhang.workflow.v1.PauseScheduleRequest\x1a/.xiaozhizhang.workflow.v1.PauseScheduleResponse\x12s\n" +
+	"\x0eResumeSchedule\x12/.xiaozhizhang.workflow.v1.ResumeScheduleRequest\x1a0.xiaozhizhang.workflow.v1.ResumeScheduleResponse\x12s\n" +
+	"\x0eRunScheduleNow\x12/.xiaozhizhang.workflow.v1.RunScheduleNowRequest\x1a0.xiaozhizhang.workflow.v1.RunScheduleNowResponse\x12y\n" +
+	"\x10ListScheduleRuns\x121.xiaozhizhang.workflow.v1.ListScheduleRunsRequest\x1a2.xiaozhizhang.workflow.v1.ListScheduleRunsResponse\x12j\n" +
+	"\rWatchInstance\x12..xiaozhizhang.workflow.v1.WatchInstanceRequest\x1a'.xiaozhizhang.workflow.v1.InstanceEvent0\x01\x12`\n" +
+	"\bWatchDef\x12).xiaozhizhang.workflow.v1.WatchDefRequest\x1a'.xiaozhizhang.workflow.v1.InstanceEvent0\x01B7Z5github.com/xsxdot/aio/system/workflow/api/proto;protob\x06proto3"
 
 var (
 	file_workflow_proto_rawDescOnce sync.Once
@@ -1974,75 +5573,195 @@ func file_workflow_proto_rawDescGZIP() []byte {
 	return file_workflow_proto_rawDescData
 }
 
-var file_workflow_proto_msgTypes = make([]protoimpl.MessageInfo, 29)
+var file_workflow_proto_msgTypes = make([]protoimpl.MessageInfo, 85)
 var file_workflow_proto_goTypes = []any{
-	(*CreateDefRequest)(nil),            // 0: xiaozhizhang.workflow.v1.CreateDefRequest
-	(*CreateDefResponse)(nil),           // 1: xiaozhizhang.workflow.v1.CreateDefResponse
-	(*StartWorkflowRequest)(nil),        // 2: xiaozhizhang.workflow.v1.StartWorkflowRequest
-	(*StartWorkflowResponse)(nil),       // 3: xiaozhizhang.workflow.v1.StartWorkflowResponse
-	(*ReportNodeCompletedRequest)(nil),  // 4: xiaozhizhang.workflow.v1.ReportNodeCompletedRequest
-	(*ReportNodeCompletedResponse)(nil), // 5: xiaozhizhang.workflow.v1.ReportNodeCompletedResponse
-	(*RollbackToNodeRequest)(nil),       // 6: xiaozhizhang.workflow.v1.RollbackToNodeRequest
-	(*RollbackToNodeResponse)(nil),      // 7: xiaozhizhang.workflow.v1.RollbackToNodeResponse
-	(*GetExecutionTrailRequest)(nil),    // 8: xiaozhizhang.workflow.v1.GetExecutionTrailRequest
-	(*GetExecutionTrailResponse)(nil),   // 9: xiaozhizhang.workflow.v1.GetExecutionTrailResponse
-	(*ExecutionTrailCheckpoint)(nil),    // 10: xiaozhizhang.workflow.v1.ExecutionTrailCheckpoint
-	(*GetExecutionStateRequest)(nil),    // 11: xiaozhizhang.workflow.v1.GetExecutionStateRequest
-	(*GetExecutionStateResponse)(nil),   // 12: xiaozhizhang.workflow.v1.GetExecutionStateResponse
-	(*GetDefRequest)(nil),               // 13: xiaozhizhang.workflow.v1.GetDefRequest
-	(*GetDefResponse)(nil),              // 14: xiaozhizhang.workflow.v1.GetDefResponse
-	(*ListDefsRequest)(nil),             // 15: xiaozhizhang.workflow.v1.ListDefsRequest
-	(*ListDefsResponse)(nil),            // 16: xiaozhizhang.workflow.v1.ListDefsResponse
-	(*CreateIfNotExistsRequest)(nil),    // 17: xiaozhizhang.workflow.v1.CreateIfNotExistsRequest
-	(*CreateIfNotExistsResponse)(nil),   // 18: xiaozhizhang.workflow.v1.CreateIfNotExistsResponse
-	(*GetInstanceRequest)(nil),          // 19: xiaozhizhang.workflow.v1.GetInstanceRequest
-	(*GetInstanceResponse)(nil),         // 20: xiaozhizhang.workflow.v1.GetInstanceResponse
-	(*GetInstanceStatusRequest)(nil),    // 21: xiaozhizhang.workflow.v1.GetInstanceStatusRequest
-	(*GetInstanceStatusResponse)(nil),   // 22: xiaozhizhang.workflow.v1.GetInstanceStatusResponse
-	(*ListInstancesRequest)(nil),        // 23: xiaozhizhang.workflow.v1.ListInstancesRequest
-	(*ListInstancesResponse)(nil),       // 24: xiaozhizhang.workflow.v1.ListInstancesResponse
-	(*CancelInstanceRequest)(nil),       // 25: xiaozhizhang.workflow.v1.CancelInstanceRequest
-	(*CancelInstanceResponse)(nil),      // 26: xiaozhizhang.workflow.v1.CancelInstanceResponse
-	(*RetryNodeRequest)(nil),            // 27: xiaozhizhang.workflow.v1.RetryNodeRequest
-	(*RetryNodeResponse)(nil),           // 28: xiaozhizhang.workflow.v1.RetryNodeResponse
+	(*CreateDefRequest)(nil),                // 0: xiaozhizhang.workflow.v1.CreateDefRequest
+	(*CreateDefResponse)(nil),               // 1: xiaozhizhang.workflow.v1.CreateDefResponse
+	(*ValidateDefRequest)(nil),              // 2: xiaozhizhang.workflow.v1.ValidateDefRequest
+	(*DefLintIssue)(nil),                    // 3: xiaozhizhang.workflow.v1.DefLintIssue
+	(*ValidateDefResponse)(nil),             // 4: xiaozhizhang.workflow.v1.ValidateDefResponse
+	(*StartWorkflowRequest)(nil),            // 5: xiaozhizhang.workflow.v1.StartWorkflowRequest
+	(*StartWorkflowResponse)(nil),           // 6: xiaozhizhang.workflow.v1.StartWorkflowResponse
+	(*ReportNodeCompletedRequest)(nil),      // 7: xiaozhizhang.workflow.v1.ReportNodeCompletedRequest
+	(*ReportNodeCompletedResponse)(nil),     // 8: xiaozhizhang.workflow.v1.ReportNodeCompletedResponse
+	(*RollbackToNodeRequest)(nil),           // 9: xiaozhizhang.workflow.v1.RollbackToNodeRequest
+	(*RollbackToNodeResponse)(nil),          // 10: xiaozhizhang.workflow.v1.RollbackToNodeResponse
+	(*GetExecutionTrailRequest)(nil),        // 11: xiaozhizhang.workflow.v1.GetExecutionTrailRequest
+	(*GetExecutionTrailResponse)(nil),       // 12: xiaozhizhang.workflow.v1.GetExecutionTrailResponse
+	(*ExecutionTrailChild)(nil),             // 13: xiaozhizhang.workflow.v1.ExecutionTrailChild
+	(*ExecutionTrailCheckpoint)(nil),        // 14: xiaozhizhang.workflow.v1.ExecutionTrailCheckpoint
+	(*GetExecutionStateRequest)(nil),        // 15: xiaozhizhang.workflow.v1.GetExecutionStateRequest
+	(*GetExecutionStateResponse)(nil),       // 16: xiaozhizhang.workflow.v1.GetExecutionStateResponse
+	(*GetDefRequest)(nil),                   // 17: xiaozhizhang.workflow.v1.GetDefRequest
+	(*GetDefResponse)(nil),                  // 18: xiaozhizhang.workflow.v1.GetDefResponse
+	(*ListDefsRequest)(nil),                 // 19: xiaozhizhang.workflow.v1.ListDefsRequest
+	(*ListDefsResponse)(nil),                // 20: xiaozhizhang.workflow.v1.ListDefsResponse
+	(*CreateIfNotExistsRequest)(nil),        // 21: xiaozhizhang.workflow.v1.CreateIfNotExistsRequest
+	(*CreateIfNotExistsResponse)(nil),       // 22: xiaozhizhang.workflow.v1.CreateIfNotExistsResponse
+	(*GetInstanceRequest)(nil),              // 23: xiaozhizhang.workflow.v1.GetInstanceRequest
+	(*GetInstanceResponse)(nil),             // 24: xiaozhizhang.workflow.v1.GetInstanceResponse
+	(*GetInstanceByBusinessKeyRequest)(nil), // 25: xiaozhizhang.workflow.v1.GetInstanceByBusinessKeyRequest
+	(*GetInstanceStatusRequest)(nil),        // 26: xiaozhizhang.workflow.v1.GetInstanceStatusRequest
+	(*GetInstanceStatusResponse)(nil),       // 27: xiaozhizhang.workflow.v1.GetInstanceStatusResponse
+	(*ListInstancesRequest)(nil),            // 28: xiaozhizhang.workflow.v1.ListInstancesRequest
+	(*ListInstancesResponse)(nil),           // 29: xiaozhizhang.workflow.v1.ListInstancesResponse
+	(*CancelInstanceRequest)(nil),           // 30: xiaozhizhang.workflow.v1.CancelInstanceRequest
+	(*CancelInstanceResponse)(nil),          // 31: xiaozhizhang.workflow.v1.CancelInstanceResponse
+	(*RetryNodeRequest)(nil),                // 32: xiaozhizhang.workflow.v1.RetryNodeRequest
+	(*RetryNodeResponse)(nil),               // 33: xiaozhizhang.workflow.v1.RetryNodeResponse
+	(*CompensateInstanceRequest)(nil),       // 34: xiaozhizhang.workflow.v1.CompensateInstanceRequest
+	(*CompensateInstanceResponse)(nil),      // 35: xiaozhizhang.workflow.v1.CompensateInstanceResponse
+	(*ForkInstanceRequest)(nil),             // 36: xiaozhizhang.workflow.v1.ForkInstanceRequest
+	(*ForkInstanceResponse)(nil),            // 37: xiaozhizhang.workflow.v1.ForkInstanceResponse
+	(*PauseInstanceRequest)(nil),            // 38: xiaozhizhang.workflow.v1.PauseInstanceRequest
+	(*PauseInstanceResponse)(nil),           // 39: xiaozhizhang.workflow.v1.PauseInstanceResponse
+	(*ResumeInstanceRequest)(nil),           // 40: xiaozhizhang.workflow.v1.ResumeInstanceRequest
+	(*ResumeInstanceResponse)(nil),          // 41: xiaozhizhang.workflow.v1.ResumeInstanceResponse
+	(*PauseInstancesByDefRequest)(nil),      // 42: xiaozhizhang.workflow.v1.PauseInstancesByDefRequest
+	(*PauseInstancesByDefResponse)(nil),     // 43: xiaozhizhang.workflow.v1.PauseInstancesByDefResponse
+	(*ResumeInstancesByDefRequest)(nil),     // 44: xiaozhizhang.workflow.v1.ResumeInstancesByDefRequest
+	(*ResumeInstancesByDefResponse)(nil),    // 45: xiaozhizhang.workflow.v1.ResumeInstancesByDefResponse
+	(*MigrateInstancesRequest)(nil),         // 46: xiaozhizhang.workflow.v1.MigrateInstancesRequest
+	(*InstanceMigrationResult)(nil),         // 47: xiaozhizhang.workflow.v1.InstanceMigrationResult
+	(*MigrateInstancesResponse)(nil),        // 48: xiaozhizhang.workflow.v1.MigrateInstancesResponse
+	(*ListInstanceMigrationsRequest)(nil),   // 49: xiaozhizhang.workflow.v1.ListInstanceMigrationsRequest
+	(*InstanceMigrationRecord)(nil),         // 50: xiaozhizhang.workflow.v1.InstanceMigrationRecord
+	(*ListInstanceMigrationsResponse)(nil),  // 51: xiaozhizhang.workflow.v1.ListInstanceMigrationsResponse
+	(*RenderDefRequest)(nil),                // 52: xiaozhizhang.workflow.v1.RenderDefRequest
+	(*RenderInstanceRequest)(nil),           // 53: xiaozhizhang.workflow.v1.RenderInstanceRequest
+	(*RenderedGraph)(nil),                   // 54: xiaozhizhang.workflow.v1.RenderedGraph
+	(*SendSignalRequest)(nil),               // 55: xiaozhizhang.workflow.v1.SendSignalRequest
+	(*SendSignalResponse)(nil),              // 56: xiaozhizhang.workflow.v1.SendSignalResponse
+	(*SendSignalByKeyRequest)(nil),          // 57: xiaozhizhang.workflow.v1.SendSignalByKeyRequest
+	(*SendSignalByKeyResponse)(nil),         // 58: xiaozhizhang.workflow.v1.SendSignalByKeyResponse
+	(*ScheduleSpec)(nil),                    // 59: xiaozhizhang.workflow.v1.ScheduleSpec
+	(*ScheduleInfo)(nil),                    // 60: xiaozhizhang.workflow.v1.ScheduleInfo
+	(*CreateScheduleRequest)(nil),           // 61: xiaozhizhang.workflow.v1.CreateScheduleRequest
+	(*CreateScheduleResponse)(nil),          // 62: xiaozhizhang.workflow.v1.CreateScheduleResponse
+	(*UpdateScheduleRequest)(nil),           // 63: xiaozhizhang.workflow.v1.UpdateScheduleRequest
+	(*UpdateScheduleResponse)(nil),          // 64: xiaozhizhang.workflow.v1.UpdateScheduleResponse
+	(*DeleteScheduleRequest)(nil),           // 65: xiaozhizhang.workflow.v1.DeleteScheduleRequest
+	(*DeleteScheduleResponse)(nil),          // 66: xiaozhizhang.workflow.v1.DeleteScheduleResponse
+	(*GetScheduleRequest)(nil),              // 67: xiaozhizhang.workflow.v1.GetScheduleRequest
+	(*GetScheduleResponse)(nil),             // 68: xiaozhizhang.workflow.v1.GetScheduleResponse
+	(*ListSchedulesRequest)(nil),            // 69: xiaozhizhang.workflow.v1.ListSchedulesRequest
+	(*ListSchedulesResponse)(nil),           // 70: xiaozhizhang.workflow.v1.ListSchedulesResponse
+	(*PauseScheduleRequest)(nil),            // 71: xiaozhizhang.workflow.v1.PauseScheduleRequest
+	(*PauseScheduleResponse)(nil),           // 72: xiaozhizhang.workflow.v1.PauseScheduleResponse
+	(*ResumeScheduleRequest)(nil),           // 73: xiaozhizhang.workflow.v1.ResumeScheduleRequest
+	(*ResumeScheduleResponse)(nil),          // 74: xiaozhizhang.workflow.v1.ResumeScheduleResponse
+	(*RunScheduleNowRequest)(nil),           // 75: xiaozhizhang.workflow.v1.RunScheduleNowRequest
+	(*RunScheduleNowResponse)(nil),          // 76: xiaozhizhang.workflow.v1.RunScheduleNowResponse
+	(*ListScheduleRunsRequest)(nil),         // 77: xiaozhizhang.workflow.v1.ListScheduleRunsRequest
+	(*ScheduleRunInfo)(nil),                 // 78: xiaozhizhang.workflow.v1.ScheduleRunInfo
+	(*ListScheduleRunsResponse)(nil),        // 79: xiaozhizhang.workflow.v1.ListScheduleRunsResponse
+	(*InstanceEvent)(nil),                   // 80: xiaozhizhang.workflow.v1.InstanceEvent
+	(*WatchInstanceRequest)(nil),            // 81: xiaozhizhang.workflow.v1.WatchInstanceRequest
+	(*WatchDefRequest)(nil),                 // 82: xiaozhizhang.workflow.v1.WatchDefRequest
+	nil,                                     // 83: xiaozhizhang.workflow.v1.MigrateInstancesRequest.NodeMappingEntry
+	nil,                                     // 84: xiaozhizhang.workflow.v1.RenderedGraph.NodeStatusEntry
 }
 var file_workflow_proto_depIdxs = []int32{
-	10, // 0: xiaozhizhang.workflow.v1.GetExecutionTrailResponse.checkpoints:type_name -> xiaozhizhang.workflow.v1.ExecutionTrailCheckpoint
-	14, // 1: xiaozhizhang.workflow.v1.ListDefsResponse.items:type_name -> xiaozhizhang.workflow.v1.GetDefResponse
-	20, // 2: xiaozhizhang.workflow.v1.ListInstancesResponse.items:type_name -> xiaozhizhang.workflow.v1.GetInstanceResponse
-	0,  // 3: xiaozhizhang.workflow.v1.WorkflowService.CreateDef:input_type -> xiaozhizhang.workflow.v1.CreateDefRequest
-	2,  // 4: xiaozhizhang.workflow.v1.WorkflowService.StartWorkflow:input_type -> xiaozhizhang.workflow.v1.StartWorkflowRequest
-	4,  // 5: xiaozhizhang.workflow.v1.WorkflowService.ReportNodeCompleted:input_type -> xiaozhizhang.workflow.v1.ReportNodeCompletedRequest
-	6,  // 6: xiaozhizhang.workflow.v1.WorkflowService.RollbackToNode:input_type -> xiaozhizhang.workflow.v1.RollbackToNodeRequest
-	8,  // 7: xiaozhizhang.workflow.v1.WorkflowService.GetExecutionTrail:input_type -> xiaozhizhang.workflow.v1.GetExecutionTrailRequest
-	11, // 8: xiaozhizhang.workflow.v1.WorkflowService.GetExecutionState:input_type -> xiaozhizhang.workflow.v1.GetExecutionStateRequest
-	13, // 9: xiaozhizhang.workflow.v1.WorkflowService.GetDef:input_type -> xiaozhizhang.workflow.v1.GetDefRequest
-	15, // 10: xiaozhizhang.workflow.v1.WorkflowService.ListDefs:input_type -> xiaozhizhang.workflow.v1.ListDefsRequest
-	17, // 11: xiaozhizhang.workflow.v1.WorkflowService.CreateIfNotExists:input_type -> xiaozhizhang.workflow.v1.CreateIfNotExistsRequest
-	19, // 12: xiaozhizhang.workflow.v1.WorkflowService.GetInstance:input_type -> xiaozhizhang.workflow.v1.GetInstanceRequest
-	21, // 13: xiaozhizhang.workflow.v1.WorkflowService.GetInstanceStatus:input_type -> xiaozhizhang.workflow.v1.GetInstanceStatusRequest
-	23, // 14: xiaozhizhang.workflow.v1.WorkflowService.ListInstances:input_type -> xiaozhizhang.workflow.v1.ListInstancesRequest
-	25, // 15: xiaozhizhang.workflow.v1.WorkflowService.CancelInstance:input_type -> xiaozhizhang.workflow.v1.CancelInstanceRequest
-	27, // 16: xiaozhizhang.workflow.v1.WorkflowService.RetryNode:input_type -> xiaozhizhang.workflow.v1.RetryNodeRequest
-	1,  // 17: xiaozhizhang.workflow.v1.WorkflowService.CreateDef:output_type -> xiaozhizhang.workflow.v1.CreateDefResponse
-	3,  // 18: xiaozhizhang.workflow.v1.WorkflowService.StartWorkflow:output_type -> xiaozhizhang.workflow.v1.StartWorkflowResponse
-	5,  // 19: xiaozhizhang.workflow.v1.WorkflowService.ReportNodeCompleted:output_type -> xiaozhizhang.workflow.v1.ReportNodeCompletedResponse
-	7,  // 20: xiaozhizhang.workflow.v1.WorkflowService.RollbackToNode:output_type -> xiaozhizhang.workflow.v1.RollbackToNodeResponse
-	9,  // 21: xiaozhizhang.workflow.v1.WorkflowService.GetExecutionTrail:output_type -> xiaozhizhang.workflow.v1.GetExecutionTrailResponse
-	12, // 22: xiaozhizhang.workflow.v1.WorkflowService.GetExecutionState:output_type -> xiaozhizhang.workflow.v1.GetExecutionStateResponse
-	14, // 23: xiaozhizhang.workflow.v1.WorkflowService.GetDef:output_type -> xiaozhizhang.workflow.v1.GetDefResponse
-	16, // 24: xiaozhizhang.workflow.v1.WorkflowService.ListDefs:output_type -> xiaozhizhang.workflow.v1.ListDefsResponse
-	18, // 25: xiaozhizhang.workflow.v1.WorkflowService.CreateIfNotExists:output_type -> xiaozhizhang.workflow.v1.CreateIfNotExistsResponse
-	20, // 26: xiaozhizhang.workflow.v1.WorkflowService.GetInstance:output_type -> xiaozhizhang.workflow.v1.GetInstanceResponse
-	22, // 27: xiaozhizhang.workflow.v1.WorkflowService.GetInstanceStatus:output_type -> xiaozhizhang.workflow.v1.GetInstanceStatusResponse
-	24, // 28: xiaozhizhang.workflow.v1.WorkflowService.ListInstances:output_type -> xiaozhizhang.workflow.v1.ListInstancesResponse
-	26, // 29: xiaozhizhang.workflow.v1.WorkflowService.CancelInstance:output_type -> xiaozhizhang.workflow.v1.CancelInstanceResponse
-	28, // 30: xiaozhizhang.workflow.v1.WorkflowService.RetryNode:output_type -> xiaozhizhang.workflow.v1.RetryNodeResponse
-	17, // [17:31] is the sub-list for method output_type
-	3,  // [3:17] is the sub-list for method input_type
-	3,  // [3:3] is the sub-list for extension type_name
-	3,  // [3:3] is the sub-list for extension extendee
-	0,  // [0:3] is the sub-list for field type_name
+	3,  // 0: xiaozhizhang.workflow.v1.ValidateDefResponse.issues:type_name -> xiaozhizhang.workflow.v1.DefLintIssue
+	14, // 1: xiaozhizhang.workflow.v1.GetExecutionTrailResponse.checkpoints:type_name -> xiaozhizhang.workflow.v1.ExecutionTrailCheckpoint
+	13, // 2: xiaozhizhang.workflow.v1.GetExecutionTrailResponse.children:type_name -> xiaozhizhang.workflow.v1.ExecutionTrailChild
+	18, // 3: xiaozhizhang.workflow.v1.ListDefsResponse.items:type_name -> xiaozhizhang.workflow.v1.GetDefResponse
+	24, // 4: xiaozhizhang.workflow.v1.ListInstancesResponse.items:type_name -> xiaozhizhang.workflow.v1.GetInstanceResponse
+	83, // 5: xiaozhizhang.workflow.v1.MigrateInstancesRequest.node_mapping:type_name -> xiaozhizhang.workflow.v1.MigrateInstancesRequest.NodeMappingEntry
+	47, // 6: xiaozhizhang.workflow.v1.MigrateInstancesResponse.results:type_name -> xiaozhizhang.workflow.v1.InstanceMigrationResult
+	50, // 7: xiaozhizhang.workflow.v1.ListInstanceMigrationsResponse.items:type_name -> xiaozhizhang.workflow.v1.InstanceMigrationRecord
+	84, // 8: xiaozhizhang.workflow.v1.RenderedGraph.node_status:type_name -> xiaozhizhang.workflow.v1.RenderedGraph.NodeStatusEntry
+	59, // 9: xiaozhizhang.workflow.v1.ScheduleInfo.spec:type_name -> xiaozhizhang.workflow.v1.ScheduleSpec
+	59, // 10: xiaozhizhang.workflow.v1.CreateScheduleRequest.spec:type_name -> xiaozhizhang.workflow.v1.ScheduleSpec
+	60, // 11: xiaozhizhang.workflow.v1.CreateScheduleResponse.schedule:type_name -> xiaozhizhang.workflow.v1.ScheduleInfo
+	59, // 12: xiaozhizhang.workflow.v1.UpdateScheduleRequest.spec:type_name -> xiaozhizhang.workflow.v1.ScheduleSpec
+	60, // 13: xiaozhizhang.workflow.v1.UpdateScheduleResponse.schedule:type_name -> xiaozhizhang.workflow.v1.ScheduleInfo
+	60, // 14: xiaozhizhang.workflow.v1.GetScheduleResponse.schedule:type_name -> xiaozhizhang.workflow.v1.ScheduleInfo
+	60, // 15: xiaozhizhang.workflow.v1.ListSchedulesResponse.items:type_name -> xiaozhizhang.workflow.v1.ScheduleInfo
+	78, // 16: xiaozhizhang.workflow.v1.ListScheduleRunsResponse.items:type_name -> xiaozhizhang.workflow.v1.ScheduleRunInfo
+	0,  // 17: xiaozhizhang.workflow.v1.WorkflowService.CreateDef:input_type -> xiaozhizhang.workflow.v1.CreateDefRequest
+	2,  // 18: xiaozhizhang.workflow.v1.WorkflowService.ValidateDef:input_type -> xiaozhizhang.workflow.v1.ValidateDefRequest
+	5,  // 19: xiaozhizhang.workflow.v1.WorkflowService.StartWorkflow:input_type -> xiaozhizhang.workflow.v1.StartWorkflowRequest
+	7,  // 20: xiaozhizhang.workflow.v1.WorkflowService.ReportNodeCompleted:input_type -> xiaozhizhang.workflow.v1.ReportNodeCompletedRequest
+	9,  // 21: xiaozhizhang.workflow.v1.WorkflowService.RollbackToNode:input_type -> xiaozhizhang.workflow.v1.RollbackToNodeRequest
+	11, // 22: xiaozhizhang.workflow.v1.WorkflowService.GetExecutionTrail:input_type -> xiaozhizhang.workflow.v1.GetExecutionTrailRequest
+	15, // 23: xiaozhizhang.workflow.v1.WorkflowService.GetExecutionState:input_type -> xiaozhizhang.workflow.v1.GetExecutionStateRequest
+	17, // 24: xiaozhizhang.workflow.v1.WorkflowService.GetDef:input_type -> xiaozhizhang.workflow.v1.GetDefRequest
+	19, // 25: xiaozhizhang.workflow.v1.WorkflowService.ListDefs:input_type -> xiaozhizhang.workflow.v1.ListDefsRequest
+	21, // 26: xiaozhizhang.workflow.v1.WorkflowService.CreateIfNotExists:input_type -> xiaozhizhang.workflow.v1.CreateIfNotExistsRequest
+	23, // 27: xiaozhizhang.workflow.v1.WorkflowService.GetInstance:input_type -> xiaozhizhang.workflow.v1.GetInstanceRequest
+	25, // 28: xiaozhizhang.workflow.v1.WorkflowService.GetInstanceByBusinessKey:input_type -> xiaozhizhang.workflow.v1.GetInstanceByBusinessKeyRequest
+	26, // 29: xiaozhizhang.workflow.v1.WorkflowService.GetInstanceStatus:input_type -> xiaozhizhang.workflow.v1.GetInstanceStatusRequest
+	28, // 30: xiaozhizhang.workflow.v1.WorkflowService.ListInstances:input_type -> xiaozhizhang.workflow.v1.ListInstancesRequest
+	30, // 31: xiaozhizhang.workflow.v1.WorkflowService.CancelInstance:input_type -> xiaozhizhang.workflow.v1.CancelInstanceRequest
+	32, // 32: xiaozhizhang.workflow.v1.WorkflowService.RetryNode:input_type -> xiaozhizhang.workflow.v1.RetryNodeRequest
+	34, // 33: xiaozhizhang.workflow.v1.WorkflowService.CompensateInstance:input_type -> xiaozhizhang.workflow.v1.CompensateInstanceRequest
+	36, // 34: xiaozhizhang.workflow.v1.WorkflowService.ForkInstance:input_type -> xiaozhizhang.workflow.v1.ForkInstanceRequest
+	38, // 35: xiaozhizhang.workflow.v1.WorkflowService.PauseInstance:input_type -> xiaozhizhang.workflow.v1.PauseInstanceRequest
+	40, // 36: xiaozhizhang.workflow.v1.WorkflowService.ResumeInstance:input_type -> xiaozhizhang.workflow.v1.ResumeInstanceRequest
+	42, // 37: xiaozhizhang.workflow.v1.WorkflowService.PauseInstancesByDef:input_type -> xiaozhizhang.workflow.v1.PauseInstancesByDefRequest
+	44, // 38: xiaozhizhang.workflow.v1.WorkflowService.ResumeInstancesByDef:input_type -> xiaozhizhang.workflow.v1.ResumeInstancesByDefRequest
+	46, // 39: xiaozhizhang.workflow.v1.WorkflowService.MigrateInstances:input_type -> xiaozhizhang.workflow.v1.MigrateInstancesRequest
+	49, // 40: xiaozhizhang.workflow.v1.WorkflowService.ListInstanceMigrations:input_type -> xiaozhizhang.workflow.v1.ListInstanceMigrationsRequest
+	52, // 41: xiaozhizhang.workflow.v1.WorkflowService.RenderDef:input_type -> xiaozhizhang.workflow.v1.RenderDefRequest
+	53, // 42: xiaozhizhang.workflow.v1.WorkflowService.RenderInstance:input_type -> xiaozhizhang.workflow.v1.RenderInstanceRequest
+	55, // 43: xiaozhizhang.workflow.v1.WorkflowService.SendSignal:input_type -> xiaozhizhang.workflow.v1.SendSignalRequest
+	57, // 44: xiaozhizhang.workflow.v1.WorkflowService.SendSignalByKey:input_type -> xiaozhizhang.workflow.v1.SendSignalByKeyRequest
+	61, // 45: xiaozhizhang.workflow.v1.WorkflowService.CreateSchedule:input_type -> xiaozhizhang.workflow.v1.CreateScheduleRequest
+	63, // 46: xiaozhizhang.workflow.v1.WorkflowService.UpdateSchedule:input_type -> xiaozhizhang.workflow.v1.UpdateScheduleRequest
+	65, // 47: xiaozhizhang.workflow.v1.WorkflowService.DeleteSchedule:input_type -> xiaozhizhang.workflow.v1.DeleteScheduleRequest
+	67, // 48: xiaozhizhang.workflow.v1.WorkflowService.GetSchedule:input_type -> xiaozhizhang.workflow.v1.GetScheduleRequest
+	69, // 49: xiaozhizhang.workflow.v1.WorkflowService.ListSchedules:input_type -> xiaozhizhang.workflow.v1.ListSchedulesRequest
+	71, // 50: xiaozhizhang.workflow.v1.WorkflowService.PauseSchedule:input_type -> xiaozhizhang.workflow.v1.PauseScheduleRequest
+	73, // 51: xiaozhizhang.workflow.v1.WorkflowService.ResumeSchedule:input_type -> xiaozhizhang.workflow.v1.ResumeScheduleRequest
+	75, // 52: xiaozhizhang.workflow.v1.WorkflowService.RunScheduleNow:input_type -> xiaozhizhang.workflow.v1.RunScheduleNowRequest
+	77, // 53: xiaozhizhang.workflow.v1.WorkflowService.ListScheduleRuns:input_type -> xiaozhizhang.workflow.v1.ListScheduleRunsRequest
+	81, // 54: xiaozhizhang.workflow.v1.WorkflowService.WatchInstance:input_type -> xiaozhizhang.workflow.v1.WatchInstanceRequest
+	82, // 55: xiaozhizhang.workflow.v1.WorkflowService.WatchDef:input_type -> xiaozhizhang.workflow.v1.WatchDefRequest
+	1,  // 56: xiaozhizhang.workflow.v1.WorkflowService.CreateDef:output_type -> xiaozhizhang.workflow.v1.CreateDefResponse
+	4,  // 57: xiaozhizhang.workflow.v1.WorkflowService.ValidateDef:output_type -> xiaozhizhang.workflow.v1.ValidateDefResponse
+	6,  // 58: xiaozhizhang.workflow.v1.WorkflowService.StartWorkflow:output_type -> xiaozhizhang.workflow.v1.StartWorkflowResponse
+	8,  // 59: xiaozhizhang.workflow.v1.WorkflowService.ReportNodeCompleted:output_type -> xiaozhizhang.workflow.v1.ReportNodeCompletedResponse
+	10, // 60: xiaozhizhang.workflow.v1.WorkflowService.RollbackToNode:output_type -> xiaozhizhang.workflow.v1.RollbackToNodeResponse
+	12, // 61: xiaozhizhang.workflow.v1.WorkflowService.GetExecutionTrail:output_type -> xiaozhizhang.workflow.v1.GetExecutionTrailResponse
+	16, // 62: xiaozhizhang.workflow.v1.WorkflowService.GetExecutionState:output_type -> xiaozhizhang.workflow.v1.GetExecutionStateResponse
+	18, // 63: xiaozhizhang.workflow.v1.WorkflowService.GetDef:output_type -> xiaozhizhang.workflow.v1.GetDefResponse
+	20, // 64: xiaozhizhang.workflow.v1.WorkflowService.ListDefs:output_type -> xiaozhizhang.workflow.v1.ListDefsResponse
+	22, // 65: xiaozhizhang.workflow.v1.WorkflowService.CreateIfNotExists:output_type -> xiaozhizhang.workflow.v1.CreateIfNotExistsResponse
+	24, // 66: xiaozhizhang.workflow.v1.WorkflowService.GetInstance:output_type -> xiaozhizhang.workflow.v1.GetInstanceResponse
+	24, // 67: xiaozhizhang.workflow.v1.WorkflowService.GetInstanceByBusinessKey:output_type -> xiaozhizhang.workflow.v1.GetInstanceResponse
+	27, // 68: xiaozhizhang.workflow.v1.WorkflowService.GetInstanceStatus:output_type -> xiaozhizhang.workflow.v1.GetInstanceStatusResponse
+	29, // 69: xiaozhizhang.workflow.v1.WorkflowService.ListInstances:output_type -> xiaozhizhang.workflow.v1.ListInstancesResponse
+	31, // 70: xiaozhizhang.workflow.v1.WorkflowService.CancelInstance:output_type -> xiaozhizhang.workflow.v1.CancelInstanceResponse
+	33, // 71: xiaozhizhang.workflow.v1.WorkflowService.RetryNode:output_type -> xiaozhizhang.workflow.v1.RetryNodeResponse
+	35, // 72: xiaozhizhang.workflow.v1.WorkflowService.CompensateInstance:output_type -> xiaozhizhang.workflow.v1.CompensateInstanceResponse
+	37, // 73: xiaozhizhang.workflow.v1.WorkflowService.ForkInstance:output_type -> xiaozhizhang.workflow.v1.ForkInstanceResponse
+	39, // 74: xiaozhizhang.workflow.v1.WorkflowService.PauseInstance:output_type -> xiaozhizhang.workflow.v1.PauseInstanceResponse
+	41, // 75: xiaozhizhang.workflow.v1.WorkflowService.ResumeInstance:output_type -> xiaozhizhang.workflow.v1.ResumeInstanceResponse
+	43, // 76: xiaozhizhang.workflow.v1.WorkflowService.PauseInstancesByDef:output_type -> xiaozhizhang.workflow.v1.PauseInstancesByDefResponse
+	45, // 77: xiaozhizhang.workflow.v1.WorkflowService.ResumeInstancesByDef:output_type -> xiaozhizhang.workflow.v1.ResumeInstancesByDefResponse
+	48, // 78: xiaozhizhang.workflow.v1.WorkflowService.MigrateInstances:output_type -> xiaozhizhang.workflow.v1.MigrateInstancesResponse
+	51, // 79: xiaozhizhang.workflow.v1.WorkflowService.ListInstanceMigrations:output_type -> xiaozhizhang.workflow.v1.ListInstanceMigrationsResponse
+	54, // 80: xiaozhizhang.workflow.v1.WorkflowService.RenderDef:output_type -> xiaozhizhang.workflow.v1.RenderedGraph
+	54, // 81: xiaozhizhang.workflow.v1.WorkflowService.RenderInstance:output_type -> xiaozhizhang.workflow.v1.RenderedGraph
+	56, // 82: xiaozhizhang.workflow.v1.WorkflowService.SendSignal:output_type -> xiaozhizhang.workflow.v1.SendSignalResponse
+	58, // 83: xiaozhizhang.workflow.v1.WorkflowService.SendSignalByKey:output_type -> xiaozhizhang.workflow.v1.SendSignalByKeyResponse
+	62, // 84: xiaozhizhang.workflow.v1.WorkflowService.CreateSchedule:output_type -> xiaozhizhang.workflow.v1.CreateScheduleResponse
+	64, // 85: xiaozhizhang.workflow.v1.WorkflowService.UpdateSchedule:output_type -> xiaozhizhang.workflow.v1.UpdateScheduleResponse
+	66, // 86: xiaozhizhang.workflow.v1.WorkflowService.DeleteSchedule:output_type -> xiaozhizhang.workflow.v1.DeleteScheduleResponse
+	68, // 87: xiaozhizhang.workflow.v1.WorkflowService.GetSchedule:output_type -> xiaozhizhang.workflow.v1.GetScheduleResponse
+	70, // 88: xiaozhizhang.workflow.v1.WorkflowService.ListSchedules:output_type -> xiaozhizhang.workflow.v1.ListSchedulesResponse
+	72, // 89: xiaozhizhang.workflow.v1.WorkflowService.PauseSchedule:output_type -> xiaozhizhang.workflow.v1.PauseScheduleResponse
+	74, // 90: xiaozhizhang.workflow.v1.WorkflowService.ResumeSchedule:output_type -> xiaozhizhang.workflow.v1.ResumeScheduleResponse
+	76, // 91: xiaozhizhang.workflow.v1.WorkflowService.RunScheduleNow:output_type -> xiaozhizhang.workflow.v1.RunScheduleNowResponse
+	79, // 92: xiaozhizhang.workflow.v1.WorkflowService.ListScheduleRuns:output_type -> xiaozhizhang.workflow.v1.ListScheduleRunsResponse
+	80, // 93: xiaozhizhang.workflow.v1.WorkflowService.WatchInstance:output_type -> xiaozhizhang.workflow.v1.InstanceEvent
+	80, // 94: xiaozhizhang.workflow.v1.WorkflowService.WatchDef:output_type -> xiaozhizhang.workflow.v1.InstanceEvent
+	56, // [56:95] is the sub-list for method output_type
+	17, // [17:56] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
 }
 
 func init() { file_workflow_proto_init() }
@@ -2056,7 +5775,7 @@ func file_workflow_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_workflow_proto_rawDesc), len(file_workflow_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   29,
+			NumMessages:   85,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,20 +19,45 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	WorkflowService_CreateDef_FullMethodName           = "/xiaozhizhang.workflow.v1.WorkflowService/CreateDef"
-	WorkflowService_StartWorkflow_FullMethodName       = "/xiaozhizhang.workflow.v1.WorkflowService/StartWorkflow"
-	WorkflowService_ReportNodeCompleted_FullMethodName = "/xiaozhizhang.workflow.v1.WorkflowService/ReportNodeCompleted"
-	WorkflowService_RollbackToNode_FullMethodName      = "/xiaozhizhang.workflow.v1.WorkflowService/RollbackToNode"
-	WorkflowService_GetExecutionTrail_FullMethodName   = "/xiaozhizhang.workflow.v1.WorkflowService/GetExecutionTrail"
-	WorkflowService_GetExecutionState_FullMethodName   = "/xiaozhizhang.workflow.v1.WorkflowService/GetExecutionState"
-	WorkflowService_GetDef_FullMethodName              = "/xiaozhizhang.workflow.v1.WorkflowService/GetDef"
-	WorkflowService_ListDefs_FullMethodName            = "/xiaozhizhang.workflow.v1.WorkflowService/ListDefs"
-	WorkflowService_CreateIfNotExists_FullMethodName   = "/xiaozhizhang.workflow.v1.WorkflowService/CreateIfNotExists"
-	WorkflowService_GetInstance_FullMethodName         = "/xiaozhizhang.workflow.v1.WorkflowService/GetInstance"
-	WorkflowService_GetInstanceStatus_FullMethodName   = "/xiaozhizhang.workflow.v1.WorkflowService/GetInstanceStatus"
-	WorkflowService_ListInstances_FullMethodName       = "/xiaozhizhang.workflow.v1.WorkflowService/ListInstances"
-	WorkflowService_CancelInstance_FullMethodName      = "/xiaozhizhang.workflow.v1.WorkflowService/CancelInstance"
-	WorkflowService_RetryNode_FullMethodName           = "/xiaozhizhang.workflow.v1.WorkflowService/RetryNode"
+	WorkflowService_CreateDef_FullMethodName                = "/xiaozhizhang.workflow.v1.WorkflowService/CreateDef"
+	WorkflowService_ValidateDef_FullMethodName              = "/xiaozhizhang.workflow.v1.WorkflowService/ValidateDef"
+	WorkflowService_StartWorkflow_FullMethodName            = "/xiaozhizhang.workflow.v1.WorkflowService/StartWorkflow"
+	WorkflowService_ReportNodeCompleted_FullMethodName      = "/xiaozhizhang.workflow.v1.WorkflowService/ReportNodeCompleted"
+	WorkflowService_RollbackToNode_FullMethodName           = "/xiaozhizhang.workflow.v1.WorkflowService/RollbackToNode"
+	WorkflowService_GetExecutionTrail_FullMethodName        = "/xiaozhizhang.workflow.v1.WorkflowService/GetExecutionTrail"
+	WorkflowService_GetExecutionState_FullMethodName        = "/xiaozhizhang.workflow.v1.WorkflowService/GetExecutionState"
+	WorkflowService_GetDef_FullMethodName                   = "/xiaozhizhang.workflow.v1.WorkflowService/GetDef"
+	WorkflowService_ListDefs_FullMethodName                 = "/xiaozhizhang.workflow.v1.WorkflowService/ListDefs"
+	WorkflowService_CreateIfNotExists_FullMethodName        = "/xiaozhizhang.workflow.v1.WorkflowService/CreateIfNotExists"
+	WorkflowService_GetInstance_FullMethodName              = "/xiaozhizhang.workflow.v1.WorkflowService/GetInstance"
+	WorkflowService_GetInstanceByBusinessKey_FullMethodName = "/xiaozhizhang.workflow.v1.WorkflowService/GetInstanceByBusinessKey"
+	WorkflowService_GetInstanceStatus_FullMethodName        = "/xiaozhizhang.workflow.v1.WorkflowService/GetInstanceStatus"
+	WorkflowService_ListInstances_FullMethodName            = "/xiaozhizhang.workflow.v1.WorkflowService/ListInstances"
+	WorkflowService_CancelInstance_FullMethodName           = "/xiaozhizhang.workflow.v1.WorkflowService/CancelInstance"
+	WorkflowService_RetryNode_FullMethodName                = "/xiaozhizhang.workflow.v1.WorkflowService/RetryNode"
+	WorkflowService_CompensateInstance_FullMethodName       = "/xiaozhizhang.workflow.v1.WorkflowService/CompensateInstance"
+	WorkflowService_ForkInstance_FullMethodName             = "/xiaozhizhang.workflow.v1.WorkflowService/ForkInstance"
+	WorkflowService_PauseInstance_FullMethodName            = "/xiaozhizhang.workflow.v1.WorkflowService/PauseInstance"
+	WorkflowService_ResumeInstance_FullMethodName           = "/xiaozhizhang.workflow.v1.WorkflowService/ResumeInstance"
+	WorkflowService_PauseInstancesByDef_FullMethodName      = "/xiaozhizhang.workflow.v1.WorkflowService/PauseInstancesByDef"
+	WorkflowService_ResumeInstancesByDef_FullMethodName     = "/xiaozhizhang.workflow.v1.WorkflowService/ResumeInstancesByDef"
+	WorkflowService_MigrateInstances_FullMethodName         = "/xiaozhizhang.workflow.v1.WorkflowService/MigrateInstances"
+	WorkflowService_ListInstanceMigrations_FullMethodName   = "/xiaozhizhang.workflow.v1.WorkflowService/ListInstanceMigrations"
+	WorkflowService_RenderDef_FullMethodName                = "/xiaozhizhang.workflow.v1.WorkflowService/RenderDef"
+	WorkflowService_RenderInstance_FullMethodName           = "/xiaozhizhang.workflow.v1.WorkflowService/RenderInstance"
+	WorkflowService_SendSignal_FullMethodName               = "/xiaozhizhang.workflow.v1.WorkflowService/SendSignal"
+	WorkflowService_SendSignalByKey_FullMethodName          = "/xiaozhizhang.workflow.v1.WorkflowService/SendSignalByKey"
+	WorkflowService_CreateSchedule_FullMethodName           = "/xiaozhizhang.workflow.v1.WorkflowService/CreateSchedule"
+	WorkflowService_UpdateSchedule_FullMethodName           = "/xiaozhizhang.workflow.v1.WorkflowService/UpdateSchedule"
+	WorkflowService_DeleteSchedule_FullMethodName           = "/xiaozhizhang.workflow.v1.WorkflowService/DeleteSchedule"
+	WorkflowService_GetSchedule_FullMethodName              = "/xiaozhizhang.workflow.v1.WorkflowService/GetSchedule"
+	WorkflowService_ListSchedules_FullMethodName            = "/xiaozhizhang.workflow.v1.WorkflowService/ListSchedules"
+	WorkflowService_PauseSchedule_FullMethodName            = "/xiaozhizhang.workflow.v1.WorkflowService/PauseSchedule"
+	WorkflowService_ResumeSchedule_FullMethodName           = "/xiaozhizhang.workflow.v1.WorkflowService/ResumeSchedule"
+	WorkflowService_RunScheduleNow_FullMethodName           = "/xiaozhizhang.workflow.v1.WorkflowService/RunScheduleNow"
+	WorkflowService_ListScheduleRuns_FullMethodName         = "/xiaozhizhang.workflow.v1.WorkflowService/ListScheduleRuns"
+	WorkflowService_WatchInstance_FullMethodName            = "/xiaozhizhang.workflow.v1.WorkflowService/WatchInstance"
+	WorkflowService_WatchDef_FullMethodName                 = "/xiaozhizhang.workflow.v1.WorkflowService/WatchDef"
 )
 
 // WorkflowServiceClient is the client API for WorkflowService service.
@@ -42,6 +67,7 @@ const (
 // WorkflowService 工作流服务
 type WorkflowServiceClient interface {
 	CreateDef(ctx context.Context, in *CreateDefRequest, opts ...grpc.CallOption) (*CreateDefResponse, error)
+	ValidateDef(ctx context.Context, in *ValidateDefRequest, opts ...grpc.CallOption) (*ValidateDefResponse, error)
 	StartWorkflow(ctx context.Context, in *StartWorkflowRequest, opts ...grpc.CallOption) (*StartWorkflowResponse, error)
 	ReportNodeCompleted(ctx context.Context, in *ReportNodeCompletedRequest, opts ...grpc.CallOption) (*ReportNodeCompletedResponse, error)
 	RollbackToNode(ctx context.Context, in *RollbackToNodeRequest, opts ...grpc.CallOption) (*RollbackToNodeResponse, error)
@@ -51,10 +77,36 @@ type WorkflowServiceClient interface {
 	ListDefs(ctx context.Context, in *ListDefsRequest, opts ...grpc.CallOption) (*ListDefsResponse, error)
 	CreateIfNotExists(ctx context.Context, in *CreateIfNotExistsRequest, opts ...grpc.CallOption) (*CreateIfNotExistsResponse, error)
 	GetInstance(ctx context.Context, in *GetInstanceRequest, opts ...grpc.CallOption) (*GetInstanceResponse, error)
+	GetInstanceByBusinessKey(ctx context.Context, in *GetInstanceByBusinessKeyRequest, opts ...grpc.CallOption) (*GetInstanceResponse, error)
 	GetInstanceStatus(ctx context.Context, in *GetInstanceStatusRequest, opts ...grpc.CallOption) (*GetInstanceStatusResponse, error)
 	ListInstances(ctx context.Context, in *ListInstancesRequest, opts ...grpc.CallOption) (*ListInstancesResponse, error)
 	CancelInstance(ctx context.Context, in *CancelInstanceRequest, opts ...grpc.CallOption) (*CancelInstanceResponse, error)
 	RetryNode(ctx context.Context, in *RetryNodeRequest, opts ...grpc.CallOption) (*RetryNodeResponse, error)
+	CompensateInstance(ctx context.Context, in *CompensateInstanceRequest, opts ...grpc.CallOption) (*CompensateInstanceResponse, error)
+	ForkInstance(ctx context.Context, in *ForkInstanceRequest, opts ...grpc.CallOption) (*ForkInstanceResponse, error)
+	PauseInstance(ctx context.Context, in *PauseInstanceRequest, opts ...grpc.CallOption) (*PauseInstanceResponse, error)
+	ResumeInstance(ctx context.Context, in *ResumeInstanceRequest, opts ...grpc.CallOption) (*ResumeInstanceResponse, error)
+	PauseInstancesByDef(ctx context.Context, in *PauseInstancesByDefRequest, opts ...grpc.CallOption) (*PauseInstancesByDefResponse, error)
+	ResumeInstancesByDef(ctx context.Context, in *ResumeInstancesByDefRequest, opts ...grpc.CallOption) (*ResumeInstancesByDefResponse, error)
+	MigrateInstances(ctx context.Context, in *MigrateInstancesRequest, opts ...grpc.CallOption) (*MigrateInstancesResponse, error)
+	ListInstanceMigrations(ctx context.Context, in *ListInstanceMigrationsRequest, opts ...grpc.CallOption) (*ListInstanceMigrationsResponse, error)
+	RenderDef(ctx context.Context, in *RenderDefRequest, opts ...grpc.CallOption) (*RenderedGraph, error)
+	RenderInstance(ctx context.Context, in *RenderInstanceRequest, opts ...grpc.CallOption) (*RenderedGraph, error)
+	SendSignal(ctx context.Context, in *SendSignalRequest, opts ...grpc.CallOption) (*SendSignalResponse, error)
+	SendSignalByKey(ctx context.Context, in *SendSignalByKeyRequest, opts ...grpc.CallOption) (*SendSignalByKeyResponse, error)
+	// 定时调度
+	CreateSchedule(ctx context.Context, in *CreateScheduleRequest, opts ...grpc.CallOption) (*CreateScheduleResponse, error)
+	UpdateSchedule(ctx context.Context, in *UpdateScheduleRequest, opts ...grpc.CallOption) (*UpdateScheduleResponse, error)
+	DeleteSchedule(ctx context.Context, in *DeleteScheduleRequest, opts ...grpc.CallOption) (*DeleteScheduleResponse, error)
+	GetSchedule(ctx context.Context, in *GetScheduleRequest, opts ...grpc.CallOption) (*GetScheduleResponse, error)
+	ListSchedules(ctx context.Context, in *ListSchedulesRequest, opts ...grpc.CallOption) (*ListSchedulesResponse, error)
+	PauseSchedule(ctx context.Context, in *PauseScheduleRequest, opts ...grpc.CallOption) (*PauseScheduleResponse, error)
+	ResumeSchedule(ctx context.Context, in *ResumeScheduleRequest, opts ...grpc.CallOption) (*ResumeScheduleResponse, error)
+	RunScheduleNow(ctx context.Context, in *RunScheduleNowRequest, opts ...grpc.CallOption) (*RunScheduleNowResponse, error)
+	ListScheduleRuns(ctx context.Context, in *ListScheduleRunsRequest, opts ...grpc.CallOption) (*ListScheduleRunsResponse, error)
+	// 实例事件流
+	WatchInstance(ctx context.Context, in *WatchInstanceRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[InstanceEvent], error)
+	WatchDef(ctx context.Context, in *WatchDefRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[InstanceEvent], error)
 }
 
 type workflowServiceClient struct {
@@ -75,6 +127,16 @@ func (c *workflowServiceClient) CreateDef(ctx context.Context, in *CreateDefRequ
 	return out, nil
 }
 
+func (c *workflowServiceClient) ValidateDef(ctx context.Context, in *ValidateDefRequest, opts ...grpc.CallOption) (*ValidateDefResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidateDefResponse)
+	err := c.cc.Invoke(ctx, WorkflowService_ValidateDef_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *workflowServiceClient) StartWorkflow(ctx context.Context, in *StartWorkflowRequest, opts ...grpc.CallOption) (*StartWorkflowResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(StartWorkflowResponse)
@@ -165,6 +227,16 @@ func (c *workflowServiceClient) GetInstance(ctx context.Context, in *GetInstance
 	return out, nil
 }
 
+func (c *workflowServiceClient) GetInstanceByBusinessKey(ctx context.Context, in *GetInstanceByBusinessKeyRequest, opts ...grpc.CallOption) (*GetInstanceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetInstanceResponse)
+	err := c.cc.Invoke(ctx, WorkflowService_GetInstanceByBusinessKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *workflowServiceClient) GetInstanceStatus(ctx context.Context, in *GetInstanceStatusRequest, opts ...grpc.CallOption) (*GetInstanceStatusResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetInstanceStatusResponse)
@@ -205,6 +277,254 @@ func (c *workflowServiceClient) RetryNode(ctx context.Context, in *RetryNodeRequ
 	return out, nil
 }
 
+func (c *workflowServiceClient) CompensateInstance(ctx context.Context, in *CompensateInstanceRequest, opts ...grpc.CallOption) (*CompensateInstanceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CompensateInstanceResponse)
+	err := c.cc.Invoke(ctx, WorkflowService_CompensateInstance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowServiceClient) ForkInstance(ctx context.Context, in *ForkInstanceRequest, opts ...grpc.CallOption) (*ForkInstanceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ForkInstanceResponse)
+	err := c.cc.Invoke(ctx, WorkflowService_ForkInstance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowServiceClient) PauseInstance(ctx context.Context, in *PauseInstanceRequest, opts ...grpc.CallOption) (*PauseInstanceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PauseInstanceResponse)
+	err := c.cc.Invoke(ctx, WorkflowService_PauseInstance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowServiceClient) ResumeInstance(ctx context.Context, in *ResumeInstanceRequest, opts ...grpc.CallOption) (*ResumeInstanceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResumeInstanceResponse)
+	err := c.cc.Invoke(ctx, WorkflowService_ResumeInstance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowServiceClient) PauseInstancesByDef(ctx context.Context, in *PauseInstancesByDefRequest, opts ...grpc.CallOption) (*PauseInstancesByDefResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PauseInstancesByDefResponse)
+	err := c.cc.Invoke(ctx, WorkflowService_PauseInstancesByDef_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowServiceClient) ResumeInstancesByDef(ctx context.Context, in *ResumeInstancesByDefRequest, opts ...grpc.CallOption) (*ResumeInstancesByDefResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResumeInstancesByDefResponse)
+	err := c.cc.Invoke(ctx, WorkflowService_ResumeInstancesByDef_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowServiceClient) MigrateInstances(ctx context.Context, in *MigrateInstancesRequest, opts ...grpc.CallOption) (*MigrateInstancesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MigrateInstancesResponse)
+	err := c.cc.Invoke(ctx, WorkflowService_MigrateInstances_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowServiceClient) ListInstanceMigrations(ctx context.Context, in *ListInstanceMigrationsRequest, opts ...grpc.CallOption) (*ListInstanceMigrationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListInstanceMigrationsResponse)
+	err := c.cc.Invoke(ctx, WorkflowService_ListInstanceMigrations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowServiceClient) RenderDef(ctx context.Context, in *RenderDefRequest, opts ...grpc.CallOption) (*RenderedGraph, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RenderedGraph)
+	err := c.cc.Invoke(ctx, WorkflowService_RenderDef_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowServiceClient) RenderInstance(ctx context.Context, in *RenderInstanceRequest, opts ...grpc.CallOption) (*RenderedGraph, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RenderedGraph)
+	err := c.cc.Invoke(ctx, WorkflowService_RenderInstance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowServiceClient) SendSignal(ctx context.Context, in *SendSignalRequest, opts ...grpc.CallOption) (*SendSignalResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SendSignalResponse)
+	err := c.cc.Invoke(ctx, WorkflowService_SendSignal_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowServiceClient) SendSignalByKey(ctx context.Context, in *SendSignalByKeyRequest, opts ...grpc.CallOption) (*SendSignalByKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SendSignalByKeyResponse)
+	err := c.cc.Invoke(ctx, WorkflowService_SendSignalByKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowServiceClient) CreateSchedule(ctx context.Context, in *CreateScheduleRequest, opts ...grpc.CallOption) (*CreateScheduleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateScheduleResponse)
+	err := c.cc.Invoke(ctx, WorkflowService_CreateSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowServiceClient) UpdateSchedule(ctx context.Context, in *UpdateScheduleRequest, opts ...grpc.CallOption) (*UpdateScheduleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateScheduleResponse)
+	err := c.cc.Invoke(ctx, WorkflowService_UpdateSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowServiceClient) DeleteSchedule(ctx context.Context, in *DeleteScheduleRequest, opts ...grpc.CallOption) (*DeleteScheduleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteScheduleResponse)
+	err := c.cc.Invoke(ctx, WorkflowService_DeleteSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowServiceClient) GetSchedule(ctx context.Context, in *GetScheduleRequest, opts ...grpc.CallOption) (*GetScheduleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetScheduleResponse)
+	err := c.cc.Invoke(ctx, WorkflowService_GetSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowServiceClient) ListSchedules(ctx context.Context, in *ListSchedulesRequest, opts ...grpc.CallOption) (*ListSchedulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSchedulesResponse)
+	err := c.cc.Invoke(ctx, WorkflowService_ListSchedules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowServiceClient) PauseSchedule(ctx context.Context, in *PauseScheduleRequest, opts ...grpc.CallOption) (*PauseScheduleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PauseScheduleResponse)
+	err := c.cc.Invoke(ctx, WorkflowService_PauseSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowServiceClient) ResumeSchedule(ctx context.Context, in *ResumeScheduleRequest, opts ...grpc.CallOption) (*ResumeScheduleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResumeScheduleResponse)
+	err := c.cc.Invoke(ctx, WorkflowService_ResumeSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowServiceClient) RunScheduleNow(ctx context.Context, in *RunScheduleNowRequest, opts ...grpc.CallOption) (*RunScheduleNowResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RunScheduleNowResponse)
+	err := c.cc.Invoke(ctx, WorkflowService_RunScheduleNow_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowServiceClient) ListScheduleRuns(ctx context.Context, in *ListScheduleRunsRequest, opts ...grpc.CallOption) (*ListScheduleRunsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListScheduleRunsResponse)
+	err := c.cc.Invoke(ctx, WorkflowService_ListScheduleRuns_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowServiceClient) WatchInstance(ctx context.Context, in *WatchInstanceRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[InstanceEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &WorkflowService_ServiceDesc.Streams[0], WorkflowService_WatchInstance_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchInstanceRequest, InstanceEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WorkflowService_WatchInstanceClient = grpc.ServerStreamingClient[InstanceEvent]
+
+func (c *workflowServiceClient) WatchDef(ctx context.Context, in *WatchDefRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[InstanceEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &WorkflowService_ServiceDesc.Streams[1], WorkflowService_WatchDef_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchDefRequest, InstanceEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WorkflowService_WatchDefClient = grpc.ServerStreamingClient[InstanceEvent]
+
 // WorkflowServiceServer is the server API for WorkflowService service.
 // All implementations must embed UnimplementedWorkflowServiceServer
 // for forward compatibility.
@@ -212,6 +532,7 @@ func (c *workflowServiceClient) RetryNode(ctx context.Context, in *RetryNodeRequ
 // WorkflowService 工作流服务
 type WorkflowServiceServer interface {
 	CreateDef(context.Context, *CreateDefRequest) (*CreateDefResponse, error)
+	ValidateDef(context.Context, *ValidateDefRequest) (*ValidateDefResponse, error)
 	StartWorkflow(context.Context, *StartWorkflowRequest) (*StartWorkflowResponse, error)
 	ReportNodeCompleted(context.Context, *ReportNodeCompletedRequest) (*ReportNodeCompletedResponse, error)
 	RollbackToNode(context.Context, *RollbackToNodeRequest) (*RollbackToNodeResponse, error)
@@ -221,10 +542,36 @@ type WorkflowServiceServer interface {
 	ListDefs(context.Context, *ListDefsRequest) (*ListDefsResponse, error)
 	CreateIfNotExists(context.Context, *CreateIfNotExistsRequest) (*CreateIfNotExistsResponse, error)
 	GetInstance(context.Context, *GetInstanceRequest) (*GetInstanceResponse, error)
+	GetInstanceByBusinessKey(context.Context, *GetInstanceByBusinessKeyRequest) (*GetInstanceResponse, error)
 	GetInstanceStatus(context.Context, *GetInstanceStatusRequest) (*GetInstanceStatusResponse, error)
 	ListInstances(context.Context, *ListInstancesRequest) (*ListInstancesResponse, error)
 	CancelInstance(context.Context, *CancelInstanceRequest) (*CancelInstanceResponse, error)
 	RetryNode(context.Context, *RetryNodeRequest) (*RetryNodeResponse, error)
+	CompensateInstance(context.Context, *CompensateInstanceRequest) (*CompensateInstanceResponse, error)
+	ForkInstance(context.Context, *ForkInstanceRequest) (*ForkInstanceResponse, error)
+	PauseInstance(context.Context, *PauseInstanceRequest) (*PauseInstanceResponse, error)
+	ResumeInstance(context.Context, *ResumeInstanceRequest) (*ResumeInstanceResponse, error)
+	PauseInstancesByDef(context.Context, *PauseInstancesByDefRequest) (*PauseInstancesByDefResponse, error)
+	ResumeInstancesByDef(context.Context, *ResumeInstancesByDefRequest) (*ResumeInstancesByDefResponse, error)
+	MigrateInstances(context.Context, *MigrateInstancesRequest) (*MigrateInstancesResponse, error)
+	ListInstanceMigrations(context.Context, *ListInstanceMigrationsRequest) (*ListInstanceMigrationsResponse, error)
+	RenderDef(context.Context, *RenderDefRequest) (*RenderedGraph, error)
+	RenderInstance(context.Context, *RenderInstanceRequest) (*RenderedGraph, error)
+	SendSignal(context.Context, *SendSignalRequest) (*SendSignalResponse, error)
+	SendSignalByKey(context.Context, *SendSignalByKeyRequest) (*SendSignalByKeyResponse, error)
+	// 定时调度
+	CreateSchedule(context.Context, *CreateScheduleRequest) (*CreateScheduleResponse, error)
+	UpdateSchedule(context.Context, *UpdateScheduleRequest) (*UpdateScheduleResponse, error)
+	DeleteSchedule(context.Context, *DeleteScheduleRequest) (*DeleteScheduleResponse, error)
+	GetSchedule(context.Context, *GetScheduleRequest) (*GetScheduleResponse, error)
+	ListSchedules(context.Context, *ListSchedulesRequest) (*ListSchedulesResponse, error)
+	PauseSchedule(context.Context, *PauseScheduleRequest) (*PauseScheduleResponse, error)
+	ResumeSchedule(context.Context, *ResumeScheduleRequest) (*ResumeScheduleResponse, error)
+	RunScheduleNow(context.Context, *RunScheduleNowRequest) (*RunScheduleNowResponse, error)
+	ListScheduleRuns(context.Context, *ListScheduleRunsRequest) (*ListScheduleRunsResponse, error)
+	// 实例事件流
+	WatchInstance(*WatchInstanceRequest, grpc.ServerStreamingServer[InstanceEvent]) error
+	WatchDef(*WatchDefRequest, grpc.ServerStreamingServer[InstanceEvent]) error
 	mustEmbedUnimplementedWorkflowServiceServer()
 }
 
@@ -238,6 +585,9 @@ type UnimplementedWorkflowServiceServer struct{}
 func (UnimplementedWorkflowServiceServer) CreateDef(context.Context, *CreateDefRequest) (*CreateDefResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateDef not implemented")
 }
+func (UnimplementedWorkflowServiceServer) ValidateDef(context.Context, *ValidateDefRequest) (*ValidateDefResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateDef not implemented")
+}
 func (UnimplementedWorkflowServiceServer) StartWorkflow(context.Context, *StartWorkflowRequest) (*StartWorkflowResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method StartWorkflow not implemented")
 }
@@ -265,6 +615,9 @@ func (UnimplementedWorkflowServiceServer) CreateIfNotExists(context.Context, *Cr
 func (UnimplementedWorkflowServiceServer) GetInstance(context.Context, *GetInstanceRequest) (*GetInstanceResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetInstance not implemented")
 }
+func (UnimplementedWorkflowServiceServer) GetInstanceByBusinessKey(context.Context, *GetInstanceByBusinessKeyRequest) (*GetInstanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInstanceByBusinessKey not implemented")
+}
 func (UnimplementedWorkflowServiceServer) GetInstanceStatus(context.Context, *GetInstanceStatusRequest) (*GetInstanceStatusResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetInstanceStatus not implemented")
 }
@@ -277,6 +630,75 @@ func (UnimplementedWorkflowServiceServer) CancelInstance(context.Context, *Cance
 func (UnimplementedWorkflowServiceServer) RetryNode(context.Context, *RetryNodeRequest) (*RetryNodeResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RetryNode not implemented")
 }
+func (UnimplementedWorkflowServiceServer) CompensateInstance(context.Context, *CompensateInstanceRequest) (*CompensateInstanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompensateInstance not implemented")
+}
+func (UnimplementedWorkflowServiceServer) ForkInstance(context.Context, *ForkInstanceRequest) (*ForkInstanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ForkInstance not implemented")
+}
+func (UnimplementedWorkflowServiceServer) PauseInstance(context.Context, *PauseInstanceRequest) (*PauseInstanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseInstance not implemented")
+}
+func (UnimplementedWorkflowServiceServer) ResumeInstance(context.Context, *ResumeInstanceRequest) (*ResumeInstanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeInstance not implemented")
+}
+func (UnimplementedWorkflowServiceServer) PauseInstancesByDef(context.Context, *PauseInstancesByDefRequest) (*PauseInstancesByDefResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseInstancesByDef not implemented")
+}
+func (UnimplementedWorkflowServiceServer) ResumeInstancesByDef(context.Context, *ResumeInstancesByDefRequest) (*ResumeInstancesByDefResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeInstancesByDef not implemented")
+}
+func (UnimplementedWorkflowServiceServer) MigrateInstances(context.Context, *MigrateInstancesRequest) (*MigrateInstancesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MigrateInstances not implemented")
+}
+func (UnimplementedWorkflowServiceServer) ListInstanceMigrations(context.Context, *ListInstanceMigrationsRequest) (*ListInstanceMigrationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInstanceMigrations not implemented")
+}
+func (UnimplementedWorkflowServiceServer) RenderDef(context.Context, *RenderDefRequest) (*RenderedGraph, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenderDef not implemented")
+}
+func (UnimplementedWorkflowServiceServer) RenderInstance(context.Context, *RenderInstanceRequest) (*RenderedGraph, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenderInstance not implemented")
+}
+func (UnimplementedWorkflowServiceServer) SendSignal(context.Context, *SendSignalRequest) (*SendSignalResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendSignal not implemented")
+}
+func (UnimplementedWorkflowServiceServer) SendSignalByKey(context.Context, *SendSignalByKeyRequest) (*SendSignalByKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendSignalByKey not implemented")
+}
+func (UnimplementedWorkflowServiceServer) CreateSchedule(context.Context, *CreateScheduleRequest) (*CreateScheduleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSchedule not implemented")
+}
+func (UnimplementedWorkflowServiceServer) UpdateSchedule(context.Context, *UpdateScheduleRequest) (*UpdateScheduleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateSchedule not implemented")
+}
+func (UnimplementedWorkflowServiceServer) DeleteSchedule(context.Context, *DeleteScheduleRequest) (*DeleteScheduleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSchedule not implemented")
+}
+func (UnimplementedWorkflowServiceServer) GetSchedule(context.Context, *GetScheduleRequest) (*GetScheduleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSchedule not implemented")
+}
+func (UnimplementedWorkflowServiceServer) ListSchedules(context.Context, *ListSchedulesRequest) (*ListSchedulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSchedules not implemented")
+}
+func (UnimplementedWorkflowServiceServer) PauseSchedule(context.Context, *PauseScheduleRequest) (*PauseScheduleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseSchedule not implemented")
+}
+func (UnimplementedWorkflowServiceServer) ResumeSchedule(context.Context, *ResumeScheduleRequest) (*ResumeScheduleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeSchedule not implemented")
+}
+func (UnimplementedWorkflowServiceServer) RunScheduleNow(context.Context, *RunScheduleNowRequest) (*RunScheduleNowResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RunScheduleNow not implemented")
+}
+func (UnimplementedWorkflowServiceServer) ListScheduleRuns(context.Context, *ListScheduleRunsRequest) (*ListScheduleRunsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListScheduleRuns not implemented")
+}
+func (UnimplementedWorkflowServiceServer) WatchInstance(*WatchInstanceRequest, grpc.ServerStreamingServer[InstanceEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchInstance not implemented")
+}
+func (UnimplementedWorkflowServiceServer) WatchDef(*WatchDefRequest, grpc.ServerStreamingServer[InstanceEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchDef not implemented")
+}
 func (UnimplementedWorkflowServiceServer) mustEmbedUnimplementedWorkflowServiceServer() {}
 func (UnimplementedWorkflowServiceServer) testEmbeddedByValue()                         {}
 
@@ -316,6 +738,24 @@ func _WorkflowService_CreateDef_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WorkflowService_ValidateDef_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateDefRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).ValidateDef(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowService_ValidateDef_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).ValidateDef(ctx, req.(*ValidateDefRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _WorkflowService_StartWorkflow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(StartWorkflowRequest)
 	if err := dec(in); err != nil {
@@ -478,6 +918,24 @@ func _WorkflowService_GetInstance_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WorkflowService_GetInstanceByBusinessKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInstanceByBusinessKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).GetInstanceByBusinessKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowService_GetInstanceByBusinessKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).GetInstanceByBusinessKey(ctx, req.(*GetInstanceByBusinessKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _WorkflowService_GetInstanceStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetInstanceStatusRequest)
 	if err := dec(in); err != nil {
@@ -550,27 +1008,431 @@ func _WorkflowService_RetryNode_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
-// WorkflowService_ServiceDesc is the grpc.ServiceDesc for WorkflowService service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var WorkflowService_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "xiaozhizhang.workflow.v1.WorkflowService",
-	HandlerType: (*WorkflowServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "CreateDef",
-			Handler:    _WorkflowService_CreateDef_Handler,
-		},
-		{
-			MethodName: "StartWorkflow",
-			Handler:    _WorkflowService_StartWorkflow_Handler,
-		},
-		{
-			MethodName: "ReportNodeCompleted",
-			Handler:    _WorkflowService_ReportNodeCompleted_Handler,
-		},
-		{
-			MethodName: "RollbackToNode",
+func _WorkflowService_CompensateInstance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompensateInstanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).CompensateInstance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowService_CompensateInstance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).CompensateInstance(ctx, req.(*CompensateInstanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowService_ForkInstance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForkInstanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).ForkInstance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowService_ForkInstance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).ForkInstance(ctx, req.(*ForkInstanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowService_PauseInstance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseInstanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).PauseInstance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowService_PauseInstance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).PauseInstance(ctx, req.(*PauseInstanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowService_ResumeInstance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeInstanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).ResumeInstance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowService_ResumeInstance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).ResumeInstance(ctx, req.(*ResumeInstanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowService_PauseInstancesByDef_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseInstancesByDefRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).PauseInstancesByDef(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowService_PauseInstancesByDef_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).PauseInstancesByDef(ctx, req.(*PauseInstancesByDefRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowService_ResumeInstancesByDef_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeInstancesByDefRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).ResumeInstancesByDef(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowService_ResumeInstancesByDef_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).ResumeInstancesByDef(ctx, req.(*ResumeInstancesByDefRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowService_MigrateInstances_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MigrateInstancesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).MigrateInstances(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowService_MigrateInstances_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).MigrateInstances(ctx, req.(*MigrateInstancesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowService_ListInstanceMigrations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInstanceMigrationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).ListInstanceMigrations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowService_ListInstanceMigrations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).ListInstanceMigrations(ctx, req.(*ListInstanceMigrationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowService_RenderDef_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenderDefRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).RenderDef(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowService_RenderDef_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).RenderDef(ctx, req.(*RenderDefRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowService_RenderInstance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenderInstanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).RenderInstance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowService_RenderInstance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).RenderInstance(ctx, req.(*RenderInstanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowService_SendSignal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendSignalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).SendSignal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowService_SendSignal_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).SendSignal(ctx, req.(*SendSignalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowService_SendSignalByKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendSignalByKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).SendSignalByKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowService_SendSignalByKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).SendSignalByKey(ctx, req.(*SendSignalByKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowService_CreateSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).CreateSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowService_CreateSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).CreateSchedule(ctx, req.(*CreateScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowService_UpdateSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).UpdateSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowService_UpdateSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).UpdateSchedule(ctx, req.(*UpdateScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowService_DeleteSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).DeleteSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowService_DeleteSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).DeleteSchedule(ctx, req.(*DeleteScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowService_GetSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).GetSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowService_GetSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).GetSchedule(ctx, req.(*GetScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowService_ListSchedules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSchedulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).ListSchedules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowService_ListSchedules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).ListSchedules(ctx, req.(*ListSchedulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowService_PauseSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).PauseSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowService_PauseSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).PauseSchedule(ctx, req.(*PauseScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowService_ResumeSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).ResumeSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowService_ResumeSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).ResumeSchedule(ctx, req.(*ResumeScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowService_RunScheduleNow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RunScheduleNowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).RunScheduleNow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowService_RunScheduleNow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).RunScheduleNow(ctx, req.(*RunScheduleNowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowService_ListScheduleRuns_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListScheduleRunsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).ListScheduleRuns(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowService_ListScheduleRuns_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).ListScheduleRuns(ctx, req.(*ListScheduleRunsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowService_WatchInstance_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchInstanceRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WorkflowServiceServer).WatchInstance(m, &grpc.GenericServerStream[WatchInstanceRequest, InstanceEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WorkflowService_WatchInstanceServer = grpc.ServerStreamingServer[InstanceEvent]
+
+func _WorkflowService_WatchDef_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchDefRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WorkflowServiceServer).WatchDef(m, &grpc.GenericServerStream[WatchDefRequest, InstanceEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WorkflowService_WatchDefServer = grpc.ServerStreamingServer[InstanceEvent]
+
+// WorkflowService_ServiceDesc is the grpc.ServiceDesc for WorkflowService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var WorkflowService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "xiaozhizhang.workflow.v1.WorkflowService",
+	HandlerType: (*WorkflowServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateDef",
+			Handler:    _WorkflowService_CreateDef_Handler,
+		},
+		{
+			MethodName: "ValidateDef",
+			Handler:    _WorkflowService_ValidateDef_Handler,
+		},
+		{
+			MethodName: "StartWorkflow",
+			Handler:    _WorkflowService_StartWorkflow_Handler,
+		},
+		{
+			MethodName: "ReportNodeCompleted",
+			Handler:    _WorkflowService_ReportNodeCompleted_Handler,
+		},
+		{
+			MethodName: "RollbackToNode",
 			Handler:    _WorkflowService_RollbackToNode_Handler,
 		},
 		{
@@ -597,6 +1459,10 @@ var WorkflowService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetInstance",
 			Handler:    _WorkflowService_GetInstance_Handler,
 		},
+		{
+			MethodName: "GetInstanceByBusinessKey",
+			Handler:    _WorkflowService_GetInstanceByBusinessKey_Handler,
+		},
 		{
 			MethodName: "GetInstanceStatus",
 			Handler:    _WorkflowService_GetInstanceStatus_Handler,
@@ -613,7 +1479,102 @@ var WorkflowService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RetryNode",
 			Handler:    _WorkflowService_RetryNode_Handler,
 		},
+		{
+			MethodName: "CompensateInstance",
+			Handler:    _WorkflowService_CompensateInstance_Handler,
+		},
+		{
+			MethodName: "ForkInstance",
+			Handler:    _WorkflowService_ForkInstance_Handler,
+		},
+		{
+			MethodName: "PauseInstance",
+			Handler:    _WorkflowService_PauseInstance_Handler,
+		},
+		{
+			MethodName: "ResumeInstance",
+			Handler:    _WorkflowService_ResumeInstance_Handler,
+		},
+		{
+			MethodName: "PauseInstancesByDef",
+			Handler:    _WorkflowService_PauseInstancesByDef_Handler,
+		},
+		{
+			MethodName: "ResumeInstancesByDef",
+			Handler:    _WorkflowService_ResumeInstancesByDef_Handler,
+		},
+		{
+			MethodName: "MigrateInstances",
+			Handler:    _WorkflowService_MigrateInstances_Handler,
+		},
+		{
+			MethodName: "ListInstanceMigrations",
+			Handler:    _WorkflowService_ListInstanceMigrations_Handler,
+		},
+		{
+			MethodName: "RenderDef",
+			Handler:    _WorkflowService_RenderDef_Handler,
+		},
+		{
+			MethodName: "RenderInstance",
+			Handler:    _WorkflowService_RenderInstance_Handler,
+		},
+		{
+			MethodName: "SendSignal",
+			Handler:    _WorkflowService_SendSignal_Handler,
+		},
+		{
+			MethodName: "SendSignalByKey",
+			Handler:    _WorkflowService_SendSignalByKey_Handler,
+		},
+		{
+			MethodName: "CreateSchedule",
+			Handler:    _WorkflowService_CreateSchedule_Handler,
+		},
+		{
+			MethodName: "UpdateSchedule",
+			Handler:    _WorkflowService_UpdateSchedule_Handler,
+		},
+		{
+			MethodName: "DeleteSchedule",
+			Handler:    _WorkflowService_DeleteSchedule_Handler,
+		},
+		{
+			MethodName: "GetSchedule",
+			Handler:    _WorkflowService_GetSchedule_Handler,
+		},
+		{
+			MethodName: "ListSchedules",
+			Handler:    _WorkflowService_ListSchedules_Handler,
+		},
+		{
+			MethodName: "PauseSchedule",
+			Handler:    _WorkflowService_PauseSchedule_Handler,
+		},
+		{
+			MethodName: "ResumeSchedule",
+			Handler:    _WorkflowService_ResumeSchedule_Handler,
+		},
+		{
+			MethodName: "RunScheduleNow",
+			Handler:    _WorkflowService_RunScheduleNow_Handler,
+		},
+		{
+			MethodName: "ListScheduleRuns",
+			Handler:    _WorkflowService_ListScheduleRuns_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchInstance",
+			Handler:       _WorkflowService_WatchInstance_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchDef",
+			Handler:       _WorkflowService_WatchDef_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "workflow.proto",
 }
@@ -3,12 +3,14 @@ package grpc
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"strings"
 
 	"github.com/xsxdot/aio/base"
 	"github.com/xsxdot/aio/system/workflow/api/client"
 	pb "github.com/xsxdot/aio/system/workflow/api/proto"
 	"github.com/xsxdot/aio/system/workflow/internal/app"
+	errorc "github.com/xsxdot/gokit/err"
 	"github.com/xsxdot/gokit/logger"
 
 	"google.golang.org/grpc"
@@ -71,6 +73,10 @@ func (s *WorkflowService) CreateDef(ctx context.Context, req *pb.CreateDefReques
 
 	defID, err := s.client.CreateDef(ctx, env, req.Code, req.Name, req.DagJson, version)
 	if err != nil {
+		var lintErr *app.DefLintError
+		if errors.As(err, &lintErr) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
 		s.log.WithErr(err).Error("创建工作流定义失败")
 		return nil, status.Error(codes.Internal, err.Error())
 	}
@@ -80,6 +86,32 @@ func (s *WorkflowService) CreateDef(ctx context.Context, req *pb.CreateDefReques
 	}, nil
 }
 
+// ValidateDef 校验 DAG 而不创建定义
+func (s *WorkflowService) ValidateDef(ctx context.Context, req *pb.ValidateDefRequest) (*pb.ValidateDefResponse, error) {
+	if strings.TrimSpace(req.DagJson) == "" {
+		return nil, status.Error(codes.InvalidArgument, "dag_json 不能为空")
+	}
+	res := s.client.ValidateDef(req.DagJson)
+	issues := make([]*pb.DefLintIssue, 0, len(res.Issues))
+	for _, issue := range res.Issues {
+		item := &pb.DefLintIssue{
+			Severity:  issue.Severity,
+			Code:      issue.Code,
+			NodeId:    issue.NodeID,
+			EdgeIndex: -1,
+			Field:     issue.Field,
+			Message:   issue.Message,
+		}
+		if issue.Edge != nil {
+			item.EdgeIndex = int32(issue.Edge.Index)
+			item.EdgeFrom = issue.Edge.From
+			item.EdgeTo = issue.Edge.To
+		}
+		issues = append(issues, item)
+	}
+	return &pb.ValidateDefResponse{Valid: res.Valid, Issues: issues}, nil
+}
+
 // StartWorkflow 启动工作流
 func (s *WorkflowService) StartWorkflow(ctx context.Context, req *pb.StartWorkflowRequest) (*pb.StartWorkflowResponse, error) {
 	if strings.TrimSpace(req.DefCode) == "" {
@@ -103,14 +135,18 @@ func (s *WorkflowService) StartWorkflow(ctx context.Context, req *pb.StartWorkfl
 	if strings.TrimSpace(env) == "" {
 		env = base.ENV
 	}
-	instanceID, err := s.client.StartWorkflow(ctx, req.DefCode, initialData, env)
+	instanceID, existed, err := s.client.StartWorkflowWithBusinessKey(ctx, req.DefCode, req.BusinessKey, initialData, env)
 	if err != nil {
-		s.log.WithErr(err).Error("启动工作流失败")
-		return nil, status.Error(codes.Internal, err.Error())
+		var schemaErr *app.SchemaValidationError
+		if errors.As(err, &schemaErr) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, s.statusError(err, "启动工作流失败")
 	}
 
 	return &pb.StartWorkflowResponse{
 		InstanceId: instanceID,
+		Existed:    existed,
 	}, nil
 }
 
@@ -218,6 +254,7 @@ func (s *WorkflowService) GetExecutionTrail(ctx context.Context, req *pb.GetExec
 			}
 		}
 		checkpoints[i] = &pb.ExecutionTrailCheckpoint{
+			CheckpointId:   cp.CheckpointID,
 			NodeId:         cp.NodeID,
 			NodeOutputJson: nodeOutputJSON,
 			StateAfterJson: stateAfterJSON,
@@ -225,12 +262,25 @@ func (s *WorkflowService) GetExecutionTrail(ctx context.Context, req *pb.GetExec
 		}
 	}
 
+	children := make([]*pb.ExecutionTrailChild, len(trail.Children))
+	for i, c := range trail.Children {
+		children[i] = &pb.ExecutionTrailChild{
+			InstanceId: c.InstanceID,
+			NodeId:     c.NodeID,
+			Status:     c.Status,
+			CreatedAt:  c.CreatedAt,
+		}
+	}
+
 	return &pb.GetExecutionTrailResponse{
-		InstanceId:    trail.InstanceID,
-		Status:        trail.Status,
-		CurrentState:  trail.CurrentState,
-		ActiveNodeIds: trail.ActiveNodeIDs,
-		Checkpoints:   checkpoints,
+		InstanceId:       trail.InstanceID,
+		Status:           trail.Status,
+		CurrentState:     trail.CurrentState,
+		ActiveNodeIds:    trail.ActiveNodeIDs,
+		Checkpoints:      checkpoints,
+		ParentInstanceId: trail.ParentInstanceID,
+		ParentNodeId:     trail.ParentNodeID,
+		Children:         children,
 	}, nil
 }
 
@@ -275,12 +325,14 @@ func (s *WorkflowService) GetDef(ctx context.Context, req *pb.GetDefRequest) (*p
 		return &pb.GetDefResponse{NotFound: true}, nil
 	}
 	return &pb.GetDefResponse{
-		DefId:   def.ID,
-		Code:    def.Code,
-		Version: def.Version,
-		Name:    def.Name,
-		DagJson: def.DAGJSON,
-		Env:     def.Env,
+		DefId:       def.ID,
+		Code:        def.Code,
+		Version:     def.Version,
+		Name:        def.Name,
+		DagJson:     def.DAGJSON,
+		Env:         def.Env,
+		InputSchema: def.InputSchema,
+		StateSchema: def.StateSchema,
 	}, nil
 }
 
@@ -305,12 +357,14 @@ func (s *WorkflowService) ListDefs(ctx context.Context, req *pb.ListDefsRequest)
 	pbItems := make([]*pb.GetDefResponse, len(items))
 	for i, d := range items {
 		pbItems[i] = &pb.GetDefResponse{
-			DefId:   d.ID,
-			Code:    d.Code,
-			Version: d.Version,
-			Name:    d.Name,
-			DagJson: d.DAGJSON,
-			Env:     d.Env,
+			DefId:       d.ID,
+			Code:        d.Code,
+			Version:     d.Version,
+			Name:        d.Name,
+			DagJson:     d.DAGJSON,
+			Env:         d.Env,
+			InputSchema: d.InputSchema,
+			StateSchema: d.StateSchema,
 		}
 	}
 	return &pb.ListDefsResponse{Items: pbItems, Total: total}, nil
@@ -361,19 +415,46 @@ func (s *WorkflowService) GetInstance(ctx context.Context, req *pb.GetInstanceRe
 		createdAt = inst.CreatedAt.Format("2006-01-02 15:04:05")
 	}
 	return &pb.GetInstanceResponse{
-		InstanceId:    inst.ID,
-		DefId:         inst.DefID,
-		DefCode:       defCode,
-		DefVersion:    inst.DefVersion,
-		Env:           inst.Env,
-		Status:        string(inst.Status),
-		InitialState:  inst.InitialState,
-		CurrentState:  inst.CurrentState,
-		ActiveNodeIds: inst.ActiveNodeIDs,
-		CreatedAt:     createdAt,
+		InstanceId:             inst.ID,
+		DefId:                  inst.DefID,
+		DefCode:                defCode,
+		DefVersion:             inst.DefVersion,
+		Env:                    inst.Env,
+		Status:                 string(inst.Status),
+		InitialState:           inst.InitialState,
+		CurrentState:           inst.CurrentState,
+		ActiveNodeIds:          inst.ActiveNodeIDs,
+		CreatedAt:              createdAt,
+		ParentInstanceId:       inst.ParentInstanceID,
+		ParentNodeId:           inst.ParentNodeID,
+		ForkedFromInstanceId:   inst.ForkedFromInstanceID,
+		ForkedFromCheckpointId: inst.ForkedFromCheckpointID,
+		BusinessKey:            inst.BusinessKey,
 	}, nil
 }
 
+// GetInstanceByBusinessKey 按业务键获取最近一个实例，不存在时返回 not_found
+func (s *WorkflowService) GetInstanceByBusinessKey(ctx context.Context, req *pb.GetInstanceByBusinessKeyRequest) (*pb.GetInstanceResponse, error) {
+	if strings.TrimSpace(req.DefCode) == "" {
+		return nil, status.Error(codes.InvalidArgument, "def_code 不能为空")
+	}
+	if strings.TrimSpace(req.BusinessKey) == "" {
+		return nil, status.Error(codes.InvalidArgument, "business_key 不能为空")
+	}
+	env := req.Env
+	if strings.TrimSpace(env) == "" {
+		env = base.ENV
+	}
+	inst, err := s.client.GetInstanceByBusinessKey(ctx, env, req.DefCode, req.BusinessKey)
+	if err != nil {
+		if errorc.IsNotFound(err) {
+			return &pb.GetInstanceResponse{NotFound: true}, nil
+		}
+		return nil, s.statusError(err, "按业务键获取实例失败")
+	}
+	return s.GetInstance(ctx, &pb.GetInstanceRequest{InstanceId: inst.ID})
+}
+
 // GetInstanceStatus 轻量查询实例状态
 func (s *WorkflowService) GetInstanceStatus(ctx context.Context, req *pb.GetInstanceStatusRequest) (*pb.GetInstanceStatusResponse, error) {
 	if req.InstanceId <= 0 {
@@ -402,6 +483,7 @@ func (s *WorkflowService) ListInstances(ctx context.Context, req *pb.ListInstanc
 	filter := &app.ListInstancesFilter{
 		DefCode:       req.DefCode,
 		Status:        req.Status,
+		BusinessKey:   req.BusinessKey,
 		CreatedAfter:  req.CreatedAfter,
 		CreatedBefore: req.CreatedBefore,
 	}
@@ -425,6 +507,7 @@ func (s *WorkflowService) ListInstances(ctx context.Context, req *pb.ListInstanc
 			Status:        string(inst.Status),
 			ActiveNodeIds: inst.ActiveNodeIDs,
 			CreatedAt:     createdAt,
+			BusinessKey:   inst.BusinessKey,
 		}
 	}
 	return &pb.ListInstancesResponse{Items: pbItems, Total: total}, nil
@@ -458,3 +541,522 @@ func (s *WorkflowService) RetryNode(ctx context.Context, req *pb.RetryNodeReques
 	}
 	return &pb.RetryNodeResponse{Success: true, Message: "重试成功"}, nil
 }
+
+// CompensateInstance 触发实例补偿
+func (s *WorkflowService) CompensateInstance(ctx context.Context, req *pb.CompensateInstanceRequest) (*pb.CompensateInstanceResponse, error) {
+	if req.InstanceId <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "instance_id 不能为空")
+	}
+	err := s.client.CompensateInstance(ctx, req.InstanceId)
+	if err != nil {
+		s.log.WithErr(err).Error("触发补偿失败")
+		return &pb.CompensateInstanceResponse{Success: false, Message: err.Error()}, nil
+	}
+	return &pb.CompensateInstanceResponse{Success: true, Message: "已开始补偿"}, nil
+}
+
+// ForkInstance 从检查点分叉新实例
+func (s *WorkflowService) ForkInstance(ctx context.Context, req *pb.ForkInstanceRequest) (*pb.ForkInstanceResponse, error) {
+	if req.InstanceId <= 0 || req.CheckpointId <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "instance_id 与 checkpoint_id 不能为空")
+	}
+	var patch map[string]interface{}
+	if strings.TrimSpace(req.PatchJson) != "" {
+		if err := json.Unmarshal([]byte(req.PatchJson), &patch); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "patch_json 必须是 JSON 对象")
+		}
+	}
+	instanceID, err := s.client.ForkInstance(ctx, req.InstanceId, req.CheckpointId, patch, req.Env)
+	if err != nil {
+		s.log.WithErr(err).Error("分叉实例失败")
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return &pb.ForkInstanceResponse{InstanceId: instanceID}, nil
+}
+
+// PauseInstance 暂停实例
+func (s *WorkflowService) PauseInstance(ctx context.Context, req *pb.PauseInstanceRequest) (*pb.PauseInstanceResponse, error) {
+	if req.InstanceId <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "instance_id 不能为空")
+	}
+	if err := s.client.PauseInstance(ctx, req.InstanceId); err != nil {
+		s.log.WithErr(err).Error("暂停实例失败")
+		return &pb.PauseInstanceResponse{Success: false, Message: err.Error()}, nil
+	}
+	return &pb.PauseInstanceResponse{Success: true, Message: "暂停成功"}, nil
+}
+
+// ResumeInstance 恢复实例
+func (s *WorkflowService) ResumeInstance(ctx context.Context, req *pb.ResumeInstanceRequest) (*pb.ResumeInstanceResponse, error) {
+	if req.InstanceId <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "instance_id 不能为空")
+	}
+	if err := s.client.ResumeInstance(ctx, req.InstanceId); err != nil {
+		s.log.WithErr(err).Error("恢复实例失败")
+		return &pb.ResumeInstanceResponse{Success: false, Message: err.Error()}, nil
+	}
+	return &pb.ResumeInstanceResponse{Success: true, Message: "恢复成功"}, nil
+}
+
+// PauseInstancesByDef 按定义编码批量暂停实例
+func (s *WorkflowService) PauseInstancesByDef(ctx context.Context, req *pb.PauseInstancesByDefRequest) (*pb.PauseInstancesByDefResponse, error) {
+	if strings.TrimSpace(req.DefCode) == "" {
+		return nil, status.Error(codes.InvalidArgument, "def_code 不能为空")
+	}
+	affected, err := s.client.PauseInstancesByDef(ctx, req.DefCode, req.Env)
+	if err != nil {
+		s.log.WithErr(err).Error("批量暂停实例失败")
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.PauseInstancesByDefResponse{Affected: affected}, nil
+}
+
+// ResumeInstancesByDef 按定义编码批量恢复实例
+func (s *WorkflowService) ResumeInstancesByDef(ctx context.Context, req *pb.ResumeInstancesByDefRequest) (*pb.ResumeInstancesByDefResponse, error) {
+	if strings.TrimSpace(req.DefCode) == "" {
+		return nil, status.Error(codes.InvalidArgument, "def_code 不能为空")
+	}
+	affected, err := s.client.ResumeInstancesByDef(ctx, req.DefCode, req.Env)
+	if err != nil {
+		s.log.WithErr(err).Error("批量恢复实例失败")
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.ResumeInstancesByDefResponse{Affected: affected}, nil
+}
+
+// MigrateInstances 把实例迁移到目标定义版本
+func (s *WorkflowService) MigrateInstances(ctx context.Context, req *pb.MigrateInstancesRequest) (*pb.MigrateInstancesResponse, error) {
+	if strings.TrimSpace(req.DefCode) == "" {
+		return nil, status.Error(codes.InvalidArgument, "def_code 不能为空")
+	}
+	report, err := s.client.MigrateInstances(ctx, &app.MigrateInstancesInput{
+		DefCode:       req.DefCode,
+		Env:           req.Env,
+		TargetVersion: req.TargetVersion,
+		InstanceIDs:   req.InstanceIds,
+		FromVersion:   req.FromVersion,
+		NodeMapping:   req.NodeMapping,
+		DryRun:        req.DryRun,
+	})
+	if err != nil {
+		s.log.WithErr(err).Error("迁移实例失败")
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	results := make([]*pb.InstanceMigrationResult, len(report.Results))
+	for i, r := range report.Results {
+		results[i] = &pb.InstanceMigrationResult{
+			InstanceId:    r.InstanceID,
+			FromVersion:   r.FromVersion,
+			Migrated:      r.Migrated,
+			Reason:        r.Reason,
+			ActiveNodeIds: r.ActiveNodeIDs,
+		}
+	}
+	return &pb.MigrateInstancesResponse{
+		TargetDefId:   report.TargetDefID,
+		TargetVersion: report.TargetVersion,
+		DryRun:        report.DryRun,
+		Migrated:      int32(report.Migrated),
+		Rejected:      int32(report.Rejected),
+		Results:       results,
+	}, nil
+}
+
+// ListInstanceMigrations 列出实例的迁移审计记录
+func (s *WorkflowService) ListInstanceMigrations(ctx context.Context, req *pb.ListInstanceMigrationsRequest) (*pb.ListInstanceMigrationsResponse, error) {
+	if req.InstanceId <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "instance_id 不能为空")
+	}
+	items, err := s.client.ListInstanceMigrations(ctx, req.InstanceId)
+	if err != nil {
+		s.log.WithErr(err).Error("列出实例迁移记录失败")
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	pbItems := make([]*pb.InstanceMigrationRecord, len(items))
+	for i, m := range items {
+		createdAt := ""
+		if !m.CreatedAt.IsZero() {
+			createdAt = m.CreatedAt.Format("2006-01-02 15:04:05")
+		}
+		pbItems[i] = &pb.InstanceMigrationRecord{
+			Id:               m.ID,
+			FromVersion:      m.FromVersion,
+			ToVersion:        m.ToVersion,
+			NodeMappingJson:  m.NodeMapping,
+			ActiveBeforeJson: m.ActiveBefore,
+			ActiveAfterJson:  m.ActiveAfter,
+			CreatedAt:        createdAt,
+		}
+	}
+	return &pb.ListInstanceMigrationsResponse{Items: pbItems}, nil
+}
+
+// RenderDef 把定义的 DAG 渲染为 Mermaid 或 DOT 文本
+func (s *WorkflowService) RenderDef(ctx context.Context, req *pb.RenderDefRequest) (*pb.RenderedGraph, error) {
+	if strings.TrimSpace(req.Code) == "" {
+		return nil, status.Error(codes.InvalidArgument, "code 不能为空")
+	}
+	env := req.Env
+	if strings.TrimSpace(env) == "" {
+		env = base.ENV
+	}
+	g, err := s.client.RenderDef(ctx, env, req.Code, req.Version, req.Format)
+	if err != nil {
+		return nil, s.statusError(err, "渲染工作流定义失败")
+	}
+	return &pb.RenderedGraph{Format: g.Format, Content: g.Content}, nil
+}
+
+// RenderInstance 渲染实例的 DAG 并按执行进度给节点着色
+func (s *WorkflowService) RenderInstance(ctx context.Context, req *pb.RenderInstanceRequest) (*pb.RenderedGraph, error) {
+	if req.InstanceId <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "instance_id 不能为空")
+	}
+	g, err := s.client.RenderInstance(ctx, req.InstanceId, req.Format)
+	if err != nil {
+		return nil, s.statusError(err, "渲染工作流实例失败")
+	}
+	return &pb.RenderedGraph{Format: g.Format, Content: g.Content, NodeStatus: g.NodeStatus}, nil
+}
+
+// SendSignal 向实例发送信号，payload 合并入状态；实例有 wait_signal 节点在等待该信号时由其接收并推进
+func (s *WorkflowService) SendSignal(ctx context.Context, req *pb.SendSignalRequest) (*pb.SendSignalResponse, error) {
+	if req.InstanceId <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "instance_id 不能为空")
+	}
+	if strings.TrimSpace(req.SignalName) == "" {
+		return nil, status.Error(codes.InvalidArgument, "signal_name 不能为空")
+	}
+	payload, err := parseSignalPayload(req.PayloadJson)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	// env 为空时由引擎使用实例所在环境
+	if err := s.client.SendSignal(ctx, req.InstanceId, req.SignalName, payload, req.WakeupNode, req.Env); err != nil {
+		return nil, s.statusError(err, "发送信号失败")
+	}
+	return &pb.SendSignalResponse{Success: true, Message: "信号已发送"}, nil
+}
+
+// SendSignalByKey 按关联键投递信号，调用方无需知道实例 ID
+func (s *WorkflowService) SendSignalByKey(ctx context.Context, req *pb.SendSignalByKeyRequest) (*pb.SendSignalByKeyResponse, error) {
+	if strings.TrimSpace(req.SignalName) == "" {
+		return nil, status.Error(codes.InvalidArgument, "signal_name 不能为空")
+	}
+	if strings.TrimSpace(req.CorrelationKey) == "" {
+		return nil, status.Error(codes.InvalidArgument, "correlation_key 不能为空")
+	}
+	payload, err := parseSignalPayload(req.PayloadJson)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	env := req.Env
+	if strings.TrimSpace(env) == "" {
+		env = base.ENV
+	}
+	res, err := s.client.SendSignalByKey(ctx, env, req.SignalName, req.CorrelationKey, payload)
+	if err != nil {
+		return nil, s.statusError(err, "按关联键投递信号失败")
+	}
+	return &pb.SendSignalByKeyResponse{Delivered: int32(res.Delivered), InstanceIds: res.InstanceIDs}, nil
+}
+
+// parseSignalPayload 解析信号 payload，空串视为空对象
+func parseSignalPayload(raw string) (map[string]interface{}, error) {
+	payload := make(map[string]interface{})
+	if raw == "" {
+		return payload, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &payload); err != nil {
+		return nil, errors.New("payload_json 必须是 JSON 对象")
+	}
+	if payload == nil {
+		payload = make(map[string]interface{})
+	}
+	return payload, nil
+}
+
+// statusError 把应用层错误转换为 gRPC 状态：参数校验失败为 InvalidArgument，定义或实例不存在为 NotFound
+func (s *WorkflowService) statusError(err error, msg string) error {
+	var e *errorc.Error
+	if errors.As(err, &e) && e.ErrorCode == errorc.ErrorCodeValid {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errorc.IsNotFound(err) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	s.log.WithErr(err).Error(msg)
+	return status.Error(codes.Internal, err.Error())
+}
+
+// scheduleInputFromPB 把 gRPC 调度配置转换为应用层参数
+func scheduleInputFromPB(spec *pb.ScheduleSpec) *app.ScheduleInput {
+	env := spec.GetEnv()
+	if strings.TrimSpace(env) == "" {
+		env = base.ENV
+	}
+	return &app.ScheduleInput{
+		Env:                 env,
+		Name:                spec.GetName(),
+		DefCode:             spec.GetDefCode(),
+		DefVersion:          spec.GetDefVersion(),
+		CronExpr:            spec.GetCronExpr(),
+		Timezone:            spec.GetTimezone(),
+		InitialDataTemplate: spec.GetInitialDataTemplate(),
+		OverlapPolicy:       spec.GetOverlapPolicy(),
+	}
+}
+
+// scheduleToPB 把调度模型转换为 gRPC 消息
+func scheduleToPB(s *app.WorkflowScheduleModel) *pb.ScheduleInfo {
+	info := &pb.ScheduleInfo{
+		ScheduleId: s.ID,
+		Spec: &pb.ScheduleSpec{
+			Env:                 s.Env,
+			Name:                s.Name,
+			DefCode:             s.DefCode,
+			DefVersion:          s.DefVersion,
+			CronExpr:            s.CronExpr,
+			Timezone:            s.Timezone,
+			InitialDataTemplate: s.InitialDataTemplate,
+			OverlapPolicy:       string(s.OverlapPolicy),
+		},
+		Status: string(s.Status),
+	}
+	if s.NextFireAt != nil {
+		info.NextFireAt = s.NextFireAt.Format("2006-01-02 15:04:05")
+	}
+	if s.LastFireAt != nil {
+		info.LastFireAt = s.LastFireAt.Format("2006-01-02 15:04:05")
+	}
+	if !s.CreatedAt.IsZero() {
+		info.CreatedAt = s.CreatedAt.Format("2006-01-02 15:04:05")
+	}
+	return info
+}
+
+// validateScheduleSpec 校验调度配置必填项
+func validateScheduleSpec(spec *pb.ScheduleSpec) error {
+	if spec == nil {
+		return status.Error(codes.InvalidArgument, "spec 不能为空")
+	}
+	if strings.TrimSpace(spec.Name) == "" {
+		return status.Error(codes.InvalidArgument, "name 不能为空")
+	}
+	if strings.TrimSpace(spec.DefCode) == "" {
+		return status.Error(codes.InvalidArgument, "def_code 不能为空")
+	}
+	if strings.TrimSpace(spec.CronExpr) == "" {
+		return status.Error(codes.InvalidArgument, "cron_expr 不能为空")
+	}
+	return nil
+}
+
+// CreateSchedule 创建定时调度
+func (s *WorkflowService) CreateSchedule(ctx context.Context, req *pb.CreateScheduleRequest) (*pb.CreateScheduleResponse, error) {
+	if err := validateScheduleSpec(req.Spec); err != nil {
+		return nil, err
+	}
+	sched, err := s.client.CreateSchedule(ctx, scheduleInputFromPB(req.Spec))
+	if err != nil {
+		s.log.WithErr(err).Error("创建定时调度失败")
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.CreateScheduleResponse{Schedule: scheduleToPB(sched)}, nil
+}
+
+// UpdateSchedule 整体更新定时调度
+func (s *WorkflowService) UpdateSchedule(ctx context.Context, req *pb.UpdateScheduleRequest) (*pb.UpdateScheduleResponse, error) {
+	if req.ScheduleId <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "schedule_id 不能为空")
+	}
+	if err := validateScheduleSpec(req.Spec); err != nil {
+		return nil, err
+	}
+	sched, err := s.client.UpdateSchedule(ctx, req.ScheduleId, scheduleInputFromPB(req.Spec))
+	if err != nil {
+		s.log.WithErr(err).Error("更新定时调度失败")
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.UpdateScheduleResponse{Schedule: scheduleToPB(sched)}, nil
+}
+
+// DeleteSchedule 删除定时调度
+func (s *WorkflowService) DeleteSchedule(ctx context.Context, req *pb.DeleteScheduleRequest) (*pb.DeleteScheduleResponse, error) {
+	if req.ScheduleId <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "schedule_id 不能为空")
+	}
+	if err := s.client.DeleteSchedule(ctx, req.ScheduleId); err != nil {
+		s.log.WithErr(err).Error("删除定时调度失败")
+		return &pb.DeleteScheduleResponse{Success: false, Message: err.Error()}, nil
+	}
+	return &pb.DeleteScheduleResponse{Success: true, Message: "删除成功"}, nil
+}
+
+// GetSchedule 获取定时调度
+func (s *WorkflowService) GetSchedule(ctx context.Context, req *pb.GetScheduleRequest) (*pb.GetScheduleResponse, error) {
+	if req.ScheduleId <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "schedule_id 不能为空")
+	}
+	sched, err := s.client.GetSchedule(ctx, req.ScheduleId)
+	if err != nil {
+		if errorc.IsNotFound(err) {
+			return &pb.GetScheduleResponse{NotFound: true}, nil
+		}
+		s.log.WithErr(err).Error("获取定时调度失败")
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.GetScheduleResponse{Schedule: scheduleToPB(sched)}, nil
+}
+
+// ListSchedules 分页列出定时调度
+func (s *WorkflowService) ListSchedules(ctx context.Context, req *pb.ListSchedulesRequest) (*pb.ListSchedulesResponse, error) {
+	env := req.Env
+	if strings.TrimSpace(env) == "" {
+		env = base.ENV
+	}
+	pageNum, pageSize := req.PageNum, req.PageSize
+	if pageNum <= 0 {
+		pageNum = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	items, total, err := s.client.ListSchedules(ctx, env, req.DefCode, pageNum, pageSize)
+	if err != nil {
+		s.log.WithErr(err).Error("列出定时调度失败")
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	pbItems := make([]*pb.ScheduleInfo, len(items))
+	for i, item := range items {
+		pbItems[i] = scheduleToPB(item)
+	}
+	return &pb.ListSchedulesResponse{Items: pbItems, Total: total}, nil
+}
+
+// PauseSchedule 暂停定时调度
+func (s *WorkflowService) PauseSchedule(ctx context.Context, req *pb.PauseScheduleRequest) (*pb.PauseScheduleResponse, error) {
+	if req.ScheduleId <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "schedule_id 不能为空")
+	}
+	if err := s.client.PauseSchedule(ctx, req.ScheduleId); err != nil {
+		s.log.WithErr(err).Error("暂停定时调度失败")
+		return &pb.PauseScheduleResponse{Success: false, Message: err.Error()}, nil
+	}
+	return &pb.PauseScheduleResponse{Success: true, Message: "调度已暂停"}, nil
+}
+
+// ResumeSchedule 恢复定时调度
+func (s *WorkflowService) ResumeSchedule(ctx context.Context, req *pb.ResumeScheduleRequest) (*pb.ResumeScheduleResponse, error) {
+	if req.ScheduleId <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "schedule_id 不能为空")
+	}
+	if err := s.client.ResumeSchedule(ctx, req.ScheduleId); err != nil {
+		s.log.WithErr(err).Error("恢复定时调度失败")
+		return &pb.ResumeScheduleResponse{Success: false, Message: err.Error()}, nil
+	}
+	return &pb.ResumeScheduleResponse{Success: true, Message: "调度已恢复"}, nil
+}
+
+// RunScheduleNow 立即按调度配置拉起一个实例
+func (s *WorkflowService) RunScheduleNow(ctx context.Context, req *pb.RunScheduleNowRequest) (*pb.RunScheduleNowResponse, error) {
+	if req.ScheduleId <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "schedule_id 不能为空")
+	}
+	instanceID, err := s.client.RunScheduleNow(ctx, req.ScheduleId)
+	if err != nil {
+		s.log.WithErr(err).Error("立即运行定时调度失败")
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &pb.RunScheduleNowResponse{InstanceId: instanceID}, nil
+}
+
+// ListScheduleRuns 分页列出调度的运行历史
+func (s *WorkflowService) ListScheduleRuns(ctx context.Context, req *pb.ListScheduleRunsRequest) (*pb.ListScheduleRunsResponse, error) {
+	if req.ScheduleId <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "schedule_id 不能为空")
+	}
+	pageNum, pageSize := req.PageNum, req.PageSize
+	if pageNum <= 0 {
+		pageNum = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	items, total, err := s.client.ListScheduleRuns(ctx, req.ScheduleId, pageNum, pageSize)
+	if err != nil {
+		s.log.WithErr(err).Error("列出调度运行历史失败")
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	pbItems := make([]*pb.ScheduleRunInfo, len(items))
+	for i, r := range items {
+		createdAt := ""
+		if !r.CreatedAt.IsZero() {
+			createdAt = r.CreatedAt.Format("2006-01-02 15:04:05")
+		}
+		pbItems[i] = &pb.ScheduleRunInfo{
+			RunId:          r.ID,
+			ScheduleId:     r.ScheduleID,
+			Trigger:        string(r.Trigger),
+			Status:         string(r.Status),
+			ScheduledAt:    r.ScheduledAt.Format("2006-01-02 15:04:05"),
+			InstanceId:     r.InstanceID,
+			InstanceStatus: r.InstanceStatus,
+			Error:          r.Error,
+			CreatedAt:      createdAt,
+		}
+	}
+	return &pb.ListScheduleRunsResponse{Items: pbItems, Total: total}, nil
+}
+
+// WatchInstance 推送单个实例的事件流，实例进入终态且事件推送完毕后结束
+func (s *WorkflowService) WatchInstance(req *pb.WatchInstanceRequest, stream grpc.ServerStreamingServer[pb.InstanceEvent]) error {
+	if req.InstanceId <= 0 {
+		return status.Error(codes.InvalidArgument, "instance_id 不能为空")
+	}
+	err := s.client.WatchInstance(stream.Context(), req.InstanceId, req.AfterSeq, func(e *app.WorkflowInstanceEventModel) error {
+		return stream.Send(instanceEventToPB(e))
+	})
+	return s.watchError(stream.Context(), err, "订阅实例事件失败")
+}
+
+// WatchDef 推送某定义编码下所有实例的事件流，直到客户端断开
+func (s *WorkflowService) WatchDef(req *pb.WatchDefRequest, stream grpc.ServerStreamingServer[pb.InstanceEvent]) error {
+	if strings.TrimSpace(req.DefCode) == "" {
+		return status.Error(codes.InvalidArgument, "def_code 不能为空")
+	}
+	env := req.Env
+	if strings.TrimSpace(env) == "" {
+		env = base.ENV
+	}
+	err := s.client.WatchDef(stream.Context(), env, req.DefCode, req.AfterEventId, func(e *app.WorkflowInstanceEventModel) error {
+		return stream.Send(instanceEventToPB(e))
+	})
+	return s.watchError(stream.Context(), err, "订阅定义事件失败")
+}
+
+// watchError 把订阅结束原因转换为 gRPC 状态：客户端主动断开不算错误
+func (s *WorkflowService) watchError(ctx context.Context, err error, msg string) error {
+	if err == nil || ctx.Err() != nil {
+		return nil
+	}
+	if errorc.IsNotFound(err) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	s.log.WithErr(err).Error(msg)
+	return status.Error(codes.Internal, err.Error())
+}
+
+func instanceEventToPB(e *app.WorkflowInstanceEventModel) *pb.InstanceEvent {
+	return &pb.InstanceEvent{
+		EventId:     e.ID,
+		InstanceId:  e.InstanceID,
+		Seq:         e.Seq,
+		DefId:       e.DefID,
+		Type:        string(e.Type),
+		NodeId:      e.NodeID,
+		Status:      e.Status,
+		PayloadJson: e.Payload,
+		CreatedAt:   e.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
@@ -1,7 +1,12 @@
 package controller
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/xsxdot/aio/base"
 	"github.com/xsxdot/aio/system/workflow/api/dto"
@@ -9,9 +14,11 @@ import (
 	errorc "github.com/xsxdot/gokit/err"
 	"github.com/xsxdot/gokit/logger"
 	"github.com/xsxdot/gokit/result"
+	"github.com/xsxdot/gokit/security"
 	"github.com/xsxdot/gokit/utils"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
 )
 
 type WorkflowAdminController struct {
@@ -32,11 +39,58 @@ func (ctrl *WorkflowAdminController) RegisterRoutes(admin fiber.Router) {
 	router := admin.Group("/workflow")
 
 	router.Post("/defs", base.AdminAuth.RequireAdminAuth("admin:workflow:create"), ctrl.CreateDef)
+	router.Post("/defs/validate", base.AdminAuth.RequireAdminAuth("admin:workflow:read"), ctrl.ValidateDef)
+	router.Post("/simulate", base.AdminAuth.RequireAdminAuth("admin:workflow:read"), ctrl.Simulate)
 	router.Post("/instances/:id/rollback", base.AdminAuth.RequireAdminAuth("admin:workflow:update"), ctrl.Rollback)
 	router.Post("/instances/:id/signal", base.AdminAuth.RequireAdminAuth("admin:workflow:update"), ctrl.SendSignal)
+	router.Post("/signals", base.AdminAuth.RequireAdminAuth("admin:workflow:update"), ctrl.SendSignalByKey)
+	router.Post("/instances/:id/fork", base.AdminAuth.RequireAdminAuth("admin:workflow:create"), ctrl.ForkInstance)
+	router.Post("/instances/:id/compensate", base.AdminAuth.RequireAdminAuth("admin:workflow:update"), ctrl.Compensate)
+	router.Post("/instances/:id/pause", base.AdminAuth.RequireAdminAuth("admin:workflow:update"), ctrl.PauseInstance)
+	router.Post("/instances/:id/resume", base.AdminAuth.RequireAdminAuth("admin:workflow:update"), ctrl.ResumeInstance)
+	router.Post("/defs/:code/pause", base.AdminAuth.RequireAdminAuth("admin:workflow:update"), ctrl.PauseInstancesByDef)
+	router.Post("/defs/:code/resume", base.AdminAuth.RequireAdminAuth("admin:workflow:update"), ctrl.ResumeInstancesByDef)
+	router.Post("/defs/:code/migrate", base.AdminAuth.RequireAdminAuth("admin:workflow:update"), ctrl.MigrateInstances)
+	router.Get("/instances/:id/migrations", base.AdminAuth.RequireAdminAuth("admin:workflow:read"), ctrl.ListInstanceMigrations)
+	router.Get("/instances", base.AdminAuth.RequireAdminAuth("admin:workflow:read"), ctrl.ListInstances)
+	router.Get("/instances/by-business-key", base.AdminAuth.RequireAdminAuth("admin:workflow:read"), ctrl.GetInstanceByBusinessKey)
 	router.Get("/instances/:id", base.AdminAuth.RequireAdminAuth("admin:workflow:read"), ctrl.GetInstance)
 	router.Get("/instances/:id/trail", base.AdminAuth.RequireAdminAuth("admin:workflow:read"), ctrl.GetExecutionTrail)
 	router.Get("/instances/:id/state", base.AdminAuth.RequireAdminAuth("admin:workflow:read"), ctrl.GetExecutionState)
+	router.Get("/instances/:id/events", base.AdminAuth.RequireAdminAuth("admin:workflow:read"), ctrl.WatchInstanceEvents)
+	router.Get("/defs/:code/events", base.AdminAuth.RequireAdminAuth("admin:workflow:read"), ctrl.WatchDefEvents)
+	router.Get("/defs/:code/graph", base.AdminAuth.RequireAdminAuth("admin:workflow:read"), ctrl.RenderDef)
+	router.Get("/instances/:id/graph", base.AdminAuth.RequireAdminAuth("admin:workflow:read"), ctrl.RenderInstance)
+	router.Get("/instances/:id/node-runs", base.AdminAuth.RequireAdminAuth("admin:workflow:read"), ctrl.ListNodeRuns)
+	router.Get("/defs/:code/analytics/nodes", base.AdminAuth.RequireAdminAuth("admin:workflow:read"), ctrl.NodeAnalytics)
+	router.Get("/defs/:code/analytics/instances", base.AdminAuth.RequireAdminAuth("admin:workflow:read"), ctrl.InstanceStatusAnalytics)
+
+	router.Post("/schedules", base.AdminAuth.RequireAdminAuth("admin:workflow:create"), ctrl.CreateSchedule)
+	router.Get("/schedules", base.AdminAuth.RequireAdminAuth("admin:workflow:read"), ctrl.ListSchedules)
+	router.Get("/schedules/:id", base.AdminAuth.RequireAdminAuth("admin:workflow:read"), ctrl.GetSchedule)
+	router.Put("/schedules/:id", base.AdminAuth.RequireAdminAuth("admin:workflow:update"), ctrl.UpdateSchedule)
+	router.Delete("/schedules/:id", base.AdminAuth.RequireAdminAuth("admin:workflow:delete"), ctrl.DeleteSchedule)
+	router.Post("/schedules/:id/pause", base.AdminAuth.RequireAdminAuth("admin:workflow:update"), ctrl.PauseSchedule)
+	router.Post("/schedules/:id/resume", base.AdminAuth.RequireAdminAuth("admin:workflow:update"), ctrl.ResumeSchedule)
+	router.Post("/schedules/:id/run", base.AdminAuth.RequireAdminAuth("admin:workflow:update"), ctrl.RunScheduleNow)
+	router.Get("/schedules/:id/runs", base.AdminAuth.RequireAdminAuth("admin:workflow:read"), ctrl.ListScheduleRuns)
+
+	router.Post("/retention-policies", base.AdminAuth.RequireAdminAuth("admin:workflow:create"), ctrl.CreateRetentionPolicy)
+	router.Get("/retention-policies", base.AdminAuth.RequireAdminAuth("admin:workflow:read"), ctrl.ListRetentionPolicies)
+	router.Get("/retention-policies/:id", base.AdminAuth.RequireAdminAuth("admin:workflow:read"), ctrl.GetRetentionPolicy)
+	router.Put("/retention-policies/:id", base.AdminAuth.RequireAdminAuth("admin:workflow:update"), ctrl.UpdateRetentionPolicy)
+	router.Delete("/retention-policies/:id", base.AdminAuth.RequireAdminAuth("admin:workflow:delete"), ctrl.DeleteRetentionPolicy)
+	router.Post("/retention/run", base.AdminAuth.RequireAdminAuth("admin:workflow:delete"), ctrl.RunRetention)
+
+	router.Post("/triggers", base.AdminAuth.RequireAdminAuth("admin:workflow:create"), ctrl.CreateTrigger)
+	router.Get("/triggers", base.AdminAuth.RequireAdminAuth("admin:workflow:read"), ctrl.ListTriggers)
+	router.Get("/triggers/:id", base.AdminAuth.RequireAdminAuth("admin:workflow:read"), ctrl.GetTrigger)
+	router.Put("/triggers/:id", base.AdminAuth.RequireAdminAuth("admin:workflow:update"), ctrl.UpdateTrigger)
+	router.Delete("/triggers/:id", base.AdminAuth.RequireAdminAuth("admin:workflow:delete"), ctrl.DeleteTrigger)
+
+	router.Get("/approvals/inbox", base.AdminAuth.RequireAdminAuth("admin:workflow:read"), ctrl.ApprovalInbox)
+	router.Get("/approvals/:id", base.AdminAuth.RequireAdminAuth("admin:workflow:read"), ctrl.GetApproval)
+	router.Post("/approvals/:id/decision", base.AdminAuth.RequireAdminAuth("admin:workflow:update"), ctrl.DecideApproval)
 }
 
 func (ctrl *WorkflowAdminController) CreateDef(c *fiber.Ctx) error {
@@ -61,6 +115,48 @@ func (ctrl *WorkflowAdminController) CreateDef(c *fiber.Ctx) error {
 	return result.OK(c, fiber.Map{"id": id})
 }
 
+// ValidateDef 校验 DAG 而不创建定义，返回全部问题及其节点/边位置
+func (ctrl *WorkflowAdminController) ValidateDef(c *fiber.Ctx) error {
+	var req dto.ValidateDefRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ctrl.err.New("解析请求参数失败", err).WithTraceID(utils.Context(c)).ToLog(ctrl.log.GetLogger())
+	}
+	if errMsg, err := utils.Validate(&req); err != nil {
+		return ctrl.err.New(errMsg, err).WithTraceID(utils.Context(c)).ToLog(ctrl.log.GetLogger())
+	}
+	return result.OK(c, ctrl.app.ValidateDef(req.DAGJSON))
+}
+
+// Simulate 以模拟的节点输出离线运行定义，返回节点完成轨迹与最终状态
+func (ctrl *WorkflowAdminController) Simulate(c *fiber.Ctx) error {
+	var req dto.SimulateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ctrl.err.New("解析请求参数失败", err).WithTraceID(utils.Context(c)).ToLog(ctrl.log.GetLogger())
+	}
+	if errMsg, err := utils.Validate(&req); err != nil {
+		return ctrl.err.New(errMsg, err).WithTraceID(utils.Context(c)).ToLog(ctrl.log.GetLogger())
+	}
+	mocks := make(map[string]*app.SimulationMock, len(req.Mocks))
+	for nodeID, m := range req.Mocks {
+		if m == nil {
+			continue
+		}
+		mocks[nodeID] = &app.SimulationMock{Output: m.Output, Outputs: m.Outputs, Error: m.Error, FailOn: m.FailOn, Timeout: m.Timeout}
+	}
+	res, err := ctrl.app.Simulate(utils.Context(c), &app.SimulationInput{
+		Env:         req.Env,
+		DAGJSON:     req.DAGJSON,
+		DefCode:     req.DefCode,
+		DefVersion:  req.Version,
+		InitialData: req.InitialData,
+		Mocks:       mocks,
+	})
+	if err != nil {
+		return err
+	}
+	return result.OK(c, res)
+}
+
 func (ctrl *WorkflowAdminController) Rollback(c *fiber.Ctx) error {
 	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
 	if err != nil {
@@ -108,6 +204,150 @@ func (ctrl *WorkflowAdminController) SendSignal(c *fiber.Ctx) error {
 	return result.OK(c, fiber.Map{"msg": "信号已发送"})
 }
 
+// SendSignalByKey 按关联键投递信号，返回推进的等待数与实例
+func (ctrl *WorkflowAdminController) SendSignalByKey(c *fiber.Ctx) error {
+	var req dto.SignalByKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ctrl.err.New("解析请求参数失败", err).WithTraceID(utils.Context(c)).ToLog(ctrl.log.GetLogger())
+	}
+	if errMsg, err := utils.Validate(&req); err != nil {
+		return ctrl.err.New(errMsg, err).WithTraceID(utils.Context(c)).ToLog(ctrl.log.GetLogger())
+	}
+	if req.Payload == nil {
+		req.Payload = make(map[string]interface{})
+	}
+	res, err := ctrl.app.SendSignalByKey(utils.Context(c), req.Env, req.SignalName, req.CorrelationKey, req.Payload)
+	if err != nil {
+		return err
+	}
+	return result.OK(c, res)
+}
+
+func (ctrl *WorkflowAdminController) ForkInstance(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return ctrl.err.New("实例ID参数错误", err).WithTraceID(utils.Context(c))
+	}
+	var req dto.ForkInstanceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ctrl.err.New("解析请求参数失败", err).WithTraceID(utils.Context(c)).ToLog(ctrl.log.GetLogger())
+	}
+	if errMsg, err := utils.Validate(&req); err != nil {
+		return ctrl.err.New(errMsg, err).WithTraceID(utils.Context(c)).ToLog(ctrl.log.GetLogger())
+	}
+	instanceID, err := ctrl.app.ForkInstance(utils.Context(c), id, req.CheckpointID, req.Patch, req.Env)
+	if err != nil {
+		return err
+	}
+	return result.OK(c, fiber.Map{"instance_id": instanceID})
+}
+
+func (ctrl *WorkflowAdminController) Compensate(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return ctrl.err.New("实例ID参数错误", err).WithTraceID(utils.Context(c))
+	}
+	if err := ctrl.app.CompensateInstance(utils.Context(c), id); err != nil {
+		return err
+	}
+	return result.OK(c, fiber.Map{"msg": "已开始补偿"})
+}
+
+func (ctrl *WorkflowAdminController) PauseInstance(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return ctrl.err.New("实例ID参数错误", err).WithTraceID(utils.Context(c))
+	}
+	if err := ctrl.app.PauseInstance(utils.Context(c), id); err != nil {
+		return err
+	}
+	return result.OK(c, fiber.Map{"msg": "暂停成功"})
+}
+
+func (ctrl *WorkflowAdminController) ResumeInstance(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return ctrl.err.New("实例ID参数错误", err).WithTraceID(utils.Context(c))
+	}
+	if err := ctrl.app.ResumeInstance(utils.Context(c), id); err != nil {
+		return err
+	}
+	return result.OK(c, fiber.Map{"msg": "恢复成功"})
+}
+
+// PauseInstancesByDef 批量暂停定义下的实例，环境取 query env，缺省为当前环境
+func (ctrl *WorkflowAdminController) PauseInstancesByDef(c *fiber.Ctx) error {
+	affected, err := ctrl.app.PauseInstancesByDef(utils.Context(c), c.Params("code"), c.Query("env"))
+	if err != nil {
+		return err
+	}
+	return result.OK(c, fiber.Map{"affected": affected})
+}
+
+// ResumeInstancesByDef 批量恢复定义下已暂停的实例，环境取 query env，缺省为当前环境
+func (ctrl *WorkflowAdminController) ResumeInstancesByDef(c *fiber.Ctx) error {
+	affected, err := ctrl.app.ResumeInstancesByDef(utils.Context(c), c.Params("code"), c.Query("env"))
+	if err != nil {
+		return err
+	}
+	return result.OK(c, fiber.Map{"affected": affected})
+}
+
+// MigrateInstances 把定义下的实例迁移到目标版本，dry_run 时只返回校验结果
+func (ctrl *WorkflowAdminController) MigrateInstances(c *fiber.Ctx) error {
+	var req dto.MigrateInstancesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ctrl.err.New("解析请求参数失败", err).WithTraceID(utils.Context(c)).ToLog(ctrl.log.GetLogger())
+	}
+	report, err := ctrl.app.MigrateInstances(utils.Context(c), &app.MigrateInstancesInput{
+		DefCode:       c.Params("code"),
+		Env:           req.Env,
+		TargetVersion: req.TargetVersion,
+		InstanceIDs:   req.InstanceIDs,
+		FromVersion:   req.FromVersion,
+		NodeMapping:   req.NodeMapping,
+		DryRun:        req.DryRun,
+	})
+	if err != nil {
+		return err
+	}
+	return result.OK(c, report)
+}
+
+func (ctrl *WorkflowAdminController) ListInstanceMigrations(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return ctrl.err.New("实例ID参数错误", err).WithTraceID(utils.Context(c))
+	}
+	items, err := ctrl.app.ListInstanceMigrations(utils.Context(c), id)
+	if err != nil {
+		return err
+	}
+	return result.OK(c, fiber.Map{"items": items})
+}
+
+// RenderDef 把定义的 DAG 渲染为 Mermaid 或 DOT 文本；?format=mermaid|dot&version=0&env=
+func (ctrl *WorkflowAdminController) RenderDef(c *fiber.Ctx) error {
+	g, err := ctrl.app.RenderDef(utils.Context(c), c.Query("env"), c.Params("code"), int32(c.QueryInt("version", 0)), c.Query("format"))
+	if err != nil {
+		return err
+	}
+	return result.OK(c, g)
+}
+
+// RenderInstance 渲染实例的 DAG 并按执行进度给节点着色；?format=mermaid|dot
+func (ctrl *WorkflowAdminController) RenderInstance(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return ctrl.err.New("实例ID参数错误", err).WithTraceID(utils.Context(c))
+	}
+	g, err := ctrl.app.RenderInstance(utils.Context(c), id, c.Query("format"))
+	if err != nil {
+		return err
+	}
+	return result.OK(c, g)
+}
+
 func (ctrl *WorkflowAdminController) GetInstance(c *fiber.Ctx) error {
 	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
 	if err != nil {
@@ -120,6 +360,32 @@ func (ctrl *WorkflowAdminController) GetInstance(c *fiber.Ctx) error {
 	return result.OK(c, inst)
 }
 
+// ListInstances 分页列出实例；?def_code=&status=&business_key=&created_after=&created_before=（Unix 秒）
+func (ctrl *WorkflowAdminController) ListInstances(c *fiber.Ctx) error {
+	filter := &app.ListInstancesFilter{
+		DefCode:       c.Query("def_code"),
+		Status:        c.Query("status"),
+		BusinessKey:   c.Query("business_key"),
+		CreatedAfter:  int64(c.QueryInt("created_after", 0)),
+		CreatedBefore: int64(c.QueryInt("created_before", 0)),
+	}
+	items, total, err := ctrl.app.ListInstances(utils.Context(c), filter,
+		int32(c.QueryInt("page_num", 1)), int32(c.QueryInt("page_size", 10)))
+	if err != nil {
+		return err
+	}
+	return result.OK(c, fiber.Map{"items": items, "total": total})
+}
+
+// GetInstanceByBusinessKey 按业务键获取最近一个实例；?def_code=&business_key=&env=
+func (ctrl *WorkflowAdminController) GetInstanceByBusinessKey(c *fiber.Ctx) error {
+	inst, err := ctrl.app.GetInstanceByBusinessKey(utils.Context(c), c.Query("env"), c.Query("def_code"), c.Query("business_key"))
+	if err != nil {
+		return err
+	}
+	return result.OK(c, inst)
+}
+
 func (ctrl *WorkflowAdminController) GetExecutionTrail(c *fiber.Ctx) error {
 	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
 	if err != nil {
@@ -155,3 +421,519 @@ func (ctrl *WorkflowAdminController) GetExecutionState(c *fiber.Ctx) error {
 	}
 	return result.OK(c, state)
 }
+
+// sseKeepAliveInterval SSE 空闲时发送注释行的间隔，用于穿透代理超时并及时发现客户端断开
+const sseKeepAliveInterval = 15 * time.Second
+
+// WatchInstanceEvents 以 SSE 推送实例事件，事件 id 为实例内序号。
+// 续传位置取 after_seq 参数，浏览器 EventSource 重连时自动携带的 Last-Event-ID 优先
+func (ctrl *WorkflowAdminController) WatchInstanceEvents(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return ctrl.err.New("实例ID参数错误", err).WithTraceID(utils.Context(c))
+	}
+	afterSeq, err := sseCursor(c, "after_seq")
+	if err != nil {
+		return ctrl.err.New("after_seq 参数错误", err).WithTraceID(utils.Context(c))
+	}
+	// 开始推送后无法再返回错误响应，先确认实例存在
+	if _, err := ctrl.app.GetInstance(utils.Context(c), id); err != nil {
+		return err
+	}
+	return ctrl.streamSSE(c, func(ctx context.Context, fn func(*app.WorkflowInstanceEventModel) error) error {
+		return ctrl.app.WatchInstance(ctx, id, afterSeq, fn)
+	}, func(e *app.WorkflowInstanceEventModel) int64 { return e.Seq })
+}
+
+// WatchDefEvents 以 SSE 推送某定义编码下所有实例的事件，事件 id 为全局事件 ID
+func (ctrl *WorkflowAdminController) WatchDefEvents(c *fiber.Ctx) error {
+	code := c.Params("code")
+	env := c.Query("env")
+	if env == "" {
+		env = base.ENV
+	}
+	afterID, err := sseCursor(c, "after_event_id")
+	if err != nil {
+		return ctrl.err.New("after_event_id 参数错误", err).WithTraceID(utils.Context(c))
+	}
+	return ctrl.streamSSE(c, func(ctx context.Context, fn func(*app.WorkflowInstanceEventModel) error) error {
+		return ctrl.app.WatchDef(ctx, env, code, afterID, fn)
+	}, func(e *app.WorkflowInstanceEventModel) int64 { return e.ID })
+}
+
+// sseCursor 读取续传游标：Last-Event-ID 请求头优先，其次为查询参数
+func sseCursor(c *fiber.Ctx, queryKey string) (int64, error) {
+	raw := c.Get("Last-Event-ID")
+	if raw == "" {
+		raw = c.Query(queryKey)
+	}
+	if raw == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(raw, 10, 64)
+}
+
+// streamSSE 在独立 goroutine 中运行订阅，把事件逐条写成 SSE 帧。
+//
+// fasthttp 的流式写入在 handler 返回后才执行，且客户端断开不会取消请求 ctx，
+// 因此订阅使用自建 ctx：写入或 flush 失败即视为断开并取消订阅。
+func (ctrl *WorkflowAdminController) streamSSE(c *fiber.Ctx, watch func(context.Context, func(*app.WorkflowInstanceEventModel) error) error, cursor func(*app.WorkflowInstanceEventModel) int64) error {
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(fasthttp.StreamWriter(func(w *bufio.Writer) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		events := make(chan *app.WorkflowInstanceEventModel)
+		done := make(chan error, 1)
+		go func() {
+			done <- watch(ctx, func(e *app.WorkflowInstanceEventModel) error {
+				select {
+				case events <- e:
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			})
+		}()
+
+		keepAlive := time.NewTicker(sseKeepAliveInterval)
+		defer keepAlive.Stop()
+		for {
+			select {
+			case e := <-events:
+				data, _ := json.Marshal(e)
+				fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", cursor(e), e.Type, data)
+			case <-keepAlive.C:
+				fmt.Fprint(w, ": keep-alive\n\n")
+			case err := <-done:
+				if err != nil && ctx.Err() == nil {
+					ctrl.log.WithErr(err).Warn("SSE 订阅异常结束")
+					fmt.Fprintf(w, "event: error\ndata: %q\n\n", err.Error())
+				} else {
+					fmt.Fprint(w, "event: end\ndata: {}\n\n")
+				}
+				_ = w.Flush()
+				return
+			}
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	}))
+	return nil
+}
+
+// parseScheduleRequest 解析并校验调度请求体
+func (ctrl *WorkflowAdminController) parseScheduleRequest(c *fiber.Ctx) (*app.ScheduleInput, error) {
+	var req dto.ScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return nil, ctrl.err.New("解析请求参数失败", err).WithTraceID(utils.Context(c)).ToLog(ctrl.log.GetLogger())
+	}
+	if errMsg, err := utils.Validate(&req); err != nil {
+		return nil, ctrl.err.New(errMsg, err).WithTraceID(utils.Context(c)).ToLog(ctrl.log.GetLogger())
+	}
+	return &app.ScheduleInput{
+		Env:                 req.Env,
+		Name:                req.Name,
+		DefCode:             req.DefCode,
+		DefVersion:          req.DefVersion,
+		CronExpr:            req.CronExpr,
+		Timezone:            req.Timezone,
+		InitialDataTemplate: req.InitialDataTemplate,
+		OverlapPolicy:       req.OverlapPolicy,
+	}, nil
+}
+
+func (ctrl *WorkflowAdminController) CreateSchedule(c *fiber.Ctx) error {
+	in, err := ctrl.parseScheduleRequest(c)
+	if err != nil {
+		return err
+	}
+	sched, err := ctrl.app.CreateSchedule(utils.Context(c), in)
+	if err != nil {
+		return err
+	}
+	return result.OK(c, sched)
+}
+
+func (ctrl *WorkflowAdminController) UpdateSchedule(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return ctrl.err.New("调度ID参数错误", err).WithTraceID(utils.Context(c))
+	}
+	in, err := ctrl.parseScheduleRequest(c)
+	if err != nil {
+		return err
+	}
+	sched, err := ctrl.app.UpdateSchedule(utils.Context(c), id, in)
+	if err != nil {
+		return err
+	}
+	return result.OK(c, sched)
+}
+
+func (ctrl *WorkflowAdminController) DeleteSchedule(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return ctrl.err.New("调度ID参数错误", err).WithTraceID(utils.Context(c))
+	}
+	if err := ctrl.app.DeleteSchedule(utils.Context(c), id); err != nil {
+		return err
+	}
+	return result.OK(c, fiber.Map{"msg": "删除成功"})
+}
+
+func (ctrl *WorkflowAdminController) GetSchedule(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return ctrl.err.New("调度ID参数错误", err).WithTraceID(utils.Context(c))
+	}
+	sched, err := ctrl.app.GetSchedule(utils.Context(c), id)
+	if err != nil {
+		return err
+	}
+	return result.OK(c, sched)
+}
+
+func (ctrl *WorkflowAdminController) ListSchedules(c *fiber.Ctx) error {
+	items, total, err := ctrl.app.ListSchedules(utils.Context(c), c.Query("env"), c.Query("def_code"),
+		int32(c.QueryInt("page_num", 1)), int32(c.QueryInt("page_size", 10)))
+	if err != nil {
+		return err
+	}
+	return result.OK(c, fiber.Map{"items": items, "total": total})
+}
+
+func (ctrl *WorkflowAdminController) PauseSchedule(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return ctrl.err.New("调度ID参数错误", err).WithTraceID(utils.Context(c))
+	}
+	if err := ctrl.app.PauseSchedule(utils.Context(c), id); err != nil {
+		return err
+	}
+	return result.OK(c, fiber.Map{"msg": "调度已暂停"})
+}
+
+func (ctrl *WorkflowAdminController) ResumeSchedule(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return ctrl.err.New("调度ID参数错误", err).WithTraceID(utils.Context(c))
+	}
+	if err := ctrl.app.ResumeSchedule(utils.Context(c), id); err != nil {
+		return err
+	}
+	return result.OK(c, fiber.Map{"msg": "调度已恢复"})
+}
+
+func (ctrl *WorkflowAdminController) RunScheduleNow(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return ctrl.err.New("调度ID参数错误", err).WithTraceID(utils.Context(c))
+	}
+	instanceID, err := ctrl.app.RunScheduleNow(utils.Context(c), id)
+	if err != nil {
+		return err
+	}
+	return result.OK(c, fiber.Map{"instance_id": instanceID})
+}
+
+func (ctrl *WorkflowAdminController) ListScheduleRuns(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return ctrl.err.New("调度ID参数错误", err).WithTraceID(utils.Context(c))
+	}
+	items, total, err := ctrl.app.ListScheduleRuns(utils.Context(c), id,
+		int32(c.QueryInt("page_num", 1)), int32(c.QueryInt("page_size", 10)))
+	if err != nil {
+		return err
+	}
+	return result.OK(c, fiber.Map{"items": items, "total": total})
+}
+
+// parseTriggerRequest 解析并校验触发器请求体
+func (ctrl *WorkflowAdminController) parseTriggerRequest(c *fiber.Ctx) (*app.TriggerInput, error) {
+	var req dto.TriggerRequest
+	if err := c.BodyParser(&req); err != nil {
+		return nil, ctrl.err.New("解析请求参数失败", err).WithTraceID(utils.Context(c)).ToLog(ctrl.log.GetLogger())
+	}
+	if errMsg, err := utils.Validate(&req); err != nil {
+		return nil, ctrl.err.New(errMsg, err).WithTraceID(utils.Context(c)).ToLog(ctrl.log.GetLogger())
+	}
+	return &app.TriggerInput{
+		Code:            req.Code,
+		Name:            req.Name,
+		Env:             req.Env,
+		DefCode:         req.DefCode,
+		Disabled:        req.Disabled,
+		AuthType:        req.AuthType,
+		Secret:          req.Secret,
+		SignatureHeader: req.SignatureHeader,
+		SignaturePrefix: req.SignaturePrefix,
+		BodyMapping:     req.BodyMapping,
+		DedupHeader:     req.DedupHeader,
+		DedupPath:       req.DedupPath,
+	}, nil
+}
+
+func (ctrl *WorkflowAdminController) CreateTrigger(c *fiber.Ctx) error {
+	in, err := ctrl.parseTriggerRequest(c)
+	if err != nil {
+		return err
+	}
+	trigger, err := ctrl.app.CreateTrigger(utils.Context(c), in)
+	if err != nil {
+		return err
+	}
+	return result.OK(c, trigger)
+}
+
+func (ctrl *WorkflowAdminController) UpdateTrigger(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return ctrl.err.New("触发器ID参数错误", err).WithTraceID(utils.Context(c))
+	}
+	in, err := ctrl.parseTriggerRequest(c)
+	if err != nil {
+		return err
+	}
+	trigger, err := ctrl.app.UpdateTrigger(utils.Context(c), id, in)
+	if err != nil {
+		return err
+	}
+	return result.OK(c, trigger)
+}
+
+func (ctrl *WorkflowAdminController) DeleteTrigger(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return ctrl.err.New("触发器ID参数错误", err).WithTraceID(utils.Context(c))
+	}
+	if err := ctrl.app.DeleteTrigger(utils.Context(c), id); err != nil {
+		return err
+	}
+	return result.OK(c, fiber.Map{"msg": "删除成功"})
+}
+
+func (ctrl *WorkflowAdminController) GetTrigger(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return ctrl.err.New("触发器ID参数错误", err).WithTraceID(utils.Context(c))
+	}
+	trigger, err := ctrl.app.GetTrigger(utils.Context(c), id)
+	if err != nil {
+		return err
+	}
+	return result.OK(c, trigger)
+}
+
+func (ctrl *WorkflowAdminController) ListTriggers(c *fiber.Ctx) error {
+	items, total, err := ctrl.app.ListTriggers(utils.Context(c), c.Query("env"),
+		int32(c.QueryInt("page_num", 1)), int32(c.QueryInt("page_size", 10)))
+	if err != nil {
+		return err
+	}
+	return result.OK(c, fiber.Map{"items": items, "total": total})
+}
+
+// approvalActor 取当前登录管理员作为审批人
+func (ctrl *WorkflowAdminController) approvalActor(c *fiber.Ctx) (app.ApprovalActor, error) {
+	adminID, err := security.GetAdminId(c)
+	if err != nil || adminID <= 0 {
+		return app.ApprovalActor{}, ctrl.err.New("无法识别当前管理员", err).NoAuth().WithTraceID(utils.Context(c))
+	}
+	account, _ := security.GetAdminAccount(c)
+	roles, _ := security.GetAdminRoles(c)
+	return app.ApprovalActor{
+		AdminID: adminID,
+		Account: account,
+		Roles:   roles,
+		IsSuper: security.IsAdminSuper(c),
+	}, nil
+}
+
+// ApprovalInbox 列出当前管理员待处理的审批（跨实例）
+func (ctrl *WorkflowAdminController) ApprovalInbox(c *fiber.Ctx) error {
+	actor, err := ctrl.approvalActor(c)
+	if err != nil {
+		return err
+	}
+	items, total, err := ctrl.app.ListApprovalInbox(utils.Context(c), actor,
+		int32(c.QueryInt("page_num", 1)), int32(c.QueryInt("page_size", 10)))
+	if err != nil {
+		return err
+	}
+	return result.OK(c, fiber.Map{"items": items, "total": total})
+}
+
+func (ctrl *WorkflowAdminController) GetApproval(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return ctrl.err.New("审批ID参数错误", err).WithTraceID(utils.Context(c))
+	}
+	detail, err := ctrl.app.GetApproval(utils.Context(c), id)
+	if err != nil {
+		return err
+	}
+	return result.OK(c, detail)
+}
+
+// DecideApproval 以当前管理员身份提交审批决定，记录决定人、决定与时间
+func (ctrl *WorkflowAdminController) DecideApproval(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return ctrl.err.New("审批ID参数错误", err).WithTraceID(utils.Context(c))
+	}
+	var req dto.ApprovalDecisionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ctrl.err.New("解析请求参数失败", err).WithTraceID(utils.Context(c)).ToLog(ctrl.log.GetLogger())
+	}
+	if errMsg, err := utils.Validate(&req); err != nil {
+		return ctrl.err.New(errMsg, err).WithTraceID(utils.Context(c)).ToLog(ctrl.log.GetLogger())
+	}
+	actor, err := ctrl.approvalActor(c)
+	if err != nil {
+		return err
+	}
+	approval, err := ctrl.app.DecideApproval(utils.Context(c), id, actor, req.Decision, req.Comment, req.Form)
+	if err != nil {
+		return err
+	}
+	return result.OK(c, approval)
+}
+
+// ListNodeRuns 按触发顺序列出实例的节点执行记录（开始/结束时间、结果、检查点与任务尝试）
+func (ctrl *WorkflowAdminController) ListNodeRuns(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return ctrl.err.New("实例ID参数错误", err).WithTraceID(utils.Context(c))
+	}
+	items, err := ctrl.app.ListNodeRuns(utils.Context(c), id)
+	if err != nil {
+		return err
+	}
+	return result.OK(c, fiber.Map{"items": items, "total": len(items)})
+}
+
+// parseAnalyticsQuery 解析报表范围：?env=&version=&from=&to=&bucket=，from/to 为 Unix 秒，缺省为最近 7 天
+func parseAnalyticsQuery(c *fiber.Ctx) *app.AnalyticsQuery {
+	q := &app.AnalyticsQuery{
+		Env:     c.Query("env"),
+		DefCode: c.Params("code"),
+		Version: int32(c.QueryInt("version", 0)),
+		Bucket:  c.Query("bucket"),
+	}
+	if from := c.QueryInt("from", 0); from > 0 {
+		q.From = time.Unix(int64(from), 0)
+	}
+	if to := c.QueryInt("to", 0); to > 0 {
+		q.To = time.Unix(int64(to), 0)
+	}
+	return q
+}
+
+// NodeAnalytics 定义各节点的耗时分位数、排队与执行时长、重试与 error 边频率
+func (ctrl *WorkflowAdminController) NodeAnalytics(c *fiber.Ctx) error {
+	report, err := ctrl.app.NodeAnalytics(utils.Context(c), parseAnalyticsQuery(c))
+	if err != nil {
+		return err
+	}
+	return result.OK(c, report)
+}
+
+// InstanceStatusAnalytics 定义按时间桶统计的各终态实例数
+func (ctrl *WorkflowAdminController) InstanceStatusAnalytics(c *fiber.Ctx) error {
+	report, err := ctrl.app.InstanceStatusAnalytics(utils.Context(c), parseAnalyticsQuery(c))
+	if err != nil {
+		return err
+	}
+	return result.OK(c, report)
+}
+
+func (ctrl *WorkflowAdminController) parseRetentionPolicyRequest(c *fiber.Ctx) (*app.RetentionPolicyInput, error) {
+	var req dto.RetentionPolicyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return nil, ctrl.err.New("解析请求参数失败", err).WithTraceID(utils.Context(c)).ToLog(ctrl.log.GetLogger())
+	}
+	if errMsg, err := utils.Validate(&req); err != nil {
+		return nil, ctrl.err.New(errMsg, err).WithTraceID(utils.Context(c)).ToLog(ctrl.log.GetLogger())
+	}
+	return &app.RetentionPolicyInput{
+		Env:        req.Env,
+		DefCode:    req.DefCode,
+		RetainDays: req.RetainDays,
+		Action:     req.Action,
+	}, nil
+}
+
+func (ctrl *WorkflowAdminController) CreateRetentionPolicy(c *fiber.Ctx) error {
+	in, err := ctrl.parseRetentionPolicyRequest(c)
+	if err != nil {
+		return err
+	}
+	policy, err := ctrl.app.CreateRetentionPolicy(utils.Context(c), in)
+	if err != nil {
+		return err
+	}
+	return result.OK(c, policy)
+}
+
+func (ctrl *WorkflowAdminController) UpdateRetentionPolicy(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return ctrl.err.New("保留策略ID参数错误", err).WithTraceID(utils.Context(c))
+	}
+	in, err := ctrl.parseRetentionPolicyRequest(c)
+	if err != nil {
+		return err
+	}
+	policy, err := ctrl.app.UpdateRetentionPolicy(utils.Context(c), id, in)
+	if err != nil {
+		return err
+	}
+	return result.OK(c, policy)
+}
+
+func (ctrl *WorkflowAdminController) DeleteRetentionPolicy(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return ctrl.err.New("保留策略ID参数错误", err).WithTraceID(utils.Context(c))
+	}
+	if err := ctrl.app.DeleteRetentionPolicy(utils.Context(c), id); err != nil {
+		return err
+	}
+	return result.OK(c, fiber.Map{"msg": "删除成功"})
+}
+
+func (ctrl *WorkflowAdminController) GetRetentionPolicy(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return ctrl.err.New("保留策略ID参数错误", err).WithTraceID(utils.Context(c))
+	}
+	policy, err := ctrl.app.GetRetentionPolicy(utils.Context(c), id)
+	if err != nil {
+		return err
+	}
+	return result.OK(c, policy)
+}
+
+func (ctrl *WorkflowAdminController) ListRetentionPolicies(c *fiber.Ctx) error {
+	items, err := ctrl.app.ListRetentionPolicies(utils.Context(c), c.Query("env"))
+	if err != nil {
+		return err
+	}
+	return result.OK(c, fiber.Map{"items": items, "total": len(items)})
+}
+
+// RunRetention 手动执行保留策略：policy_id 为空时执行全部策略，dry_run=true 时只返回待处理数
+func (ctrl *WorkflowAdminController) RunRetention(c *fiber.Ctx) error {
+	policyID := int64(c.QueryInt("policy_id", 0))
+	reports, err := ctrl.app.RunRetention(utils.Context(c), time.Now(), policyID, c.QueryBool("dry_run", false))
+	if err != nil {
+		return err
+	}
+	return result.OK(c, fiber.Map{"items": reports})
+}
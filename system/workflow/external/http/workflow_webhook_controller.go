@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"strings"
+
+	"github.com/xsxdot/aio/system/workflow/internal/app"
+	errorc "github.com/xsxdot/gokit/err"
+	"github.com/xsxdot/gokit/logger"
+	"github.com/xsxdot/gokit/result"
+	"github.com/xsxdot/gokit/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// WorkflowWebhookController Webhook 公开入口：外部系统无需 SDK 凭证即可启动工作流
+type WorkflowWebhookController struct {
+	app *app.App
+	err *errorc.ErrorBuilder
+	log *logger.Log
+}
+
+func NewWorkflowWebhookController(a *app.App) *WorkflowWebhookController {
+	return &WorkflowWebhookController{
+		app: a,
+		err: errorc.NewErrorBuilder("WorkflowWebhookController"),
+		log: logger.GetLogger().WithEntryName("WorkflowWebhookController"),
+	}
+}
+
+func (ctrl *WorkflowWebhookController) RegisterRoutes(api fiber.Router) {
+	// 无登录鉴权：由触发器配置的 HMAC 签名或共享令牌校验
+	api.Post("/workflow/webhooks/:code", ctrl.Receive)
+}
+
+// Receive 受理一次投递。签名基于原始请求体计算，因此这里直接取 Body 而不做 BodyParser。
+func (ctrl *WorkflowWebhookController) Receive(c *fiber.Ctx) error {
+	headers := make(map[string]string)
+	for k, v := range c.GetReqHeaders() {
+		if len(v) > 0 {
+			headers[strings.ToLower(k)] = v[0]
+		}
+	}
+	req := &app.WebhookRequest{
+		// fasthttp 的 Body 在请求结束后会被复用，这里复制一份
+		Body:    append([]byte(nil), c.Body()...),
+		Headers: headers,
+		Query:   c.Queries(),
+	}
+	instanceID, duplicate, err := ctrl.app.HandleWebhook(utils.Context(c), c.Params("code"), req)
+	if err != nil {
+		return err
+	}
+	return result.OK(c, fiber.Map{"instance_id": instanceID, "duplicate": duplicate})
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/xsxdot/aio/base"
+	"github.com/xsxdot/aio/pkg/core/config"
 	executorClient "github.com/xsxdot/aio/system/executor/api/client"
 	"github.com/xsxdot/aio/system/workflow/internal/dao"
 	"github.com/xsxdot/aio/system/workflow/internal/model"
@@ -28,8 +29,35 @@ type App struct {
 	ExecutorClient    *executorClient.ExecutorClient
 	// AppliedCallbackDao 回调幂等标记，仅供 ReportNodeCompletedFromJob 在推进事务内使用
 	AppliedCallbackDao *dao.WorkflowAppliedCallbackDao
-	log                *logger.Log
-	err                *errorc.ErrorBuilder
+	// ScheduleService / ScheduleRunService 定时调度及其运行历史，由 module.go 装配；
+	// 不涉及调度的测试可以不设置
+	ScheduleService    *service.WorkflowScheduleService
+	ScheduleRunService *service.WorkflowScheduleRunService
+	// TriggerService Webhook 触发器及其投递去重，由 module.go 装配
+	TriggerService *service.WorkflowTriggerService
+	// EventService 实例事件流水，由 module.go 装配；未设置时推进不落事件、订阅接口不可用
+	EventService *service.WorkflowInstanceEventService
+	// ApprovalService 审批待办与决定记录，由 module.go 装配；未设置时配置了审批规则的审批节点无法进入
+	ApprovalService *service.WorkflowApprovalService
+	// ConfigReader 配置中心读取，由 module.go 装配；未设置时引用了配置的 http 节点请求失败
+	ConfigReader ConfigReader
+	// HTTPSecretRules http 节点密钥引用的放行规则，由 module.go 按 workflow.http-secrets 装配；
+	// 为空时任何引用都被拒绝
+	HTTPSecretRules []config.HTTPSecretRule
+	// MigrationService 实例迁移审计记录，由 module.go 装配；迁移本身在事务内直接写审计表
+	MigrationService *service.WorkflowInstanceMigrationService
+	// SignalWaitService wait_signal 节点的信号等待，由 module.go 装配；未设置时 wait_signal 节点无法进入
+	SignalWaitService *service.WorkflowSignalWaitService
+	// BusinessKeyService 业务键占用，由 module.go 装配；未设置时不能带业务键启动实例
+	BusinessKeyService *service.WorkflowBusinessKeyService
+	// RetentionService 实例保留策略与归档清理，由 module.go 装配；未设置时保留策略不可用
+	RetentionService *service.WorkflowRetentionService
+	// ArchiveUploader 保留策略导出文件的上传目标，由 module.go 以 base.OSS 装配；未设置时不能使用 export 方式
+	ArchiveUploader ArchiveUploader
+	// NodeRunService 节点执行记录与耗时报表，由 module.go 装配；未设置时推进不记录节点耗时、报表接口不可用
+	NodeRunService *service.WorkflowNodeRunService
+	log            *logger.Log
+	err            *errorc.ErrorBuilder
 }
 
 // NewApp 创建内部 App。
@@ -63,26 +91,48 @@ func (a *App) CreateDef(ctx context.Context, env, code, name, dagJSON string, ve
 	if version <= 0 {
 		version = 1
 	}
-	var dag model.DAG
-	if err := json.Unmarshal([]byte(dagJSON), &dag); err != nil {
-		return 0, a.err.New("解析DAG失败", err)
-	}
-	if err := dag.Validate(); err != nil {
-		return 0, a.err.New("DAG 验证失败: "+err.Error(), err)
+	def, dag, err := a.parseDef(dagJSON)
+	if err != nil {
+		return 0, err
 	}
-	def := &model.WorkflowDefModel{
-		Env:     env,
-		Code:    code,
-		Version: version,
-		Name:    name,
-		DAGJSON: dagJSON,
+	if err := a.lintHTTPSecrets(env, code, dag).Err(); err != nil {
+		return 0, a.err.New("DAG 验证失败: "+err.Error(), err).WithCode(errorc.ErrorCodeValid)
 	}
+	def.Env = env
+	def.Code = code
+	def.Version = version
+	def.Name = name
 	if err := a.DefService.Create(ctx, def); err != nil {
 		return 0, err
 	}
 	return def.ID, nil
 }
 
+// parseDef 校验 DAG JSON 并构造未落库的定义（含序列化后的 input_schema / state_schema）
+func (a *App) parseDef(dagJSON string) (*model.WorkflowDefModel, *model.DAG, error) {
+	if err := lintDAG(dagJSON).Err(); err != nil {
+		return nil, nil, a.err.New("DAG 验证失败: "+err.Error(), err).WithCode(errorc.ErrorCodeValid)
+	}
+	var dag model.DAG
+	if err := json.Unmarshal([]byte(dagJSON), &dag); err != nil {
+		return nil, nil, a.err.New("解析DAG失败", err)
+	}
+	inputSchema, err := marshalDefSchema(dag.InputSchema)
+	if err != nil {
+		return nil, nil, a.err.New("序列化 input_schema 失败", err)
+	}
+	stateSchema, err := marshalDefSchema(dag.StateSchema)
+	if err != nil {
+		return nil, nil, a.err.New("序列化 state_schema 失败", err)
+	}
+	def := &model.WorkflowDefModel{
+		DAGJSON:     dagJSON,
+		InputSchema: inputSchema,
+		StateSchema: stateSchema,
+	}
+	return def, &dag, nil
+}
+
 // GetInstance 获取工作流实例
 func (a *App) GetInstance(ctx context.Context, instanceID int64) (*model.WorkflowInstanceModel, error) {
 	return a.InstanceService.FindById(ctx, instanceID)
@@ -162,6 +212,24 @@ func (a *App) OnJobCompleted(ctx context.Context, jobID uint64, callbackData, re
 		return a.err.New("callback_data 格式无效", err).WithTraceID(ctx)
 	}
 
+	// 审批截止唤醒任务到期：按升级或失败路径推进
+	if isDeadline, _ := data["approval_deadline"].(bool); isDeadline {
+		approvalID, _ := configInt(data, "approval_id")
+		return a.handleApprovalDeadline(ctx, int64(jobID), int64(approvalID), instanceID, nodeID, callbackEnv)
+	}
+
+	// 信号等待超时：按 timeout 边或失败路径推进
+	if isSignalTimeout, _ := data["signal_timeout"].(bool); isSignalTimeout {
+		waitID, _ := configInt(data, "wait_id")
+		return a.handleSignalWaitTimeout(ctx, int64(jobID), int64(waitID), instanceID, nodeID, callbackEnv)
+	}
+
+	// 节点超时唤醒任务到期：不携带业务输出，按超时语义推进
+	if isTimeout, _ := data["timeout"].(bool); isTimeout {
+		nonce, _ := data["nonce"].(string)
+		return a.handleNodeTimeout(ctx, int64(jobID), instanceID, nodeID, callbackEnv, nonce)
+	}
+
 	var output map[string]interface{}
 	if resultJSON != "" {
 		if parseErr := json.Unmarshal([]byte(resultJSON), &output); parseErr != nil {
@@ -173,6 +241,16 @@ func (a *App) OnJobCompleted(ctx context.Context, jobID uint64, callbackData, re
 		output = make(map[string]interface{})
 	}
 
+	// 补偿任务回调：推进补偿计划而非节点
+	if isCompensate, _ := data["compensate"].(bool); isCompensate {
+		step, _ := configInt(data, "step")
+		return a.handleCompensationStep(ctx, int64(jobID), instanceID, nodeID, step, output, callbackEnv)
+	}
+
+	// Map 子任务回调：携带派发批次，推进时丢弃上一轮的迟到回调
+	if wave, _ := data["wave"].(string); wave != "" {
+		ctx = withMapWave(ctx, wave)
+	}
 	if err := a.ReportNodeCompletedFromJob(ctx, int64(jobID), instanceID, nodeID, output, callbackEnv, subJobID); err != nil {
 		a.log.WithErr(err).Errorf("任务 %d 回调 ReportNodeCompleted 失败", jobID)
 		return err
@@ -0,0 +1,59 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/xsxdot/aio/base"
+	"github.com/xsxdot/aio/system/executor"
+	"github.com/xsxdot/aio/system/workflow/internal/dao"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	"github.com/xsxdot/aio/system/workflow/internal/service"
+	"github.com/xsxdot/gokit/logger"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// newTestApp 创建按 module.go 完整装配的 App：每个测试独占一个内存 sqlite，建好全部工作流表
+// （model.AllModels）与 executor 表，并接入真实的进程内 executor，使任务的派发、取消都落到
+// aio_executor_jobs 表中可直接断言。新增表或服务时只需改这里，各功能的测试无需感知。
+func newTestApp(t *testing.T) (*App, *gorm.DB) {
+	t.Helper()
+	dbName := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	db, err := gorm.Open(sqlite.Open("file:"+dbName+"?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(model.AllModels()...); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	log := logger.GetLogger()
+	if err := executor.AutoMigrate(db, log); err != nil {
+		t.Fatalf("migrate executor: %v", err)
+	}
+	prev := base.DB
+	base.DB = db
+	t.Cleanup(func() { base.DB = prev })
+	if base.Logger == nil {
+		base.Logger = log
+	}
+
+	a := NewApp(
+		service.NewWorkflowDefService(dao.NewWorkflowDefDao(db, log), log),
+		service.NewWorkflowInstanceService(dao.NewWorkflowInstanceDao(db, log), log),
+		service.NewWorkflowCheckpointService(dao.NewWorkflowCheckpointDao(db, log), log),
+		executor.NewModule().Client,
+		dao.NewWorkflowAppliedCallbackDao(db, log),
+	)
+	a.ScheduleService = service.NewWorkflowScheduleService(dao.NewWorkflowScheduleDao(db, log), log)
+	a.ScheduleRunService = service.NewWorkflowScheduleRunService(dao.NewWorkflowScheduleRunDao(db, log), log)
+	a.TriggerService = service.NewWorkflowTriggerService(dao.NewWorkflowTriggerDao(db, log), dao.NewWorkflowTriggerDeliveryDao(db, log), log)
+	a.EventService = service.NewWorkflowInstanceEventService(dao.NewWorkflowInstanceEventDao(db, log), log)
+	a.ApprovalService = service.NewWorkflowApprovalService(dao.NewWorkflowApprovalDao(db, log), dao.NewWorkflowApprovalDecisionDao(db, log), log)
+	a.MigrationService = service.NewWorkflowInstanceMigrationService(dao.NewWorkflowInstanceMigrationDao(db, log), log)
+	a.SignalWaitService = service.NewWorkflowSignalWaitService(dao.NewWorkflowSignalWaitDao(db, log), log)
+	a.BusinessKeyService = service.NewWorkflowBusinessKeyService(dao.NewWorkflowBusinessKeyDao(db, log), log)
+	a.RetentionService = service.NewWorkflowRetentionService(dao.NewWorkflowRetentionPolicyDao(db, log), dao.NewWorkflowArchiveDao(db, log), log)
+	a.NodeRunService = service.NewWorkflowNodeRunService(dao.NewWorkflowNodeRunDao(db, log), log)
+	return a, db
+}
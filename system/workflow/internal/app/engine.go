@@ -3,7 +3,9 @@ package app
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -190,12 +192,14 @@ func setValueAtPath(data map[string]interface{}, path string, value interface{})
 	cur[parts[len(parts)-1]] = value
 }
 
-// handleMapSubTaskCompleted 处理 Map 节点的子任务回调，聚合结果，计数器归零时写 output_path 并触发下游
-func (a *App) handleMapSubTaskCompleted(tx *gorm.DB, instance *model.WorkflowInstanceModel, nodeID string, subID int, output map[string]interface{}, data workflowStateData, sys workflowStateSys, dag *model.DAG, nextNodeIDs *[]string) (bool, error) {
-	_, hasErrorMsg := output["error_msg"]
-	if hasErrorMsg {
-		return false, nil
-	}
+// handleMapSubTaskCompleted 处理 Map 节点的子任务回调，聚合结果，计数器归零时写 output_path 并触发下游。
+//
+// 失败的子任务在容忍范围内按完成处理：对应位置的结果记为 {"error_msg": ...}，并追加到
+// 逐项错误列表；超出容忍范围时返回 handled=false，由调用方按 Map 节点整体失败走 error 边。
+// 聚合结果写入后违反 state_schema 时返回 *SchemaValidationError，状态未改动，同样由调用方
+// 按整体失败处理。限流模式下每结束一个子任务即派发下一个待派发元素，其入参构造失败时返回
+// *nodeInputError，同样按整体失败处理。
+func (a *App) handleMapSubTaskCompleted(ctx context.Context, tx *gorm.DB, instance *model.WorkflowInstanceModel, def *model.WorkflowDefModel, nodeID string, subID int, output map[string]interface{}, data workflowStateData, sys workflowStateSys, dag *model.DAG, env string, nextNodeIDs *[]string) (bool, error) {
 	mapCounters, _ := sys["map_counters"].(map[string]interface{})
 	mapResults, _ := sys["map_results"].(map[string]interface{})
 	if mapCounters == nil || mapResults == nil {
@@ -221,25 +225,43 @@ func (a *App) handleMapSubTaskCompleted(tx *gorm.DB, instance *model.WorkflowIns
 	if !ok || resultsArr == nil || subID < 0 || subID >= len(resultsArr) {
 		return true, nil // 修复：无效结果也应吞掉，防止幽灵触发
 	}
+	if staleMapWave(ctx, sys, nodeID) {
+		a.log.WithField("instance_id", instance.ID).WithField("node_id", nodeID).WithField("sub_job_id", subID).
+			Warn("Map 子任务回调属于已结束的派发批次，丢弃")
+		return true, nil
+	}
+	node := dag.GetNode(nodeID)
+	if node == nil {
+		return true, nil
+	}
+	cfg, err := parseMapNodeConfig(node)
+	if err != nil {
+		return true, err
+	}
+	if errorMsg, hasErrorMsg := output["error_msg"]; hasErrorMsg {
+		if !recordMapItemFailure(cfg, sys, nodeID, subID, len(resultsArr), errorMsg) {
+			return false, nil
+		}
+		output = map[string]interface{}{"error_msg": errorMsg}
+	}
 	resultsArr[subID] = output
 	count--
 	mapCounters[nodeID] = count
-	newStateStr, _ := serializeWorkflowState(data, sys)
-	instance.CurrentState = newStateStr
 	if count > 0 {
+		if err := a.dispatchNextMapItem(ctx, instance, node, cfg, env, data, sys); err != nil {
+			return true, err
+		}
+		newStateStr, _ := serializeWorkflowState(data, sys)
+		instance.CurrentState = newStateStr
 		return true, tx.Save(instance).Error
 	}
-	node := dag.GetNode(nodeID)
-	if node == nil {
-		return true, nil
-	}
-	outputPath, _ := node.Config["output_path"].(string)
-	if outputPath != "" {
-		setValueAtPath(data, outputPath, resultsArr)
+	writeResults, checkpointOutput := mapAggregation(cfg, sys, nodeID, resultsArr)
+	if err := validateStateChange(def, data, writeResults); err != nil {
+		return false, err
 	}
-	delete(mapCounters, nodeID)
-	delete(mapResults, nodeID)
-	newStateStr, _ = serializeWorkflowState(data, sys)
+	writeResults(data)
+	clearMapNodeSys(sys, nodeID)
+	newStateStr, _ := serializeWorkflowState(data, sys)
 	instance.CurrentState = newStateStr
 	var activeNodes []string
 	if instance.ActiveNodeIDs != "" {
@@ -251,7 +273,7 @@ func (a *App) handleMapSubTaskCompleted(tx *gorm.DB, instance *model.WorkflowIns
 			newActiveNodes = append(newActiveNodes, n)
 		}
 	}
-	outputBytes, _ := json.Marshal(map[string]interface{}{"merged": resultsArr})
+	outputBytes, _ := json.Marshal(checkpointOutput)
 	if err := tx.Create(&model.WorkflowCheckpointModel{
 		InstanceID: instance.ID,
 		NodeID:     nodeID,
@@ -260,37 +282,11 @@ func (a *App) handleMapSubTaskCompleted(tx *gorm.DB, instance *model.WorkflowIns
 	}).Error; err != nil {
 		return true, err
 	}
-	outEdges := dag.GetOutgoingEdges(nodeID)
-	for _, edge := range outEdges {
-		if edge.Type == model.EdgeTypeError {
-			continue
-		}
-		pass, err := a.evaluateCondition(edge.Condition, data)
-		if err != nil {
-			a.log.WithErr(err).Errorf("评估 map 下游边 %s->%s 条件失败", edge.From, edge.To)
-			continue
-		}
-		if pass {
-			var cpCount int64
-			tx.Model(&model.WorkflowCheckpointModel{}).
-				Where("instance_id = ? AND node_id = ?", instance.ID, edge.To).
-				Count(&cpCount)
-			inActive := false
-			for _, an := range newActiveNodes {
-				if an == edge.To {
-					inActive = true
-					break
-				}
-			}
-			if edgeTargetEligibleForScheduling(edge, cpCount, inActive) {
-				if !allPredecessorsCompleted(tx, instance.ID, edge.To, dag) {
-					continue
-				}
-				*nextNodeIDs = append(*nextNodeIDs, edge.To)
-				newActiveNodes = append(newActiveNodes, edge.To)
-			}
-		}
+	edges := a.selectOutEdges(dag, nodeID, nodeOutcome{Node: node, Output: checkpointOutput}, data, sys)
+	if err := a.clearLoopbackCheckpoints(ctx, tx, instance.ID, edges); err != nil {
+		return true, err
 	}
+	a.scheduleEdges(ctx, tx, instance.ID, dag, env, edges, &newActiveNodes, nextNodeIDs)
 	activeNodesBytes, _ := json.Marshal(newActiveNodes)
 	instance.ActiveNodeIDs = string(activeNodesBytes)
 	if len(newActiveNodes) == 0 {
@@ -301,7 +297,7 @@ func (a *App) handleMapSubTaskCompleted(tx *gorm.DB, instance *model.WorkflowIns
 
 // allPredecessorsCompleted 检查目标节点的所有前驱（非 error 入边）是否都已完成（有 checkpoint）
 // 用于 Join Barrier：多入边汇聚时，只有所有前驱都完成才触发下游节点
-func allPredecessorsCompleted(tx *gorm.DB, instanceID int64, nodeID string, dag *model.DAG) bool {
+func allPredecessorsCompleted(cps checkpointLog, nodeID string, dag *model.DAG) bool {
 	inEdges := dag.GetIncomingEdges(nodeID)
 	for _, edge := range inEdges {
 		if edge.Type == model.EdgeTypeError {
@@ -313,11 +309,7 @@ func allPredecessorsCompleted(tx *gorm.DB, instanceID int64, nodeID string, dag
 		if edge.Condition != "" {
 			continue // 条件边属于互斥路由路径，不要求所有分支前驱都完成
 		}
-		var cpCount int64
-		tx.Model(&model.WorkflowCheckpointModel{}).
-			Where("instance_id = ? AND node_id = ?", instanceID, edge.From).
-			Count(&cpCount)
-		if cpCount == 0 {
+		if cps.count(edge.From) == 0 {
 			return false
 		}
 	}
@@ -346,7 +338,16 @@ func (a *App) StartWorkflow(ctx context.Context, defCode string, initialData map
 	if err != nil {
 		return 0, err
 	}
+	return a.startInstance(ctx, def, initialData, env, 0, "", "")
+}
 
+// startInstance 基于已确定的定义版本创建实例并触发起始节点。
+// parentInstanceID/parentNodeID 非零时表示由 subworkflow 节点拉起的子实例；
+// businessKey 只随实例落库，唯一性由调用方保证（见 StartWorkflowWithBusinessKey）。
+func (a *App) startInstance(ctx context.Context, def *model.WorkflowDefModel, initialData map[string]interface{}, env string, parentInstanceID int64, parentNodeID, businessKey string) (int64, error) {
+	if err := validateInitialInput(def, initialData); err != nil {
+		return 0, a.err.New("初始输入校验失败: "+err.Error(), err).WithCode(errorc.ErrorCodeValid)
+	}
 	var dag model.DAG
 	if err := json.Unmarshal([]byte(def.DAGJSON), &dag); err != nil {
 		return 0, a.err.New("解析DAG失败", err)
@@ -373,13 +374,16 @@ func (a *App) StartWorkflow(ctx context.Context, defCode string, initialData map
 	}
 
 	instance := &model.WorkflowInstanceModel{
-		DefID:         def.ID,
-		DefVersion:    def.Version,
-		Env:           env,
-		Status:        model.InstanceStatusRunning,
-		InitialState:  stateStr,
-		CurrentState:  stateStr,
-		ActiveNodeIDs: string(activeNodesJSON),
+		DefID:            def.ID,
+		DefVersion:       def.Version,
+		Env:              env,
+		Status:           model.InstanceStatusRunning,
+		InitialState:     stateStr,
+		CurrentState:     stateStr,
+		ActiveNodeIDs:    string(activeNodesJSON),
+		ParentInstanceID: parentInstanceID,
+		ParentNodeID:     parentNodeID,
+		BusinessKey:      businessKey,
 	}
 
 	if err := a.InstanceService.Create(ctx, instance); err != nil {
@@ -393,6 +397,8 @@ func (a *App) StartWorkflow(ctx context.Context, defCode string, initialData map
 			instance.Status = model.InstanceStatusFailed
 			if _, updErr := a.InstanceService.UpdateById(ctx, instance.ID, instance); updErr != nil {
 				a.log.WithErr(updErr).Errorf("更新实例状态为 FAILED 失败")
+			} else if evErr := a.recordInstanceEvent(ctx, instance, model.InstanceEventTerminal, "", nil); evErr != nil {
+				a.log.WithErr(evErr).Warn("记录实例终态事件失败")
 			}
 			return 0, a.err.New("触发起始节点失败: "+err.Error(), err)
 		}
@@ -411,7 +417,11 @@ func (a *App) StartWorkflow(ctx context.Context, defCode string, initialData map
 //
 // 注意：供 condition 节点递归与人工审批推进使用，这两类调用不来自可重试
 // 载体，无需幂等。来自 executor 回调的推进必须用 ReportNodeCompletedFromJob。
+// 配置了审批规则的审批节点不接受本入口，须经 DecideApproval 推进。
 func (a *App) ReportNodeCompleted(ctx context.Context, instanceID int64, nodeID string, output map[string]interface{}, env string, subJobID ...int) error {
+	if err := a.rejectManagedApprovalReport(ctx, instanceID, nodeID); err != nil {
+		return err
+	}
 	return a.reportNodeCompleted(ctx, 0, instanceID, nodeID, output, env, subJobID...)
 }
 
@@ -457,13 +467,60 @@ func (a *App) reportNodeCompleted(ctx context.Context, jobID int64, instanceID i
 	var instance model.WorkflowInstanceModel
 	var dag model.DAG
 	var skippedAsDuplicate bool
+	// statusBefore 为加锁读到的实例状态，用于判断本次推进是否让实例进入终态
+	var statusBefore model.WorkflowInstanceStatus
+	// stateBefore / nodeDone 用于生成实例事件：推进前的状态快照，以及本节点是否已落 checkpoint
+	var stateBefore string
+	var nodeDone, eventsRecorded bool
+	// recordEvents 在派发下游之前记录本次推进的事件与节点执行记录，使节点完成事件的序号先于下游触发事件
+	recordEvents := func(tx *gorm.DB) error {
+		if eventsRecorded || skippedAsDuplicate {
+			return nil
+		}
+		eventsRecorded = true
+		txCtx := mvc.WithTxToContext(ctx, tx)
+		if err := a.recordAdvanceEvents(txCtx, &instance, nodeID, nodeDone, output, stateBefore, statusBefore); err != nil {
+			return err
+		}
+		return a.recordNodeRunProgress(txCtx, &instance, &dag, nodeID, jobID, nodeDone, output, nextNodeIDs)
+	}
 
 	// 用 ExtractDB 而非 base.DB：triggerNode 对 condition 节点会递归回到本方法、
 	// 对 approval 节点会进入 updateInstanceStatusToWaitingWithLock，而外层事务
 	// 已对本实例行持有 FOR UPDATE。若这里另开事务，内层会等外层释放行锁、
 	// 外层又在等内层返回，必然死锁。ExtractDB 复用外层事务（SavePoint），
 	// 无外层事务时行为与 base.DB 完全一致。
-	err := mvc.ExtractDB(ctx, base.DB).Transaction(func(tx *gorm.DB) error {
+	// 事务透传：ExecutorClient 是进程内直调、executor DAO 全线走 mvc.ExtractDB，
+	// 因此把 tx 放进 ctx 后，下游任务的 INSERT 与本次状态推进落在同一个事务。
+	// 这消除了「状态已提交、任务未入库」的崩溃窗口——此前该窗口会让实例停在
+	// RUNNING 且 ActiveNodeIDs 非空，但没有任何 executor job 存在，永久卡死。
+	triggerNext := func(tx *gorm.DB) error {
+		if err := recordEvents(tx); err != nil {
+			return err
+		}
+		// 暂停中的实例只记录推进结果，后继节点待恢复时补派
+		if instance.Status == model.InstanceStatusPaused {
+			return a.deferPausedTriggers(tx, &instance, nextNodeIDs)
+		}
+		txCtx := mvc.WithTxToContext(ctx, tx)
+		for _, nextNodeID := range nextNodeIDs {
+			node := dag.GetNode(nextNodeID)
+			if node == nil {
+				continue
+			}
+			if err := a.triggerNode(txCtx, &instance, node, &dag, env); err != nil {
+				a.log.WithErr(err).
+					WithField("instance_id", instance.ID).
+					WithField("node_id", node.ID).
+					Error("触发后续节点失败，整体回滚本次推进")
+				// 返回错误让整个事务回滚：要么状态推进且下游已入库，要么什么都没发生。
+				// 由承载本次回调的 outbox 任务重试兜底，不再需要把实例置 FAILED。
+				return err
+			}
+		}
+		return nil
+	}
+	advance := func(tx *gorm.DB) error {
 		// 幂等登记必须在推进逻辑之前、且与推进同事务：承载回调的 outbox 任务
 		// 会重试，而 applyStateReducer 的 append 模式重放会重复追加输出，
 		// 造成状态污染（nova 的 Map 节点大量使用该模式汇聚结果）。
@@ -485,6 +542,9 @@ func (a *App) reportNodeCompleted(ctx context.Context, jobID int64, instanceID i
 		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", instanceID).First(&instance).Error; err != nil {
 			return err
 		}
+		statusBefore = instance.Status
+		stateBefore = instance.CurrentState
+		activeBefore := parseActiveNodeIDs(instance.ActiveNodeIDs)
 		if env == "" && instance.Env != "" {
 			env = instance.Env
 		}
@@ -495,8 +555,8 @@ func (a *App) reportNodeCompleted(ctx context.Context, jobID int64, instanceID i
 		// 检测是否为失败回调（带 error_msg）
 		_, hasErrorMsg := output["error_msg"]
 
-		// 如果实例已经失败/完成，且当前是成功回调，只记录 Checkpoint，不触发下游
-		if (instance.Status == model.InstanceStatusFailed || instance.Status == model.InstanceStatusCompleted || instance.Status == model.InstanceStatusCanceled) && !hasErrorMsg {
+		// 如果实例已经结束或正在补偿，且当前是成功回调，只记录 Checkpoint，不触发下游
+		if (isTerminalStatus(instance.Status) || instance.Status == model.InstanceStatusCompensating) && !hasErrorMsg {
 			a.log.WithField("instance_id", instanceID).
 				WithField("node_id", nodeID).
 				WithField("status", instance.Status).
@@ -524,6 +584,7 @@ func (a *App) reportNodeCompleted(ctx context.Context, jobID int64, instanceID i
 			}).Error; err != nil {
 				return err
 			}
+			nodeDone = true
 			// 更新 CurrentState（可选）
 			instance.CurrentState = newStateStr
 			if err := tx.Save(&instance).Error; err != nil {
@@ -532,7 +593,7 @@ func (a *App) reportNodeCompleted(ctx context.Context, jobID int64, instanceID i
 			return nil
 		}
 
-		if instance.Status != model.InstanceStatusRunning && instance.Status != model.InstanceStatusWaiting {
+		if instance.Status != model.InstanceStatusRunning && instance.Status != model.InstanceStatusWaiting && instance.Status != model.InstanceStatusPaused {
 			return fmt.Errorf("实例状态不允许推进: %s", instance.Status)
 		}
 		if instance.Status == model.InstanceStatusWaiting {
@@ -550,6 +611,9 @@ func (a *App) reportNodeCompleted(ctx context.Context, jobID int64, instanceID i
 		if sys == nil {
 			sys = make(workflowStateSys)
 		}
+		if instance.Status == model.InstanceStatusPaused {
+			markPausedAdvanced(sys)
+		}
 
 		var def model.WorkflowDefModel
 		if err := tx.Where("id = ?", instance.DefID).First(&def).Error; err != nil {
@@ -559,37 +623,93 @@ func (a *App) reportNodeCompleted(ctx context.Context, jobID int64, instanceID i
 			return fmt.Errorf("解析 DAG 失败: %w", err)
 		}
 		dag.Normalize()
+		// 实例迁移时改名的节点：在途任务仍以旧 ID 回调，翻译成新 ID 后按新 DAG 推进
+		nodeID = resolveNodeAlias(sys, &dag, nodeID)
 
 		// 3. Map 子任务回调：聚合结果，计数器归零时写 output_path 并触发下游
 		if subID >= 0 {
-			handled, err := a.handleMapSubTaskCompleted(tx, &instance, nodeID, subID, output, data, sys, &dag, &nextNodeIDs)
-			if err != nil {
+			handled, err := a.handleMapSubTaskCompleted(mvc.WithTxToContext(ctx, tx), tx, &instance, &def, nodeID, subID, output, data, sys, &dag, env, &nextNodeIDs)
+			var schemaErr *SchemaValidationError
+			var inputErr *nodeInputError
+			switch {
+			case errors.As(err, &schemaErr):
+				output = map[string]interface{}{"error_msg": "状态校验失败: " + schemaErr.Error()}
+				hasErrorMsg, handled = true, false
+			case errors.As(err, &inputErr):
+				output = map[string]interface{}{"error_msg": inputErr.Error()}
+				hasErrorMsg, handled = true, false
+			case err != nil:
 				return err
 			}
 			if handled {
-				return nil
+				nodeDone = containsString(activeBefore, nodeID) && !containsString(parseActiveNodeIDs(instance.ActiveNodeIDs), nodeID)
+				// Map 聚合完成时 nextNodeIDs 已填入下游，同样需要派发
+				return triggerNext(tx)
+			}
+		}
+
+		// output_mapping 求值失败按节点失败处理（走 error 边），而不是让承载回调的 outbox 任务无限重试
+		var projected map[string]interface{}
+		var loopRoute string
+		if !hasErrorMsg {
+			if node := dag.GetNode(nodeID); node != nil {
+				var p map[string]interface{}
+				var mErr error
+				switch node.Type {
+				case model.NodeTypeTransform:
+					// transform 节点在此基于加锁读到的状态求值，结果同时作为节点输出记录
+					if p, mErr = evalTransformNode(node.Config, data); mErr == nil {
+						output = p
+					}
+				case model.NodeTypeLoop:
+					// loop 节点在此推进轮次并决定走 body 出边还是退出边
+					loopRoute, mErr = advanceLoopNode(node, data, sys)
+				default:
+					p, _, mErr = projectNodeOutput(node.Config, data, output)
+				}
+				if mErr != nil {
+					output = map[string]interface{}{"error_msg": mErr.Error()}
+					hasErrorMsg = true
+				} else {
+					projected = p
+				}
+			}
+		}
+		// 合并后的状态违反 state_schema 同样按节点失败处理，违规结果不写入状态
+		if !hasErrorMsg {
+			var nodeConfig map[string]interface{}
+			if node := dag.GetNode(nodeID); node != nil {
+				nodeConfig = node.Config
+			}
+			if sErr := validateMergedState(&def, data, output, nodeConfig, projected); sErr != nil {
+				output = map[string]interface{}{"error_msg": "状态校验失败: " + sErr.Error()}
+				hasErrorMsg = true
 			}
 		}
 
 		// 4. 检测是否为 Executor 最终失败回调（带 error_msg），走 error 边或置实例 FAILED
 		if hasErrorMsg {
-			// 修复：如果是 Map 节点的子任务报错，立即清除 Latch 计数，防止幽灵并发
+			// 清除前先取出本批次号，熔断时只取消本批次的子任务
+			wave := currentMapWave(sys, nodeID)
+			// 修复：如果是 Map 节点的子任务报错（或 Map 节点整体超时），立即清除 Latch 计数，防止幽灵并发
+			if subID >= 0 || sys["map_counters"] != nil {
+				clearMapNodeSys(sys, nodeID)
+			}
 			if subID >= 0 {
-				if counters, ok := sys["map_counters"].(map[string]interface{}); ok {
-					delete(counters, nodeID)
-				}
-				if results, ok := sys["map_results"].(map[string]interface{}); ok {
-					delete(results, nodeID)
+				// 超出失败容忍：其余在途子任务的结果已无意义，取消以释放 executor 队列
+				prefix := mapSubDedupPrefix(instance.ID, nodeID, wave)
+				if err := a.ExecutorClient.CancelActiveJobsByDedupKeyPrefix(mvc.WithTxToContext(ctx, tx), env, prefix); err != nil {
+					a.log.WithErr(err).WithField("instance_id", instance.ID).WithField("node_id", nodeID).
+						Warn("Map 节点熔断后取消在途子任务失败")
 				}
 			}
-			outEdges := dag.GetOutgoingEdges(nodeID)
-			var errorEdges []model.Edge
-			for _, e := range outEdges {
+			hasFailureEdges := false
+			for _, e := range dag.GetOutgoingEdges(nodeID) {
 				if e.Type == model.EdgeTypeError || e.Type == model.EdgeTypeAlways {
-					errorEdges = append(errorEdges, e)
+					hasFailureEdges = true
 				}
 			}
-			if len(errorEdges) == 0 {
+			if !hasFailureEdges {
 				instance.Status = model.InstanceStatusFailed
 				activeNodesBytes, _ := json.Marshal([]string{})
 				instance.ActiveNodeIDs = string(activeNodesBytes)
@@ -600,6 +720,7 @@ func (a *App) reportNodeCompleted(ctx context.Context, jobID int64, instanceID i
 					NodeOutput: string(outputBytes),
 					StateAfter: instance.CurrentState,
 				}).Error
+				nodeDone = true
 				if err := tx.Save(&instance).Error; err != nil {
 					return err
 				}
@@ -622,33 +743,8 @@ func (a *App) reportNodeCompleted(ctx context.Context, jobID int64, instanceID i
 				}
 			}
 
-			for _, edge := range errorEdges {
-				pass, err := a.evaluateCondition(edge.Condition, data)
-				if err != nil {
-					a.log.WithErr(err).Errorf("评估 error 边 %s->%s 条件失败", edge.From, edge.To)
-					continue
-				}
-				if pass {
-					var cpCount int64
-					tx.Model(&model.WorkflowCheckpointModel{}).
-						Where("instance_id = ? AND node_id = ?", instance.ID, edge.To).
-						Count(&cpCount)
-					inActive := false
-					for _, an := range newActiveNodes {
-						if an == edge.To {
-							inActive = true
-							break
-						}
-					}
-					if edgeTargetEligibleForScheduling(edge, cpCount, inActive) {
-						if !allPredecessorsCompleted(tx, instance.ID, edge.To, &dag) {
-							continue
-						}
-						nextNodeIDs = append(nextNodeIDs, edge.To)
-						newActiveNodes = append(newActiveNodes, edge.To)
-					}
-				}
-			}
+			edges := a.selectOutEdges(&dag, nodeID, nodeOutcome{Node: dag.GetNode(nodeID), Output: output, Failed: true}, data, sys)
+			a.scheduleEdges(mvc.WithTxToContext(ctx, tx), tx, instance.ID, &dag, env, edges, &newActiveNodes, &nextNodeIDs)
 
 			outputBytes, _ := json.Marshal(output)
 			if err := tx.Create(&model.WorkflowCheckpointModel{
@@ -659,6 +755,7 @@ func (a *App) reportNodeCompleted(ctx context.Context, jobID int64, instanceID i
 			}).Error; err != nil {
 				return err
 			}
+			nodeDone = true
 
 			activeNodesBytes, _ := json.Marshal(newActiveNodes)
 			instance.ActiveNodeIDs = string(activeNodesBytes)
@@ -669,7 +766,8 @@ func (a *App) reportNodeCompleted(ctx context.Context, jobID int64, instanceID i
 			if err := tx.Save(&instance).Error; err != nil {
 				return err
 			}
-			return nil
+			// error 边选中的降级节点与成功路径一样同事务派发
+			return triggerNext(tx)
 		}
 
 		// 4. 正常成功路径：使用 state_update_mode 合并状态，排除 error 边
@@ -678,7 +776,7 @@ func (a *App) reportNodeCompleted(ctx context.Context, jobID int64, instanceID i
 		if node != nil {
 			nodeConfig = node.Config
 		}
-		applyStateReducer(data, output, nodeConfig)
+		applyNodeOutput(data, output, nodeConfig, projected)
 		newStateStr, err := serializeWorkflowState(data, sys)
 		if err != nil {
 			return fmt.Errorf("序列化状态失败: %w", err)
@@ -707,85 +805,13 @@ func (a *App) reportNodeCompleted(ctx context.Context, jobID int64, instanceID i
 		}).Error; err != nil {
 			return err
 		}
+		nodeDone = true
 
-		// --- Loopback 边 checkpoint 清理 ---
-		// 当 loopback 边即将触发时，删除从回环目标到当前节点之间的旧 checkpoint，
-		// 使重跑节点完成后的 forward 边可以重新调度下游节点。
-		outEdges := dag.GetOutgoingEdges(nodeID)
-		var loopbackTargets []string
-		for _, edge := range outEdges {
-			if edge.Type == model.EdgeTypeError || !edge.IsLoopback {
-				continue
-			}
-			pass, err := a.evaluateCondition(edge.Condition, data)
-			if err != nil {
-				a.log.WithErr(err).Errorf("评估 loopback 边 %s->%s 条件失败", edge.From, edge.To)
-				continue
-			}
-			if pass {
-				loopbackTargets = append(loopbackTargets, edge.To)
-			}
-		}
-		if len(loopbackTargets) > 0 {
-			checkpoints, err := a.CheckpointService.ListByInstanceIDOrderByCreatedAscWithTx(ctx, tx, instance.ID)
-			if err != nil {
-				return fmt.Errorf("获取 checkpoint 列表失败: %w", err)
-			}
-			currentCpIndex := len(checkpoints) - 1 // 刚创建的当前节点 checkpoint
-			earliestLoopbackCpIndex := currentCpIndex
-			for _, target := range loopbackTargets {
-				for i := len(checkpoints) - 1; i >= 0; i-- {
-					if checkpoints[i].NodeID == target {
-						if i < earliestLoopbackCpIndex {
-							earliestLoopbackCpIndex = i
-						}
-						break
-					}
-				}
-			}
-			if earliestLoopbackCpIndex < currentCpIndex {
-				if err := a.CheckpointService.DeleteFromIndexRangeWithTx(ctx, tx, instance.ID, earliestLoopbackCpIndex, currentCpIndex); err != nil {
-					return fmt.Errorf("清理 loopback 下游 checkpoint 失败: %w", err)
-				}
-				a.log.WithFields(map[string]interface{}{
-					"instance_id": instance.ID,
-					"from_index":  earliestLoopbackCpIndex,
-					"to_index":    currentCpIndex,
-					"targets":     loopbackTargets,
-				}).Info("Loopback 边触发，已清理下游 checkpoint")
-			}
-		}
-
-		for _, edge := range outEdges {
-			if edge.Type == model.EdgeTypeError {
-				continue
-			}
-			pass, err := a.evaluateCondition(edge.Condition, data)
-			if err != nil {
-				a.log.WithErr(err).Errorf("评估边 %s->%s 条件失败", edge.From, edge.To)
-				continue
-			}
-			if pass {
-				var cpCount int64
-				tx.Model(&model.WorkflowCheckpointModel{}).
-					Where("instance_id = ? AND node_id = ?", instance.ID, edge.To).
-					Count(&cpCount)
-				inActive := false
-				for _, an := range newActiveNodes {
-					if an == edge.To {
-						inActive = true
-						break
-					}
-				}
-				if edgeTargetEligibleForScheduling(edge, cpCount, inActive) {
-					if !allPredecessorsCompleted(tx, instance.ID, edge.To, &dag) {
-						continue
-					}
-					nextNodeIDs = append(nextNodeIDs, edge.To)
-					newActiveNodes = append(newActiveNodes, edge.To)
-				}
-			}
+		edges := a.selectOutEdges(&dag, nodeID, nodeOutcome{Node: node, Output: output, LoopRoute: loopRoute}, data, sys)
+		if err := a.clearLoopbackCheckpoints(ctx, tx, instance.ID, edges); err != nil {
+			return err
 		}
+		a.scheduleEdges(mvc.WithTxToContext(ctx, tx), tx, instance.ID, &dag, env, edges, &newActiveNodes, &nextNodeIDs)
 
 		activeNodesBytes, _ := json.Marshal(newActiveNodes)
 		instance.ActiveNodeIDs = string(activeNodesBytes)
@@ -796,28 +822,33 @@ func (a *App) reportNodeCompleted(ctx context.Context, jobID int64, instanceID i
 		if err := tx.Save(&instance).Error; err != nil {
 			return err
 		}
-
-		// 事务透传：ExecutorClient 是进程内直调、executor DAO 全线走 mvc.ExtractDB，
-		// 因此把 tx 放进 ctx 后，下游任务的 INSERT 与本次状态推进落在同一个事务。
-		// 这消除了「状态已提交、任务未入库」的崩溃窗口——此前该窗口会让实例停在
-		// RUNNING 且 ActiveNodeIDs 非空，但没有任何 executor job 存在，永久卡死。
-		txCtx := mvc.WithTxToContext(ctx, tx)
-		for _, nextNodeID := range nextNodeIDs {
-			node := dag.GetNode(nextNodeID)
-			if node == nil {
-				continue
-			}
-			if err := a.triggerNode(txCtx, &instance, node, &dag, env); err != nil {
-				a.log.WithErr(err).
-					WithField("instance_id", instance.ID).
-					WithField("node_id", node.ID).
-					Error("触发后续节点失败，整体回滚本次推进")
-				// 返回错误让整个事务回滚：要么状态推进且下游已入库，要么什么都没发生。
-				// 由承载本次回调的 outbox 任务重试兜底，不再需要把实例置 FAILED。
+		return triggerNext(tx)
+	}
+	err := mvc.ExtractDB(ctx, base.DB).Transaction(func(tx *gorm.DB) error {
+		if err := advance(tx); err != nil {
+			return err
+		}
+		// 实例在本次推进中失败且有已完成节点声明了 compensate 时转入补偿，
+		// 此时实例尚未结束，不回报父实例
+		var compensating bool
+		if !skippedAsDuplicate && statusBefore != model.InstanceStatusFailed && instance.Status == model.InstanceStatusFailed {
+			started, err := a.beginCompensation(mvc.WithTxToContext(ctx, tx), tx, &instance, &dag, env)
+			if err != nil {
 				return err
 			}
+			compensating = started
+		}
+		// 未经 triggerNext 的路径（迟到回调、error 边收尾等）在此补记事件
+		if err := recordEvents(tx); err != nil {
+			return err
+		}
+		if compensating {
+			return a.recordCompensationStarted(mvc.WithTxToContext(ctx, tx), &instance)
+		}
+		// 子实例在本次推进中进入终态时，同事务回报父实例的 subworkflow 节点
+		if !isTerminalStatus(statusBefore) && isTerminalStatus(instance.Status) {
+			return a.notifyParentOfChildTerminal(mvc.WithTxToContext(ctx, tx), &instance)
 		}
-
 		return nil
 	})
 
@@ -854,11 +885,51 @@ func (a *App) reportNodeCompleted(ctx context.Context, jobID int64, instanceID i
 	return nil
 }
 
-// triggerNode 根据节点类型执行具体动作，env 用于 Executor 任务隔离
+// triggerNode 根据节点类型执行具体动作，env 用于 Executor 任务隔离。
+//
+// 触发事件、节点执行记录与派发在同一事务内：startInstance 触发起始节点时不在推进事务内，
+// 派发失败（含 Map 节点派发到一半）须连同已写的触发记录与已提交的任务一起回滚；
+// 已在推进事务内时为 SavePoint。入参构造失败（*nodeInputError）回滚派发后按节点失败推进，
+// 不向调用方返回错误。
 func (a *App) triggerNode(ctx context.Context, instance *model.WorkflowInstanceModel, node *model.Node, dag *model.DAG, env string) error {
 	if env == "" {
 		env = base.ENV
 	}
+	err := mvc.ExtractDB(ctx, base.DB).Transaction(func(tx *gorm.DB) error {
+		txCtx := mvc.WithTxToContext(ctx, tx)
+		if err := a.recordNodeTriggered(txCtx, instance, node, env); err != nil {
+			return err
+		}
+		return a.dispatchNode(txCtx, instance, node, dag, env)
+	})
+	var inputErr *nodeInputError
+	if !errors.As(err, &inputErr) {
+		return err
+	}
+	// 入参构造失败：派发的副作用已随上面的事务回滚，补记触发后以 error_msg 推进该节点
+	a.log.WithErr(err).
+		WithField("instance_id", instance.ID).
+		WithField("node_id", node.ID).
+		Warn("节点入参构造失败，按节点失败推进")
+	return mvc.ExtractDB(ctx, base.DB).Transaction(func(tx *gorm.DB) error {
+		txCtx := mvc.WithTxToContext(ctx, tx)
+		if err := a.recordNodeTriggered(txCtx, instance, node, env); err != nil {
+			return err
+		}
+		return a.ReportNodeCompleted(txCtx, instance.ID, node.ID, map[string]interface{}{"error_msg": inputErr.Error()}, env)
+	})
+}
+
+// recordNodeTriggered 记录节点触发事件与节点执行记录
+func (a *App) recordNodeTriggered(ctx context.Context, instance *model.WorkflowInstanceModel, node *model.Node, env string) error {
+	if err := a.recordInstanceEvent(ctx, instance, model.InstanceEventNodeTriggered, node.ID, map[string]interface{}{"node_type": node.Type}); err != nil {
+		return err
+	}
+	return a.recordNodeRunStarted(ctx, instance, node, env)
+}
+
+// dispatchNode 按节点类型派发任务或进入等待，同步节点直接推进
+func (a *App) dispatchNode(ctx context.Context, instance *model.WorkflowInstanceModel, node *model.Node, dag *model.DAG, env string) error {
 	switch node.Type {
 	case model.NodeTypeTask:
 		var serviceName, methodName string
@@ -877,42 +948,82 @@ func (a *App) triggerNode(ctx context.Context, instance *model.WorkflowInstanceM
 		payload := map[string]interface{}{
 			"instance_id": instance.ID,
 			"node_id":     node.ID,
-			"state":       stateObj,
 			"config":      node.Config,
 		}
+		data, sys, err := parseWorkflowState(instance.CurrentState)
+		if err != nil {
+			return a.err.New("解析状态失败", err)
+		}
+		// 处于循环体内时携带当前轮次，供 Worker 区分每一轮
+		if loop := currentLoop(sys); loop != nil {
+			payload["loop"] = loop
+		}
+		// 配置了 input_mapping 时只派发声明的入参，不再携带整份 state
+		input, mapped, err := buildNodeInput(node.Config, data, loopExprVars(sys))
+		if err != nil {
+			return a.err.New("节点 "+node.ID+" 输入映射失败", &nodeInputError{msg: "输入映射失败", err: err})
+		}
+		if mapped {
+			payload["input"] = input
+		} else {
+			payload["state"] = stateObj
+		}
 		payloadBytes, _ := json.Marshal(payload)
 		callbackDataBytes, _ := json.Marshal(map[string]interface{}{"instance_id": instance.ID, "node_id": node.ID, "env": env})
 		callbackData := string(callbackDataBytes)
 		// 后缀保证回环重跑时与已成功任务的幂等键区分，Executor 不会对 succeeded 任务用新 Args 重新入队
-		dedupKey := fmt.Sprintf("wf_%d_node_%s_%d", instance.ID, node.ID, time.Now().UnixNano())
-
-		_, err := a.ExecutorClient.SubmitJob(ctx, &executorDto.SubmitJobInput{
-			Env:              env,
-			TargetService:    serviceName,
-			Method:           methodName,
-			ArgsJSON:         string(payloadBytes),
-			RunAt:            0,
-			MaxAttempts:      3,
-			Priority:         0,
-			DedupKey:         dedupKey,
-			RetryBackoffType: executorDto.RetryBackoffExponential,
-			Source:           "workflow",
-			CallbackData:     callbackData,
-		})
+		dedupKey := nodeDedupPrefix(instance.ID, node.ID) + strconv.FormatInt(time.Now().UnixNano(), 10)
+
+		policy, err := parseNodeJobPolicy(node.Config)
 		if err != nil {
+			return a.err.New("节点 "+node.ID+" 投递策略配置无效", err)
+		}
+		jobInput := &executorDto.SubmitJobInput{
+			Env:           env,
+			TargetService: serviceName,
+			Method:        methodName,
+			ArgsJSON:      string(payloadBytes),
+			RunAt:         0,
+			DedupKey:      dedupKey,
+			Source:        "workflow",
+			CallbackData:  callbackData,
+		}
+		policy.applyTo(jobInput)
+		if _, err := a.ExecutorClient.SubmitJob(ctx, jobInput); err != nil {
 			return a.err.New("提交任务到Executor失败", err)
 		}
+		if policy.Timeout > 0 {
+			return a.scheduleNodeTimeout(ctx, instance, node.ID, env, policy.Timeout)
+		}
 
 	case model.NodeTypeApproval:
-		// 等待外部接口调用 ReportNodeCompleted 推进，使用事务+行锁与 ReportNodeCompleted 并发安全
-		return a.updateInstanceStatusToWaitingWithLock(ctx, instance.ID)
+		return a.triggerApprovalNode(ctx, instance, node, env)
 
 	case model.NodeTypeCondition:
 		// 路由网关节点，不执行实际操作，立即计算后续分支
 		return a.ReportNodeCompleted(ctx, instance.ID, node.ID, nil, env)
 
+	case model.NodeTypeTransform:
+		// 表达式在推进事务内求值并写入状态，随即计算后续分支
+		return a.ReportNodeCompleted(ctx, instance.ID, node.ID, nil, env)
+
+	case model.NodeTypeLoop:
+		return a.triggerLoopNode(ctx, instance, node, env)
+
 	case model.NodeTypeMap:
 		return a.triggerMapNode(ctx, instance, node, dag, env)
+
+	case model.NodeTypeSubWorkflow:
+		return a.triggerSubWorkflowNode(ctx, instance, node, env)
+
+	case model.NodeTypeTimer:
+		return a.triggerTimerNode(ctx, instance, node, env)
+
+	case model.NodeTypeHTTP:
+		return a.triggerHTTPNode(ctx, instance, node, env)
+
+	case model.NodeTypeWaitSignal:
+		return a.triggerWaitSignalNode(ctx, instance, node, env)
 	}
 	return nil
 }
@@ -922,9 +1033,9 @@ func (a *App) triggerMapNode(ctx context.Context, instance *model.WorkflowInstan
 	if env == "" {
 		env = base.ENV
 	}
-	itemsPath, _ := node.Config["items_path"].(string)
-	if itemsPath == "" {
-		return a.err.New("Map 节点缺少 items_path 配置", nil)
+	cfg, err := parseMapNodeConfig(node)
+	if err != nil {
+		return a.err.New(err.Error(), err)
 	}
 	data, sys, err := parseWorkflowState(instance.CurrentState)
 	if err != nil {
@@ -936,47 +1047,27 @@ func (a *App) triggerMapNode(ctx context.Context, instance *model.WorkflowInstan
 	if sys == nil {
 		sys = make(workflowStateSys)
 	}
-	itemsRaw := getValueAtPath(data, itemsPath)
+	itemsRaw := getValueAtPath(data, cfg.ItemsPath)
 	itemsArr, ok := itemsRaw.([]interface{})
 	if !ok || len(itemsArr) == 0 {
-		return a.err.New("Map 节点 items_path 对应值非数组或为空", nil)
+		return a.err.New("Map 节点 items_path 对应值非数组或为空", &nodeInputError{msg: "items_path 对应值非数组或为空: " + cfg.ItemsPath})
 	}
 	N := len(itemsArr)
-	iteratorConf, _ := node.Config["iterator"].(map[string]interface{})
-	var serviceName, methodName string
-	if iteratorConf != nil {
-		if s, ok := iteratorConf["service"].(string); ok {
-			serviceName = s
-		}
-		if m, ok := iteratorConf["method"].(string); ok {
-			methodName = m
-		}
-	}
-	if serviceName == "" || methodName == "" {
-		return a.err.New("Map 节点 iterator 缺少 service 或 method", nil)
-	}
-	itemAlias, _ := node.Config["item_alias"].(string)
-	if itemAlias == "" {
-		itemAlias = "item"
-	}
-	if mapCounters, ok := sys["map_counters"].(map[string]interface{}); ok {
-		if mapCounters == nil {
-			mapCounters = make(map[string]interface{})
-			sys["map_counters"] = mapCounters
-		}
-		mapCounters[node.ID] = N
-	} else {
-		sys["map_counters"] = map[string]interface{}{node.ID: N}
+	// 超时针对整个 Map 节点，取自节点配置；子任务的重试/优先级取自 iterator 块
+	nodePolicy, err := parseNodeJobPolicy(node.Config)
+	if err != nil {
+		return a.err.New("Map 节点投递策略配置无效", err)
 	}
-	results := make([]interface{}, N)
-	if mapResults, ok := sys["map_results"].(map[string]interface{}); ok {
-		if mapResults == nil {
-			mapResults = make(map[string]interface{})
-			sys["map_results"] = mapResults
-		}
-		mapResults[node.ID] = results
-	} else {
-		sys["map_results"] = map[string]interface{}{node.ID: results}
+	window := cfg.initialWindow(N)
+	mapWave := strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	clearMapNodeSys(sys, node.ID)
+	mapSysEntry(sys, "map_counters")[node.ID] = N
+	mapSysEntry(sys, "map_results")[node.ID] = make([]interface{}, N)
+	mapSysEntry(sys, "map_dispatch")[node.ID] = map[string]interface{}{"next": window, "failed": 0, "wave": mapWave}
+	if window < N {
+		// 限流时保存元素快照，后续派发不受 items_path 在执行期间被改写的影响
+		mapSysEntry(sys, "map_items")[node.ID] = itemsArr
 	}
 	newStateStr, err := serializeWorkflowState(data, sys)
 	if err != nil {
@@ -986,47 +1077,15 @@ func (a *App) triggerMapNode(ctx context.Context, instance *model.WorkflowInstan
 	if _, err := a.InstanceService.UpdateById(ctx, instance.ID, instance); err != nil {
 		return a.err.New("更新实例状态失败", err)
 	}
-	// 修复：将 CurrentState 从 JSON string 还原为 map，避免 double-encoding
-	var mapStateObj map[string]interface{}
-	if err := json.Unmarshal([]byte(instance.CurrentState), &mapStateObj); err != nil {
-		return a.err.New("解析 CurrentState 用于 Map 子任务派发失败", err)
-	}
-	mapWave := time.Now().UnixNano()
-	for i := 0; i < N; i++ {
-		item := itemsArr[i]
-		subPayload := map[string]interface{}{
-			"instance_id": instance.ID,
-			"node_id":     node.ID,
-			"state":       mapStateObj,
-			"config":      node.Config,
-			itemAlias:     item,
-		}
-		payloadBytes, _ := json.Marshal(subPayload)
-		callbackDataBytes, _ := json.Marshal(map[string]interface{}{
-			"instance_id": instance.ID,
-			"node_id":     node.ID,
-			"env":         env,
-			"sub_job_id":  i,
-		})
-		callbackData := string(callbackDataBytes)
-		dedupKey := fmt.Sprintf("wf_%d_node_%s_sub_%d_%d", instance.ID, node.ID, i, mapWave)
-		_, err := a.ExecutorClient.SubmitJob(ctx, &executorDto.SubmitJobInput{
-			Env:              env,
-			TargetService:    serviceName,
-			Method:           methodName,
-			ArgsJSON:         string(payloadBytes),
-			RunAt:            0,
-			MaxAttempts:      3,
-			Priority:         0,
-			DedupKey:         dedupKey,
-			RetryBackoffType: executorDto.RetryBackoffExponential,
-			Source:           "workflow",
-			CallbackData:     callbackData,
-		})
-		if err != nil {
-			return a.err.New("提交 Map 子任务到 Executor 失败", err)
+	mapStateObj := mapPayloadState(data, sys)
+	for i := 0; i < window; i++ {
+		if err := a.submitMapSubJob(ctx, instance, node, cfg, env, i, itemsArr[i], mapWave, data, mapStateObj); err != nil {
+			return err
 		}
 	}
+	if nodePolicy.Timeout > 0 {
+		return a.scheduleNodeTimeout(ctx, instance, node.ID, env, nodePolicy.Timeout)
+	}
 	return nil
 }
 
@@ -1056,7 +1115,7 @@ func filterStateForExpr(state map[string]interface{}) map[string]interface{} {
 }
 
 // updateInstanceStatusToWaitingWithLock 带事务和行锁更新实例状态为 WAITING，与 ReportNodeCompleted 并发安全
-func (a *App) updateInstanceStatusToWaitingWithLock(ctx context.Context, instanceID int64) error {
+func (a *App) updateInstanceStatusToWaitingWithLock(ctx context.Context, instanceID int64, nodeID string) error {
 	// 同 ReportNodeCompleted：本方法会被事务内的 triggerNode 调用，必须复用外层事务
 	return mvc.ExtractDB(ctx, base.DB).Transaction(func(tx *gorm.DB) error {
 		inst, err := a.InstanceService.FindByIdForUpdate(ctx, tx, instanceID)
@@ -1067,12 +1126,16 @@ func (a *App) updateInstanceStatusToWaitingWithLock(ctx context.Context, instanc
 			return fmt.Errorf("实例状态不允许设为 WAITING: %s", inst.Status)
 		}
 		inst.Status = model.InstanceStatusWaiting
-		return a.InstanceService.SaveWithTx(ctx, tx, inst)
+		if err := a.InstanceService.SaveWithTx(ctx, tx, inst); err != nil {
+			return err
+		}
+		return a.recordInstanceEvent(mvc.WithTxToContext(ctx, tx), inst, model.InstanceEventWaiting, nodeID, nil)
 	})
 }
 
-// evaluateCondition 评估表达式，仅传入过滤后的 state 降低敏感数据泄露风险
-func (a *App) evaluateCondition(condition string, state map[string]interface{}) (bool, error) {
+// evaluateCondition 评估表达式，仅传入过滤后的 state 降低敏感数据泄露风险。
+// vars 为额外注入的变量（如 loop），未注入 loop 时其为空对象
+func (a *App) evaluateCondition(condition string, state map[string]interface{}, vars ...map[string]interface{}) (bool, error) {
 	if condition == "" {
 		return true, nil
 	}
@@ -1080,6 +1143,12 @@ func (a *App) evaluateCondition(condition string, state map[string]interface{})
 	safeState := filterStateForExpr(state)
 	env := map[string]interface{}{
 		"state": safeState,
+		"loop":  map[string]interface{}{},
+	}
+	for _, v := range vars {
+		for k, val := range v {
+			env[k] = val
+		}
 	}
 
 	program, err := expr.Compile(condition, expr.Env(env))
@@ -1102,6 +1171,7 @@ func (a *App) RollbackToNode(ctx context.Context, instanceID int64, targetNodeID
 	var dag model.DAG
 	var stateToRestore string
 	var deleteFromIndex int = -1
+	var prevActiveNodes []string
 
 	// 统一走 ExtractDB：与其他事务入口保持一致，避免以后被移入事务后踩死锁
 	err := mvc.ExtractDB(ctx, base.DB).Transaction(func(tx *gorm.DB) error {
@@ -1170,14 +1240,23 @@ func (a *App) RollbackToNode(ctx context.Context, instanceID int64, targetNodeID
 		}
 
 		// 5. 更新实例状态
+		prevActiveNodes = parseActiveNodeIDs(instance.ActiveNodeIDs)
+		stateBefore := instance.CurrentState
 		instance.CurrentState = stateToRestore
 		instance.ActiveNodeIDs = fmt.Sprintf(`["%s"]`, targetNodeID)
 		instance.Status = model.InstanceStatusRunning
 		if err := a.InstanceService.SaveWithTx(ctx, tx, instance); err != nil {
 			return err
 		}
+		// 回滚前等待中的审批与信号已无意义；目标若是审批或 wait_signal 节点，重新触发时会重新登记
+		if err := a.cancelPendingApprovals(mvc.WithTxToContext(ctx, tx), instanceID); err != nil {
+			return err
+		}
+		if err := a.cancelSignalWaits(mvc.WithTxToContext(ctx, tx), instanceID); err != nil {
+			return err
+		}
 
-		return nil
+		return a.recordStateChanged(mvc.WithTxToContext(ctx, tx), instance, targetNodeID, stateBefore)
 	})
 
 	if err != nil {
@@ -1203,6 +1282,13 @@ func (a *App) RollbackToNode(ctx context.Context, instanceID int64, targetNodeID
 		_ = a.ExecutorClient.CancelJobByDedupKey(ctx, envToUse, prefix)
 		_ = a.ExecutorClient.CancelActiveJobsByDedupKeyPrefix(ctx, envToUse, prefix)
 	}
+	// 回滚前活跃节点的在途任务（含 timer 延时任务）与子实例已无意义，一并取消
+	for _, nodeID := range prevActiveNodes {
+		prefix := fmt.Sprintf("wf_%d_node_%s", instanceID, nodeID)
+		_ = a.ExecutorClient.CancelJobByDedupKey(ctx, envToUse, prefix)
+		_ = a.ExecutorClient.CancelActiveJobsByDedupKeyPrefix(ctx, envToUse, prefix)
+		a.cancelChildInstances(ctx, instanceID, nodeID)
+	}
 	node := dag.GetNode(targetNodeID)
 	if node != nil {
 		return a.triggerNode(ctx, instance, node, &dag, envToUse)
@@ -1217,13 +1303,26 @@ type ExecutionTrail struct {
 	CurrentState  string                     `json:"current_state"`
 	ActiveNodeIDs string                     `json:"active_node_ids"`
 	Checkpoints   []ExecutionTrailCheckpoint `json:"checkpoints"`
+	// ParentInstanceID / ParentNodeID 非零表示本实例由父实例的 subworkflow 节点拉起
+	ParentInstanceID int64                 `json:"parent_instance_id,omitempty"`
+	ParentNodeID     string                `json:"parent_node_id,omitempty"`
+	Children         []ExecutionTrailChild `json:"children,omitempty"`
+}
+
+// ExecutionTrailChild 本实例 subworkflow 节点拉起的子实例
+type ExecutionTrailChild struct {
+	InstanceID int64  `json:"instance_id"`
+	NodeID     string `json:"node_id"`
+	Status     string `json:"status"`
+	CreatedAt  string `json:"created_at"`
 }
 
 type ExecutionTrailCheckpoint struct {
-	NodeID     string                 `json:"node_id"`
-	NodeOutput map[string]interface{} `json:"node_output"`
-	StateAfter map[string]interface{} `json:"state_after,omitempty"`
-	CreatedAt  string                 `json:"created_at"`
+	CheckpointID int64                  `json:"checkpoint_id"`
+	NodeID       string                 `json:"node_id"`
+	NodeOutput   map[string]interface{} `json:"node_output"`
+	StateAfter   map[string]interface{} `json:"state_after,omitempty"`
+	CreatedAt    string                 `json:"created_at"`
 }
 
 type ExecutionTrailOptions struct {
@@ -1280,19 +1379,37 @@ func (a *App) GetExecutionTrailWithOptions(ctx context.Context, instanceID int64
 			stateAfter = make(map[string]interface{})
 		}
 		trail = append(trail, ExecutionTrailCheckpoint{
-			NodeID:     cp.NodeID,
-			NodeOutput: nodeOutput,
-			StateAfter: stateAfter,
-			CreatedAt:  cp.CreatedAt.Format(time.RFC3339),
+			CheckpointID: cp.ID,
+			NodeID:       cp.NodeID,
+			NodeOutput:   nodeOutput,
+			StateAfter:   stateAfter,
+			CreatedAt:    cp.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	childModels, err := a.InstanceService.ListChildren(ctx, instanceID, "")
+	if err != nil {
+		return nil, err
+	}
+	children := make([]ExecutionTrailChild, 0, len(childModels))
+	for _, c := range childModels {
+		children = append(children, ExecutionTrailChild{
+			InstanceID: c.ID,
+			NodeID:     c.ParentNodeID,
+			Status:     string(c.Status),
+			CreatedAt:  c.CreatedAt.Format(time.RFC3339),
 		})
 	}
 
 	return &ExecutionTrail{
-		InstanceID:    instance.ID,
-		Status:        string(instance.Status),
-		CurrentState:  instance.CurrentState,
-		ActiveNodeIDs: instance.ActiveNodeIDs,
-		Checkpoints:   trail,
+		InstanceID:       instance.ID,
+		Status:           string(instance.Status),
+		CurrentState:     instance.CurrentState,
+		ActiveNodeIDs:    instance.ActiveNodeIDs,
+		Checkpoints:      trail,
+		ParentInstanceID: instance.ParentInstanceID,
+		ParentNodeID:     instance.ParentNodeID,
+		Children:         children,
 	}, nil
 }
 
@@ -1329,19 +1446,28 @@ func (a *App) GetExecutionState(ctx context.Context, instanceID int64, nodeID st
 	}, nil
 }
 
-// CancelInstance 取消运行中的实例（仅 RUNNING/WAITING 可取消）
+// CancelInstance 取消运行中的实例（仅 RUNNING/WAITING/PAUSED 可取消）
 func (a *App) CancelInstance(ctx context.Context, instanceID int64) error {
 	instance, err := a.InstanceService.FindById(ctx, instanceID)
 	if err != nil {
 		return a.err.New("获取实例失败", err)
 	}
-	if instance.Status != model.InstanceStatusRunning && instance.Status != model.InstanceStatusWaiting {
-		return a.err.New("只有运行中或等待中的实例才能取消", nil).WithCode(errorc.ErrorCodeValid)
+	if instance.Status != model.InstanceStatusRunning && instance.Status != model.InstanceStatusWaiting && instance.Status != model.InstanceStatusPaused {
+		return a.err.New("只有运行中、等待中或已暂停的实例才能取消", nil).WithCode(errorc.ErrorCodeValid)
 	}
 	instance.Status = model.InstanceStatusCanceled
 	if _, err := a.InstanceService.UpdateById(ctx, instance.ID, instance); err != nil {
 		return a.err.New("更新实例状态失败", err)
 	}
+	if err := a.recordInstanceEvent(ctx, instance, model.InstanceEventTerminal, "", nil); err != nil {
+		a.log.WithErr(err).WithField("instance_id", instanceID).Warn("记录实例取消事件失败")
+	}
+	if err := a.cancelPendingApprovals(ctx, instanceID); err != nil {
+		a.log.WithErr(err).WithField("instance_id", instanceID).Warn("作废实例审批待办失败")
+	}
+	if err := a.cancelSignalWaits(ctx, instanceID); err != nil {
+		a.log.WithErr(err).WithField("instance_id", instanceID).Warn("作废实例信号等待失败")
+	}
 	env := instance.Env
 	if env == "" {
 		env = base.ENV
@@ -1354,6 +1480,14 @@ func (a *App) CancelInstance(ctx context.Context, instanceID int64) error {
 		prefix := fmt.Sprintf("wf_%d_node_%s", instanceID, nodeID)
 		_ = a.ExecutorClient.CancelJobByDedupKey(ctx, env, prefix)
 		_ = a.ExecutorClient.CancelActiveJobsByDedupKeyPrefix(ctx, env, prefix)
+		a.cancelChildInstances(ctx, instanceID, nodeID)
+	}
+	// 被直接取消的子实例需要回报父节点，父实例已取消时会被跳过；级联取消由父侧自行推进
+	if isCascadeCancel(ctx) {
+		return nil
+	}
+	if err := a.notifyParentOfChildTerminal(ctx, instance); err != nil {
+		a.log.WithErr(err).WithField("instance_id", instanceID).Warn("子工作流取消后回报父实例失败")
 	}
 	return nil
 }
@@ -1375,6 +1509,12 @@ func (a *App) RetryNode(ctx context.Context, instanceID int64, nodeID string) er
 		if instance.Status != model.InstanceStatusFailed {
 			return fmt.Errorf("只有失败状态的实例才能重试节点: %s", instance.Status)
 		}
+		// 补偿过的实例已撤销副作用，不能再从中途重试
+		if _, sys, _ := parseWorkflowState(instance.CurrentState); sys != nil {
+			if _, compensated := loadCompensationPlan(sys); compensated {
+				return fmt.Errorf("实例已执行过补偿，不能重试节点")
+			}
+		}
 		def, err := a.DefService.FindByIdWithTx(ctx, tx, instance.DefID)
 		if err != nil {
 			return err
@@ -1419,10 +1559,14 @@ func (a *App) RetryNode(ctx context.Context, instanceID int64, nodeID string) er
 				return err
 			}
 		}
+		stateBefore := instance.CurrentState
 		instance.CurrentState = stateToRestore
 		instance.ActiveNodeIDs = fmt.Sprintf(`["%s"]`, nodeID)
 		instance.Status = model.InstanceStatusRunning
-		return a.InstanceService.SaveWithTx(ctx, tx, instance)
+		if err := a.InstanceService.SaveWithTx(ctx, tx, instance); err != nil {
+			return err
+		}
+		return a.recordStateChanged(mvc.WithTxToContext(ctx, tx), instance, nodeID, stateBefore)
 	})
 	if err != nil {
 		return a.err.New("重试节点失败", err)
@@ -1438,8 +1582,19 @@ func (a *App) RetryNode(ctx context.Context, instanceID int64, nodeID string) er
 	return nil
 }
 
-// SendSignal 接收外部信号，合并 Payload 入 state，可选唤醒指定节点继续执行
+// SendSignal 接收外部信号，合并 Payload 入 state，可选唤醒指定节点继续执行。
+// 未指定唤醒节点且实例有 wait_signal 节点正在等待该信号时，信号投递给这些节点并推进（见 engine_signal.go）
 func (a *App) SendSignal(ctx context.Context, instanceID int64, signalName string, payload map[string]interface{}, wakeupNode string, env string) error {
+	if wakeupNode == "" && a.SignalWaitService != nil {
+		waits, err := a.SignalWaitService.ListWaitingByInstance(ctx, instanceID, signalName)
+		if err != nil {
+			return err
+		}
+		if len(waits) > 0 {
+			_, err := a.deliverSignalToWaits(ctx, waits, payload)
+			return err
+		}
+	}
 	var instance model.WorkflowInstanceModel
 	var dag model.DAG
 
@@ -1448,6 +1603,7 @@ func (a *App) SendSignal(ctx context.Context, instanceID int64, signalName strin
 		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", instanceID).First(&instance).Error; err != nil {
 			return err
 		}
+		stateBefore, statusBefore := instance.CurrentState, instance.Status
 		if instance.Status != model.InstanceStatusCompleted && instance.Status != model.InstanceStatusWaiting {
 			return fmt.Errorf("只有 COMPLETED 或 WAITING 状态的实例才能接收信号: %s", instance.Status)
 		}
@@ -1469,9 +1625,19 @@ func (a *App) SendSignal(ctx context.Context, instanceID int64, signalName strin
 			sys = make(workflowStateSys)
 		}
 
-		for k, v := range payload {
-			data[k] = v
+		var def model.WorkflowDefModel
+		if err := tx.Where("id = ?", instance.DefID).First(&def).Error; err != nil {
+			return err
+		}
+		mergePayload := func(d workflowStateData) {
+			for k, v := range payload {
+				d[k] = v
+			}
+		}
+		if err := validateStateChange(&def, data, mergePayload); err != nil {
+			return err
 		}
+		mergePayload(data)
 		newStateStr, err := serializeWorkflowState(data, sys)
 		if err != nil {
 			return err
@@ -1497,10 +1663,6 @@ func (a *App) SendSignal(ctx context.Context, instanceID int64, signalName strin
 		}
 
 		if wakeupNode != "" {
-			var def model.WorkflowDefModel
-			if err := tx.Where("id = ?", instance.DefID).First(&def).Error; err != nil {
-				return err
-			}
 			if err := json.Unmarshal([]byte(def.DAGJSON), &dag); err != nil {
 				return fmt.Errorf("解析 DAG 失败: %w", err)
 			}
@@ -1513,9 +1675,29 @@ func (a *App) SendSignal(ctx context.Context, instanceID int64, signalName strin
 			// 优化：节点已触发并投递给 Executor，语义上应为 RUNNING
 			instance.Status = model.InstanceStatusRunning
 		}
-		return tx.Save(&instance).Error
+		if err := tx.Save(&instance).Error; err != nil {
+			return err
+		}
+		txCtx := mvc.WithTxToContext(ctx, tx)
+		if err := a.recordInstanceEvent(txCtx, &instance, model.InstanceEventSignalReceived, wakeupNode, map[string]interface{}{
+			"signal_name": signalName,
+			"payload":     payload,
+		}); err != nil {
+			return err
+		}
+		if err := a.recordStateChanged(txCtx, &instance, "", stateBefore); err != nil {
+			return err
+		}
+		if statusBefore != model.InstanceStatusWaiting && instance.Status == model.InstanceStatusWaiting {
+			return a.recordInstanceEvent(txCtx, &instance, model.InstanceEventWaiting, "", nil)
+		}
+		return nil
 	})
 	if err != nil {
+		var schemaErr *SchemaValidationError
+		if errors.As(err, &schemaErr) {
+			return a.err.New("信号数据校验失败: "+schemaErr.Error(), err).WithCode(errorc.ErrorCodeValid)
+		}
 		return a.err.New("发送信号失败", err)
 	}
 
@@ -0,0 +1,438 @@
+// 职责：节点耗时分析——节点每触发一次记录一条执行记录，完成时回填结束时间、结果、
+// 检查点与 executor 任务尝试；并按定义编码与版本汇总各节点耗时分位数、排队与执行时长、
+// 重试次数、error 边频率，以及各终态实例数随时间的分布。
+//
+// 边界：记录与推进写在同一事务内，推进回滚时记录一并消失。任务耗时取自 executor 的尝试记录，
+// 定时唤醒类任务（timer、超时、审批截止）不计入；查询任务失败只告警不阻断推进。
+// 报表在内存中计算分位数，单次最多读取 analyticsMaxRows 行，超出时 Truncated 为 true，
+// 应缩小时间范围。实例的 updated_at 作为进入终态的时间，与保留策略一致。
+package app
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/xsxdot/aio/base"
+	executorCallback "github.com/xsxdot/aio/system/executor/api/callback"
+	"github.com/xsxdot/aio/system/workflow/internal/dao"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+)
+
+// WorkflowNodeRunModel 类型别名，供 api/client 等层使用
+type WorkflowNodeRunModel = model.WorkflowNodeRunModel
+
+const (
+	// analyticsMaxRows 单次报表最多读取的记录数
+	analyticsMaxRows = 100000
+	// analyticsMaxBuckets 终态分布最多的时间桶数
+	analyticsMaxBuckets = 1000
+	// analyticsDefaultRange 未指定起始时间时的统计范围
+	analyticsDefaultRange = 7 * 24 * time.Hour
+)
+
+// AnalyticsQuery 报表范围：定义编码的某个版本（0 表示全部版本）在 [From, To) 内的数据
+type AnalyticsQuery struct {
+	Env     string
+	DefCode string
+	Version int32
+	From    time.Time // 零值表示 To 之前 7 天
+	To      time.Time // 零值表示当前时间
+	Bucket  string    // 终态分布的时间粒度：hour/day，空表示 day
+}
+
+// DurationStats 一组时长（毫秒）的分位数与平均值
+type DurationStats struct {
+	P50 int64 `json:"p50_ms"`
+	P95 int64 `json:"p95_ms"`
+	P99 int64 `json:"p99_ms"`
+	Avg int64 `json:"avg_ms"`
+	Max int64 `json:"max_ms"`
+}
+
+// NodeAnalytics 单个节点的汇总
+type NodeAnalytics struct {
+	NodeID    string `json:"node_id"`
+	NodeType  string `json:"node_type"`
+	Runs      int64  `json:"runs"`
+	Succeeded int64  `json:"succeeded"`
+	Failed    int64  `json:"failed"`
+	// ErrorEdges 失败后经 error 边降级的次数，ErrorEdgeRate 为其占执行次数的比例
+	ErrorEdges    int64   `json:"error_edges"`
+	ErrorEdgeRate float64 `json:"error_edge_rate"`
+	// Duration 触发到完成；QueueWait / Run 为 executor 任务的排队（含重试退避）与执行时长
+	Duration  DurationStats `json:"duration"`
+	QueueWait DurationStats `json:"queue_wait"`
+	Run       DurationStats `json:"run"`
+	Jobs      int64         `json:"jobs"`
+	Attempts  int64         `json:"attempts"`
+	Retries   int64         `json:"retries"`
+}
+
+// NodeAnalyticsReport 定义的节点耗时报表，Nodes 按 p95 耗时降序
+type NodeAnalyticsReport struct {
+	Env       string           `json:"env"`
+	DefCode   string           `json:"def_code"`
+	Versions  []int32          `json:"versions"`
+	From      time.Time        `json:"from"`
+	To        time.Time        `json:"to"`
+	Nodes     []*NodeAnalytics `json:"nodes"`
+	Truncated bool             `json:"truncated"`
+}
+
+// InstanceStatusBucket 一个时间桶内进入各终态的实例数
+type InstanceStatusBucket struct {
+	Start  time.Time                              `json:"start"`
+	Counts map[model.WorkflowInstanceStatus]int64 `json:"counts"`
+	Total  int64                                  `json:"total"`
+}
+
+// InstanceStatusReport 定义的终态实例分布
+type InstanceStatusReport struct {
+	Env       string                                 `json:"env"`
+	DefCode   string                                 `json:"def_code"`
+	Versions  []int32                                `json:"versions"`
+	From      time.Time                              `json:"from"`
+	To        time.Time                              `json:"to"`
+	Bucket    string                                 `json:"bucket"`
+	Totals    map[model.WorkflowInstanceStatus]int64 `json:"totals"`
+	Buckets   []*InstanceStatusBucket                `json:"buckets"`
+	Truncated bool                                   `json:"truncated"`
+}
+
+// recordNodeRunStarted 节点触发时新建执行记录；同节点尚未完成的旧记录（回滚、回环重跑）置为 ABANDONED
+func (a *App) recordNodeRunStarted(ctx context.Context, instance *model.WorkflowInstanceModel, node *model.Node, env string) error {
+	if a.NodeRunService == nil {
+		return nil
+	}
+	now := time.Now()
+	if err := a.NodeRunService.AbandonRunning(ctx, instance.ID, node.ID, now); err != nil {
+		return err
+	}
+	return a.NodeRunService.Create(ctx, &model.WorkflowNodeRunModel{
+		InstanceID: instance.ID,
+		NodeID:     node.ID,
+		NodeType:   string(node.Type),
+		DefID:      instance.DefID,
+		Env:        env,
+		Status:     model.NodeRunStatusRunning,
+		StartedAt:  now,
+	})
+}
+
+// recordNodeRunProgress 节点回调推进后更新执行记录：累计承载本次回调的任务耗时，
+// 节点完成时回填结束时间、结果与检查点。功能上线前触发的节点没有记录，直接跳过
+func (a *App) recordNodeRunProgress(ctx context.Context, instance *model.WorkflowInstanceModel, dag *model.DAG, nodeID string, jobID int64, nodeDone bool, output map[string]interface{}, nextNodeIDs []string) error {
+	if a.NodeRunService == nil || (jobID <= 0 && !nodeDone) {
+		return nil
+	}
+	run, err := a.NodeRunService.FindRunning(ctx, instance.ID, nodeID)
+	if err != nil {
+		if errorc.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	fields := make(map[string]interface{})
+	if jobID > 0 {
+		if stats := a.nodeJobStats(ctx, jobID, run.StartedAt); stats != nil {
+			run.Jobs++
+			run.Attempts += stats.attempts
+			run.QueueWaitMs += stats.queueWaitMs
+			run.RunMs += stats.runMs
+			fields["jobs"] = run.Jobs
+			fields["attempts"] = run.Attempts
+			fields["queue_wait_ms"] = run.QueueWaitMs
+			fields["run_ms"] = run.RunMs
+			fields["job_id"] = jobID
+			fields["attempt_id"] = stats.attemptID
+		}
+	}
+	if nodeDone {
+		now := time.Now()
+		duration := now.Sub(run.StartedAt).Milliseconds()
+		status := model.NodeRunStatusSucceeded
+		if _, failed := output["error_msg"]; failed {
+			status = model.NodeRunStatusFailed
+			fields["error_edge"] = routedByErrorEdge(dag, nodeID, nextNodeIDs)
+		}
+		checkpointID, err := a.NodeRunService.LatestCheckpointID(ctx, instance.ID, nodeID)
+		if err != nil {
+			return err
+		}
+		fields["status"] = status
+		fields["finished_at"] = now
+		fields["duration_ms"] = duration
+		fields["checkpoint_id"] = checkpointID
+		if run.Jobs == 0 {
+			fields["run_ms"] = duration
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return a.NodeRunService.UpdateFields(ctx, run.ID, fields)
+}
+
+// routedByErrorEdge 失败的节点是否经 error 边触发了下游
+func routedByErrorEdge(dag *model.DAG, nodeID string, nextNodeIDs []string) bool {
+	for _, edge := range dag.GetOutgoingEdges(nodeID) {
+		if edge.Type == model.EdgeTypeError && containsString(nextNodeIDs, edge.To) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeJobStat 单个 executor 任务的耗时
+type nodeJobStat struct {
+	attempts    int32
+	attemptID   int64
+	queueWaitMs int64
+	runMs       int64
+}
+
+// nodeJobStats 由任务的尝试记录计算耗时：执行时长为各次尝试之和，排队时长为从入队到最后一次
+// 尝试结束中其余的时间。定时唤醒任务与早于本次触发的任务（迟到回调，容忍数据库 1 秒的时间精度）返回 nil
+func (a *App) nodeJobStats(ctx context.Context, jobID int64, since time.Time) *nodeJobStat {
+	job, err := a.ExecutorClient.GetJob(ctx, uint64(jobID))
+	if err != nil {
+		a.log.WithErr(err).WithField("job_id", jobID).Warn("查询节点任务失败，跳过耗时统计")
+		return nil
+	}
+	if job.Method == executorCallback.MethodTimerFired || job.CreatedAt.Before(since.Add(-time.Second)) {
+		return nil
+	}
+	attempts, err := a.ExecutorClient.ListJobAttempts(ctx, uint64(jobID))
+	if err != nil {
+		a.log.WithErr(err).WithField("job_id", jobID).Warn("查询任务尝试记录失败，跳过耗时统计")
+		return nil
+	}
+
+	stat := &nodeJobStat{attempts: int32(len(attempts))}
+	end := time.Now()
+	if len(attempts) > 0 {
+		// 尝试记录按次数倒序，第一条即产生本次结果的尝试
+		stat.attemptID = attempts[0].ID
+		end = job.CreatedAt
+	}
+	for _, at := range attempts {
+		if at.StartedAt == nil {
+			continue
+		}
+		finished := time.Now()
+		if at.FinishedAt != nil {
+			finished = *at.FinishedAt
+		}
+		stat.runMs += finished.Sub(*at.StartedAt).Milliseconds()
+		if finished.After(end) {
+			end = finished
+		}
+	}
+	if wait := end.Sub(job.CreatedAt).Milliseconds() - stat.runMs; wait > 0 {
+		stat.queueWaitMs = wait
+	}
+	return stat
+}
+
+// ListNodeRuns 按触发顺序列出实例的节点执行记录
+func (a *App) ListNodeRuns(ctx context.Context, instanceID int64) ([]*model.WorkflowNodeRunModel, error) {
+	if a.NodeRunService == nil {
+		return nil, a.err.New("节点耗时记录未启用", nil)
+	}
+	if _, err := a.InstanceService.FindById(ctx, instanceID); err != nil {
+		return nil, a.err.New("获取实例失败", err)
+	}
+	return a.NodeRunService.ListByInstance(ctx, instanceID)
+}
+
+// analyticsScope 补默认值并解析定义编码对应的版本
+func (a *App) analyticsScope(ctx context.Context, q *AnalyticsQuery) (*dao.NodeRunRangeFilter, []int32, error) {
+	if a.NodeRunService == nil {
+		return nil, nil, a.err.New("节点耗时记录未启用", nil)
+	}
+	if q.DefCode == "" {
+		return nil, nil, a.err.New("def_code 不能为空", nil).WithCode(errorc.ErrorCodeValid)
+	}
+	if q.Env == "" {
+		q.Env = base.ENV
+	}
+	if q.To.IsZero() {
+		q.To = time.Now()
+	}
+	if q.From.IsZero() {
+		q.From = q.To.Add(-analyticsDefaultRange)
+	}
+	if !q.From.Before(q.To) {
+		return nil, nil, a.err.New("from 必须早于 to", nil).WithCode(errorc.ErrorCodeValid)
+	}
+	defs, err := a.NodeRunService.ListDefVersions(ctx, q.Env, q.DefCode, q.Version)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(defs) == 0 {
+		return nil, nil, a.err.New("工作流定义不存在", nil).NotFound()
+	}
+	f := &dao.NodeRunRangeFilter{From: q.From, To: q.To}
+	versions := make([]int32, 0, len(defs))
+	for _, def := range defs {
+		f.DefIDs = append(f.DefIDs, def.ID)
+		versions = append(versions, def.Version)
+	}
+	return f, versions, nil
+}
+
+// NodeAnalytics 按节点汇总范围内触发且已完成的执行记录
+func (a *App) NodeAnalytics(ctx context.Context, q *AnalyticsQuery) (*NodeAnalyticsReport, error) {
+	f, versions, err := a.analyticsScope(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	runs, err := a.NodeRunService.ListFinished(ctx, f, analyticsMaxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	type samples struct {
+		stat                     *NodeAnalytics
+		duration, queueWait, run []int64
+	}
+	byNode := make(map[string]*samples)
+	var order []string
+	for _, r := range runs {
+		s := byNode[r.NodeID]
+		if s == nil {
+			s = &samples{stat: &NodeAnalytics{NodeID: r.NodeID, NodeType: r.NodeType}}
+			byNode[r.NodeID] = s
+			order = append(order, r.NodeID)
+		}
+		st := s.stat
+		st.Runs++
+		if r.Status == model.NodeRunStatusFailed {
+			st.Failed++
+		} else {
+			st.Succeeded++
+		}
+		if r.ErrorEdge {
+			st.ErrorEdges++
+		}
+		st.Jobs += int64(r.Jobs)
+		st.Attempts += int64(r.Attempts)
+		s.duration = append(s.duration, r.DurationMs)
+		s.queueWait = append(s.queueWait, r.QueueWaitMs)
+		s.run = append(s.run, r.RunMs)
+	}
+
+	report := &NodeAnalyticsReport{
+		Env:       q.Env,
+		DefCode:   q.DefCode,
+		Versions:  versions,
+		From:      q.From,
+		To:        q.To,
+		Nodes:     make([]*NodeAnalytics, 0, len(order)),
+		Truncated: len(runs) >= analyticsMaxRows,
+	}
+	for _, nodeID := range order {
+		s := byNode[nodeID]
+		st := s.stat
+		st.ErrorEdgeRate = float64(st.ErrorEdges) / float64(st.Runs)
+		if st.Attempts > st.Jobs {
+			st.Retries = st.Attempts - st.Jobs
+		}
+		st.Duration = durationStats(s.duration)
+		st.QueueWait = durationStats(s.queueWait)
+		st.Run = durationStats(s.run)
+		report.Nodes = append(report.Nodes, st)
+	}
+	sort.SliceStable(report.Nodes, func(i, j int) bool { return report.Nodes[i].Duration.P95 > report.Nodes[j].Duration.P95 })
+	return report, nil
+}
+
+// InstanceStatusAnalytics 按时间桶统计范围内进入各终态的实例数，空桶同样列出
+func (a *App) InstanceStatusAnalytics(ctx context.Context, q *AnalyticsQuery) (*InstanceStatusReport, error) {
+	var step time.Duration
+	switch q.Bucket {
+	case "", "day":
+		q.Bucket, step = "day", 24*time.Hour
+	case "hour":
+		step = time.Hour
+	default:
+		return nil, a.err.New("bucket 只能是 hour/day", nil).WithCode(errorc.ErrorCodeValid)
+	}
+	f, versions, err := a.analyticsScope(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	start := truncateToBucket(q.From, q.Bucket)
+	n := int(q.To.Sub(start)/step) + 1
+	if n > analyticsMaxBuckets {
+		return nil, a.err.New("时间范围过大，请缩小范围或使用更粗的 bucket", nil).WithCode(errorc.ErrorCodeValid)
+	}
+	rows, err := a.NodeRunService.ListTerminalInstances(ctx, f, terminalInstanceStatuses, analyticsMaxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &InstanceStatusReport{
+		Env:       q.Env,
+		DefCode:   q.DefCode,
+		Versions:  versions,
+		From:      q.From,
+		To:        q.To,
+		Bucket:    q.Bucket,
+		Totals:    make(map[model.WorkflowInstanceStatus]int64),
+		Buckets:   make([]*InstanceStatusBucket, 0, n),
+		Truncated: len(rows) >= analyticsMaxRows,
+	}
+	for t := start; t.Before(q.To); t = t.Add(step) {
+		report.Buckets = append(report.Buckets, &InstanceStatusBucket{Start: t, Counts: make(map[model.WorkflowInstanceStatus]int64)})
+	}
+	for _, row := range rows {
+		idx := int(row.UpdatedAt.Sub(start) / step)
+		if idx < 0 || idx >= len(report.Buckets) {
+			continue
+		}
+		b := report.Buckets[idx]
+		b.Counts[row.Status]++
+		b.Total++
+		report.Totals[row.Status]++
+	}
+	return report, nil
+}
+
+// truncateToBucket 把时间对齐到所在时间桶的起点（day 按 t 所在时区的零点）
+func truncateToBucket(t time.Time, bucket string) time.Time {
+	if bucket == "hour" {
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// durationStats 计算分位数（最近秩法）、平均值与最大值，values 会被排序
+func durationStats(values []int64) DurationStats {
+	if len(values) == 0 {
+		return DurationStats{}
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	var sum int64
+	for _, v := range values {
+		sum += v
+	}
+	percentile := func(p float64) int64 {
+		idx := int(math.Ceil(p*float64(len(values)))) - 1
+		if idx < 0 {
+			idx = 0
+		}
+		return values[idx]
+	}
+	return DurationStats{
+		P50: percentile(0.50),
+		P95: percentile(0.95),
+		P99: percentile(0.99),
+		Avg: sum / int64(len(values)),
+		Max: values[len(values)-1],
+	}
+}
@@ -0,0 +1,170 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"gorm.io/gorm"
+)
+
+const analyticsTestDAG = `{"nodes":[
+	{"id":"A","type":"task","config":{"service":"svc","method":"a"}},
+	{"id":"B","type":"task","config":{"service":"svc","method":"b"}},
+	{"id":"H","type":"task","config":{"service":"svc","method":"h"}}
+],"edges":[{"from":"A","to":"B"},{"from":"A","to":"H","type":"error"}]}`
+
+func newAnalyticsTestApp(t *testing.T) (*App, *gorm.DB) {
+	t.Helper()
+	a, db := newTestApp(t)
+	if _, err := a.CreateDef(context.Background(), "test", "analytics_flow", "analytics", analyticsTestDAG, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	return a, db
+}
+
+// completeNodeJob 为节点任务补上执行尝试（相对入队时间的 [开始, 结束] 秒数）后按 Worker 回报完成
+func completeNodeJob(t *testing.T, a *App, db *gorm.DB, instanceID int64, nodeID, resultJSON string, attempts ...[2]int) {
+	t.Helper()
+	var job struct {
+		ID           int64
+		CallbackData string
+		CreatedAt    time.Time
+	}
+	if err := db.Table("aio_executor_jobs").Select("id, callback_data, created_at").
+		Where("dedup_key LIKE ?", fmt.Sprintf("wf_%d_node_%s_%%", instanceID, nodeID)).
+		Order("id desc").Limit(1).Scan(&job).Error; err != nil || job.ID == 0 {
+		t.Fatalf("find job of %s: %v", nodeID, err)
+	}
+	for i, at := range attempts {
+		started, finished := job.CreatedAt.Add(time.Duration(at[0])*time.Second), job.CreatedAt.Add(time.Duration(at[1])*time.Second)
+		if err := db.Exec("INSERT INTO executor_job_attempts (job_id, attempt_no, status, started_at, finished_at, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+			job.ID, i+1, "succeeded", started, finished, started, finished).Error; err != nil {
+			t.Fatalf("insert attempt: %v", err)
+		}
+	}
+	if err := a.OnJobCompleted(context.Background(), uint64(job.ID), job.CallbackData, resultJSON); err != nil {
+		t.Fatalf("complete %s: %v", nodeID, err)
+	}
+}
+
+// 节点执行记录关联完成时的检查点与产生结果的任务尝试，执行时长为各次尝试之和、
+// 排队时长含重试退避；失败经 error 边降级的节点记为 error_edge，报表据此汇总重试与 error 边频率
+func TestNodeRunsLinkAttemptsAndAggregate(t *testing.T) {
+	ctx := context.Background()
+	a, db := newAnalyticsTestApp(t)
+
+	ok, err := a.StartWorkflow(ctx, "analytics_flow", nil, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	// 第 1 次尝试 1s~2s 失败，退避后第 2 次尝试 5s~7s 成功：执行 3s，排队 4s
+	completeNodeJob(t, a, db, ok, "A", `{"v":1}`, [2]int{1, 2}, [2]int{5, 7})
+	completeNodeJob(t, a, db, ok, "B", `{}`)
+
+	failed, err := a.StartWorkflow(ctx, "analytics_flow", nil, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	completeNodeJob(t, a, db, failed, "A", `{"error_msg":"boom"}`, [2]int{0, 1})
+
+	runs, err := a.ListNodeRuns(ctx, ok)
+	if err != nil || len(runs) != 2 || runs[0].NodeID != "A" || runs[1].NodeID != "B" {
+		t.Fatalf("runs = %+v err=%v", runs, err)
+	}
+	runA := runs[0]
+	if runA.Status != model.NodeRunStatusSucceeded || runA.FinishedAt == nil || runA.Jobs != 1 || runA.Attempts != 2 ||
+		runA.RunMs != 3000 || runA.QueueWaitMs != 4000 || runA.CheckpointID == 0 || runA.AttemptID == 0 {
+		t.Fatalf("run A = %+v", runA)
+	}
+	var lastAttempt int64
+	db.Table("executor_job_attempts").Select("id").Where("job_id = ? AND attempt_no = 2", runA.JobID).Scan(&lastAttempt)
+	if runA.AttemptID != lastAttempt {
+		t.Fatalf("attempt_id = %d, want 产生结果的第 2 次尝试 %d", runA.AttemptID, lastAttempt)
+	}
+	runs, _ = a.ListNodeRuns(ctx, failed)
+	if len(runs) != 2 || runs[0].Status != model.NodeRunStatusFailed || !runs[0].ErrorEdge || runs[1].NodeID != "H" ||
+		runs[1].Status != model.NodeRunStatusRunning {
+		t.Fatalf("failed instance runs = %+v", runs)
+	}
+
+	report, err := a.NodeAnalytics(ctx, &AnalyticsQuery{Env: "test", DefCode: "analytics_flow"})
+	if err != nil {
+		t.Fatalf("node analytics: %v", err)
+	}
+	var nodeA *NodeAnalytics
+	for _, n := range report.Nodes {
+		if n.NodeID == "A" {
+			nodeA = n
+		}
+	}
+	if nodeA == nil || nodeA.Runs != 2 || nodeA.Failed != 1 || nodeA.ErrorEdges != 1 || nodeA.ErrorEdgeRate != 0.5 ||
+		nodeA.Retries != 1 || nodeA.Run.P99 != 3000 || nodeA.Run.P50 != 1000 || nodeA.QueueWait.Max != 4000 {
+		t.Fatalf("node A = %+v", nodeA)
+	}
+	if len(report.Nodes) != 2 || report.Versions[0] != 1 {
+		t.Fatalf("report = %+v，未完成的 H 不应计入", report)
+	}
+}
+
+// 终态分布按时间桶列出（含空桶），范围外与未结束的实例不计入；参数错误与不存在的定义分别报错
+func TestInstanceStatusAnalyticsBuckets(t *testing.T) {
+	ctx := context.Background()
+	a, db := newAnalyticsTestApp(t)
+	now := time.Now()
+
+	start := func(ageHours int, cancel bool) {
+		id, err := a.StartWorkflow(ctx, "analytics_flow", nil, "test")
+		if err != nil {
+			t.Fatalf("start: %v", err)
+		}
+		if cancel {
+			if err := a.CancelInstance(ctx, id); err != nil {
+				t.Fatalf("cancel: %v", err)
+			}
+		} else {
+			completeNodeJob(t, a, db, id, "A", `{}`)
+			completeNodeJob(t, a, db, id, "B", `{}`)
+		}
+		db.Exec("UPDATE aio_workflow_instance SET updated_at = ? WHERE id = ?", now.Add(-time.Duration(ageHours)*time.Hour), id)
+	}
+	start(0, false)
+	start(0, true)
+	start(2, false)
+	start(30, false) // 范围外
+	if _, err := a.StartWorkflow(ctx, "analytics_flow", nil, "test"); err != nil {
+		t.Fatalf("start running: %v", err)
+	}
+
+	report, err := a.InstanceStatusAnalytics(ctx, &AnalyticsQuery{
+		Env: "test", DefCode: "analytics_flow", Bucket: "hour", From: now.Add(-3 * time.Hour), To: now.Add(time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("instance analytics: %v", err)
+	}
+	if report.Totals[model.InstanceStatusCompleted] != 2 || report.Totals[model.InstanceStatusCanceled] != 1 || len(report.Totals) != 2 {
+		t.Fatalf("totals = %v", report.Totals)
+	}
+	if len(report.Buckets) < 4 {
+		t.Fatalf("buckets = %d, want >= 4（含空桶）", len(report.Buckets))
+	}
+	var sum int64
+	for _, b := range report.Buckets {
+		sum += b.Total
+	}
+	if sum != 3 || report.Buckets[0].Total != 0 {
+		t.Fatalf("buckets = %+v", report.Buckets)
+	}
+
+	var e *errorc.Error
+	if _, err := a.InstanceStatusAnalytics(ctx, &AnalyticsQuery{Env: "test", DefCode: "analytics_flow", Bucket: "week"}); !errors.As(err, &e) || e.ErrorCode != errorc.ErrorCodeValid {
+		t.Fatalf("bucket=week err = %v, want ErrorCodeValid", err)
+	}
+	if _, err := a.NodeAnalytics(ctx, &AnalyticsQuery{Env: "test", DefCode: "missing"}); !errorc.IsNotFound(err) {
+		t.Fatalf("missing def err = %v, want NotFound", err)
+	}
+}
@@ -0,0 +1,501 @@
+// 职责：审批节点——按节点配置登记待办与审批人，校验审批人身份、决定与表单，
+// 汇总会签结果，并以最终决定（approve/reject/request_changes/escalate）选择
+// 带 decision 的出边推进；同时提供管理员的待办收件箱。
+//
+// 边界：只有配置了 assignee_ids、assignee_roles、required_approvals、decisions、
+// form_schema 或 deadline 之一的审批节点才由本文件托管；未配置的审批节点保持原语义，
+// 由外部直接 ReportNodeCompleted 推进。托管节点只能经 DecideApproval 或截止到期回调推进，
+// 直接上报会被拒绝，否则会绕过授权与会签。审批待办的一切变更都先持有实例行锁，
+// 与推进路径串行。截止时间借助 executor 延时任务实现，幂等键沿用
+// wf_{instance}_node_{node} 前缀，实例取消与回滚的按前缀取消会一并撤销。
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/xsxdot/aio/base"
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	executorCallback "github.com/xsxdot/aio/system/executor/api/callback"
+	executorDto "github.com/xsxdot/aio/system/executor/api/dto"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"gorm.io/gorm"
+)
+
+// 类型别名，供 api/client 等层使用
+type WorkflowApprovalModel = model.WorkflowApprovalModel
+type WorkflowApprovalAssigneeModel = model.WorkflowApprovalAssigneeModel
+type WorkflowApprovalDecisionModel = model.WorkflowApprovalDecisionModel
+
+// approvalExpiredErrorMsg 截止到期且无 escalate 边时写入的 error_msg
+const approvalExpiredErrorMsg = "审批超过截止时间未决定"
+
+// ApprovalActor 做出审批决定的管理员
+type ApprovalActor struct {
+	AdminID int64
+	Account string
+	Roles   []string
+	// IsSuper 超级管理员可以决定任意待办
+	IsSuper bool
+}
+
+// ApprovalDetail 审批待办及其审批人、已记录的决定
+type ApprovalDetail struct {
+	Approval  *model.WorkflowApprovalModel           `json:"approval"`
+	Assignees []*model.WorkflowApprovalAssigneeModel `json:"assignees"`
+	Decisions []*model.WorkflowApprovalDecisionModel `json:"decisions"`
+}
+
+// triggerApprovalNode 进入审批节点：托管节点作废本节点遗留的待办、登记新待办并按需登记截止唤醒，
+// 随后实例进入 WAITING
+func (a *App) triggerApprovalNode(ctx context.Context, instance *model.WorkflowInstanceModel, node *model.Node, env string) error {
+	spec, managed, err := node.ApprovalSpec()
+	if err != nil {
+		return a.err.New("审批节点配置无效", err).WithCode(errorc.ErrorCodeValid)
+	}
+	if !managed {
+		// 等待外部接口调用 ReportNodeCompleted 推进，使用事务+行锁与 ReportNodeCompleted 并发安全
+		return a.updateInstanceStatusToWaitingWithLock(ctx, instance.ID, node.ID)
+	}
+	if a.ApprovalService == nil {
+		return a.err.New("审批服务未启用，无法进入托管审批节点 "+node.ID, nil)
+	}
+
+	// 回环或回滚重新进入本节点时，上一轮的待办已无意义
+	if err := a.ApprovalService.CancelPending(ctx, instance.ID, node.ID); err != nil {
+		return err
+	}
+	decisionsBytes, _ := json.Marshal(spec.Decisions)
+	approval := &model.WorkflowApprovalModel{
+		InstanceID:        instance.ID,
+		NodeID:            node.ID,
+		DefID:             instance.DefID,
+		Env:               env,
+		RequiredApprovals: spec.RequiredApprovals,
+		Decisions:         string(decisionsBytes),
+		Status:            model.ApprovalStatusPending,
+	}
+	if spec.FormSchema != nil {
+		schemaBytes, _ := json.Marshal(spec.FormSchema)
+		approval.FormSchema = string(schemaBytes)
+	}
+	if spec.Deadline > 0 {
+		deadlineAt := time.Now().Add(spec.Deadline)
+		approval.DeadlineAt = &deadlineAt
+	}
+	var assignees []*model.WorkflowApprovalAssigneeModel
+	for _, id := range spec.AssigneeIDs {
+		assignees = append(assignees, &model.WorkflowApprovalAssigneeModel{Kind: model.ApprovalAssigneeAdmin, Value: strconv.FormatInt(id, 10)})
+	}
+	for _, role := range spec.AssigneeRoles {
+		assignees = append(assignees, &model.WorkflowApprovalAssigneeModel{Kind: model.ApprovalAssigneeRole, Value: role})
+	}
+	if err := a.ApprovalService.CreateWithAssignees(ctx, approval, assignees); err != nil {
+		return err
+	}
+	if approval.DeadlineAt != nil {
+		if err := a.scheduleApprovalDeadline(ctx, approval, env); err != nil {
+			return err
+		}
+	}
+	return a.updateInstanceStatusToWaitingWithLock(ctx, instance.ID, node.ID)
+}
+
+// scheduleApprovalDeadline 提交截止唤醒任务，到期回调经 OnJobCompleted 进入 handleApprovalDeadline
+func (a *App) scheduleApprovalDeadline(ctx context.Context, approval *model.WorkflowApprovalModel, env string) error {
+	callbackDataBytes, _ := json.Marshal(map[string]interface{}{
+		"instance_id":       approval.InstanceID,
+		"node_id":           approval.NodeID,
+		"env":               env,
+		"approval_deadline": true,
+		"approval_id":       approval.ID,
+	})
+	argsBytes, _ := json.Marshal(map[string]interface{}{"instance_id": approval.InstanceID, "node_id": approval.NodeID, "approval_id": approval.ID})
+	_, err := a.ExecutorClient.SubmitJob(ctx, &executorDto.SubmitJobInput{
+		Env:              env,
+		TargetService:    executorCallback.InternalTargetService,
+		Method:           executorCallback.MethodTimerFired,
+		ArgsJSON:         string(argsBytes),
+		RunAt:            approval.DeadlineAt.Unix(),
+		MaxAttempts:      defaultNodeMaxAttempts,
+		DedupKey:         approvalDeadlineDedupPrefix(approval.InstanceID, approval.NodeID) + strconv.FormatInt(approval.ID, 10),
+		RetryBackoffType: executorDto.RetryBackoffExponential,
+		Source:           "workflow",
+		CallbackData:     string(callbackDataBytes),
+	})
+	if err != nil {
+		return a.err.New("提交审批截止任务到Executor失败", err)
+	}
+	return nil
+}
+
+func approvalDeadlineDedupPrefix(instanceID int64, nodeID string) string {
+	return nodeDedupPrefix(instanceID, nodeID) + "deadline_"
+}
+
+// DecideApproval 记录管理员对审批待办的决定。
+//
+// reject / request_changes 一票即定；approve 累计到 required_approvals 人时通过。
+// 得出最终决定后同事务推进审批节点，节点输出为
+// {"decision", "comment", "form", "approvals": [...]}，decision 用于选择出边。
+// 表单按待办登记的 form_schema 校验，必填项只在 approve 时要求。
+func (a *App) DecideApproval(ctx context.Context, approvalID int64, actor ApprovalActor, decision, comment string, form map[string]interface{}) (*model.WorkflowApprovalModel, error) {
+	if a.ApprovalService == nil {
+		return nil, a.err.New("审批服务未启用", nil)
+	}
+	if decision == "" {
+		return nil, a.err.New("decision 不能为空", nil).WithCode(errorc.ErrorCodeValid)
+	}
+	found, err := a.ApprovalService.FindById(ctx, approvalID)
+	if err != nil {
+		if errorc.IsNotFound(err) {
+			return nil, a.err.New("审批待办不存在", err).NotFound()
+		}
+		return nil, err
+	}
+
+	var approval *model.WorkflowApprovalModel
+	err = mvc.ExtractDB(ctx, base.DB).Transaction(func(tx *gorm.DB) error {
+		txCtx := mvc.WithTxToContext(ctx, tx)
+		inst, err := a.InstanceService.FindByIdForUpdate(ctx, tx, found.InstanceID)
+		if err != nil {
+			return a.err.New("获取实例失败", err)
+		}
+		// 持有实例行锁后重读，拿到与推进路径串行后的最新状态
+		approval, err = a.ApprovalService.FindById(txCtx, approvalID)
+		if err != nil {
+			return err
+		}
+		if approval.Status != model.ApprovalStatusPending {
+			return a.err.New("审批已结束: "+string(approval.Status), nil).WithCode(errorc.ErrorCodeValid)
+		}
+		if (inst.Status != model.InstanceStatusRunning && inst.Status != model.InstanceStatusWaiting && inst.Status != model.InstanceStatusPaused) ||
+			!containsString(parseActiveNodeIDs(inst.ActiveNodeIDs), approval.NodeID) {
+			return a.err.New("实例已不在等待该审批", nil).WithCode(errorc.ErrorCodeValid)
+		}
+
+		assignees, err := a.ApprovalService.ListAssignees(txCtx, approvalID)
+		if err != nil {
+			return err
+		}
+		if !actor.IsSuper && !approvalAssignedTo(assignees, actor) {
+			return a.err.New("当前管理员不是该审批的审批人", nil).NoAuth()
+		}
+		var allowed []string
+		_ = json.Unmarshal([]byte(approval.Decisions), &allowed)
+		if !containsString(allowed, decision) {
+			return a.err.New("不允许的审批决定: "+decision, nil).WithCode(errorc.ErrorCodeValid)
+		}
+		if approval.FormSchema != "" {
+			var schema map[string]interface{}
+			if err := json.Unmarshal([]byte(approval.FormSchema), &schema); err != nil {
+				return a.err.New("解析审批表单 schema 失败", err)
+			}
+			if err := validateApprovalForm(schema, form, decision == model.ApprovalDecisionApprove); err != nil {
+				return a.err.New("审批表单校验失败: "+err.Error(), err).WithCode(errorc.ErrorCodeValid)
+			}
+		}
+
+		decisions, err := a.ApprovalService.ListDecisions(txCtx, approvalID)
+		if err != nil {
+			return err
+		}
+		for _, d := range decisions {
+			if d.AdminID == actor.AdminID {
+				return a.err.New("当前管理员已对该审批做出决定", nil).WithCode(errorc.ErrorCodeValid)
+			}
+		}
+		record := &model.WorkflowApprovalDecisionModel{
+			ApprovalID:   approvalID,
+			InstanceID:   approval.InstanceID,
+			NodeID:       approval.NodeID,
+			AdminID:      actor.AdminID,
+			AdminAccount: actor.Account,
+			Decision:     decision,
+			Comment:      comment,
+		}
+		if form != nil {
+			formBytes, _ := json.Marshal(form)
+			record.Payload = string(formBytes)
+		}
+		if err := a.ApprovalService.CreateDecision(txCtx, record); err != nil {
+			return err
+		}
+		decisions = append(decisions, record)
+
+		if decision == model.ApprovalDecisionApprove {
+			approves := 0
+			for _, d := range decisions {
+				if d.Decision == model.ApprovalDecisionApprove {
+					approves++
+				}
+			}
+			if approves < approval.RequiredApprovals {
+				return nil
+			}
+		}
+
+		now := time.Now()
+		approval.Status = approvalStatusForDecision(decision)
+		approval.Decision = decision
+		approval.DecidedAt = &now
+		if err := a.ApprovalService.UpdateFields(txCtx, approvalID, map[string]interface{}{
+			"status":     approval.Status,
+			"decision":   decision,
+			"decided_at": now,
+		}); err != nil {
+			return err
+		}
+		env := approval.Env
+		if env == "" {
+			env = inst.Env
+		}
+		if approval.DeadlineAt != nil {
+			if err := a.ExecutorClient.CancelActiveJobsByDedupKeyPrefix(txCtx, env, approvalDeadlineDedupPrefix(approval.InstanceID, approval.NodeID)); err != nil {
+				a.log.WithErr(err).WithField("approval_id", approvalID).Warn("审批已决定，取消截止任务失败")
+			}
+		}
+		return a.reportNodeCompleted(txCtx, 0, approval.InstanceID, approval.NodeID, approvalNodeOutput(decision, comment, form, decisions), env)
+	})
+	if err != nil {
+		// 事务内的错误均已带上错误码（参数、权限或推进失败），原样返回
+		return nil, err
+	}
+	return approval, nil
+}
+
+// handleApprovalDeadline 处理审批截止回调：待办仍未决定且节点仍在等待时，
+// 有 escalate 边则以 decision=escalate 推进，否则以 error_msg 推进（走 error 边，无则实例 FAILED）。
+//
+// 过期的截止回调（已决定、已作废或实例已结束）只登记幂等标记后忽略；实例已暂停时推迟到恢复后重放，
+// jobID=0 即为重放。
+func (a *App) handleApprovalDeadline(ctx context.Context, jobID, approvalID, instanceID int64, nodeID, env string) error {
+	stale, deferred := false, false
+	err := mvc.ExtractDB(ctx, base.DB).Transaction(func(tx *gorm.DB) error {
+		txCtx := mvc.WithTxToContext(ctx, tx)
+		inst, err := a.InstanceService.FindByIdForUpdate(ctx, tx, instanceID)
+		if err != nil {
+			return err
+		}
+		var approval *model.WorkflowApprovalModel
+		if a.ApprovalService != nil {
+			approval, err = a.ApprovalService.FindById(txCtx, approvalID)
+			if err != nil && !errorc.IsNotFound(err) {
+				return err
+			}
+		}
+		stale = approval == nil || approval.Status != model.ApprovalStatusPending ||
+			(inst.Status != model.InstanceStatusRunning && inst.Status != model.InstanceStatusWaiting && inst.Status != model.InstanceStatusPaused) ||
+			!containsString(parseActiveNodeIDs(inst.ActiveNodeIDs), nodeID)
+		if stale {
+			if a.AppliedCallbackDao == nil || jobID == 0 {
+				return nil
+			}
+			_, mErr := a.AppliedCallbackDao.MarkApplied(ctx, tx, jobID, instanceID, nodeID)
+			return mErr
+		}
+		if inst.Status == model.InstanceStatusPaused {
+			deferred = true
+			return a.deferPausedTimer(ctx, tx, jobID, inst, pausedTimer{Kind: pausedTimerApprovalDeadline, NodeID: nodeID, RefID: approvalID})
+		}
+		if env == "" {
+			env = inst.Env
+		}
+
+		def, err := a.DefService.FindByIdWithTx(ctx, tx, inst.DefID)
+		if err != nil {
+			return err
+		}
+		var dag model.DAG
+		if err := json.Unmarshal([]byte(def.DAGJSON), &dag); err != nil {
+			return fmt.Errorf("解析DAG失败: %w", err)
+		}
+		status, output := approvalDeadlineOutcome(&dag, nodeID)
+		fields := map[string]interface{}{"status": status, "decided_at": time.Now()}
+		if status == model.ApprovalStatusEscalated {
+			fields["decision"] = model.ApprovalDecisionEscalate
+		}
+		if err := a.ApprovalService.UpdateFields(txCtx, approvalID, fields); err != nil {
+			return err
+		}
+		return a.reportNodeCompleted(txCtx, jobID, instanceID, nodeID, output, env)
+	})
+	if err != nil {
+		return a.err.New("处理审批截止失败", err).WithTraceID(ctx)
+	}
+	if stale {
+		a.log.WithField("approval_id", approvalID).Debug("审批截止回调已过期，忽略")
+	} else if !deferred {
+		a.log.WithField("approval_id", approvalID).WithField("instance_id", instanceID).
+			Warn("审批超过截止时间，已按升级或失败路径推进")
+	}
+	return nil
+}
+
+// rejectManagedApprovalReport 托管审批节点不接受直接上报完成，必须经 DecideApproval 或截止回调推进
+func (a *App) rejectManagedApprovalReport(ctx context.Context, instanceID int64, nodeID string) error {
+	inst, err := a.InstanceService.FindById(ctx, instanceID)
+	if err != nil {
+		// 交由推进路径给出原有的错误
+		return nil
+	}
+	def, err := a.DefService.FindById(ctx, inst.DefID)
+	if err != nil {
+		return nil
+	}
+	var dag model.DAG
+	if err := json.Unmarshal([]byte(def.DAGJSON), &dag); err != nil {
+		return nil
+	}
+	node := dag.GetNode(nodeID)
+	if node == nil || node.Type != model.NodeTypeApproval {
+		return nil
+	}
+	if _, managed, _ := node.ApprovalSpec(); managed {
+		return a.err.New("审批节点 "+nodeID+" 配置了审批规则，须通过审批决定接口推进", nil).WithCode(errorc.ErrorCodeValid)
+	}
+	return nil
+}
+
+// cancelPendingApprovals 作废实例中仍待决定的审批，审批服务未装配时跳过
+func (a *App) cancelPendingApprovals(ctx context.Context, instanceID int64) error {
+	if a.ApprovalService == nil {
+		return nil
+	}
+	return a.ApprovalService.CancelPending(ctx, instanceID, "")
+}
+
+// ListApprovalInbox 分页列出当前管理员待处理的审批
+func (a *App) ListApprovalInbox(ctx context.Context, actor ApprovalActor, pageNum, pageSize int32) ([]*model.WorkflowApprovalModel, int64, error) {
+	if a.ApprovalService == nil {
+		return nil, 0, a.err.New("审批服务未启用", nil)
+	}
+	return a.ApprovalService.ListInbox(ctx, actor.AdminID, actor.Roles, pageNum, pageSize)
+}
+
+// GetApproval 查询审批待办及其审批人、决定记录
+func (a *App) GetApproval(ctx context.Context, approvalID int64) (*ApprovalDetail, error) {
+	if a.ApprovalService == nil {
+		return nil, a.err.New("审批服务未启用", nil)
+	}
+	approval, err := a.ApprovalService.FindById(ctx, approvalID)
+	if err != nil {
+		if errorc.IsNotFound(err) {
+			return nil, a.err.New("审批待办不存在", err).NotFound()
+		}
+		return nil, err
+	}
+	assignees, err := a.ApprovalService.ListAssignees(ctx, approvalID)
+	if err != nil {
+		return nil, err
+	}
+	decisions, err := a.ApprovalService.ListDecisions(ctx, approvalID)
+	if err != nil {
+		return nil, err
+	}
+	return &ApprovalDetail{Approval: approval, Assignees: assignees, Decisions: decisions}, nil
+}
+
+// approvalDeadlineOutcome 截止到期时待办的终态与节点输出：有 escalate 边时按升级推进，
+// 否则以 error_msg 推进
+func approvalDeadlineOutcome(dag *model.DAG, nodeID string) (model.ApprovalStatus, map[string]interface{}) {
+	for _, e := range dag.GetOutgoingEdges(nodeID) {
+		if e.Decision == model.ApprovalDecisionEscalate {
+			return model.ApprovalStatusEscalated, map[string]interface{}{"decision": model.ApprovalDecisionEscalate}
+		}
+	}
+	return model.ApprovalStatusExpired, map[string]interface{}{"error_msg": approvalExpiredErrorMsg}
+}
+
+// decisionEdgeMatches 带 decision 的边只在节点输出的 decision 相同时可走
+func decisionEdgeMatches(edge model.Edge, output map[string]interface{}) bool {
+	if edge.Decision == "" {
+		return true
+	}
+	decision, _ := output["decision"].(string)
+	return decision == edge.Decision
+}
+
+// approvalAssignedTo 审批人包含该管理员或其任一角色；未指定审批人时任何人都可以决定
+func approvalAssignedTo(assignees []*model.WorkflowApprovalAssigneeModel, actor ApprovalActor) bool {
+	if len(assignees) == 0 {
+		return true
+	}
+	adminID := strconv.FormatInt(actor.AdminID, 10)
+	for _, as := range assignees {
+		switch as.Kind {
+		case model.ApprovalAssigneeAdmin:
+			if as.Value == adminID {
+				return true
+			}
+		case model.ApprovalAssigneeRole:
+			if containsString(actor.Roles, as.Value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func approvalStatusForDecision(decision string) model.ApprovalStatus {
+	switch decision {
+	case model.ApprovalDecisionApprove:
+		return model.ApprovalStatusApproved
+	case model.ApprovalDecisionReject:
+		return model.ApprovalStatusRejected
+	default:
+		return model.ApprovalStatusChangesRequested
+	}
+}
+
+// approvalNodeOutput 审批节点的输出：最终决定、做出最终决定者的意见与表单，以及全部审批记录
+func approvalNodeOutput(decision, comment string, form map[string]interface{}, decisions []*model.WorkflowApprovalDecisionModel) map[string]interface{} {
+	approvals := make([]interface{}, 0, len(decisions))
+	for _, d := range decisions {
+		approvals = append(approvals, map[string]interface{}{
+			"admin_id":      d.AdminID,
+			"admin_account": d.AdminAccount,
+			"decision":      d.Decision,
+			"comment":       d.Comment,
+			"decided_at":    d.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	output := map[string]interface{}{
+		"decision":  decision,
+		"comment":   comment,
+		"approvals": approvals,
+	}
+	if form != nil {
+		output["form"] = form
+	}
+	return output
+}
+
+// validateApprovalForm 按 form_schema 校验审批表单，与输入/状态 schema 共用 validateJSONSchema。
+// required 只在 requireFields 为 true（approve）时检查；值为 null 的字段视为未填写
+func validateApprovalForm(schema map[string]interface{}, form map[string]interface{}, requireFields bool) error {
+	if !requireFields {
+		relaxed := make(map[string]interface{}, len(schema))
+		for k, v := range schema {
+			if k != "required" {
+				relaxed[k] = v
+			}
+		}
+		schema = relaxed
+	}
+	filled := make(map[string]interface{}, len(form))
+	for k, v := range form {
+		if v != nil {
+			filled[k] = v
+		}
+	}
+	value, err := normalizeJSONValue(filled)
+	if err != nil {
+		return fmt.Errorf("序列化表单失败: %w", err)
+	}
+	return validateJSONSchema(schema, value)
+}
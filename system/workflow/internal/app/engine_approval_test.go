@@ -0,0 +1,214 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	"gorm.io/gorm"
+)
+
+func startApprovalInstance(t *testing.T, a *App, db *gorm.DB, dagJSON string) (int64, *model.WorkflowApprovalModel) {
+	t.Helper()
+	ctx := context.Background()
+	if _, err := a.CreateDef(ctx, "test", "approval_def", "approval", dagJSON, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	id, err := a.StartWorkflow(ctx, "approval_def", map[string]interface{}{"amount": 100}, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	inst, err := a.GetInstance(ctx, id)
+	if err != nil {
+		t.Fatalf("get instance: %v", err)
+	}
+	if inst.Status != model.InstanceStatusWaiting {
+		t.Fatalf("status = %s, want WAITING", inst.Status)
+	}
+	var approval model.WorkflowApprovalModel
+	if err := db.Where("instance_id = ? AND status = ?", id, model.ApprovalStatusPending).Take(&approval).Error; err != nil {
+		t.Fatalf("审批待办未登记: %v", err)
+	}
+	return id, &approval
+}
+
+func inboxTotal(t *testing.T, a *App, actor ApprovalActor) int64 {
+	t.Helper()
+	_, total, err := a.ListApprovalInbox(context.Background(), actor, 1, 10)
+	if err != nil {
+		t.Fatalf("inbox: %v", err)
+	}
+	return total
+}
+
+// 会签：两人 approve 才通过，首个 approve 只记录不推进；已决定者与非审批人都从收件箱消失，
+// 非审批人提交决定被拒；通过后只走 decision=approve 的出边。
+func TestApprovalQuorumRoutesByDecision(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+	id, approval := startApprovalInstance(t, a, db, `{"nodes":[
+		{"id":"A","type":"approval","config":{"assignee_ids":[1,2,3],"required_approvals":2}},
+		{"id":"OK","type":"task","config":{"service":"svc","method":"ok"}},
+		{"id":"NO","type":"task","config":{"service":"svc","method":"no"}}
+	],"edges":[
+		{"from":"A","to":"OK","decision":"approve"},
+		{"from":"A","to":"NO","decision":"reject"}
+	]}`)
+
+	if n := inboxTotal(t, a, ApprovalActor{AdminID: 9}); n != 0 {
+		t.Fatalf("非审批人收件箱 = %d, want 0", n)
+	}
+	if _, err := a.DecideApproval(ctx, approval.ID, ApprovalActor{AdminID: 9}, model.ApprovalDecisionApprove, "", nil); !isNoAuth(err) {
+		t.Fatalf("非审批人决定 err = %v, want NoAuth", err)
+	}
+
+	got, err := a.DecideApproval(ctx, approval.ID, ApprovalActor{AdminID: 1, Account: "alice"}, model.ApprovalDecisionApprove, "ok", nil)
+	if err != nil {
+		t.Fatalf("admin 1 approve: %v", err)
+	}
+	if got.Status != model.ApprovalStatusPending {
+		t.Fatalf("一票 approve 后 status = %s, want PENDING", got.Status)
+	}
+	if inboxTotal(t, a, ApprovalActor{AdminID: 1}) != 0 || inboxTotal(t, a, ApprovalActor{AdminID: 2}) != 1 {
+		t.Fatal("已决定的审批人仍能看到待办，或其余审批人看不到")
+	}
+	if _, err := a.DecideApproval(ctx, approval.ID, ApprovalActor{AdminID: 1}, model.ApprovalDecisionApprove, "", nil); err == nil {
+		t.Fatal("同一审批人重复决定未被拒绝")
+	}
+
+	got, err = a.DecideApproval(ctx, approval.ID, ApprovalActor{AdminID: 2, Account: "bob"}, model.ApprovalDecisionApprove, "lgtm", nil)
+	if err != nil {
+		t.Fatalf("admin 2 approve: %v", err)
+	}
+	if got.Status != model.ApprovalStatusApproved || got.DecidedAt == nil {
+		t.Fatalf("第二票后 status = %s, want APPROVED", got.Status)
+	}
+	inst, err := a.GetInstance(ctx, id)
+	if err != nil {
+		t.Fatalf("get instance: %v", err)
+	}
+	if inst.Status != model.InstanceStatusRunning || inst.ActiveNodeIDs != `["OK"]` {
+		t.Fatalf("status=%s active=%s, want RUNNING [OK]", inst.Status, inst.ActiveNodeIDs)
+	}
+	detail, err := a.GetApproval(ctx, approval.ID)
+	if err != nil {
+		t.Fatalf("get approval: %v", err)
+	}
+	if len(detail.Decisions) != 2 || detail.Decisions[0].AdminAccount != "alice" || detail.Decisions[1].Comment != "lgtm" {
+		t.Fatalf("决定记录 = %+v", detail.Decisions)
+	}
+}
+
+// 配置了审批规则的节点不能被直接上报绕过；approve 必须满足表单必填项，
+// reject 不要求必填项且一票即定；未列入 decisions 的决定被拒。
+func TestApprovalFormValidationAndDirectReportRejected(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+	id, approval := startApprovalInstance(t, a, db, `{"nodes":[
+		{"id":"A","type":"approval","config":{"assignee_roles":["auditor"],
+			"form_schema":{"type":"object","required":["level"],
+				"properties":{"level":{"type":"string","enum":["low","high"]}}}}},
+		{"id":"NO","type":"task","config":{"service":"svc","method":"no"}}
+	],"edges":[{"from":"A","to":"NO","decision":"reject"}]}`)
+
+	if err := a.ReportNodeCompleted(ctx, id, "A", map[string]interface{}{"decision": "approve"}, "test"); err == nil {
+		t.Fatal("直接上报托管审批节点未被拒绝")
+	}
+	auditor := ApprovalActor{AdminID: 5, Roles: []string{"auditor"}}
+	if inboxTotal(t, a, auditor) != 1 || inboxTotal(t, a, ApprovalActor{AdminID: 6, Roles: []string{"ops"}}) != 0 {
+		t.Fatal("按角色分派的待办未只出现在对应角色的收件箱")
+	}
+	if _, err := a.DecideApproval(ctx, approval.ID, auditor, model.ApprovalDecisionApprove, "", nil); err == nil {
+		t.Fatal("缺少必填字段的 approve 未被拒绝")
+	}
+	if _, err := a.DecideApproval(ctx, approval.ID, auditor, model.ApprovalDecisionApprove, "", map[string]interface{}{"level": "mid"}); err == nil {
+		t.Fatal("不在 enum 中的字段值未被拒绝")
+	}
+	if _, err := a.DecideApproval(ctx, approval.ID, auditor, model.ApprovalDecisionRequestChanges, "", nil); err == nil {
+		t.Fatal("未列入 decisions 的决定未被拒绝")
+	}
+
+	got, err := a.DecideApproval(ctx, approval.ID, auditor, model.ApprovalDecisionReject, "too risky", nil)
+	if err != nil {
+		t.Fatalf("reject: %v", err)
+	}
+	if got.Status != model.ApprovalStatusRejected {
+		t.Fatalf("status = %s, want REJECTED", got.Status)
+	}
+	inst, err := a.GetInstance(ctx, id)
+	if err != nil {
+		t.Fatalf("get instance: %v", err)
+	}
+	if inst.ActiveNodeIDs != `["NO"]` {
+		t.Fatalf("active = %s, want [NO]", inst.ActiveNodeIDs)
+	}
+	data, _, _ := parseWorkflowState(inst.CurrentState)
+	if data["decision"] != model.ApprovalDecisionReject || data["comment"] != "too risky" {
+		t.Fatalf("state = %v, want decision/comment 合并自审批输出", data)
+	}
+}
+
+// 截止到期仍未决定时走 escalate 边；重复投递的截止回调命中幂等标记，不会二次推进。
+func TestApprovalDeadlineEscalates(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+	id, approval := startApprovalInstance(t, a, db, `{"nodes":[
+		{"id":"A","type":"approval","config":{"deadline":"1h"}},
+		{"id":"OK","type":"task","config":{"service":"svc","method":"ok"}},
+		{"id":"ESC","type":"task","config":{"service":"svc","method":"escalate"}}
+	],"edges":[
+		{"from":"A","to":"OK","decision":"approve"},
+		{"from":"A","to":"ESC","decision":"escalate"}
+	]}`)
+	if approval.DeadlineAt == nil {
+		t.Fatal("deadline_at 未登记")
+	}
+
+	var job struct {
+		ID           uint64
+		CallbackData string
+	}
+	if err := db.Table("aio_executor_jobs").Select("id, callback_data").
+		Where("dedup_key LIKE ?", approvalDeadlineDedupPrefix(id, "A")+"%").Take(&job).Error; err != nil {
+		t.Fatalf("截止任务未登记: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := a.OnJobCompleted(ctx, job.ID, job.CallbackData, ""); err != nil {
+			t.Fatalf("deadline callback #%d: %v", i, err)
+		}
+	}
+
+	inst, err := a.GetInstance(ctx, id)
+	if err != nil {
+		t.Fatalf("get instance: %v", err)
+	}
+	if inst.ActiveNodeIDs != `["ESC"]` {
+		t.Fatalf("active = %s, want [ESC]", inst.ActiveNodeIDs)
+	}
+	detail, err := a.GetApproval(ctx, approval.ID)
+	if err != nil {
+		t.Fatalf("get approval: %v", err)
+	}
+	if detail.Approval.Status != model.ApprovalStatusEscalated {
+		t.Fatalf("status = %s, want ESCALATED", detail.Approval.Status)
+	}
+}
+
+// decision 边必须从配置了审批规则的审批节点出发，escalate 边要求配置 deadline；
+// form_schema 与 input/state schema 一样在定义校验时检查
+func TestApprovalDecisionEdgeValidation(t *testing.T) {
+	ctx := context.Background()
+	a, _ := newTestApp(t)
+	bad := []string{
+		`{"nodes":[{"id":"T","type":"task"},{"id":"B","type":"task"}],"edges":[{"from":"T","to":"B","decision":"approve"}]}`,
+		`{"nodes":[{"id":"A","type":"approval"},{"id":"B","type":"task"}],"edges":[{"from":"A","to":"B","decision":"approve"}]}`,
+		`{"nodes":[{"id":"A","type":"approval","config":{"assignee_ids":[1]}},{"id":"B","type":"task"}],"edges":[{"from":"A","to":"B","decision":"escalate"}]}`,
+		`{"nodes":[{"id":"A","type":"approval","config":{"assignee_ids":[1],"required_approvals":2}}]}`,
+		`{"nodes":[{"id":"A","type":"approval","config":{"assignee_ids":[1],"form_schema":{"properties":{"level":{"type":"text"}}}}}]}`,
+	}
+	for i, dagJSON := range bad {
+		if _, err := a.CreateDef(ctx, "test", "bad_approval", "bad", dagJSON, int32(i+1)); err == nil {
+			t.Fatalf("dag #%d 未被拒绝", i)
+		}
+	}
+}
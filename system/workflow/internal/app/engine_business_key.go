@@ -0,0 +1,88 @@
+// 职责：业务键——调用方为实例指定业务键（如订单号），同一 env + 定义编码下未结束的实例
+// 至多一个；带业务键重复启动时返回已有实例，吸收调用方在网络错误后的重试。
+//
+// 边界：唯一性由 aio_workflow_business_key 的唯一索引承载，同键的并发启动在该行上串行，
+// 可跨多个 aio 进程。键所指实例进入终态后记录不删除，下一次用同一键启动时在行锁下改指向
+// 新实例，因此各终态路径无需释放业务键。起始节点触发失败时整个启动事务回滚，业务键随之释放。
+package app
+
+import (
+	"context"
+	"strings"
+
+	"github.com/xsxdot/aio/base"
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"gorm.io/gorm"
+)
+
+// maxBusinessKeyLen 业务键最大长度，与 business_key 列宽一致
+const maxBusinessKeyLen = 200
+
+// StartWorkflowWithBusinessKey 以业务键启动实例：该键在 env + 定义编码下已有未结束的实例时
+// 直接返回其 ID（existed=true），不再创建；businessKey 为空时等同于 StartWorkflow
+func (a *App) StartWorkflowWithBusinessKey(ctx context.Context, defCode, businessKey string, initialData map[string]interface{}, env string) (instanceID int64, existed bool, err error) {
+	businessKey = strings.TrimSpace(businessKey)
+	if businessKey == "" {
+		instanceID, err = a.StartWorkflow(ctx, defCode, initialData, env)
+		return instanceID, false, err
+	}
+	if len(businessKey) > maxBusinessKeyLen {
+		return 0, false, a.err.New("business_key 长度不能超过 200", nil).WithCode(errorc.ErrorCodeValid)
+	}
+	if a.BusinessKeyService == nil {
+		return 0, false, a.err.New("业务键服务未启用", nil)
+	}
+	if env == "" {
+		env = base.ENV
+	}
+	def, err := a.DefService.FindByCode(ctx, env, defCode)
+	if err != nil {
+		return 0, false, err
+	}
+
+	err = mvc.ExtractDB(ctx, base.DB).Transaction(func(tx *gorm.DB) error {
+		txCtx := mvc.WithTxToContext(ctx, tx)
+		claim := &model.WorkflowBusinessKeyModel{Env: env, DefCode: defCode, BusinessKey: businessKey}
+		claimed, err := a.BusinessKeyService.Claim(txCtx, claim)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			if claim, err = a.BusinessKeyService.FindForUpdate(txCtx, env, defCode, businessKey); err != nil {
+				return err
+			}
+			if claim.InstanceID > 0 {
+				inst, err := a.InstanceService.FindById(txCtx, claim.InstanceID)
+				if err != nil && !errorc.IsNotFound(err) {
+					return err
+				}
+				if inst != nil && !isTerminalStatus(inst.Status) {
+					instanceID, existed = inst.ID, true
+					return nil
+				}
+			}
+		}
+		if instanceID, err = a.startInstance(txCtx, def, initialData, env, 0, "", businessKey); err != nil {
+			return err
+		}
+		return a.BusinessKeyService.SetInstanceID(txCtx, claim.ID, instanceID)
+	})
+	if err != nil {
+		return 0, false, err
+	}
+	return instanceID, existed, nil
+}
+
+// GetInstanceByBusinessKey 查询某定义编码在 env 下以该业务键启动的最近一个实例（可能已结束），
+// 不存在时返回 NotFound
+func (a *App) GetInstanceByBusinessKey(ctx context.Context, env, defCode, businessKey string) (*model.WorkflowInstanceModel, error) {
+	if strings.TrimSpace(defCode) == "" || strings.TrimSpace(businessKey) == "" {
+		return nil, a.err.New("def_code 与 business_key 不能为空", nil).WithCode(errorc.ErrorCodeValid)
+	}
+	if env == "" {
+		env = base.ENV
+	}
+	return a.InstanceService.FindLatestByBusinessKey(ctx, env, defCode, strings.TrimSpace(businessKey))
+}
@@ -0,0 +1,83 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	errorc "github.com/xsxdot/gokit/err"
+	"gorm.io/gorm"
+)
+
+func newBusinessKeyTestApp(t *testing.T) (*App, *gorm.DB) {
+	t.Helper()
+	a, db := newTestApp(t)
+	if _, err := a.CreateDef(context.Background(), "test", "order_flow", "order", `{"nodes":[{"id":"A","type":"task","config":{"service":"svc","method":"a"}}]}`, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	return a, db
+}
+
+// 调用方网络错误后重试：同键启动返回同一个未结束的实例；实例结束后同一键可以再次启动，
+// 按业务键查询返回最近一个实例，列表可按业务键筛选出历史实例。
+func TestStartWorkflowWithBusinessKeyIsIdempotent(t *testing.T) {
+	ctx := context.Background()
+	a, _ := newBusinessKeyTestApp(t)
+
+	first, existed, err := a.StartWorkflowWithBusinessKey(ctx, "order_flow", "SO1", map[string]interface{}{"n": 1}, "test")
+	if err != nil || existed {
+		t.Fatalf("first start: id=%d existed=%v err=%v", first, existed, err)
+	}
+	retry, existed, err := a.StartWorkflowWithBusinessKey(ctx, "order_flow", "SO1", map[string]interface{}{"n": 2}, "test")
+	if err != nil || !existed || retry != first {
+		t.Fatalf("retry: id=%d existed=%v err=%v, want %d existed", retry, existed, err, first)
+	}
+	other, existed, err := a.StartWorkflowWithBusinessKey(ctx, "order_flow", "SO2", nil, "test")
+	if err != nil || existed || other == first {
+		t.Fatalf("other key: id=%d existed=%v err=%v", other, existed, err)
+	}
+
+	if err := a.CancelInstance(ctx, first); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	second, existed, err := a.StartWorkflowWithBusinessKey(ctx, "order_flow", "SO1", nil, "test")
+	if err != nil || existed || second == first {
+		t.Fatalf("restart after terminal: id=%d existed=%v err=%v", second, existed, err)
+	}
+
+	inst, err := a.GetInstanceByBusinessKey(ctx, "test", "order_flow", "SO1")
+	if err != nil || inst.ID != second || inst.BusinessKey != "SO1" {
+		t.Fatalf("get by key: inst=%+v err=%v, want %d", inst, err, second)
+	}
+	items, total, err := a.ListInstances(ctx, &ListInstancesFilter{DefCode: "order_flow", BusinessKey: "SO1"}, 1, 10)
+	if err != nil || total != 2 || len(items) != 2 || items[0].BusinessKey != "SO1" {
+		t.Fatalf("list by key: total=%d items=%d err=%v", total, len(items), err)
+	}
+}
+
+// 业务键只在 env + 定义编码内唯一；查不到返回 NotFound，超长的键按参数错误拒绝
+func TestBusinessKeyScopeAndErrors(t *testing.T) {
+	ctx := context.Background()
+	a, _ := newBusinessKeyTestApp(t)
+	if _, err := a.CreateDef(ctx, "test", "refund_flow", "refund", `{"nodes":[{"id":"A","type":"task","config":{"service":"svc","method":"a"}}]}`, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	orderID, _, err := a.StartWorkflowWithBusinessKey(ctx, "order_flow", "K1", nil, "test")
+	if err != nil {
+		t.Fatalf("start order: %v", err)
+	}
+	refundID, existed, err := a.StartWorkflowWithBusinessKey(ctx, "refund_flow", "K1", nil, "test")
+	if err != nil || existed || refundID == orderID {
+		t.Fatalf("同键不同定义应各自启动: id=%d existed=%v err=%v", refundID, existed, err)
+	}
+
+	if _, err := a.GetInstanceByBusinessKey(ctx, "test", "order_flow", "missing"); !errorc.IsNotFound(err) {
+		t.Fatalf("err = %v, want NotFound", err)
+	}
+	_, _, err = a.StartWorkflowWithBusinessKey(ctx, "order_flow", strings.Repeat("k", maxBusinessKeyLen+1), nil, "test")
+	var e *errorc.Error
+	if !errors.As(err, &e) || e.ErrorCode != errorc.ErrorCodeValid {
+		t.Fatalf("err = %v, want ErrorCodeValid", err)
+	}
+}
@@ -0,0 +1,417 @@
+// 职责：Saga 补偿——实例失败或运维人员触发补偿时，按 checkpoint 逆序逐个执行已完成节点
+// 声明的 compensate 任务，实例经 COMPENSATING 进入 COMPENSATED。
+//
+// 边界：补偿计划（待补偿的 checkpoint 及进度）在补偿开始时一次性确定并写入 _sys，之后不再
+// 纳入新的 checkpoint。补偿任务串行派发，上一步成功后才派发下一步；某一步重试耗尽时实例
+// 置 FAILED 并保留进度，再次触发补偿从失败的那一步继续。补偿任务的幂等键不使用节点前缀
+// wf_{instance}_node_，不会被按节点取消在途任务的逻辑误伤。
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/xsxdot/aio/base"
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	executorDto "github.com/xsxdot/aio/system/executor/api/dto"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"gorm.io/gorm"
+)
+
+// sysCompensationKey _sys 中补偿计划的键
+const sysCompensationKey = "compensation"
+
+// compensationStep 补偿计划中的一步：补偿某个节点的某次完成
+type compensationStep struct {
+	NodeID       string `json:"node_id"`
+	CheckpointID int64  `json:"checkpoint_id"`
+}
+
+// compensationPlan 补偿计划与进度，Next 为下一步（或正在执行的一步）的下标
+type compensationPlan struct {
+	Steps []compensationStep `json:"steps"`
+	Next  int                `json:"next"`
+	// Error 最近一次补偿失败的原因，重新触发补偿时清空
+	Error string `json:"error,omitempty"`
+}
+
+// loadCompensationPlan 读取 _sys 中的补偿计划，未开始过补偿时返回 false
+func loadCompensationPlan(sys workflowStateSys) (*compensationPlan, bool) {
+	raw, ok := sys[sysCompensationKey]
+	if !ok || raw == nil {
+		return nil, false
+	}
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return nil, false
+	}
+	var plan compensationPlan
+	if err := json.Unmarshal(b, &plan); err != nil {
+		return nil, false
+	}
+	return &plan, true
+}
+
+// storeCompensationPlan 将补偿计划写回 _sys
+func storeCompensationPlan(sys workflowStateSys, plan *compensationPlan) {
+	b, _ := json.Marshal(plan)
+	var raw map[string]interface{}
+	_ = json.Unmarshal(b, &raw)
+	sys[sysCompensationKey] = raw
+}
+
+// buildCompensationSteps 按 checkpoint 逆序列出需要补偿的节点完成记录
+func (a *App) buildCompensationSteps(ctx context.Context, tx *gorm.DB, instanceID int64, dag *model.DAG) ([]compensationStep, error) {
+	checkpoints, err := a.CheckpointService.ListByInstanceIDOrderByCreatedAscWithTx(ctx, tx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	return compensationSteps(checkpoints, dag), nil
+}
+
+// compensationSteps 从按时间升序的 checkpoint 中逆序挑出需要补偿的记录：节点声明了 compensate，
+// 且该次完成未带 error_msg（失败的节点没有需要撤销的副作用）
+func compensationSteps(checkpoints []*model.WorkflowCheckpointModel, dag *model.DAG) []compensationStep {
+	var steps []compensationStep
+	for i := len(checkpoints) - 1; i >= 0; i-- {
+		cp := checkpoints[i]
+		node := dag.GetNode(cp.NodeID)
+		if node == nil {
+			continue
+		}
+		conf, err := node.CompensateConfig()
+		if err != nil || conf == nil {
+			continue
+		}
+		var output map[string]interface{}
+		if cp.NodeOutput != "" {
+			_ = json.Unmarshal([]byte(cp.NodeOutput), &output)
+		}
+		if _, failed := output["error_msg"]; failed {
+			continue
+		}
+		steps = append(steps, compensationStep{NodeID: cp.NodeID, CheckpointID: cp.ID})
+	}
+	return steps
+}
+
+// beginCompensation 实例刚失败时自动转入补偿：没有需要补偿的节点时返回 false，实例保持 FAILED。
+// 调用方须持有实例行锁，并在记录完本次推进的事件后调用 recordCompensationStarted
+func (a *App) beginCompensation(ctx context.Context, tx *gorm.DB, instance *model.WorkflowInstanceModel, dag *model.DAG, env string) (bool, error) {
+	steps, err := a.buildCompensationSteps(ctx, tx, instance.ID, dag)
+	if err != nil {
+		return false, err
+	}
+	if len(steps) == 0 {
+		return false, nil
+	}
+	// 并行分支上仍在执行的节点任务不再需要，其迟到的回调只会记录 checkpoint
+	prefix := fmt.Sprintf("wf_%d_node_", instance.ID)
+	if err := a.ExecutorClient.CancelActiveJobsByDedupKeyPrefix(ctx, env, prefix); err != nil {
+		return false, a.err.New("取消实例在途任务失败", err)
+	}
+	plan := &compensationPlan{Steps: steps}
+	if err := a.enterCompensating(ctx, tx, instance, plan, dag, env); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// enterCompensating 写入补偿计划、置 COMPENSATING 并派发当前一步
+func (a *App) enterCompensating(ctx context.Context, tx *gorm.DB, instance *model.WorkflowInstanceModel, plan *compensationPlan, dag *model.DAG, env string) error {
+	data, sys, err := parseWorkflowState(instance.CurrentState)
+	if err != nil {
+		return a.err.New("解析状态失败", err)
+	}
+	if sys == nil {
+		sys = make(workflowStateSys)
+	}
+	storeCompensationPlan(sys, plan)
+	newState, err := serializeWorkflowState(data, sys)
+	if err != nil {
+		return err
+	}
+	instance.CurrentState = newState
+	instance.Status = model.InstanceStatusCompensating
+	instance.ActiveNodeIDs = "[]"
+	if err := tx.Save(instance).Error; err != nil {
+		return err
+	}
+	return a.submitCompensationStep(ctx, instance, plan, dag, env)
+}
+
+// recordCompensationStarted 记录补偿开始事件
+func (a *App) recordCompensationStarted(ctx context.Context, instance *model.WorkflowInstanceModel) error {
+	_, sys, _ := parseWorkflowState(instance.CurrentState)
+	plan, ok := loadCompensationPlan(sys)
+	if !ok {
+		return nil
+	}
+	nodeIDs := make([]string, 0, len(plan.Steps)-plan.Next)
+	for _, step := range plan.Steps[plan.Next:] {
+		nodeIDs = append(nodeIDs, step.NodeID)
+	}
+	return a.recordInstanceEvent(ctx, instance, model.InstanceEventCompensation, "", map[string]interface{}{
+		"phase": "started",
+		"nodes": nodeIDs,
+	})
+}
+
+// submitCompensationStep 派发补偿计划的当前一步。
+//
+// 补偿任务的 Args 为 {"instance_id","node_id","checkpoint_id","compensate":true,"output"}，
+// output 即该节点那次完成时记录的 NodeOutput
+func (a *App) submitCompensationStep(ctx context.Context, instance *model.WorkflowInstanceModel, plan *compensationPlan, dag *model.DAG, env string) error {
+	step := plan.Steps[plan.Next]
+	node := dag.GetNode(step.NodeID)
+	if node == nil {
+		return a.err.New("补偿节点不存在: "+step.NodeID, nil)
+	}
+	conf, err := node.CompensateConfig()
+	if err != nil {
+		return err
+	}
+	if conf == nil {
+		return a.err.New("节点 "+step.NodeID+" 未配置 compensate", nil)
+	}
+	checkpoint, err := a.CheckpointService.FindById(ctx, step.CheckpointID)
+	if err != nil {
+		return a.err.New("获取补偿节点的检查点失败", err)
+	}
+	var nodeOutput map[string]interface{}
+	if checkpoint.NodeOutput != "" {
+		if err := json.Unmarshal([]byte(checkpoint.NodeOutput), &nodeOutput); err != nil {
+			return a.err.New("解析补偿节点输出失败", err)
+		}
+	}
+	argsBytes, _ := json.Marshal(map[string]interface{}{
+		"instance_id":   instance.ID,
+		"node_id":       step.NodeID,
+		"checkpoint_id": step.CheckpointID,
+		"compensate":    true,
+		"output":        nodeOutput,
+	})
+	callbackDataBytes, _ := json.Marshal(map[string]interface{}{
+		"instance_id": instance.ID,
+		"node_id":     step.NodeID,
+		"env":         env,
+		"compensate":  true,
+		"step":        plan.Next,
+	})
+	policy, err := parseNodeJobPolicy(conf)
+	if err != nil {
+		return a.err.New("节点 "+step.NodeID+" 补偿投递策略配置无效", err)
+	}
+	service, _ := conf["service"].(string)
+	method, _ := conf["method"].(string)
+	jobInput := &executorDto.SubmitJobInput{
+		Env:           env,
+		TargetService: service,
+		Method:        method,
+		ArgsJSON:      string(argsBytes),
+		DedupKey:      fmt.Sprintf("wf_%d_compensate_%d_%d", instance.ID, plan.Next, time.Now().UnixNano()),
+		Source:        "workflow",
+		CallbackData:  string(callbackDataBytes),
+	}
+	policy.applyTo(jobInput)
+	if _, err := a.ExecutorClient.SubmitJob(ctx, jobInput); err != nil {
+		return a.err.New("提交补偿任务到Executor失败", err)
+	}
+	return nil
+}
+
+// handleCompensationStep 处理补偿任务的回调：成功则派发下一步或进入 COMPENSATED，
+// 失败（重试耗尽，带 error_msg）则实例置 FAILED 并保留进度。
+// 实例已不在补偿中或回调不是当前一步时视为过期回调，仅登记幂等标记
+func (a *App) handleCompensationStep(ctx context.Context, jobID int64, instanceID int64, nodeID string, step int, output map[string]interface{}, env string) error {
+	var duplicate, stale bool
+	err := mvc.ExtractDB(ctx, base.DB).Transaction(func(tx *gorm.DB) error {
+		if jobID > 0 && a.AppliedCallbackDao != nil {
+			applied, err := a.AppliedCallbackDao.MarkApplied(ctx, tx, jobID, instanceID, nodeID)
+			if err != nil {
+				return err
+			}
+			if !applied {
+				duplicate = true
+				return nil
+			}
+		}
+		instance, err := a.InstanceService.FindByIdForUpdate(ctx, tx, instanceID)
+		if err != nil {
+			return err
+		}
+		data, sys, err := parseWorkflowState(instance.CurrentState)
+		if err != nil {
+			return a.err.New("解析状态失败", err)
+		}
+		plan, ok := loadCompensationPlan(sys)
+		if instance.Status != model.InstanceStatusCompensating || !ok || plan.Next != step || step >= len(plan.Steps) {
+			stale = true
+			return nil
+		}
+		if env == "" {
+			env = instance.Env
+		}
+		if env == "" {
+			env = base.ENV
+		}
+		statusBefore := instance.Status
+		txCtx := mvc.WithTxToContext(ctx, tx)
+
+		payload := map[string]interface{}{"step": step, "output": output}
+		if errMsg, failed := output["error_msg"]; failed {
+			plan.Error = fmt.Sprintf("节点 %s 补偿失败: %v", nodeID, errMsg)
+			instance.Status = model.InstanceStatusFailed
+			payload["phase"] = "step_failed"
+		} else {
+			plan.Next++
+			payload["phase"] = "step_completed"
+			if plan.Next >= len(plan.Steps) {
+				instance.Status = model.InstanceStatusCompensated
+			}
+		}
+		storeCompensationPlan(sys, plan)
+		newState, err := serializeWorkflowState(data, sys)
+		if err != nil {
+			return err
+		}
+		instance.CurrentState = newState
+		if err := a.InstanceService.SaveWithTx(ctx, tx, instance); err != nil {
+			return err
+		}
+		if err := a.recordInstanceEvent(txCtx, instance, model.InstanceEventCompensation, nodeID, payload); err != nil {
+			return err
+		}
+
+		if instance.Status == model.InstanceStatusCompensating {
+			def, err := a.DefService.FindByIdWithTx(ctx, tx, instance.DefID)
+			if err != nil {
+				return err
+			}
+			var dag model.DAG
+			if err := json.Unmarshal([]byte(def.DAGJSON), &dag); err != nil {
+				return fmt.Errorf("解析DAG失败: %w", err)
+			}
+			dag.Normalize()
+			return a.submitCompensationStep(txCtx, instance, plan, &dag, env)
+		}
+		if err := a.recordTerminalIfEntered(txCtx, instance, statusBefore); err != nil {
+			return err
+		}
+		return a.notifyParentOfChildTerminal(txCtx, instance)
+	})
+	if err != nil {
+		a.log.WithErr(err).
+			WithField("job_id", jobID).
+			WithField("instance_id", instanceID).
+			WithField("node_id", nodeID).
+			Error("处理补偿回调失败")
+		return a.err.New("处理补偿回调失败", err)
+	}
+	if duplicate || stale {
+		a.log.WithField("job_id", jobID).
+			WithField("instance_id", instanceID).
+			WithField("node_id", nodeID).
+			WithField("step", step).
+			Info("补偿回调重复或已过期，已跳过")
+	}
+	return nil
+}
+
+// CompensateInstance 运维触发补偿：逆序执行已完成节点的 compensate 任务。
+//
+// 运行中/等待中的实例先停止推进（取消在途任务、子实例与审批待办）；
+// 上一次补偿中途失败的实例从失败的那一步继续。没有需要补偿的节点时直接进入 COMPENSATED
+func (a *App) CompensateInstance(ctx context.Context, instanceID int64) error {
+	var notifyChild bool
+	err := mvc.ExtractDB(ctx, base.DB).Transaction(func(tx *gorm.DB) error {
+		instance, err := a.InstanceService.FindByIdForUpdate(ctx, tx, instanceID)
+		if err != nil {
+			if errorc.IsNotFound(err) {
+				return a.err.New("实例不存在", err).NotFound()
+			}
+			return err
+		}
+		switch instance.Status {
+		case model.InstanceStatusRunning, model.InstanceStatusWaiting, model.InstanceStatusPaused, model.InstanceStatusFailed, model.InstanceStatusCanceled:
+		default:
+			return a.err.New("只有运行中、等待中、已暂停、失败或已取消的实例才能补偿", nil).WithCode(errorc.ErrorCodeValid)
+		}
+		env := instance.Env
+		if env == "" {
+			env = base.ENV
+		}
+		def, err := a.DefService.FindByIdWithTx(ctx, tx, instance.DefID)
+		if err != nil {
+			return err
+		}
+		var dag model.DAG
+		if err := json.Unmarshal([]byte(def.DAGJSON), &dag); err != nil {
+			return fmt.Errorf("解析DAG失败: %w", err)
+		}
+		dag.Normalize()
+
+		_, sys, err := parseWorkflowState(instance.CurrentState)
+		if err != nil {
+			return a.err.New("解析状态失败", err)
+		}
+		plan, resumed := loadCompensationPlan(sys)
+		if resumed && plan.Next < len(plan.Steps) {
+			plan.Error = ""
+		} else {
+			steps, err := a.buildCompensationSteps(ctx, tx, instance.ID, &dag)
+			if err != nil {
+				return err
+			}
+			plan = &compensationPlan{Steps: steps}
+		}
+
+		txCtx := mvc.WithTxToContext(ctx, tx)
+		statusBefore := instance.Status
+		if !isTerminalStatus(statusBefore) {
+			if err := a.ExecutorClient.CancelActiveJobsByDedupKeyPrefix(txCtx, env, fmt.Sprintf("wf_%d_node_", instance.ID)); err != nil {
+				return a.err.New("取消实例在途任务失败", err)
+			}
+			for _, nodeID := range parseActiveNodeIDs(instance.ActiveNodeIDs) {
+				a.cancelChildInstances(txCtx, instance.ID, nodeID)
+			}
+			if err := a.cancelPendingApprovals(txCtx, instance.ID); err != nil {
+				return err
+			}
+			if err := a.cancelSignalWaits(txCtx, instance.ID); err != nil {
+				return err
+			}
+		}
+
+		if len(plan.Steps) == 0 {
+			instance.Status = model.InstanceStatusCompensated
+			instance.ActiveNodeIDs = "[]"
+			if err := a.InstanceService.SaveWithTx(ctx, tx, instance); err != nil {
+				return err
+			}
+			notifyChild = !isTerminalStatus(statusBefore)
+			return a.recordInstanceEvent(txCtx, instance, model.InstanceEventTerminal, "", nil)
+		}
+		if err := a.enterCompensating(txCtx, tx, instance, plan, &dag, env); err != nil {
+			return err
+		}
+		return a.recordCompensationStarted(txCtx, instance)
+	})
+	if err != nil {
+		// 参数类错误已带错误码，原样返回，避免包装后丢失
+		return err
+	}
+	if notifyChild {
+		instance, err := a.InstanceService.FindById(ctx, instanceID)
+		if err == nil {
+			err = a.notifyParentOfChildTerminal(ctx, instance)
+		}
+		if err != nil {
+			a.log.WithErr(err).WithField("instance_id", instanceID).Warn("子工作流补偿后回报父实例失败")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	"gorm.io/gorm"
+)
+
+const compensationTestDAG = `{"nodes":[
+	{"id":"A","type":"task","config":{"service":"svc","method":"a","compensate":{"service":"svc","method":"undo_a"}}},
+	{"id":"B","type":"task","config":{"service":"svc","method":"b","compensate":{"service":"svc","method":"undo_b"}}},
+	{"id":"C","type":"task","config":{"service":"svc","method":"c"}}
+],"edges":[{"from":"A","to":"B"},{"from":"B","to":"C"}]}`
+
+type compensationJob struct {
+	ID           uint64
+	Method       string
+	ArgsJSON     string
+	CallbackData string
+}
+
+// pendingCompensationJobs 按派发先后列出实例的补偿任务
+func pendingCompensationJobs(t *testing.T, db *gorm.DB, instanceID int64) []compensationJob {
+	t.Helper()
+	var jobs []compensationJob
+	if err := db.Table("aio_executor_jobs").Select("id, method, args_json, callback_data").
+		Where("dedup_key LIKE ?", fmt.Sprintf("wf_%d_compensate_%%", instanceID)).
+		Order("id asc").Find(&jobs).Error; err != nil {
+		t.Fatalf("query compensation jobs: %v", err)
+	}
+	return jobs
+}
+
+func instanceStatus(t *testing.T, a *App, id int64) model.WorkflowInstanceStatus {
+	t.Helper()
+	inst, err := a.GetInstance(context.Background(), id)
+	if err != nil {
+		t.Fatalf("get instance: %v", err)
+	}
+	return inst.Status
+}
+
+// 节点失败且无 error 边时自动补偿：按 checkpoint 逆序先补偿 B 再补偿 A，补偿任务携带
+// 节点当时的输出；上一步成功后才派发下一步，重复回调不会多推进一步。
+func TestCompensationRunsInReverseOnFailure(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+	if _, err := a.CreateDef(ctx, "test", "saga_def", "saga", compensationTestDAG, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	id, err := a.StartWorkflow(ctx, "saga_def", nil, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	for _, step := range []struct {
+		node   string
+		output map[string]interface{}
+	}{
+		{"A", map[string]interface{}{"order_id": "o-1"}},
+		{"B", map[string]interface{}{"payment_id": "p-1"}},
+		{"C", map[string]interface{}{"error_msg": "发货失败"}},
+	} {
+		if err := a.ReportNodeCompleted(ctx, id, step.node, step.output, "test"); err != nil {
+			t.Fatalf("report %s: %v", step.node, err)
+		}
+	}
+	if s := instanceStatus(t, a, id); s != model.InstanceStatusCompensating {
+		t.Fatalf("status = %s, want COMPENSATING", s)
+	}
+
+	jobs := pendingCompensationJobs(t, db, id)
+	if len(jobs) != 1 || jobs[0].Method != "undo_b" {
+		t.Fatalf("首个补偿任务 = %+v, want 仅 undo_b", jobs)
+	}
+	var args map[string]interface{}
+	_ = json.Unmarshal([]byte(jobs[0].ArgsJSON), &args)
+	if out, _ := args["output"].(map[string]interface{}); out["payment_id"] != "p-1" {
+		t.Fatalf("补偿任务未携带节点输出: %v", args)
+	}
+	for i := 0; i < 2; i++ {
+		if err := a.OnJobCompleted(ctx, jobs[0].ID, jobs[0].CallbackData, `{}`); err != nil {
+			t.Fatalf("undo_b callback #%d: %v", i, err)
+		}
+	}
+
+	jobs = pendingCompensationJobs(t, db, id)
+	if len(jobs) != 2 || jobs[1].Method != "undo_a" {
+		t.Fatalf("补偿任务 = %+v, want undo_b 后派发 undo_a", jobs)
+	}
+	if err := a.OnJobCompleted(ctx, jobs[1].ID, jobs[1].CallbackData, `{}`); err != nil {
+		t.Fatalf("undo_a callback: %v", err)
+	}
+	if s := instanceStatus(t, a, id); s != model.InstanceStatusCompensated {
+		t.Fatalf("status = %s, want COMPENSATED", s)
+	}
+}
+
+// 运维对运行中的实例触发补偿；补偿任务失败时实例 FAILED 且不能再重试节点，
+// 再次触发补偿从失败的那一步继续直至 COMPENSATED。
+func TestCompensateInstanceResumesAfterFailure(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+	if _, err := a.CreateDef(ctx, "test", "saga_def", "saga", compensationTestDAG, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	id, err := a.StartWorkflow(ctx, "saga_def", nil, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := a.ReportNodeCompleted(ctx, id, "A", map[string]interface{}{"order_id": "o-1"}, "test"); err != nil {
+		t.Fatalf("report A: %v", err)
+	}
+
+	if err := a.CompensateInstance(ctx, id); err != nil {
+		t.Fatalf("compensate: %v", err)
+	}
+	jobs := pendingCompensationJobs(t, db, id)
+	if len(jobs) != 1 || jobs[0].Method != "undo_a" {
+		t.Fatalf("补偿任务 = %+v, want 仅 undo_a", jobs)
+	}
+	if err := a.OnJobCompleted(ctx, jobs[0].ID, jobs[0].CallbackData, `{"error_msg":"退款接口不可用"}`); err != nil {
+		t.Fatalf("undo_a failed callback: %v", err)
+	}
+	if s := instanceStatus(t, a, id); s != model.InstanceStatusFailed {
+		t.Fatalf("status = %s, want FAILED", s)
+	}
+	if err := a.RetryNode(ctx, id, "B"); err == nil {
+		t.Fatal("补偿过的实例重试节点未被拒绝")
+	}
+
+	if err := a.CompensateInstance(ctx, id); err != nil {
+		t.Fatalf("compensate again: %v", err)
+	}
+	jobs = pendingCompensationJobs(t, db, id)
+	if len(jobs) != 2 || jobs[1].Method != "undo_a" {
+		t.Fatalf("补偿任务 = %+v, want 重新派发 undo_a", jobs)
+	}
+	if err := a.OnJobCompleted(ctx, jobs[1].ID, jobs[1].CallbackData, `{}`); err != nil {
+		t.Fatalf("undo_a callback: %v", err)
+	}
+	if s := instanceStatus(t, a, id); s != model.InstanceStatusCompensated {
+		t.Fatalf("status = %s, want COMPENSATED", s)
+	}
+	if err := a.CompensateInstance(ctx, id); err == nil {
+		t.Fatal("已补偿的实例再次补偿未被拒绝")
+	}
+}
+
+// compensate 只能配置在 task 节点上，且必须给出 service 与 method
+func TestCompensateConfigValidation(t *testing.T) {
+	ctx := context.Background()
+	a, _ := newTestApp(t)
+	bad := []string{
+		`{"nodes":[{"id":"T","type":"task","config":{"compensate":{"service":"svc"}}}]}`,
+		`{"nodes":[{"id":"W","type":"timer","config":{"duration":"1m","compensate":{"service":"svc","method":"m"}}}]}`,
+	}
+	for i, dagJSON := range bad {
+		if _, err := a.CreateDef(ctx, "test", "bad_saga", "bad", dagJSON, int32(i+1)); err == nil {
+			t.Fatalf("dag #%d 未被拒绝", i)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+// 职责：实例事件流——推进过程中落库节点触发/完成、状态变化、等待、信号与终态事件，
+// 并以「按序号续传」的方式向 WatchInstance / WatchDef 订阅方推送。
+//
+// 边界：事件与状态推进写在同一事务内，推进回滚时事件一并消失，订阅方不会看到
+// 未生效的变化。推送侧按间隔轮询事件表而非进程内广播：多个 aio 实例共享同一张表，
+// 订阅落在任一实例上都能收到其余实例推进产生的事件。事件表不在此处清理。
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/xsxdot/aio/base"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+)
+
+// WorkflowInstanceEventModel 类型别名，供 api/client 等层使用
+type WorkflowInstanceEventModel = model.WorkflowInstanceEventModel
+
+const (
+	// instanceEventPollInterval 订阅方无新事件时的轮询间隔
+	instanceEventPollInterval = 500 * time.Millisecond
+	// instanceEventBatchSize 单次轮询读取的事件上限
+	instanceEventBatchSize = 200
+)
+
+// recordInstanceEvent 记录一条实例事件，Status 取 inst 当前内存中的状态。
+// EventService 未装配时（不涉及事件的测试）直接跳过
+func (a *App) recordInstanceEvent(ctx context.Context, inst *model.WorkflowInstanceModel, typ model.InstanceEventType, nodeID string, payload map[string]interface{}) error {
+	if a.EventService == nil {
+		return nil
+	}
+	event := &model.WorkflowInstanceEventModel{
+		InstanceID: inst.ID,
+		DefID:      inst.DefID,
+		Env:        inst.Env,
+		Type:       typ,
+		NodeID:     nodeID,
+		Status:     string(inst.Status),
+	}
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return a.err.New("序列化实例事件失败", err)
+		}
+		event.Payload = string(b)
+	}
+	return a.EventService.Append(ctx, event)
+}
+
+// recordStateChanged 业务数据（不含 _sys）与 before 不同时记录 state_changed。
+//
+// 载荷只带本次变化的 JSON Merge Patch（RFC 7386），不带完整业务数据：事件与状态同事务落库且
+// 永久保留，每次推进都写整份状态会让事件表随状态大小 × 推进次数膨胀。订阅方从初始状态起
+// 依次合并即可还原任一时刻的业务数据。
+func (a *App) recordStateChanged(ctx context.Context, inst *model.WorkflowInstanceModel, nodeID, before string) error {
+	oldData, _, _ := parseWorkflowState(before)
+	newData, _, err := parseWorkflowState(inst.CurrentState)
+	if err != nil {
+		return nil
+	}
+	patch := jsonMergePatchDiff(oldData, newData)
+	if len(patch) == 0 {
+		return nil
+	}
+	return a.recordInstanceEvent(ctx, inst, model.InstanceEventStateChanged, nodeID, map[string]interface{}{"patch": patch})
+}
+
+// jsonMergePatchDiff 生成把 before 变为 after 的 JSON Merge Patch，与 applyJSONMergePatch 互逆：
+// 删除的键为 null，两侧都是对象时逐层比较，其余变化的值整体替换。after 中值为 null 的键
+// 按 Merge Patch 语义等同于删除
+func jsonMergePatchDiff(before, after map[string]interface{}) map[string]interface{} {
+	patch := make(map[string]interface{})
+	for k := range before {
+		if _, ok := after[k]; !ok {
+			patch[k] = nil
+		}
+	}
+	for k, v := range after {
+		old, existed := before[k]
+		if existed && reflect.DeepEqual(old, v) {
+			continue
+		}
+		om, oldIsMap := old.(map[string]interface{})
+		nm, newIsMap := v.(map[string]interface{})
+		if existed && oldIsMap && newIsMap {
+			if sub := jsonMergePatchDiff(om, nm); len(sub) > 0 {
+				patch[k] = sub
+			}
+			continue
+		}
+		patch[k] = v
+	}
+	return patch
+}
+
+// recordTerminalIfEntered 实例在本次操作中从非终态进入终态时记录 terminal
+func (a *App) recordTerminalIfEntered(ctx context.Context, inst *model.WorkflowInstanceModel, statusBefore model.WorkflowInstanceStatus) error {
+	if isTerminalStatus(statusBefore) || !isTerminalStatus(inst.Status) {
+		return nil
+	}
+	return a.recordInstanceEvent(ctx, inst, model.InstanceEventTerminal, "", nil)
+}
+
+// recordAdvanceEvents 记录一次节点推进产生的事件：节点完成、业务状态变化、进入终态
+func (a *App) recordAdvanceEvents(ctx context.Context, inst *model.WorkflowInstanceModel, nodeID string, nodeDone bool, output map[string]interface{}, stateBefore string, statusBefore model.WorkflowInstanceStatus) error {
+	if nodeDone {
+		_, failed := output["error_msg"]
+		if err := a.recordInstanceEvent(ctx, inst, model.InstanceEventNodeCompleted, nodeID, map[string]interface{}{
+			"output": output,
+			"failed": failed,
+		}); err != nil {
+			return err
+		}
+	}
+	if err := a.recordStateChanged(ctx, inst, nodeID, stateBefore); err != nil {
+		return err
+	}
+	return a.recordTerminalIfEntered(ctx, inst, statusBefore)
+}
+
+// WatchInstance 按序号推送实例中 seq > afterSeq 的事件，afterSeq=0 表示从头回放。
+//
+// 实例处于终态且已无未推送事件时返回 nil；fn 返回错误或 ctx 结束时提前返回。
+// 已结束的实例被信号唤醒后，订阅方以最后收到的 seq 重新订阅即可继续。
+func (a *App) WatchInstance(ctx context.Context, instanceID, afterSeq int64, fn func(*model.WorkflowInstanceEventModel) error) error {
+	if a.EventService == nil {
+		return a.err.New("实例事件未启用", nil)
+	}
+	if _, err := a.InstanceService.FindById(ctx, instanceID); err != nil {
+		if errorc.IsNotFound(err) {
+			return a.err.New("实例不存在", err).NotFound()
+		}
+		return err
+	}
+	for {
+		events, err := a.EventService.ListByInstanceAfterSeq(ctx, instanceID, afterSeq, instanceEventBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, e := range events {
+			if err := fn(e); err != nil {
+				return err
+			}
+			afterSeq = e.Seq
+		}
+		if len(events) == instanceEventBatchSize {
+			continue
+		}
+		if len(events) == 0 {
+			// 先读事件、再读状态：终态与 terminal 事件同事务提交，此处看到终态时其事件必已在上一轮读到
+			inst, err := a.InstanceService.FindById(ctx, instanceID)
+			if err != nil {
+				return err
+			}
+			if isTerminalStatus(inst.Status) {
+				return nil
+			}
+		}
+		if err := waitEventPoll(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// WatchDef 推送某环境下某定义编码（全部版本）所有实例的事件，直到 ctx 结束。
+//
+// afterID 为事件主键游标（跨实例全局递增），0 表示只推送订阅之后产生的事件。
+//
+// 注意：主键在插入时分配、按提交先后可见，并发推进下后分配的事件可能先提交，
+// 游标越过后先分配者将不再推送。需要逐条不漏的场景请按实例订阅（WatchInstance）。
+func (a *App) WatchDef(ctx context.Context, env, defCode string, afterID int64, fn func(*model.WorkflowInstanceEventModel) error) error {
+	if a.EventService == nil {
+		return a.err.New("实例事件未启用", nil)
+	}
+	if defCode == "" {
+		return a.err.New("def_code 不能为空", nil).WithCode(errorc.ErrorCodeValid)
+	}
+	if env == "" {
+		env = base.ENV
+	}
+	if afterID <= 0 {
+		maxID, err := a.EventService.MaxID(ctx)
+		if err != nil {
+			return err
+		}
+		afterID = maxID
+	}
+	for {
+		events, err := a.EventService.ListByDefAfterID(ctx, env, defCode, afterID, instanceEventBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, e := range events {
+			if err := fn(e); err != nil {
+				return err
+			}
+			afterID = e.ID
+		}
+		if len(events) == instanceEventBatchSize {
+			continue
+		}
+		if err := waitEventPoll(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+func waitEventPoll(ctx context.Context) error {
+	t := time.NewTimer(instanceEventPollInterval)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,191 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+)
+
+func collectInstanceEvents(t *testing.T, a *App, instanceID, afterSeq int64) []*model.WorkflowInstanceEventModel {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var events []*model.WorkflowInstanceEventModel
+	if err := a.WatchInstance(ctx, instanceID, afterSeq, func(e *model.WorkflowInstanceEventModel) error {
+		events = append(events, e)
+		return nil
+	}); err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+	return events
+}
+
+// 事件序号连续，且节点完成先于其下游触发；实例进入终态后订阅自然结束，
+// 携带最后收到的序号重连只推送其后的事件。
+func TestWatchInstanceOrderedEventsAndResume(t *testing.T) {
+	ctx := context.Background()
+	a, _ := newTestApp(t)
+	dagJSON := `{"nodes":[
+		{"id":"A","type":"approval"},
+		{"id":"T","type":"task","config":{"service":"svc","method":"m"}}
+	],"edges":[{"from":"A","to":"T"}]}`
+	if _, err := a.CreateDef(ctx, "test", "watch_def", "watch", dagJSON, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	id, err := a.StartWorkflow(ctx, "watch_def", map[string]interface{}{"n": 1}, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := a.ReportNodeCompleted(ctx, id, "A", map[string]interface{}{"approved": true}, "test"); err != nil {
+		t.Fatalf("complete A: %v", err)
+	}
+	if err := a.ReportNodeCompleted(ctx, id, "T", map[string]interface{}{"done": true}, "test"); err != nil {
+		t.Fatalf("complete T: %v", err)
+	}
+
+	events := collectInstanceEvents(t, a, id, 0)
+	want := []struct {
+		typ  model.InstanceEventType
+		node string
+	}{
+		{model.InstanceEventNodeTriggered, "A"},
+		{model.InstanceEventWaiting, "A"},
+		{model.InstanceEventNodeCompleted, "A"},
+		{model.InstanceEventStateChanged, "A"},
+		{model.InstanceEventNodeTriggered, "T"},
+		{model.InstanceEventNodeCompleted, "T"},
+		{model.InstanceEventStateChanged, "T"},
+		{model.InstanceEventTerminal, ""},
+	}
+	if len(events) != len(want) {
+		for _, e := range events {
+			t.Logf("seq=%d type=%s node=%s", e.Seq, e.Type, e.NodeID)
+		}
+		t.Fatalf("事件数 = %d, want %d", len(events), len(want))
+	}
+	for i, e := range events {
+		if e.Seq != int64(i+1) || e.Type != want[i].typ || e.NodeID != want[i].node {
+			t.Fatalf("events[%d] = seq=%d %s/%s, want seq=%d %s/%s", i, e.Seq, e.Type, e.NodeID, i+1, want[i].typ, want[i].node)
+		}
+	}
+	if last := events[len(events)-1]; last.Status != string(model.InstanceStatusCompleted) {
+		t.Fatalf("terminal status = %s, want COMPLETED", last.Status)
+	}
+
+	resumed := collectInstanceEvents(t, a, id, 5)
+	if len(resumed) != 3 || resumed[0].Seq != 6 {
+		t.Fatalf("续传得到 %d 条、首条 seq=%d, want 3 条且从 6 开始", len(resumed), resumed[0].Seq)
+	}
+}
+
+// 按定义订阅从订阅时刻开始，只推送该定义编码下的实例事件。
+func TestWatchDefStreamsNewInstances(t *testing.T) {
+	a, _ := newTestApp(t)
+	dagJSON := `{"nodes":[{"id":"T","type":"task","config":{"service":"svc","method":"m"}}]}`
+	for _, code := range []string{"watched", "other"} {
+		if _, err := a.CreateDef(context.Background(), "test", code, code, dagJSON, 1); err != nil {
+			t.Fatalf("create def: %v", err)
+		}
+	}
+	if _, err := a.StartWorkflow(context.Background(), "watched", nil, "test"); err != nil {
+		t.Fatalf("start before watch: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	got := make(chan *model.WorkflowInstanceEventModel, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- a.WatchDef(ctx, "test", "watched", 0, func(e *model.WorkflowInstanceEventModel) error {
+			got <- e
+			return nil
+		})
+	}()
+	time.Sleep(100 * time.Millisecond)
+	if _, err := a.StartWorkflow(context.Background(), "other", nil, "test"); err != nil {
+		t.Fatalf("start other: %v", err)
+	}
+	id, err := a.StartWorkflow(context.Background(), "watched", nil, "test")
+	if err != nil {
+		t.Fatalf("start watched: %v", err)
+	}
+
+	select {
+	case e := <-got:
+		if e.InstanceID != id || e.Type != model.InstanceEventNodeTriggered {
+			t.Fatalf("event = instance %d %s, want 订阅后新实例 %d 的 node_triggered", e.InstanceID, e.Type, id)
+		}
+	case <-ctx.Done():
+		t.Fatal("未收到订阅后新实例的事件")
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("watch 返回 %v, want context.Canceled", err)
+	}
+}
+
+// state_changed 只带相对上一状态的 Merge Patch，从初始状态依次合并即还原当前业务数据；
+// 事件序号来自实例行上的计数器：推进中途派发 Map 节点会用推进开始时读到的实例整行更新，
+// 不能把计数器覆盖回旧值而让后续事件撞序号。
+func TestStateChangedPatchAndEventSeqCounter(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+	dagJSON := `{"nodes":[
+		{"id":"A","type":"task","config":{"service":"svc","method":"a"}},
+		{"id":"M","type":"map","config":{"items_path":"items","output_path":"results","iterator":{"service":"svc","method":"each"}}}
+	],"edges":[{"from":"A","to":"M"}]}`
+	if _, err := a.CreateDef(ctx, "test", "patch_def", "patch", dagJSON, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	initial := map[string]interface{}{"items": []interface{}{"a"}, "order": map[string]interface{}{"id": 1, "status": "new"}}
+	id, err := a.StartWorkflow(ctx, "patch_def", initial, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := a.ReportNodeCompleted(ctx, id, "A", map[string]interface{}{"order": map[string]interface{}{"id": 1, "status": "paid"}}, "test"); err != nil {
+		t.Fatalf("complete A: %v", err)
+	}
+	if err := a.reportNodeCompleted(ctx, 0, id, "M", map[string]interface{}{"v": "A"}, "test", 0); err != nil {
+		t.Fatalf("complete M: %v", err)
+	}
+
+	inst, _ := a.GetInstance(ctx, id)
+	state, _, _ := parseWorkflowState(inst.InitialState)
+	var patches []string
+	events := collectInstanceEvents(t, a, id, 0)
+	for _, e := range events {
+		if e.Type != model.InstanceEventStateChanged {
+			continue
+		}
+		var payload map[string]interface{}
+		if err := json.Unmarshal([]byte(e.Payload), &payload); err != nil {
+			t.Fatalf("解析载荷: %v", err)
+		}
+		if _, ok := payload["state"]; ok {
+			t.Fatalf("载荷仍带完整状态: %s", e.Payload)
+		}
+		patch, _ := payload["patch"].(map[string]interface{})
+		state = applyJSONMergePatch(state, patch)
+		patches = append(patches, e.Payload)
+	}
+	if want := []string{`{"patch":{"order":{"status":"paid"}}}`, `{"patch":{"results":[{"v":"A"}]}}`}; !reflect.DeepEqual(patches, want) {
+		t.Fatalf("patches = %v, want %v", patches, want)
+	}
+	current, _, _ := parseWorkflowState(inst.CurrentState)
+	if !reflect.DeepEqual(state, current) {
+		t.Fatalf("合并结果 %v != 当前状态 %v", state, current)
+	}
+
+	var counter int64
+	if err := db.Table("aio_workflow_instance").Select("event_seq").Where("id = ?", id).Row().Scan(&counter); err != nil {
+		t.Fatalf("读取计数器: %v", err)
+	}
+	if last := events[len(events)-1].Seq; counter != last || last != int64(len(events)) {
+		t.Fatalf("event_seq = %d, 末条 seq = %d, 事件数 = %d", counter, last, len(events))
+	}
+}
@@ -0,0 +1,199 @@
+// 职责：从历史检查点分叉——以某个 checkpoint 的 StateAfter（可叠加 JSON Merge Patch）为
+// 起点，创建同一定义版本的新实例，并从该节点的后继继续执行。
+//
+// 边界：来源实例只读不写。新实例复制来源实例截至该检查点（含）的 checkpoint 轨迹，
+// 使汇聚判定、回滚与轨迹查询在新实例上照常工作；_sys 只继承所在循环的栈与轮次，使循环体
+// 内的分叉点按原轮次继续（Map 计数、暂停与补偿现场都属于来源实例的运行时记账，不继承）。
+// 后继按与推进相同的边选择规则（engine_route.go）确定，只派发所选节点的后继：来源实例在
+// 该时刻仍在运行的其他并行分支不会被带入新实例。
+package app
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/xsxdot/aio/base"
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"gorm.io/gorm"
+)
+
+// applyJSONMergePatch 按 RFC 7386 将 patch 合并进 target：值为 null 的键被删除，
+// 两侧同为对象时递归合并，其余情况整体替换。返回新 map，不修改入参
+func applyJSONMergePatch(target, patch map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(target)+len(patch))
+	for k, v := range target {
+		out[k] = v
+	}
+	for k, v := range patch {
+		if v == nil {
+			delete(out, k)
+			continue
+		}
+		if pm, ok := v.(map[string]interface{}); ok {
+			tm, _ := out[k].(map[string]interface{})
+			out[k] = applyJSONMergePatch(tm, pm)
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// forkInheritedSysKeys 分叉时从检查点 _sys 继承的键：进行中循环的栈与各循环的当前轮次
+var forkInheritedSysKeys = []string{"loop_stack", "loops"}
+
+// forkSuccessors 按节点当次输出选择分叉后要派发的后继：带 error_msg 时走 error/always 边，
+// 否则走成功出边；loop 节点的走向由检查点中的循环栈还原（仍在栈中即继续循环体）。
+// 汇聚节点按复制来的 checkpoint 判定
+func (a *App) forkSuccessors(ctx context.Context, tx *gorm.DB, instanceID int64, node *model.Node, output map[string]interface{}, data workflowStateData, sys workflowStateSys, dag *model.DAG, env string) []string {
+	_, failed := output["error_msg"]
+	outcome := nodeOutcome{Node: node, Output: output, Failed: failed}
+	if node.Type == model.NodeTypeLoop && !failed {
+		outcome.LoopRoute = loopRouteExit
+		if containsString(loopStack(sys), node.ID) {
+			outcome.LoopRoute = loopRouteBody
+		}
+	}
+	edges := a.selectOutEdges(dag, node.ID, outcome, data, sys)
+	var active, next []string
+	a.scheduleEdges(ctx, tx, instanceID, dag, env, edges, &active, &next)
+	return next
+}
+
+// ForkInstance 从来源实例的某个检查点分叉出新实例并返回其 ID。
+//
+// 新实例使用来源实例的定义版本，业务数据为该检查点的 StateAfter 叠加 patch（JSON Merge Patch，
+// 可为空），环境为 env（为空时沿用来源实例）。所选节点的后继立即派发；没有后继时
+// 按节点成败直接进入 COMPLETED / FAILED。
+func (a *App) ForkInstance(ctx context.Context, sourceInstanceID, checkpointID int64, patch map[string]interface{}, env string) (int64, error) {
+	source, err := a.InstanceService.FindById(ctx, sourceInstanceID)
+	if err != nil {
+		if errorc.IsNotFound(err) {
+			return 0, a.err.New("来源实例不存在", err).NotFound()
+		}
+		return 0, a.err.New("获取来源实例失败", err)
+	}
+	checkpoints, err := a.CheckpointService.ListByInstanceIDOrderByCreatedAsc(ctx, sourceInstanceID)
+	if err != nil {
+		return 0, a.err.New("获取检查点失败", err)
+	}
+	index := -1
+	for i, cp := range checkpoints {
+		if cp.ID == checkpointID {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return 0, a.err.New("检查点不属于该实例", nil).WithCode(errorc.ErrorCodeValid)
+	}
+	checkpoint := checkpoints[index]
+
+	def, err := a.DefService.FindById(ctx, source.DefID)
+	if err != nil {
+		return 0, a.err.New("获取工作流定义失败", err)
+	}
+	var dag model.DAG
+	if err := json.Unmarshal([]byte(def.DAGJSON), &dag); err != nil {
+		return 0, a.err.New("解析DAG失败", err)
+	}
+	dag.Normalize()
+	if dag.GetNode(checkpoint.NodeID) == nil {
+		return 0, a.err.New("检查点 "+checkpoint.NodeID+" 不是定义中的节点，不能作为分叉点", nil).WithCode(errorc.ErrorCodeValid)
+	}
+
+	data, cpSys, err := parseWorkflowState(checkpoint.StateAfter)
+	if err != nil {
+		return 0, a.err.New("解析检查点状态失败", err)
+	}
+	if len(patch) > 0 {
+		data = applyJSONMergePatch(data, patch)
+	}
+	sys := make(workflowStateSys)
+	for _, key := range forkInheritedSysKeys {
+		if v, ok := cpSys[key]; ok {
+			sys[key] = v
+		}
+	}
+	stateStr, err := serializeWorkflowState(data, sys)
+	if err != nil {
+		return 0, a.err.New("序列化状态失败", err)
+	}
+	var output map[string]interface{}
+	if checkpoint.NodeOutput != "" {
+		_ = json.Unmarshal([]byte(checkpoint.NodeOutput), &output)
+	}
+	if env == "" {
+		env = source.Env
+	}
+	if env == "" {
+		env = base.ENV
+	}
+
+	instance := &model.WorkflowInstanceModel{
+		DefID:                  source.DefID,
+		DefVersion:             source.DefVersion,
+		Env:                    env,
+		Status:                 model.InstanceStatusRunning,
+		InitialState:           source.InitialState,
+		CurrentState:           stateStr,
+		ActiveNodeIDs:          "[]",
+		ForkedFromInstanceID:   source.ID,
+		ForkedFromCheckpointID: checkpoint.ID,
+	}
+	err = mvc.ExtractDB(ctx, base.DB).Transaction(func(tx *gorm.DB) error {
+		txCtx := mvc.WithTxToContext(ctx, tx)
+		if err := tx.Create(instance).Error; err != nil {
+			return err
+		}
+		trail := make([]*model.WorkflowCheckpointModel, 0, index+1)
+		for _, cp := range checkpoints[:index+1] {
+			copied := &model.WorkflowCheckpointModel{
+				InstanceID: instance.ID,
+				NodeID:     cp.NodeID,
+				NodeOutput: cp.NodeOutput,
+				StateAfter: cp.StateAfter,
+			}
+			copied.CreatedAt = cp.CreatedAt
+			trail = append(trail, copied)
+		}
+		// 分叉点的状态以叠加 patch 后的为准，后继节点据此读取
+		trail[index].StateAfter = stateStr
+		if err := tx.Create(&trail).Error; err != nil {
+			return err
+		}
+
+		next := a.forkSuccessors(txCtx, tx, instance.ID, dag.GetNode(checkpoint.NodeID), output, data, sys, &dag, env)
+		if len(next) == 0 {
+			instance.Status = model.InstanceStatusCompleted
+			if _, failed := output["error_msg"]; failed {
+				instance.Status = model.InstanceStatusFailed
+			}
+			if err := tx.Save(instance).Error; err != nil {
+				return err
+			}
+			return a.recordInstanceEvent(txCtx, instance, model.InstanceEventTerminal, "", nil)
+		}
+		activeJSON, _ := json.Marshal(next)
+		instance.ActiveNodeIDs = string(activeJSON)
+		if err := tx.Save(instance).Error; err != nil {
+			return err
+		}
+		for _, nodeID := range next {
+			if err := a.triggerNode(txCtx, instance, dag.GetNode(nodeID), &dag, env); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, a.err.New("分叉实例失败", err)
+	}
+	a.log.WithField("instance_id", instance.ID).
+		WithField("source_instance_id", source.ID).
+		WithField("checkpoint_id", checkpoint.ID).
+		Info("已从检查点分叉新实例")
+	return instance.ID, nil
+}
@@ -0,0 +1,174 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+)
+
+const forkTestDAG = `{"nodes":[
+	{"id":"A","type":"task","config":{"service":"svc","method":"a"}},
+	{"id":"B","type":"task","config":{"service":"svc","method":"b"}},
+	{"id":"C","type":"task","config":{"service":"svc","method":"c"}}
+],"edges":[{"from":"A","to":"B"},{"from":"B","to":"C"}]}`
+
+// runForkSource 启动一个实例并依次回调 A、B 成功、C 失败，返回实例 ID 与按节点索引的检查点 ID
+func runForkSource(t *testing.T, a *App) (int64, map[string]int64) {
+	t.Helper()
+	ctx := context.Background()
+	if _, err := a.CreateDef(ctx, "test", "fork_def", "fork", forkTestDAG, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	id, err := a.StartWorkflow(ctx, "fork_def", nil, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	for _, step := range []struct {
+		node   string
+		output map[string]interface{}
+	}{
+		{"A", map[string]interface{}{"x": 1}},
+		{"B", map[string]interface{}{"y": 2}},
+		{"C", map[string]interface{}{"error_msg": "下游不可用"}},
+	} {
+		if err := a.ReportNodeCompleted(ctx, id, step.node, step.output, "test"); err != nil {
+			t.Fatalf("report %s: %v", step.node, err)
+		}
+	}
+	trail, err := a.GetExecutionTrail(ctx, id)
+	if err != nil {
+		t.Fatalf("trail: %v", err)
+	}
+	cps := make(map[string]int64)
+	for _, cp := range trail.Checkpoints {
+		cps[cp.NodeID] = cp.CheckpointID
+	}
+	return id, cps
+}
+
+// 从 B 的检查点分叉：新实例带着叠加 patch 后的状态（null 删除键）从 C 继续，
+// 复制 A、B 的轨迹并记录来源；来源实例保持 FAILED 不受影响。
+func TestForkInstanceFromCheckpointWithPatch(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+	sourceID, cps := runForkSource(t, a)
+
+	forkID, err := a.ForkInstance(ctx, sourceID, cps["B"], map[string]interface{}{"y": 3, "x": nil}, "")
+	if err != nil {
+		t.Fatalf("fork: %v", err)
+	}
+	inst, err := a.GetInstance(ctx, forkID)
+	if err != nil {
+		t.Fatalf("get fork: %v", err)
+	}
+	if inst.Status != model.InstanceStatusRunning || inst.ActiveNodeIDs != `["C"]` {
+		t.Fatalf("status=%s active=%s, want RUNNING [C]", inst.Status, inst.ActiveNodeIDs)
+	}
+	if inst.ForkedFromInstanceID != sourceID || inst.ForkedFromCheckpointID != cps["B"] || inst.Env != "test" {
+		t.Fatalf("来源信息不正确: %+v", inst)
+	}
+	data, _, _ := parseWorkflowState(inst.CurrentState)
+	if _, ok := data["x"]; ok || data["y"] != float64(3) {
+		t.Fatalf("patch 未生效: %v", data)
+	}
+	if n := countNodeJobs(t, db, forkID, "C", ""); n != 1 {
+		t.Fatalf("分叉实例 C 的任务数 = %d, want 1", n)
+	}
+	trail, err := a.GetExecutionTrail(ctx, forkID)
+	if err != nil {
+		t.Fatalf("fork trail: %v", err)
+	}
+	if len(trail.Checkpoints) != 2 || trail.Checkpoints[1].NodeID != "B" {
+		t.Fatalf("分叉实例轨迹 = %+v, want A、B", trail.Checkpoints)
+	}
+
+	if s := instanceStatus(t, a, sourceID); s != model.InstanceStatusFailed {
+		t.Fatalf("source status = %s, want FAILED", s)
+	}
+	if err := a.ReportNodeCompleted(ctx, forkID, "C", map[string]interface{}{"ok": true}, "test"); err != nil {
+		t.Fatalf("report fork C: %v", err)
+	}
+	if s := instanceStatus(t, a, forkID); s != model.InstanceStatusCompleted {
+		t.Fatalf("fork status = %s, want COMPLETED", s)
+	}
+}
+
+// 检查点必须属于来源实例；从没有后继的失败节点分叉时新实例直接 FAILED
+func TestForkInstanceRejectsForeignCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	a, _ := newTestApp(t)
+	sourceID, cps := runForkSource(t, a)
+	otherID, err := a.StartWorkflow(ctx, "fork_def", nil, "test")
+	if err != nil {
+		t.Fatalf("start other: %v", err)
+	}
+	if _, err := a.ForkInstance(ctx, otherID, cps["A"], nil, ""); err == nil {
+		t.Fatal("其他实例的检查点未被拒绝")
+	}
+
+	forkID, err := a.ForkInstance(ctx, sourceID, cps["C"], nil, "")
+	if err != nil {
+		t.Fatalf("fork at C: %v", err)
+	}
+	if s := instanceStatus(t, a, forkID); s != model.InstanceStatusFailed {
+		t.Fatalf("status = %s, want FAILED", s)
+	}
+}
+
+// 从循环体内 loop 节点的检查点分叉：新实例继承循环栈与当前轮次，只进入循环体且 loop.index
+// 沿用原轮次；此后回到 loop 节点时按继承的轮次正常退出。
+func TestForkInstanceInsideLoopKeepsLoopContext(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+	dagJSON := `{"nodes":[
+		{"id":"S","type":"task","config":{"service":"svc","method":"s"}},
+		{"id":"L","type":"loop","config":{"body":"P","max_iterations":5,"while":"state.has_more"}},
+		{"id":"P","type":"task","config":{"service":"svc","method":"page","input_mapping":{"page_no":"loop.index + 1"}}},
+		{"id":"N","type":"task","config":{"service":"svc","method":"n"}}
+	],"edges":[
+		{"from":"S","to":"L"},{"from":"L","to":"P"},{"from":"L","to":"N"},
+		{"from":"P","to":"L","is_loopback":true}
+	]}`
+	if _, err := a.CreateDef(ctx, "test", "fork_loop", "fork_loop", dagJSON, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	sourceID, err := a.StartWorkflow(ctx, "fork_loop", map[string]interface{}{"has_more": true}, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	for _, node := range []string{"S", "P"} {
+		if err := a.ReportNodeCompleted(ctx, sourceID, node, nil, "test"); err != nil {
+			t.Fatalf("report %s: %v", node, err)
+		}
+	}
+	trail, err := a.GetExecutionTrail(ctx, sourceID)
+	if err != nil {
+		t.Fatalf("trail: %v", err)
+	}
+	var loopCp int64
+	for _, cp := range trail.Checkpoints {
+		if cp.NodeID == "L" {
+			loopCp = cp.CheckpointID
+		}
+	}
+
+	forkID, err := a.ForkInstance(ctx, sourceID, loopCp, nil, "")
+	if err != nil {
+		t.Fatalf("fork: %v", err)
+	}
+	if countNodeJobs(t, db, forkID, "P", "") != 1 || countNodeJobs(t, db, forkID, "N", "") != 0 {
+		t.Fatal("分叉点为循环中的 loop 节点，应只进入循环体")
+	}
+	payload := latestNodePayload(t, db, forkID, "P")
+	input, _ := payload["input"].(map[string]interface{})
+	if input["page_no"] != float64(2) {
+		t.Fatalf("分叉后循环体入参 = %v，want 沿用第 1 轮", input)
+	}
+	if err := a.ReportNodeCompleted(ctx, forkID, "P", map[string]interface{}{"has_more": false}, "test"); err != nil {
+		t.Fatalf("report P: %v", err)
+	}
+	if countNodeJobs(t, db, forkID, "N", "") != 1 {
+		t.Fatal("分叉实例回到 loop 节点后未走退出边")
+	}
+}
@@ -1,9 +1,10 @@
 // 职责：为「单次推进内的同步递归」提供跳数护栏，防止纯 condition 回环把
 // 进程递归到栈溢出。
 //
-// 边界：只约束不落地即返回的同步跳转（当前仅 condition 节点走这条路径）。
+// 边界：只约束不落地即返回的同步跳转（condition、transform、loop 节点走这条路径）。
 // 经由 executor 任务或人工审批驱动的正常回环迭代不受影响——那些每一跳都是
-// 独立的一次推进，进入本护栏时计数从零开始。
+// 独立的一次推进，进入本护栏时计数从零开始；同步循环体的多轮迭代由 loop 节点
+// 逐轮推进（见 engine_loop.go），也不会累加跳数。
 package app
 
 import (
@@ -34,8 +35,8 @@ type advanceGuardKeyType struct{}
 // 支持 Unwrap 不由本包决定，靠错误身份传递会在包装实现变化时静默失效。
 type advanceGuard struct {
 	limitHit bool
-	// path 记录本次递归链经过的节点，超限时用于定位是哪条回环。
-	// 长度天然被 maxAdvanceHops 限制。
+	// path 记录当前递归链经过的节点，超限时用于定位是哪条回环。
+	// 按跳数截断，长度天然被 maxAdvanceHops 限制。
 	path []string
 }
 
@@ -56,6 +57,9 @@ func enterAdvanceHop(ctx context.Context, nodeID string) (newCtx context.Context
 		g = &advanceGuard{}
 		ctx = context.WithValue(ctx, advanceGuardKeyType{}, g)
 	}
+	if hop < len(g.path) {
+		g.path = g.path[:hop]
+	}
 	g.path = append(g.path, nodeID)
 	if hop >= maxAdvanceHops {
 		g.limitHit = true
@@ -81,9 +85,20 @@ func (a *App) failInstanceForHopLimit(ctx context.Context, instanceID int64, pat
 		if inst == nil {
 			return nil
 		}
+		wasTerminal := isTerminalStatus(inst.Status)
 		inst.Status = model.InstanceStatusFailed
 		inst.ActiveNodeIDs = "[]"
-		return a.InstanceService.SaveWithTx(ctx, tx, inst)
+		if err := a.InstanceService.SaveWithTx(ctx, tx, inst); err != nil {
+			return err
+		}
+		if wasTerminal {
+			return nil
+		}
+		txCtx := mvc.WithTxToContext(ctx, tx)
+		if err := a.recordInstanceEvent(txCtx, inst, model.InstanceEventTerminal, "", nil); err != nil {
+			return err
+		}
+		return a.notifyParentOfChildTerminal(txCtx, inst)
 	})
 	if err != nil {
 		a.log.WithErr(err).
@@ -98,3 +98,38 @@ func TestEnterAdvanceHopAllowsNormalDepth(t *testing.T) {
 		}
 	}
 }
+
+// 经由 any 汇聚的纯 condition 自回环：回环重入 join 节点时不走汇聚计数，
+// 护栏仍必须拦住无限递归并把实例终结为 FAILED，而不是让汇聚逻辑把回环「吞掉」。
+func TestHopGuardTripsThroughAnyJoinLoop(t *testing.T) {
+	ctx := context.Background()
+	a, _ := newTestApp(t)
+
+	dagJSON := `{"nodes":[
+		{"id":"START","type":"task","config":{"service":"svc","method":"s"}},
+		{"id":"X","type":"task","config":{"service":"svc","method":"x"}},
+		{"id":"C1","type":"condition","config":{"join":"any"}}
+	],"edges":[
+		{"from":"START","to":"C1"},{"from":"X","to":"C1"},
+		{"from":"C1","to":"C1","is_loopback":true}
+	]}`
+	// 定义校验会拒绝永远无法结束的环路，这里直接落库，模拟校验上线前已存在的定义
+	def := &model.WorkflowDefModel{Env: "test", Code: "hop_join", Name: "hop_join", Version: 1, DAGJSON: dagJSON}
+	if err := a.DefService.Create(ctx, def); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	id, err := a.StartWorkflow(ctx, "hop_join", map[string]interface{}{}, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := a.reportNodeCompleted(ctx, 0, id, "START", map[string]interface{}{}, "test"); err != nil {
+		t.Fatalf("超限应由最外层善后并返回 nil，实际: %v", err)
+	}
+	inst, err := a.GetInstance(ctx, id)
+	if err != nil {
+		t.Fatalf("get instance: %v", err)
+	}
+	if inst.Status != model.InstanceStatusFailed {
+		t.Fatalf("status = %s, want FAILED —— any 汇聚让纯 condition 回环绕过了跳数护栏", inst.Status)
+	}
+}
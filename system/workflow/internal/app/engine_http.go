@@ -0,0 +1,495 @@
+// 职责：http 节点——按节点配置调用外部 HTTP 接口，把响应写回状态。派发时渲染 URL/header
+// 模板并按 input_mapping 构造请求体，作为 executor 任务提交；请求由 executor 内部 worker
+// 调用 HandleInternalJob 发起，成功或失败都经常规任务确认与完成回调推进本节点。
+//
+// 边界：请求与普通 task 节点一样享有租约、重试（max_attempts/retry_backoff）、节点超时与
+// 完成回调 outbox 的保障；同一请求可能因重试或租约到期被发送多次，被调接口需自行幂等。
+// header 中的 ${config:key#path} 密钥引用只在发请求时从配置中心读取，不写入任务参数；
+// 引用须命中 HTTPSecretRules（workflow.http-secrets），创建定义、派发与发请求前各校验一次。
+// 网络错误、5xx、408、429 按可重试失败处理，其余不在成功状态码内的响应直接转死信并走
+// error 边。响应体上限 1MB。
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xsxdot/aio/base"
+	"github.com/xsxdot/aio/pkg/core/config"
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	executorCallback "github.com/xsxdot/aio/system/executor/api/callback"
+	executorDto "github.com/xsxdot/aio/system/executor/api/dto"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+)
+
+const (
+	// HTTPNodeMethod http 节点请求任务的 method，由内部 worker 交给 HandleInternalJob 执行
+	HTTPNodeMethod = "internal.workflow_http"
+
+	httpDefaultRequestTimeout = 10 * time.Second
+	// httpMaxRequestTimeout 单次请求超时上限，须小于内部 worker 的任务租约，避免请求未结束任务即被重投
+	httpMaxRequestTimeout = 50 * time.Second
+	httpMaxResponseBytes  = 1 << 20
+)
+
+// ConfigReader 读取配置中心配置（解密后的对象 JSON），http 节点据此解析 header 中的密钥引用
+type ConfigReader interface {
+	GetConfigJSON(ctx context.Context, key, env string) (string, error)
+}
+
+var (
+	// httpTemplateRe URL 与 header 中的 {{expr}} 模板
+	httpTemplateRe = regexp.MustCompile(`\{\{(.+?)\}\}`)
+	// httpSecretRefRe header 中的 ${config:key} 或 ${config:key#path} 密钥引用
+	httpSecretRefRe = regexp.MustCompile(`\$\{config:([^}#]+)(?:#([^}]+))?\}`)
+	// httpStatusRe success_status 的取值：三位状态码或 2xx 这样的状态码段
+	httpStatusRe = regexp.MustCompile(`^[1-5](xx|\d\d)$`)
+	httpMethods  = map[string]bool{
+		http.MethodGet: true, http.MethodPost: true, http.MethodPut: true,
+		http.MethodPatch: true, http.MethodDelete: true, http.MethodHead: true,
+	}
+	httpNodeClient = &http.Client{}
+)
+
+// httpNodeArgs http 请求任务的参数，派发时已完成模板渲染，执行时只解析密钥引用
+type httpNodeArgs struct {
+	InstanceID    int64             `json:"instance_id"`
+	NodeID        string            `json:"node_id"`
+	Env           string            `json:"env"`
+	DefCode       string            `json:"def_code"`
+	Method        string            `json:"method"`
+	URL           string            `json:"url"`
+	Headers       map[string]string `json:"headers,omitempty"`
+	Body          interface{}       `json:"body,omitempty"`
+	SuccessStatus []string          `json:"success_status"`
+	TimeoutMs     int64             `json:"timeout_ms"`
+	// Envelope 为 true 时结果为 {status, headers, body}，供 output_mapping 取状态码与响应头
+	Envelope bool `json:"envelope,omitempty"`
+}
+
+// triggerHTTPNode 渲染请求并提交到内部 worker 执行。
+//
+// 节点配置：
+//   - url: 必填，支持 {{expr}} 模板（基于 state 求值，结果按所在位置做路径或查询参数转义）
+//   - method: 默认有请求体时 POST，否则 GET
+//   - headers: 对象，值支持 {{expr}} 模板与 ${config:key#path} 密钥引用（须命中 HTTPSecretRules）
+//   - input_mapping: 作为 JSON 请求体
+//   - output_mapping: 可选，求值环境中 output 为 {status, headers, body}；未配置时响应体为对象则
+//     整体合并到状态，否则以 {"body": 响应体} 合并
+//   - success_status: 成功状态码列表，如 [200, "3xx"]，默认 ["2xx"]
+//   - request_timeout: 单次请求超时，默认 10s，不超过 50s；整体超时与重试同 task 节点（timeout、max_attempts 等）
+func (a *App) triggerHTTPNode(ctx context.Context, instance *model.WorkflowInstanceModel, node *model.Node, env string) error {
+	data, _, err := parseWorkflowState(instance.CurrentState)
+	if err != nil {
+		return a.err.New("解析状态失败", err)
+	}
+	args, err := buildHTTPNodeArgs(node.Config, data)
+	if err != nil {
+		return a.err.New("节点 "+node.ID+" 请求构造失败", &nodeInputError{msg: "请求构造失败", err: err})
+	}
+	def, err := a.DefService.FindByIdWithTx(ctx, mvc.ExtractDB(ctx, base.DB), instance.DefID)
+	if err != nil {
+		return err
+	}
+	if name, err := a.checkHTTPSecretRefs(env, def.Code, args.URL, args.Headers); err != nil {
+		return a.err.New("节点 "+node.ID+" header "+name+" 密钥引用被拒绝", err)
+	}
+	args.InstanceID = instance.ID
+	args.NodeID = node.ID
+	args.Env = env
+	args.DefCode = def.Code
+	policy, err := parseNodeJobPolicy(node.Config)
+	if err != nil {
+		return a.err.New("节点 "+node.ID+" 投递策略配置无效", err)
+	}
+
+	argsBytes, _ := json.Marshal(args)
+	callbackDataBytes, _ := json.Marshal(map[string]interface{}{"instance_id": instance.ID, "node_id": node.ID, "env": env})
+	dedupKey := nodeDedupPrefix(instance.ID, node.ID) + strconv.FormatInt(time.Now().UnixNano(), 10)
+	jobInput := &executorDto.SubmitJobInput{
+		Env:           env,
+		TargetService: executorCallback.InternalTargetService,
+		Method:        HTTPNodeMethod,
+		ArgsJSON:      string(argsBytes),
+		DedupKey:      dedupKey,
+		Source:        "workflow",
+		CallbackData:  string(callbackDataBytes),
+	}
+	policy.applyTo(jobInput)
+	if _, err := a.ExecutorClient.SubmitJob(ctx, jobInput); err != nil {
+		return a.err.New("提交 HTTP 请求任务到Executor失败", err)
+	}
+	if policy.Timeout > 0 {
+		return a.scheduleNodeTimeout(ctx, instance, node.ID, env, policy.Timeout)
+	}
+	return nil
+}
+
+// buildHTTPNodeArgs 按节点配置与当前状态渲染请求
+func buildHTTPNodeArgs(conf map[string]interface{}, data map[string]interface{}) (*httpNodeArgs, error) {
+	rawURL, _ := conf["url"].(string)
+	if rawURL == "" {
+		return nil, fmt.Errorf("http 节点缺少 url 配置")
+	}
+	env := map[string]interface{}{"state": filterStateForExpr(data)}
+	renderedURL, err := renderHTTPTemplate(rawURL, env, true)
+	if err != nil {
+		return nil, fmt.Errorf("url 渲染失败: %w", err)
+	}
+	if _, err := url.ParseRequestURI(renderedURL); err != nil {
+		return nil, fmt.Errorf("url 无效: %w", err)
+	}
+
+	args := &httpNodeArgs{URL: renderedURL, Envelope: conf["output_mapping"] != nil}
+	if headers, ok := conf["headers"].(map[string]interface{}); ok {
+		args.Headers = make(map[string]string, len(headers))
+		for name, raw := range headers {
+			tmpl, isStr := raw.(string)
+			if !isStr {
+				return nil, fmt.Errorf("headers.%s 必须是字符串", name)
+			}
+			v, err := renderHTTPTemplate(tmpl, env, false)
+			if err != nil {
+				return nil, fmt.Errorf("headers.%s 渲染失败: %w", name, err)
+			}
+			args.Headers[name] = v
+		}
+	}
+	body, mapped, err := buildNodeInput(conf, data, nil)
+	if err != nil {
+		return nil, err
+	}
+	if mapped {
+		args.Body = body
+	}
+
+	args.Method = http.MethodGet
+	if mapped {
+		args.Method = http.MethodPost
+	}
+	if m, ok := conf["method"].(string); ok && m != "" {
+		args.Method = strings.ToUpper(m)
+		if !httpMethods[args.Method] {
+			return nil, fmt.Errorf("不支持的请求方法 %s", m)
+		}
+	}
+	if args.SuccessStatus, err = parseHTTPSuccessStatus(conf["success_status"]); err != nil {
+		return nil, err
+	}
+	timeout, err := parseHTTPRequestTimeout(conf["request_timeout"])
+	if err != nil {
+		return nil, err
+	}
+	args.TimeoutMs = timeout.Milliseconds()
+	return args, nil
+}
+
+// renderHTTPTemplate 替换模板中的 {{expr}}。escape 为 true 时按占位符所在位置转义：
+// '?' 之前按路径段、之后按查询参数。state 取值中不允许出现密钥引用，防止借状态读取配置
+func renderHTTPTemplate(tmpl string, env map[string]interface{}, escape bool) (string, error) {
+	queryStart := strings.Index(tmpl, "?")
+	var sb strings.Builder
+	last := 0
+	for _, loc := range httpTemplateRe.FindAllStringSubmatchIndex(tmpl, -1) {
+		sb.WriteString(tmpl[last:loc[0]])
+		last = loc[1]
+		out, err := evalMappingExpr(strings.TrimSpace(tmpl[loc[2]:loc[3]]), env)
+		if err != nil {
+			return "", err
+		}
+		s := httpValueString(out)
+		if httpSecretRefRe.MatchString(s) {
+			return "", fmt.Errorf("模板取值不能包含配置引用")
+		}
+		if escape {
+			if queryStart >= 0 && loc[0] > queryStart {
+				s = url.QueryEscape(s)
+			} else {
+				s = url.PathEscape(s)
+			}
+		}
+		sb.WriteString(s)
+	}
+	sb.WriteString(tmpl[last:])
+	return sb.String(), nil
+}
+
+// httpValueString 把求值结果转为字符串：字符串原样输出，其余按 JSON 编码
+func httpValueString(v interface{}) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case nil:
+		return ""
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// parseHTTPSuccessStatus 解析成功状态码列表，未配置时为 ["2xx"]
+func parseHTTPSuccessStatus(raw interface{}) ([]string, error) {
+	if raw == nil {
+		return []string{"2xx"}, nil
+	}
+	list, ok := raw.([]interface{})
+	if !ok || len(list) == 0 {
+		return nil, fmt.Errorf("success_status 必须是非空数组")
+	}
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		var s string
+		switch v := item.(type) {
+		case string:
+			s = strings.ToLower(v)
+		case float64:
+			s = strconv.FormatFloat(v, 'f', -1, 64)
+		}
+		if !httpStatusRe.MatchString(s) {
+			return nil, fmt.Errorf("success_status 取值无效: %v", item)
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// parseHTTPRequestTimeout 解析单次请求超时，未配置时取默认值
+func parseHTTPRequestTimeout(raw interface{}) (time.Duration, error) {
+	if raw == nil {
+		return httpDefaultRequestTimeout, nil
+	}
+	d, err := toTimerDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("request_timeout 配置无效: %w", err)
+	}
+	if d <= 0 || d > httpMaxRequestTimeout {
+		return 0, fmt.Errorf("request_timeout 必须大于 0 且不超过 %s", httpMaxRequestTimeout)
+	}
+	return d, nil
+}
+
+// httpStatusMatches 判断状态码是否命中成功规则
+func httpStatusMatches(rules []string, code int) bool {
+	s := strconv.Itoa(code)
+	for _, rule := range rules {
+		if rule == s || (strings.HasSuffix(rule, "xx") && rule[0] == s[0]) {
+			return true
+		}
+	}
+	return false
+}
+
+// HandleInternalJob 执行 http 节点的请求任务（由 executor 内部 worker 调用）。
+// 返回错误时按任务重试策略重试；不可重试的失败经 executorCallback.Permanent 包装
+func (a *App) HandleInternalJob(ctx context.Context, argsJSON string) (string, error) {
+	var args httpNodeArgs
+	if err := json.Unmarshal([]byte(argsJSON), &args); err != nil {
+		return "", executorCallback.Permanent(fmt.Errorf("解析请求参数失败: %w", err))
+	}
+	if name, err := a.checkHTTPSecretRefs(args.Env, args.DefCode, args.URL, args.Headers); err != nil {
+		return "", executorCallback.Permanent(fmt.Errorf("header %s: %w", name, err))
+	}
+	headers := make(map[string]string, len(args.Headers))
+	for name, v := range args.Headers {
+		resolved, err := a.resolveSecretRefs(ctx, v, args.Env)
+		if err != nil {
+			return "", fmt.Errorf("header %s: %w", name, err)
+		}
+		headers[name] = resolved
+	}
+
+	var body io.Reader
+	if args.Body != nil {
+		b, err := json.Marshal(args.Body)
+		if err != nil {
+			return "", executorCallback.Permanent(fmt.Errorf("序列化请求体失败: %w", err))
+		}
+		body = bytes.NewReader(b)
+	}
+	timeout := time.Duration(args.TimeoutMs) * time.Millisecond
+	if timeout <= 0 || timeout > httpMaxRequestTimeout {
+		timeout = httpDefaultRequestTimeout
+	}
+	reqCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(reqCtx, args.Method, args.URL, body)
+	if err != nil {
+		return "", executorCallback.Permanent(fmt.Errorf("构造请求失败: %w", err))
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, v := range headers {
+		req.Header.Set(name, v)
+	}
+
+	resp, err := httpNodeClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("请求 %s 失败: %w", args.URL, err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, httpMaxResponseBytes+1))
+	if err != nil {
+		return "", fmt.Errorf("读取响应失败: %w", err)
+	}
+	if len(raw) > httpMaxResponseBytes {
+		return "", executorCallback.Permanent(fmt.Errorf("响应体超过 %d 字节", httpMaxResponseBytes))
+	}
+
+	if !httpStatusMatches(args.SuccessStatus, resp.StatusCode) {
+		snippet := string(raw)
+		if len(snippet) > 200 {
+			snippet = snippet[:200]
+		}
+		statusErr := fmt.Errorf("HTTP %d: %s", resp.StatusCode, snippet)
+		switch {
+		case resp.StatusCode >= 500, resp.StatusCode == http.StatusRequestTimeout, resp.StatusCode == http.StatusTooManyRequests:
+			return "", statusErr
+		default:
+			return "", executorCallback.Permanent(statusErr)
+		}
+	}
+
+	result := buildHTTPNodeResult(&args, resp, raw)
+	b, err := json.Marshal(result)
+	if err != nil {
+		return "", executorCallback.Permanent(fmt.Errorf("序列化响应失败: %w", err))
+	}
+	return string(b), nil
+}
+
+// buildHTTPNodeResult 构造任务结果：JSON 响应体按 JSON 解析，否则保留原文
+func buildHTTPNodeResult(args *httpNodeArgs, resp *http.Response, raw []byte) map[string]interface{} {
+	var body interface{}
+	if len(bytes.TrimSpace(raw)) > 0 {
+		if err := json.Unmarshal(raw, &body); err != nil {
+			body = string(raw)
+		}
+	}
+	if args.Envelope {
+		headers := make(map[string]interface{}, len(resp.Header))
+		for name := range resp.Header {
+			headers[strings.ToLower(name)] = resp.Header.Get(name)
+		}
+		return map[string]interface{}{"status": resp.StatusCode, "headers": headers, "body": body}
+	}
+	if obj, ok := body.(map[string]interface{}); ok {
+		return obj
+	}
+	if body == nil {
+		return map[string]interface{}{}
+	}
+	return map[string]interface{}{"body": body}
+}
+
+// checkHTTPSecretRefs 校验 headers 中的密钥引用：每个引用的配置 key 都须与请求主机、环境、
+// 定义编码一起命中一条 HTTPSecretRules。rawURL 可以是未渲染的模板，此时主机部分不能含模板。
+// 返回出问题的 header 名
+func (a *App) checkHTTPSecretRefs(env, defCode, rawURL string, headers map[string]string) (string, error) {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	host := ""
+	for _, name := range names {
+		for _, m := range httpSecretRefRe.FindAllStringSubmatch(headers[name], -1) {
+			if host == "" {
+				u, err := url.Parse(rawURL)
+				if err != nil || u.Hostname() == "" || strings.Contains(u.Host, "{{") {
+					return name, fmt.Errorf("引用密钥时 url 须以固定的 scheme://host 开头")
+				}
+				host = strings.ToLower(u.Hostname())
+			}
+			if !httpSecretAllowed(a.HTTPSecretRules, env, defCode, m[1], host) {
+				return name, fmt.Errorf("配置 %s 不在 workflow.http-secrets 放行范围内（env=%s, def=%s, host=%s）", m[1], env, defCode, host)
+			}
+		}
+	}
+	return "", nil
+}
+
+// httpSecretAllowed 是否有规则允许在 env 环境的 defCode 定义中把配置 key 发往 host
+func httpSecretAllowed(rules []config.HTTPSecretRule, env, defCode, key, host string) bool {
+	for _, rule := range rules {
+		if rule.Env != "" && rule.Env != env {
+			continue
+		}
+		if len(rule.DefCodes) > 0 && !containsString(rule.DefCodes, defCode) {
+			continue
+		}
+		keyOK := false
+		for _, prefix := range rule.KeyPrefixes {
+			if prefix != "" && strings.HasPrefix(key, prefix) {
+				keyOK = true
+				break
+			}
+		}
+		if !keyOK {
+			continue
+		}
+		for _, pattern := range rule.Hosts {
+			pattern = strings.ToLower(pattern)
+			if pattern == host || (strings.HasPrefix(pattern, "*.") && strings.HasSuffix(host, pattern[1:])) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// resolveSecretRefs 把 ${config:key#path} 替换为配置中心中的值，path 为对象内的点分路径
+func (a *App) resolveSecretRefs(ctx context.Context, s, env string) (string, error) {
+	matches := httpSecretRefRe.FindAllStringSubmatch(s, -1)
+	if len(matches) == 0 {
+		return s, nil
+	}
+	if a.ConfigReader == nil {
+		return "", executorCallback.Permanent(fmt.Errorf("引用了配置但未接入配置中心"))
+	}
+	// 同一配置只读取一次
+	cache := make(map[string]map[string]interface{})
+	for _, m := range matches {
+		key := m[1]
+		if _, ok := cache[key]; ok {
+			continue
+		}
+		raw, err := a.ConfigReader.GetConfigJSON(ctx, key, env)
+		if err != nil {
+			return "", fmt.Errorf("读取配置 %s 失败: %w", key, err)
+		}
+		var obj map[string]interface{}
+		if err := json.Unmarshal([]byte(raw), &obj); err != nil {
+			return "", executorCallback.Permanent(fmt.Errorf("配置 %s 不是对象: %w", key, err))
+		}
+		cache[key] = obj
+	}
+	var resolveErr error
+	out := httpSecretRefRe.ReplaceAllStringFunc(s, func(ref string) string {
+		m := httpSecretRefRe.FindStringSubmatch(ref)
+		var v interface{} = cache[m[1]]
+		if m[2] != "" {
+			for _, part := range strings.Split(m[2], ".") {
+				obj, _ := v.(map[string]interface{})
+				v = obj[part]
+			}
+			if v == nil && resolveErr == nil {
+				resolveErr = executorCallback.Permanent(fmt.Errorf("配置 %s 中不存在 %s", m[1], m[2]))
+			}
+		}
+		return httpValueString(v)
+	})
+	if resolveErr != nil {
+		return "", resolveErr
+	}
+	return out, nil
+}
@@ -0,0 +1,188 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/xsxdot/aio/pkg/core/config"
+	executorCallback "github.com/xsxdot/aio/system/executor/api/callback"
+	errorc "github.com/xsxdot/gokit/err"
+)
+
+type fakeConfigReader map[string]string
+
+func (f fakeConfigReader) GetConfigJSON(_ context.Context, key, _ string) (string, error) {
+	v, ok := f[key]
+	if !ok {
+		return "", fmt.Errorf("配置 %s 不存在", key)
+	}
+	return v, nil
+}
+
+// 派发时渲染 URL 模板并按 input_mapping 构造请求体，密钥只以引用形式进入任务参数；
+// 执行时从配置中心解析密钥，响应经 output_mapping 写回状态并继续推进。
+func TestHTTPNodeRequestAndResultMapping(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+	a.ConfigReader = fakeConfigReader{"payment": `{"api":{"token":"s3cret"}}`}
+	a.HTTPSecretRules = []config.HTTPSecretRule{{Env: "test", KeyPrefixes: []string{"payment"}, Hosts: []string{"127.0.0.1"}}}
+
+	var gotPath, gotQuery, gotAuth string
+	var gotBody map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery, gotAuth = r.URL.EscapedPath(), r.URL.RawQuery, r.Header.Get("Authorization")
+		raw, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(raw, &gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"P-9"}`))
+	}))
+	defer srv.Close()
+
+	dagJSON := fmt.Sprintf(`{"nodes":[
+		{"id":"H","type":"http","config":{
+			"url":"%s/orders/{{state.order_id}}/pay?note={{state.note}}",
+			"headers":{"Authorization":"Bearer ${config:payment#api.token}"},
+			"input_mapping":{"amount":"state.amount"},
+			"output_mapping":{"payment_id":"output.body.id","pay_status":"output.status"}}},
+		{"id":"B","type":"task","config":{"service":"svc","method":"b"}}
+	],"edges":[{"from":"H","to":"B"}]}`, srv.URL)
+	if _, err := a.CreateDef(ctx, "test", "http_def", "http", dagJSON, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	id, err := a.StartWorkflow(ctx, "http_def", map[string]interface{}{"order_id": "O 1", "note": "a&b", "amount": 12}, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	var argsJSON string
+	if err := db.Table("aio_executor_jobs").Select("args_json").
+		Where("method = ? AND dedup_key LIKE ?", HTTPNodeMethod, fmt.Sprintf("wf_%d_node_H_%%", id)).
+		Row().Scan(&argsJSON); err != nil {
+		t.Fatalf("未派发请求任务: %v", err)
+	}
+	if strings.Contains(argsJSON, "s3cret") {
+		t.Fatalf("密钥被写入任务参数: %s", argsJSON)
+	}
+
+	resultJSON, err := a.HandleInternalJob(ctx, argsJSON)
+	if err != nil {
+		t.Fatalf("执行请求: %v", err)
+	}
+	if gotPath != "/orders/O%201/pay" || gotQuery != "note=a%26b" || gotAuth != "Bearer s3cret" || gotBody["amount"] != float64(12) {
+		t.Fatalf("请求 path=%q query=%q auth=%q body=%v", gotPath, gotQuery, gotAuth, gotBody)
+	}
+	var output map[string]interface{}
+	if err := json.Unmarshal([]byte(resultJSON), &output); err != nil {
+		t.Fatalf("解析结果: %v", err)
+	}
+	if err := a.ReportNodeCompleted(ctx, id, "H", output, "test"); err != nil {
+		t.Fatalf("report H: %v", err)
+	}
+	inst, _ := a.GetInstance(ctx, id)
+	data, _, _ := parseWorkflowState(inst.CurrentState)
+	if data["payment_id"] != "P-9" || data["pay_status"] != float64(http.StatusCreated) {
+		t.Fatalf("响应未映射到状态: %v", data)
+	}
+	if countNodeJobs(t, db, id, "B", "") != 1 {
+		t.Fatal("请求成功后未推进到 B")
+	}
+}
+
+// 不在成功状态码内的响应：5xx/429 按可重试失败返回，其余 4xx 标记为不可重试；
+// success_status 可把特定状态码视为成功
+func TestHTTPNodeStatusClassification(t *testing.T) {
+	a, _ := newTestApp(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var code int
+		_, _ = fmt.Sscanf(strings.TrimPrefix(r.URL.Path, "/"), "%d", &code)
+		w.WriteHeader(code)
+		_, _ = w.Write([]byte("plain"))
+	}))
+	defer srv.Close()
+
+	run := func(code int, successStatus string) (map[string]interface{}, error) {
+		conf := map[string]interface{}{"url": fmt.Sprintf("%s/%d", srv.URL, code)}
+		if successStatus != "" {
+			var list []interface{}
+			_ = json.Unmarshal([]byte(successStatus), &list)
+			conf["success_status"] = list
+		}
+		args, err := buildHTTPNodeArgs(conf, nil)
+		if err != nil {
+			t.Fatalf("build args: %v", err)
+		}
+		argsJSON, _ := json.Marshal(args)
+		resultJSON, err := a.HandleInternalJob(context.Background(), string(argsJSON))
+		if err != nil {
+			return nil, err
+		}
+		var out map[string]interface{}
+		_ = json.Unmarshal([]byte(resultJSON), &out)
+		return out, nil
+	}
+
+	for _, code := range []int{http.StatusBadGateway, http.StatusTooManyRequests} {
+		if _, err := run(code, ""); err == nil || executorCallback.IsPermanent(err) {
+			t.Fatalf("%d 应为可重试失败: %v", code, err)
+		}
+	}
+	if _, err := run(http.StatusBadRequest, ""); !executorCallback.IsPermanent(err) {
+		t.Fatalf("400 应为不可重试失败: %v", err)
+	}
+	out, err := run(http.StatusNotFound, `["2xx", 404]`)
+	if err != nil || out["body"] != "plain" {
+		t.Fatalf("404 在 success_status 内应成功: out=%v err=%v", out, err)
+	}
+}
+
+// 密钥引用须按环境、定义编码命中放行规则，且只能发往规则内的主机：创建定义时拒绝范围外的 key、
+// 主机与含模板的主机；放行范围收紧后已有定义的派发被拒，被篡改 URL 的请求任务不会发出
+func TestHTTPSecretRefsOutsideAllowlistRejected(t *testing.T) {
+	ctx := context.Background()
+	a, _ := newTestApp(t)
+	a.ConfigReader = fakeConfigReader{"payment": `{"token":"s3cret"}`, "db": `{"password":"root"}`}
+	a.HTTPSecretRules = []config.HTTPSecretRule{
+		{Env: "test", DefCodes: []string{"pay_def"}, KeyPrefixes: []string{"payment"}, Hosts: []string{"*.pay.example", "api.pay.example"}},
+	}
+	dagFor := func(rawURL, ref string) string {
+		return fmt.Sprintf(`{"nodes":[{"id":"H","type":"http","config":{"url":%q,"headers":{"X-Token":"${config:%s}"}}}]}`, rawURL, ref)
+	}
+	for _, tc := range []struct{ code, url, ref string }{
+		{"other_def", "https://api.pay.example/charge", "payment#token"},
+		{"pay_def", "https://api.pay.example/charge", "db#password"},
+		{"pay_def", "https://evil.example/charge", "payment#token"},
+		{"pay_def", "https://{{state.host}}/charge", "payment#token"},
+		{"pay_def", "{{state.base}}/charge", "payment#token"},
+	} {
+		_, err := a.CreateDef(ctx, "test", tc.code, "http", dagFor(tc.url, tc.ref), 1)
+		var e *errorc.Error
+		if !errors.As(err, &e) || e.ErrorCode != errorc.ErrorCodeValid || !strings.Contains(err.Error(), "X-Token") {
+			t.Fatalf("%s %s %s 应被拒绝: %v", tc.code, tc.url, tc.ref, err)
+		}
+	}
+	if _, err := a.CreateDef(ctx, "test", "pay_def", "http", dagFor("https://gw.pay.example/charge/{{state.id}}", "payment#token"), 1); err != nil {
+		t.Fatalf("放行范围内的定义: %v", err)
+	}
+
+	a.HTTPSecretRules = nil
+	if _, err := a.StartWorkflow(ctx, "pay_def", map[string]interface{}{"id": "1"}, "test"); err == nil {
+		t.Fatal("放行规则收紧后仍派发了请求")
+	}
+
+	hit := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { hit = true }))
+	defer srv.Close()
+	a.HTTPSecretRules = []config.HTTPSecretRule{{KeyPrefixes: []string{"payment"}, Hosts: []string{"api.pay.example"}}}
+	argsJSON, _ := json.Marshal(&httpNodeArgs{Env: "test", DefCode: "pay_def", Method: http.MethodGet, URL: srv.URL,
+		Headers: map[string]string{"X-Token": "${config:payment#token}"}, SuccessStatus: []string{"2xx"}})
+	if _, err := a.HandleInternalJob(ctx, string(argsJSON)); !executorCallback.IsPermanent(err) || hit {
+		t.Fatalf("发往范围外主机的请求 err=%v hit=%v", err, hit)
+	}
+}
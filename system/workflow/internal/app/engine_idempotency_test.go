@@ -60,3 +60,25 @@ func TestAppendModeIsNotNaturallyIdempotent(t *testing.T) {
 		t.Fatalf("len = %d, want 2 —— 若为 1 说明 reducer 已幂等，本计划的幂等表需重新评估", len(arr))
 	}
 }
+
+// any 汇聚下同一前驱回调重放：第二次必须命中幂等标记整体跳过，汇聚节点只调度一次、
+// 前驱只留一条 checkpoint，兄弟分支的取消也不会被重复执行。
+func TestAnyJoinReplayIsIdempotent(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+	id := startJoinWorkflow(t, a, "join_replay", `"join":"any"`, joinABCEdges)
+
+	for i := 0; i < 2; i++ {
+		if err := a.reportNodeCompleted(ctx, 7001, id, "A", map[string]interface{}{}, "test"); err != nil {
+			t.Fatalf("report A #%d: %v", i+1, err)
+		}
+	}
+	if n := countNodeJobs(t, db, id, "J", ""); n != 1 {
+		t.Fatalf("J 任务数 = %d, want 1", n)
+	}
+	var cpCount int64
+	db.Model(&model.WorkflowCheckpointModel{}).Where("instance_id = ? AND node_id = ?", id, "A").Count(&cpCount)
+	if cpCount != 1 {
+		t.Fatalf("A checkpoint 数 = %d, want 1", cpCount)
+	}
+}
@@ -0,0 +1,140 @@
+// 职责：多入边节点的汇聚语义——all / any / quorum:N，以及汇聚满足后兄弟分支的处理。
+//
+// 边界：all 沿用 allPredecessorsCompleted 的原有判定；any/quorum 按「前驱已落
+// checkpoint 且其指向本节点的边在当时确实会走」计数，判定完全基于 checkpoint，
+// 不在 _sys 中额外记账，回滚、回环清理 checkpoint 后自然重新计数。汇聚节点一旦
+// 被调度，已有 checkpoint 或在活跃列表中，迟到的兄弟分支不会再次触发它。
+package app
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+)
+
+// joinReady 在 edge 即将调度 edge.To 时判断汇聚条件是否满足。
+// 返回的 spec 供调用方决定是否处理兄弟分支。
+func (a *App) joinReady(cps checkpointLog, edge model.Edge, dag *model.DAG) (bool, model.JoinSpec) {
+	target := dag.GetNode(edge.To)
+	if target == nil {
+		return false, model.JoinSpec{}
+	}
+	spec, err := target.JoinSpec()
+	if err != nil {
+		a.log.WithErr(err).WithField("node_id", edge.To).Error("解析 join 配置失败，按 all 处理")
+		spec = model.JoinSpec{}
+	}
+	if spec.Quorum == 0 {
+		return allPredecessorsCompleted(cps, edge.To, dag), spec
+	}
+	if edge.IsLoopback {
+		// 回环重入不是一次汇聚事件，前向前驱在首轮已满足过
+		return true, spec
+	}
+	arrived := 1 // 当前边的来源：其 checkpoint 可能尚未写入（error 路径先调度后落 checkpoint）
+	for _, from := range dag.JoinPredecessors(edge.To) {
+		if from == edge.From {
+			continue
+		}
+		if a.predecessorArrived(cps, from, edge.To, dag) {
+			arrived++
+		}
+	}
+	return arrived >= spec.Quorum, spec
+}
+
+// predecessorArrived 判断前驱 from 最近一次执行是否沿某条边到达了 to：
+// 按其 checkpoint 的成败匹配边类型，并用该 checkpoint 的 StateAfter 重新求值边条件。
+func (a *App) predecessorArrived(cps checkpointLog, from, to string, dag *model.DAG) bool {
+	cp := cps.latest(from)
+	if cp == nil {
+		return false
+	}
+	var output map[string]interface{}
+	_ = json.Unmarshal([]byte(cp.NodeOutput), &output)
+	_, failed := output["error_msg"]
+	data, _, _ := parseWorkflowState(cp.StateAfter)
+	for _, e := range dag.GetIncomingEdges(to) {
+		if e.From != from || e.IsLoopback {
+			continue
+		}
+		switch e.Type {
+		case model.EdgeTypeError:
+			if !failed {
+				continue
+			}
+		case model.EdgeTypeAlways:
+		default:
+			if failed {
+				continue
+			}
+		}
+		if pass, err := a.evaluateCondition(e.Condition, data); err == nil && pass {
+			return true
+		}
+	}
+	return false
+}
+
+// dropJoinSiblings 汇聚节点 joinNodeID 被调度后，把仍在运行的兄弟分支——活跃且能经前向边
+// 到达汇聚节点的节点——从活跃列表与待派发列表中移除，返回被移除的节点
+func dropJoinSiblings(joinNodeID string, dag *model.DAG, activeNodes *[]string, nextNodeIDs *[]string) []string {
+	var canceled []string
+	kept := make([]string, 0, len(*activeNodes))
+	for _, n := range *activeNodes {
+		if n != joinNodeID && reachesForward(dag, n, joinNodeID) {
+			canceled = append(canceled, n)
+			continue
+		}
+		kept = append(kept, n)
+	}
+	if len(canceled) == 0 {
+		return nil
+	}
+	*activeNodes = kept
+	pending := make([]string, 0, len(*nextNodeIDs))
+	for _, n := range *nextNodeIDs {
+		if !containsString(canceled, n) {
+			pending = append(pending, n)
+		}
+	}
+	*nextNodeIDs = pending
+	return canceled
+}
+
+// cancelJoinSiblings 取消 dropJoinSiblings 移出的兄弟分支的在途任务与子实例
+func (a *App) cancelJoinSiblings(ctx context.Context, instanceID int64, joinNodeID string, canceled []string, env string) {
+	for _, n := range canceled {
+		if err := a.ExecutorClient.CancelActiveJobsByDedupKeyPrefix(ctx, env, nodeDedupPrefix(instanceID, n)); err != nil {
+			a.log.WithErr(err).WithField("instance_id", instanceID).WithField("node_id", n).
+				Warn("汇聚满足后取消兄弟分支任务失败")
+		}
+		a.cancelChildInstances(ctx, instanceID, n)
+	}
+	a.log.WithField("instance_id", instanceID).
+		WithField("join_node", joinNodeID).
+		WithField("canceled", canceled).
+		Info("汇聚已满足，已取消仍在运行的兄弟分支")
+}
+
+// reachesForward 判断 from 是否能沿非回环边到达 to
+func reachesForward(dag *model.DAG, from, to string) bool {
+	visited := map[string]bool{from: true}
+	queue := []string{from}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, e := range dag.GetOutgoingEdges(cur) {
+			if e.IsLoopback || visited[e.To] {
+				continue
+			}
+			if e.To == to {
+				return true
+			}
+			visited[e.To] = true
+			queue = append(queue, e.To)
+		}
+	}
+	return false
+}
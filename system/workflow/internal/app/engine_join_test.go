@@ -0,0 +1,174 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	"gorm.io/gorm"
+)
+
+func countNodeJobs(t *testing.T, db *gorm.DB, instanceID int64, nodeID string, status string) int64 {
+	t.Helper()
+	var n int64
+	q := db.Table("aio_executor_jobs").Where("dedup_key LIKE ?", nodeDedupPrefix(instanceID, nodeID)+"%")
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	if err := q.Count(&n).Error; err != nil {
+		t.Fatalf("count jobs: %v", err)
+	}
+	return n
+}
+
+// startJoinWorkflow 三个起始任务 A/B/C 汇聚到任务 J
+func startJoinWorkflow(t *testing.T, a *App, code string, joinConfig string, edges string) int64 {
+	t.Helper()
+	ctx := context.Background()
+	dagJSON := `{"nodes":[
+		{"id":"A","type":"task","config":{"service":"svc","method":"a"}},
+		{"id":"B","type":"task","config":{"service":"svc","method":"b"}},
+		{"id":"C","type":"task","config":{"service":"svc","method":"c"}},
+		{"id":"J","type":"task","config":{"service":"svc","method":"j",` + joinConfig + `}}
+	],"edges":` + edges + `}`
+	if _, err := a.CreateDef(ctx, "test", code, code, dagJSON, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	id, err := a.StartWorkflow(ctx, code, map[string]interface{}{}, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	return id
+}
+
+const joinABCEdges = `[{"from":"A","to":"J"},{"from":"B","to":"J"},{"from":"C","to":"J"}]`
+
+// any：首个前驱到达即调度 J，其余仍在运行的兄弟分支默认被取消。
+func TestJoinAnyCancelsSiblings(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+	id := startJoinWorkflow(t, a, "join_any", `"join":"any"`, joinABCEdges)
+
+	if err := a.reportNodeCompleted(ctx, 0, id, "B", map[string]interface{}{"b": 1}, "test"); err != nil {
+		t.Fatalf("report B: %v", err)
+	}
+	inst, err := a.GetInstance(ctx, id)
+	if err != nil {
+		t.Fatalf("get instance: %v", err)
+	}
+	if inst.ActiveNodeIDs != `["J"]` {
+		t.Fatalf("active = %s, want [J]", inst.ActiveNodeIDs)
+	}
+	if n := countNodeJobs(t, db, id, "J", ""); n != 1 {
+		t.Fatalf("J 任务数 = %d, want 1", n)
+	}
+	for _, sibling := range []string{"A", "C"} {
+		if n := countNodeJobs(t, db, id, sibling, "canceled"); n != 1 {
+			t.Fatalf("兄弟分支 %s 已取消任务数 = %d, want 1", sibling, n)
+		}
+	}
+}
+
+// 取消兄弟分支只命中其自身的任务：ID 以兄弟分支 ID 开头（a 之于 a_x）或只因 LIKE 通配符相似
+// （x_1 之于 xy1）的无关节点必须继续运行。
+func TestJoinAnyCancelsOnlySiblingJobs(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+	dagJSON := `{"nodes":[
+		{"id":"a","type":"task","config":{"service":"svc","method":"a"}},
+		{"id":"x_1","type":"task","config":{"service":"svc","method":"x"}},
+		{"id":"b","type":"task","config":{"service":"svc","method":"b"}},
+		{"id":"a_x","type":"task","config":{"service":"svc","method":"ax"}},
+		{"id":"xy1","type":"task","config":{"service":"svc","method":"xy"}},
+		{"id":"J","type":"task","config":{"service":"svc","method":"j","join":"any"}}
+	],"edges":[{"from":"a","to":"J"},{"from":"x_1","to":"J"},{"from":"b","to":"J"}]}`
+	if _, err := a.CreateDef(ctx, "test", "join_prefix", "join_prefix", dagJSON, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	id, err := a.StartWorkflow(ctx, "join_prefix", map[string]interface{}{}, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+
+	if err := a.reportNodeCompleted(ctx, 0, id, "b", map[string]interface{}{}, "test"); err != nil {
+		t.Fatalf("report b: %v", err)
+	}
+	for _, sibling := range []string{"a", "x_1"} {
+		if n := countNodeJobs(t, db, id, sibling, "canceled"); n != 1 {
+			t.Fatalf("兄弟分支 %s 已取消任务数 = %d, want 1", sibling, n)
+		}
+	}
+	for _, other := range []string{"a_x", "xy1"} {
+		if n := countNodeJobs(t, db, id, other, "pending"); n != 1 {
+			t.Fatalf("无关节点 %s 待执行任务数 = %d, want 1", other, n)
+		}
+	}
+}
+
+// ignore：兄弟分支继续运行，其迟到的完成不得再次调度已触发的 J。
+func TestJoinAnyIgnoreSiblingsDoesNotRetrigger(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+	id := startJoinWorkflow(t, a, "join_ignore", `"join":"any","join_siblings":"ignore"`, joinABCEdges)
+
+	if err := a.reportNodeCompleted(ctx, 0, id, "A", map[string]interface{}{}, "test"); err != nil {
+		t.Fatalf("report A: %v", err)
+	}
+	if err := a.reportNodeCompleted(ctx, 0, id, "B", map[string]interface{}{}, "test"); err != nil {
+		t.Fatalf("report B: %v", err)
+	}
+	inst, err := a.GetInstance(ctx, id)
+	if err != nil {
+		t.Fatalf("get instance: %v", err)
+	}
+	if got := parseActiveNodeIDs(inst.ActiveNodeIDs); len(got) != 2 || !containsString(got, "C") || !containsString(got, "J") {
+		t.Fatalf("active = %s, want C 与 J", inst.ActiveNodeIDs)
+	}
+	if n := countNodeJobs(t, db, id, "J", ""); n != 1 {
+		t.Fatalf("J 任务数 = %d, want 1 —— 迟到的兄弟分支重复调度了汇聚节点", n)
+	}
+	if n := countNodeJobs(t, db, id, "C", "canceled"); n != 0 {
+		t.Fatalf("ignore 策略下 C 被取消了 %d 个任务", n)
+	}
+}
+
+// quorum 只计数「边确实会走」的前驱：条件不成立的前驱虽已完成，也不算到达。
+func TestJoinQuorumCountsOnlyTakenEdges(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+	edges := `[{"from":"A","to":"J","condition":"state.a_ok == true"},
+		{"from":"B","to":"J","condition":"state.b_ok == true"},
+		{"from":"C","to":"J","condition":"state.c_ok == true"}]`
+	id := startJoinWorkflow(t, a, "join_quorum", `"join":"quorum:2"`, edges)
+
+	if err := a.reportNodeCompleted(ctx, 0, id, "A", map[string]interface{}{"a_ok": true}, "test"); err != nil {
+		t.Fatalf("report A: %v", err)
+	}
+	if err := a.reportNodeCompleted(ctx, 0, id, "B", map[string]interface{}{"b_ok": false}, "test"); err != nil {
+		t.Fatalf("report B: %v", err)
+	}
+	if n := countNodeJobs(t, db, id, "J", ""); n != 0 {
+		t.Fatalf("只有 1 个前驱到达时 J 已被调度")
+	}
+	if err := a.reportNodeCompleted(ctx, 0, id, "C", map[string]interface{}{"c_ok": true}, "test"); err != nil {
+		t.Fatalf("report C: %v", err)
+	}
+	if n := countNodeJobs(t, db, id, "J", ""); n != 1 {
+		t.Fatalf("J 任务数 = %d, want 1", n)
+	}
+}
+
+// quorum 超过前驱数的定义永远无法触发，必须在校验阶段拒绝。
+func TestJoinQuorumExceedingPredecessorsIsRejected(t *testing.T) {
+	d := &model.DAG{
+		Nodes: []model.Node{
+			{ID: "A", Type: model.NodeTypeTask},
+			{ID: "B", Type: model.NodeTypeTask},
+			{ID: "J", Type: model.NodeTypeTask, Config: map[string]interface{}{"join": "quorum:3"}},
+		},
+		Edges: []model.Edge{{From: "A", To: "J"}, {From: "B", To: "J"}},
+	}
+	if err := d.Validate(); err == nil {
+		t.Fatal("quorum:3 但只有 2 个前驱，校验未拒绝")
+	}
+}
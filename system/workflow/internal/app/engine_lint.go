@@ -0,0 +1,668 @@
+// 职责：定义校验（lint）——在创建定义时一次性找出运行期才会暴露的配置错误：按节点类型
+// 检查配置、用 expr 预编译边条件与映射表达式、找出不可达节点与走不到终点的节点，
+// 并以带节点/边位置的结构化问题列表返回。
+//
+// 边界：DAG 声明了 state_schema 时，表达式中的 state 按声明的字段类型做类型检查，
+// 引用未声明字段视为错误；未声明时只检查语法与返回类型。结构性规则（环路、汇聚
+// quorum、审批决定边等）仍由 model.DAG.Validate 负责，本文件在其余检查都通过后调用它
+// 兜底。error 级问题阻止创建定义，warning 级只提示。
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/expr-lang/expr"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+)
+
+const (
+	// LintSeverityError 阻止创建定义的问题
+	LintSeverityError = "error"
+	// LintSeverityWarning 仅提示的问题
+	LintSeverityWarning = "warning"
+)
+
+// 定义校验问题类别
+const (
+	LintCodeInvalidJSON        = "invalid_json"         // dag_json 不是合法 JSON
+	LintCodeInvalidDAG         = "invalid_dag"          // 结构性错误（环路、汇聚、审批决定边等）
+	LintCodeDuplicateNode      = "duplicate_node"       // 节点 ID 为空或重复
+	LintCodeInvalidNodeID      = "invalid_node_id"      // 节点 ID 含保留的分隔符
+	LintCodeUnknownNodeType    = "unknown_node_type"    // 不支持的节点类型
+	LintCodeMissingConfig      = "missing_config"       // 缺少必填配置
+	LintCodeInvalidConfig      = "invalid_config"       // 配置值类型或取值不合法
+	LintCodeBadExpression      = "bad_expression"       // 映射或定时表达式无法编译
+	LintCodeBadCondition       = "bad_condition"        // 边条件无法编译或不返回布尔值
+	LintCodeUnknownNode        = "unknown_node"         // 边引用了不存在的节点
+	LintCodeInvalidInputSchema = "invalid_input_schema" // input_schema 声明不合法
+	LintCodeInvalidStateSchema = "invalid_state_schema" // state_schema 声明不合法
+	LintCodeUnreachableNode    = "unreachable_node"     // 从起始节点无法到达
+	LintCodeDeadEndNode        = "dead_end_node"        // 从该节点出发永远走不到终点
+	LintCodeConditionalDeadEnd = "conditional_dead_end" // 成功出边全部带条件，都不满足时分支在此结束
+)
+
+// LintEdgeRef 问题所在的边：Index 为 edges 数组下标（含 depends_on 展开的边）
+type LintEdgeRef struct {
+	Index int    `json:"index"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// DefLintIssue 定义校验发现的单个问题
+type DefLintIssue struct {
+	Severity string       `json:"severity"`
+	Code     string       `json:"code"`
+	NodeID   string       `json:"node_id,omitempty"`
+	Edge     *LintEdgeRef `json:"edge,omitempty"`
+	Field    string       `json:"field,omitempty"` // 出问题的配置项，如 config.items_path
+	Message  string       `json:"message"`
+}
+
+// DefLintResult 定义校验结果，Valid 表示没有 error 级问题
+type DefLintResult struct {
+	Valid  bool            `json:"valid"`
+	Issues []*DefLintIssue `json:"issues"`
+}
+
+// DefLintError 定义校验未通过，携带全部 error 级问题
+type DefLintError struct {
+	Issues []*DefLintIssue
+}
+
+func (e *DefLintError) Error() string {
+	msgs := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		msgs[i] = issue.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Err 存在 error 级问题时返回 *DefLintError，否则返回 nil
+func (r *DefLintResult) Err() error {
+	if r.Valid {
+		return nil
+	}
+	var errs []*DefLintIssue
+	for _, issue := range r.Issues {
+		if issue.Severity == LintSeverityError {
+			errs = append(errs, issue)
+		}
+	}
+	return &DefLintError{Issues: errs}
+}
+
+// ValidateDef 校验 DAG JSON 而不创建定义，供编辑器与 CI 预检
+func (a *App) ValidateDef(dagJSON string) *DefLintResult {
+	return lintDAG(dagJSON)
+}
+
+var (
+	lintInterfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+	lintMapType       = reflect.TypeOf(map[string]interface{}{})
+	// lintMissingFieldRe 从 expr 的类型错误中取出未声明的字段名
+	lintMissingFieldRe = regexp.MustCompile(`has no field (\w+)`)
+)
+
+// linter 单次校验的上下文
+type linter struct {
+	dag       *model.DAG
+	stateType reflect.Type
+	issues    []*DefLintIssue
+	hasError  bool
+}
+
+func (l *linter) add(severity, code, nodeID string, edge *LintEdgeRef, field, msg string) {
+	l.issues = append(l.issues, &DefLintIssue{Severity: severity, Code: code, NodeID: nodeID, Edge: edge, Field: field, Message: msg})
+	if severity == LintSeverityError {
+		l.hasError = true
+	}
+}
+
+func (l *linter) nodeError(nodeID, code, field, msg string) {
+	l.add(LintSeverityError, code, nodeID, nil, field, fmt.Sprintf("节点 %s: %s", nodeID, msg))
+}
+
+// lintDAG 对 DAG JSON 做完整校验
+func lintDAG(dagJSON string) *DefLintResult {
+	l := &linter{dag: &model.DAG{}, stateType: lintMapType}
+	if err := json.Unmarshal([]byte(dagJSON), l.dag); err != nil {
+		l.add(LintSeverityError, LintCodeInvalidJSON, "", nil, "", "解析DAG失败: "+err.Error())
+		return l.result()
+	}
+	l.dag.Normalize()
+	if len(l.dag.Nodes) == 0 {
+		l.add(LintSeverityError, LintCodeInvalidDAG, "", nil, "", "DAG 至少需要一个节点")
+		return l.result()
+	}
+	l.lintSchemas()
+
+	seen := make(map[string]bool, len(l.dag.Nodes))
+	for i := range l.dag.Nodes {
+		n := &l.dag.Nodes[i]
+		if n.ID == "" || seen[n.ID] {
+			l.add(LintSeverityError, LintCodeDuplicateNode, n.ID, nil, "id", fmt.Sprintf("第 %d 个节点的 ID 为空或重复: %q", i+1, n.ID))
+			continue
+		}
+		seen[n.ID] = true
+		if strings.Contains(n.ID, model.NodeIDDelimiter) {
+			l.add(LintSeverityError, LintCodeInvalidNodeID, n.ID, nil, "id", fmt.Sprintf("节点 ID %q 不能包含 %q", n.ID, model.NodeIDDelimiter))
+			continue
+		}
+		l.lintNode(n)
+	}
+	for i, e := range l.dag.Edges {
+		l.lintEdge(i, e)
+	}
+	if l.hasError {
+		return l.result()
+	}
+	if err := l.dag.Validate(); err != nil {
+		l.add(LintSeverityError, LintCodeInvalidDAG, "", nil, "", err.Error())
+		return l.result()
+	}
+	l.lintReachability()
+	return l.result()
+}
+
+func (l *linter) result() *DefLintResult {
+	issues := l.issues
+	if issues == nil {
+		issues = []*DefLintIssue{}
+	}
+	return &DefLintResult{Valid: !l.hasError, Issues: issues}
+}
+
+// lintSchemas 校验 input_schema / state_schema 本身，并据 state_schema 构造表达式中 state 的静态类型
+func (l *linter) lintSchemas() {
+	if l.dag.InputSchema != nil {
+		if err := checkJSONSchema(l.dag.InputSchema, "input_schema"); err != nil {
+			l.add(LintSeverityError, LintCodeInvalidInputSchema, "", nil, "input_schema", err.Error())
+		}
+	}
+	if l.dag.StateSchema == nil {
+		return
+	}
+	if err := checkJSONSchema(l.dag.StateSchema, "state_schema"); err != nil {
+		l.add(LintSeverityError, LintCodeInvalidStateSchema, "", nil, "state_schema", err.Error())
+		return
+	}
+	l.stateType = schemaExprType(l.dag.StateSchema)
+}
+
+// schemaExprType 把（已通过 checkJSONSchema 的）schema 转换为 expr 类型检查用的 Go 类型：
+// 带 properties 的 object 转为结构体（字段以 expr 标签对应属性名），number/integer 均为
+// float64（与 JSON 解码后的运行期类型一致），未声明或声明多个 type 的属性不做类型约束。
+// 以敏感前缀开头的属性不参与求值（见 filterStateForExpr），不放入结构体
+func schemaExprType(schema map[string]interface{}) reflect.Type {
+	typ, _ := schema["type"].(string)
+	switch typ {
+	case "string":
+		return reflect.TypeOf("")
+	case "number", "integer":
+		return reflect.TypeOf(float64(0))
+	case "boolean":
+		return reflect.TypeOf(false)
+	case "array":
+		return reflect.TypeOf([]interface{}{})
+	case "object":
+	case "":
+		if schema["properties"] == nil {
+			return lintInterfaceType
+		}
+	default:
+		return lintInterfaceType
+	}
+	props, ok := schema["properties"].(map[string]interface{})
+	if !ok {
+		return lintMapType
+	}
+	var fields []reflect.StructField
+	for name, p := range props {
+		prop, _ := p.(map[string]interface{})
+		if stateKeyBlacklisted(name) {
+			continue
+		}
+		fields = append(fields, reflect.StructField{
+			Name: fmt.Sprintf("F%d", len(fields)),
+			Type: schemaExprType(prop),
+			Tag:  reflect.StructTag(fmt.Sprintf(`expr:"%s"`, name)),
+		})
+	}
+	return reflect.StructOf(fields)
+}
+
+// stateKeyBlacklisted 与 filterStateForExpr 一致：敏感前缀字段不进入表达式求值环境
+func stateKeyBlacklisted(key string) bool {
+	lower := strings.ToLower(key)
+	for _, prefix := range exprStateBlacklistPrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// exprEnv 构造编译期求值环境：state 为声明的类型，extra 中的变量为任意类型，output 为对象
+func (l *linter) exprEnv(withOutput bool, extra ...string) interface{} {
+	fields := []reflect.StructField{{Name: "State", Type: l.stateType, Tag: `expr:"state"`}}
+	if withOutput {
+		fields = append(fields, reflect.StructField{Name: "Output", Type: lintMapType, Tag: `expr:"output"`})
+	}
+	for i, name := range extra {
+		fields = append(fields, reflect.StructField{
+			Name: fmt.Sprintf("X%d", i),
+			Type: lintInterfaceType,
+			Tag:  reflect.StructTag(fmt.Sprintf(`expr:"%s"`, name)),
+		})
+	}
+	return reflect.New(reflect.StructOf(fields)).Elem().Interface()
+}
+
+// compileMessage 把 expr 编译错误转换为面向定义作者的说明
+func compileMessage(err error) string {
+	if m := lintMissingFieldRe.FindStringSubmatch(err.Error()); m != nil {
+		return fmt.Sprintf("引用了 state_schema 未声明的字段 %s", m[1])
+	}
+	return err.Error()
+}
+
+// lintNode 按节点类型检查配置
+func (l *linter) lintNode(n *model.Node) {
+	var extra []string
+	switch n.Type {
+	case model.NodeTypeTask:
+		for _, key := range []string{"service", "method"} {
+			if s, _ := n.Config[key].(string); s == "" {
+				l.nodeError(n.ID, LintCodeMissingConfig, "config."+key, "task 节点缺少 "+key+" 配置")
+			}
+		}
+		l.lintConfigTypes(n.ID, n.Config, "config.", lintPolicyNumberKeys, []string{"retry_backoff"})
+		if _, err := parseNodeJobPolicy(n.Config); err != nil {
+			l.nodeError(n.ID, LintCodeInvalidConfig, "config", err.Error())
+		}
+		if _, err := n.CompensateConfig(); err != nil {
+			l.nodeError(n.ID, LintCodeInvalidConfig, "config.compensate", err.Error())
+		}
+	case model.NodeTypeMap:
+		if s, _ := n.Config["items_path"].(string); s == "" {
+			l.nodeError(n.ID, LintCodeMissingConfig, "config.items_path", "map 节点缺少 items_path 配置")
+		}
+		iter, _ := n.Config["iterator"].(map[string]interface{})
+		svc, _ := iter["service"].(string)
+		method, _ := iter["method"].(string)
+		if svc == "" || method == "" {
+			l.nodeError(n.ID, LintCodeMissingConfig, "config.iterator", "map 节点 iterator 缺少 service 或 method")
+		}
+		l.lintConfigTypes(n.ID, n.Config, "config.", append(lintPolicyNumberKeys, lintMapNumberKeys...), lintMapStringKeys)
+		l.lintConfigTypes(n.ID, iter, "config.iterator.", lintPolicyNumberKeys, []string{"retry_backoff"})
+		if cfg, err := parseMapNodeConfig(n); err == nil {
+			extra = append(extra, cfg.ItemAlias)
+		} else if svc != "" && method != "" && n.Config["items_path"] != nil {
+			l.nodeError(n.ID, LintCodeInvalidConfig, "config", err.Error())
+		}
+		if _, err := parseNodeJobPolicy(n.Config); err != nil {
+			l.nodeError(n.ID, LintCodeInvalidConfig, "config", err.Error())
+		}
+	case model.NodeTypeCondition:
+	case model.NodeTypeApproval:
+		if spec, _, err := n.ApprovalSpec(); err != nil {
+			l.nodeError(n.ID, LintCodeInvalidConfig, "config", err.Error())
+		} else if spec.FormSchema != nil {
+			if err := checkJSONSchema(spec.FormSchema, "form_schema"); err != nil {
+				l.nodeError(n.ID, LintCodeInvalidConfig, "config.form_schema", err.Error())
+			}
+		}
+	case model.NodeTypeSubWorkflow:
+		if code, _ := n.Config["def_code"].(string); code == "" {
+			l.nodeError(n.ID, LintCodeMissingConfig, "config.def_code", "subworkflow 节点缺少 def_code 配置")
+		}
+		if raw, ok := n.Config["version"]; ok {
+			if v, isInt := configInt(n.Config, "version"); !isInt || v < 0 {
+				l.nodeError(n.ID, LintCodeInvalidConfig, "config.version", fmt.Sprintf("version 必须是非负整数: %v", raw))
+			}
+		}
+		if raw, ok := n.Config["input_path"]; ok {
+			if _, isStr := raw.(string); !isStr {
+				l.nodeError(n.ID, LintCodeInvalidConfig, "config.input_path", "input_path 必须是字符串")
+			}
+		}
+	case model.NodeTypeTimer:
+		until, hasUntil := n.Config["until"]
+		if !hasUntil && n.Config["duration"] == nil {
+			l.nodeError(n.ID, LintCodeMissingConfig, "config.duration", "timer 节点需配置 duration 或 until")
+		}
+		if hasUntil {
+			code, _ := until.(string)
+			if code == "" {
+				l.nodeError(n.ID, LintCodeInvalidConfig, "config.until", "until 必须是非空表达式")
+			} else if _, err := expr.Compile(code, expr.Env(l.exprEnv(false))); err != nil {
+				l.nodeError(n.ID, LintCodeBadExpression, "config.until", "until 表达式无效: "+compileMessage(err))
+			}
+		} else if raw := n.Config["duration"]; raw != nil {
+			if _, err := toTimerDuration(raw); err != nil {
+				l.nodeError(n.ID, LintCodeInvalidConfig, "config.duration", err.Error())
+			}
+		}
+	case model.NodeTypeHTTP:
+		l.lintHTTPNode(n)
+	case model.NodeTypeTransform:
+		l.lintTransformNode(n)
+	case model.NodeTypeLoop:
+		l.lintLoopNode(n)
+	case model.NodeTypeWaitSignal:
+		l.lintWaitSignalNode(n)
+	default:
+		l.nodeError(n.ID, LintCodeUnknownNodeType, "type", fmt.Sprintf("不支持的节点类型 %q", n.Type))
+		return
+	}
+
+	if _, err := n.JoinSpec(); err != nil {
+		l.nodeError(n.ID, LintCodeInvalidConfig, "config.join", err.Error())
+	}
+	if raw, ok := n.Config["state_update_mode"]; ok {
+		switch raw {
+		case stateUpdateOverwrite, stateUpdateAppend, stateUpdateDeepMerge:
+		default:
+			l.nodeError(n.ID, LintCodeInvalidConfig, "config.state_update_mode",
+				fmt.Sprintf("state_update_mode 仅支持 overwrite、append、deep_merge: %v", raw))
+		}
+	}
+	l.lintMapping(n, "input_mapping", l.exprEnv(false, append(extra, "loop")...))
+	l.lintMapping(n, "output_mapping", l.exprEnv(true))
+}
+
+var (
+	// lintPolicyNumberKeys 投递策略中的数值配置，见 parseNodeJobPolicy
+	lintPolicyNumberKeys = []string{"max_attempts", "priority", "retry_interval_sec"}
+	// lintMapNumberKeys Map 节点的数值配置，见 parseMapNodeConfig
+	lintMapNumberKeys = []string{"max_concurrency", "tolerated_failure_count", "tolerated_failure_percent"}
+	// lintMapStringKeys Map 节点的字符串配置
+	lintMapStringKeys = []string{"item_alias", "output_path", "errors_path"}
+)
+
+// lintHTTPSecrets 按 HTTPSecretRules 检查 http 节点 header 中的密钥引用。放行范围取决于环境与
+// 定义编码，只在创建定义时检查，ValidateDef 不含此项
+func (a *App) lintHTTPSecrets(env, defCode string, dag *model.DAG) *DefLintResult {
+	l := &linter{dag: dag}
+	for _, n := range dag.Nodes {
+		if n.Type != model.NodeTypeHTTP {
+			continue
+		}
+		rawURL, _ := n.Config["url"].(string)
+		rawHeaders, _ := n.Config["headers"].(map[string]interface{})
+		headers := make(map[string]string, len(rawHeaders))
+		for name, v := range rawHeaders {
+			headers[name], _ = v.(string)
+		}
+		if name, err := a.checkHTTPSecretRefs(env, defCode, rawURL, headers); err != nil {
+			l.nodeError(n.ID, LintCodeInvalidConfig, "config.headers."+name, "header "+name+" "+err.Error())
+		}
+	}
+	return l.result()
+}
+
+// lintHTTPNode 检查 http 节点的请求配置，URL 与 header 中的 {{expr}} 模板按 state 预编译
+func (l *linter) lintHTTPNode(n *model.Node) {
+	env := l.exprEnv(false)
+	lintTemplate := func(field, tmpl string) {
+		for _, m := range httpTemplateRe.FindAllStringSubmatch(tmpl, -1) {
+			if _, err := expr.Compile(strings.TrimSpace(m[1]), expr.Env(env)); err != nil {
+				l.nodeError(n.ID, LintCodeBadExpression, field, field+" 模板表达式无效: "+compileMessage(err))
+			}
+		}
+	}
+	if rawURL, _ := n.Config["url"].(string); rawURL == "" {
+		l.nodeError(n.ID, LintCodeMissingConfig, "config.url", "http 节点缺少 url 配置")
+	} else {
+		lintTemplate("config.url", rawURL)
+	}
+	if raw, ok := n.Config["method"]; ok {
+		if m, _ := raw.(string); !httpMethods[strings.ToUpper(m)] {
+			l.nodeError(n.ID, LintCodeInvalidConfig, "config.method", fmt.Sprintf("不支持的请求方法: %v", raw))
+		}
+	}
+	if raw, ok := n.Config["headers"]; ok {
+		headers, isMap := raw.(map[string]interface{})
+		if !isMap {
+			l.nodeError(n.ID, LintCodeInvalidConfig, "config.headers", "headers 必须是对象")
+		}
+		for name, v := range headers {
+			if tmpl, isStr := v.(string); isStr {
+				lintTemplate("config.headers."+name, tmpl)
+			} else {
+				l.nodeError(n.ID, LintCodeInvalidConfig, "config.headers."+name, "header 值必须是字符串")
+			}
+		}
+	}
+	if _, err := parseHTTPSuccessStatus(n.Config["success_status"]); err != nil {
+		l.nodeError(n.ID, LintCodeInvalidConfig, "config.success_status", err.Error())
+	}
+	if _, err := parseHTTPRequestTimeout(n.Config["request_timeout"]); err != nil {
+		l.nodeError(n.ID, LintCodeInvalidConfig, "config.request_timeout", err.Error())
+	}
+	l.lintConfigTypes(n.ID, n.Config, "config.", lintPolicyNumberKeys, []string{"retry_backoff"})
+	if _, err := parseNodeJobPolicy(n.Config); err != nil {
+		l.nodeError(n.ID, LintCodeInvalidConfig, "config", err.Error())
+	}
+}
+
+// lintTransformNode 按 state 预编译 transform 节点的每个 assign 表达式
+func (l *linter) lintTransformNode(n *model.Node) {
+	raw, ok := n.Config["assign"]
+	assign, isMap := raw.(map[string]interface{})
+	if !ok || (isMap && len(assign) == 0) {
+		l.nodeError(n.ID, LintCodeMissingConfig, "config.assign", "transform 节点缺少 assign 配置")
+		return
+	}
+	if !isMap {
+		l.nodeError(n.ID, LintCodeInvalidConfig, "config.assign", "assign 必须是对象")
+		return
+	}
+	for _, key := range []string{"input_mapping", "output_mapping"} {
+		if _, has := n.Config[key]; has {
+			l.nodeError(n.ID, LintCodeInvalidConfig, "config."+key, "transform 节点不支持 "+key+"，请直接在 assign 中声明")
+		}
+	}
+	env := l.exprEnv(false)
+	for path, v := range assign {
+		field := "config.assign." + path
+		code, _ := v.(string)
+		if code == "" {
+			l.nodeError(n.ID, LintCodeInvalidConfig, field, "assign."+path+" 必须是非空表达式")
+			continue
+		}
+		if _, err := expr.Compile(code, expr.Env(env), expr.MaxNodes(transformMaxNodes)); err != nil {
+			l.nodeError(n.ID, LintCodeBadExpression, field, "assign."+path+" 表达式无效: "+compileMessage(err))
+		}
+	}
+}
+
+// lintLoopNode 检查 loop 节点的轮次配置，并按 state 与 loop 预编译 while / accumulate
+func (l *linter) lintLoopNode(n *model.Node) {
+	if body, _ := n.Config["body"].(string); body == "" {
+		l.nodeError(n.ID, LintCodeMissingConfig, "config.body", "loop 节点缺少 body 配置")
+	} else if l.dag.GetNode(body) == nil {
+		l.nodeError(n.ID, LintCodeInvalidConfig, "config.body", "body 引用不存在的节点 "+body)
+	}
+	if _, ok := n.Config["max_iterations"]; !ok {
+		l.nodeError(n.ID, LintCodeMissingConfig, "config.max_iterations", "loop 节点缺少 max_iterations 配置")
+	}
+	l.lintConfigTypes(n.ID, n.Config, "config.", []string{"max_iterations"}, []string{"while", "on_exhausted", "accumulate", "accumulate_path"})
+	if _, err := parseLoopNodeConfig(n); err != nil && n.Config["body"] != nil && n.Config["max_iterations"] != nil {
+		l.nodeError(n.ID, LintCodeInvalidConfig, "config", err.Error())
+	}
+	env := l.exprEnv(false, "loop")
+	if code, _ := n.Config["while"].(string); code != "" {
+		if _, err := expr.Compile(code, expr.Env(env), expr.AsBool()); err != nil {
+			l.nodeError(n.ID, LintCodeBadExpression, "config.while", "while 表达式无效: "+compileMessage(err))
+		}
+	}
+	if code, _ := n.Config["accumulate"].(string); code != "" {
+		if _, err := expr.Compile(code, expr.Env(env)); err != nil {
+			l.nodeError(n.ID, LintCodeBadExpression, "config.accumulate", "accumulate 表达式无效: "+compileMessage(err))
+		}
+	}
+}
+
+// lintWaitSignalNode 检查 wait_signal 节点的信号名、超时与关联键表达式
+func (l *linter) lintWaitSignalNode(n *model.Node) {
+	if s, _ := n.Config["signal"].(string); s == "" {
+		l.nodeError(n.ID, LintCodeMissingConfig, "config.signal", "wait_signal 节点缺少 signal 配置")
+	}
+	if raw, ok := n.Config["timeout"]; ok {
+		if d, err := toTimerDuration(raw); err != nil {
+			l.nodeError(n.ID, LintCodeInvalidConfig, "config.timeout", err.Error())
+		} else if d <= 0 {
+			l.nodeError(n.ID, LintCodeInvalidConfig, "config.timeout", fmt.Sprintf("timeout 必须是正的时长: %v", raw))
+		}
+	}
+	if raw, ok := n.Config["correlation_key"]; ok {
+		code, _ := raw.(string)
+		if code == "" {
+			l.nodeError(n.ID, LintCodeInvalidConfig, "config.correlation_key", "correlation_key 必须是非空表达式")
+		} else if _, err := expr.Compile(code, expr.Env(l.exprEnv(false, "loop"))); err != nil {
+			l.nodeError(n.ID, LintCodeBadExpression, "config.correlation_key", "correlation_key 表达式无效: "+compileMessage(err))
+		}
+	}
+}
+
+// lintConfigTypes 检查配置值的类型：运行期按类型断言读取，类型不对的值会被静默忽略
+func (l *linter) lintConfigTypes(nodeID string, conf map[string]interface{}, prefix string, numbers, strs []string) {
+	for _, key := range numbers {
+		if raw, ok := conf[key]; ok {
+			if _, isNum := raw.(float64); !isNum {
+				l.nodeError(nodeID, LintCodeInvalidConfig, prefix+key, fmt.Sprintf("%s 必须是数字: %v", key, raw))
+			}
+		}
+	}
+	for _, key := range strs {
+		if raw, ok := conf[key]; ok {
+			if _, isStr := raw.(string); !isStr {
+				l.nodeError(nodeID, LintCodeInvalidConfig, prefix+key, fmt.Sprintf("%s 必须是字符串: %v", key, raw))
+			}
+		}
+	}
+}
+
+// lintMapping 预编译 input_mapping / output_mapping 中的每个表达式
+func (l *linter) lintMapping(n *model.Node, key string, env interface{}) {
+	raw, ok := n.Config[key]
+	if !ok {
+		return
+	}
+	mapping, isMap := raw.(map[string]interface{})
+	if !isMap {
+		l.nodeError(n.ID, LintCodeInvalidConfig, "config."+key, key+" 必须是对象")
+		return
+	}
+	for field, v := range mapping {
+		path := "config." + key + "." + field
+		code, _ := v.(string)
+		if code == "" {
+			l.nodeError(n.ID, LintCodeInvalidConfig, path, key+"."+field+" 必须是非空表达式")
+			continue
+		}
+		if _, err := expr.Compile(code, expr.Env(env)); err != nil {
+			l.nodeError(n.ID, LintCodeBadExpression, path, key+"."+field+" 表达式无效: "+compileMessage(err))
+		}
+	}
+}
+
+// lintEdge 检查边引用的节点并预编译边条件
+func (l *linter) lintEdge(i int, e model.Edge) {
+	ref := &LintEdgeRef{Index: i, From: e.From, To: e.To}
+	for _, id := range []string{e.From, e.To} {
+		if l.dag.GetNode(id) == nil {
+			l.add(LintSeverityError, LintCodeUnknownNode, "", ref, "",
+				fmt.Sprintf("边 %s->%s 引用不存在的节点 %s", e.From, e.To, id))
+		}
+	}
+	switch e.Type {
+	case model.EdgeTypeSuccess, "success", model.EdgeTypeError, model.EdgeTypeAlways:
+	default:
+		l.add(LintSeverityError, LintCodeInvalidConfig, "", ref, "type",
+			fmt.Sprintf("边 %s->%s 的 type 不支持: %s", e.From, e.To, e.Type))
+	}
+	if e.Condition == "" {
+		return
+	}
+	if _, err := expr.Compile(e.Condition, expr.Env(l.exprEnv(false, "loop")), expr.AsBool()); err != nil {
+		l.add(LintSeverityError, LintCodeBadCondition, "", ref, "condition",
+			fmt.Sprintf("边 %s->%s 的条件无效: %s", e.From, e.To, compileMessage(err)))
+	}
+}
+
+// lintReachability 找出从起始节点无法到达的节点、走不到任何终点（无出边节点）的节点，
+// 以及成功出边全部带条件的节点。所有边（含 error、回环边）都视为可走
+func (l *linter) lintReachability() {
+	out := make(map[string][]string)
+	in := make(map[string][]string)
+	for _, e := range l.dag.Edges {
+		out[e.From] = append(out[e.From], e.To)
+		in[e.To] = append(in[e.To], e.From)
+	}
+	var starts, sinks []string
+	for _, n := range l.dag.Nodes {
+		if len(in[n.ID]) == 0 {
+			starts = append(starts, n.ID)
+		}
+		if len(out[n.ID]) == 0 {
+			sinks = append(sinks, n.ID)
+		}
+	}
+	reachable := lintWalk(starts, out)
+	finishing := lintWalk(sinks, in)
+	for _, n := range l.dag.Nodes {
+		switch {
+		case !reachable[n.ID]:
+			l.add(LintSeverityError, LintCodeUnreachableNode, n.ID, nil, "",
+				fmt.Sprintf("节点 %s 从任何起始节点都无法到达", n.ID))
+		case !finishing[n.ID]:
+			l.add(LintSeverityError, LintCodeDeadEndNode, n.ID, nil, "",
+				fmt.Sprintf("节点 %s 之后的所有路径都回到环路，实例永远无法结束", n.ID))
+		}
+		l.lintConditionalFanout(n.ID)
+	}
+}
+
+// lintConditionalFanout 成功出边全部带条件且没有审批决定边时，条件都不满足会让分支在此结束
+func (l *linter) lintConditionalFanout(nodeID string) {
+	var success int
+	for _, e := range l.dag.GetOutgoingEdges(nodeID) {
+		if e.Type == model.EdgeTypeError {
+			continue
+		}
+		if e.Condition == "" || e.Decision != "" {
+			return
+		}
+		success++
+	}
+	if success > 0 {
+		l.add(LintSeverityWarning, LintCodeConditionalDeadEnd, nodeID, nil, "",
+			fmt.Sprintf("节点 %s 的成功出边都带条件，条件都不满足时该分支在此结束", nodeID))
+	}
+}
+
+// lintWalk 从 roots 出发沿 adj 做广度优先遍历，返回可达集合
+func lintWalk(roots []string, adj map[string][]string) map[string]bool {
+	seen := make(map[string]bool, len(roots))
+	queue := append([]string(nil), roots...)
+	for _, id := range roots {
+		seen[id] = true
+	}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, next := range adj[id] {
+			if !seen[next] {
+				seen[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return seen
+}
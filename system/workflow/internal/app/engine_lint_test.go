@@ -0,0 +1,136 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// findLintIssue 按问题类别与节点查找，节点为空时匹配任意节点
+func findLintIssue(res *DefLintResult, code, nodeID string) *DefLintIssue {
+	for _, issue := range res.Issues {
+		if issue.Code == code && (nodeID == "" || issue.NodeID == nodeID) {
+			return issue
+		}
+	}
+	return nil
+}
+
+// 声明 state_schema 后，边条件按字段类型检查：引用未声明字段、类型不匹配、
+// 不返回布尔值都要定位到具体的边；符合声明的条件照常通过。
+func TestLintConditionAgainstStateSchema(t *testing.T) {
+	dagJSON := `{"state_schema":{"type":"object","properties":{
+		"amount":{"type":"number"},
+		"customer":{"type":"object","properties":{"level":{"type":"string"}}}
+	}},"nodes":[
+		{"id":"A","type":"task","config":{"service":"svc","method":"a"}},
+		{"id":"B","type":"task","config":{"service":"svc","method":"b"}},
+		{"id":"C","type":"task","config":{"service":"svc","method":"c"}},
+		{"id":"D","type":"task","config":{"service":"svc","method":"d"}},
+		{"id":"E","type":"task","config":{"service":"svc","method":"e"}}
+	],"edges":[
+		{"from":"A","to":"B","condition":"state.customer.level == 'vip' && state.amount > 1000"},
+		{"from":"A","to":"C","condition":"state.amout > 10"},
+		{"from":"A","to":"D","condition":"state.customer.level > 10"},
+		{"from":"A","to":"E","condition":"state.amount + 1"}
+	]}`
+	res := lintDAG(dagJSON)
+	if res.Valid {
+		t.Fatalf("错误的条件未被发现: %+v", res.Issues)
+	}
+	var bad []int
+	for _, issue := range res.Issues {
+		if issue.Code != LintCodeBadCondition {
+			t.Fatalf("意外的问题: %+v", issue)
+		}
+		if issue.Edge == nil || issue.Edge.From != "A" || issue.Field != "condition" {
+			t.Fatalf("问题未定位到边: %+v", issue)
+		}
+		bad = append(bad, issue.Edge.Index)
+	}
+	if len(bad) != 3 || bad[0] != 1 || bad[1] != 2 || bad[2] != 3 {
+		t.Fatalf("出错的边 = %v, want [1 2 3]", bad)
+	}
+	if msg := res.Issues[0].Message; msg != "边 A->C 的条件无效: 引用了 state_schema 未声明的字段 amout" {
+		t.Fatalf("未声明字段的提示 = %q", msg)
+	}
+}
+
+// 节点配置按类型检查并定位到节点与配置项；CreateDef 拒绝时返回全部问题
+func TestLintNodeConfigAndCreateDefRejects(t *testing.T) {
+	dagJSON := `{"nodes":[
+		{"id":"T","type":"task","config":{"service":"svc","max_attempts":"three"}},
+		{"id":"M","type":"map","config":{"iterator":{"service":"svc","method":"m"},
+			"input_mapping":{"id":"elem.id"}}},
+		{"id":"W","type":"timer","config":{"duration":"soon"}},
+		{"id":"X","type":"webhook","config":{}}
+	],"edges":[{"from":"T","to":"M"},{"from":"M","to":"W"},{"from":"W","to":"X"}]}`
+	res := lintDAG(dagJSON)
+	for _, want := range []struct{ code, node, field string }{
+		{LintCodeMissingConfig, "T", "config.method"},
+		{LintCodeInvalidConfig, "T", "config.max_attempts"},
+		{LintCodeMissingConfig, "M", "config.items_path"},
+		{LintCodeBadExpression, "M", "config.input_mapping.id"},
+		{LintCodeInvalidConfig, "W", "config.duration"},
+		{LintCodeUnknownNodeType, "X", "type"},
+	} {
+		issue := findLintIssue(res, want.code, want.node)
+		if issue == nil || issue.Field != want.field {
+			t.Fatalf("缺少问题 %s@%s(%s): %+v", want.code, want.node, want.field, issue)
+		}
+	}
+
+	a, _ := newTestApp(t)
+	_, err := a.CreateDef(context.Background(), "test", "lint_def", "lint", dagJSON, 1)
+	var lintErr *DefLintError
+	if !errors.As(err, &lintErr) || len(lintErr.Issues) != len(res.Issues) {
+		t.Fatalf("CreateDef 应返回全部校验问题: %v", err)
+	}
+}
+
+// 节点 ID 含任务幂等键的分隔符时，按节点前缀取消任务会误中其他节点，必须拒绝
+func TestLintRejectsNodeIDWithDelimiter(t *testing.T) {
+	res := lintDAG(`{"nodes":[{"id":"A#1","type":"task","config":{"service":"svc","method":"a"}}],"edges":[]}`)
+	if issue := findLintIssue(res, LintCodeInvalidNodeID, "A#1"); res.Valid || issue == nil || issue.Field != "id" {
+		t.Fatalf("含 # 的节点 ID 未被拒绝: %+v", res.Issues)
+	}
+}
+
+// 孤立子图中的节点不可达；只能在环路里打转的节点永远无法结束；
+// 成功出边全带条件只给 warning，不阻止创建。
+func TestLintReachabilityAndDeadEnds(t *testing.T) {
+	res := lintDAG(`{"nodes":[
+		{"id":"A","type":"task","config":{"service":"svc","method":"a"}},
+		{"id":"B","type":"condition","config":{}},
+		{"id":"L1","type":"task","config":{"service":"svc","method":"l1"}},
+		{"id":"L2","type":"task","config":{"service":"svc","method":"l2"}},
+		{"id":"END","type":"task","config":{"service":"svc","method":"end"}}
+	],"edges":[
+		{"from":"A","to":"B"},{"from":"B","to":"END"},
+		{"from":"L1","to":"L2"},{"from":"L2","to":"L1","is_loopback":true}
+	]}`)
+	if res.Valid || findLintIssue(res, LintCodeUnreachableNode, "L1") == nil ||
+		findLintIssue(res, LintCodeUnreachableNode, "L2") == nil {
+		t.Fatalf("未发现不可达的环路: %+v", res.Issues)
+	}
+
+	res = lintDAG(`{"nodes":[
+		{"id":"A","type":"task","config":{"service":"svc","method":"a"}},
+		{"id":"B","type":"task","config":{"service":"svc","method":"b"}},
+		{"id":"C","type":"condition","config":{}}
+	],"edges":[{"from":"A","to":"B"},{"from":"B","to":"C"},{"from":"C","to":"B","is_loopback":true}]}`)
+	for _, node := range []string{"A", "B", "C"} {
+		if findLintIssue(res, LintCodeDeadEndNode, node) == nil {
+			t.Fatalf("节点 %s 未被判定为无法结束: %+v", node, res.Issues)
+		}
+	}
+
+	res = lintDAG(`{"nodes":[
+		{"id":"A","type":"task","config":{"service":"svc","method":"a"}},
+		{"id":"B","type":"task","config":{"service":"svc","method":"b"}}
+	],"edges":[{"from":"A","to":"B","condition":"state.ok == true"}]}`)
+	issue := findLintIssue(res, LintCodeConditionalDeadEnd, "A")
+	if !res.Valid || issue == nil || issue.Severity != LintSeverityWarning {
+		t.Fatalf("条件出边应仅给 warning: valid=%v issues=%+v", res.Valid, res.Issues)
+	}
+}
@@ -0,0 +1,268 @@
+// 职责：loop 节点——在 while 条件成立时重复执行循环体子路径，最多 max_iterations 轮；
+// 循环体内的边条件、input_mapping 与任务入参可通过 loop.index 读取当前轮次；每轮结束时
+// 可把一个表达式的结果累积到状态中的数组。
+//
+// 边界：循环体是从 loop 节点的 body 出边开始、经 is_loopback 边回到 loop 节点的子路径，
+// 回环本身沿用通用 loopback 机制（清理循环体 checkpoint 后重跑）。loop 节点与 condition
+// 一样同步推进：每次进入时计算走向——继续则只走 body 出边，否则走其余出边；轮次耗尽时
+// 按 on_exhausted 走 error 边或继续。轮次状态保存在 _sys，嵌套循环时 loop 指向最内层。
+// 循环体全部由同步节点组成时，整个循环在一次推进内逐轮完成：回环回到正在推进的 loop 节点时
+// 不再递归，由最外层的那次触发逐轮推进，轮数只受 max_iterations 约束，不占用单次推进的跳数。
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+)
+
+const (
+	// LoopExhaustedError 轮次耗尽时按节点失败走 error 边（默认）
+	LoopExhaustedError = "error"
+	// LoopExhaustedContinue 轮次耗尽时与条件不成立一样走退出边
+	LoopExhaustedContinue = "continue"
+	// loopMaxIterationsLimit max_iterations 的上限
+	loopMaxIterationsLimit = 10000
+
+	loopRouteBody = "body"
+	loopRouteExit = "exit"
+)
+
+// loopNodeConfig loop 节点配置
+type loopNodeConfig struct {
+	Body           string
+	While          string
+	MaxIterations  int
+	OnExhausted    string
+	Accumulate     string
+	AccumulatePath string
+}
+
+// parseLoopNodeConfig 解析 loop 节点配置。
+//
+// 配置项：body（循环体入口节点，须为本节点的直接后继）、max_iterations（必填）、
+// while（继续循环的条件，可使用 loop.index，缺省为一直循环到轮次耗尽）、
+// on_exhausted（error|continue，默认 error）、accumulate 与 accumulate_path（每轮结束时
+// 求值 accumulate 并追加到 accumulate_path 处的数组，进入循环时数组重置为空）。
+func parseLoopNodeConfig(n *model.Node) (*loopNodeConfig, error) {
+	cfg := &loopNodeConfig{OnExhausted: LoopExhaustedError}
+	cfg.Body, _ = n.Config["body"].(string)
+	if cfg.Body == "" {
+		return nil, fmt.Errorf("loop 节点 %s 缺少 body 配置", n.ID)
+	}
+	v, ok := configInt(n.Config, "max_iterations")
+	if !ok || v < 1 || v > loopMaxIterationsLimit {
+		return nil, fmt.Errorf("loop 节点 %s 的 max_iterations 必须是 1~%d 的整数", n.ID, loopMaxIterationsLimit)
+	}
+	cfg.MaxIterations = v
+	if raw, ok := n.Config["while"]; ok {
+		if cfg.While, _ = raw.(string); cfg.While == "" {
+			return nil, fmt.Errorf("loop 节点 %s 的 while 必须是非空表达式", n.ID)
+		}
+	}
+	if raw, ok := n.Config["on_exhausted"]; ok {
+		switch raw {
+		case LoopExhaustedError, LoopExhaustedContinue:
+			cfg.OnExhausted = raw.(string)
+		default:
+			return nil, fmt.Errorf("loop 节点 %s 的 on_exhausted 仅支持 error 或 continue: %v", n.ID, raw)
+		}
+	}
+	cfg.Accumulate, _ = n.Config["accumulate"].(string)
+	cfg.AccumulatePath, _ = n.Config["accumulate_path"].(string)
+	if (cfg.Accumulate == "") != (cfg.AccumulatePath == "") {
+		return nil, fmt.Errorf("loop 节点 %s 的 accumulate 与 accumulate_path 需同时配置", n.ID)
+	}
+	return cfg, nil
+}
+
+// loopStack 读取 _sys 中进行中的循环（外层在前）
+func loopStack(sys workflowStateSys) []string {
+	raw, _ := sys["loop_stack"].([]interface{})
+	stack := make([]string, 0, len(raw))
+	for _, v := range raw {
+		if s, ok := v.(string); ok {
+			stack = append(stack, s)
+		}
+	}
+	return stack
+}
+
+func setLoopStack(sys workflowStateSys, stack []string) {
+	if len(stack) == 0 {
+		delete(sys, "loop_stack")
+		return
+	}
+	raw := make([]interface{}, len(stack))
+	for i, s := range stack {
+		raw[i] = s
+	}
+	sys["loop_stack"] = raw
+}
+
+// loopStates 读取 _sys 中各循环的当前轮次上下文
+func loopStates(sys workflowStateSys) map[string]interface{} {
+	states, _ := sys["loops"].(map[string]interface{})
+	if states == nil {
+		states = make(map[string]interface{})
+		sys["loops"] = states
+	}
+	return states
+}
+
+// currentLoop 返回最内层进行中循环的上下文 {index, max_iterations, node_id}，不在循环中时返回 nil
+func currentLoop(sys workflowStateSys) map[string]interface{} {
+	stack := loopStack(sys)
+	if len(stack) == 0 {
+		return nil
+	}
+	states, _ := sys["loops"].(map[string]interface{})
+	ctx, _ := states[stack[len(stack)-1]].(map[string]interface{})
+	return ctx
+}
+
+// loopExprVars 构造表达式环境中的 loop 变量，不在循环中时为空对象
+func loopExprVars(sys workflowStateSys) map[string]interface{} {
+	ctx := currentLoop(sys)
+	if ctx == nil {
+		ctx = map[string]interface{}{}
+	}
+	return map[string]interface{}{"loop": ctx}
+}
+
+// advanceLoopNode 在推进事务内计算 loop 节点本次的走向（loopRouteBody 或 loopRouteExit），
+// 并更新 _sys 中的轮次与状态中的累积数组。返回错误时按节点失败处理：表达式求值失败，
+// 或轮次耗尽且 on_exhausted=error。
+func advanceLoopNode(n *model.Node, data workflowStateData, sys workflowStateSys) (string, error) {
+	cfg, err := parseLoopNodeConfig(n)
+	if err != nil {
+		return "", err
+	}
+	states := loopStates(sys)
+	stack := loopStack(sys)
+	depth := -1
+	for i, id := range stack {
+		if id == n.ID {
+			depth = i
+			break
+		}
+	}
+
+	index := 0
+	if depth < 0 {
+		// 首次进入：入栈并重置累积数组
+		stack = append(stack, n.ID)
+		if cfg.AccumulatePath != "" {
+			setValueAtPath(data, cfg.AccumulatePath, []interface{}{})
+		}
+	} else {
+		// 经回环边回到本节点，一轮结束；未正常退出的内层循环随之出栈
+		stack = stack[:depth+1]
+		prev, _ := states[n.ID].(map[string]interface{})
+		if v, ok := configInt(prev, "index"); ok {
+			index = v
+		}
+		if cfg.Accumulate != "" {
+			env := map[string]interface{}{"state": filterStateForExpr(data), "loop": prev}
+			v, err := evalMappingExpr(cfg.Accumulate, env)
+			if err != nil {
+				return "", fmt.Errorf("accumulate 求值失败: %w", err)
+			}
+			acc, _ := getValueAtPath(data, cfg.AccumulatePath).([]interface{})
+			setValueAtPath(data, cfg.AccumulatePath, append(acc, v))
+		}
+		index++
+	}
+
+	loopCtx := map[string]interface{}{"index": index, "max_iterations": cfg.MaxIterations, "node_id": n.ID}
+	exit := func() {
+		delete(states, n.ID)
+		setLoopStack(sys, stack[:len(stack)-1])
+	}
+	if cfg.While != "" {
+		env := map[string]interface{}{"state": filterStateForExpr(data), "loop": loopCtx}
+		out, err := evalMappingExpr(cfg.While, env)
+		if err != nil {
+			exit()
+			return "", fmt.Errorf("while 求值失败: %w", err)
+		}
+		proceed, isBool := out.(bool)
+		if !isBool {
+			exit()
+			return "", fmt.Errorf("while 未返回布尔值")
+		}
+		if !proceed {
+			exit()
+			return loopRouteExit, nil
+		}
+	}
+	if index >= cfg.MaxIterations {
+		exit()
+		if cfg.OnExhausted == LoopExhaustedContinue {
+			return loopRouteExit, nil
+		}
+		return "", fmt.Errorf("循环 %s 已执行 %d 轮，达到 max_iterations 仍未结束", n.ID, cfg.MaxIterations)
+	}
+	states[n.ID] = loopCtx
+	setLoopStack(sys, stack)
+	return loopRouteBody, nil
+}
+
+// loopEdgeAllowed loop 节点按本次走向筛选出边：继续循环只走 body 出边，退出只走其余出边
+func loopEdgeAllowed(node *model.Node, edge model.Edge, route string) bool {
+	if node == nil || node.Type != model.NodeTypeLoop || route == "" {
+		return true
+	}
+	body, _ := node.Config["body"].(string)
+	if route == loopRouteBody {
+		return edge.To == body
+	}
+	return edge.To != body
+}
+
+type syncLoopKeyType struct{}
+
+// syncLoopDriver 在一次推进的整条递归链上共享：正在逐轮推进的 loop 节点，
+// 以及循环体同步跑完后经回环边请求的下一轮
+type syncLoopDriver struct {
+	running map[string]bool
+	reentry map[string]bool
+}
+
+// triggerLoopNode 推进 loop 节点。
+//
+// 循环体全部由同步节点组成时，回环边会在同一条递归链里再次触发本节点；若每轮都递归进入，
+// 几十轮就会触到 maxAdvanceHops。此时只登记下一轮并返回，待本轮的推进逐层返回后，
+// 由最外层这次调用在循环里继续推进，递归深度与轮数无关。
+func (a *App) triggerLoopNode(ctx context.Context, instance *model.WorkflowInstanceModel, node *model.Node, env string) error {
+	return driveSyncLoop(ctx, node.ID, func(ctx context.Context) error {
+		// 轮次在推进事务内计算，随即进入循环体或退出
+		return a.ReportNodeCompleted(ctx, instance.ID, node.ID, nil, env)
+	})
+}
+
+// driveSyncLoop 以 advance 推进 loop 节点一轮，并按上述方式承接同步循环体经回环边请求的下一轮；
+// 离线模拟以内存推进复用同一驱动
+func driveSyncLoop(ctx context.Context, nodeID string, advance func(context.Context) error) error {
+	d, _ := ctx.Value(syncLoopKeyType{}).(*syncLoopDriver)
+	if d == nil {
+		d = &syncLoopDriver{running: make(map[string]bool), reentry: make(map[string]bool)}
+		ctx = context.WithValue(ctx, syncLoopKeyType{}, d)
+	}
+	if d.running[nodeID] {
+		d.reentry[nodeID] = true
+		return nil
+	}
+	d.running[nodeID] = true
+	defer delete(d.running, nodeID)
+	for {
+		if err := advance(ctx); err != nil {
+			return err
+		}
+		if !d.reentry[nodeID] {
+			return nil
+		}
+		delete(d.reentry, nodeID)
+	}
+}
@@ -0,0 +1,169 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	"gorm.io/gorm"
+)
+
+// latestNodePayload 读取节点最近一次派发的任务入参
+func latestNodePayload(t *testing.T, db *gorm.DB, instanceID int64, nodeID string) map[string]interface{} {
+	t.Helper()
+	var argsJSON string
+	if err := db.Table("aio_executor_jobs").Select("args_json").
+		Where("dedup_key LIKE ?", fmt.Sprintf("wf_%d_node_%s_%%", instanceID, nodeID)).
+		Order("id DESC").Limit(1).Row().Scan(&argsJSON); err != nil {
+		t.Fatalf("读取 %s 的任务入参: %v", nodeID, err)
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal([]byte(argsJSON), &payload); err != nil {
+		t.Fatalf("解析任务入参: %v", err)
+	}
+	return payload
+}
+
+// while 条件成立时重复执行循环体，循环体入参可读取 loop.index；每轮结果累积到数组，
+// 条件不成立时走退出边。
+func TestLoopNodeIteratesAndAccumulates(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+	dagJSON := `{"nodes":[
+		{"id":"S","type":"task","config":{"service":"svc","method":"s"}},
+		{"id":"L","type":"loop","config":{"body":"P","max_iterations":5,"while":"state.has_more",
+			"accumulate":"state.page","accumulate_path":"report.pages"}},
+		{"id":"P","type":"task","config":{"service":"svc","method":"page","input_mapping":{"page_no":"loop.index + 1"}}},
+		{"id":"N","type":"task","config":{"service":"svc","method":"n"}}
+	],"edges":[
+		{"from":"S","to":"L"},{"from":"L","to":"P"},{"from":"L","to":"N"},
+		{"from":"P","to":"L","is_loopback":true}
+	]}`
+	if _, err := a.CreateDef(ctx, "test", "loop_def", "loop", dagJSON, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	id, err := a.StartWorkflow(ctx, "loop_def", map[string]interface{}{"has_more": true}, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := a.ReportNodeCompleted(ctx, id, "S", nil, "test"); err != nil {
+		t.Fatalf("report S: %v", err)
+	}
+	for i, more := range []bool{true, false} {
+		payload := latestNodePayload(t, db, id, "P")
+		input, _ := payload["input"].(map[string]interface{})
+		loop, _ := payload["loop"].(map[string]interface{})
+		if input["page_no"] != float64(i+1) || loop["index"] != float64(i) {
+			t.Fatalf("第 %d 轮入参 input=%v loop=%v", i, input, loop)
+		}
+		out := map[string]interface{}{"page": fmt.Sprintf("p%d", i), "has_more": more}
+		if err := a.ReportNodeCompleted(ctx, id, "P", out, "test"); err != nil {
+			t.Fatalf("report P#%d: %v", i, err)
+		}
+	}
+	if n := countNodeJobs(t, db, id, "P", ""); n != 2 {
+		t.Fatalf("循环体执行 %d 轮，want 2", n)
+	}
+	if countNodeJobs(t, db, id, "N", "") != 1 {
+		t.Fatal("条件不成立后未走退出边")
+	}
+	inst, _ := a.GetInstance(ctx, id)
+	data, sys, _ := parseWorkflowState(inst.CurrentState)
+	report, _ := data["report"].(map[string]interface{})
+	if pages, _ := json.Marshal(report["pages"]); string(pages) != `["p0","p1"]` {
+		t.Fatalf("累积结果 = %s", pages)
+	}
+	if currentLoop(sys) != nil {
+		t.Fatalf("退出后循环仍在进行: %v", sys["loop_stack"])
+	}
+}
+
+// 轮次耗尽时默认走 error 边，on_exhausted=continue 时走退出边；
+// 缺少回到 loop 节点的回环边在定义校验时即被拒绝。
+func TestLoopNodeExhaustion(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+	dagFor := func(onExhausted string) string {
+		return fmt.Sprintf(`{"nodes":[
+			{"id":"S","type":"transform","config":{"assign":{"count":"0"}}},
+			{"id":"L","type":"loop","config":{"body":"T","max_iterations":3,"on_exhausted":%q}},
+			{"id":"T","type":"transform","config":{"assign":{"count":"state.count + 1"}}},
+			{"id":"N","type":"task","config":{"service":"svc","method":"n"}},
+			{"id":"H","type":"task","config":{"service":"svc","method":"h"}}
+		],"edges":[
+			{"from":"S","to":"L"},{"from":"L","to":"T"},{"from":"L","to":"N"},{"from":"L","to":"H","type":"error"},
+			{"from":"T","to":"L","is_loopback":true}
+		]}`, onExhausted)
+	}
+	for _, tc := range []struct{ onExhausted, want, other string }{
+		{LoopExhaustedError, "H", "N"},
+		{LoopExhaustedContinue, "N", "H"},
+	} {
+		code := "loop_" + tc.onExhausted
+		if _, err := a.CreateDef(ctx, "test", code, "loop", dagFor(tc.onExhausted), 1); err != nil {
+			t.Fatalf("create def: %v", err)
+		}
+		id, err := a.StartWorkflow(ctx, code, nil, "test")
+		if err != nil {
+			t.Fatalf("start: %v", err)
+		}
+		if countNodeJobs(t, db, id, tc.want, "") != 1 || countNodeJobs(t, db, id, tc.other, "") != 0 {
+			t.Fatalf("on_exhausted=%s 未走 %s", tc.onExhausted, tc.want)
+		}
+		inst, _ := a.GetInstance(ctx, id)
+		data, _, _ := parseWorkflowState(inst.CurrentState)
+		if data["count"] != float64(3) {
+			t.Fatalf("循环体执行 %v 轮，want 3", data["count"])
+		}
+		if msg, _ := data["error_msg"].(string); tc.onExhausted == LoopExhaustedError && !strings.Contains(msg, "max_iterations") {
+			t.Fatalf("error_msg = %q", msg)
+		}
+	}
+
+	res := lintDAG(`{"nodes":[
+		{"id":"S","type":"task","config":{"service":"svc","method":"s"}},
+		{"id":"L","type":"loop","config":{"body":"T","max_iterations":3}},
+		{"id":"T","type":"task","config":{"service":"svc","method":"t"}}
+	],"edges":[{"from":"S","to":"L"},{"from":"L","to":"T"}]}`)
+	if issue := findLintIssue(res, LintCodeInvalidDAG, ""); issue == nil || !strings.Contains(issue.Message, "is_loopback") {
+		t.Fatalf("缺少回环边未被发现: %+v", res.Issues)
+	}
+}
+
+// 循环体全是同步节点时整个循环在一次推进内跑完；若每轮都递归进入 loop 节点，
+// 三十来轮就会触到单次推进的跳数上限而使实例失败，轮数应只受 max_iterations 约束。
+func TestSyncLoopBodyRunsBeyondHopLimit(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+	dagJSON := `{"nodes":[
+		{"id":"S","type":"transform","config":{"assign":{"count":"0"}}},
+		{"id":"L","type":"loop","config":{"body":"T","max_iterations":50,"on_exhausted":"continue",
+			"accumulate":"loop.index","accumulate_path":"seen"}},
+		{"id":"T","type":"transform","config":{"assign":{"count":"state.count + 1"}}},
+		{"id":"N","type":"task","config":{"service":"svc","method":"n"}}
+	],"edges":[
+		{"from":"S","to":"L"},{"from":"L","to":"T"},{"from":"L","to":"N"},
+		{"from":"T","to":"L","is_loopback":true}
+	]}`
+	if _, err := a.CreateDef(ctx, "test", "sync_loop", "sync_loop", dagJSON, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	id, err := a.StartWorkflow(ctx, "sync_loop", nil, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	inst, _ := a.GetInstance(ctx, id)
+	data, _, _ := parseWorkflowState(inst.CurrentState)
+	if inst.Status != model.InstanceStatusRunning || data["count"] != float64(50) {
+		t.Fatalf("status=%s count=%v, want RUNNING 与 50 轮", inst.Status, data["count"])
+	}
+	if seen, _ := data["seen"].([]interface{}); len(seen) != 50 || seen[49] != float64(49) {
+		t.Fatalf("累积了 %d 轮", len(seen))
+	}
+	if countNodeJobs(t, db, id, "N", "") != 1 {
+		t.Fatal("轮次耗尽后未走退出边")
+	}
+}
@@ -1,6 +1,9 @@
 package app
 
 import (
+	"context"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/xsxdot/aio/system/workflow/internal/model"
@@ -23,3 +26,80 @@ func TestEdgeTargetEligibleForScheduling(t *testing.T) {
 		t.Fatal("non-loopback should schedule first time")
 	}
 }
+
+// 普通回环边（非 loop 节点）多轮重跑：回环清理从目标到源节点（含源节点本轮的 checkpoint）。
+// 只清到源节点之前时，第二轮 J 会因遗留的 checkpoint 被判为「已执行过」而不再调度，实例挂起；
+// 每轮汇聚仍须等两个分支都完成。
+func TestPlainLoopbackRunsMultipleRounds(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+	dagJSON := `{"nodes":[
+		{"id":"S","type":"task","config":{"service":"svc","method":"s"}},
+		{"id":"A","type":"task","config":{"service":"svc","method":"a"}},
+		{"id":"B1","type":"task","config":{"service":"svc","method":"b1"}},
+		{"id":"B2","type":"task","config":{"service":"svc","method":"b2"}},
+		{"id":"J","type":"task","config":{"service":"svc","method":"j"}},
+		{"id":"E","type":"task","config":{"service":"svc","method":"e"}}
+	],"edges":[
+		{"from":"S","to":"A"},{"from":"A","to":"B1"},{"from":"A","to":"B2"},
+		{"from":"B1","to":"J"},{"from":"B2","to":"J"},
+		{"from":"J","to":"A","is_loopback":true,"condition":"state.round < 3"},
+		{"from":"J","to":"E","condition":"state.round >= 3"}
+	]}`
+	if _, err := a.CreateDef(ctx, "test", "plain_loopback", "plain_loopback", dagJSON, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	id, err := a.StartWorkflow(ctx, "plain_loopback", map[string]interface{}{}, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	active := func() string {
+		inst, err := a.GetInstance(ctx, id)
+		if err != nil {
+			t.Fatalf("get instance: %v", err)
+		}
+		return inst.ActiveNodeIDs
+	}
+	trailNodes := func() []string {
+		trail, err := a.GetExecutionTrail(ctx, id)
+		if err != nil {
+			t.Fatalf("trail: %v", err)
+		}
+		nodes := make([]string, 0, len(trail.Checkpoints))
+		for _, cp := range trail.Checkpoints {
+			nodes = append(nodes, cp.NodeID)
+		}
+		return nodes
+	}
+
+	completeNodeJob(t, a, db, id, "S", `{}`)
+	for round := 1; round <= 3; round++ {
+		if got := active(); got != `["A"]` {
+			t.Fatalf("第 %d 轮开始 active = %s, want [A]", round, got)
+		}
+		completeNodeJob(t, a, db, id, "A", `{}`)
+		completeNodeJob(t, a, db, id, "B1", `{}`)
+		if got := active(); got != `["B2"]` {
+			t.Fatalf("第 %d 轮 B1 完成后 active = %s, want [B2]（J 须等 B2）", round, got)
+		}
+		completeNodeJob(t, a, db, id, "B2", `{}`)
+		if got := active(); got != `["J"]` {
+			t.Fatalf("第 %d 轮 B2 完成后 active = %s, want [J]", round, got)
+		}
+		if got := trailNodes(); strings.Join(got, ",") != "S,A,B1,B2" {
+			t.Fatalf("第 %d 轮汇聚前轨迹 = %v, want [S A B1 B2]", round, got)
+		}
+		completeNodeJob(t, a, db, id, "J", fmt.Sprintf(`{"round":%d}`, round))
+		if round < 3 {
+			if got := trailNodes(); strings.Join(got, ",") != "S" {
+				t.Fatalf("第 %d 轮回环后轨迹 = %v, want [S]", round, got)
+			}
+		}
+	}
+	if got := active(); got != `["E"]` {
+		t.Fatalf("退出回环后 active = %s, want [E]", got)
+	}
+	if got := trailNodes(); strings.Join(got, ",") != "S,A,B1,B2,J" {
+		t.Fatalf("退出回环后轨迹 = %v, want [S A B1 B2 J]", got)
+	}
+}
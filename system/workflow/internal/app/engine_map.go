@@ -0,0 +1,278 @@
+// 职责：Map 节点的配置解析、子任务派发、并发窗口与失败容忍。
+//
+// 边界：聚合计数与结果仍沿用 _sys.map_counters / _sys.map_results；本文件额外维护
+// _sys.map_dispatch（下一个待派发序号、已容忍失败数、派发批次）、_sys.map_errors
+// （逐项错误列表）与 _sys.map_items（仅限流时存在的待派发元素快照）。这些键都在
+// Map 节点结束（成功聚合或熔断）时清理，且不随子任务参数下发。子任务回调携带派发批次，
+// 与当前批次不一致的回调（回环重跑、回滚前的上一轮）直接丢弃。
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	executorDto "github.com/xsxdot/aio/system/executor/api/dto"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+)
+
+// mapNodeConfig Map 节点配置
+type mapNodeConfig struct {
+	ItemsPath   string
+	ItemAlias   string
+	OutputPath  string
+	ErrorsPath  string
+	ServiceName string
+	MethodName  string
+	// SubPolicy 子任务投递策略，取自 iterator 块
+	SubPolicy nodeJobPolicy
+	// MaxConcurrency 同时在途的子任务上限，0 表示不限
+	MaxConcurrency          int
+	ToleratedFailureCount   int
+	ToleratedFailurePercent float64
+}
+
+// parseMapNodeConfig 解析 Map 节点配置
+func parseMapNodeConfig(node *model.Node) (*mapNodeConfig, error) {
+	cfg := &mapNodeConfig{}
+	cfg.ItemsPath, _ = node.Config["items_path"].(string)
+	if cfg.ItemsPath == "" {
+		return nil, fmt.Errorf("Map 节点缺少 items_path 配置")
+	}
+	cfg.ItemAlias, _ = node.Config["item_alias"].(string)
+	if cfg.ItemAlias == "" {
+		cfg.ItemAlias = "item"
+	}
+	cfg.OutputPath, _ = node.Config["output_path"].(string)
+	cfg.ErrorsPath, _ = node.Config["errors_path"].(string)
+
+	iteratorConf, _ := node.Config["iterator"].(map[string]interface{})
+	if iteratorConf != nil {
+		cfg.ServiceName, _ = iteratorConf["service"].(string)
+		cfg.MethodName, _ = iteratorConf["method"].(string)
+	}
+	if cfg.ServiceName == "" || cfg.MethodName == "" {
+		return nil, fmt.Errorf("Map 节点 iterator 缺少 service 或 method")
+	}
+	subPolicy, err := parseNodeJobPolicy(iteratorConf)
+	if err != nil {
+		return nil, fmt.Errorf("Map 节点 iterator 投递策略配置无效: %w", err)
+	}
+	cfg.SubPolicy = subPolicy
+
+	if v, ok := configInt(node.Config, "max_concurrency"); ok {
+		if v < 0 {
+			return nil, fmt.Errorf("max_concurrency 不能为负数")
+		}
+		cfg.MaxConcurrency = v
+	}
+	if v, ok := configInt(node.Config, "tolerated_failure_count"); ok {
+		if v < 0 {
+			return nil, fmt.Errorf("tolerated_failure_count 不能为负数")
+		}
+		cfg.ToleratedFailureCount = v
+	}
+	if v, ok := node.Config["tolerated_failure_percent"].(float64); ok {
+		if v < 0 || v > 100 {
+			return nil, fmt.Errorf("tolerated_failure_percent 必须在 0~100 之间")
+		}
+		cfg.ToleratedFailurePercent = v
+	}
+	return cfg, nil
+}
+
+// toleratedFailures 返回 total 个子任务中允许失败的数量；同时配置数量与百分比时取较大者
+func (c *mapNodeConfig) toleratedFailures(total int) int {
+	byPercent := int(math.Floor(float64(total) * c.ToleratedFailurePercent / 100))
+	if byPercent > c.ToleratedFailureCount {
+		return byPercent
+	}
+	return c.ToleratedFailureCount
+}
+
+// initialWindow 首批派发的子任务数
+func (c *mapNodeConfig) initialWindow(total int) int {
+	if c.MaxConcurrency <= 0 || c.MaxConcurrency > total {
+		return total
+	}
+	return c.MaxConcurrency
+}
+
+// mapSysEntry 取 _sys 下某个按节点分组的 map，不存在时创建
+func mapSysEntry(sys workflowStateSys, key string) map[string]interface{} {
+	m, ok := sys[key].(map[string]interface{})
+	if !ok || m == nil {
+		m = make(map[string]interface{})
+		sys[key] = m
+	}
+	return m
+}
+
+// clearMapNodeSys 清理某个 Map 节点在 _sys 中的全部运行期数据
+func clearMapNodeSys(sys workflowStateSys, nodeID string) {
+	for _, key := range []string{"map_counters", "map_results", "map_dispatch", "map_errors", "map_items"} {
+		if m, ok := sys[key].(map[string]interface{}); ok {
+			delete(m, nodeID)
+		}
+	}
+}
+
+// mapPayloadState 构造派发给子任务的 state：与 CurrentState 结构一致，但不携带任何 map_* 运行期数据。
+// 结果、错误列表与元素快照随子任务完成而增长，若随参数下发，N 个子任务的参数总量为 O(N²)
+func mapPayloadState(data workflowStateData, sys workflowStateSys) map[string]interface{} {
+	payloadSys := make(map[string]interface{}, len(sys))
+	for k, v := range sys {
+		if strings.HasPrefix(k, "map_") {
+			continue
+		}
+		payloadSys[k] = v
+	}
+	return map[string]interface{}{"data": data, "_sys": payloadSys}
+}
+
+// submitMapSubJob 提交第 index 个子任务。
+//
+// wave 为本次 Map 执行的批次号，拼入幂等键使回环重跑与上一轮的子任务区分开。
+//
+// 配置了 input_mapping 时按元素求值（求值环境可引用 item_alias），子任务不再携带整份 state。
+func (a *App) submitMapSubJob(ctx context.Context, instance *model.WorkflowInstanceModel, node *model.Node, cfg *mapNodeConfig, env string, index int, item interface{}, wave string, data map[string]interface{}, stateObj map[string]interface{}) error {
+	subPayload := map[string]interface{}{
+		"instance_id": instance.ID,
+		"node_id":     node.ID,
+		"config":      node.Config,
+		cfg.ItemAlias: item,
+	}
+	input, mapped, err := buildNodeInput(node.Config, data, map[string]interface{}{cfg.ItemAlias: item})
+	if err != nil {
+		return a.err.New("Map 节点 "+node.ID+" 输入映射失败", &nodeInputError{msg: "输入映射失败", err: err})
+	}
+	if mapped {
+		subPayload["input"] = input
+	} else {
+		subPayload["state"] = stateObj
+	}
+	payloadBytes, _ := json.Marshal(subPayload)
+	callbackDataBytes, _ := json.Marshal(map[string]interface{}{
+		"instance_id": instance.ID,
+		"node_id":     node.ID,
+		"env":         env,
+		"sub_job_id":  index,
+		"wave":        wave,
+	})
+	jobInput := &executorDto.SubmitJobInput{
+		Env:           env,
+		TargetService: cfg.ServiceName,
+		Method:        cfg.MethodName,
+		ArgsJSON:      string(payloadBytes),
+		RunAt:         0,
+		DedupKey:      mapSubDedupPrefix(instance.ID, node.ID, wave) + strconv.Itoa(index),
+		Source:        "workflow",
+		CallbackData:  string(callbackDataBytes),
+	}
+	cfg.SubPolicy.applyTo(jobInput)
+	if _, err := a.ExecutorClient.SubmitJob(ctx, jobInput); err != nil {
+		return a.err.New("提交 Map 子任务到 Executor 失败", err)
+	}
+	return nil
+}
+
+// mapSubDedupPrefix 某一批次 Map 子任务幂等键的公共前缀，批次号在元素序号之前，
+// 熔断时按它取消只会命中本批次的子任务
+func mapSubDedupPrefix(instanceID int64, nodeID, wave string) string {
+	return nodeDedupPrefix(instanceID, nodeID) + "sub_" + wave + "_"
+}
+
+// mapWaveCtxKey 承载 Map 子任务回调携带的派发批次
+type mapWaveCtxKey struct{}
+
+// withMapWave 把子任务回调的派发批次放入 ctx，供 handleMapSubTaskCompleted 在推进事务内比对
+func withMapWave(ctx context.Context, wave string) context.Context {
+	return context.WithValue(ctx, mapWaveCtxKey{}, wave)
+}
+
+// staleMapWave 回调携带的批次与节点当前的派发批次不一致。未携带批次（直接调用 ReportNodeCompleted）
+// 或功能上线前派发的 Map 节点不做比对
+func staleMapWave(ctx context.Context, sys workflowStateSys, nodeID string) bool {
+	wave, _ := ctx.Value(mapWaveCtxKey{}).(string)
+	current := currentMapWave(sys, nodeID)
+	return wave != "" && current != "" && wave != current
+}
+
+// currentMapWave 节点当前的派发批次，未派发或功能上线前派发的 Map 节点返回空串
+func currentMapWave(sys workflowStateSys, nodeID string) string {
+	dispatchAll, _ := sys["map_dispatch"].(map[string]interface{})
+	dispatch, _ := dispatchAll[nodeID].(map[string]interface{})
+	wave, _ := dispatch["wave"].(string)
+	return wave
+}
+
+// dispatchNextMapItem 并发窗口内有子任务结束后，派发下一个尚未派发的元素。
+// 未限流（无 map_items 快照）或已全部派发时不做任何事。
+func (a *App) dispatchNextMapItem(ctx context.Context, instance *model.WorkflowInstanceModel, node *model.Node, cfg *mapNodeConfig, env string, data workflowStateData, sys workflowStateSys) error {
+	index, item, ok := takeNextMapItem(sys, node.ID)
+	if !ok {
+		return nil
+	}
+	wave, _ := mapSysEntry(sys, "map_dispatch")[node.ID].(map[string]interface{})["wave"].(string)
+	return a.submitMapSubJob(ctx, instance, node, cfg, env, index, item, wave, data, mapPayloadState(data, sys))
+}
+
+// takeNextMapItem 从限流快照中取出下一个待派发元素并推进派发序号，没有待派发元素时返回 false
+func takeNextMapItem(sys workflowStateSys, nodeID string) (int, interface{}, bool) {
+	items, _ := mapSysEntry(sys, "map_items")[nodeID].([]interface{})
+	dispatch, _ := mapSysEntry(sys, "map_dispatch")[nodeID].(map[string]interface{})
+	if items == nil || dispatch == nil {
+		return 0, nil, false
+	}
+	next, _ := configInt(dispatch, "next")
+	if next >= len(items) {
+		return 0, nil, false
+	}
+	dispatch["next"] = next + 1
+	return next, items[next], true
+}
+
+// mapAggregation 全部子任务结束后的聚合：write 把结果数组与逐项错误写入 output_path / errors_path，
+// output 为记录在 checkpoint 中的节点输出 {"merged": 结果, "errors": 逐项错误（有失败时）}
+func mapAggregation(cfg *mapNodeConfig, sys workflowStateSys, nodeID string, results []interface{}) (func(workflowStateData), map[string]interface{}) {
+	mapErrors, _ := mapSysEntry(sys, "map_errors")[nodeID].([]interface{})
+	if cfg.ErrorsPath != "" && mapErrors == nil {
+		mapErrors = make([]interface{}, 0)
+	}
+	write := func(d workflowStateData) {
+		if cfg.OutputPath != "" {
+			setValueAtPath(d, cfg.OutputPath, results)
+		}
+		if cfg.ErrorsPath != "" {
+			setValueAtPath(d, cfg.ErrorsPath, mapErrors)
+		}
+	}
+	output := map[string]interface{}{"merged": results}
+	if len(mapErrors) > 0 {
+		output["errors"] = mapErrors
+	}
+	return write, output
+}
+
+// recordMapItemFailure 在容忍范围内记录一个失败子任务；超出容忍范围返回 false，
+// 由调用方按 Map 节点整体失败处理
+func recordMapItemFailure(cfg *mapNodeConfig, sys workflowStateSys, nodeID string, subID int, total int, errorMsg interface{}) bool {
+	dispatch, _ := mapSysEntry(sys, "map_dispatch")[nodeID].(map[string]interface{})
+	if dispatch == nil {
+		dispatch = make(map[string]interface{})
+		mapSysEntry(sys, "map_dispatch")[nodeID] = dispatch
+	}
+	failed, _ := configInt(dispatch, "failed")
+	failed++
+	if failed > cfg.toleratedFailures(total) {
+		return false
+	}
+	dispatch["failed"] = failed
+	errs := mapSysEntry(sys, "map_errors")
+	list, _ := errs[nodeID].([]interface{})
+	errs[nodeID] = append(list, map[string]interface{}{"index": subID, "error_msg": errorMsg})
+	return true
+}
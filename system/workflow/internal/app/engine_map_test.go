@@ -0,0 +1,226 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	"gorm.io/gorm"
+)
+
+func countMapSubJobs(t *testing.T, db *gorm.DB, instanceID int64, nodeID string, status string) int64 {
+	t.Helper()
+	var n int64
+	q := db.Table("aio_executor_jobs").Where("dedup_key LIKE ?", nodeDedupPrefix(instanceID, nodeID)+"sub_%")
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	if err := q.Count(&n).Error; err != nil {
+		t.Fatalf("count sub jobs: %v", err)
+	}
+	return n
+}
+
+// 限流 + 容忍失败：同时在途不超过 max_concurrency，失败项在容忍范围内按结果记录，
+// 聚合完成后必须继续派发下游节点（此前聚合完成的推进会漏掉下游派发）。
+func TestMapNodeThrottlesAndToleratesFailures(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+
+	dagJSON := `{"nodes":[
+		{"id":"M","type":"map","config":{"items_path":"items","output_path":"results","errors_path":"errs",
+			"max_concurrency":2,"tolerated_failure_count":1,
+			"iterator":{"service":"svc","method":"each"}}},
+		{"id":"T","type":"task","config":{"service":"svc","method":"after"}}
+	],"edges":[{"from":"M","to":"T"}]}`
+	if _, err := a.CreateDef(ctx, "test", "map_def", "map", dagJSON, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	id, err := a.StartWorkflow(ctx, "map_def", map[string]interface{}{
+		"items": []interface{}{"a", "b", "c"},
+	}, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if n := countMapSubJobs(t, db, id, "M", ""); n != 2 {
+		t.Fatalf("首批派发 %d 个子任务，want 2（max_concurrency）", n)
+	}
+
+	if err := a.reportNodeCompleted(ctx, 0, id, "M", map[string]interface{}{"v": "A"}, "test", 0); err != nil {
+		t.Fatalf("report sub 0: %v", err)
+	}
+	if n := countMapSubJobs(t, db, id, "M", ""); n != 3 {
+		t.Fatalf("子任务结束后共派发 %d 个，want 3", n)
+	}
+	if err := a.reportNodeCompleted(ctx, 0, id, "M", map[string]interface{}{"error_msg": "boom"}, "test", 1); err != nil {
+		t.Fatalf("report sub 1: %v", err)
+	}
+	if err := a.reportNodeCompleted(ctx, 0, id, "M", map[string]interface{}{"v": "C"}, "test", 2); err != nil {
+		t.Fatalf("report sub 2: %v", err)
+	}
+
+	inst, err := a.GetInstance(ctx, id)
+	if err != nil {
+		t.Fatalf("get instance: %v", err)
+	}
+	if inst.Status != model.InstanceStatusRunning || inst.ActiveNodeIDs != `["T"]` {
+		t.Fatalf("status=%s active=%s, want RUNNING [T]", inst.Status, inst.ActiveNodeIDs)
+	}
+	var downstream int64
+	db.Table("aio_executor_jobs").Where("dedup_key LIKE ?", fmt.Sprintf("wf_%d_node_T_%%", id)).Count(&downstream)
+	if downstream != 1 {
+		t.Fatalf("下游 T 任务数 = %d, want 1", downstream)
+	}
+
+	data, sys, err := parseWorkflowState(inst.CurrentState)
+	if err != nil {
+		t.Fatalf("parse state: %v", err)
+	}
+	results, _ := data["results"].([]interface{})
+	if len(results) != 3 {
+		t.Fatalf("results = %v, want 3 项", data["results"])
+	}
+	if item, _ := results[1].(map[string]interface{}); item["error_msg"] != "boom" {
+		t.Fatalf("results[1] = %v, want error_msg=boom", results[1])
+	}
+	errs, _ := data["errs"].([]interface{})
+	if len(errs) != 1 {
+		t.Fatalf("errs = %v, want 1 项", data["errs"])
+	}
+	for _, key := range []string{"map_counters", "map_results", "map_dispatch", "map_errors", "map_items"} {
+		if m, ok := sys[key].(map[string]interface{}); ok && m["M"] != nil {
+			t.Fatalf("_sys.%s.M 未清理: %v", key, m["M"])
+		}
+	}
+}
+
+// 失败数超过容忍上限时 Map 节点整体失败，其余在途子任务必须被取消，不再占用 executor 队列。
+func TestMapNodeFailsFastBeyondTolerance(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+
+	dagJSON := `{"nodes":[
+		{"id":"M","type":"map","config":{"items_path":"items","tolerated_failure_percent":25,
+			"iterator":{"service":"svc","method":"each"}}}
+	]}`
+	if _, err := a.CreateDef(ctx, "test", "map_ff", "map", dagJSON, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	id, err := a.StartWorkflow(ctx, "map_ff", map[string]interface{}{
+		"items": []interface{}{1, 2, 3},
+	}, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	// 3 * 25% 向下取整为 0，首个失败即超限
+	if err := a.reportNodeCompleted(ctx, 0, id, "M", map[string]interface{}{"error_msg": "boom"}, "test", 0); err != nil {
+		t.Fatalf("report sub 0: %v", err)
+	}
+	inst, err := a.GetInstance(ctx, id)
+	if err != nil {
+		t.Fatalf("get instance: %v", err)
+	}
+	if inst.Status != model.InstanceStatusFailed {
+		t.Fatalf("status = %s, want FAILED", inst.Status)
+	}
+	if n := countMapSubJobs(t, db, id, "M", "canceled"); n != 3 {
+		t.Fatalf("已取消子任务 %d 个，want 3", n)
+	}
+}
+
+// 熔断只取消本批次的子任务：ID 以 Map 节点 ID 加 _sub 开头的并行节点不能被一并取消。
+func TestMapFailFastKeepsOtherNodesJobs(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+
+	dagJSON := `{"nodes":[
+		{"id":"M","type":"map","config":{"items_path":"items","iterator":{"service":"svc","method":"each"}}},
+		{"id":"M_sub","type":"task","config":{"service":"svc","method":"other"}},
+		{"id":"H","type":"task","config":{"service":"svc","method":"h"}}
+	],"edges":[{"from":"M","to":"H","type":"error"}]}`
+	if _, err := a.CreateDef(ctx, "test", "map_ff_scope", "map", dagJSON, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	id, err := a.StartWorkflow(ctx, "map_ff_scope", map[string]interface{}{"items": []interface{}{1, 2}}, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := a.reportNodeCompleted(ctx, 0, id, "M", map[string]interface{}{"error_msg": "boom"}, "test", 0); err != nil {
+		t.Fatalf("report sub 0: %v", err)
+	}
+	if n := countMapSubJobs(t, db, id, "M", "canceled"); n != 2 {
+		t.Fatalf("已取消子任务 %d 个，want 2", n)
+	}
+	if n := countNodeJobs(t, db, id, "M_sub", "pending"); n != 1 {
+		t.Fatalf("M_sub 待执行任务数 = %d, want 1（不应被 M 的熔断取消）", n)
+	}
+}
+
+// 百分比与数量同时配置时取较大者，作为「至少容忍 N 个」的下限。
+func TestMapToleratedFailures(t *testing.T) {
+	cfg := &mapNodeConfig{ToleratedFailureCount: 2, ToleratedFailurePercent: 10}
+	if got := cfg.toleratedFailures(10); got != 2 {
+		t.Fatalf("toleratedFailures(10) = %d, want 2", got)
+	}
+	if got := cfg.toleratedFailures(100); got != 10 {
+		t.Fatalf("toleratedFailures(100) = %d, want 10", got)
+	}
+}
+
+// 限流派发的后续子任务不随参数携带 map_* 运行期数据（结果随完成数增长，否则参数总量为 O(N²)）；
+// 回调携带派发批次，批次与当前不一致的迟到回调不计入聚合、也不触发后续派发。
+func TestMapSubJobPayloadAndStaleWave(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+
+	dagJSON := `{"nodes":[
+		{"id":"M","type":"map","config":{"items_path":"items","output_path":"results","max_concurrency":1,
+			"iterator":{"service":"svc","method":"each"}}}
+	]}`
+	if _, err := a.CreateDef(ctx, "test", "map_wave", "map", dagJSON, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	id, err := a.StartWorkflow(ctx, "map_wave", map[string]interface{}{"items": []interface{}{"a", "b", "c"}}, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	completeNodeJob(t, a, db, id, "M", `{"v":"A"}`)
+
+	payload := latestNodePayload(t, db, id, "M")
+	state, _ := payload["state"].(map[string]interface{})
+	sys, _ := state["_sys"].(map[string]interface{})
+	if payload["item"] != "b" || sys == nil {
+		t.Fatalf("第 2 个子任务参数 = %v", payload)
+	}
+	for k := range sys {
+		if strings.HasPrefix(k, "map_") {
+			t.Fatalf("子任务参数携带了 _sys.%s", k)
+		}
+	}
+
+	var callbackData string
+	db.Table("aio_executor_jobs").Select("callback_data").Where("dedup_key LIKE ?", nodeDedupPrefix(id, "M")+"sub_%_1").Row().Scan(&callbackData)
+	var cb map[string]interface{}
+	if err := json.Unmarshal([]byte(callbackData), &cb); err != nil || cb["wave"] == "" {
+		t.Fatalf("callback_data = %s", callbackData)
+	}
+	cb["wave"] = "1"
+	staleData, _ := json.Marshal(cb)
+	if err := a.OnJobCompleted(ctx, 999999, string(staleData), `{"v":"STALE"}`); err != nil {
+		t.Fatalf("stale callback: %v", err)
+	}
+	if n := countMapSubJobs(t, db, id, "M", ""); n != 2 {
+		t.Fatalf("迟到回调触发了后续派发，共 %d 个子任务", n)
+	}
+
+	completeNodeJob(t, a, db, id, "M", `{"v":"B"}`)
+	completeNodeJob(t, a, db, id, "M", `{"v":"C"}`)
+	inst, _ := a.GetInstance(ctx, id)
+	data, _, _ := parseWorkflowState(inst.CurrentState)
+	if got, _ := json.Marshal(data["results"]); string(got) != `[{"v":"A"},{"v":"B"},{"v":"C"}]` {
+		t.Fatalf("聚合结果 = %s", got)
+	}
+}
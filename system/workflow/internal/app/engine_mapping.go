@@ -0,0 +1,126 @@
+// 职责：节点级输入/输出映射——input_mapping 按声明构造任务入参，output_mapping
+// 按声明把任务结果写到状态中的指定位置。
+//
+// 边界：映射值均为 expr 表达式，求值环境与边条件一致（state 经 filterStateForExpr
+// 过滤敏感前缀字段）。未配置映射的节点行为不变：任务仍收到完整 state，结果仍按
+// state_update_mode 整体合并。checkpoint 的 NodeOutput 始终记录原始结果。
+package app
+
+import (
+	"fmt"
+
+	"github.com/expr-lang/expr"
+)
+
+// nodeMapping 读取节点配置中的映射声明（input_mapping / output_mapping）
+func nodeMapping(conf map[string]interface{}, key string) (map[string]interface{}, bool) {
+	m, ok := conf[key].(map[string]interface{})
+	return m, ok && len(m) > 0
+}
+
+// evalMappingExpr 在给定环境下求值单个映射表达式
+func evalMappingExpr(code string, env map[string]interface{}) (interface{}, error) {
+	program, err := expr.Compile(code, expr.Env(env))
+	if err != nil {
+		return nil, err
+	}
+	return expr.Run(program, env)
+}
+
+// nodeInputError 按当前状态构造节点入参失败：input_mapping 求值、HTTP 请求渲染、Map 的 items_path
+// 与 subworkflow 的 input_path 取值等。与 output_mapping 求值失败一样按节点失败处理（走 error 边，
+// 无 error 边则实例 FAILED），而不是回滚整次推进、让承载回调的 outbox 任务无限重试
+type nodeInputError struct {
+	msg string
+	err error
+}
+
+func (e *nodeInputError) Error() string {
+	if e.err == nil {
+		return e.msg
+	}
+	return e.msg + ": " + e.err.Error()
+}
+
+func (e *nodeInputError) Unwrap() error { return e.err }
+
+// buildNodeInput 按 input_mapping 构造任务入参：键为入参字段名，值为表达式。
+// 节点未配置 input_mapping 时返回 ok=false，调用方沿用整份 state。
+//
+// extra 为额外注入求值环境的变量，例如 Map 节点的当前元素（item_alias）、循环体内的 loop；
+// 未注入 loop 时其为空对象。
+func buildNodeInput(conf map[string]interface{}, data map[string]interface{}, extra map[string]interface{}) (map[string]interface{}, bool, error) {
+	mapping, ok := nodeMapping(conf, "input_mapping")
+	if !ok {
+		return nil, false, nil
+	}
+	env := map[string]interface{}{"state": filterStateForExpr(data), "loop": map[string]interface{}{}}
+	for k, v := range extra {
+		env[k] = v
+	}
+	input := make(map[string]interface{}, len(mapping))
+	for field, raw := range mapping {
+		code, isStr := raw.(string)
+		if !isStr || code == "" {
+			return nil, true, fmt.Errorf("input_mapping.%s 必须是非空表达式", field)
+		}
+		v, err := evalMappingExpr(code, env)
+		if err != nil {
+			return nil, true, fmt.Errorf("input_mapping.%s 求值失败: %w", field, err)
+		}
+		input[field] = v
+	}
+	return input, true, nil
+}
+
+// projectNodeOutput 按 output_mapping 把任务结果投影为待写入状态的对象：
+// 键为状态路径（点分），值为表达式，求值环境含 output（原始结果）与 state。
+// 节点未配置 output_mapping 时返回 ok=false，调用方沿用原始结果。
+func projectNodeOutput(conf map[string]interface{}, data map[string]interface{}, output map[string]interface{}) (map[string]interface{}, bool, error) {
+	mapping, ok := nodeMapping(conf, "output_mapping")
+	if !ok {
+		return nil, false, nil
+	}
+	if output == nil {
+		output = map[string]interface{}{}
+	}
+	env := map[string]interface{}{"output": output, "state": filterStateForExpr(data)}
+	projected := make(map[string]interface{}, len(mapping))
+	for path, raw := range mapping {
+		code, isStr := raw.(string)
+		if !isStr || code == "" {
+			return nil, true, fmt.Errorf("output_mapping.%s 必须是非空表达式", path)
+		}
+		v, err := evalMappingExpr(code, env)
+		if err != nil {
+			return nil, true, fmt.Errorf("output_mapping.%s 求值失败: %w", path, err)
+		}
+		setValueAtPath(projected, path, v)
+	}
+	return projected, true, nil
+}
+
+// applyNodeOutput 将节点结果写入状态：配置了 output_mapping（或 transform 节点的 assign）
+// 时先投影，再交给 reducer。
+//
+// overwrite 模式下投影结果按声明的路径逐个写入，只覆盖声明的叶子路径，不会整体
+// 替换同一顶层键下的其他字段；append / deep_merge 模式下投影结果整体交给 reducer。
+func applyNodeOutput(data workflowStateData, output map[string]interface{}, nodeConfig map[string]interface{}, projected map[string]interface{}) {
+	if projected == nil {
+		applyStateReducer(data, output, nodeConfig)
+		return
+	}
+	mode, _ := nodeConfig["state_update_mode"].(string)
+	if mode != "" && mode != stateUpdateOverwrite {
+		applyStateReducer(data, projected, nodeConfig)
+		return
+	}
+	mapping, ok := nodeMapping(nodeConfig, "output_mapping")
+	if !ok {
+		// transform 节点的写入路径来自 assign
+		mapping, _ = transformAssignments(nodeConfig)
+	}
+	for path := range mapping {
+		setValueAtPath(data, path, getValueAtPath(projected, path))
+	}
+}
@@ -0,0 +1,150 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+)
+
+// overwrite 模式下 output_mapping 只写声明的叶子路径：同一顶层键下未声明的字段必须保留，
+// 结果中未声明的字段也不得漏进状态。
+func TestApplyNodeOutputOverwriteWritesDeclaredPathsOnly(t *testing.T) {
+	data := workflowStateData{"order": map[string]interface{}{"id": float64(7), "status": "new"}}
+	conf := map[string]interface{}{"output_mapping": map[string]interface{}{"order.status": "output.status"}}
+	output := map[string]interface{}{"status": "paid", "raw": "large payload"}
+
+	projected, ok, err := projectNodeOutput(conf, data, output)
+	if err != nil || !ok {
+		t.Fatalf("project: ok=%v err=%v", ok, err)
+	}
+	applyNodeOutput(data, output, conf, projected)
+
+	order, _ := data["order"].(map[string]interface{})
+	if order["id"] != float64(7) || order["status"] != "paid" {
+		t.Fatalf("order = %v, want id 保留、status=paid", order)
+	}
+	if _, leaked := data["raw"]; leaked {
+		t.Fatal("未声明的结果字段 raw 被写入了状态")
+	}
+}
+
+// append 模式下投影结果整体交给 reducer，与未配置映射时的追加语义一致。
+func TestApplyNodeOutputAppendUsesProjection(t *testing.T) {
+	data := workflowStateData{}
+	conf := map[string]interface{}{
+		"state_update_mode": "append",
+		"output_key":        "scores",
+		"output_mapping":    map[string]interface{}{"score": "output.score * 10"},
+	}
+	for _, score := range []float64{1, 2} {
+		output := map[string]interface{}{"score": score, "debug": true}
+		projected, _, err := projectNodeOutput(conf, data, output)
+		if err != nil {
+			t.Fatalf("project: %v", err)
+		}
+		applyNodeOutput(data, output, conf, projected)
+	}
+	scores, _ := data["scores"].([]interface{})
+	if len(scores) != 2 {
+		t.Fatalf("scores = %v, want 2 项", data["scores"])
+	}
+	if first, _ := scores[0].(map[string]interface{}); first["score"] != float64(10) || first["debug"] != nil {
+		t.Fatalf("scores[0] = %v, want 仅含 score=10", scores[0])
+	}
+}
+
+// 配置 input_mapping 的任务只派发声明的入参，不再携带整份 state；
+// output_mapping 求值失败按节点失败处理，而不是让回调无限重试。
+func TestTaskInputMappingAndOutputMappingFailure(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+
+	dagJSON := `{"nodes":[{"id":"T","type":"task","config":{"service":"svc","method":"m",
+		"input_mapping":{"order_id":"state.order.id","count":"len(state.items)"},
+		"output_mapping":{"result":"output.detail.value"}}}]}`
+	if _, err := a.CreateDef(ctx, "test", "mapping", "mapping", dagJSON, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	id, err := a.StartWorkflow(ctx, "mapping", map[string]interface{}{
+		"order": map[string]interface{}{"id": "O-1"},
+		"items": []interface{}{1, 2, 3},
+		"notes": "unrelated",
+	}, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+
+	var argsJSON string
+	if err := db.Table("aio_executor_jobs").Select("args_json").
+		Where("dedup_key LIKE ?", fmt.Sprintf("wf_%d_node_T_%%", id)).Scan(&argsJSON).Error; err != nil {
+		t.Fatalf("load job args: %v", err)
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal([]byte(argsJSON), &payload); err != nil {
+		t.Fatalf("parse args: %v", err)
+	}
+	if _, ok := payload["state"]; ok {
+		t.Fatalf("配置 input_mapping 后仍派发了整份 state: %s", argsJSON)
+	}
+	input, _ := payload["input"].(map[string]interface{})
+	if input["order_id"] != "O-1" || input["count"] != float64(3) {
+		t.Fatalf("input = %v, want order_id=O-1 count=3", input)
+	}
+
+	if err := a.reportNodeCompleted(ctx, 0, id, "T", map[string]interface{}{"value": 1}, "test"); err != nil {
+		t.Fatalf("report: %v", err)
+	}
+	inst, err := a.GetInstance(ctx, id)
+	if err != nil {
+		t.Fatalf("get instance: %v", err)
+	}
+	if inst.Status != model.InstanceStatusFailed {
+		t.Fatalf("status = %s, want FAILED（output_mapping 求值失败且无 error 边）", inst.Status)
+	}
+}
+
+// input_mapping 求值失败与 output_mapping 一样按节点失败处理：推进照常提交并走 error 边，
+// 失败节点不派发任务；起始节点无 error 边时实例 FAILED，而不是让推进回滚后无限重试。
+func TestInputMappingFailureRoutesToErrorEdge(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+	dagJSON := `{"nodes":[
+		{"id":"A","type":"task","config":{"service":"svc","method":"a"}},
+		{"id":"B","type":"task","config":{"service":"svc","method":"b","input_mapping":{"n":"len(state.items)"}}},
+		{"id":"H","type":"task","config":{"service":"svc","method":"h"}}
+	],"edges":[{"from":"A","to":"B"},{"from":"B","to":"H","type":"error"}]}`
+	if _, err := a.CreateDef(ctx, "test", "input_fail", "input_fail", dagJSON, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	id, err := a.StartWorkflow(ctx, "input_fail", nil, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := a.ReportNodeCompleted(ctx, id, "A", nil, "test"); err != nil {
+		t.Fatalf("report A: %v", err)
+	}
+	if countNodeJobs(t, db, id, "B", "") != 0 || countNodeJobs(t, db, id, "H", "") != 1 {
+		t.Fatal("入参构造失败未按节点失败走 error 边")
+	}
+	inst, _ := a.GetInstance(ctx, id)
+	data, _, _ := parseWorkflowState(inst.CurrentState)
+	if msg, _ := data["error_msg"].(string); !strings.Contains(msg, "input_mapping.n") {
+		t.Fatalf("error_msg = %q", msg)
+	}
+
+	startFail := `{"nodes":[{"id":"B","type":"task","config":{"service":"svc","method":"b","input_mapping":{"n":"len(state.items)"}}}]}`
+	if _, err := a.CreateDef(ctx, "test", "input_fail_start", "input_fail_start", startFail, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	id, err = a.StartWorkflow(ctx, "input_fail_start", nil, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if s := instanceStatus(t, a, id); s != model.InstanceStatusFailed {
+		t.Fatalf("status = %s, want FAILED", s)
+	}
+}
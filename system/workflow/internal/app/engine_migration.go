@@ -0,0 +1,342 @@
+// 职责：实例迁移——把运行中的实例改挂到同一定义编码的另一个版本，修复 DAG 缺陷后
+// 存量的长流程实例无需重跑即可走新逻辑。
+//
+// 边界：只迁移 RUNNING / WAITING / PAUSED 实例。迁移只改 DefID/DefVersion、活跃节点与
+// 暂停积压列表，不派发也不取消任何任务：在途任务、审批、子实例与定时器仍以旧节点 ID
+// 回调，由 _sys.node_aliases 在推进时翻译成新 ID；节点超时的派发标识随改名转到新 ID，
+// 旧 ID 的超时回调经别名翻译后照常生效。
+// 已完成节点的 checkpoint 保留原 ID，不参与新 ID 的汇聚判定。每个实例单独加锁、单独
+// 事务，写一条审计记录与 migrated 事件；dry-run 只做同样的校验，不写任何数据。
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/xsxdot/aio/base"
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"gorm.io/gorm"
+)
+
+type WorkflowInstanceMigrationModel = model.WorkflowInstanceMigrationModel
+
+// sysNodeAliasesKey _sys 中迁移改名的节点别名（旧 ID -> 新 ID）
+const sysNodeAliasesKey = "node_aliases"
+
+// MigrateInstancesInput 实例迁移请求
+type MigrateInstancesInput struct {
+	DefCode       string            // 必填，定义编码
+	Env           string            // 定义与实例所在环境，空则用进程默认环境
+	TargetVersion int32             // 目标版本，<=0 表示最新版本
+	InstanceIDs   []int64           // 指定实例；为空时迁移该定义下全部运行中/等待中/已暂停的实例
+	FromVersion   int32             // 未指定实例时只迁移该版本的实例，<=0 表示不限
+	NodeMapping   map[string]string // 活跃节点改名：旧 ID -> 新 ID，未列出的按原 ID 对应
+	DryRun        bool              // 只校验并报告，不迁移
+}
+
+// InstanceMigrationResult 单个实例的迁移结果
+type InstanceMigrationResult struct {
+	InstanceID    int64    `json:"instance_id"`
+	FromVersion   int32    `json:"from_version"`
+	Migrated      bool     `json:"migrated"`                  // dry-run 时表示可以迁移
+	Reason        string   `json:"reason,omitempty"`          // 不能迁移的原因
+	ActiveNodeIDs []string `json:"active_node_ids,omitempty"` // 迁移后的活跃节点
+}
+
+// MigrationReport 迁移报告
+type MigrationReport struct {
+	TargetDefID   int64                      `json:"target_def_id"`
+	TargetVersion int32                      `json:"target_version"`
+	DryRun        bool                       `json:"dry_run"`
+	Migrated      int                        `json:"migrated"` // 已迁移（dry-run 时为可迁移）的实例数
+	Rejected      int                        `json:"rejected"`
+	Results       []*InstanceMigrationResult `json:"results"`
+}
+
+// migrationTarget 目标定义及其 DAG
+type migrationTarget struct {
+	def *model.WorkflowDefModel
+	dag *model.DAG
+}
+
+// loadNodeAliases 读取 _sys 中的节点别名
+func loadNodeAliases(sys workflowStateSys) map[string]string {
+	raw, _ := sys[sysNodeAliasesKey].(map[string]interface{})
+	aliases := make(map[string]string, len(raw))
+	for k, v := range raw {
+		if s, ok := v.(string); ok {
+			aliases[k] = s
+		}
+	}
+	return aliases
+}
+
+// resolveNodeAlias 回调携带的节点 ID 不在当前 DAG 中时，按迁移登记的别名翻译成新 ID
+func resolveNodeAlias(sys workflowStateSys, dag *model.DAG, nodeID string) string {
+	if dag.GetNode(nodeID) != nil {
+		return nodeID
+	}
+	if alias, ok := loadNodeAliases(sys)[nodeID]; ok {
+		return alias
+	}
+	return nodeID
+}
+
+// planInstanceMigration 校验实例能否迁移到目标版本，返回迁移后的活跃节点与本次生效的改名；
+// 不能迁移时返回原因
+func planInstanceMigration(inst *model.WorkflowInstanceModel, fromDef *model.WorkflowDefModel, fromDAG *model.DAG, target *migrationTarget, mapping map[string]string) ([]string, map[string]string, string) {
+	if inst.Status != model.InstanceStatusRunning && inst.Status != model.InstanceStatusWaiting && inst.Status != model.InstanceStatusPaused {
+		return nil, nil, fmt.Sprintf("实例状态 %s 不可迁移", inst.Status)
+	}
+	if fromDef.Code != target.def.Code {
+		return nil, nil, fmt.Sprintf("实例属于定义 %s，不是 %s", fromDef.Code, target.def.Code)
+	}
+	if inst.DefID == target.def.ID {
+		return nil, nil, "实例已是目标版本"
+	}
+	var active []string
+	renamed := make(map[string]string)
+	for _, id := range parseActiveNodeIDs(inst.ActiveNodeIDs) {
+		newID := id
+		if mapped, ok := mapping[id]; ok && mapped != "" {
+			newID = mapped
+		}
+		newNode := target.dag.GetNode(newID)
+		if newNode == nil {
+			if newID != id {
+				return nil, nil, fmt.Sprintf("活跃节点 %s 映射到的 %s 在目标版本中不存在", id, newID)
+			}
+			return nil, nil, fmt.Sprintf("活跃节点 %s 在目标版本中不存在", id)
+		}
+		if oldNode := fromDAG.GetNode(id); oldNode != nil {
+			if oldNode.Type != newNode.Type {
+				return nil, nil, fmt.Sprintf("活跃节点 %s 的类型由 %s 变为 %s", id, oldNode.Type, newNode.Type)
+			}
+			// Map 节点的在途计数与结果按节点 ID 记账，改名会让后续子任务回调对不上
+			if newID != id && oldNode.Type == model.NodeTypeMap {
+				return nil, nil, fmt.Sprintf("Map 节点 %s 在途时不能改名", id)
+			}
+		}
+		if newID != id {
+			renamed[id] = newID
+		}
+		if !containsString(active, newID) {
+			active = append(active, newID)
+		}
+	}
+	return active, renamed, ""
+}
+
+// MigrateInstances 把实例迁移到同一定义编码的目标版本，逐个实例返回结果。
+//
+// 单个实例不能迁移不影响其他实例；dry-run 时报告哪些实例不能迁移及原因。
+func (a *App) MigrateInstances(ctx context.Context, in *MigrateInstancesInput) (*MigrationReport, error) {
+	if in == nil || in.DefCode == "" {
+		return nil, a.err.New("def_code 不能为空", nil).WithCode(errorc.ErrorCodeValid)
+	}
+	env := in.Env
+	if env == "" {
+		env = base.ENV
+	}
+	targetDef, err := a.GetDefByCodeAndVersion(ctx, env, in.DefCode, in.TargetVersion)
+	if err != nil {
+		if errorc.IsNotFound(err) {
+			return nil, a.err.New("目标定义版本不存在", err).NotFound()
+		}
+		return nil, a.err.New("获取目标定义失败", err)
+	}
+	var targetDAG model.DAG
+	if err := json.Unmarshal([]byte(targetDef.DAGJSON), &targetDAG); err != nil {
+		return nil, a.err.New("解析目标DAG失败", err)
+	}
+	targetDAG.Normalize()
+	target := &migrationTarget{def: targetDef, dag: &targetDAG}
+
+	ids := in.InstanceIDs
+	if len(ids) == 0 {
+		statuses := []model.WorkflowInstanceStatus{model.InstanceStatusRunning, model.InstanceStatusWaiting, model.InstanceStatusPaused}
+		var afterID int64
+		for {
+			batch, err := a.InstanceService.ListIDsByDefCode(ctx, in.DefCode, env, statuses, afterID, bulkInstanceBatchSize)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, batch...)
+			if len(batch) < bulkInstanceBatchSize {
+				break
+			}
+			afterID = batch[len(batch)-1]
+		}
+	}
+
+	report := &MigrationReport{
+		TargetDefID:   targetDef.ID,
+		TargetVersion: targetDef.Version,
+		DryRun:        in.DryRun,
+		Results:       make([]*InstanceMigrationResult, 0, len(ids)),
+	}
+	// 来源定义按 ID 缓存，同一批实例通常只涉及少数几个旧版本
+	fromDefs := make(map[int64]*migrationTarget)
+	for _, id := range ids {
+		res := a.migrateInstance(ctx, id, in, target, fromDefs)
+		if res == nil {
+			continue
+		}
+		if res.Migrated {
+			report.Migrated++
+		} else {
+			report.Rejected++
+		}
+		report.Results = append(report.Results, res)
+	}
+	a.log.WithField("def_code", in.DefCode).
+		WithField("target_version", targetDef.Version).
+		WithField("dry_run", in.DryRun).
+		WithField("migrated", report.Migrated).
+		WithField("rejected", report.Rejected).
+		Info("实例迁移完成")
+	return report, nil
+}
+
+// migrateInstance 在单独事务内锁定并迁移一个实例。按筛选条件选实例时，
+// FromVersion 不匹配的实例返回 nil，不计入报告
+func (a *App) migrateInstance(ctx context.Context, instanceID int64, in *MigrateInstancesInput, target *migrationTarget, fromDefs map[int64]*migrationTarget) *InstanceMigrationResult {
+	res := &InstanceMigrationResult{InstanceID: instanceID}
+	skip := false
+	err := mvc.ExtractDB(ctx, base.DB).Transaction(func(tx *gorm.DB) error {
+		inst, err := a.InstanceService.FindByIdForUpdate(ctx, tx, instanceID)
+		if err != nil {
+			if errorc.IsNotFound(err) {
+				res.Reason = "实例不存在"
+				return nil
+			}
+			return err
+		}
+		res.FromVersion = inst.DefVersion
+		if len(in.InstanceIDs) == 0 && in.FromVersion > 0 && inst.DefVersion != in.FromVersion {
+			skip = true
+			return nil
+		}
+		from, ok := fromDefs[inst.DefID]
+		if !ok {
+			def, err := a.DefService.FindByIdWithTx(ctx, tx, inst.DefID)
+			if err != nil {
+				return err
+			}
+			var dag model.DAG
+			if err := json.Unmarshal([]byte(def.DAGJSON), &dag); err != nil {
+				return fmt.Errorf("解析实例DAG失败: %w", err)
+			}
+			dag.Normalize()
+			from = &migrationTarget{def: def, dag: &dag}
+			fromDefs[inst.DefID] = from
+		}
+
+		active, renamed, reason := planInstanceMigration(inst, from.def, from.dag, target, in.NodeMapping)
+		if reason != "" {
+			res.Reason = reason
+			return nil
+		}
+		res.ActiveNodeIDs = active
+		if in.DryRun {
+			res.Migrated = true
+			return nil
+		}
+
+		data, sys, err := parseWorkflowState(inst.CurrentState)
+		if err != nil {
+			return fmt.Errorf("解析 CurrentState 失败: %w", err)
+		}
+		if sys == nil {
+			sys = make(workflowStateSys)
+		}
+		if len(renamed) > 0 {
+			// 已登记的别名一并指向最新的 ID，多次迁移后旧回调仍能落到正确节点
+			aliases := loadNodeAliases(sys)
+			for oldID, cur := range aliases {
+				if next, ok := renamed[cur]; ok {
+					aliases[oldID] = next
+				}
+			}
+			for oldID, newID := range renamed {
+				aliases[oldID] = newID
+			}
+			sys[sysNodeAliasesKey] = aliases
+			// 派发标识随节点改名：超时回调携带旧 ID，经别名翻译后按新 ID 核对，迁移不会让超时失效
+			if nonces, ok := sys[sysNodeTimeoutsKey].(map[string]interface{}); ok {
+				moved := make(map[string]interface{}, len(renamed))
+				for oldID, newID := range renamed {
+					if nonce, ok := nonces[oldID]; ok {
+						moved[newID] = nonce
+						delete(nonces, oldID)
+					}
+				}
+				for newID, nonce := range moved {
+					nonces[newID] = nonce
+				}
+			}
+			if snap, ok := loadPauseSnapshot(sys); ok {
+				for i, id := range snap.Pending {
+					if newID, ok := renamed[id]; ok {
+						snap.Pending[i] = newID
+					}
+				}
+				storePauseSnapshot(sys, snap)
+			}
+		}
+		newState, err := serializeWorkflowState(data, sys)
+		if err != nil {
+			return err
+		}
+		activeBefore := inst.ActiveNodeIDs
+		activeJSON, _ := json.Marshal(active)
+		mappingJSON, _ := json.Marshal(renamed)
+		inst.DefID = target.def.ID
+		inst.DefVersion = target.def.Version
+		inst.ActiveNodeIDs = string(activeJSON)
+		inst.CurrentState = newState
+		if err := a.InstanceService.SaveWithTx(ctx, tx, inst); err != nil {
+			return err
+		}
+		if err := tx.Create(&model.WorkflowInstanceMigrationModel{
+			InstanceID:   inst.ID,
+			FromDefID:    from.def.ID,
+			FromVersion:  from.def.Version,
+			ToDefID:      target.def.ID,
+			ToVersion:    target.def.Version,
+			NodeMapping:  string(mappingJSON),
+			ActiveBefore: activeBefore,
+			ActiveAfter:  string(activeJSON),
+			StateAfter:   newState,
+		}).Error; err != nil {
+			return err
+		}
+		res.Migrated = true
+		return a.recordInstanceEvent(mvc.WithTxToContext(ctx, tx), inst, model.InstanceEventMigrated, "", map[string]interface{}{
+			"from_version": from.def.Version,
+			"to_version":   target.def.Version,
+			"node_mapping": renamed,
+		})
+	})
+	if err != nil {
+		a.log.WithErr(err).WithField("instance_id", instanceID).Warn("迁移实例失败")
+		res.Migrated = false
+		res.ActiveNodeIDs = nil
+		res.Reason = "迁移失败: " + err.Error()
+	}
+	if skip {
+		return nil
+	}
+	return res
+}
+
+// ListInstanceMigrations 列出实例的迁移审计记录
+func (a *App) ListInstanceMigrations(ctx context.Context, instanceID int64) ([]*model.WorkflowInstanceMigrationModel, error) {
+	if a.MigrationService == nil {
+		return nil, a.err.New("迁移记录服务未初始化", nil)
+	}
+	return a.MigrationService.ListByInstance(ctx, instanceID)
+}
@@ -0,0 +1,208 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+)
+
+const migrationV1DAG = `{"nodes":[
+	{"id":"A","type":"task","config":{"service":"svc","method":"a"}},
+	{"id":"B","type":"task","config":{"service":"svc","method":"b"}}
+],"edges":[{"from":"A","to":"B"}]}`
+
+const migrationV2DAG = `{"nodes":[
+	{"id":"A","type":"task","config":{"service":"svc","method":"a"}},
+	{"id":"B2","type":"task","config":{"service":"svc","method":"b"}},
+	{"id":"C","type":"task","config":{"service":"svc","method":"c"}}
+],"edges":[{"from":"A","to":"B2"},{"from":"B2","to":"C"}]}`
+
+func migrationResult(t *testing.T, report *MigrationReport, id int64) *InstanceMigrationResult {
+	t.Helper()
+	for _, r := range report.Results {
+		if r.InstanceID == id {
+			return r
+		}
+	}
+	t.Fatalf("报告中没有实例 %d: %+v", id, report.Results)
+	return nil
+}
+
+// dry-run 报告活跃节点在新版本中不存在的实例且不改动任何数据；带节点映射迁移后，
+// 在途任务以旧节点 ID 回调仍按新 DAG 推进，并留下审计记录。
+func TestMigrateInstancesWithDryRunAndNodeMapping(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+	if _, err := a.CreateDef(ctx, "test", "mig_def", "mig", migrationV1DAG, 1); err != nil {
+		t.Fatalf("create v1: %v", err)
+	}
+	atB, err := a.StartWorkflow(ctx, "mig_def", nil, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	atA, err := a.StartWorkflow(ctx, "mig_def", nil, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := a.ReportNodeCompleted(ctx, atB, "A", map[string]interface{}{"x": 1}, "test"); err != nil {
+		t.Fatalf("report A: %v", err)
+	}
+	if _, err := a.CreateDef(ctx, "test", "mig_def", "mig", migrationV2DAG, 2); err != nil {
+		t.Fatalf("create v2: %v", err)
+	}
+
+	report, err := a.MigrateInstances(ctx, &MigrateInstancesInput{DefCode: "mig_def", Env: "test", DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if report.Migrated != 1 || report.Rejected != 1 || report.TargetVersion != 2 {
+		t.Fatalf("dry run report = %+v", report)
+	}
+	if r := migrationResult(t, report, atB); r.Migrated || !strings.Contains(r.Reason, "B") {
+		t.Fatalf("停在 B 的实例应因 B 不存在被拒绝: %+v", r)
+	}
+	if inst, _ := a.GetInstance(ctx, atA); inst.DefVersion != 1 {
+		t.Fatalf("dry-run 改动了实例版本: %d", inst.DefVersion)
+	}
+
+	report, err = a.MigrateInstances(ctx, &MigrateInstancesInput{
+		DefCode:     "mig_def",
+		Env:         "test",
+		NodeMapping: map[string]string{"B": "B2"},
+	})
+	if err != nil || report.Migrated != 2 {
+		t.Fatalf("migrate report = %+v, err = %v", report, err)
+	}
+	inst, err := a.GetInstance(ctx, atB)
+	if err != nil {
+		t.Fatalf("get instance: %v", err)
+	}
+	if inst.DefVersion != 2 || inst.ActiveNodeIDs != `["B2"]` {
+		t.Fatalf("version=%d active=%s, want 2 [B2]", inst.DefVersion, inst.ActiveNodeIDs)
+	}
+	records, err := a.ListInstanceMigrations(ctx, atB)
+	if err != nil || len(records) != 1 || records[0].FromVersion != 1 || records[0].ToVersion != 2 {
+		t.Fatalf("迁移记录 = %+v, err = %v", records, err)
+	}
+
+	// 迁移前派发的 B 任务回调仍携带旧 ID
+	if err := a.ReportNodeCompleted(ctx, atB, "B", map[string]interface{}{"y": 2}, "test"); err != nil {
+		t.Fatalf("report old B: %v", err)
+	}
+	if n := countNodeJobs(t, db, atB, "C", ""); n != 1 {
+		t.Fatalf("迁移后 C 的任务数 = %d, want 1", n)
+	}
+	var cpCount int64
+	db.Model(&model.WorkflowCheckpointModel{}).Where("instance_id = ? AND node_id = ?", atB, "B2").Count(&cpCount)
+	if cpCount != 1 {
+		t.Fatalf("旧 ID 回调未按新节点落 checkpoint: %d", cpCount)
+	}
+}
+
+// 迁移前派发的节点超时以旧 ID 回调，改名后仍须生效：取消旧 ID 下的在途任务并按新节点的 error 边推进，
+// 否则仍在运行的节点失去超时，可能永远挂起。
+func TestMigratedNodeKeepsItsTimeout(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+	v1 := `{"nodes":[
+		{"id":"A","type":"task","config":{"service":"svc","method":"a"}},
+		{"id":"B","type":"task","config":{"service":"svc","method":"b","timeout":"1m"}}
+	],"edges":[{"from":"A","to":"B"}]}`
+	v2 := `{"nodes":[
+		{"id":"A","type":"task","config":{"service":"svc","method":"a"}},
+		{"id":"B2","type":"task","config":{"service":"svc","method":"b","timeout":"1m"}},
+		{"id":"H","type":"task","config":{"service":"svc","method":"h"}}
+	],"edges":[{"from":"A","to":"B2"},{"from":"B2","to":"H","type":"error"}]}`
+	if _, err := a.CreateDef(ctx, "test", "mig_timeout", "mig", v1, 1); err != nil {
+		t.Fatalf("create v1: %v", err)
+	}
+	id, err := a.StartWorkflow(ctx, "mig_timeout", nil, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := a.ReportNodeCompleted(ctx, id, "A", map[string]interface{}{}, "test"); err != nil {
+		t.Fatalf("report A: %v", err)
+	}
+	var timer struct {
+		ID           int64
+		CallbackData string
+	}
+	db.Table("aio_executor_jobs").Select("id, callback_data").
+		Where("dedup_key LIKE ?", nodeDedupPrefix(id, "B")+"timeout_%").Scan(&timer)
+	if timer.ID == 0 {
+		t.Fatalf("未登记节点 B 的超时任务")
+	}
+
+	if _, err := a.CreateDef(ctx, "test", "mig_timeout", "mig", v2, 2); err != nil {
+		t.Fatalf("create v2: %v", err)
+	}
+	report, err := a.MigrateInstances(ctx, &MigrateInstancesInput{
+		DefCode:     "mig_timeout",
+		Env:         "test",
+		NodeMapping: map[string]string{"B": "B2"},
+	})
+	if err != nil || report.Migrated != 1 {
+		t.Fatalf("migrate report = %+v, err = %v", report, err)
+	}
+
+	if err := a.OnJobCompleted(ctx, uint64(timer.ID), timer.CallbackData, ""); err != nil {
+		t.Fatalf("timeout: %v", err)
+	}
+	inst, err := a.GetInstance(ctx, id)
+	if err != nil {
+		t.Fatalf("get instance: %v", err)
+	}
+	if inst.Status != model.InstanceStatusRunning || inst.ActiveNodeIDs != `["H"]` {
+		t.Fatalf("status=%s active=%s, want RUNNING [H]（超时应按 B2 的 error 边推进）", inst.Status, inst.ActiveNodeIDs)
+	}
+	if n := countNodeJobs(t, db, id, "B", "pending"); n != 0 {
+		t.Fatalf("B 待执行任务数 = %d, want 0（超时应取消旧 ID 下的在途任务）", n)
+	}
+}
+
+// 已结束的实例、活跃节点类型改变的实例都不能迁移，且各自给出原因
+func TestMigrateInstancesRejectsIncompatibleInstances(t *testing.T) {
+	ctx := context.Background()
+	a, _ := newTestApp(t)
+	if _, err := a.CreateDef(ctx, "test", "mig_def", "mig", migrationV1DAG, 1); err != nil {
+		t.Fatalf("create v1: %v", err)
+	}
+	running, err := a.StartWorkflow(ctx, "mig_def", nil, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	canceled, err := a.StartWorkflow(ctx, "mig_def", nil, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := a.CancelInstance(ctx, canceled); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	v2 := `{"nodes":[{"id":"A","type":"timer","config":{"duration":"1m"}}]}`
+	if _, err := a.CreateDef(ctx, "test", "mig_def", "mig", v2, 2); err != nil {
+		t.Fatalf("create v2: %v", err)
+	}
+
+	report, err := a.MigrateInstances(ctx, &MigrateInstancesInput{
+		DefCode:     "mig_def",
+		Env:         "test",
+		InstanceIDs: []int64{running, canceled},
+	})
+	if err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if report.Migrated != 0 || report.Rejected != 2 {
+		t.Fatalf("report = %+v, want 全部拒绝", report)
+	}
+	if r := migrationResult(t, report, running); !strings.Contains(r.Reason, "类型") {
+		t.Fatalf("类型改变的原因 = %q", r.Reason)
+	}
+	if r := migrationResult(t, report, canceled); !strings.Contains(r.Reason, "CANCELED") {
+		t.Fatalf("已取消实例的原因 = %q", r.Reason)
+	}
+	if inst, _ := a.GetInstance(ctx, running); inst.DefVersion != 1 {
+		t.Fatalf("被拒绝的实例版本被改动: %d", inst.DefVersion)
+	}
+}
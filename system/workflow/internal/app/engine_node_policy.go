@@ -0,0 +1,238 @@
+// 职责：节点级任务投递策略——重试次数、退避方式、优先级与节点超时。
+//
+// 边界：重试/退避/优先级只是透传给 executor 的 SubmitJobInput，由 executor 负责
+// 执行；超时则由本引擎负责：派发任务的同时登记一个到期唤醒任务（internal.timer_fired），
+// 到期时若节点仍活跃，取消其在途任务并按 error 边路由，不依赖 executor 租约到期。
+// 每次派发生成一个派发标识记入 _sys.node_timeouts，唤醒任务携带同一个值，不一致即视为过期：
+// 回环清理、回滚与重试都会删除节点的 checkpoint，不能再用 checkpoint 数区分派发轮次。
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/xsxdot/aio/base"
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	executorCallback "github.com/xsxdot/aio/system/executor/api/callback"
+	executorDto "github.com/xsxdot/aio/system/executor/api/dto"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultNodeMaxAttempts = 3
+	// sysNodeTimeoutsKey _sys 中各节点最近一次派发的超时标识（节点 ID -> 派发标识）
+	sysNodeTimeoutsKey = "node_timeouts"
+	// nodeTimeoutErrorMsg 节点超时时写入 error_msg 的内容
+	nodeTimeoutErrorMsg = "节点执行超时"
+)
+
+// nodeJobPolicy 节点派发任务时使用的投递策略
+type nodeJobPolicy struct {
+	MaxAttempts      int32
+	Priority         int32
+	RetryBackoffType executorDto.RetryBackoffType
+	RetryIntervalSec int32
+	Timeout          time.Duration
+}
+
+// parseNodeJobPolicy 从节点配置（或 map 节点的 iterator 块）读取投递策略，未配置的项取默认值。
+//
+// 配置项：max_attempts、priority、retry_backoff（exponential|fixed）、retry_interval_sec、
+// timeout（Go duration 字符串或秒数）。
+func parseNodeJobPolicy(conf map[string]interface{}) (nodeJobPolicy, error) {
+	p := nodeJobPolicy{
+		MaxAttempts:      defaultNodeMaxAttempts,
+		RetryBackoffType: executorDto.RetryBackoffExponential,
+	}
+	if v, ok := configInt(conf, "max_attempts"); ok {
+		if v < 1 {
+			return p, fmt.Errorf("max_attempts 必须 >= 1")
+		}
+		p.MaxAttempts = int32(v)
+	}
+	if v, ok := configInt(conf, "priority"); ok {
+		p.Priority = int32(v)
+	}
+	if s, ok := conf["retry_backoff"].(string); ok && s != "" {
+		switch executorDto.RetryBackoffType(s) {
+		case executorDto.RetryBackoffExponential, executorDto.RetryBackoffFixed:
+			p.RetryBackoffType = executorDto.RetryBackoffType(s)
+		default:
+			return p, fmt.Errorf("retry_backoff 仅支持 exponential 或 fixed: %s", s)
+		}
+	}
+	if v, ok := configInt(conf, "retry_interval_sec"); ok {
+		if v < 0 {
+			return p, fmt.Errorf("retry_interval_sec 不能为负数")
+		}
+		p.RetryIntervalSec = int32(v)
+	}
+	if raw, ok := conf["timeout"]; ok && raw != nil {
+		d, err := toTimerDuration(raw)
+		if err != nil {
+			return p, fmt.Errorf("timeout 配置无效: %w", err)
+		}
+		p.Timeout = d
+	}
+	return p, nil
+}
+
+// applyTo 将策略写入任务提交参数
+func (p nodeJobPolicy) applyTo(in *executorDto.SubmitJobInput) {
+	in.MaxAttempts = p.MaxAttempts
+	in.Priority = p.Priority
+	in.RetryBackoffType = p.RetryBackoffType
+	in.RetryIntervalSec = p.RetryIntervalSec
+}
+
+// scheduleNodeTimeout 为节点登记超时唤醒任务。
+//
+// 本次派发的标识写入 _sys.node_timeouts 并随唤醒任务回传，超时回调据此判断是否仍是同一次派发：
+// 回环、回滚或重试重新派发节点时派发标识被覆盖，上一次遗留的超时任务不能打断新的执行。
+func (a *App) scheduleNodeTimeout(ctx context.Context, instance *model.WorkflowInstanceModel, nodeID string, env string, timeout time.Duration) error {
+	nonce := strconv.FormatInt(time.Now().UnixNano(), 10)
+	if err := a.storeNodeTimeoutNonce(ctx, instance, nodeID, nonce); err != nil {
+		return a.err.New("登记节点派发标识失败", err)
+	}
+	callbackDataBytes, _ := json.Marshal(map[string]interface{}{
+		"instance_id": instance.ID,
+		"node_id":     nodeID,
+		"env":         env,
+		"timeout":     true,
+		"nonce":       nonce,
+	})
+	argsBytes, _ := json.Marshal(map[string]interface{}{"instance_id": instance.ID, "node_id": nodeID})
+	_, err := a.ExecutorClient.SubmitJob(ctx, &executorDto.SubmitJobInput{
+		Env:              env,
+		TargetService:    executorCallback.InternalTargetService,
+		Method:           executorCallback.MethodTimerFired,
+		ArgsJSON:         string(argsBytes),
+		RunAt:            time.Now().Add(timeout).Unix(),
+		MaxAttempts:      defaultNodeMaxAttempts,
+		DedupKey:         nodeDedupPrefix(instance.ID, nodeID) + fmt.Sprintf("timeout_%d", time.Now().UnixNano()),
+		RetryBackoffType: executorDto.RetryBackoffExponential,
+		Source:           "workflow",
+		CallbackData:     string(callbackDataBytes),
+	})
+	if err != nil {
+		return a.err.New("提交节点超时任务到Executor失败", err)
+	}
+	return nil
+}
+
+// storeNodeTimeoutNonce 加锁读取实例最新状态，记下节点本次的派发标识。
+// 调用方手里的实例可能已被同事务内的同步节点推进过，因此以库中状态为准并同步回 instance
+func (a *App) storeNodeTimeoutNonce(ctx context.Context, instance *model.WorkflowInstanceModel, nodeID, nonce string) error {
+	return mvc.ExtractDB(ctx, base.DB).Transaction(func(tx *gorm.DB) error {
+		inst, err := a.InstanceService.FindByIdForUpdate(ctx, tx, instance.ID)
+		if err != nil {
+			return err
+		}
+		data, sys, err := parseWorkflowState(inst.CurrentState)
+		if err != nil {
+			return fmt.Errorf("解析 CurrentState 失败: %w", err)
+		}
+		mapSysEntry(sys, sysNodeTimeoutsKey)[nodeID] = nonce
+		newStateStr, err := serializeWorkflowState(data, sys)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&model.WorkflowInstanceModel{}).Where("id = ?", instance.ID).
+			Update("current_state", newStateStr).Error; err != nil {
+			return err
+		}
+		instance.CurrentState = newStateStr
+		return nil
+	})
+}
+
+// nodeDedupPrefix 节点所有任务（执行、超时、截止等唤醒）共用的幂等键前缀。节点 ID 后紧跟
+// model.NodeIDDelimiter，按前缀取消时不会误中 ID 以 nodeID 开头的其他节点
+func nodeDedupPrefix(instanceID int64, nodeID string) string {
+	return fmt.Sprintf("wf_%d_node_%s%s", instanceID, nodeID, model.NodeIDDelimiter)
+}
+
+// nodeTimeoutNonce 节点最近一次派发登记的标识，未登记时为空
+func nodeTimeoutNonce(sys workflowStateSys, nodeID string) string {
+	nonces, _ := sys[sysNodeTimeoutsKey].(map[string]interface{})
+	nonce, _ := nonces[nodeID].(string)
+	return nonce
+}
+
+// timeoutNodeID 超时回调对应的当前节点 ID。实例迁移改名后回调仍携带旧 ID，
+// 派发标识已随改名登记到新 ID 下，按 _sys.node_aliases 翻译
+func timeoutNodeID(sys workflowStateSys, nodeID, nonce string) string {
+	if nodeTimeoutNonce(sys, nodeID) == nonce {
+		return nodeID
+	}
+	if alias, ok := loadNodeAliases(sys)[nodeID]; ok {
+		return alias
+	}
+	return nodeID
+}
+
+// handleNodeTimeout 处理节点超时回调：节点仍活跃且派发标识与最近一次派发一致时，
+// 取消其在途任务并以 error_msg 推进（走 error 边，无 error 边则实例 FAILED）。
+//
+// 过期的超时回调（节点已完成、实例已结束或节点已被重新派发）只登记幂等标记后忽略；
+// 实例已暂停时推迟到恢复后重放，jobID=0 即为重放。
+func (a *App) handleNodeTimeout(ctx context.Context, jobID int64, instanceID int64, nodeID string, env string, nonce string) error {
+	stale, deferred := false, false
+	target := nodeID
+	err := mvc.ExtractDB(ctx, base.DB).Transaction(func(tx *gorm.DB) error {
+		inst, err := a.InstanceService.FindByIdForUpdate(ctx, tx, instanceID)
+		if err != nil {
+			return err
+		}
+		if inst.Status != model.InstanceStatusRunning && inst.Status != model.InstanceStatusWaiting && inst.Status != model.InstanceStatusPaused {
+			stale = true
+		} else {
+			_, sys, err := parseWorkflowState(inst.CurrentState)
+			if err != nil {
+				return fmt.Errorf("解析 CurrentState 失败: %w", err)
+			}
+			target = timeoutNodeID(sys, nodeID, nonce)
+			stale = nonce == "" || !containsString(parseActiveNodeIDs(inst.ActiveNodeIDs), target) ||
+				nodeTimeoutNonce(sys, target) != nonce
+		}
+		if env == "" {
+			env = inst.Env
+		}
+		if stale {
+			if a.AppliedCallbackDao == nil || jobID == 0 {
+				return nil
+			}
+			_, mErr := a.AppliedCallbackDao.MarkApplied(ctx, tx, jobID, instanceID, nodeID)
+			return mErr
+		}
+		if inst.Status == model.InstanceStatusPaused {
+			deferred = true
+			return a.deferPausedTimer(ctx, tx, jobID, inst, pausedTimer{Kind: pausedTimerNodeTimeout, NodeID: nodeID, Nonce: nonce})
+		}
+
+		txCtx := mvc.WithTxToContext(ctx, tx)
+		// 取消节点仍在租约中的任务：其迟到的成功回调会因任务已取消而无法确认，不会再推进。
+		// 在途任务与子实例按派发时的节点 ID 登记，迁移改名后仍用回调携带的旧 ID
+		if err := a.ExecutorClient.CancelActiveJobsByDedupKeyPrefix(txCtx, env, nodeDedupPrefix(instanceID, nodeID)); err != nil {
+			a.log.WithErr(err).WithField("instance_id", instanceID).WithField("node_id", nodeID).
+				Warn("超时后取消节点在途任务失败")
+		}
+		a.cancelChildInstances(txCtx, instanceID, nodeID)
+		return a.reportNodeCompleted(txCtx, jobID, instanceID, target, map[string]interface{}{"error_msg": nodeTimeoutErrorMsg}, env)
+	})
+	if err != nil {
+		return a.err.New("处理节点超时失败", err).WithTraceID(ctx)
+	}
+	if stale {
+		a.log.WithField("instance_id", instanceID).WithField("node_id", nodeID).
+			Debug("节点超时回调已过期，忽略")
+	} else if !deferred {
+		a.log.WithField("instance_id", instanceID).WithField("node_id", nodeID).
+			Warn("节点执行超时，已按 error 边推进")
+	}
+	return nil
+}
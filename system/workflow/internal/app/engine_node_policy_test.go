@@ -0,0 +1,185 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	executorDto "github.com/xsxdot/aio/system/executor/api/dto"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+)
+
+// 未配置任何策略时必须与改造前硬编码的值一致（3 次、优先级 0、指数退避），
+// 否则存量 DAG 的重试行为会被悄悄改变。
+func TestParseNodeJobPolicyDefaultsMatchLegacy(t *testing.T) {
+	p, err := parseNodeJobPolicy(nil)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if p.MaxAttempts != 3 || p.Priority != 0 || p.RetryBackoffType != executorDto.RetryBackoffExponential || p.Timeout != 0 {
+		t.Fatalf("defaults = %+v", p)
+	}
+}
+
+func TestParseNodeJobPolicyReadsConfig(t *testing.T) {
+	p, err := parseNodeJobPolicy(map[string]interface{}{
+		"max_attempts":       float64(10),
+		"priority":           float64(5),
+		"retry_backoff":      "fixed",
+		"retry_interval_sec": float64(30),
+		"timeout":            "2m",
+	})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	in := &executorDto.SubmitJobInput{}
+	p.applyTo(in)
+	if in.MaxAttempts != 10 || in.Priority != 5 || in.RetryBackoffType != executorDto.RetryBackoffFixed || in.RetryIntervalSec != 30 {
+		t.Fatalf("applied = %+v", in)
+	}
+	if p.Timeout != 2*time.Minute {
+		t.Fatalf("timeout = %s, want 2m", p.Timeout)
+	}
+}
+
+// 支付类调用依赖 max_attempts=1 表示「绝不重试」；0 或负数必须拒绝，
+// 不能被当作「未配置」静默回落到 3 次。
+func TestParseNodeJobPolicyRejectsInvalid(t *testing.T) {
+	bad := []map[string]interface{}{
+		{"max_attempts": float64(0)},
+		{"retry_backoff": "linear"},
+		{"timeout": "soon"},
+	}
+	for _, conf := range bad {
+		if _, err := parseNodeJobPolicy(conf); err == nil {
+			t.Fatalf("conf %v 未被拒绝", conf)
+		}
+	}
+}
+
+// 节点已被重新派发（派发标识已覆盖）后到达的超时回调必须被忽略，
+// 否则会把新一次执行按 error 边推进。
+func TestHandleNodeTimeoutIgnoresStaleNonce(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+
+	state := `{"data":{},"_sys":{"node_timeouts":{"A":"2"}}}`
+	inst := &model.WorkflowInstanceModel{
+		DefID: 1, DefVersion: 1, Status: model.InstanceStatusRunning,
+		InitialState: state, CurrentState: state, ActiveNodeIDs: `["A"]`,
+	}
+	if err := db.Create(inst).Error; err != nil {
+		t.Fatalf("create instance: %v", err)
+	}
+
+	for i, nonce := range []string{"1", ""} {
+		if err := a.handleNodeTimeout(ctx, int64(7001+i), inst.ID, "A", "test", nonce); err != nil {
+			t.Fatalf("handle timeout: %v", err)
+		}
+	}
+	got, err := a.GetInstance(ctx, inst.ID)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if got.Status != model.InstanceStatusRunning || got.ActiveNodeIDs != `["A"]` {
+		t.Fatalf("过期超时回调推进了实例: status=%s active=%s", got.Status, got.ActiveNodeIDs)
+	}
+}
+
+// 回滚会删除节点的 checkpoint，按 checkpoint 数判断轮次时上一次派发遗留的超时任务会被当成本轮的，
+// 在新执行到期前把它判为超时；回滚后旧超时回调必须被忽略，新登记的超时照常生效。
+func TestNodeTimeoutFromBeforeRollbackIsStale(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+	dagJSON := `{"nodes":[
+		{"id":"A","type":"task","config":{"service":"svc","method":"a","timeout":"1m"}},
+		{"id":"B","type":"task","config":{"service":"svc","method":"b"}}
+	],"edges":[{"from":"A","to":"B"}]}`
+	if _, err := a.CreateDef(ctx, "test", "timeout_rollback", "timeout_rollback", dagJSON, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	id, err := a.StartWorkflow(ctx, "timeout_rollback", nil, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	type timerJob struct {
+		ID           int64
+		CallbackData string
+	}
+	latestTimer := func() timerJob {
+		var job timerJob
+		db.Table("aio_executor_jobs").Select("id, callback_data").
+			Where("dedup_key LIKE ?", fmt.Sprintf("wf_%d_node_A_timeout_%%", id)).
+			Order("id desc").Limit(1).Scan(&job)
+		if job.ID == 0 {
+			t.Fatalf("未登记节点 A 的超时任务")
+		}
+		return job
+	}
+	oldTimer := latestTimer()
+
+	if err := a.ReportNodeCompleted(ctx, id, "A", map[string]interface{}{}, "test"); err != nil {
+		t.Fatalf("complete A: %v", err)
+	}
+	if err := a.RollbackToNode(ctx, id, "A", "test"); err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+	newTimer := latestTimer()
+	if newTimer.ID == oldTimer.ID {
+		t.Fatalf("回滚后未重新登记超时任务")
+	}
+
+	if err := a.OnJobCompleted(ctx, uint64(oldTimer.ID), oldTimer.CallbackData, ""); err != nil {
+		t.Fatalf("old timer: %v", err)
+	}
+	got, _ := a.GetInstance(ctx, id)
+	if got.Status != model.InstanceStatusRunning || got.ActiveNodeIDs != `["A"]` {
+		t.Fatalf("回滚前的超时回调打断了新执行: status=%s active=%s", got.Status, got.ActiveNodeIDs)
+	}
+
+	if err := a.OnJobCompleted(ctx, uint64(newTimer.ID), newTimer.CallbackData, ""); err != nil {
+		t.Fatalf("new timer: %v", err)
+	}
+	if got, _ = a.GetInstance(ctx, id); got.Status != model.InstanceStatusFailed {
+		t.Fatalf("status = %s, want FAILED（新登记的超时应生效）", got.Status)
+	}
+}
+
+// 超时只取消本节点的在途任务：ID 以超时节点 ID 开头的并行节点（fetch 之于 fetch_detail）不能被一并取消，
+// 否则它拿不到回调，会一直挂起。
+func TestNodeTimeoutCancelsOnlyItsOwnJobs(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+	dagJSON := `{"nodes":[
+		{"id":"fetch","type":"task","config":{"service":"svc","method":"fetch","timeout":"1m"}},
+		{"id":"fetch_detail","type":"task","config":{"service":"svc","method":"detail"}},
+		{"id":"H","type":"task","config":{"service":"svc","method":"h"}}
+	],"edges":[{"from":"fetch","to":"H","type":"error"}]}`
+	if _, err := a.CreateDef(ctx, "test", "timeout_prefix", "timeout_prefix", dagJSON, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	id, err := a.StartWorkflow(ctx, "timeout_prefix", nil, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	var timer struct {
+		ID           int64
+		CallbackData string
+	}
+	db.Table("aio_executor_jobs").Select("id, callback_data").
+		Where("dedup_key LIKE ?", nodeDedupPrefix(id, "fetch")+"timeout_%").Scan(&timer)
+	if timer.ID == 0 {
+		t.Fatalf("未登记节点 fetch 的超时任务")
+	}
+
+	if err := a.OnJobCompleted(ctx, uint64(timer.ID), timer.CallbackData, ""); err != nil {
+		t.Fatalf("timeout: %v", err)
+	}
+	if n := countNodeJobs(t, db, id, "fetch", "pending"); n != 0 {
+		t.Fatalf("fetch 待执行任务数 = %d, want 0（超时应取消其在途任务）", n)
+	}
+	if n := countNodeJobs(t, db, id, "fetch_detail", "pending"); n != 1 {
+		t.Fatalf("fetch_detail 待执行任务数 = %d, want 1（不应被 fetch 的超时取消）", n)
+	}
+}
@@ -0,0 +1,342 @@
+// 职责：实例暂停与恢复——故障期间冻结实例，之后原样继续。
+//
+// 边界：暂停不取消在途任务，节点回调照常落 checkpoint 并合并状态，只是把本应派发的
+// 后继节点记入 _sys.pause.pending（它们已在 ActiveNodeIDs 中），恢复时统一补派。
+// 暂停期间到期的节点超时、审批截止与信号等待超时不改变路由，记入 _sys.pause.timers，
+// 恢复时按到期顺序重放，重放时照常判断是否已过期（如节点已完成、审批已决定）。
+// 暂停期间实例照样可能因无路可走而 COMPLETED / FAILED。暂停状态下不接受回滚与信号，
+// 可以直接取消或触发补偿。批量操作按实例逐个加锁处理，单个实例状态已变化时跳过。
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/xsxdot/aio/base"
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"gorm.io/gorm"
+)
+
+const (
+	// sysPauseKey _sys 中暂停现场的键
+	sysPauseKey = "pause"
+	// bulkInstanceBatchSize 批量暂停/恢复每批处理的实例数
+	bulkInstanceBatchSize = 200
+
+	pausedTimerNodeTimeout      = "node_timeout"
+	pausedTimerApprovalDeadline = "approval_deadline"
+	pausedTimerSignalTimeout    = "signal_timeout"
+)
+
+// pauseSnapshot 暂停现场：暂停前的状态，以及暂停期间被推迟派发的节点与推迟处理的计时器
+type pauseSnapshot struct {
+	Status  model.WorkflowInstanceStatus `json:"status"`
+	Pending []string                     `json:"pending,omitempty"`
+	Timers  []pausedTimer                `json:"timers,omitempty"`
+}
+
+// pausedTimer 暂停期间到期的计时器回调，恢复时重放
+type pausedTimer struct {
+	Kind   string `json:"kind"`
+	NodeID string `json:"node_id"`
+	// Nonce 节点超时对应的派发标识
+	Nonce string `json:"nonce,omitempty"`
+	// RefID 审批截止对应的审批待办 ID，信号等待超时对应的等待 ID
+	RefID int64 `json:"ref_id,omitempty"`
+}
+
+// loadPauseSnapshot 读取 _sys 中的暂停现场
+func loadPauseSnapshot(sys workflowStateSys) (*pauseSnapshot, bool) {
+	raw, ok := sys[sysPauseKey]
+	if !ok || raw == nil {
+		return nil, false
+	}
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return nil, false
+	}
+	var snap pauseSnapshot
+	if err := json.Unmarshal(b, &snap); err != nil {
+		return nil, false
+	}
+	return &snap, true
+}
+
+// storePauseSnapshot 将暂停现场写回 _sys
+func storePauseSnapshot(sys workflowStateSys, snap *pauseSnapshot) {
+	b, _ := json.Marshal(snap)
+	var raw map[string]interface{}
+	_ = json.Unmarshal(b, &raw)
+	sys[sysPauseKey] = raw
+}
+
+// markPausedAdvanced 暂停期间有节点完成：恢复时应回到 RUNNING，而不是暂停前的 WAITING
+func markPausedAdvanced(sys workflowStateSys) {
+	snap, ok := loadPauseSnapshot(sys)
+	if !ok || snap.Status == model.InstanceStatusRunning {
+		return
+	}
+	snap.Status = model.InstanceStatusRunning
+	storePauseSnapshot(sys, snap)
+}
+
+// deferPausedTriggers 暂停中的实例不派发后继节点，记入暂停现场待恢复时补派
+func (a *App) deferPausedTriggers(tx *gorm.DB, instance *model.WorkflowInstanceModel, nodeIDs []string) error {
+	if len(nodeIDs) == 0 {
+		return nil
+	}
+	data, sys, err := parseWorkflowState(instance.CurrentState)
+	if err != nil {
+		return fmt.Errorf("解析 CurrentState 失败: %w", err)
+	}
+	if sys == nil {
+		sys = make(workflowStateSys)
+	}
+	snap, ok := loadPauseSnapshot(sys)
+	if !ok {
+		snap = &pauseSnapshot{Status: model.InstanceStatusRunning}
+	}
+	for _, id := range nodeIDs {
+		if !containsString(snap.Pending, id) {
+			snap.Pending = append(snap.Pending, id)
+		}
+	}
+	storePauseSnapshot(sys, snap)
+	newState, err := serializeWorkflowState(data, sys)
+	if err != nil {
+		return err
+	}
+	instance.CurrentState = newState
+	if err := tx.Save(instance).Error; err != nil {
+		return err
+	}
+	a.log.WithField("instance_id", instance.ID).
+		WithField("pending", snap.Pending).
+		Info("实例已暂停，推迟派发后继节点")
+	return nil
+}
+
+// deferPausedTimer 暂停中的实例不处理到期的计时器：记入暂停现场待恢复时重放，并登记回调幂等标记。
+// 须在已对实例行加锁的事务内调用
+func (a *App) deferPausedTimer(ctx context.Context, tx *gorm.DB, jobID int64, instance *model.WorkflowInstanceModel, timer pausedTimer) error {
+	data, sys, err := parseWorkflowState(instance.CurrentState)
+	if err != nil {
+		return fmt.Errorf("解析 CurrentState 失败: %w", err)
+	}
+	if sys == nil {
+		sys = make(workflowStateSys)
+	}
+	snap, ok := loadPauseSnapshot(sys)
+	if !ok {
+		snap = &pauseSnapshot{Status: model.InstanceStatusRunning}
+	}
+	snap.Timers = append(snap.Timers, timer)
+	storePauseSnapshot(sys, snap)
+	newState, err := serializeWorkflowState(data, sys)
+	if err != nil {
+		return err
+	}
+	instance.CurrentState = newState
+	if err := tx.Save(instance).Error; err != nil {
+		return err
+	}
+	a.log.WithField("instance_id", instance.ID).
+		WithField("node_id", timer.NodeID).
+		WithField("kind", timer.Kind).
+		Info("实例已暂停，到期的计时器待恢复后处理")
+	if a.AppliedCallbackDao == nil || jobID == 0 {
+		return nil
+	}
+	_, err = a.AppliedCallbackDao.MarkApplied(ctx, tx, jobID, instance.ID, timer.NodeID)
+	return err
+}
+
+// replayPausedTimers 恢复后按到期顺序重放暂停期间推迟的计时器，各自重新判断是否已过期
+func (a *App) replayPausedTimers(ctx context.Context, instance *model.WorkflowInstanceModel, timers []pausedTimer) error {
+	for _, t := range timers {
+		var err error
+		switch t.Kind {
+		case pausedTimerNodeTimeout:
+			err = a.handleNodeTimeout(ctx, 0, instance.ID, t.NodeID, instance.Env, t.Nonce)
+		case pausedTimerApprovalDeadline:
+			err = a.handleApprovalDeadline(ctx, 0, t.RefID, instance.ID, t.NodeID, instance.Env)
+		case pausedTimerSignalTimeout:
+			err = a.handleSignalWaitTimeout(ctx, 0, t.RefID, instance.ID, t.NodeID, instance.Env)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PauseInstance 暂停运行中或等待中的实例
+func (a *App) PauseInstance(ctx context.Context, instanceID int64) error {
+	return mvc.ExtractDB(ctx, base.DB).Transaction(func(tx *gorm.DB) error {
+		instance, err := a.InstanceService.FindByIdForUpdate(ctx, tx, instanceID)
+		if err != nil {
+			if errorc.IsNotFound(err) {
+				return a.err.New("实例不存在", err).NotFound()
+			}
+			return a.err.New("获取实例失败", err)
+		}
+		if instance.Status != model.InstanceStatusRunning && instance.Status != model.InstanceStatusWaiting {
+			return a.err.New("只有运行中或等待中的实例才能暂停", nil).WithCode(errorc.ErrorCodeValid)
+		}
+		data, sys, err := parseWorkflowState(instance.CurrentState)
+		if err != nil {
+			return a.err.New("解析状态失败", err)
+		}
+		if sys == nil {
+			sys = make(workflowStateSys)
+		}
+		storePauseSnapshot(sys, &pauseSnapshot{Status: instance.Status})
+		newState, err := serializeWorkflowState(data, sys)
+		if err != nil {
+			return a.err.New("序列化状态失败", err)
+		}
+		instance.CurrentState = newState
+		instance.Status = model.InstanceStatusPaused
+		if err := a.InstanceService.SaveWithTx(ctx, tx, instance); err != nil {
+			return a.err.New("更新实例状态失败", err)
+		}
+		return a.recordInstanceEvent(mvc.WithTxToContext(ctx, tx), instance, model.InstanceEventPaused, "", nil)
+	})
+}
+
+// ResumeInstance 恢复已暂停的实例：回到暂停前的状态，补派暂停期间积压的节点，
+// 再重放暂停期间到期的计时器
+func (a *App) ResumeInstance(ctx context.Context, instanceID int64) error {
+	return mvc.ExtractDB(ctx, base.DB).Transaction(func(tx *gorm.DB) error {
+		instance, err := a.InstanceService.FindByIdForUpdate(ctx, tx, instanceID)
+		if err != nil {
+			if errorc.IsNotFound(err) {
+				return a.err.New("实例不存在", err).NotFound()
+			}
+			return a.err.New("获取实例失败", err)
+		}
+		if instance.Status != model.InstanceStatusPaused {
+			return a.err.New("只有已暂停的实例才能恢复", nil).WithCode(errorc.ErrorCodeValid)
+		}
+		data, sys, err := parseWorkflowState(instance.CurrentState)
+		if err != nil {
+			return a.err.New("解析状态失败", err)
+		}
+		snap, ok := loadPauseSnapshot(sys)
+		if !ok {
+			snap = &pauseSnapshot{Status: model.InstanceStatusRunning}
+		}
+		delete(sys, sysPauseKey)
+		newState, err := serializeWorkflowState(data, sys)
+		if err != nil {
+			return a.err.New("序列化状态失败", err)
+		}
+		// 积压节点可能已被 join 取消兄弟分支等逻辑移出活跃列表，只补派仍活跃的
+		active := parseActiveNodeIDs(instance.ActiveNodeIDs)
+		var pending []string
+		for _, id := range snap.Pending {
+			if containsString(active, id) {
+				pending = append(pending, id)
+			}
+		}
+		instance.CurrentState = newState
+		instance.Status = snap.Status
+		if len(pending) > 0 {
+			instance.Status = model.InstanceStatusRunning
+		}
+		if err := a.InstanceService.SaveWithTx(ctx, tx, instance); err != nil {
+			return a.err.New("更新实例状态失败", err)
+		}
+		txCtx := mvc.WithTxToContext(ctx, tx)
+		if err := a.recordInstanceEvent(txCtx, instance, model.InstanceEventResumed, "", map[string]interface{}{
+			"pending": pending,
+			"timers":  len(snap.Timers),
+		}); err != nil {
+			return err
+		}
+		if err := a.triggerResumedNodes(txCtx, instance, pending); err != nil {
+			return err
+		}
+		// 与恢复同事务重放：失败则整体回滚，实例保持暂停
+		return a.replayPausedTimers(txCtx, instance, snap.Timers)
+	})
+}
+
+// triggerResumedNodes 派发暂停期间积压的节点
+func (a *App) triggerResumedNodes(ctx context.Context, instance *model.WorkflowInstanceModel, pending []string) error {
+	if len(pending) == 0 {
+		return nil
+	}
+	tx := mvc.ExtractDB(ctx, base.DB)
+	def, err := a.DefService.FindByIdWithTx(ctx, tx, instance.DefID)
+	if err != nil {
+		return a.err.New("获取工作流定义失败", err)
+	}
+	var dag model.DAG
+	if err := json.Unmarshal([]byte(def.DAGJSON), &dag); err != nil {
+		return a.err.New("解析DAG失败", err)
+	}
+	dag.Normalize()
+	env := instance.Env
+	if env == "" {
+		env = base.ENV
+	}
+	for _, nodeID := range pending {
+		node := dag.GetNode(nodeID)
+		if node == nil {
+			continue
+		}
+		// 与恢复同事务派发：失败则整体回滚，实例保持暂停
+		if err := a.triggerNode(ctx, instance, node, &dag, env); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PauseInstancesByDef 暂停某定义编码（全部版本）在指定环境下所有运行中或等待中的实例，返回暂停的数量
+func (a *App) PauseInstancesByDef(ctx context.Context, defCode, env string) (int64, error) {
+	return a.bulkInstanceAction(ctx, defCode, env,
+		[]model.WorkflowInstanceStatus{model.InstanceStatusRunning, model.InstanceStatusWaiting}, a.PauseInstance, "暂停")
+}
+
+// ResumeInstancesByDef 恢复某定义编码（全部版本）在指定环境下所有已暂停的实例，返回恢复的数量
+func (a *App) ResumeInstancesByDef(ctx context.Context, defCode, env string) (int64, error) {
+	return a.bulkInstanceAction(ctx, defCode, env,
+		[]model.WorkflowInstanceStatus{model.InstanceStatusPaused}, a.ResumeInstance, "恢复")
+}
+
+// bulkInstanceAction 分批遍历匹配的实例逐个执行 fn，单个实例失败（如状态已被并发改变）时
+// 记日志跳过，返回成功处理的数量
+func (a *App) bulkInstanceAction(ctx context.Context, defCode, env string, statuses []model.WorkflowInstanceStatus, fn func(context.Context, int64) error, action string) (int64, error) {
+	if defCode == "" {
+		return 0, a.err.New("def_code 不能为空", nil).WithCode(errorc.ErrorCodeValid)
+	}
+	if env == "" {
+		env = base.ENV
+	}
+	var affected, afterID int64
+	for {
+		ids, err := a.InstanceService.ListIDsByDefCode(ctx, defCode, env, statuses, afterID, bulkInstanceBatchSize)
+		if err != nil {
+			return affected, err
+		}
+		for _, id := range ids {
+			if err := fn(ctx, id); err != nil {
+				a.log.WithErr(err).
+					WithField("instance_id", id).
+					WithField("def_code", defCode).
+					Warnf("批量%s实例时跳过", action)
+				continue
+			}
+			affected++
+		}
+		if len(ids) < bulkInstanceBatchSize {
+			return affected, nil
+		}
+		afterID = ids[len(ids)-1]
+	}
+}
@@ -0,0 +1,171 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+)
+
+const pauseTestDAG = `{"nodes":[
+	{"id":"A","type":"task","config":{"service":"svc","method":"a"}},
+	{"id":"B","type":"task","config":{"service":"svc","method":"b"}}
+],"edges":[{"from":"A","to":"B"}]}`
+
+// 暂停期间节点回调照常落 checkpoint 与状态，但后继节点不派发；恢复后补派并回到 RUNNING。
+func TestPausedInstanceDefersSuccessorsUntilResume(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+	if _, err := a.CreateDef(ctx, "test", "pause_def", "pause", pauseTestDAG, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	id, err := a.StartWorkflow(ctx, "pause_def", nil, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := a.PauseInstance(ctx, id); err != nil {
+		t.Fatalf("pause: %v", err)
+	}
+	if err := a.PauseInstance(ctx, id); err == nil {
+		t.Fatal("重复暂停未被拒绝")
+	}
+
+	if err := a.ReportNodeCompleted(ctx, id, "A", map[string]interface{}{"x": 1}, "test"); err != nil {
+		t.Fatalf("report A while paused: %v", err)
+	}
+	inst, err := a.GetInstance(ctx, id)
+	if err != nil {
+		t.Fatalf("get instance: %v", err)
+	}
+	if inst.Status != model.InstanceStatusPaused || inst.ActiveNodeIDs != `["B"]` {
+		t.Fatalf("status=%s active=%s, want PAUSED [B]", inst.Status, inst.ActiveNodeIDs)
+	}
+	if data, _, _ := parseWorkflowState(inst.CurrentState); data["x"] != float64(1) {
+		t.Fatalf("暂停期间的节点输出未合并: %v", data)
+	}
+	if n := countNodeJobs(t, db, id, "B", ""); n != 0 {
+		t.Fatalf("暂停期间派发了 B 的任务: %d", n)
+	}
+
+	if err := a.ResumeInstance(ctx, id); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	inst, err = a.GetInstance(ctx, id)
+	if err != nil {
+		t.Fatalf("get instance: %v", err)
+	}
+	if inst.Status != model.InstanceStatusRunning {
+		t.Fatalf("status = %s, want RUNNING", inst.Status)
+	}
+	if n := countNodeJobs(t, db, id, "B", ""); n != 1 {
+		t.Fatalf("恢复后 B 的任务数 = %d, want 1", n)
+	}
+	if _, sys, _ := parseWorkflowState(inst.CurrentState); sys[sysPauseKey] != nil {
+		t.Fatalf("恢复后暂停现场未清理: %v", sys)
+	}
+	if err := a.ResumeInstance(ctx, id); err == nil {
+		t.Fatal("未暂停的实例恢复未被拒绝")
+	}
+}
+
+// 批量暂停只作用于该定义运行中/等待中的实例，已结束的实例不受影响；批量恢复对应地恢复。
+func TestPauseAndResumeInstancesByDef(t *testing.T) {
+	ctx := context.Background()
+	a, _ := newTestApp(t)
+	if _, err := a.CreateDef(ctx, "test", "pause_def", "pause", pauseTestDAG, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	var ids []int64
+	for i := 0; i < 3; i++ {
+		id, err := a.StartWorkflow(ctx, "pause_def", nil, "test")
+		if err != nil {
+			t.Fatalf("start: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := a.CancelInstance(ctx, ids[2]); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+
+	n, err := a.PauseInstancesByDef(ctx, "pause_def", "test")
+	if err != nil || n != 2 {
+		t.Fatalf("paused = %d, err = %v, want 2", n, err)
+	}
+	for i, id := range ids {
+		want := model.InstanceStatusPaused
+		if i == 2 {
+			want = model.InstanceStatusCanceled
+		}
+		if s := instanceStatus(t, a, id); s != want {
+			t.Fatalf("instance %d status = %s, want %s", id, s, want)
+		}
+	}
+
+	n, err = a.ResumeInstancesByDef(ctx, "pause_def", "test")
+	if err != nil || n != 2 {
+		t.Fatalf("resumed = %d, err = %v, want 2", n, err)
+	}
+	if s := instanceStatus(t, a, ids[0]); s != model.InstanceStatusRunning {
+		t.Fatalf("status = %s, want RUNNING", s)
+	}
+}
+
+// 暂停期间到期的节点超时不改变路由，恢复后才按 error 边推进；暂停期间节点已完成的，
+// 恢复时重放的超时已过期，按正常路径补派后继。
+func TestNodeTimeoutDuringPauseReplaysOnResume(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+	dagJSON := `{"nodes":[
+		{"id":"A","type":"task","config":{"service":"svc","method":"a","timeout":"1m"}},
+		{"id":"B","type":"task","config":{"service":"svc","method":"b"}},
+		{"id":"H","type":"task","config":{"service":"svc","method":"h"}}
+	],"edges":[{"from":"A","to":"B"},{"from":"A","to":"H","type":"error"}]}`
+	if _, err := a.CreateDef(ctx, "test", "pause_timeout", "pause_timeout", dagJSON, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	for _, completeWhilePaused := range []bool{false, true} {
+		id, err := a.StartWorkflow(ctx, "pause_timeout", nil, "test")
+		if err != nil {
+			t.Fatalf("start: %v", err)
+		}
+		var timer struct {
+			ID           uint64
+			CallbackData string
+		}
+		if err := db.Table("aio_executor_jobs").Select("id, callback_data").
+			Where("dedup_key LIKE ?", fmt.Sprintf("wf_%d_node_A_timeout_%%", id)).Take(&timer).Error; err != nil {
+			t.Fatalf("超时任务未登记: %v", err)
+		}
+		if err := a.PauseInstance(ctx, id); err != nil {
+			t.Fatalf("pause: %v", err)
+		}
+		if err := a.OnJobCompleted(ctx, timer.ID, timer.CallbackData, ""); err != nil {
+			t.Fatalf("timer: %v", err)
+		}
+		inst, _ := a.GetInstance(ctx, id)
+		if inst.Status != model.InstanceStatusPaused || inst.ActiveNodeIDs != `["A"]` {
+			t.Fatalf("暂停期间超时改变了实例: status=%s active=%s", inst.Status, inst.ActiveNodeIDs)
+		}
+		if completeWhilePaused {
+			if err := a.ReportNodeCompleted(ctx, id, "A", map[string]interface{}{"ok": true}, "test"); err != nil {
+				t.Fatalf("complete A: %v", err)
+			}
+		}
+
+		if err := a.ResumeInstance(ctx, id); err != nil {
+			t.Fatalf("resume: %v", err)
+		}
+		want, other := "H", "B"
+		if completeWhilePaused {
+			want, other = "B", "H"
+		}
+		inst, _ = a.GetInstance(ctx, id)
+		if inst.ActiveNodeIDs != fmt.Sprintf(`["%s"]`, want) || countNodeJobs(t, db, id, want, "") != 1 || countNodeJobs(t, db, id, other, "") != 0 {
+			t.Fatalf("暂停中完成=%v: active=%s, want 只派发 %s", completeWhilePaused, inst.ActiveNodeIDs, want)
+		}
+		if _, sys, _ := parseWorkflowState(inst.CurrentState); sys[sysPauseKey] != nil {
+			t.Fatalf("恢复后暂停现场未清理: %v", sys)
+		}
+	}
+}
@@ -0,0 +1,321 @@
+// 职责：把定义的 DAG 渲染成 Mermaid flowchart 或 Graphviz DOT 文本，便于在 PR 中审阅定义、
+// 在管理端查看实例卡在哪里；实例视图按 checkpoint 与活跃节点给节点着色。
+//
+// 边界：只读，不触碰引擎状态。边的 error/always 类型、回环、审批 decision 与条件表达式
+// 作为边标签；节点 ID 在 Mermaid 中映射为 n0、n1… 以避开语法字符，原始 ID 保留在标签里。
+// 实例着色规则：最近一次 checkpoint 输出含 error_msg 为 failed、否则为 completed；当前活跃的
+// 节点覆盖 checkpoint 状态，审批/定时/子工作流节点或实例处于 WAITING 时为 waiting，其余为
+// active。迁移前的旧节点 ID 与信号等非节点 checkpoint 不在图中，直接忽略。
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+)
+
+const (
+	// RenderFormatMermaid Mermaid flowchart 文本（默认）
+	RenderFormatMermaid = "mermaid"
+	// RenderFormatDOT Graphviz DOT 文本
+	RenderFormatDOT = "dot"
+)
+
+// 实例视图中的节点状态，未出现的节点表示尚未执行
+const (
+	NodeStatusCompleted = "completed"
+	NodeStatusActive    = "active"
+	NodeStatusWaiting   = "waiting"
+	NodeStatusFailed    = "failed"
+)
+
+// nodeStatusOrder 着色样式的输出顺序，保证渲染结果稳定
+var nodeStatusOrder = []string{NodeStatusCompleted, NodeStatusActive, NodeStatusWaiting, NodeStatusFailed}
+
+// nodeStatusColors 各状态的填充色与描边色
+var nodeStatusColors = map[string][2]string{
+	NodeStatusCompleted: {"#d4edda", "#28a745"},
+	NodeStatusActive:    {"#cce5ff", "#1f6feb"},
+	NodeStatusWaiting:   {"#fff3cd", "#d39e00"},
+	NodeStatusFailed:    {"#f8d7da", "#d9534f"},
+}
+
+const renderErrorEdgeColor = "#d9534f"
+
+// RenderedGraph 渲染结果；NodeStatus 仅实例视图返回，键为节点 ID
+type RenderedGraph struct {
+	Format     string            `json:"format"`
+	Content    string            `json:"content"`
+	NodeStatus map[string]string `json:"node_status,omitempty"`
+}
+
+// normalizeRenderFormat 校验渲染格式，空值为 mermaid
+func normalizeRenderFormat(format string) (string, bool) {
+	switch f := strings.ToLower(strings.TrimSpace(format)); f {
+	case "":
+		return RenderFormatMermaid, true
+	case RenderFormatMermaid, RenderFormatDOT:
+		return f, true
+	default:
+		return "", false
+	}
+}
+
+// RenderDef 渲染定义的 DAG，version<=0 表示最新版本
+func (a *App) RenderDef(ctx context.Context, env, code string, version int32, format string) (*RenderedGraph, error) {
+	f, ok := normalizeRenderFormat(format)
+	if !ok {
+		return nil, a.err.New("format 仅支持 mermaid 或 dot", nil).WithCode(errorc.ErrorCodeValid)
+	}
+	def, err := a.GetDefByCodeAndVersion(ctx, env, code, version)
+	if err != nil {
+		if errorc.IsNotFound(err) {
+			return nil, a.err.New("工作流定义不存在", err).NotFound()
+		}
+		return nil, a.err.New("获取工作流定义失败", err)
+	}
+	var dag model.DAG
+	if err := json.Unmarshal([]byte(def.DAGJSON), &dag); err != nil {
+		return nil, a.err.New("解析DAG失败", err)
+	}
+	dag.Normalize()
+	return &RenderedGraph{Format: f, Content: renderDAG(&dag, f, nil)}, nil
+}
+
+// RenderInstance 渲染实例所用定义版本的 DAG，并按执行进度给节点着色
+func (a *App) RenderInstance(ctx context.Context, instanceID int64, format string) (*RenderedGraph, error) {
+	f, ok := normalizeRenderFormat(format)
+	if !ok {
+		return nil, a.err.New("format 仅支持 mermaid 或 dot", nil).WithCode(errorc.ErrorCodeValid)
+	}
+	instance, err := a.InstanceService.FindById(ctx, instanceID)
+	if err != nil {
+		if errorc.IsNotFound(err) {
+			return nil, a.err.New("实例不存在", err).NotFound()
+		}
+		return nil, a.err.New("获取实例失败", err)
+	}
+	def, err := a.DefService.FindById(ctx, instance.DefID)
+	if err != nil {
+		return nil, a.err.New("获取工作流定义失败", err)
+	}
+	var dag model.DAG
+	if err := json.Unmarshal([]byte(def.DAGJSON), &dag); err != nil {
+		return nil, a.err.New("解析DAG失败", err)
+	}
+	dag.Normalize()
+	checkpoints, err := a.CheckpointService.ListTrailByInstanceIDOrderByCreatedAsc(ctx, instanceID)
+	if err != nil {
+		return nil, a.err.New("获取执行快照失败", err)
+	}
+	status := instanceNodeStatus(instance, &dag, checkpoints)
+	return &RenderedGraph{Format: f, Content: renderDAG(&dag, f, status), NodeStatus: status}, nil
+}
+
+// instanceNodeStatus 按 checkpoint（升序）与活跃节点计算实例中各节点的状态
+func instanceNodeStatus(instance *model.WorkflowInstanceModel, dag *model.DAG, checkpoints []*model.WorkflowCheckpointModel) map[string]string {
+	status := make(map[string]string)
+	for _, cp := range checkpoints {
+		if dag.GetNode(cp.NodeID) == nil {
+			continue
+		}
+		var output map[string]interface{}
+		_ = json.Unmarshal([]byte(cp.NodeOutput), &output)
+		if _, failed := output["error_msg"]; failed {
+			status[cp.NodeID] = NodeStatusFailed
+		} else {
+			status[cp.NodeID] = NodeStatusCompleted
+		}
+	}
+	for _, id := range parseActiveNodeIDs(instance.ActiveNodeIDs) {
+		node := dag.GetNode(id)
+		if node == nil {
+			continue
+		}
+		if instance.Status == model.InstanceStatusWaiting || isWaitingNodeType(node.Type) {
+			status[id] = NodeStatusWaiting
+		} else {
+			status[id] = NodeStatusActive
+		}
+	}
+	return status
+}
+
+// renderDAG 按格式渲染 DAG；nodeStatus 非空时按状态着色
+func renderDAG(dag *model.DAG, format string, nodeStatus map[string]string) string {
+	if format == RenderFormatDOT {
+		return renderDOT(dag, nodeStatus)
+	}
+	return renderMermaid(dag, nodeStatus)
+}
+
+// renderNodeLines 节点标签：第一行为节点 ID，第二行为 task 的 service.method 或节点类型
+func renderNodeLines(n *model.Node) []string {
+	if n.Type == model.NodeTypeTask || n.Type == "" {
+		service, _ := n.Config["service"].(string)
+		method, _ := n.Config["method"].(string)
+		if service != "" || method != "" {
+			return []string{n.ID, service + "." + method}
+		}
+		return []string{n.ID}
+	}
+	return []string{n.ID, string(n.Type)}
+}
+
+// renderEdgeLabel 边标签：类型、回环与审批 decision 在前，条件表达式在后
+func renderEdgeLabel(e model.Edge) string {
+	var tags []string
+	if e.Type == model.EdgeTypeError || e.Type == model.EdgeTypeAlways {
+		tags = append(tags, string(e.Type))
+	}
+	if e.IsLoopback {
+		tags = append(tags, "loopback")
+	}
+	if e.Decision != "" {
+		tags = append(tags, "decision="+e.Decision)
+	}
+	label := strings.Join(tags, " ")
+	if cond := strings.Join(strings.Fields(e.Condition), " "); cond != "" {
+		if label != "" {
+			label += ": "
+		}
+		label += cond
+	}
+	return label
+}
+
+// isWaitingNodeType 等待外部事件推进的节点类型：渲染为圆角，活跃时着色为 waiting
+func isWaitingNodeType(t model.NodeType) bool {
+	return t == model.NodeTypeApproval || t == model.NodeTypeTimer || t == model.NodeTypeSubWorkflow || t == model.NodeTypeWaitSignal
+}
+
+var mermaidEscaper = strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;", "|", "#124;", "\n", " ")
+
+func renderMermaid(dag *model.DAG, nodeStatus map[string]string) string {
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+	keys := make(map[string]string, len(dag.Nodes))
+	key := func(id string) string {
+		if k, ok := keys[id]; ok {
+			return k
+		}
+		k := fmt.Sprintf("n%d", len(keys))
+		keys[id] = k
+		return k
+	}
+	byStatus := make(map[string][]string)
+	for i := range dag.Nodes {
+		n := &dag.Nodes[i]
+		lines := renderNodeLines(n)
+		for j, l := range lines {
+			lines[j] = mermaidEscaper.Replace(l)
+		}
+		label := `"` + strings.Join(lines, "<br/>") + `"`
+		k := key(n.ID)
+		switch {
+		case n.Type == model.NodeTypeCondition:
+			fmt.Fprintf(&b, "    %s{%s}\n", k, label)
+		case n.Type == model.NodeTypeLoop:
+			fmt.Fprintf(&b, "    %s{{%s}}\n", k, label)
+		case isWaitingNodeType(n.Type):
+			fmt.Fprintf(&b, "    %s([%s])\n", k, label)
+		default:
+			fmt.Fprintf(&b, "    %s[%s]\n", k, label)
+		}
+		if s, ok := nodeStatus[n.ID]; ok {
+			byStatus[s] = append(byStatus[s], k)
+		}
+	}
+	var errorLinks []string
+	for i, e := range dag.Edges {
+		arrow := "-->"
+		switch {
+		case e.IsLoopback:
+			arrow = "-.->"
+		case e.Type == model.EdgeTypeAlways:
+			arrow = "==>"
+		}
+		if label := renderEdgeLabel(e); label != "" {
+			fmt.Fprintf(&b, "    %s %s|\"%s\"| %s\n", key(e.From), arrow, mermaidEscaper.Replace(label), key(e.To))
+		} else {
+			fmt.Fprintf(&b, "    %s %s %s\n", key(e.From), arrow, key(e.To))
+		}
+		if e.Type == model.EdgeTypeError {
+			errorLinks = append(errorLinks, fmt.Sprint(i))
+		}
+	}
+	if len(errorLinks) > 0 {
+		fmt.Fprintf(&b, "    linkStyle %s stroke:%s,color:%s\n", strings.Join(errorLinks, ","), renderErrorEdgeColor, renderErrorEdgeColor)
+	}
+	for _, s := range nodeStatusOrder {
+		if len(byStatus[s]) == 0 {
+			continue
+		}
+		c := nodeStatusColors[s]
+		fmt.Fprintf(&b, "    classDef %s fill:%s,stroke:%s\n", s, c[0], c[1])
+		fmt.Fprintf(&b, "    class %s %s\n", strings.Join(byStatus[s], ","), s)
+	}
+	return b.String()
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", " ")
+
+func renderDOT(dag *model.DAG, nodeStatus map[string]string) string {
+	var b strings.Builder
+	b.WriteString("digraph workflow {\n")
+	b.WriteString("    rankdir=TB;\n")
+	b.WriteString("    node [shape=box, fontname=\"Helvetica\"];\n")
+	b.WriteString("    edge [fontname=\"Helvetica\", fontsize=10];\n")
+	for i := range dag.Nodes {
+		n := &dag.Nodes[i]
+		lines := renderNodeLines(n)
+		for j, l := range lines {
+			lines[j] = dotEscaper.Replace(l)
+		}
+		attrs := []string{fmt.Sprintf(`label="%s"`, strings.Join(lines, `\n`))}
+		var styles []string
+		switch {
+		case n.Type == model.NodeTypeCondition:
+			attrs = append(attrs, "shape=diamond")
+		case n.Type == model.NodeTypeLoop:
+			attrs = append(attrs, "shape=hexagon")
+		case isWaitingNodeType(n.Type):
+			styles = append(styles, "rounded")
+		}
+		if s, ok := nodeStatus[n.ID]; ok {
+			c := nodeStatusColors[s]
+			styles = append(styles, "filled")
+			attrs = append(attrs, fmt.Sprintf(`fillcolor="%s"`, c[0]), fmt.Sprintf(`color="%s"`, c[1]))
+		}
+		if len(styles) > 0 {
+			attrs = append(attrs, fmt.Sprintf(`style="%s"`, strings.Join(styles, ",")))
+		}
+		fmt.Fprintf(&b, "    \"%s\" [%s];\n", dotEscaper.Replace(n.ID), strings.Join(attrs, ", "))
+	}
+	for _, e := range dag.Edges {
+		var attrs []string
+		if label := renderEdgeLabel(e); label != "" {
+			attrs = append(attrs, fmt.Sprintf(`label="%s"`, dotEscaper.Replace(label)))
+		}
+		switch {
+		case e.IsLoopback:
+			attrs = append(attrs, "style=dashed", "constraint=false")
+		case e.Type == model.EdgeTypeAlways:
+			attrs = append(attrs, "style=bold")
+		}
+		if e.Type == model.EdgeTypeError {
+			attrs = append(attrs, fmt.Sprintf(`color="%s"`, renderErrorEdgeColor), fmt.Sprintf(`fontcolor="%s"`, renderErrorEdgeColor))
+		}
+		line := fmt.Sprintf("    \"%s\" -> \"%s\"", dotEscaper.Replace(e.From), dotEscaper.Replace(e.To))
+		if len(attrs) > 0 {
+			line += " [" + strings.Join(attrs, ", ") + "]"
+		}
+		b.WriteString(line + ";\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
@@ -0,0 +1,129 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	errorc "github.com/xsxdot/gokit/err"
+)
+
+const renderTestDAG = `{"nodes":[
+	{"id":"A","type":"task","config":{"service":"svc","method":"a"}},
+	{"id":"C","type":"condition","config":{}},
+	{"id":"B","type":"task","config":{"service":"svc","method":"b"}},
+	{"id":"W","type":"timer","config":{"duration":"1h"}},
+	{"id":"H","type":"task","config":{"service":"svc","method":"h"}},
+	{"id":"E","type":"task","config":{"service":"svc","method":"e"}}
+],"edges":[
+	{"from":"A","to":"C"},
+	{"from":"A","to":"H","type":"error"},
+	{"from":"C","to":"B","condition":"state.amount > 100"},
+	{"from":"C","to":"W","condition":"state.amount <= 100"},
+	{"from":"B","to":"C","is_loopback":true,"condition":"state.retry == \"yes\""},
+	{"from":"B","to":"E","type":"always"}
+]}`
+
+// 定义视图：边类型、回环与条件作为标签输出，Mermaid 中的语法字符被转义；
+// DOT 按边类型区分线型与颜色；不支持的格式按参数错误返回。
+func TestRenderDefMermaidAndDOT(t *testing.T) {
+	ctx := context.Background()
+	a, _ := newTestApp(t)
+	if _, err := a.CreateDef(ctx, "test", "render_def", "render", renderTestDAG, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+
+	g, err := a.RenderDef(ctx, "test", "render_def", 0, "")
+	if err != nil {
+		t.Fatalf("render mermaid: %v", err)
+	}
+	if g.Format != RenderFormatMermaid || g.NodeStatus != nil {
+		t.Fatalf("format=%s node_status=%v", g.Format, g.NodeStatus)
+	}
+	for _, want := range []string{
+		"flowchart TD",
+		`n0["A<br/>svc.a"]`,
+		`n1{"C<br/>condition"}`,
+		`n3(["W<br/>timer"])`,
+		`n0 -->|"error"| n4`,
+		`n1 -->|"state.amount #gt; 100"| n2`,
+		`n2 -.->|"loopback: state.retry == #quot;yes#quot;"| n1`,
+		`n2 ==>|"always"| n5`,
+		"linkStyle 1 stroke:",
+	} {
+		if !strings.Contains(g.Content, want) {
+			t.Fatalf("mermaid 缺少 %q:\n%s", want, g.Content)
+		}
+	}
+
+	g, err = a.RenderDef(ctx, "test", "render_def", 1, "DOT")
+	if err != nil {
+		t.Fatalf("render dot: %v", err)
+	}
+	for _, want := range []string{
+		"digraph workflow {",
+		`"C" [label="C\ncondition", shape=diamond];`,
+		`"A" -> "H" [label="error", color="#d9534f"`,
+		`"B" -> "C" [label="loopback: state.retry == \"yes\"", style=dashed, constraint=false];`,
+	} {
+		if !strings.Contains(g.Content, want) {
+			t.Fatalf("dot 缺少 %q:\n%s", want, g.Content)
+		}
+	}
+
+	var e *errorc.Error
+	if _, err := a.RenderDef(ctx, "test", "render_def", 0, "svg"); !errors.As(err, &e) || e.ErrorCode != errorc.ErrorCodeValid {
+		t.Fatalf("不支持的格式应返回参数错误: %v", err)
+	}
+}
+
+// 实例视图：已完成、正在执行、等待中与失败的节点分别着色，状态随推进更新。
+func TestRenderInstanceOverlay(t *testing.T) {
+	ctx := context.Background()
+	a, _ := newTestApp(t)
+	if _, err := a.CreateDef(ctx, "test", "render_def", "render", renderTestDAG, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+
+	id, err := a.StartWorkflow(ctx, "render_def", map[string]interface{}{"amount": 10}, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := a.ReportNodeCompleted(ctx, id, "A", nil, "test"); err != nil {
+		t.Fatalf("report A: %v", err)
+	}
+	g, err := a.RenderInstance(ctx, id, RenderFormatMermaid)
+	if err != nil {
+		t.Fatalf("render instance: %v", err)
+	}
+	if g.NodeStatus["A"] != NodeStatusCompleted || g.NodeStatus["C"] != NodeStatusCompleted ||
+		g.NodeStatus["W"] != NodeStatusWaiting || g.NodeStatus["B"] != "" {
+		t.Fatalf("node_status = %v", g.NodeStatus)
+	}
+	if !strings.Contains(g.Content, "class n0,n1 completed") || !strings.Contains(g.Content, "class n3 waiting") {
+		t.Fatalf("mermaid 未按状态着色:\n%s", g.Content)
+	}
+
+	id, err = a.StartWorkflow(ctx, "render_def", map[string]interface{}{"amount": 500}, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := a.ReportNodeCompleted(ctx, id, "A", map[string]interface{}{"error_msg": "boom"}, "test"); err != nil {
+		t.Fatalf("report A: %v", err)
+	}
+	g, err = a.RenderInstance(ctx, id, RenderFormatDOT)
+	if err != nil {
+		t.Fatalf("render instance: %v", err)
+	}
+	if g.NodeStatus["A"] != NodeStatusFailed || g.NodeStatus["H"] != NodeStatusActive {
+		t.Fatalf("node_status = %v", g.NodeStatus)
+	}
+	if !strings.Contains(g.Content, `"A" [label="A\nsvc.a", fillcolor="#f8d7da", color="#d9534f", style="filled"];`) {
+		t.Fatalf("dot 未按状态着色:\n%s", g.Content)
+	}
+
+	if _, err := a.RenderInstance(ctx, 99999, ""); !errorc.IsNotFound(err) {
+		t.Fatalf("实例不存在应返回 NotFound: %v", err)
+	}
+}
@@ -0,0 +1,338 @@
+// 职责：实例保留策略——按 env 或定义编码配置终态实例的保留天数，到期后把实例与检查点
+// 移入归档表，或导出为 gzip 压缩的 JSON 上传到 OSS，随后物理删除实例及其附属记录。
+//
+// 边界：实例以 updated_at 作为进入终态的时间（终态后不再更新）。清理按批执行，每批一个事务：
+// 以 SKIP LOCKED 锁定一批实例、写归档或上传、再删除，多个 aio 进程同时执行时各取不同的批次。
+// 上传成功而事务提交失败时，下一轮会以新的批次重新导出这些实例，OSS 中可能出现重复记录但
+// 不会丢失。审批与迁移记录作为审计留存，不随实例清理。
+package app
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/xsxdot/aio/base"
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	"github.com/xsxdot/aio/system/workflow/internal/dao"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"gorm.io/gorm"
+)
+
+// 类型别名，供 api/client 等层使用
+type WorkflowRetentionPolicyModel = model.WorkflowRetentionPolicyModel
+
+const (
+	// retentionBatchSize 每个事务处理的实例数
+	retentionBatchSize = 200
+	// retentionMaxBatches 单个策略一次执行最多处理的批数，剩余的留给下一次执行
+	retentionMaxBatches = 50
+	// retentionObjectPrefix 导出文件在 OSS 中的目录前缀
+	retentionObjectPrefix = "workflow-archive"
+)
+
+// terminalInstanceStatuses 可被保留策略处置的实例状态，与 isTerminalStatus 一致
+var terminalInstanceStatuses = []model.WorkflowInstanceStatus{
+	model.InstanceStatusCompleted,
+	model.InstanceStatusFailed,
+	model.InstanceStatusCanceled,
+	model.InstanceStatusCompensated,
+}
+
+// ArchiveUploader 导出文件上传，由 module.go 以 base.OSS 装配
+type ArchiveUploader interface {
+	UploadFile(ctx context.Context, objectKey string, reader io.Reader) error
+}
+
+// RetentionPolicyInput 创建/更新保留策略的参数
+type RetentionPolicyInput struct {
+	Env        string
+	DefCode    string // 空表示 env 下所有没有单独策略的定义
+	RetainDays int
+	Action     string // archive/export，空表示 archive
+}
+
+// RetentionReport 单个策略一次执行（或试运行）的结果
+type RetentionReport struct {
+	PolicyID int64                 `json:"policy_id"`
+	Env      string                `json:"env"`
+	DefCode  string                `json:"def_code"`
+	Action   model.RetentionAction `json:"action"`
+	Before   time.Time             `json:"before"`
+	DryRun   bool                  `json:"dry_run"`
+	// Instances / Checkpoints 试运行时为待处理数，否则为已处理数
+	Instances   int64    `json:"instances"`
+	Checkpoints int64    `json:"checkpoints"`
+	Objects     []string `json:"objects,omitempty"`
+	Error       string   `json:"error,omitempty"`
+}
+
+// retentionExport 导出文件内容
+type retentionExport struct {
+	PolicyID   int64                      `json:"policy_id"`
+	ExportedAt time.Time                  `json:"exported_at"`
+	Instances  []*retentionExportInstance `json:"instances"`
+}
+
+type retentionExportInstance struct {
+	*model.WorkflowInstanceModel
+	Checkpoints []*model.WorkflowCheckpointModel `json:"checkpoints"`
+}
+
+// normalizeRetentionInput 补默认值并校验策略参数
+func (a *App) normalizeRetentionInput(ctx context.Context, in *RetentionPolicyInput) error {
+	if in.Env == "" {
+		in.Env = base.ENV
+	}
+	in.DefCode = strings.TrimSpace(in.DefCode)
+	if in.Action == "" {
+		in.Action = string(model.RetentionActionArchive)
+	}
+	if in.RetainDays <= 0 {
+		return a.err.New("retain_days 必须大于 0", nil).WithCode(errorc.ErrorCodeValid)
+	}
+	switch model.RetentionAction(in.Action) {
+	case model.RetentionActionArchive:
+	case model.RetentionActionExport:
+		if a.ArchiveUploader == nil {
+			return a.err.New("未配置 OSS，不能使用 export 方式", nil).WithCode(errorc.ErrorCodeValid)
+		}
+	default:
+		return a.err.New("action 只能是 archive/export", nil).WithCode(errorc.ErrorCodeValid)
+	}
+	if in.DefCode != "" {
+		if _, err := a.DefService.FindByCode(ctx, in.Env, in.DefCode); err != nil {
+			return a.err.New("保留策略引用的工作流定义不存在", err).WithCode(errorc.ErrorCodeValid)
+		}
+	}
+	return nil
+}
+
+// checkRetentionScope 同一 env + def_code 至多一条策略
+func (a *App) checkRetentionScope(ctx context.Context, env, defCode string, selfID int64) error {
+	existing, err := a.RetentionService.FindByScope(ctx, env, defCode)
+	if err != nil {
+		if errorc.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if existing.ID != selfID {
+		return a.err.New("该范围已存在保留策略", nil).WithCode(errorc.ErrorCodeValid)
+	}
+	return nil
+}
+
+// CreateRetentionPolicy 创建保留策略
+func (a *App) CreateRetentionPolicy(ctx context.Context, in *RetentionPolicyInput) (*model.WorkflowRetentionPolicyModel, error) {
+	if err := a.normalizeRetentionInput(ctx, in); err != nil {
+		return nil, err
+	}
+	if err := a.checkRetentionScope(ctx, in.Env, in.DefCode, 0); err != nil {
+		return nil, err
+	}
+	policy := &model.WorkflowRetentionPolicyModel{
+		Env:        in.Env,
+		DefCode:    in.DefCode,
+		RetainDays: in.RetainDays,
+		Action:     model.RetentionAction(in.Action),
+	}
+	if err := a.RetentionService.Create(ctx, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// UpdateRetentionPolicy 整体更新保留策略
+func (a *App) UpdateRetentionPolicy(ctx context.Context, id int64, in *RetentionPolicyInput) (*model.WorkflowRetentionPolicyModel, error) {
+	if _, err := a.RetentionService.FindById(ctx, id); err != nil {
+		return nil, a.err.New("获取保留策略失败", err)
+	}
+	if err := a.normalizeRetentionInput(ctx, in); err != nil {
+		return nil, err
+	}
+	if err := a.checkRetentionScope(ctx, in.Env, in.DefCode, id); err != nil {
+		return nil, err
+	}
+	if err := a.RetentionService.UpdateFields(ctx, id, map[string]interface{}{
+		"env":         in.Env,
+		"def_code":    in.DefCode,
+		"retain_days": in.RetainDays,
+		"action":      in.Action,
+	}); err != nil {
+		return nil, err
+	}
+	return a.RetentionService.FindById(ctx, id)
+}
+
+// DeleteRetentionPolicy 删除保留策略，已归档或导出的数据不受影响
+func (a *App) DeleteRetentionPolicy(ctx context.Context, id int64) error {
+	if _, err := a.RetentionService.FindById(ctx, id); err != nil {
+		return a.err.New("获取保留策略失败", err)
+	}
+	return a.RetentionService.HardDeleteById(ctx, id)
+}
+
+// GetRetentionPolicy 获取保留策略
+func (a *App) GetRetentionPolicy(ctx context.Context, id int64) (*model.WorkflowRetentionPolicyModel, error) {
+	return a.RetentionService.FindById(ctx, id)
+}
+
+// ListRetentionPolicies 列出 env 下的保留策略
+func (a *App) ListRetentionPolicies(ctx context.Context, env string) ([]*model.WorkflowRetentionPolicyModel, error) {
+	if env == "" {
+		env = base.ENV
+	}
+	return a.RetentionService.ListPolicies(ctx, env)
+}
+
+// RunRetention 执行保留策略。policyID 为 0 时执行所有环境的全部策略（每日任务），
+// dryRun 时只统计待处理的实例与检查点数，不做任何修改。
+//
+// 单个策略失败只记入其报告的 Error 并继续执行其余策略。
+func (a *App) RunRetention(ctx context.Context, now time.Time, policyID int64, dryRun bool) ([]*RetentionReport, error) {
+	if a.RetentionService == nil {
+		return nil, a.err.New("保留策略服务未启用", nil)
+	}
+	var policies []*model.WorkflowRetentionPolicyModel
+	if policyID > 0 {
+		policy, err := a.RetentionService.FindById(ctx, policyID)
+		if err != nil {
+			return nil, a.err.New("获取保留策略失败", err)
+		}
+		policies = append(policies, policy)
+	} else {
+		var err error
+		if policies, err = a.RetentionService.ListPolicies(ctx, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	reports := make([]*RetentionReport, 0, len(policies))
+	for _, policy := range policies {
+		report := &RetentionReport{
+			PolicyID: policy.ID,
+			Env:      policy.Env,
+			DefCode:  policy.DefCode,
+			Action:   policy.Action,
+			Before:   now.AddDate(0, 0, -policy.RetainDays),
+			DryRun:   dryRun,
+		}
+		if err := a.runRetentionPolicy(ctx, policy, report, now); err != nil {
+			report.Error = err.Error()
+			a.log.WithErr(err).WithField("policy_id", policy.ID).Warn("执行工作流保留策略失败")
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// runRetentionPolicy 按批处理单个策略下保留期满的实例，结果累加到 report
+func (a *App) runRetentionPolicy(ctx context.Context, policy *model.WorkflowRetentionPolicyModel, report *RetentionReport, now time.Time) error {
+	f, err := a.RetentionService.ExpiredFilter(ctx, policy, terminalInstanceStatuses, report.Before)
+	if err != nil || f == nil {
+		return err
+	}
+	if report.DryRun {
+		report.Instances, report.Checkpoints, err = a.RetentionService.CountExpired(ctx, f)
+		return err
+	}
+
+	for i := 0; i < retentionMaxBatches; i++ {
+		n, err := a.retainBatch(ctx, policy, f, report, now)
+		if err != nil {
+			return err
+		}
+		if n < retentionBatchSize {
+			break
+		}
+	}
+	return a.RetentionService.UpdateFields(ctx, policy.ID, map[string]interface{}{"last_run_at": now})
+}
+
+// retainBatch 在一个事务内锁定一批实例，归档或导出后删除，返回本批实例数
+func (a *App) retainBatch(ctx context.Context, policy *model.WorkflowRetentionPolicyModel, f *dao.ExpiredInstanceFilter, report *RetentionReport, now time.Time) (int, error) {
+	var (
+		instances   []*model.WorkflowInstanceModel
+		checkpoints []*model.WorkflowCheckpointModel
+		objectKey   string
+	)
+	err := mvc.ExtractDB(ctx, base.DB).Transaction(func(tx *gorm.DB) error {
+		txCtx := mvc.WithTxToContext(ctx, tx)
+		var err error
+		if instances, checkpoints, err = a.RetentionService.LockExpiredBatch(txCtx, f, retentionBatchSize); err != nil || len(instances) == 0 {
+			return err
+		}
+		switch policy.Action {
+		case model.RetentionActionExport:
+			if objectKey, err = a.exportRetainedInstances(txCtx, policy, instances, checkpoints, now); err != nil {
+				return err
+			}
+		default:
+			if err := a.RetentionService.Archive(txCtx, instances, checkpoints, now); err != nil {
+				return err
+			}
+		}
+		ids := make([]int64, 0, len(instances))
+		for _, inst := range instances {
+			ids = append(ids, inst.ID)
+		}
+		return a.RetentionService.Purge(txCtx, ids)
+	})
+	if err != nil {
+		return 0, err
+	}
+	report.Instances += int64(len(instances))
+	report.Checkpoints += int64(len(checkpoints))
+	if objectKey != "" {
+		report.Objects = append(report.Objects, objectKey)
+	}
+	return len(instances), nil
+}
+
+// exportRetainedInstances 把一批实例及其检查点压缩为 JSON 上传到 OSS，返回对象键。
+//
+// 对象键由策略范围、日期与本批首尾实例 ID 确定，重试同一批次会覆盖而不是新增文件
+func (a *App) exportRetainedInstances(ctx context.Context, policy *model.WorkflowRetentionPolicyModel, instances []*model.WorkflowInstanceModel, checkpoints []*model.WorkflowCheckpointModel, now time.Time) (string, error) {
+	if a.ArchiveUploader == nil {
+		return "", a.err.New("未配置 OSS，无法导出实例", nil)
+	}
+	byInstance := make(map[int64][]*model.WorkflowCheckpointModel, len(instances))
+	for _, cp := range checkpoints {
+		byInstance[cp.InstanceID] = append(byInstance[cp.InstanceID], cp)
+	}
+	payload := retentionExport{PolicyID: policy.ID, ExportedAt: now, Instances: make([]*retentionExportInstance, 0, len(instances))}
+	for _, inst := range instances {
+		cps := byInstance[inst.ID]
+		if cps == nil {
+			cps = []*model.WorkflowCheckpointModel{}
+		}
+		payload.Instances = append(payload.Instances, &retentionExportInstance{WorkflowInstanceModel: inst, Checkpoints: cps})
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(payload); err != nil {
+		return "", a.err.New("序列化导出数据失败", err)
+	}
+	if err := zw.Close(); err != nil {
+		return "", a.err.New("压缩导出数据失败", err)
+	}
+
+	scope := policy.DefCode
+	if scope == "" {
+		scope = "_all"
+	}
+	objectKey := fmt.Sprintf("%s/%s/%s/%s/%d-%d.json.gz", retentionObjectPrefix, policy.Env, scope, now.Format("20060102"),
+		instances[0].ID, instances[len(instances)-1].ID)
+	if err := a.ArchiveUploader.UploadFile(ctx, objectKey, &buf); err != nil {
+		return "", a.err.New("上传导出文件失败", err)
+	}
+	return objectKey, nil
+}
@@ -0,0 +1,179 @@
+package app
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"gorm.io/gorm"
+)
+
+type memoryUploader struct {
+	objects map[string][]byte
+}
+
+func (u *memoryUploader) UploadFile(_ context.Context, objectKey string, reader io.Reader) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	u.objects[objectKey] = data
+	return nil
+}
+
+func newRetentionTestApp(t *testing.T) (*App, *gorm.DB) {
+	t.Helper()
+	a, db := newTestApp(t)
+	ctx := context.Background()
+	for _, code := range []string{"order_flow", "audit_flow"} {
+		if _, err := a.CreateDef(ctx, "test", code, code, `{"nodes":[{"id":"A","type":"task","config":{"service":"svc","method":"a"}}]}`, 1); err != nil {
+			t.Fatalf("create def: %v", err)
+		}
+	}
+	return a, db
+}
+
+// startAgedInstance 启动实例并补一条检查点；canceled 时取消实例并把 updated_at 回拨 daysAgo 天
+func startAgedInstance(t *testing.T, a *App, db *gorm.DB, code string, canceled bool, daysAgo int) int64 {
+	t.Helper()
+	ctx := context.Background()
+	id, err := a.StartWorkflow(ctx, code, map[string]interface{}{"n": 1}, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := db.Create(&model.WorkflowCheckpointModel{InstanceID: id, NodeID: "A", NodeOutput: `{}`, StateAfter: `{"n":1}`}).Error; err != nil {
+		t.Fatalf("create checkpoint: %v", err)
+	}
+	if canceled {
+		if err := a.CancelInstance(ctx, id); err != nil {
+			t.Fatalf("cancel: %v", err)
+		}
+		if err := db.Exec("UPDATE aio_workflow_instance SET updated_at = ? WHERE id = ?", time.Now().AddDate(0, 0, -daysAgo), id).Error; err != nil {
+			t.Fatalf("age instance: %v", err)
+		}
+	}
+	return id
+}
+
+func countRows(t *testing.T, db *gorm.DB, table, where string, args ...interface{}) int64 {
+	t.Helper()
+	var n int64
+	if err := db.Table(table).Where(where, args...).Count(&n).Error; err != nil {
+		t.Fatalf("count %s: %v", table, err)
+	}
+	return n
+}
+
+// 试运行只统计不修改；正式执行把保留期满的终态实例连同检查点移入归档表并清理附属记录，
+// 未到期的终态实例与仍在运行的实例不受影响
+func TestRetentionArchivesExpiredInstances(t *testing.T) {
+	ctx := context.Background()
+	a, db := newRetentionTestApp(t)
+	expired := startAgedInstance(t, a, db, "order_flow", true, 40)
+	recent := startAgedInstance(t, a, db, "order_flow", true, 5)
+	running := startAgedInstance(t, a, db, "order_flow", false, 0)
+
+	policy, err := a.CreateRetentionPolicy(ctx, &RetentionPolicyInput{Env: "test", DefCode: "order_flow", RetainDays: 30})
+	if err != nil || policy.Action != model.RetentionActionArchive {
+		t.Fatalf("create policy: %+v err=%v", policy, err)
+	}
+
+	reports, err := a.RunRetention(ctx, time.Now(), policy.ID, true)
+	if err != nil || len(reports) != 1 || reports[0].Instances != 1 || reports[0].Checkpoints != 1 || reports[0].Error != "" {
+		t.Fatalf("dry run: reports=%+v err=%v", reports, err)
+	}
+	if n := countRows(t, db, "aio_workflow_instance", "id = ?", expired); n != 1 {
+		t.Fatal("试运行不应删除实例")
+	}
+
+	reports, err = a.RunRetention(ctx, time.Now(), 0, false)
+	if err != nil || len(reports) != 1 || reports[0].Instances != 1 || reports[0].Error != "" {
+		t.Fatalf("run: reports=%+v err=%v", reports, err)
+	}
+	if n := countRows(t, db, "aio_workflow_instance", "id = ?", expired); n != 0 {
+		t.Fatal("保留期满的实例未被清理")
+	}
+	for _, table := range []string{"aio_workflow_checkpoint", "aio_workflow_instance_event"} {
+		if n := countRows(t, db, table, "instance_id = ?", expired); n != 0 {
+			t.Fatalf("%s 仍有 %d 行属于已清理的实例", table, n)
+		}
+	}
+	var archived model.WorkflowInstanceArchiveModel
+	if err := db.Where("id = ?", expired).Take(&archived).Error; err != nil || archived.Status != model.InstanceStatusCanceled {
+		t.Fatalf("archived instance: %+v err=%v", archived, err)
+	}
+	if n := countRows(t, db, "aio_workflow_checkpoint_archive", "instance_id = ?", expired); n != 1 {
+		t.Fatalf("archived checkpoints = %d, want 1", n)
+	}
+	for _, id := range []int64{recent, running} {
+		if n := countRows(t, db, "aio_workflow_instance", "id = ?", id); n != 1 {
+			t.Fatalf("实例 %d 不应被清理", id)
+		}
+	}
+	if got, _ := a.GetRetentionPolicy(ctx, policy.ID); got.LastRunAt == nil {
+		t.Fatal("执行后应记录 last_run_at")
+	}
+}
+
+// 整个环境的策略跳过单独配置了策略的定义；export 方式上传可还原的 gzip JSON 后清理，
+// 未配置 OSS 时不能创建 export 策略，同一范围不能重复配置
+func TestRetentionExportsAndScopes(t *testing.T) {
+	ctx := context.Background()
+	a, db := newRetentionTestApp(t)
+
+	_, err := a.CreateRetentionPolicy(ctx, &RetentionPolicyInput{Env: "test", RetainDays: 7, Action: "export"})
+	var e *errorc.Error
+	if !errors.As(err, &e) || e.ErrorCode != errorc.ErrorCodeValid {
+		t.Fatalf("未配置 OSS 时 err = %v, want ErrorCodeValid", err)
+	}
+	uploader := &memoryUploader{objects: map[string][]byte{}}
+	a.ArchiveUploader = uploader
+
+	orderID := startAgedInstance(t, a, db, "order_flow", true, 40)
+	auditID := startAgedInstance(t, a, db, "audit_flow", true, 40)
+	if _, err := a.CreateRetentionPolicy(ctx, &RetentionPolicyInput{Env: "test", RetainDays: 7, Action: "export"}); err != nil {
+		t.Fatalf("create env policy: %v", err)
+	}
+	if _, err := a.CreateRetentionPolicy(ctx, &RetentionPolicyInput{Env: "test", DefCode: "audit_flow", RetainDays: 365}); err != nil {
+		t.Fatalf("create def policy: %v", err)
+	}
+	if _, err := a.CreateRetentionPolicy(ctx, &RetentionPolicyInput{Env: "test", DefCode: "audit_flow", RetainDays: 30}); !errors.As(err, &e) || e.ErrorCode != errorc.ErrorCodeValid {
+		t.Fatalf("重复范围 err = %v, want ErrorCodeValid", err)
+	}
+
+	if _, err := a.RunRetention(ctx, time.Now(), 0, false); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if n := countRows(t, db, "aio_workflow_instance", "id = ?", orderID); n != 0 {
+		t.Fatal("环境策略应清理 order_flow 的实例")
+	}
+	if n := countRows(t, db, "aio_workflow_instance", "id = ?", auditID); n != 1 {
+		t.Fatal("audit_flow 有单独策略且未到期，不应被环境策略清理")
+	}
+	if n := countRows(t, db, "aio_workflow_instance_archive", "1 = 1"); n != 0 {
+		t.Fatalf("export 方式不应写归档表: %d", n)
+	}
+	if len(uploader.objects) != 1 {
+		t.Fatalf("uploaded objects = %d, want 1", len(uploader.objects))
+	}
+	for _, data := range uploader.objects {
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("gzip: %v", err)
+		}
+		var payload retentionExport
+		if err := json.NewDecoder(zr).Decode(&payload); err != nil {
+			t.Fatalf("decode export: %v", err)
+		}
+		if len(payload.Instances) != 1 || payload.Instances[0].ID != orderID || len(payload.Instances[0].Checkpoints) != 1 {
+			t.Fatalf("export payload = %+v", payload)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+// 职责：边选择——节点结束后决定沿哪些出边继续：成功时走非 error 边，并按节点输出筛选审批
+// 决定边、loop 节点的本次走向与 wait_signal 的 timeout 边；失败时走 error/always 边。边条件
+// 按业务数据与当前循环上下文（loop 变量）求值。成功、失败、Map 聚合、回环清理与实例分叉
+// 共用这一套规则，新增筛选条件只改这里。
+//
+// 边界：selectOutEdges 只求值不写库；clearLoopbackCheckpoints 须紧跟当前节点 checkpoint 的写入
+// 调用；scheduleEdges 在推进事务内按 checkpoint 去重、汇聚判定与 quorum 取消兄弟分支决定真正
+// 派发的下一跳，不触发节点，派发由调用方完成。判定只经 checkpointLog 读取节点完成记录，
+// 离线模拟以内存中的记录复用同一套判定。
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	"gorm.io/gorm"
+)
+
+// nodeOutcome 节点本次结束的结果，决定可走的出边
+type nodeOutcome struct {
+	// Node 结束的节点；迁移后已不在定义中时为 nil，只按边类型与条件筛选
+	Node   *model.Node
+	Output map[string]interface{}
+	Failed bool
+	// LoopRoute loop 节点本次的走向（loopRouteBody / loopRouteExit），其余节点为空
+	LoopRoute string
+}
+
+// selectOutEdges 按定义顺序返回节点结束后可以走、且条件成立的出边；条件求值失败的边视为不成立
+func (a *App) selectOutEdges(dag *model.DAG, nodeID string, outcome nodeOutcome, data workflowStateData, sys workflowStateSys) []model.Edge {
+	vars := loopExprVars(sys)
+	var selected []model.Edge
+	for _, edge := range dag.GetOutgoingEdges(nodeID) {
+		if outcome.Failed {
+			if edge.Type != model.EdgeTypeError && edge.Type != model.EdgeTypeAlways {
+				continue
+			}
+		} else if edge.Type == model.EdgeTypeError || !decisionEdgeMatches(edge, outcome.Output) ||
+			!loopEdgeAllowed(outcome.Node, edge, outcome.LoopRoute) || !waitSignalEdgeAllowed(outcome.Node, edge, outcome.Output) {
+			continue
+		}
+		pass, err := a.evaluateCondition(edge.Condition, data, vars)
+		if err != nil {
+			a.log.WithErr(err).Errorf("评估边 %s->%s 条件失败", edge.From, edge.To)
+			continue
+		}
+		if pass {
+			selected = append(selected, edge)
+		}
+	}
+	return selected
+}
+
+// checkpointLog 路由判定读取的节点完成记录：推进时读本实例的 checkpoint 表，离线模拟读内存
+type checkpointLog interface {
+	// count 节点已落的 checkpoint 数
+	count(nodeID string) int64
+	// latest 节点最近一次的 checkpoint，没有时返回 nil
+	latest(nodeID string) *model.WorkflowCheckpointModel
+}
+
+// txCheckpoints 在推进事务内读取实例的 checkpoint
+type txCheckpoints struct {
+	tx         *gorm.DB
+	instanceID int64
+}
+
+func (c txCheckpoints) count(nodeID string) int64 {
+	var n int64
+	c.tx.Model(&model.WorkflowCheckpointModel{}).
+		Where("instance_id = ? AND node_id = ?", c.instanceID, nodeID).
+		Count(&n)
+	return n
+}
+
+func (c txCheckpoints) latest(nodeID string) *model.WorkflowCheckpointModel {
+	var cp model.WorkflowCheckpointModel
+	err := c.tx.Where("instance_id = ? AND node_id = ?", c.instanceID, nodeID).Order("id DESC").Limit(1).Find(&cp).Error
+	if err != nil || cp.ID == 0 {
+		return nil
+	}
+	return &cp
+}
+
+// scheduleEdges 把选中出边的目标加入下一跳：已在活跃列表中的不重复加入，非回环边的目标已执行过
+// 时不再调度，汇聚节点须满足 join 条件；quorum 达成且配置了 cancel_siblings 时一并取消其余分支
+func (a *App) scheduleEdges(ctx context.Context, tx *gorm.DB, instanceID int64, dag *model.DAG, env string, edges []model.Edge, activeNodes, nextNodeIDs *[]string) {
+	a.planEdges(txCheckpoints{tx: tx, instanceID: instanceID}, dag, edges, activeNodes, nextNodeIDs, func(joinNodeID string, siblings []string) {
+		a.cancelJoinSiblings(ctx, instanceID, joinNodeID, siblings, env)
+	})
+}
+
+// planEdges 为 scheduleEdges 的判定部分，只改动活跃列表与下一跳；因汇聚满足而移出的兄弟分支
+// 交给 cancel 撤销其在途任务，cancel 可为 nil
+func (a *App) planEdges(cps checkpointLog, dag *model.DAG, edges []model.Edge, activeNodes, nextNodeIDs *[]string, cancel func(joinNodeID string, siblings []string)) {
+	for _, edge := range edges {
+		if !edgeTargetEligibleForScheduling(edge, cps.count(edge.To), containsString(*activeNodes, edge.To)) {
+			continue
+		}
+		ready, join := a.joinReady(cps, edge, dag)
+		if !ready {
+			continue
+		}
+		*nextNodeIDs = append(*nextNodeIDs, edge.To)
+		*activeNodes = append(*activeNodes, edge.To)
+		if join.Quorum > 0 && join.CancelSiblings {
+			if siblings := dropJoinSiblings(edge.To, dag, activeNodes, nextNodeIDs); len(siblings) > 0 && cancel != nil {
+				cancel(edge.To, siblings)
+			}
+		}
+	}
+}
+
+// clearLoopbackCheckpoints 选中的边含回环边时，删除从回环目标到当前节点之间的旧 checkpoint（含当前
+// 节点刚写入的这一条），使重跑节点完成后的 forward 边可以重新调度下游；保留当前节点的 checkpoint
+// 会让它在下一轮因「已执行过」而不再被调度，回环只能生效一次
+func (a *App) clearLoopbackCheckpoints(ctx context.Context, tx *gorm.DB, instanceID int64, edges []model.Edge) error {
+	if !hasLoopbackEdge(edges) {
+		return nil
+	}
+	checkpoints, err := a.CheckpointService.ListByInstanceIDOrderByCreatedAscWithTx(ctx, tx, instanceID)
+	if err != nil {
+		return fmt.Errorf("获取 checkpoint 列表失败: %w", err)
+	}
+	current := len(checkpoints) - 1 // 刚创建的当前节点 checkpoint
+	earliest, ok := loopbackClearFrom(checkpoints, edges)
+	if !ok {
+		return nil
+	}
+	if err := a.CheckpointService.DeleteFromIndexRangeWithTx(ctx, tx, instanceID, earliest, current+1); err != nil {
+		return fmt.Errorf("清理 loopback 下游 checkpoint 失败: %w", err)
+	}
+	a.log.WithFields(map[string]interface{}{
+		"instance_id": instanceID,
+		"from_index":  earliest,
+		"to_index":    current + 1,
+	}).Info("Loopback 边触发，已清理下游 checkpoint")
+	return nil
+}
+
+func hasLoopbackEdge(edges []model.Edge) bool {
+	for _, edge := range edges {
+		if edge.IsLoopback {
+			return true
+		}
+	}
+	return false
+}
+
+// loopbackClearFrom 在按时间升序的 checkpoint 中找出回环须清理的起点：各回环目标最近一次
+// checkpoint 中最早的一条，清理范围到末尾（当前节点刚写入的一条）为止；无需清理时返回 false
+func loopbackClearFrom(checkpoints []*model.WorkflowCheckpointModel, edges []model.Edge) (int, bool) {
+	current := len(checkpoints) - 1
+	earliest := current
+	for _, edge := range edges {
+		if !edge.IsLoopback {
+			continue
+		}
+		for i := len(checkpoints) - 1; i >= 0; i-- {
+			if checkpoints[i].NodeID == edge.To {
+				if i < earliest {
+					earliest = i
+				}
+				break
+			}
+		}
+	}
+	return earliest, earliest < current
+}
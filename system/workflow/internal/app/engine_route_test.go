@@ -0,0 +1,40 @@
+package app
+
+import (
+	"context"
+	"testing"
+)
+
+// Map 节点聚合完成后的出边与其他节点共用同一套边选择：循环体内的 Map 节点，其出边条件
+// 同样能读取 loop 变量，回环边同样清理循环体 checkpoint，下一轮的 Map 才能再次派发。
+func TestMapAggregationRoutesWithLoopVars(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+	dagJSON := `{"nodes":[
+		{"id":"S","type":"transform","config":{"assign":{"started":"true"}}},
+		{"id":"L","type":"loop","config":{"body":"M","max_iterations":2,"on_exhausted":"continue"}},
+		{"id":"M","type":"map","config":{"items_path":"items","iterator":{"service":"svc","method":"each"}}},
+		{"id":"X","type":"task","config":{"service":"svc","method":"x"}},
+		{"id":"N","type":"task","config":{"service":"svc","method":"n"}}
+	],"edges":[
+		{"from":"S","to":"L"},{"from":"L","to":"M"},{"from":"L","to":"N"},
+		{"from":"M","to":"X","condition":"loop.index == 0"},
+		{"from":"M","to":"L","is_loopback":true,"condition":"loop.index == 0"}
+	]}`
+	if _, err := a.CreateDef(ctx, "test", "map_in_loop", "map_in_loop", dagJSON, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	id, err := a.StartWorkflow(ctx, "map_in_loop", map[string]interface{}{"items": []interface{}{1}}, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := a.reportNodeCompleted(ctx, 0, id, "M", map[string]interface{}{"v": 1}, "test", 0); err != nil {
+		t.Fatalf("report sub 0: %v", err)
+	}
+	if countNodeJobs(t, db, id, "X", "") != 1 {
+		t.Fatal("第 0 轮 Map 完成后未按 loop.index 走到 X")
+	}
+	if n := countMapSubJobs(t, db, id, "M", ""); n != 2 {
+		t.Fatalf("回环边未按 loop.index 进入第 1 轮，Map 子任务共 %d 个", n)
+	}
+}
@@ -0,0 +1,450 @@
+// 职责：工作流定时调度——按 cron 表达式周期性拉起某个定义的新实例，并提供调度的
+// 增删改查、暂停/恢复、立即运行与运行历史。
+//
+// 边界：触发由每个 aio 进程内的轮询任务驱动（见 module.go StartScheduleTicker），
+// 多实例部署时靠调度行锁 + 锁内复核 next_fire_at 保证同一触发点只生效一次，不依赖
+// Redis 选主。停机期间错过的多个触发点合并为一次补触发，不逐个追补。
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/xsxdot/aio/base"
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"gorm.io/gorm"
+)
+
+// 类型别名，供 api/client 等层使用
+type WorkflowScheduleModel = model.WorkflowScheduleModel
+type WorkflowScheduleRunItem = model.WorkflowScheduleRunItem
+
+// scheduleBatchSize 单次轮询最多处理的调度数，剩余的留给下一轮
+const scheduleBatchSize = 100
+
+// scheduleCronParser 与 gokit/scheduler 的 cron 任务一致：标准 5 段表达式，支持 @every/@daily 等描述符
+var scheduleCronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// ScheduleInput 创建/更新调度的参数
+type ScheduleInput struct {
+	Env                 string
+	Name                string
+	DefCode             string
+	DefVersion          int32 // 0 表示每次触发时取最新版本
+	CronExpr            string
+	Timezone            string // IANA 时区名，空表示服务器本地时区
+	InitialDataTemplate string // text/template，渲染结果须为 JSON 对象；空表示空对象
+	OverlapPolicy       string // skip/queue/allow，空表示 skip
+}
+
+// scheduleTemplateVars 初始数据模板可引用的变量
+type scheduleTemplateVars struct {
+	ScheduleID   int64
+	ScheduleName string
+	ScheduledAt  time.Time // 计划触发时间，位于调度时区
+	Trigger      string    // cron / manual
+}
+
+// loadScheduleLocation 解析调度时区，空表示服务器本地时区
+func loadScheduleLocation(tz string) (*time.Location, error) {
+	if tz == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(tz)
+}
+
+// nextScheduleFireTime 计算 after 之后的下一个触发点，cron 表达式在调度时区下求值
+func nextScheduleFireTime(cronExpr, tz string, after time.Time) (time.Time, error) {
+	loc, err := loadScheduleLocation(tz)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("时区无效: %w", err)
+	}
+	sched, err := scheduleCronParser.Parse(cronExpr)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("cron 表达式无效: %w", err)
+	}
+	next := sched.Next(after.In(loc))
+	if next.IsZero() {
+		return time.Time{}, fmt.Errorf("cron 表达式 %q 没有后续触发点", cronExpr)
+	}
+	return next.Local(), nil
+}
+
+// renderScheduleInitialData 渲染初始数据模板并解析为 JSON 对象
+func renderScheduleInitialData(tmpl string, vars scheduleTemplateVars) (map[string]interface{}, error) {
+	data := make(map[string]interface{})
+	if tmpl == "" {
+		return data, nil
+	}
+	t, err := template.New("initial_data").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("初始数据模板解析失败: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, vars); err != nil {
+		return nil, fmt.Errorf("初始数据模板渲染失败: %w", err)
+	}
+	if err := json.Unmarshal(buf.Bytes(), &data); err != nil {
+		return nil, fmt.Errorf("初始数据模板渲染结果不是 JSON 对象: %w", err)
+	}
+	return data, nil
+}
+
+// normalizeScheduleInput 补默认值并校验调度参数，返回首个触发点
+func (a *App) normalizeScheduleInput(ctx context.Context, in *ScheduleInput, now time.Time) (time.Time, error) {
+	if in.Env == "" {
+		in.Env = base.ENV
+	}
+	if in.OverlapPolicy == "" {
+		in.OverlapPolicy = string(model.OverlapSkip)
+	}
+	if in.Name == "" || in.DefCode == "" || in.CronExpr == "" {
+		return time.Time{}, a.err.New("name、def_code、cron_expr 不能为空", nil).WithCode(errorc.ErrorCodeValid)
+	}
+	switch model.ScheduleOverlapPolicy(in.OverlapPolicy) {
+	case model.OverlapSkip, model.OverlapQueue, model.OverlapAllow:
+	default:
+		return time.Time{}, a.err.New("overlap_policy 只能是 skip/queue/allow", nil).WithCode(errorc.ErrorCodeValid)
+	}
+	next, err := nextScheduleFireTime(in.CronExpr, in.Timezone, now)
+	if err != nil {
+		return time.Time{}, a.err.New(err.Error(), err).WithCode(errorc.ErrorCodeValid)
+	}
+	// 用示例变量试渲染一次，让模板错误在保存时暴露，而不是在凌晨的某次触发时
+	if _, err := renderScheduleInitialData(in.InitialDataTemplate, scheduleTemplateVars{ScheduledAt: next, Trigger: string(model.ScheduleTriggerCron)}); err != nil {
+		return time.Time{}, a.err.New(err.Error(), err).WithCode(errorc.ErrorCodeValid)
+	}
+	if _, err := a.GetDefByCodeAndVersion(ctx, in.Env, in.DefCode, in.DefVersion); err != nil {
+		return time.Time{}, a.err.New("调度引用的工作流定义不存在", err).WithCode(errorc.ErrorCodeValid)
+	}
+	return next, nil
+}
+
+// CreateSchedule 创建调度，创建后即处于启用状态
+func (a *App) CreateSchedule(ctx context.Context, in *ScheduleInput) (*model.WorkflowScheduleModel, error) {
+	next, err := a.normalizeScheduleInput(ctx, in, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	sched := &model.WorkflowScheduleModel{
+		Env:                 in.Env,
+		Name:                in.Name,
+		DefCode:             in.DefCode,
+		DefVersion:          in.DefVersion,
+		CronExpr:            in.CronExpr,
+		Timezone:            in.Timezone,
+		InitialDataTemplate: in.InitialDataTemplate,
+		OverlapPolicy:       model.ScheduleOverlapPolicy(in.OverlapPolicy),
+		Status:              model.ScheduleStatusActive,
+		NextFireAt:          &next,
+	}
+	if err := a.ScheduleService.Create(ctx, sched); err != nil {
+		return nil, err
+	}
+	return sched, nil
+}
+
+// UpdateSchedule 整体更新调度配置；启用中的调度按新表达式从当前时间重新计算下次触发点
+func (a *App) UpdateSchedule(ctx context.Context, id int64, in *ScheduleInput) (*model.WorkflowScheduleModel, error) {
+	sched, err := a.ScheduleService.FindById(ctx, id)
+	if err != nil {
+		return nil, a.err.New("获取调度失败", err)
+	}
+	next, err := a.normalizeScheduleInput(ctx, in, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{
+		"env":                   in.Env,
+		"name":                  in.Name,
+		"def_code":              in.DefCode,
+		"def_version":           in.DefVersion,
+		"cron_expr":             in.CronExpr,
+		"timezone":              in.Timezone,
+		"initial_data_template": in.InitialDataTemplate,
+		"overlap_policy":        in.OverlapPolicy,
+	}
+	if sched.Status == model.ScheduleStatusActive {
+		fields["next_fire_at"] = next
+	}
+	if err := a.ScheduleService.UpdateFields(ctx, id, fields); err != nil {
+		return nil, err
+	}
+	return a.ScheduleService.FindById(ctx, id)
+}
+
+// DeleteSchedule 删除调度，仍在排队的运行记录一并标记为跳过；已拉起的实例不受影响
+func (a *App) DeleteSchedule(ctx context.Context, id int64) error {
+	return mvc.ExtractDB(ctx, base.DB).Transaction(func(tx *gorm.DB) error {
+		txCtx := mvc.WithTxToContext(ctx, tx)
+		if _, err := a.ScheduleService.FindByIdForUpdate(ctx, tx, id); err != nil {
+			return a.err.New("获取调度失败", err)
+		}
+		if err := a.ScheduleRunService.SkipQueued(txCtx, id, "调度已删除"); err != nil {
+			return err
+		}
+		return a.ScheduleService.DeleteById(txCtx, id)
+	})
+}
+
+// GetSchedule 获取调度
+func (a *App) GetSchedule(ctx context.Context, id int64) (*model.WorkflowScheduleModel, error) {
+	return a.ScheduleService.FindById(ctx, id)
+}
+
+// ListSchedules 分页列出调度，defCode 为空时不限定义
+func (a *App) ListSchedules(ctx context.Context, env, defCode string, pageNum, pageSize int32) ([]*model.WorkflowScheduleModel, int64, error) {
+	if env == "" {
+		env = base.ENV
+	}
+	return a.ScheduleService.ListSchedules(ctx, env, defCode, pageNum, pageSize)
+}
+
+// ListScheduleRuns 分页列出调度的运行历史（新的在前）
+func (a *App) ListScheduleRuns(ctx context.Context, scheduleID int64, pageNum, pageSize int32) ([]*model.WorkflowScheduleRunItem, int64, error) {
+	return a.ScheduleRunService.ListRuns(ctx, scheduleID, pageNum, pageSize)
+}
+
+// PauseSchedule 暂停调度：不再产生新的触发，排队中的运行也暂不出队
+func (a *App) PauseSchedule(ctx context.Context, id int64) error {
+	if _, err := a.ScheduleService.FindById(ctx, id); err != nil {
+		return a.err.New("获取调度失败", err)
+	}
+	return a.ScheduleService.UpdateFields(ctx, id, map[string]interface{}{
+		"status":       model.ScheduleStatusPaused,
+		"next_fire_at": nil,
+	})
+}
+
+// ResumeSchedule 恢复调度，从当前时间起计算下次触发点；暂停期间错过的触发点不补
+func (a *App) ResumeSchedule(ctx context.Context, id int64) error {
+	sched, err := a.ScheduleService.FindById(ctx, id)
+	if err != nil {
+		return a.err.New("获取调度失败", err)
+	}
+	next, err := nextScheduleFireTime(sched.CronExpr, sched.Timezone, time.Now())
+	if err != nil {
+		return a.err.New(err.Error(), err).WithCode(errorc.ErrorCodeValid)
+	}
+	return a.ScheduleService.UpdateFields(ctx, id, map[string]interface{}{
+		"status":       model.ScheduleStatusActive,
+		"next_fire_at": next,
+	})
+}
+
+// RunScheduleNow 立即按调度配置拉起一个实例。
+//
+// 这是运维的显式操作：不受暂停状态和重叠策略限制，也不影响 cron 的下次触发点。
+// 启动失败时仍会留下一条 FAILED 运行记录，并把原因返回给调用方。
+func (a *App) RunScheduleNow(ctx context.Context, id int64) (int64, error) {
+	var instanceID int64
+	var launchErr error
+	err := mvc.ExtractDB(ctx, base.DB).Transaction(func(tx *gorm.DB) error {
+		sched, err := a.ScheduleService.FindByIdForUpdate(ctx, tx, id)
+		if err != nil {
+			return a.err.New("获取调度失败", err)
+		}
+		run := &model.WorkflowScheduleRunModel{
+			ScheduleID:  sched.ID,
+			Trigger:     model.ScheduleTriggerManual,
+			ScheduledAt: time.Now(),
+		}
+		instanceID, launchErr = a.launchScheduledInstance(ctx, tx, sched, run)
+		return a.ScheduleRunService.Create(mvc.WithTxToContext(ctx, tx), run)
+	})
+	if err != nil {
+		return 0, err
+	}
+	if launchErr != nil {
+		return 0, a.err.New("按调度启动实例失败: "+launchErr.Error(), launchErr)
+	}
+	return instanceID, nil
+}
+
+// FireDueSchedules 触发所有已到期的调度，并让排队中的运行在条件满足时出队。
+// 由调度轮询任务周期性调用；单个调度失败只记日志，不影响其他调度。
+func (a *App) FireDueSchedules(ctx context.Context, now time.Time) (int, error) {
+	due, err := a.ScheduleService.ListDue(ctx, now, scheduleBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	fired := 0
+	for _, s := range due {
+		ok, err := a.fireSchedule(ctx, s.ID, now)
+		if err != nil {
+			a.log.WithErr(err).WithField("schedule_id", s.ID).Warn("调度触发失败")
+			continue
+		}
+		if ok {
+			fired++
+		}
+	}
+
+	queued, err := a.ScheduleRunService.ListQueuedScheduleIDs(ctx, scheduleBatchSize)
+	if err != nil {
+		return fired, err
+	}
+	for _, id := range queued {
+		if err := a.dequeueScheduleRun(ctx, id); err != nil {
+			a.log.WithErr(err).WithField("schedule_id", id).Warn("调度排队运行出队失败")
+		}
+	}
+	return fired, nil
+}
+
+// fireSchedule 在调度行锁内复核并消费一个到期触发点，返回本实例是否抢到了该触发点。
+//
+// 多个 aio 实例可能同时看到同一个到期调度；先拿到行锁的实例把 next_fire_at 推进到
+// now 之后，其余实例锁内复核时发现未到期，直接放弃。
+func (a *App) fireSchedule(ctx context.Context, id int64, now time.Time) (bool, error) {
+	fired := false
+	err := mvc.ExtractDB(ctx, base.DB).Transaction(func(tx *gorm.DB) error {
+		txCtx := mvc.WithTxToContext(ctx, tx)
+		sched, err := a.ScheduleService.FindByIdForUpdate(ctx, tx, id)
+		if err != nil {
+			return a.err.New("获取调度失败", err)
+		}
+		if sched.Status != model.ScheduleStatusActive || sched.NextFireAt == nil || sched.NextFireAt.After(now) {
+			return nil
+		}
+		scheduledAt := *sched.NextFireAt
+		fields := map[string]interface{}{"last_fire_at": scheduledAt}
+		if next, err := nextScheduleFireTime(sched.CronExpr, sched.Timezone, now); err != nil {
+			a.log.WithErr(err).WithField("schedule_id", id).Warn("调度无法计算下次触发点，已自动暂停")
+			fields["status"] = model.ScheduleStatusPaused
+			fields["next_fire_at"] = nil
+		} else {
+			fields["next_fire_at"] = next
+		}
+		if err := a.ScheduleService.UpdateFields(txCtx, id, fields); err != nil {
+			return err
+		}
+		fired = true
+
+		run := &model.WorkflowScheduleRunModel{
+			ScheduleID:  sched.ID,
+			Trigger:     model.ScheduleTriggerCron,
+			ScheduledAt: scheduledAt,
+		}
+		launch := true
+		if sched.OverlapPolicy != model.OverlapAllow {
+			inFlight, err := a.ScheduleRunService.CountInFlight(txCtx, id)
+			if err != nil {
+				return err
+			}
+			// queue 策略下已有排队记录时也要排到其后，保证按触发顺序启动
+			var queued *model.WorkflowScheduleRunModel
+			if sched.OverlapPolicy == model.OverlapQueue {
+				if queued, err = a.ScheduleRunService.FirstQueued(txCtx, id); err != nil {
+					return err
+				}
+			}
+			if inFlight > 0 || queued != nil {
+				launch = false
+				run.Status = model.ScheduleRunSkipped
+				if sched.OverlapPolicy == model.OverlapQueue {
+					run.Status = model.ScheduleRunQueued
+				}
+			}
+		}
+		if launch {
+			if _, err := a.launchScheduledInstance(ctx, tx, sched, run); err != nil {
+				a.log.WithErr(err).WithField("schedule_id", id).Warn("按调度启动实例失败")
+			}
+		}
+		return a.ScheduleRunService.Create(txCtx, run)
+	})
+	return fired, err
+}
+
+// dequeueScheduleRun queue 策略：调度没有在途实例时启动最早的一条排队运行
+func (a *App) dequeueScheduleRun(ctx context.Context, id int64) error {
+	return mvc.ExtractDB(ctx, base.DB).Transaction(func(tx *gorm.DB) error {
+		txCtx := mvc.WithTxToContext(ctx, tx)
+		sched, err := a.ScheduleService.FindByIdForUpdate(ctx, tx, id)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				// 调度已删除（删除时会清理排队记录，这里兜底）
+				return a.ScheduleRunService.SkipQueued(txCtx, id, "调度已删除")
+			}
+			return a.err.New("获取调度失败", err)
+		}
+		if sched.Status != model.ScheduleStatusActive {
+			return nil
+		}
+		inFlight, err := a.ScheduleRunService.CountInFlight(txCtx, id)
+		if err != nil || inFlight > 0 {
+			return err
+		}
+		run, err := a.ScheduleRunService.FirstQueued(txCtx, id)
+		if err != nil || run == nil {
+			return err
+		}
+		if _, err := a.launchScheduledInstance(ctx, tx, sched, run); err != nil {
+			a.log.WithErr(err).WithField("schedule_id", id).Warn("排队运行启动实例失败")
+		}
+		return a.ScheduleRunService.UpdateFields(txCtx, run.ID, map[string]interface{}{
+			"status":      run.Status,
+			"instance_id": run.InstanceID,
+			"error":       run.Error,
+		})
+	})
+}
+
+// launchScheduledInstance 在调用方事务内按调度配置拉起实例，并把结果写回 run（不落库）。
+//
+// 实例创建与起始节点派发放在保存点内：启动失败只回滚保存点，调用方仍会提交调度的
+// 推进与 FAILED 运行记录，避免一个坏调度在每个轮询周期反复失败。
+func (a *App) launchScheduledInstance(ctx context.Context, tx *gorm.DB, sched *model.WorkflowScheduleModel, run *model.WorkflowScheduleRunModel) (int64, error) {
+	loc, err := loadScheduleLocation(sched.Timezone)
+	if err != nil {
+		loc = time.Local
+	}
+	vars := scheduleTemplateVars{
+		ScheduleID:   sched.ID,
+		ScheduleName: sched.Name,
+		ScheduledAt:  run.ScheduledAt.In(loc),
+		Trigger:      string(run.Trigger),
+	}
+	var instanceID int64
+	data, err := renderScheduleInitialData(sched.InitialDataTemplate, vars)
+	if err == nil {
+		err = tx.Transaction(func(sp *gorm.DB) error {
+			spCtx := mvc.WithTxToContext(ctx, sp)
+			def, err := a.GetDefByCodeAndVersion(spCtx, sched.Env, sched.DefCode, sched.DefVersion)
+			if err != nil {
+				return err
+			}
+			instanceID, err = a.startInstance(spCtx, def, data, sched.Env, 0, "", "")
+			return err
+		})
+	}
+	if err != nil {
+		run.Status = model.ScheduleRunFailed
+		run.InstanceID = 0
+		run.Error = truncateScheduleError(err.Error())
+		return 0, err
+	}
+	run.Status = model.ScheduleRunStarted
+	run.InstanceID = instanceID
+	run.Error = ""
+	return instanceID, nil
+}
+
+// truncateScheduleError 截断错误信息以适配 error 列长度
+func truncateScheduleError(msg string) string {
+	const maxLen = 1000
+	if len(msg) <= maxLen {
+		return msg
+	}
+	return strings.ToValidUTF8(msg[:maxLen], "")
+}
@@ -0,0 +1,184 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+)
+
+// createDueSchedule 创建单任务定义及其调度，并把下次触发点拨到过去使其立即到期
+func createDueSchedule(t *testing.T, a *App, code string, policy model.ScheduleOverlapPolicy) *model.WorkflowScheduleModel {
+	t.Helper()
+	ctx := context.Background()
+	dagJSON := `{"nodes":[{"id":"T","type":"task","config":{"service":"svc","method":"m"}}]}`
+	if _, err := a.CreateDef(ctx, "test", code, code, dagJSON, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	sched, err := a.CreateSchedule(ctx, &ScheduleInput{
+		Env:                 "test",
+		Name:                code,
+		DefCode:             code,
+		CronExpr:            "*/5 * * * *",
+		Timezone:            "Asia/Shanghai",
+		InitialDataTemplate: `{"day":"{{.ScheduledAt.Format "2006-01-02"}}","schedule":{{.ScheduleID}}}`,
+		OverlapPolicy:       string(policy),
+	})
+	if err != nil {
+		t.Fatalf("create schedule: %v", err)
+	}
+	makeScheduleDue(t, a, sched.ID)
+	return sched
+}
+
+func makeScheduleDue(t *testing.T, a *App, id int64) {
+	t.Helper()
+	if err := a.ScheduleService.UpdateFields(context.Background(), id, map[string]interface{}{
+		"next_fire_at": time.Now().Add(-time.Minute),
+	}); err != nil {
+		t.Fatalf("make due: %v", err)
+	}
+}
+
+func listRuns(t *testing.T, a *App, id int64) []*model.WorkflowScheduleRunItem {
+	t.Helper()
+	runs, _, err := a.ListScheduleRuns(context.Background(), id, 1, 100)
+	if err != nil {
+		t.Fatalf("list runs: %v", err)
+	}
+	return runs
+}
+
+// 同一触发点只能被消费一次：多个 aio 实例轮询到同一到期调度时，后到者在锁内复核
+// 发现 next_fire_at 已被推进，必须放弃，而不是重复拉起实例。
+func TestFireDueSchedulesConsumesFirePointOnce(t *testing.T) {
+	ctx := context.Background()
+	a, _ := newTestApp(t)
+	sched := createDueSchedule(t, a, "sched_once", model.OverlapAllow)
+
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		if _, err := a.FireDueSchedules(ctx, now); err != nil {
+			t.Fatalf("fire #%d: %v", i, err)
+		}
+	}
+	runs := listRuns(t, a, sched.ID)
+	if len(runs) != 1 || runs[0].Status != model.ScheduleRunStarted || runs[0].InstanceID == 0 {
+		t.Fatalf("runs = %+v, want 1 条 STARTED", runs)
+	}
+	if runs[0].InstanceStatus != string(model.InstanceStatusRunning) {
+		t.Fatalf("instance_status = %s, want RUNNING", runs[0].InstanceStatus)
+	}
+
+	got, err := a.GetSchedule(ctx, sched.ID)
+	if err != nil {
+		t.Fatalf("get schedule: %v", err)
+	}
+	if got.NextFireAt == nil || !got.NextFireAt.After(now) || got.LastFireAt == nil {
+		t.Fatalf("next=%v last=%v, want next 推进到 now 之后且记录 last", got.NextFireAt, got.LastFireAt)
+	}
+
+	inst, err := a.GetInstance(ctx, runs[0].InstanceID)
+	if err != nil {
+		t.Fatalf("get instance: %v", err)
+	}
+	data, _, err := parseWorkflowState(inst.InitialState)
+	if err != nil {
+		t.Fatalf("parse state: %v", err)
+	}
+	wantDay := runs[0].ScheduledAt.In(mustLoadLocation(t, "Asia/Shanghai")).Format("2006-01-02")
+	if data["day"] != wantDay || data["schedule"] != float64(sched.ID) {
+		t.Fatalf("initial data = %v, want day=%s schedule=%d", data, wantDay, sched.ID)
+	}
+}
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("load location: %v", err)
+	}
+	return loc
+}
+
+// skip：上一实例仍在运行时本次触发只记历史；queue：排队，待上一实例结束后按顺序出队启动。
+func TestScheduleOverlapSkipAndQueue(t *testing.T) {
+	ctx := context.Background()
+	a, _ := newTestApp(t)
+	skip := createDueSchedule(t, a, "sched_skip", model.OverlapSkip)
+	queue := createDueSchedule(t, a, "sched_queue", model.OverlapQueue)
+
+	if _, err := a.FireDueSchedules(ctx, time.Now()); err != nil {
+		t.Fatalf("first fire: %v", err)
+	}
+	makeScheduleDue(t, a, skip.ID)
+	makeScheduleDue(t, a, queue.ID)
+	if _, err := a.FireDueSchedules(ctx, time.Now()); err != nil {
+		t.Fatalf("second fire: %v", err)
+	}
+
+	if runs := listRuns(t, a, skip.ID); len(runs) != 2 || runs[0].Status != model.ScheduleRunSkipped {
+		t.Fatalf("skip runs = %+v, want 最新一条 SKIPPED", runs)
+	}
+	runs := listRuns(t, a, queue.ID)
+	if len(runs) != 2 || runs[0].Status != model.ScheduleRunQueued || runs[0].InstanceID != 0 {
+		t.Fatalf("queue runs = %+v, want 最新一条 QUEUED", runs)
+	}
+
+	// 上一实例结束后，下一轮轮询让排队运行出队（此时调度本身尚未到期）
+	if err := a.CancelInstance(ctx, runs[1].InstanceID); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if _, err := a.FireDueSchedules(ctx, time.Now()); err != nil {
+		t.Fatalf("drain: %v", err)
+	}
+	runs = listRuns(t, a, queue.ID)
+	if runs[0].Status != model.ScheduleRunStarted || runs[0].InstanceID == 0 {
+		t.Fatalf("出队后 run = %+v, want STARTED 且拉起实例", runs[0])
+	}
+}
+
+// 立即运行不受暂停限制；启动失败也要留下 FAILED 记录，运维能在历史中看到原因。
+func TestRunScheduleNowWhilePausedAndRecordsFailure(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+	sched := createDueSchedule(t, a, "sched_manual", model.OverlapSkip)
+	if err := a.PauseSchedule(ctx, sched.ID); err != nil {
+		t.Fatalf("pause: %v", err)
+	}
+	if fired, err := a.FireDueSchedules(ctx, time.Now()); err != nil || fired != 0 {
+		t.Fatalf("暂停后仍被触发: fired=%d err=%v", fired, err)
+	}
+
+	instanceID, err := a.RunScheduleNow(ctx, sched.ID)
+	if err != nil || instanceID == 0 {
+		t.Fatalf("run now: id=%d err=%v", instanceID, err)
+	}
+
+	if err := db.Where("code = ?", "sched_manual").Delete(&model.WorkflowDefModel{}).Error; err != nil {
+		t.Fatalf("delete def: %v", err)
+	}
+	if _, err := a.RunScheduleNow(ctx, sched.ID); err == nil {
+		t.Fatal("定义已删除，立即运行应返回错误")
+	}
+	runs := listRuns(t, a, sched.ID)
+	if len(runs) != 2 || runs[0].Status != model.ScheduleRunFailed || runs[0].Error == "" || runs[0].Trigger != model.ScheduleTriggerManual {
+		t.Fatalf("runs = %+v, want 最新一条 manual FAILED 且带原因", runs)
+	}
+}
+
+// cron 在调度时区下求值：上海 09:00 即 UTC 01:00。
+func TestNextScheduleFireTimeUsesTimezone(t *testing.T) {
+	after := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	next, err := nextScheduleFireTime("0 9 * * *", "Asia/Shanghai", after)
+	if err != nil {
+		t.Fatalf("next: %v", err)
+	}
+	if want := time.Date(2026, 1, 1, 1, 0, 0, 0, time.UTC); !next.Equal(want) {
+		t.Fatalf("next = %v, want %v", next.UTC(), want)
+	}
+	if _, err := nextScheduleFireTime("0 9 * * *", "Mars/Base", after); err == nil {
+		t.Fatal("无效时区未被拒绝")
+	}
+}
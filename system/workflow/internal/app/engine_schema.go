@@ -0,0 +1,382 @@
+// 职责：定义声明的输入/状态 schema——实例启动前按 input_schema 校验初始输入，节点成功
+// 合并输出后按 state_schema 校验 data 区，校验失败给出字段级错误。
+//
+// 边界：支持 JSON Schema 的常用子集（type、enum、required、properties、
+// additionalProperties、items、minimum/maximum、minLength/maxLength、minItems/maxItems、
+// pattern），不支持 $ref 与组合关键字；审批表单的 form_schema 共用同一校验。节点输出合并、
+// Map 聚合写入 output_path 后违反 state_schema 时按节点失败处理（走 error 边，无 error 边则
+// 实例 FAILED），合并结果不落库；SendSignal 合并的信号数据违反时拒绝该信号。失败回调写入的
+// error_msg 不做状态校验。
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+)
+
+// SchemaViolation 单个字段的 schema 校验错误，Field 为字段路径（如 order.items[0].sku），根为空
+type SchemaViolation struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// SchemaValidationError 校验未通过，携带全部字段级错误
+type SchemaValidationError struct {
+	Violations []*SchemaViolation
+}
+
+func (e *SchemaValidationError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		if v.Field == "" {
+			msgs[i] = v.Message
+		} else {
+			msgs[i] = "字段 " + v.Field + " " + v.Message
+		}
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// schemaKnownTypes schema 支持的 type 取值
+var schemaKnownTypes = map[string]bool{
+	"string": true, "number": true, "integer": true, "boolean": true, "object": true, "array": true, "null": true,
+}
+
+// validateJSONSchema 按 schema 校验 JSON 解码后的值，schema 为空时直接通过
+func validateJSONSchema(schema map[string]interface{}, value interface{}) error {
+	if len(schema) == 0 {
+		return nil
+	}
+	var violations []*SchemaViolation
+	checkSchemaValue(schema, value, "", &violations)
+	if len(violations) > 0 {
+		return &SchemaValidationError{Violations: violations}
+	}
+	return nil
+}
+
+func checkSchemaValue(schema map[string]interface{}, value interface{}, path string, out *[]*SchemaViolation) {
+	fail := func(format string, args ...interface{}) {
+		*out = append(*out, &SchemaViolation{Field: path, Message: fmt.Sprintf(format, args...)})
+	}
+	if types := schemaTypes(schema); len(types) > 0 {
+		matched := false
+		for _, typ := range types {
+			if matchesSchemaType(typ, value) && (typ == "null" || value != nil) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			fail("应为 %s 类型", strings.Join(types, " 或 "))
+			return
+		}
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		matched := false
+		for _, e := range enum {
+			if fmt.Sprint(e) == fmt.Sprint(value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			fail("的值 %v 不在可选范围内", value)
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		n := utf8.RuneCountInString(v)
+		if limit, ok := schemaNumber(schema, "minLength"); ok && float64(n) < limit {
+			fail("长度不能小于 %v", limit)
+		}
+		if limit, ok := schemaNumber(schema, "maxLength"); ok && float64(n) > limit {
+			fail("长度不能大于 %v", limit)
+		}
+		if pattern, ok := schema["pattern"].(string); ok && pattern != "" {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(v) {
+				fail("不匹配格式 %s", pattern)
+			}
+		}
+	case float64:
+		if limit, ok := schemaNumber(schema, "minimum"); ok && v < limit {
+			fail("不能小于 %v", limit)
+		}
+		if limit, ok := schemaNumber(schema, "maximum"); ok && v > limit {
+			fail("不能大于 %v", limit)
+		}
+	case []interface{}:
+		if limit, ok := schemaNumber(schema, "minItems"); ok && float64(len(v)) < limit {
+			fail("元素个数不能少于 %v", limit)
+		}
+		if limit, ok := schemaNumber(schema, "maxItems"); ok && float64(len(v)) > limit {
+			fail("元素个数不能多于 %v", limit)
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				checkSchemaValue(items, item, fmt.Sprintf("%s[%d]", path, i), out)
+			}
+		}
+	case map[string]interface{}:
+		checkSchemaObject(schema, v, path, out)
+	}
+}
+
+func checkSchemaObject(schema map[string]interface{}, obj map[string]interface{}, path string, out *[]*SchemaViolation) {
+	required, _ := schema["required"].([]interface{})
+	for _, r := range required {
+		name, _ := r.(string)
+		if v, ok := obj[name]; name != "" && (!ok || v == nil) {
+			*out = append(*out, &SchemaViolation{Field: joinSchemaPath(path, name), Message: "为必填字段"})
+		}
+	}
+	props, _ := schema["properties"].(map[string]interface{})
+	extra, _ := schema["additionalProperties"].(map[string]interface{})
+	closed := schema["additionalProperties"] == false
+	// 按字段名排序，保证同一份数据的错误顺序稳定
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		field := joinSchemaPath(path, k)
+		if prop, ok := props[k].(map[string]interface{}); ok {
+			checkSchemaValue(prop, obj[k], field, out)
+			continue
+		}
+		switch {
+		case closed:
+			*out = append(*out, &SchemaViolation{Field: field, Message: "未在 schema 中声明"})
+		case extra != nil:
+			checkSchemaValue(extra, obj[k], field, out)
+		}
+	}
+}
+
+// schemaTypes 读取 type，兼容字符串与字符串数组两种写法
+func schemaTypes(schema map[string]interface{}) []string {
+	switch t := schema["type"].(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		types := make([]string, 0, len(t))
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+// matchesSchemaType 判断 JSON 解码后的值是否符合 schema type
+func matchesSchemaType(typ string, v interface{}) bool {
+	switch typ {
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == float64(int64(f))
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "object":
+		_, ok := v.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := v.([]interface{})
+		return ok
+	}
+	return true
+}
+
+func schemaNumber(schema map[string]interface{}, key string) (float64, bool) {
+	v, ok := schema[key].(float64)
+	return v, ok && !math.IsNaN(v)
+}
+
+func joinSchemaPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// checkJSONSchema 检查 schema 本身是否合法（定义校验时调用），返回第一个问题
+func checkJSONSchema(schema map[string]interface{}, path string) error {
+	switch t := schema["type"].(type) {
+	case nil:
+	case string:
+		if !schemaKnownTypes[t] {
+			return fmt.Errorf("%s.type 不支持: %s", path, t)
+		}
+	case []interface{}:
+		for _, item := range t {
+			if s, _ := item.(string); !schemaKnownTypes[s] {
+				return fmt.Errorf("%s.type 不支持: %v", path, item)
+			}
+		}
+	default:
+		return fmt.Errorf("%s.type 必须是字符串或字符串数组", path)
+	}
+	if raw, ok := schema["required"]; ok {
+		list, isList := raw.([]interface{})
+		if !isList {
+			return fmt.Errorf("%s.required 必须是字符串数组", path)
+		}
+		for _, item := range list {
+			if _, isStr := item.(string); !isStr {
+				return fmt.Errorf("%s.required 必须是字符串数组", path)
+			}
+		}
+	}
+	if raw, ok := schema["enum"]; ok {
+		if _, isList := raw.([]interface{}); !isList {
+			return fmt.Errorf("%s.enum 必须是数组", path)
+		}
+	}
+	if raw, ok := schema["pattern"]; ok {
+		pattern, _ := raw.(string)
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("%s.pattern 不是合法的正则: %v", path, err)
+		}
+	}
+	for _, key := range []string{"minimum", "maximum", "minLength", "maxLength", "minItems", "maxItems"} {
+		if raw, ok := schema[key]; ok {
+			if _, isNum := raw.(float64); !isNum {
+				return fmt.Errorf("%s.%s 必须是数字", path, key)
+			}
+		}
+	}
+	if raw, ok := schema["properties"]; ok {
+		props, isMap := raw.(map[string]interface{})
+		if !isMap {
+			return fmt.Errorf("%s.properties 必须是对象", path)
+		}
+		for name, p := range props {
+			prop, isMap := p.(map[string]interface{})
+			if !isMap {
+				return fmt.Errorf("%s.properties.%s 必须是对象", path, name)
+			}
+			if err := checkJSONSchema(prop, path+".properties."+name); err != nil {
+				return err
+			}
+		}
+	}
+	if raw, ok := schema["additionalProperties"]; ok {
+		switch v := raw.(type) {
+		case bool:
+		case map[string]interface{}:
+			if err := checkJSONSchema(v, path+".additionalProperties"); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%s.additionalProperties 必须是布尔值或对象", path)
+		}
+	}
+	if raw, ok := schema["items"]; ok {
+		items, isMap := raw.(map[string]interface{})
+		if !isMap {
+			return fmt.Errorf("%s.items 必须是对象", path)
+		}
+		if err := checkJSONSchema(items, path+".items"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseDefSchema 解析定义上保存的 schema JSON，未声明时返回 nil
+func parseDefSchema(raw string) (map[string]interface{}, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &schema); err != nil {
+		return nil, fmt.Errorf("解析 schema 失败: %w", err)
+	}
+	return schema, nil
+}
+
+// marshalDefSchema 把 DAG 中声明的 schema 序列化后保存到定义上
+func marshalDefSchema(schema map[string]interface{}) (string, error) {
+	if len(schema) == 0 {
+		return "", nil
+	}
+	b, err := json.Marshal(schema)
+	return string(b), err
+}
+
+// normalizeJSONValue 经一次 JSON 往返，把调用方传入的 Go 值（int、结构体等）统一为
+// JSON 解码后的形态，使校验结果与落库后读取时一致
+func normalizeJSONValue(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out interface{}
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// validateInitialInput 按定义的 input_schema 校验启动实例的初始输入
+func validateInitialInput(def *model.WorkflowDefModel, initialData map[string]interface{}) error {
+	schema, err := parseDefSchema(def.InputSchema)
+	if err != nil || schema == nil {
+		return err
+	}
+	var input interface{} = map[string]interface{}{}
+	if initialData != nil {
+		if input, err = normalizeJSONValue(initialData); err != nil {
+			return fmt.Errorf("序列化初始输入失败: %w", err)
+		}
+	}
+	return validateJSONSchema(schema, input)
+}
+
+// validateMergedState 在副本上试合并节点结果，按定义的 state_schema 校验合并后的 data 区。
+// 返回 nil 表示可以合并（含未声明 state_schema 的情况）
+func validateMergedState(def *model.WorkflowDefModel, data workflowStateData, output, nodeConfig, projected map[string]interface{}) error {
+	return validateStateChange(def, data, func(trial workflowStateData) {
+		applyNodeOutput(trial, output, nodeConfig, projected)
+	})
+}
+
+// validateStateChange 在 data 的副本上执行 apply，按定义的 state_schema 校验结果；data 本身不变。
+// 返回 nil 表示可以写入（含未声明 state_schema 的情况）
+func validateStateChange(def *model.WorkflowDefModel, data workflowStateData, apply func(workflowStateData)) error {
+	schema, err := parseDefSchema(def.StateSchema)
+	if err != nil || schema == nil {
+		return err
+	}
+	copied, err := normalizeJSONValue(data)
+	if err != nil {
+		return fmt.Errorf("复制状态失败: %w", err)
+	}
+	trial, _ := copied.(map[string]interface{})
+	if trial == nil {
+		trial = map[string]interface{}{}
+	}
+	apply(trial)
+	merged, err := normalizeJSONValue(trial)
+	if err != nil {
+		return fmt.Errorf("序列化状态失败: %w", err)
+	}
+	return validateJSONSchema(schema, merged)
+}
@@ -0,0 +1,159 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+)
+
+const schemaTestDAG = `{
+	"input_schema":{"type":"object","required":["order_id"],"properties":{
+		"order_id":{"type":"string","minLength":3},
+		"items":{"type":"array","items":{"type":"object","required":["sku"],"properties":{"qty":{"type":"integer","minimum":1}}}}
+	}},
+	"state_schema":{"type":"object","properties":{"amount":{"type":"number"}}},
+	"nodes":[
+		{"id":"A","type":"task","config":{"service":"svc","method":"a"}},
+		{"id":"B","type":"task","config":{"service":"svc","method":"b"}},
+		{"id":"H","type":"task","config":{"service":"svc","method":"h"}}
+	],
+	"edges":[{"from":"A","to":"B"},{"from":"A","to":"H","type":"error"}]
+}`
+
+// 定义保存声明的 schema；初始输入不符合 input_schema 时拒绝启动，并给出全部字段级错误
+func TestStartWorkflowValidatesInputSchema(t *testing.T) {
+	ctx := context.Background()
+	a, _ := newTestApp(t)
+	if _, err := a.CreateDef(ctx, "test", "schema_def", "schema", schemaTestDAG, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	def, err := a.GetDefByCode(ctx, "test", "schema_def")
+	if err != nil || !strings.Contains(def.InputSchema, "order_id") || !strings.Contains(def.StateSchema, "amount") {
+		t.Fatalf("定义未保存 schema: %+v, err = %v", def, err)
+	}
+
+	_, err = a.StartWorkflow(ctx, "schema_def", map[string]interface{}{
+		"order_id": "O1",
+		"items":    []interface{}{map[string]interface{}{"sku": "S1", "qty": 0}, map[string]interface{}{"qty": 2}},
+	}, "test")
+	var schemaErr *SchemaValidationError
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("非法输入未被拒绝: %v", err)
+	}
+	var fields []string
+	for _, v := range schemaErr.Violations {
+		fields = append(fields, v.Field)
+	}
+	if strings.Join(fields, ",") != "items[0].qty,items[1].sku,order_id" {
+		t.Fatalf("字段级错误 = %v", fields)
+	}
+
+	if _, err := a.StartWorkflow(ctx, "schema_def", map[string]interface{}{
+		"order_id": "O-100",
+		"items":    []interface{}{map[string]interface{}{"sku": "S1", "qty": 2}},
+	}, "test"); err != nil {
+		t.Fatalf("合法输入启动失败: %v", err)
+	}
+}
+
+// 节点结果合并后违反 state_schema 时按节点失败走 error 边，违规值不写入状态；
+// 合法结果照常合并推进。
+func TestStateSchemaViolationRoutesToErrorEdge(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+	if _, err := a.CreateDef(ctx, "test", "schema_def", "schema", schemaTestDAG, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	input := map[string]interface{}{"order_id": "O-100"}
+	bad, err := a.StartWorkflow(ctx, "schema_def", input, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := a.ReportNodeCompleted(ctx, bad, "A", map[string]interface{}{"amount": "12.5"}, "test"); err != nil {
+		t.Fatalf("report A: %v", err)
+	}
+	if countNodeJobs(t, db, bad, "H", "") != 1 || countNodeJobs(t, db, bad, "B", "") != 0 {
+		t.Fatal("状态校验失败未走 error 边")
+	}
+	inst, _ := a.GetInstance(ctx, bad)
+	data, _, _ := parseWorkflowState(inst.CurrentState)
+	if _, ok := data["amount"]; ok {
+		t.Fatalf("违规值被写入状态: %v", data)
+	}
+	if msg, _ := data["error_msg"].(string); !strings.Contains(msg, "字段 amount") {
+		t.Fatalf("error_msg = %q, want 含字段路径", msg)
+	}
+
+	good, err := a.StartWorkflow(ctx, "schema_def", input, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := a.ReportNodeCompleted(ctx, good, "A", map[string]interface{}{"amount": 12.5}, "test"); err != nil {
+		t.Fatalf("report A: %v", err)
+	}
+	if countNodeJobs(t, db, good, "B", "") != 1 {
+		t.Fatal("合法结果未推进到 B")
+	}
+	if s := instanceStatus(t, a, good); s != model.InstanceStatusRunning {
+		t.Fatalf("status = %s, want RUNNING", s)
+	}
+}
+
+// Map 聚合写入 output_path、SendSignal 合并信号数据同样按 state_schema 校验：违规的聚合结果
+// 按 Map 节点失败走 error 边且不写入状态，违规的信号被拒绝且状态不变。
+func TestStateSchemaCheckedOnMapAggregationAndSignal(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+	dagJSON := `{
+		"state_schema":{"type":"object","properties":{
+			"totals":{"type":"array","items":{"type":"object","required":["amount"]}},
+			"paid":{"type":"boolean"}
+		}},
+		"nodes":[
+			{"id":"M","type":"map","config":{"items_path":"items","output_path":"totals","iterator":{"service":"svc","method":"each"}}},
+			{"id":"B","type":"task","config":{"service":"svc","method":"b"}},
+			{"id":"H","type":"task","config":{"service":"svc","method":"h"}}
+		],
+		"edges":[{"from":"M","to":"B"},{"from":"M","to":"H","type":"error"}]
+	}`
+	if _, err := a.CreateDef(ctx, "test", "schema_map", "schema_map", dagJSON, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	id, err := a.StartWorkflow(ctx, "schema_map", map[string]interface{}{"items": []interface{}{"a"}}, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := a.reportNodeCompleted(ctx, 0, id, "M", map[string]interface{}{"total": 3}, "test", 0); err != nil {
+		t.Fatalf("report sub 0: %v", err)
+	}
+	if countNodeJobs(t, db, id, "H", "") != 1 || countNodeJobs(t, db, id, "B", "") != 0 {
+		t.Fatal("聚合结果违反 state_schema 未走 error 边")
+	}
+	inst, _ := a.GetInstance(ctx, id)
+	data, sys, _ := parseWorkflowState(inst.CurrentState)
+	if _, ok := data["totals"]; ok || mapSysEntry(sys, "map_counters")["M"] != nil {
+		t.Fatalf("违规聚合结果被写入: data=%v sys=%v", data, sys)
+	}
+
+	if err := a.ReportNodeCompleted(ctx, id, "H", nil, "test"); err != nil {
+		t.Fatalf("report H: %v", err)
+	}
+	var e *errorc.Error
+	if err := a.SendSignal(ctx, id, "paid", map[string]interface{}{"paid": "yes"}, "", ""); !errors.As(err, &e) || e.ErrorCode != errorc.ErrorCodeValid {
+		t.Fatalf("违规信号 err = %v, want ErrorCodeValid", err)
+	}
+	if inst, _ = a.GetInstance(ctx, id); inst.Status != model.InstanceStatusCompleted {
+		t.Fatalf("违规信号改变了实例: status=%s", inst.Status)
+	}
+	if err := a.SendSignal(ctx, id, "paid", map[string]interface{}{"paid": true}, "", ""); err != nil {
+		t.Fatalf("合法信号: %v", err)
+	}
+	inst, _ = a.GetInstance(ctx, id)
+	if data, _, _ = parseWorkflowState(inst.CurrentState); data["paid"] != true {
+		t.Fatalf("合法信号未合并: %v", data)
+	}
+}
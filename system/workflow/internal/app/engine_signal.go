@@ -0,0 +1,340 @@
+// 职责：wait_signal 节点——挂起所在分支直到收到指定名称的信号，信号 payload 作为节点输出
+// 合并进状态；可配置超时路由，以及按业务关联键投递，外部服务无需知道实例 ID。
+//
+// 边界：进入节点时登记一条信号等待（关联键在此刻基于状态求值并固化），实例进入 WAITING。
+// 信号可按实例（SendSignal）或按 env + 信号名 + 关联键（SendSignalByKey）投递，命中的每个
+// 等待各自加实例行锁、在同一事务内置为已收到并推进节点；等待已结束或节点已不活跃时静默
+// 作废。超时借助 executor 延时任务实现，到期有 decision=timeout 的出边则只走该边，否则以
+// error_msg 结束本节点；幂等键沿用 wf_{instance}_node_{node} 前缀，实例取消与回滚的按前缀
+// 取消会一并撤销。信号先于节点进入到达时不会被缓存。
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xsxdot/aio/base"
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	executorCallback "github.com/xsxdot/aio/system/executor/api/callback"
+	executorDto "github.com/xsxdot/aio/system/executor/api/dto"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"gorm.io/gorm"
+)
+
+// signalTimeoutErrorMsg 等待超时且无 timeout 边时写入的 error_msg
+const signalTimeoutErrorMsg = "等待信号超时"
+
+// SignalDelivery 按关联键投递信号的结果
+type SignalDelivery struct {
+	// Delivered 收到信号并推进的等待数
+	Delivered   int     `json:"delivered"`
+	InstanceIDs []int64 `json:"instance_ids"`
+}
+
+// waitSignalConfig wait_signal 节点配置
+type waitSignalConfig struct {
+	Signal         string
+	CorrelationKey string
+	Timeout        time.Duration
+}
+
+// parseWaitSignalConfig 解析 wait_signal 节点配置。
+//
+// 配置项：signal（必填，等待的信号名）、correlation_key（可选，表达式，进入节点时基于
+// state 求值得到关联键，结果须为非空字符串或数字）、timeout（可选，Go duration 字符串或秒数）。
+func parseWaitSignalConfig(n *model.Node) (*waitSignalConfig, error) {
+	cfg := &waitSignalConfig{}
+	cfg.Signal, _ = n.Config["signal"].(string)
+	if cfg.Signal == "" {
+		return nil, fmt.Errorf("wait_signal 节点 %s 缺少 signal 配置", n.ID)
+	}
+	if raw, ok := n.Config["correlation_key"]; ok {
+		if cfg.CorrelationKey, _ = raw.(string); cfg.CorrelationKey == "" {
+			return nil, fmt.Errorf("wait_signal 节点 %s 的 correlation_key 必须是非空表达式", n.ID)
+		}
+	}
+	if raw, ok := n.Config["timeout"]; ok {
+		d, err := toTimerDuration(raw)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("wait_signal 节点 %s 的 timeout 必须是正的时长: %v", n.ID, raw)
+		}
+		cfg.Timeout = d
+	}
+	return cfg, nil
+}
+
+// correlationKeyString 把关联键表达式的结果转换为字符串
+func correlationKeyString(v interface{}) (string, error) {
+	var key string
+	switch k := v.(type) {
+	case string:
+		key = k
+	case float64:
+		key = strconv.FormatFloat(k, 'f', -1, 64)
+	case int:
+		key = strconv.Itoa(k)
+	case int64:
+		key = strconv.FormatInt(k, 10)
+	default:
+		return "", fmt.Errorf("关联键必须是字符串或数字: %T", v)
+	}
+	if strings.TrimSpace(key) == "" {
+		return "", fmt.Errorf("关联键为空")
+	}
+	return key, nil
+}
+
+// triggerWaitSignalNode 进入 wait_signal 节点：作废本节点遗留的等待、登记新等待并按需登记超时唤醒，
+// 随后实例进入 WAITING
+func (a *App) triggerWaitSignalNode(ctx context.Context, instance *model.WorkflowInstanceModel, node *model.Node, env string) error {
+	if a.SignalWaitService == nil {
+		return a.err.New("信号等待服务未启用，无法进入 wait_signal 节点 "+node.ID, nil)
+	}
+	cfg, err := parseWaitSignalConfig(node)
+	if err != nil {
+		return a.err.New("wait_signal 节点配置无效", err).WithCode(errorc.ErrorCodeValid)
+	}
+	wait := &model.WorkflowSignalWaitModel{
+		InstanceID: instance.ID,
+		NodeID:     node.ID,
+		Env:        env,
+		SignalName: cfg.Signal,
+		Status:     model.SignalWaitStatusWaiting,
+	}
+	if cfg.CorrelationKey != "" {
+		data, sys, err := parseWorkflowState(instance.CurrentState)
+		if err != nil {
+			return a.err.New("解析状态失败", err)
+		}
+		exprEnv := loopExprVars(sys)
+		exprEnv["state"] = filterStateForExpr(data)
+		v, err := evalMappingExpr(cfg.CorrelationKey, exprEnv)
+		if err == nil {
+			wait.CorrelationKey, err = correlationKeyString(v)
+		}
+		if err != nil {
+			return a.err.New("wait_signal 节点 "+node.ID+" 的 correlation_key 求值失败", err)
+		}
+	}
+	if cfg.Timeout > 0 {
+		deadlineAt := time.Now().Add(cfg.Timeout)
+		wait.DeadlineAt = &deadlineAt
+	}
+
+	// 回环或回滚重新进入本节点时，上一轮的等待已无意义
+	if err := a.SignalWaitService.CancelWaiting(ctx, instance.ID, node.ID); err != nil {
+		return err
+	}
+	if err := a.SignalWaitService.Create(ctx, wait); err != nil {
+		return a.err.New("登记信号等待失败", err)
+	}
+	if wait.DeadlineAt != nil {
+		if err := a.scheduleSignalWaitTimeout(ctx, wait); err != nil {
+			return err
+		}
+	}
+	return a.updateInstanceStatusToWaitingWithLock(ctx, instance.ID, node.ID)
+}
+
+// scheduleSignalWaitTimeout 提交超时唤醒任务，到期回调经 OnJobCompleted 进入 handleSignalWaitTimeout
+func (a *App) scheduleSignalWaitTimeout(ctx context.Context, wait *model.WorkflowSignalWaitModel) error {
+	callbackDataBytes, _ := json.Marshal(map[string]interface{}{
+		"instance_id":    wait.InstanceID,
+		"node_id":        wait.NodeID,
+		"env":            wait.Env,
+		"signal_timeout": true,
+		"wait_id":        wait.ID,
+	})
+	argsBytes, _ := json.Marshal(map[string]interface{}{"instance_id": wait.InstanceID, "node_id": wait.NodeID, "wait_id": wait.ID})
+	_, err := a.ExecutorClient.SubmitJob(ctx, &executorDto.SubmitJobInput{
+		Env:              wait.Env,
+		TargetService:    executorCallback.InternalTargetService,
+		Method:           executorCallback.MethodTimerFired,
+		ArgsJSON:         string(argsBytes),
+		RunAt:            wait.DeadlineAt.Unix(),
+		MaxAttempts:      defaultNodeMaxAttempts,
+		DedupKey:         nodeDedupPrefix(wait.InstanceID, wait.NodeID) + fmt.Sprintf("signal_timeout_%d", wait.ID),
+		RetryBackoffType: executorDto.RetryBackoffExponential,
+		Source:           "workflow",
+		CallbackData:     string(callbackDataBytes),
+	})
+	if err != nil {
+		return a.err.New("提交信号等待超时任务到Executor失败", err)
+	}
+	return nil
+}
+
+// handleSignalWaitTimeout 处理信号等待超时回调：等待仍未结束且节点仍活跃时，
+// 有 timeout 边则以 decision=timeout 推进，否则以 error_msg 推进（走 error 边，无则实例 FAILED）。
+//
+// 过期的超时回调（已收到信号、已作废或实例已结束）只登记幂等标记后忽略；实例已暂停时推迟到
+// 恢复后重放，jobID=0 即为重放。
+func (a *App) handleSignalWaitTimeout(ctx context.Context, jobID, waitID, instanceID int64, nodeID, env string) error {
+	stale, deferred := false, false
+	err := mvc.ExtractDB(ctx, base.DB).Transaction(func(tx *gorm.DB) error {
+		txCtx := mvc.WithTxToContext(ctx, tx)
+		inst, err := a.InstanceService.FindByIdForUpdate(ctx, tx, instanceID)
+		if err != nil {
+			return err
+		}
+		var wait *model.WorkflowSignalWaitModel
+		if a.SignalWaitService != nil {
+			wait, err = a.SignalWaitService.FindById(txCtx, waitID)
+			if err != nil && !errorc.IsNotFound(err) {
+				return err
+			}
+		}
+		stale = wait == nil || wait.Status != model.SignalWaitStatusWaiting || !signalWaitActive(inst, nodeID)
+		if stale {
+			if a.AppliedCallbackDao == nil || jobID == 0 {
+				return nil
+			}
+			_, mErr := a.AppliedCallbackDao.MarkApplied(ctx, tx, jobID, instanceID, nodeID)
+			return mErr
+		}
+		if inst.Status == model.InstanceStatusPaused {
+			deferred = true
+			return a.deferPausedTimer(ctx, tx, jobID, inst, pausedTimer{Kind: pausedTimerSignalTimeout, NodeID: nodeID, RefID: waitID})
+		}
+		if env == "" {
+			env = inst.Env
+		}
+
+		def, err := a.DefService.FindByIdWithTx(ctx, tx, inst.DefID)
+		if err != nil {
+			return err
+		}
+		var dag model.DAG
+		if err := json.Unmarshal([]byte(def.DAGJSON), &dag); err != nil {
+			return fmt.Errorf("解析DAG失败: %w", err)
+		}
+		output := signalTimeoutOutput(&dag, nodeID, wait.SignalName)
+		if _, err := a.SignalWaitService.Resolve(txCtx, waitID, model.SignalWaitStatusTimedOut); err != nil {
+			return err
+		}
+		return a.reportNodeCompleted(txCtx, jobID, instanceID, nodeID, output, env)
+	})
+	if err != nil {
+		return a.err.New("处理信号等待超时失败", err).WithTraceID(ctx)
+	}
+	if stale {
+		a.log.WithField("wait_id", waitID).Debug("信号等待超时回调已过期，忽略")
+	} else if !deferred {
+		a.log.WithField("wait_id", waitID).WithField("instance_id", instanceID).
+			Warn("等待信号超时，已按超时或失败路径推进")
+	}
+	return nil
+}
+
+// signalWaitActive 实例仍在推进中且 wait_signal 节点仍活跃
+func signalWaitActive(inst *model.WorkflowInstanceModel, nodeID string) bool {
+	if inst.Status != model.InstanceStatusRunning && inst.Status != model.InstanceStatusWaiting && inst.Status != model.InstanceStatusPaused {
+		return false
+	}
+	return containsString(parseActiveNodeIDs(inst.ActiveNodeIDs), nodeID)
+}
+
+// SendSignalByKey 按 env + 信号名 + 关联键投递信号，推进所有命中的 wait_signal 节点；
+// 没有命中时返回 Delivered=0。env 为空时使用进程默认环境
+func (a *App) SendSignalByKey(ctx context.Context, env, signalName, correlationKey string, payload map[string]interface{}) (*SignalDelivery, error) {
+	if strings.TrimSpace(signalName) == "" || strings.TrimSpace(correlationKey) == "" {
+		return nil, a.err.New("signal_name 与 correlation_key 不能为空", nil).WithCode(errorc.ErrorCodeValid)
+	}
+	if a.SignalWaitService == nil {
+		return nil, a.err.New("信号等待服务未启用", nil)
+	}
+	if env == "" {
+		env = base.ENV
+	}
+	waits, err := a.SignalWaitService.ListWaitingByKey(ctx, env, signalName, correlationKey)
+	if err != nil {
+		return nil, err
+	}
+	return a.deliverSignalToWaits(ctx, waits, payload)
+}
+
+// deliverSignalToWaits 逐个推进等待，返回实际推进的实例
+func (a *App) deliverSignalToWaits(ctx context.Context, waits []*model.WorkflowSignalWaitModel, payload map[string]interface{}) (*SignalDelivery, error) {
+	result := &SignalDelivery{InstanceIDs: []int64{}}
+	for _, w := range waits {
+		delivered, err := a.deliverSignal(ctx, w, payload)
+		if err != nil {
+			return result, err
+		}
+		if delivered {
+			result.Delivered++
+			result.InstanceIDs = append(result.InstanceIDs, w.InstanceID)
+		}
+	}
+	return result, nil
+}
+
+// deliverSignal 在实例行锁下把等待置为已收到，并以 payload 作为节点输出推进 wait_signal 节点。
+// 等待已被并发投递或超时结束时返回 false；实例已结束或节点已不活跃时作废该等待并返回 false
+func (a *App) deliverSignal(ctx context.Context, wait *model.WorkflowSignalWaitModel, payload map[string]interface{}) (bool, error) {
+	delivered := false
+	err := mvc.ExtractDB(ctx, base.DB).Transaction(func(tx *gorm.DB) error {
+		txCtx := mvc.WithTxToContext(ctx, tx)
+		inst, err := a.InstanceService.FindByIdForUpdate(ctx, tx, wait.InstanceID)
+		if err != nil {
+			return err
+		}
+		if !signalWaitActive(inst, wait.NodeID) {
+			_, err := a.SignalWaitService.Resolve(txCtx, wait.ID, model.SignalWaitStatusCanceled)
+			return err
+		}
+		ok, err := a.SignalWaitService.Resolve(txCtx, wait.ID, model.SignalWaitStatusReceived)
+		if err != nil || !ok {
+			return err
+		}
+		if err := a.recordInstanceEvent(txCtx, inst, model.InstanceEventSignalReceived, wait.NodeID, map[string]interface{}{
+			"signal_name": wait.SignalName,
+			"payload":     payload,
+		}); err != nil {
+			return err
+		}
+		output := make(map[string]interface{}, len(payload))
+		for k, v := range payload {
+			output[k] = v
+		}
+		delivered = true
+		return a.reportNodeCompleted(txCtx, 0, inst.ID, wait.NodeID, output, wait.Env)
+	})
+	if err != nil {
+		return false, a.err.New("投递信号失败", err)
+	}
+	return delivered, nil
+}
+
+// cancelSignalWaits 作废实例中仍在等待的信号，信号等待服务未装配时跳过
+func (a *App) cancelSignalWaits(ctx context.Context, instanceID int64) error {
+	if a.SignalWaitService == nil {
+		return nil
+	}
+	return a.SignalWaitService.CancelWaiting(ctx, instanceID, "")
+}
+
+// signalTimeoutOutput 等待超时时的节点输出：有 timeout 边时以 decision=timeout 推进，否则以 error_msg 推进
+func signalTimeoutOutput(dag *model.DAG, nodeID, signalName string) map[string]interface{} {
+	for _, e := range dag.GetOutgoingEdges(nodeID) {
+		if e.Decision == model.SignalDecisionTimeout {
+			return map[string]interface{}{"decision": model.SignalDecisionTimeout}
+		}
+	}
+	return map[string]interface{}{"error_msg": signalTimeoutErrorMsg + ": " + signalName}
+}
+
+// waitSignalEdgeAllowed wait_signal 节点超时推进时只走 timeout 边；收到信号时 timeout 边
+// 已由 decisionEdgeMatches 排除
+func waitSignalEdgeAllowed(node *model.Node, edge model.Edge, output map[string]interface{}) bool {
+	if node == nil || node.Type != model.NodeTypeWaitSignal {
+		return true
+	}
+	decision, _ := output["decision"].(string)
+	return decision != model.SignalDecisionTimeout || edge.Decision == model.SignalDecisionTimeout
+}
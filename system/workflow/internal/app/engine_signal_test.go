@@ -0,0 +1,143 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+)
+
+func startSignalInstance(t *testing.T, a *App, code string, data map[string]interface{}) int64 {
+	t.Helper()
+	id, err := a.StartWorkflow(context.Background(), code, data, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	inst, err := a.GetInstance(context.Background(), id)
+	if err != nil {
+		t.Fatalf("get instance: %v", err)
+	}
+	if inst.Status != model.InstanceStatusWaiting {
+		t.Fatalf("status = %s, want WAITING", inst.Status)
+	}
+	return id
+}
+
+// 外部服务只持有业务键：按关联键投递只推进键匹配的实例，信号 payload 合并入状态；
+// 重复投递不会二次推进。按实例发送且不指定唤醒节点时同样交给 wait_signal 节点。
+func TestWaitSignalDeliveredByCorrelationKey(t *testing.T) {
+	ctx := context.Background()
+	a, _ := newTestApp(t)
+	dagJSON := `{"nodes":[
+		{"id":"W","type":"wait_signal","config":{"signal":"order_paid","correlation_key":"state.order_no"}},
+		{"id":"SHIP","type":"task","config":{"service":"order","method":"ship"}}
+	],"edges":[{"from":"W","to":"SHIP"}]}`
+	if _, err := a.CreateDef(ctx, "test", "signal_def", "signal", dagJSON, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	id1 := startSignalInstance(t, a, "signal_def", map[string]interface{}{"order_no": "SO1"})
+	id2 := startSignalInstance(t, a, "signal_def", map[string]interface{}{"order_no": "SO2"})
+
+	res, err := a.SendSignalByKey(ctx, "test", "order_paid", "SO404", nil)
+	if err != nil || res.Delivered != 0 {
+		t.Fatalf("未匹配的关联键: res=%+v err=%v", res, err)
+	}
+	for i := 0; i < 2; i++ {
+		res, err = a.SendSignalByKey(ctx, "test", "order_paid", "SO1", map[string]interface{}{"paid": true})
+		if err != nil {
+			t.Fatalf("send by key #%d: %v", i, err)
+		}
+		want := fmt.Sprintf("[%d]", id1)
+		if i == 1 {
+			want = "[]"
+		}
+		if got := fmt.Sprint(res.InstanceIDs); got != want {
+			t.Fatalf("send #%d instance_ids = %s, want %s", i, got, want)
+		}
+	}
+
+	inst, err := a.GetInstance(ctx, id1)
+	if err != nil {
+		t.Fatalf("get instance: %v", err)
+	}
+	if inst.ActiveNodeIDs != `["SHIP"]` || inst.Status != model.InstanceStatusRunning {
+		t.Fatalf("active = %s status = %s, want [SHIP] RUNNING", inst.ActiveNodeIDs, inst.Status)
+	}
+	data, _, err := parseWorkflowState(inst.CurrentState)
+	if err != nil || data["paid"] != true {
+		t.Fatalf("payload 未合并入状态: %s", inst.CurrentState)
+	}
+	if other, _ := a.GetInstance(ctx, id2); other.ActiveNodeIDs != `["W"]` {
+		t.Fatalf("关联键不匹配的实例被推进: %s", other.ActiveNodeIDs)
+	}
+
+	if err := a.SendSignal(ctx, id2, "order_paid", map[string]interface{}{"paid": true}, "", ""); err != nil {
+		t.Fatalf("send signal: %v", err)
+	}
+	if other, _ := a.GetInstance(ctx, id2); other.ActiveNodeIDs != `["SHIP"]` {
+		t.Fatalf("按实例发送未推进 wait_signal 节点: %s", other.ActiveNodeIDs)
+	}
+}
+
+// 超时到期仍未收到信号：有 timeout 边只走该边，没有则按节点失败走 error 边；
+// 重复投递的超时回调与超时后迟到的信号都不会二次推进。
+func TestWaitSignalTimeoutRoutes(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+	defs := map[string]string{
+		"timeout_edge": `{"nodes":[
+			{"id":"W","type":"wait_signal","config":{"signal":"kyc_done","correlation_key":"state.user_id","timeout":"1h"}},
+			{"id":"OK","type":"task","config":{"service":"svc","method":"ok"}},
+			{"id":"LATE","type":"task","config":{"service":"svc","method":"late"}}
+		],"edges":[{"from":"W","to":"OK"},{"from":"W","to":"LATE","decision":"timeout"}]}`,
+		"error_edge": `{"nodes":[
+			{"id":"W","type":"wait_signal","config":{"signal":"kyc_done","timeout":60}},
+			{"id":"OK","type":"task","config":{"service":"svc","method":"ok"}},
+			{"id":"FALLBACK","type":"task","config":{"service":"svc","method":"fallback"}}
+		],"edges":[{"from":"W","to":"OK"},{"from":"W","to":"FALLBACK","type":"error"}]}`,
+	}
+	want := map[string]string{"timeout_edge": `["LATE"]`, "error_edge": `["FALLBACK"]`}
+	for code, dagJSON := range defs {
+		if _, err := a.CreateDef(ctx, "test", code, code, dagJSON, 1); err != nil {
+			t.Fatalf("create def %s: %v", code, err)
+		}
+		id := startSignalInstance(t, a, code, map[string]interface{}{"user_id": 7})
+
+		var job struct {
+			ID           uint64
+			CallbackData string
+		}
+		if err := db.Table("aio_executor_jobs").Select("id, callback_data").
+			Where("dedup_key LIKE ?", fmt.Sprintf("wf_%d_node_W_signal_timeout_%%", id)).Take(&job).Error; err != nil {
+			t.Fatalf("%s: 超时任务未登记: %v", code, err)
+		}
+		for i := 0; i < 2; i++ {
+			if err := a.OnJobCompleted(ctx, job.ID, job.CallbackData, ""); err != nil {
+				t.Fatalf("%s: timeout callback #%d: %v", code, i, err)
+			}
+		}
+		inst, err := a.GetInstance(ctx, id)
+		if err != nil {
+			t.Fatalf("get instance: %v", err)
+		}
+		if inst.ActiveNodeIDs != want[code] {
+			t.Fatalf("%s: active = %s, want %s", code, inst.ActiveNodeIDs, want[code])
+		}
+		var wait model.WorkflowSignalWaitModel
+		if err := db.Where("instance_id = ?", id).Take(&wait).Error; err != nil || wait.Status != model.SignalWaitStatusTimedOut {
+			t.Fatalf("%s: wait status = %s err=%v, want TIMED_OUT", code, wait.Status, err)
+		}
+	}
+
+	res, err := a.SendSignalByKey(ctx, "test", "kyc_done", "7", nil)
+	if err != nil || res.Delivered != 0 {
+		t.Fatalf("超时后的迟到信号不应推进: res=%+v err=%v", res, err)
+	}
+	// timeout 决策边要求节点配置了 timeout
+	bad := `{"nodes":[{"id":"W","type":"wait_signal","config":{"signal":"s"}},{"id":"B","type":"task"}],
+		"edges":[{"from":"W","to":"B","decision":"timeout"}]}`
+	if _, err := a.CreateDef(ctx, "test", "bad_signal", "bad", bad, 1); err == nil {
+		t.Fatal("未配置 timeout 的 timeout 边未被拒绝")
+	}
+}
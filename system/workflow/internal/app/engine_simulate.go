@@ -0,0 +1,704 @@
+// 职责：离线模拟——不依赖数据库、Worker 与 executor，用模拟的节点输出在内存中把一个定义从头跑到底，
+// 返回完整的节点完成轨迹与最终状态，供修改 DAG 后在发布前验证路由。
+//
+// 边界：模拟不经推进事务，但与 reportNodeCompleted 共用同一套判定：边选择、下一跳调度与 join
+// （经 checkpointLog 读取内存中的完成记录）、状态归约与 output_mapping、state_schema 校验、
+// transform / loop 求值与同步循环驱动、Map 的失败容忍与聚合、回环清理、同步跳数护栏与补偿计划。
+// 节点被触发后进入内存中的待完成队列，按触发顺序以该节点的模拟输出完成；定时节点立即到期，
+// 节点被模拟为超时且配置了对应时限时，按引擎的超时/截止输出推进。审批、wait_signal 与 subworkflow
+// 以模拟输出直接完成，子实例内部不模拟；http 节点不发请求，不检查密钥引用。executor 的重试不模拟，
+// 模拟失败即为任务最终失败；补偿任务视为全部成功。除按 def_code 读取一次定义外不访问数据库。
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/xsxdot/aio/base"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+)
+
+const (
+	// simulationMaxSteps 单次模拟最多投递的节点结果数，超出视为无法结束（如回环条件永远成立）
+	simulationMaxSteps = 1000
+	// simulationFailureMsg 模拟失败未指定 error 时写入的 error_msg
+	simulationFailureMsg = "模拟失败"
+)
+
+// SimulationMock 单个节点的模拟结果。
+//
+// 节点每被执行一次（Map 的每个子任务各算一次）计一次调用，调用次序从 1 开始：
+// 命中 FailOn 或只配置了 Error 时该次调用失败；否则依次取 Outputs 中的一项（用完后重复最后一项），
+// 没有 Outputs 时取 Output，都没有时输出空对象。Timeout 为 true 时不回报结果，
+// 而是让节点的超时唤醒立即到期，用于验证 timeout 路由。
+type SimulationMock struct {
+	Output  map[string]interface{}   `json:"output,omitempty"`
+	Outputs []map[string]interface{} `json:"outputs,omitempty"`
+	Error   string                   `json:"error,omitempty"`
+	FailOn  []int                    `json:"fail_on,omitempty"`
+	Timeout bool                     `json:"timeout,omitempty"`
+}
+
+// result 第 call 次调用的节点输出
+func (m *SimulationMock) result(call int) map[string]interface{} {
+	if m == nil {
+		return map[string]interface{}{}
+	}
+	failed := m.Error != "" && len(m.FailOn) == 0
+	for _, n := range m.FailOn {
+		if n == call {
+			failed = true
+		}
+	}
+	if failed {
+		msg := m.Error
+		if msg == "" {
+			msg = simulationFailureMsg
+		}
+		return map[string]interface{}{"error_msg": msg}
+	}
+	if len(m.Outputs) > 0 {
+		idx := call - 1
+		if idx >= len(m.Outputs) {
+			idx = len(m.Outputs) - 1
+		}
+		return m.Outputs[idx]
+	}
+	if m.Output != nil {
+		return m.Output
+	}
+	return map[string]interface{}{}
+}
+
+// SimulationInput 模拟参数：DAGJSON 与 DefCode 二选一，DefCode 时模拟已保存的定义
+type SimulationInput struct {
+	Env         string // 按 DefCode 查找定义的环境，空则用进程默认环境
+	DAGJSON     string
+	DefCode     string
+	DefVersion  int32 // 0 表示最新版本
+	InitialData map[string]interface{}
+	Mocks       map[string]*SimulationMock // 节点 ID → 模拟结果，未配置的节点输出空对象
+}
+
+// SimulationStep 一次节点完成
+type SimulationStep struct {
+	NodeID string                 `json:"node_id"`
+	Output map[string]interface{} `json:"output"`
+	Failed bool                   `json:"failed"`
+	// StateAfter 本次完成后的业务状态，状态未变化时为空
+	StateAfter map[string]interface{} `json:"state_after,omitempty"`
+}
+
+// SimulationResult 模拟结果
+type SimulationResult struct {
+	Status        model.WorkflowInstanceStatus `json:"status"`
+	FinalState    map[string]interface{}       `json:"final_state"`
+	ActiveNodeIDs []string                     `json:"active_node_ids"`
+	// Trail 按完成顺序列出每一次节点完成，含回环重跑与 condition/transform 等同步节点
+	Trail []SimulationStep `json:"trail"`
+	// Blocked 模拟结束时仍活跃、但无法继续推进的节点（等待外部输入且未配置模拟结果）
+	Blocked []string `json:"blocked,omitempty"`
+	// Compensated 实例失败后按补偿计划依次补偿的节点
+	Compensated []string `json:"compensated,omitempty"`
+	// Steps 投递的节点结果数
+	Steps int `json:"steps"`
+}
+
+// simulationTask 已触发、等待模拟结果的节点；SubID 为 Map 子任务序号，其余节点为 -1
+type simulationTask struct {
+	NodeID string
+	SubID  int
+}
+
+// simulation 一次模拟运行：内存中的实例状态、活跃节点、待完成队列与 checkpoint
+type simulation struct {
+	a           *App
+	def         *model.WorkflowDefModel
+	dag         *model.DAG
+	mocks       map[string]*SimulationMock
+	calls       map[string]int
+	status      model.WorkflowInstanceStatus
+	data        workflowStateData
+	sys         workflowStateSys
+	active      []string
+	pending     []simulationTask
+	checkpoints []*model.WorkflowCheckpointModel
+	trail       []SimulationStep
+	compensated []string
+	steps       int
+}
+
+// SimulateDAG 不经数据库模拟一份 DAG JSON（in.DAGJSON 必填），供 workflowtest 在单元测试中直接调用
+func SimulateDAG(ctx context.Context, in *SimulationInput) (*SimulationResult, error) {
+	return NewApp(nil, nil, nil, nil, nil).Simulate(ctx, in)
+}
+
+// Simulate 以模拟的节点输出在内存中运行一个定义，返回轨迹与最终状态，不写库
+func (a *App) Simulate(ctx context.Context, in *SimulationInput) (*SimulationResult, error) {
+	if (in.DAGJSON == "") == (in.DefCode == "") {
+		return nil, a.err.New("dag_json 与 def_code 必须且只能指定一个", nil).WithCode(errorc.ErrorCodeValid)
+	}
+	def, err := a.simulationDef(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateInitialInput(def, in.InitialData); err != nil {
+		return nil, a.err.New("初始输入校验失败: "+err.Error(), err).WithCode(errorc.ErrorCodeValid)
+	}
+	var dag model.DAG
+	if err := json.Unmarshal([]byte(def.DAGJSON), &dag); err != nil {
+		return nil, a.err.New("解析DAG失败", err)
+	}
+	dag.Normalize()
+	for nodeID := range in.Mocks {
+		if dag.GetNode(nodeID) == nil {
+			return nil, a.err.New("mocks 引用了不存在的节点: "+nodeID, nil).WithCode(errorc.ErrorCodeValid)
+		}
+	}
+	startNodes := dag.GetStartNodes()
+	if len(startNodes) == 0 {
+		return nil, a.err.New("工作流未定义起始节点", nil)
+	}
+
+	// 经 JSON 往返，使初始数据的数字等类型与落库后读回的一致
+	initial, err := normalizeJSONValue(in.InitialData)
+	if err != nil {
+		return nil, a.err.New("序列化初始状态失败", err).WithCode(errorc.ErrorCodeValid)
+	}
+	data, _ := initial.(map[string]interface{})
+	if data == nil {
+		data = make(workflowStateData)
+	}
+	s := &simulation{
+		a:      a,
+		def:    def,
+		dag:    &dag,
+		mocks:  in.Mocks,
+		calls:  map[string]int{},
+		status: model.InstanceStatusRunning,
+		data:   data,
+		sys:    make(workflowStateSys),
+		active: []string{},
+		trail:  []SimulationStep{},
+	}
+	for _, n := range startNodes {
+		s.active = append(s.active, n.ID)
+	}
+	for _, n := range startNodes {
+		node := n
+		if err := s.trigger(ctx, &node); err != nil {
+			return nil, a.err.New("触发起始节点失败: "+err.Error(), err)
+		}
+	}
+	if err := s.run(ctx); err != nil {
+		return nil, err
+	}
+	return s.result(), nil
+}
+
+// simulationDef 取待模拟的定义：def_code 时读取已保存的版本，DAG JSON 经与 CreateDef 相同的校验后
+// 只在内存中构造
+func (a *App) simulationDef(ctx context.Context, in *SimulationInput) (*model.WorkflowDefModel, error) {
+	if in.DefCode == "" {
+		def, _, err := a.parseDef(in.DAGJSON)
+		return def, err
+	}
+	env := in.Env
+	if env == "" {
+		env = base.ENV
+	}
+	def, err := a.GetDefByCodeAndVersion(ctx, env, in.DefCode, in.DefVersion)
+	if err != nil {
+		if errorc.IsNotFound(err) {
+			return nil, a.err.New("工作流定义不存在", err).NotFound()
+		}
+		return nil, err
+	}
+	return def, nil
+}
+
+// count / latest 实现 checkpointLog，供 planEdges 的去重与 join 判定读取内存中的完成记录
+func (s *simulation) count(nodeID string) int64 {
+	var n int64
+	for _, cp := range s.checkpoints {
+		if cp.NodeID == nodeID {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *simulation) latest(nodeID string) *model.WorkflowCheckpointModel {
+	for i := len(s.checkpoints) - 1; i >= 0; i-- {
+		if s.checkpoints[i].NodeID == nodeID {
+			return s.checkpoints[i]
+		}
+	}
+	return nil
+}
+
+// run 按触发顺序逐个完成待完成队列中的任务，直到实例结束或无法继续推进
+func (s *simulation) run(ctx context.Context) error {
+	for !isTerminalStatus(s.status) {
+		task, output, ok := s.next()
+		if !ok {
+			return nil
+		}
+		if s.steps >= simulationMaxSteps {
+			return s.a.err.New(fmt.Sprintf("模拟超过 %d 步仍未结束，DAG 可能存在无限回环", simulationMaxSteps), nil).WithCode(errorc.ErrorCodeValid)
+		}
+		s.steps++
+		if err := s.complete(ctx, task.NodeID, task.SubID, output); err != nil {
+			return s.a.err.New(fmt.Sprintf("模拟第 %d 步推进节点 %s 失败", s.steps, task.NodeID), err)
+		}
+	}
+	return nil
+}
+
+// next 取下一个可以完成的任务并移出队列：先按触发顺序取 Worker 或定时唤醒会回报的任务，
+// 没有时再取等待外部输入、且配置了模拟结果的节点。模拟为超时的 Map 节点整体超时，SubID 置为 -1
+func (s *simulation) next() (simulationTask, map[string]interface{}, bool) {
+	for _, external := range []bool{false, true} {
+		for i, t := range s.pending {
+			node := s.dag.GetNode(t.NodeID)
+			if node == nil {
+				continue
+			}
+			mock := s.mocks[t.NodeID]
+			var output map[string]interface{}
+			switch {
+			case mock != nil && mock.Timeout:
+				if external {
+					continue
+				}
+				if output = s.timeoutOutput(node); output == nil {
+					continue
+				}
+				t.SubID = -1
+			case node.Type == model.NodeTypeTask || node.Type == model.NodeTypeHTTP || node.Type == model.NodeTypeMap:
+				if external {
+					continue
+				}
+				s.calls[t.NodeID]++
+				output = mock.result(s.calls[t.NodeID])
+			case node.Type == model.NodeTypeTimer:
+				if external {
+					continue
+				}
+				output = map[string]interface{}{}
+			case node.Type == model.NodeTypeApproval || node.Type == model.NodeTypeWaitSignal || node.Type == model.NodeTypeSubWorkflow:
+				if !external || mock == nil {
+					continue
+				}
+				s.calls[t.NodeID]++
+				output = mock.result(s.calls[t.NodeID])
+			default:
+				continue
+			}
+			s.pending = append(s.pending[:i:i], s.pending[i+1:]...)
+			// 经 JSON 往返，使数字等类型与 Worker 回报的结果一致
+			var normalized map[string]interface{}
+			b, _ := json.Marshal(output)
+			_ = json.Unmarshal(b, &normalized)
+			return t, normalized, true
+		}
+	}
+	return simulationTask{}, nil, false
+}
+
+// timeoutOutput 节点超时或截止到期时引擎回报的输出；节点未配置对应时限时返回 nil，模拟停在该节点
+func (s *simulation) timeoutOutput(node *model.Node) map[string]interface{} {
+	switch node.Type {
+	case model.NodeTypeTask, model.NodeTypeHTTP, model.NodeTypeMap:
+		if policy, err := parseNodeJobPolicy(node.Config); err == nil && policy.Timeout > 0 {
+			return map[string]interface{}{"error_msg": nodeTimeoutErrorMsg}
+		}
+	case model.NodeTypeApproval:
+		if spec, _, err := node.ApprovalSpec(); err == nil && spec.Deadline > 0 {
+			_, output := approvalDeadlineOutcome(s.dag, node.ID)
+			return output
+		}
+	case model.NodeTypeWaitSignal:
+		if cfg, err := parseWaitSignalConfig(node); err == nil && cfg.Timeout > 0 {
+			return signalTimeoutOutput(s.dag, node.ID, cfg.Signal)
+		}
+	}
+	return nil
+}
+
+// trigger 对应 triggerNode：入参构造失败时以 error_msg 完成该节点
+func (s *simulation) trigger(ctx context.Context, node *model.Node) error {
+	err := s.dispatch(ctx, node)
+	var inputErr *nodeInputError
+	if !errors.As(err, &inputErr) {
+		return err
+	}
+	return s.complete(ctx, node.ID, -1, map[string]interface{}{"error_msg": inputErr.Error()})
+}
+
+// dispatch 对应 dispatchNode：同步节点立即完成，其余节点按引擎的入参构造检查后进入待完成队列
+func (s *simulation) dispatch(ctx context.Context, node *model.Node) error {
+	switch node.Type {
+	case model.NodeTypeTask:
+		if _, _, err := buildNodeInput(node.Config, s.data, loopExprVars(s.sys)); err != nil {
+			return &nodeInputError{msg: "输入映射失败", err: err}
+		}
+	case model.NodeTypeHTTP:
+		if _, err := buildHTTPNodeArgs(node.Config, s.data); err != nil {
+			return &nodeInputError{msg: "请求构造失败", err: err}
+		}
+	case model.NodeTypeSubWorkflow:
+		if _, mapped, err := buildNodeInput(node.Config, s.data, nil); err != nil {
+			return &nodeInputError{msg: "输入映射失败", err: err}
+		} else if inputPath, _ := node.Config["input_path"].(string); !mapped && inputPath != "" {
+			if _, ok := getValueAtPath(s.data, inputPath).(map[string]interface{}); !ok {
+				return &nodeInputError{msg: "input_path 对应值不是对象: " + inputPath}
+			}
+		}
+	case model.NodeTypeApproval:
+		if _, _, err := node.ApprovalSpec(); err != nil {
+			return fmt.Errorf("审批节点 %s 配置无效: %w", node.ID, err)
+		}
+		s.status = model.InstanceStatusWaiting
+	case model.NodeTypeWaitSignal:
+		if _, err := parseWaitSignalConfig(node); err != nil {
+			return err
+		}
+		s.status = model.InstanceStatusWaiting
+	case model.NodeTypeTimer:
+		if _, err := resolveTimerFireAt(node.Config, s.data, time.Now()); err != nil {
+			return fmt.Errorf("计算 timer 节点到期时间失败: %w", err)
+		}
+	case model.NodeTypeCondition, model.NodeTypeTransform:
+		return s.complete(ctx, node.ID, -1, nil)
+	case model.NodeTypeLoop:
+		return driveSyncLoop(ctx, node.ID, func(ctx context.Context) error {
+			return s.complete(ctx, node.ID, -1, nil)
+		})
+	case model.NodeTypeMap:
+		return s.dispatchMap(node)
+	default:
+		return nil
+	}
+	s.pending = append(s.pending, simulationTask{NodeID: node.ID, SubID: -1})
+	return nil
+}
+
+// dispatchMap 对应 triggerMapNode：初始化 _sys 中的聚合计数与结果，首批子任务进入待完成队列
+func (s *simulation) dispatchMap(node *model.Node) error {
+	cfg, err := parseMapNodeConfig(node)
+	if err != nil {
+		return err
+	}
+	items, ok := getValueAtPath(s.data, cfg.ItemsPath).([]interface{})
+	if !ok || len(items) == 0 {
+		return &nodeInputError{msg: "items_path 对应值非数组或为空: " + cfg.ItemsPath}
+	}
+	window := cfg.initialWindow(len(items))
+	for i := 0; i < window; i++ {
+		if err := s.checkMapItemInput(node, cfg, items[i]); err != nil {
+			return err
+		}
+	}
+	clearMapNodeSys(s.sys, node.ID)
+	mapSysEntry(s.sys, "map_counters")[node.ID] = len(items)
+	mapSysEntry(s.sys, "map_results")[node.ID] = make([]interface{}, len(items))
+	mapSysEntry(s.sys, "map_dispatch")[node.ID] = map[string]interface{}{"next": window, "failed": 0}
+	if window < len(items) {
+		mapSysEntry(s.sys, "map_items")[node.ID] = items
+	}
+	for i := 0; i < window; i++ {
+		s.pending = append(s.pending, simulationTask{NodeID: node.ID, SubID: i})
+	}
+	return nil
+}
+
+func (s *simulation) checkMapItemInput(node *model.Node, cfg *mapNodeConfig, item interface{}) error {
+	if _, _, err := buildNodeInput(node.Config, s.data, map[string]interface{}{cfg.ItemAlias: item}); err != nil {
+		return &nodeInputError{msg: "输入映射失败", err: err}
+	}
+	return nil
+}
+
+// complete 对应 reportNodeCompleted：以 output 完成节点（subID >= 0 为 Map 子任务），
+// 含 error_msg 时走失败路径，否则归约状态、选择出边并触发下一跳
+func (s *simulation) complete(ctx context.Context, nodeID string, subID int, output map[string]interface{}) error {
+	ctx, _, _, exceeded := enterAdvanceHop(ctx, nodeID)
+	if exceeded {
+		return s.a.err.New("单次推进的同步递归跳数超过上限，DAG 可能存在纯 condition 回环", nil).WithCode(errorc.ErrorCodeValid)
+	}
+	if isTerminalStatus(s.status) {
+		return nil
+	}
+	if s.status == model.InstanceStatusWaiting {
+		s.status = model.InstanceStatusRunning
+	}
+	stateBefore := s.dataJSON()
+	node := s.dag.GetNode(nodeID)
+	_, hasErrorMsg := output["error_msg"]
+	var next []string
+
+	if subID >= 0 && node != nil {
+		handled, err := s.completeMapItem(node, subID, output, stateBefore, &next)
+		var schemaErr *SchemaValidationError
+		var inputErr *nodeInputError
+		switch {
+		case errors.As(err, &schemaErr):
+			output = map[string]interface{}{"error_msg": "状态校验失败: " + schemaErr.Error()}
+			hasErrorMsg, handled = true, false
+		case errors.As(err, &inputErr):
+			output = map[string]interface{}{"error_msg": inputErr.Error()}
+			hasErrorMsg, handled = true, false
+		case err != nil:
+			return err
+		}
+		if handled {
+			return s.triggerNext(ctx, next)
+		}
+	}
+
+	var projected map[string]interface{}
+	var loopRoute string
+	if !hasErrorMsg && node != nil {
+		var p map[string]interface{}
+		var mErr error
+		switch node.Type {
+		case model.NodeTypeTransform:
+			if p, mErr = evalTransformNode(node.Config, s.data); mErr == nil {
+				output = p
+			}
+		case model.NodeTypeLoop:
+			loopRoute, mErr = advanceLoopNode(node, s.data, s.sys)
+		default:
+			p, _, mErr = projectNodeOutput(node.Config, s.data, output)
+		}
+		if mErr != nil {
+			output = map[string]interface{}{"error_msg": mErr.Error()}
+			hasErrorMsg = true
+		} else {
+			projected = p
+		}
+	}
+	var nodeConfig map[string]interface{}
+	if node != nil {
+		nodeConfig = node.Config
+	}
+	if !hasErrorMsg {
+		if sErr := validateMergedState(s.def, s.data, output, nodeConfig, projected); sErr != nil {
+			output = map[string]interface{}{"error_msg": "状态校验失败: " + sErr.Error()}
+			hasErrorMsg = true
+		}
+	}
+	if hasErrorMsg {
+		return s.fail(ctx, node, nodeID, subID, output, stateBefore)
+	}
+
+	applyNodeOutput(s.data, output, nodeConfig, projected)
+	s.active = removeString(s.active, nodeID)
+	s.addCheckpoint(nodeID, output)
+	s.record(nodeID, output, false, stateBefore)
+	edges := s.a.selectOutEdges(s.dag, nodeID, nodeOutcome{Node: node, Output: output, LoopRoute: loopRoute}, s.data, s.sys)
+	s.clearLoopback(edges)
+	s.a.planEdges(s, s.dag, edges, &s.active, &next, s.cancelSiblings)
+	if len(s.active) == 0 {
+		s.status = model.InstanceStatusCompleted
+	}
+	return s.triggerNext(ctx, next)
+}
+
+// completeMapItem 对应 handleMapSubTaskCompleted：记录子任务结果，全部结束时聚合并选择出边。
+// 返回 handled=false 时由调用方按 Map 节点整体失败处理
+func (s *simulation) completeMapItem(node *model.Node, subID int, output map[string]interface{}, stateBefore string, next *[]string) (bool, error) {
+	counters := mapSysEntry(s.sys, "map_counters")
+	count, ok := configInt(counters, node.ID)
+	results, _ := mapSysEntry(s.sys, "map_results")[node.ID].([]interface{})
+	if !ok || subID >= len(results) {
+		return true, nil
+	}
+	cfg, err := parseMapNodeConfig(node)
+	if err != nil {
+		return true, err
+	}
+	if errorMsg, hasErrorMsg := output["error_msg"]; hasErrorMsg {
+		if !recordMapItemFailure(cfg, s.sys, node.ID, subID, len(results), errorMsg) {
+			return false, nil
+		}
+		output = map[string]interface{}{"error_msg": errorMsg}
+	}
+	results[subID] = output
+	count--
+	counters[node.ID] = count
+	if count > 0 {
+		if index, item, ok := takeNextMapItem(s.sys, node.ID); ok {
+			if err := s.checkMapItemInput(node, cfg, item); err != nil {
+				return false, err
+			}
+			s.pending = append(s.pending, simulationTask{NodeID: node.ID, SubID: index})
+		}
+		return true, nil
+	}
+	write, aggregated := mapAggregation(cfg, s.sys, node.ID, results)
+	if err := validateStateChange(s.def, s.data, write); err != nil {
+		return false, err
+	}
+	write(s.data)
+	clearMapNodeSys(s.sys, node.ID)
+	s.active = removeString(s.active, node.ID)
+	s.addCheckpoint(node.ID, aggregated)
+	s.record(node.ID, aggregated, false, stateBefore)
+	edges := s.a.selectOutEdges(s.dag, node.ID, nodeOutcome{Node: node, Output: aggregated}, s.data, s.sys)
+	s.clearLoopback(edges)
+	s.a.planEdges(s, s.dag, edges, &s.active, next, s.cancelSiblings)
+	if len(s.active) == 0 {
+		s.status = model.InstanceStatusCompleted
+	}
+	return true, nil
+}
+
+// fail 对应推进的失败路径：有 error/always 出边时合并 error_msg 并沿其推进，否则实例失败
+func (s *simulation) fail(ctx context.Context, node *model.Node, nodeID string, subID int, output map[string]interface{}, stateBefore string) error {
+	if subID >= 0 || s.sys["map_counters"] != nil {
+		clearMapNodeSys(s.sys, nodeID)
+	}
+	// 节点的其余在途任务（Map 子任务、超时前的执行）不再回报
+	s.dropPending(nodeID)
+	hasFailureEdges := false
+	for _, e := range s.dag.GetOutgoingEdges(nodeID) {
+		if e.Type == model.EdgeTypeError || e.Type == model.EdgeTypeAlways {
+			hasFailureEdges = true
+		}
+	}
+	if !hasFailureEdges {
+		s.active = []string{}
+		s.addCheckpoint(nodeID, output)
+		s.record(nodeID, output, true, stateBefore)
+		s.failed()
+		return nil
+	}
+	for k, v := range output {
+		s.data[k] = v
+	}
+	s.active = removeString(s.active, nodeID)
+	var next []string
+	edges := s.a.selectOutEdges(s.dag, nodeID, nodeOutcome{Node: node, Output: output, Failed: true}, s.data, s.sys)
+	s.a.planEdges(s, s.dag, edges, &s.active, &next, s.cancelSiblings)
+	s.addCheckpoint(nodeID, output)
+	s.record(nodeID, output, true, stateBefore)
+	if len(s.active) == 0 {
+		s.failed()
+		return nil
+	}
+	return s.triggerNext(ctx, next)
+}
+
+// failed 实例失败：与 beginCompensation 一样按 checkpoint 逆序确定补偿计划，模拟中补偿全部成功
+func (s *simulation) failed() {
+	s.status = model.InstanceStatusFailed
+	s.pending = nil
+	for _, step := range compensationSteps(s.checkpoints, s.dag) {
+		s.compensated = append(s.compensated, step.NodeID)
+	}
+	if len(s.compensated) > 0 {
+		s.status = model.InstanceStatusCompensated
+	}
+}
+
+// triggerNext 依次触发选中的下一跳
+func (s *simulation) triggerNext(ctx context.Context, next []string) error {
+	for _, nodeID := range next {
+		node := s.dag.GetNode(nodeID)
+		if node == nil {
+			continue
+		}
+		if err := s.trigger(ctx, node); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clearLoopback 对应 clearLoopbackCheckpoints：选中回环边时清理回环目标之后的 checkpoint
+func (s *simulation) clearLoopback(edges []model.Edge) {
+	if from, ok := loopbackClearFrom(s.checkpoints, edges); ok {
+		s.checkpoints = s.checkpoints[:from]
+	}
+}
+
+// cancelSiblings 汇聚满足后被取消的兄弟分支不再回报结果
+func (s *simulation) cancelSiblings(_ string, siblings []string) {
+	for _, nodeID := range siblings {
+		s.dropPending(nodeID)
+	}
+}
+
+func (s *simulation) dropPending(nodeID string) {
+	kept := s.pending[:0]
+	for _, t := range s.pending {
+		if t.NodeID != nodeID {
+			kept = append(kept, t)
+		}
+	}
+	s.pending = kept
+}
+
+func (s *simulation) addCheckpoint(nodeID string, output map[string]interface{}) {
+	outputBytes, _ := json.Marshal(output)
+	stateAfter, _ := serializeWorkflowState(s.data, s.sys)
+	cp := &model.WorkflowCheckpointModel{NodeID: nodeID, NodeOutput: string(outputBytes), StateAfter: stateAfter}
+	cp.ID = int64(len(s.checkpoints) + len(s.trail) + 1)
+	s.checkpoints = append(s.checkpoints, cp)
+}
+
+// record 追加一条轨迹；业务状态有变化时附上完成后的状态快照
+func (s *simulation) record(nodeID string, output map[string]interface{}, failed bool, stateBefore string) {
+	step := SimulationStep{NodeID: nodeID, Output: output, Failed: failed}
+	if s.dataJSON() != stateBefore {
+		step.StateAfter = s.dataCopy()
+	}
+	s.trail = append(s.trail, step)
+}
+
+func (s *simulation) dataJSON() string {
+	b, _ := json.Marshal(s.data)
+	return string(b)
+}
+
+func (s *simulation) dataCopy() map[string]interface{} {
+	copied, _ := normalizeJSONValue(s.data)
+	m, _ := copied.(map[string]interface{})
+	if m == nil {
+		m = map[string]interface{}{}
+	}
+	return m
+}
+
+// result 汇总模拟结果；实例未结束时仍活跃的节点即为无法继续推进的节点
+func (s *simulation) result() *SimulationResult {
+	res := &SimulationResult{
+		Status:        s.status,
+		FinalState:    s.dataCopy(),
+		ActiveNodeIDs: s.active,
+		Trail:         s.trail,
+		Compensated:   s.compensated,
+		Steps:         s.steps,
+	}
+	if !isTerminalStatus(s.status) {
+		res.Blocked = s.active
+	}
+	return res
+}
+
+// removeString 返回去掉 v 的新切片
+func removeString(list []string, v string) []string {
+	out := make([]string, 0, len(list))
+	for _, s := range list {
+		if s != v {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,125 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+)
+
+func simulationTrailNodes(res *SimulationResult) []string {
+	ids := make([]string, 0, len(res.Trail))
+	for _, step := range res.Trail {
+		ids = append(ids, step.NodeID)
+	}
+	return ids
+}
+
+// 循环体按调用次序取模拟输出并累积，失败的节点经 error 边到兜底节点；轨迹包含每一轮重跑，
+// 兜底节点也失败时实例失败，已完成且配置了 compensate 的节点列入补偿；全程不经数据库
+func TestSimulateLoopAndErrorEdge(t *testing.T) {
+	ctx := context.Background()
+	dagJSON := `{"nodes":[
+		{"id":"S","type":"task","config":{"service":"svc","method":"s","compensate":{"service":"svc","method":"undo_s"}}},
+		{"id":"L","type":"loop","config":{"body":"P","max_iterations":5,"while":"state.has_more",
+			"accumulate":"state.page","accumulate_path":"report.pages"}},
+		{"id":"P","type":"task","config":{"service":"svc","method":"page"}},
+		{"id":"N","type":"task","config":{"service":"svc","method":"n"}},
+		{"id":"H","type":"task","config":{"service":"svc","method":"h"}}
+	],"edges":[
+		{"from":"S","to":"L"},{"from":"L","to":"P"},{"from":"L","to":"N"},
+		{"from":"P","to":"L","is_loopback":true},{"from":"N","to":"H","type":"error"}
+	]}`
+
+	in := &SimulationInput{
+		DAGJSON:     dagJSON,
+		InitialData: map[string]interface{}{"has_more": true},
+		Mocks: map[string]*SimulationMock{
+			"P": {Outputs: []map[string]interface{}{{"page": 1, "has_more": true}, {"page": 2, "has_more": false}}},
+			"N": {Error: "下游不可用"},
+			"H": {Output: map[string]interface{}{"handled": true}},
+		},
+	}
+	res, err := SimulateDAG(ctx, in)
+	if err != nil {
+		t.Fatalf("simulate: %v", err)
+	}
+	if res.Status != model.InstanceStatusCompleted || len(res.Blocked) != 0 {
+		t.Fatalf("status=%s blocked=%v, want COMPLETED", res.Status, res.Blocked)
+	}
+	got := simulationTrailNodes(res)
+	want := []string{"S", "L", "P", "L", "P", "L", "N", "H"}
+	if len(got) != len(want) {
+		t.Fatalf("trail = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("trail = %v, want %v", got, want)
+		}
+	}
+	if n := res.Trail[6]; !n.Failed || n.Output["error_msg"] != "下游不可用" {
+		t.Fatalf("N step = %+v, want failed", n)
+	}
+	report, _ := res.FinalState["report"].(map[string]interface{})
+	if pages, _ := report["pages"].([]interface{}); len(pages) != 2 || res.FinalState["handled"] != true {
+		t.Fatalf("final state = %v", res.FinalState)
+	}
+
+	in.Mocks["H"] = &SimulationMock{Error: "兜底失败"}
+	if res, err = SimulateDAG(ctx, in); err != nil {
+		t.Fatalf("simulate failure: %v", err)
+	}
+	if res.Status != model.InstanceStatusCompensated || len(res.Compensated) != 1 || res.Compensated[0] != "S" {
+		t.Fatalf("status=%s compensated=%v, want COMPENSATED [S]", res.Status, res.Compensated)
+	}
+}
+
+// Map 的每个子任务各算一次调用，聚合后继续下游；等待信号且未配置模拟结果的节点使模拟停在该处
+// 并列为 blocked，模拟为超时则立即按 timeout 边推进；mocks 引用不存在的节点属于参数错误
+func TestSimulateMapAndWaitSignal(t *testing.T) {
+	ctx := context.Background()
+	dagJSON := `{"nodes":[
+		{"id":"M","type":"map","config":{"items_path":"items","output_path":"results","iterator":{"service":"svc","method":"each"}}},
+		{"id":"W","type":"wait_signal","config":{"signal":"kyc_done","timeout":"1h"}},
+		{"id":"OK","type":"task","config":{"service":"svc","method":"ok"}},
+		{"id":"LATE","type":"task","config":{"service":"svc","method":"late"}}
+	],"edges":[{"from":"M","to":"W"},{"from":"W","to":"OK"},{"from":"W","to":"LATE","decision":"timeout"}]}`
+
+	_, err := SimulateDAG(ctx, &SimulationInput{DAGJSON: dagJSON, Mocks: map[string]*SimulationMock{"X": {}}})
+	var e *errorc.Error
+	if !errors.As(err, &e) || e.ErrorCode != errorc.ErrorCodeValid {
+		t.Fatalf("未知节点 err = %v, want ErrorCodeValid", err)
+	}
+
+	in := &SimulationInput{
+		DAGJSON:     dagJSON,
+		InitialData: map[string]interface{}{"items": []interface{}{"a", "b", "c"}},
+		Mocks: map[string]*SimulationMock{
+			"M": {Outputs: []map[string]interface{}{{"v": 1}, {"v": 2}, {"v": 3}}},
+		},
+	}
+	res, err := SimulateDAG(ctx, in)
+	if err != nil {
+		t.Fatalf("simulate: %v", err)
+	}
+	if res.Status != model.InstanceStatusWaiting || len(res.Blocked) != 1 || res.Blocked[0] != "W" {
+		t.Fatalf("status=%s blocked=%v, want WAITING [W]", res.Status, res.Blocked)
+	}
+	results, _ := res.FinalState["results"].([]interface{})
+	if len(results) != 3 {
+		t.Fatalf("results = %v, want 3 项", res.FinalState["results"])
+	}
+	if item, _ := results[2].(map[string]interface{}); item["v"] != float64(3) {
+		t.Fatalf("results[2] = %v, want v=3", results[2])
+	}
+
+	in.Mocks["W"] = &SimulationMock{Timeout: true}
+	if res, err = SimulateDAG(ctx, in); err != nil {
+		t.Fatalf("simulate timeout: %v", err)
+	}
+	if got := simulationTrailNodes(res); res.Status != model.InstanceStatusCompleted || got[len(got)-1] != "LATE" {
+		t.Fatalf("status=%s trail=%v, want COMPLETED ending at LATE", res.Status, got)
+	}
+}
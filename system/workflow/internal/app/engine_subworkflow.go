@@ -0,0 +1,217 @@
+// 职责：subworkflow 节点——在父实例中拉起一个子工作流实例，子实例进入终态后
+// 把结果作为该节点的输出回报给父实例，由常规推进逻辑继续路由。
+//
+// 边界：父子关系只通过实例上的 ParentInstanceID/ParentNodeID 表达，不引入额外
+// 关联表。子实例与父实例同 env，共享同一套 executor 回调链路；子实例失败或
+// 被取消时以 error_msg 回报，父节点的 error 边可接住它。
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+)
+
+// maxSubWorkflowDepth 子工作流允许的最大嵌套深度。
+//
+// 子工作流可以引用自身定义（递归分解任务），若配错会无限拉起实例；
+// 深度护栏让这类错误在第 N 层直接失败，而不是耗尽数据库。
+const maxSubWorkflowDepth = 8
+
+// isTerminalStatus 实例是否已进入终态（不会再被推进）
+func isTerminalStatus(s model.WorkflowInstanceStatus) bool {
+	return s == model.InstanceStatusCompleted || s == model.InstanceStatusFailed || s == model.InstanceStatusCanceled ||
+		s == model.InstanceStatusCompensated
+}
+
+// configInt 读取节点配置中的整数值；JSON 解码后数字为 float64，这里统一兼容
+func configInt(conf map[string]interface{}, key string) (int, bool) {
+	switch v := conf[key].(type) {
+	case float64:
+		return int(v), true
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	}
+	return 0, false
+}
+
+// triggerSubWorkflowNode 拉起子工作流实例。
+//
+// 节点配置：
+//   - def_code: 子工作流定义编码（必填）
+//   - version: 子工作流定义版本，缺省或 0 表示最新版本
+//   - input_path: 子实例初始数据取父状态中该路径下的对象；缺省时复制父实例全部业务数据
+//   - input_mapping: 按声明构造子实例初始数据，配置时优先于 input_path
+//
+// 注意：调用方处于父实例推进事务内，子实例的创建与起始节点派发随父事务一起提交或回滚。
+func (a *App) triggerSubWorkflowNode(ctx context.Context, instance *model.WorkflowInstanceModel, node *model.Node, env string) error {
+	defCode, _ := node.Config["def_code"].(string)
+	if defCode == "" {
+		return a.err.New("subworkflow 节点缺少 def_code 配置", nil)
+	}
+	version, _ := configInt(node.Config, "version")
+
+	depth, err := a.instanceDepth(ctx, instance)
+	if err != nil {
+		return err
+	}
+	if depth >= maxSubWorkflowDepth {
+		return a.err.New(fmt.Sprintf("子工作流嵌套深度超过上限 %d", maxSubWorkflowDepth), nil)
+	}
+
+	def, err := a.GetDefByCodeAndVersion(ctx, env, defCode, int32(version))
+	if err != nil {
+		return a.err.New("获取子工作流定义失败", err)
+	}
+
+	data, _, err := parseWorkflowState(instance.CurrentState)
+	if err != nil {
+		return a.err.New("解析状态失败", err)
+	}
+	childData := make(map[string]interface{})
+	if input, mapped, err := buildNodeInput(node.Config, data, nil); err != nil {
+		return a.err.New("subworkflow 节点输入映射失败", &nodeInputError{msg: "输入映射失败", err: err})
+	} else if mapped {
+		childData = input
+	} else if inputPath, _ := node.Config["input_path"].(string); inputPath != "" {
+		sub, ok := getValueAtPath(data, inputPath).(map[string]interface{})
+		if !ok {
+			msg := "input_path 对应值不是对象: " + inputPath
+			return a.err.New("subworkflow 节点 "+msg, &nodeInputError{msg: msg})
+		}
+		for k, v := range sub {
+			childData[k] = v
+		}
+	} else {
+		for k, v := range data {
+			childData[k] = v
+		}
+	}
+
+	childID, err := a.startInstance(ctx, def, childData, env, instance.ID, node.ID, "")
+	if err != nil {
+		return err
+	}
+	a.log.WithField("instance_id", instance.ID).
+		WithField("node_id", node.ID).
+		WithField("child_instance_id", childID).
+		Info("已拉起子工作流实例")
+	return nil
+}
+
+// instanceDepth 沿父链计算实例的嵌套深度，顶层实例为 0
+func (a *App) instanceDepth(ctx context.Context, instance *model.WorkflowInstanceModel) (int, error) {
+	depth := 0
+	parentID := instance.ParentInstanceID
+	for parentID > 0 && depth <= maxSubWorkflowDepth {
+		depth++
+		parent, err := a.InstanceService.FindById(ctx, parentID)
+		if err != nil {
+			return 0, a.err.New("获取父实例失败", err)
+		}
+		parentID = parent.ParentInstanceID
+	}
+	return depth, nil
+}
+
+// notifyParentOfChildTerminal 子实例进入终态后回报父实例的 subworkflow 节点。
+//
+// 以下情况静默跳过：非子实例、父实例已不在运行/等待、父节点已不活跃（例如父实例
+// 被回滚），或该子实例不是父节点最近一次拉起的子实例（回环重复拉起时旧子实例的
+// 迟到结果不能覆盖新一轮）。
+func (a *App) notifyParentOfChildTerminal(ctx context.Context, child *model.WorkflowInstanceModel) error {
+	if child.ParentInstanceID <= 0 || child.ParentNodeID == "" {
+		return nil
+	}
+	parent, err := a.InstanceService.FindById(ctx, child.ParentInstanceID)
+	if err != nil {
+		return a.err.New("获取父实例失败", err)
+	}
+	if parent.Status != model.InstanceStatusRunning && parent.Status != model.InstanceStatusWaiting && parent.Status != model.InstanceStatusPaused {
+		return nil
+	}
+	if !containsString(parseActiveNodeIDs(parent.ActiveNodeIDs), child.ParentNodeID) {
+		return nil
+	}
+	children, err := a.InstanceService.ListChildren(ctx, parent.ID, child.ParentNodeID)
+	if err != nil {
+		return err
+	}
+	if len(children) == 0 || children[len(children)-1].ID != child.ID {
+		return nil
+	}
+
+	var output map[string]interface{}
+	switch child.Status {
+	case model.InstanceStatusCompleted:
+		data, _, err := parseWorkflowState(child.CurrentState)
+		if err != nil {
+			return a.err.New("解析子实例状态失败", err)
+		}
+		output = make(map[string]interface{}, len(data))
+		for k, v := range data {
+			output[k] = v
+		}
+	case model.InstanceStatusCanceled:
+		output = map[string]interface{}{"error_msg": fmt.Sprintf("子工作流实例 %d 已取消", child.ID)}
+	case model.InstanceStatusCompensated:
+		output = map[string]interface{}{"error_msg": fmt.Sprintf("子工作流实例 %d 已补偿", child.ID)}
+	default:
+		output = map[string]interface{}{"error_msg": fmt.Sprintf("子工作流实例 %d 执行失败", child.ID)}
+	}
+	return a.ReportNodeCompleted(ctx, parent.ID, child.ParentNodeID, output, parent.Env)
+}
+
+// cascadeCancelKeyType 标记由父节点级联发起的取消
+type cascadeCancelKeyType struct{}
+
+// isCascadeCancel 本次取消是否由父节点级联发起：此时父节点已由调用方自行推进或终结，
+// 子实例不得再回报父节点，否则会在同一次推进中重复推进父节点
+func isCascadeCancel(ctx context.Context) bool {
+	v, _ := ctx.Value(cascadeCancelKeyType{}).(bool)
+	return v
+}
+
+// cancelChildInstances 取消父实例某节点拉起的、仍在运行的子实例（级联取消）
+func (a *App) cancelChildInstances(ctx context.Context, parentInstanceID int64, nodeID string) {
+	ctx = context.WithValue(ctx, cascadeCancelKeyType{}, true)
+	children, err := a.InstanceService.ListChildren(ctx, parentInstanceID, nodeID)
+	if err != nil {
+		a.log.WithErr(err).WithField("instance_id", parentInstanceID).Warn("查询子工作流实例失败，跳过级联取消")
+		return
+	}
+	for _, child := range children {
+		if isTerminalStatus(child.Status) {
+			continue
+		}
+		if err := a.CancelInstance(ctx, child.ID); err != nil {
+			a.log.WithErr(err).
+				WithField("instance_id", parentInstanceID).
+				WithField("child_instance_id", child.ID).
+				Warn("级联取消子工作流实例失败")
+		}
+	}
+}
+
+// parseActiveNodeIDs 解析 ActiveNodeIDs 字段，格式异常时视为空
+func parseActiveNodeIDs(raw string) []string {
+	var ids []string
+	if raw != "" {
+		_ = json.Unmarshal([]byte(raw), &ids)
+	}
+	return ids
+}
+
+// containsString 判断切片中是否包含指定字符串
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+)
+
+// 子工作流只含一个 condition 节点，会在拉起时同步跑完；借此端到端验证
+// 「子实例终态 → 回报父节点 → 父实例推进」这条链路，无需 executor。
+func TestSubWorkflowCompletesParentNode(t *testing.T) {
+	ctx := context.Background()
+	a, _ := newTestApp(t)
+
+	if _, err := a.CreateDef(ctx, "test", "child", "child", `{"nodes":[{"id":"C","type":"condition"}]}`, 1); err != nil {
+		t.Fatalf("create child def: %v", err)
+	}
+	parentDAG := `{"nodes":[{"id":"SUB","type":"subworkflow","config":{"def_code":"child","input_path":"order"}}]}`
+	if _, err := a.CreateDef(ctx, "test", "parent", "parent", parentDAG, 1); err != nil {
+		t.Fatalf("create parent def: %v", err)
+	}
+
+	parentID, err := a.StartWorkflow(ctx, "parent", map[string]interface{}{
+		"order": map[string]interface{}{"sku": "A-1"},
+	}, "test")
+	if err != nil {
+		t.Fatalf("start parent: %v", err)
+	}
+
+	parent, err := a.GetInstance(ctx, parentID)
+	if err != nil {
+		t.Fatalf("get parent: %v", err)
+	}
+	if parent.Status != model.InstanceStatusCompleted {
+		t.Fatalf("parent status = %s, want COMPLETED —— 子实例终态未回报父节点", parent.Status)
+	}
+	data, _, err := parseWorkflowState(parent.CurrentState)
+	if err != nil {
+		t.Fatalf("parse parent state: %v", err)
+	}
+	if data["sku"] != "A-1" {
+		t.Fatalf("parent data = %v, want sku=A-1 合并自子实例输出", data)
+	}
+
+	trail, err := a.GetExecutionTrail(ctx, parentID)
+	if err != nil {
+		t.Fatalf("get trail: %v", err)
+	}
+	if len(trail.Children) != 1 || trail.Children[0].NodeID != "SUB" {
+		t.Fatalf("trail children = %+v, want 1 child for SUB", trail.Children)
+	}
+	child, err := a.GetInstance(ctx, trail.Children[0].InstanceID)
+	if err != nil {
+		t.Fatalf("get child: %v", err)
+	}
+	if child.ParentInstanceID != parentID || child.ParentNodeID != "SUB" {
+		t.Fatalf("child parent link = (%d, %s), want (%d, SUB)", child.ParentInstanceID, child.ParentNodeID, parentID)
+	}
+}
+
+// 回环或回滚会让同一父节点多次拉起子实例；只有最近一次拉起的子实例有权回报，
+// 否则旧子实例的迟到结果会覆盖新一轮的执行。
+func TestNotifyParentIgnoresStaleChild(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+
+	parent := &model.WorkflowInstanceModel{
+		DefID: 1, DefVersion: 1, Status: model.InstanceStatusRunning,
+		InitialState: `{"data":{},"_sys":{}}`, CurrentState: `{"data":{},"_sys":{}}`,
+		ActiveNodeIDs: `["SUB"]`,
+	}
+	if err := db.Create(parent).Error; err != nil {
+		t.Fatalf("create parent: %v", err)
+	}
+	newChild := func() *model.WorkflowInstanceModel {
+		c := &model.WorkflowInstanceModel{
+			DefID: 2, DefVersion: 1, Status: model.InstanceStatusCompleted,
+			InitialState: `{"data":{},"_sys":{}}`, CurrentState: `{"data":{"stale":true},"_sys":{}}`,
+			ActiveNodeIDs: `[]`, ParentInstanceID: parent.ID, ParentNodeID: "SUB",
+		}
+		if err := db.Create(c).Error; err != nil {
+			t.Fatalf("create child: %v", err)
+		}
+		return c
+	}
+	stale := newChild()
+	_ = newChild()
+
+	if err := a.notifyParentOfChildTerminal(ctx, stale); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	got, err := a.GetInstance(ctx, parent.ID)
+	if err != nil {
+		t.Fatalf("reload parent: %v", err)
+	}
+	if got.Status != model.InstanceStatusRunning || got.ActiveNodeIDs != `["SUB"]` {
+		t.Fatalf("parent advanced by stale child: status=%s active=%s", got.Status, got.ActiveNodeIDs)
+	}
+}
@@ -0,0 +1,147 @@
+// 职责：timer 节点——按相对时长或绝对时间延迟后继续推进。
+//
+// 边界：不在进程内计时。到期时间换算为 executor 延时任务的 RunAt，由内部 worker
+// 在到期后领取并成功确认，再经常规完成回调推进本节点，因此定时器在进程重启后
+// 依然有效；任务幂等键沿用 wf_{instance}_node_{node} 前缀，CancelInstance 与
+// RollbackToNode 的按前缀取消天然覆盖定时器。
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/expr-lang/expr"
+	executorCallback "github.com/xsxdot/aio/system/executor/api/callback"
+	executorDto "github.com/xsxdot/aio/system/executor/api/dto"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+)
+
+// triggerTimerNode 提交到期唤醒任务。
+//
+// 节点配置（二选一，同时配置时 until 优先）：
+//   - duration: 相对时长，Go duration 字符串（如 "24h"、"90m"）或秒数
+//   - until: expr 表达式，基于 state 求值得到绝对时间；支持 time、RFC3339 字符串或 Unix 秒
+//
+// 到期时间已过去时立即唤醒。
+func (a *App) triggerTimerNode(ctx context.Context, instance *model.WorkflowInstanceModel, node *model.Node, env string) error {
+	data, _, err := parseWorkflowState(instance.CurrentState)
+	if err != nil {
+		return a.err.New("解析状态失败", err)
+	}
+	fireAt, err := resolveTimerFireAt(node.Config, data, time.Now())
+	if err != nil {
+		return a.err.New("计算 timer 节点到期时间失败", err)
+	}
+
+	argsBytes, _ := json.Marshal(map[string]interface{}{
+		"instance_id": instance.ID,
+		"node_id":     node.ID,
+		"fire_at":     fireAt.Format(time.RFC3339),
+	})
+	callbackDataBytes, _ := json.Marshal(map[string]interface{}{"instance_id": instance.ID, "node_id": node.ID, "env": env})
+	dedupKey := nodeDedupPrefix(instance.ID, node.ID) + strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	_, err = a.ExecutorClient.SubmitJob(ctx, &executorDto.SubmitJobInput{
+		Env:              env,
+		TargetService:    executorCallback.InternalTargetService,
+		Method:           executorCallback.MethodTimerFired,
+		ArgsJSON:         string(argsBytes),
+		RunAt:            fireAt.Unix(),
+		MaxAttempts:      3,
+		Priority:         0,
+		DedupKey:         dedupKey,
+		RetryBackoffType: executorDto.RetryBackoffExponential,
+		Source:           "workflow",
+		CallbackData:     string(callbackDataBytes),
+	})
+	if err != nil {
+		return a.err.New("提交定时唤醒任务到Executor失败", err)
+	}
+	a.log.WithField("instance_id", instance.ID).
+		WithField("node_id", node.ID).
+		WithField("fire_at", fireAt.Format(time.RFC3339)).
+		Debug("timer 节点已登记到期唤醒")
+	return nil
+}
+
+// resolveTimerFireAt 根据节点配置计算到期时间，结果早于 now 时返回 now
+func resolveTimerFireAt(conf map[string]interface{}, data map[string]interface{}, now time.Time) (time.Time, error) {
+	var fireAt time.Time
+	if untilExpr, ok := conf["until"].(string); ok && untilExpr != "" {
+		env := map[string]interface{}{"state": filterStateForExpr(data)}
+		program, err := expr.Compile(untilExpr, expr.Env(env))
+		if err != nil {
+			return time.Time{}, err
+		}
+		out, err := expr.Run(program, env)
+		if err != nil {
+			return time.Time{}, err
+		}
+		fireAt, err = toTimerTime(out)
+		if err != nil {
+			return time.Time{}, err
+		}
+	} else {
+		d, err := toTimerDuration(conf["duration"])
+		if err != nil {
+			return time.Time{}, err
+		}
+		fireAt = now.Add(d)
+	}
+	if fireAt.Before(now) {
+		return now, nil
+	}
+	return fireAt, nil
+}
+
+// toTimerDuration 解析 duration 配置：字符串按 Go duration 解析，数字按秒
+func toTimerDuration(v interface{}) (time.Duration, error) {
+	switch d := v.(type) {
+	case string:
+		parsed, err := time.ParseDuration(d)
+		if err != nil {
+			return 0, fmt.Errorf("duration 格式无效: %w", err)
+		}
+		if parsed < 0 {
+			return 0, fmt.Errorf("duration 不能为负数")
+		}
+		return parsed, nil
+	case float64:
+		if d < 0 {
+			return 0, fmt.Errorf("duration 不能为负数")
+		}
+		return time.Duration(d * float64(time.Second)), nil
+	case int:
+		if d < 0 {
+			return 0, fmt.Errorf("duration 不能为负数")
+		}
+		return time.Duration(d) * time.Second, nil
+	case nil:
+		return 0, fmt.Errorf("timer 节点需配置 duration 或 until")
+	}
+	return 0, fmt.Errorf("duration 类型无效: %T", v)
+}
+
+// toTimerTime 将 until 表达式结果转换为时间
+func toTimerTime(v interface{}) (time.Time, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, nil
+	case string:
+		parsed, err := time.Parse(time.RFC3339, t)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("until 结果不是 RFC3339 时间: %w", err)
+		}
+		return parsed, nil
+	case float64:
+		return time.Unix(int64(t), 0), nil
+	case int:
+		return time.Unix(int64(t), 0), nil
+	case int64:
+		return time.Unix(t, 0), nil
+	}
+	return time.Time{}, fmt.Errorf("until 结果类型无效: %T", v)
+}
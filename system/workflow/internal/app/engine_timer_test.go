@@ -0,0 +1,64 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+// duration 支持 Go duration 字符串与秒数两种写法，结果相对 now 偏移。
+func TestResolveTimerFireAtDuration(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		conf map[string]interface{}
+		want time.Time
+	}{
+		{map[string]interface{}{"duration": "24h"}, now.Add(24 * time.Hour)},
+		{map[string]interface{}{"duration": float64(90)}, now.Add(90 * time.Second)},
+	}
+	for _, c := range cases {
+		got, err := resolveTimerFireAt(c.conf, nil, now)
+		if err != nil {
+			t.Fatalf("conf=%v: %v", c.conf, err)
+		}
+		if !got.Equal(c.want) {
+			t.Fatalf("conf=%v: got %s, want %s", c.conf, got, c.want)
+		}
+	}
+}
+
+// until 基于 state 求绝对时间；已过去的时间必须钳到 now，
+// 否则 RunAt 落在过去虽然也会被立即领取，但日志里的 fire_at 会误导排障。
+func TestResolveTimerFireAtUntil(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	data := map[string]interface{}{
+		"publish_at": "2026-01-02T08:00:00Z",
+		"past_at":    float64(now.Add(-time.Hour).Unix()),
+	}
+
+	got, err := resolveTimerFireAt(map[string]interface{}{"until": "state.publish_at"}, data, now)
+	if err != nil {
+		t.Fatalf("until string: %v", err)
+	}
+	if want := time.Date(2026, 1, 2, 8, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("until string: got %s, want %s", got, want)
+	}
+
+	got, err = resolveTimerFireAt(map[string]interface{}{"until": "state.past_at"}, data, now)
+	if err != nil {
+		t.Fatalf("until past: %v", err)
+	}
+	if !got.Equal(now) {
+		t.Fatalf("until past: got %s, want now %s", got, now)
+	}
+}
+
+// 两个字段都缺失或 duration 为负时必须报错，而不是静默立即唤醒。
+func TestResolveTimerFireAtRejectsInvalidConfig(t *testing.T) {
+	now := time.Now()
+	if _, err := resolveTimerFireAt(map[string]interface{}{}, nil, now); err == nil {
+		t.Fatal("缺少 duration/until 未报错")
+	}
+	if _, err := resolveTimerFireAt(map[string]interface{}{"duration": "-1h"}, nil, now); err == nil {
+		t.Fatal("负数 duration 未报错")
+	}
+}
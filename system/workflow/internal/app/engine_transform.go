@@ -0,0 +1,79 @@
+// 职责：transform 节点——在引擎内用 expr 表达式整理状态（改名、汇总、过滤数组等），
+// 把结果写到声明的状态路径后立即继续，与 condition 节点一样不经过 executor。
+//
+// 边界：表达式在节点推进事务内、基于加锁读到的最新状态求值，环境与边条件一致
+// （state 经 filterStateForExpr 过滤敏感前缀字段），同一节点的各表达式看到的是同一份
+// 状态快照，彼此不可见。每个表达式受 AST 节点数、运行期预算与求值时长限制；
+// 任一表达式失败时节点按失败处理（走 error 边，无 error 边则实例 FAILED）。
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+)
+
+const (
+	// transformMaxNodes 单个表达式的 AST 节点上限，限制表达式规模
+	transformMaxNodes = 500
+	// transformMemoryBudget 单个表达式的运行期预算，按分配与迭代元素计，限制遍历步数
+	transformMemoryBudget = 100000
+	// transformEvalTimeout 单个表达式的求值时长上限
+	transformEvalTimeout = 200 * time.Millisecond
+)
+
+// transformAssignments 读取 transform 节点的 assign 配置：键为状态路径（点分），值为表达式
+func transformAssignments(conf map[string]interface{}) (map[string]interface{}, bool) {
+	return nodeMapping(conf, "assign")
+}
+
+// evalTransformNode 按 assign 求值，返回待写入状态的对象（按路径展开）
+func evalTransformNode(conf map[string]interface{}, data map[string]interface{}) (map[string]interface{}, error) {
+	assign, ok := transformAssignments(conf)
+	if !ok {
+		return nil, fmt.Errorf("transform 节点缺少 assign 配置")
+	}
+	env := map[string]interface{}{"state": filterStateForExpr(data)}
+	out := make(map[string]interface{}, len(assign))
+	for path, raw := range assign {
+		code, isStr := raw.(string)
+		if !isStr || code == "" {
+			return nil, fmt.Errorf("assign.%s 必须是非空表达式", path)
+		}
+		v, err := evalSandboxedExpr(code, env)
+		if err != nil {
+			return nil, fmt.Errorf("assign.%s 求值失败: %w", path, err)
+		}
+		setValueAtPath(out, path, v)
+	}
+	return out, nil
+}
+
+// evalSandboxedExpr 在节点数、运行期预算与时长限制下求值表达式。
+// 超时后不等待求值结束：运行期预算保证残留的求值也会很快退出
+func evalSandboxedExpr(code string, env map[string]interface{}) (interface{}, error) {
+	program, err := expr.Compile(code, expr.Env(env), expr.MaxNodes(transformMaxNodes))
+	if err != nil {
+		return nil, err
+	}
+	type evalResult struct {
+		value interface{}
+		err   error
+	}
+	done := make(chan evalResult, 1)
+	go func() {
+		machine := vm.VM{MemoryBudget: transformMemoryBudget}
+		v, err := machine.Run(program, env)
+		done <- evalResult{value: v, err: err}
+	}()
+	timer := time.NewTimer(transformEvalTimeout)
+	defer timer.Stop()
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-timer.C:
+		return nil, fmt.Errorf("求值超过 %s", transformEvalTimeout)
+	}
+}
@@ -0,0 +1,86 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// transform 节点不经过 executor：启动后立即按 assign 写入声明的路径（同一顶层键下的
+// 其他字段保留），随即派发下游；敏感前缀字段对表达式不可见。
+func TestTransformNodeShapesStateInEngine(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+	dagJSON := `{"nodes":[
+		{"id":"T","type":"transform","config":{"assign":{
+			"order.total":"sum(map(state.order.items, #.price * #.qty))",
+			"customer_name":"state.customer.name",
+			"paid_items":"filter(state.order.items, #.paid)",
+			"leaked":"state.secret_key ?? 'hidden'"
+		}}},
+		{"id":"B","type":"task","config":{"service":"svc","method":"b"}}
+	],"edges":[{"from":"T","to":"B"}]}`
+	if _, err := a.CreateDef(ctx, "test", "transform_def", "transform", dagJSON, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	id, err := a.StartWorkflow(ctx, "transform_def", map[string]interface{}{
+		"order": map[string]interface{}{"id": "O-1", "items": []interface{}{
+			map[string]interface{}{"price": 10, "qty": 2, "paid": true},
+			map[string]interface{}{"price": 5, "qty": 1, "paid": false},
+		}},
+		"customer":   map[string]interface{}{"name": "alice"},
+		"secret_key": "k-1",
+	}, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if countNodeJobs(t, db, id, "T", "") != 0 || countNodeJobs(t, db, id, "B", "") != 1 {
+		t.Fatal("transform 节点应在引擎内完成并立即派发 B")
+	}
+	inst, _ := a.GetInstance(ctx, id)
+	data, _, _ := parseWorkflowState(inst.CurrentState)
+	order, _ := data["order"].(map[string]interface{})
+	if order["total"] != float64(25) || order["id"] != "O-1" {
+		t.Fatalf("order = %v, want total=25 且保留 id", order)
+	}
+	if paid, _ := data["paid_items"].([]interface{}); len(paid) != 1 || data["customer_name"] != "alice" {
+		t.Fatalf("paid_items=%v customer_name=%v", data["paid_items"], data["customer_name"])
+	}
+	if data["leaked"] != "hidden" {
+		t.Fatalf("敏感字段对表达式可见: leaked=%v", data["leaked"])
+	}
+}
+
+// 超出运行期预算的表达式按节点失败走 error 边，状态不被写入；定义校验提前发现编译错误
+func TestTransformNodeLimitsAndErrors(t *testing.T) {
+	ctx := context.Background()
+	a, db := newTestApp(t)
+	dagJSON := `{"nodes":[
+		{"id":"T","type":"transform","config":{"assign":{"big":"len(filter(1..10000000, # % 2 == 0))"}}},
+		{"id":"B","type":"task","config":{"service":"svc","method":"b"}},
+		{"id":"H","type":"task","config":{"service":"svc","method":"h"}}
+	],"edges":[{"from":"T","to":"B"},{"from":"T","to":"H","type":"error"}]}`
+	if _, err := a.CreateDef(ctx, "test", "transform_def", "transform", dagJSON, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	id, err := a.StartWorkflow(ctx, "transform_def", nil, "test")
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if countNodeJobs(t, db, id, "H", "") != 1 || countNodeJobs(t, db, id, "B", "") != 0 {
+		t.Fatal("超限的表达式未走 error 边")
+	}
+	inst, _ := a.GetInstance(ctx, id)
+	data, _, _ := parseWorkflowState(inst.CurrentState)
+	if _, ok := data["big"]; ok {
+		t.Fatalf("失败的结果被写入状态: %v", data)
+	}
+	if msg, _ := data["error_msg"].(string); !strings.Contains(msg, "assign.big") {
+		t.Fatalf("error_msg = %q, want 定位到 assign.big", msg)
+	}
+
+	res := lintDAG(`{"nodes":[{"id":"T","type":"transform","config":{"assign":{"x":"state.a +"}}}]}`)
+	if issue := findLintIssue(res, LintCodeBadExpression, "T"); issue == nil || issue.Field != "config.assign.x" {
+		t.Fatalf("未发现 assign 表达式错误: %+v", res.Issues)
+	}
+}
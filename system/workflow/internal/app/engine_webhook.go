@@ -0,0 +1,359 @@
+// 职责：Webhook 触发器——外部系统（代码托管、支付回调等）无需 SDK 凭证，向公开地址
+// 推送请求即可启动工作流：校验签名/令牌，按映射构造初始状态，按去重键吸收重试。
+//
+// 边界：只负责「受理请求 → 启动实例」，不回写外部系统。去重以 (触发器, 去重键) 为粒度，
+// 与实例启动在同一事务内登记；启动失败时登记一并回滚，外部系统的重试仍可成功。
+package app
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/expr-lang/expr"
+	"github.com/xsxdot/aio/base"
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"gorm.io/gorm"
+)
+
+// 类型别名，供 api/client 等层使用
+type WorkflowTriggerModel = model.WorkflowTriggerModel
+
+// maxDedupKeyLen 去重键列长度；超长的键以其 SHA-256 摘要代替
+const maxDedupKeyLen = 200
+
+// TriggerInput 创建/更新 Webhook 触发器的参数
+type TriggerInput struct {
+	Code            string
+	Name            string
+	Env             string
+	DefCode         string
+	Disabled        bool
+	AuthType        string // hmac / token
+	Secret          string // 更新时为空表示保留原值
+	SignatureHeader string
+	SignaturePrefix string
+	BodyMapping     map[string]interface{} // 状态路径 -> 表达式，可引用 body/headers/query
+	DedupHeader     string
+	DedupPath       string
+}
+
+// WebhookRequest 一次 Webhook 投递，由 HTTP 层从原始请求中提取
+type WebhookRequest struct {
+	Body    []byte
+	Headers map[string]string // 键统一为小写
+	Query   map[string]string
+}
+
+// header 按名称（大小写不敏感）读取请求头
+func (r *WebhookRequest) header(name string) string {
+	return r.Headers[strings.ToLower(name)]
+}
+
+// validateTriggerInput 补默认值并校验触发器参数；isUpdate 时允许 Secret 为空（保留原值）
+func (a *App) validateTriggerInput(ctx context.Context, in *TriggerInput, isUpdate bool) error {
+	if in.Env == "" {
+		in.Env = base.ENV
+	}
+	if in.Code == "" || in.Name == "" || in.DefCode == "" || in.SignatureHeader == "" {
+		return a.err.New("code、name、def_code、signature_header 不能为空", nil).WithCode(errorc.ErrorCodeValid)
+	}
+	if strings.ContainsAny(in.Code, "/?#") {
+		return a.err.New("code 不能包含 / ? #", nil).WithCode(errorc.ErrorCodeValid)
+	}
+	switch model.WebhookAuthType(in.AuthType) {
+	case model.WebhookAuthHMAC, model.WebhookAuthToken:
+	default:
+		return a.err.New("auth_type 只能是 hmac/token", nil).WithCode(errorc.ErrorCodeValid)
+	}
+	if in.Secret == "" && !isUpdate {
+		return a.err.New("secret 不能为空", nil).WithCode(errorc.ErrorCodeValid)
+	}
+	if in.DedupHeader != "" && in.DedupPath != "" {
+		return a.err.New("dedup_header 与 dedup_path 只能配置一个", nil).WithCode(errorc.ErrorCodeValid)
+	}
+	for path, raw := range in.BodyMapping {
+		code, ok := raw.(string)
+		if !ok || code == "" {
+			return a.err.New(fmt.Sprintf("body_mapping.%s 必须是非空表达式", path), nil).WithCode(errorc.ErrorCodeValid)
+		}
+		if _, err := expr.Compile(code); err != nil {
+			return a.err.New(fmt.Sprintf("body_mapping.%s 表达式无效: %v", path, err), err).WithCode(errorc.ErrorCodeValid)
+		}
+	}
+	if _, err := a.GetDefByCodeAndVersion(ctx, in.Env, in.DefCode, 0); err != nil {
+		return a.err.New("触发器引用的工作流定义不存在", err).WithCode(errorc.ErrorCodeValid)
+	}
+	return nil
+}
+
+// encodeBodyMapping 序列化映射配置，未配置时存空串
+func encodeBodyMapping(m map[string]interface{}) (string, error) {
+	if len(m) == 0 {
+		return "", nil
+	}
+	b, err := json.Marshal(m)
+	return string(b), err
+}
+
+// CreateTrigger 创建 Webhook 触发器，编码全局唯一
+func (a *App) CreateTrigger(ctx context.Context, in *TriggerInput) (*model.WorkflowTriggerModel, error) {
+	if err := a.validateTriggerInput(ctx, in, false); err != nil {
+		return nil, err
+	}
+	if existing, err := a.TriggerService.FindByCode(ctx, in.Code); err == nil && existing != nil {
+		return nil, a.err.New("触发器编码已存在: "+in.Code, nil).WithCode(errorc.ErrorCodeValid)
+	} else if err != nil && !errorc.IsNotFound(err) {
+		return nil, err
+	}
+	mapping, err := encodeBodyMapping(in.BodyMapping)
+	if err != nil {
+		return nil, a.err.New("序列化 body_mapping 失败", err)
+	}
+	trigger := &model.WorkflowTriggerModel{
+		Code:            in.Code,
+		Name:            in.Name,
+		Env:             in.Env,
+		DefCode:         in.DefCode,
+		Disabled:        in.Disabled,
+		AuthType:        model.WebhookAuthType(in.AuthType),
+		Secret:          in.Secret,
+		SignatureHeader: in.SignatureHeader,
+		SignaturePrefix: in.SignaturePrefix,
+		BodyMapping:     mapping,
+		DedupHeader:     in.DedupHeader,
+		DedupPath:       in.DedupPath,
+	}
+	// 上面的预检只为给出友好提示，并发创建同一编码由唯一索引兜底
+	created, err := a.TriggerService.CreateIfAbsent(ctx, trigger)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, a.err.New("触发器编码已存在: "+in.Code, nil).WithCode(errorc.ErrorCodeValid)
+	}
+	return trigger, nil
+}
+
+// UpdateTrigger 整体更新触发器；Secret 为空时保留原值，编码不可修改（外部系统已配置该地址）
+func (a *App) UpdateTrigger(ctx context.Context, id int64, in *TriggerInput) (*model.WorkflowTriggerModel, error) {
+	trigger, err := a.TriggerService.FindById(ctx, id)
+	if err != nil {
+		return nil, a.err.New("获取触发器失败", err)
+	}
+	in.Code = trigger.Code
+	if err := a.validateTriggerInput(ctx, in, true); err != nil {
+		return nil, err
+	}
+	mapping, err := encodeBodyMapping(in.BodyMapping)
+	if err != nil {
+		return nil, a.err.New("序列化 body_mapping 失败", err)
+	}
+	fields := map[string]interface{}{
+		"name":             in.Name,
+		"env":              in.Env,
+		"def_code":         in.DefCode,
+		"disabled":         in.Disabled,
+		"auth_type":        in.AuthType,
+		"signature_header": in.SignatureHeader,
+		"signature_prefix": in.SignaturePrefix,
+		"body_mapping":     gorm.Expr("NULL"),
+		"dedup_header":     in.DedupHeader,
+		"dedup_path":       in.DedupPath,
+	}
+	if mapping != "" {
+		fields["body_mapping"] = mapping
+	}
+	if in.Secret != "" {
+		fields["secret"] = in.Secret
+	}
+	if err := a.TriggerService.UpdateFields(ctx, id, fields); err != nil {
+		return nil, err
+	}
+	return a.TriggerService.FindById(ctx, id)
+}
+
+// DeleteTrigger 删除触发器，之后对其公开地址的投递返回 404；物理删除，编码可重新用于新触发器
+func (a *App) DeleteTrigger(ctx context.Context, id int64) error {
+	if _, err := a.TriggerService.FindById(ctx, id); err != nil {
+		return a.err.New("获取触发器失败", err)
+	}
+	return a.TriggerService.HardDeleteById(ctx, id)
+}
+
+// GetTrigger 获取触发器（不含 Secret）
+func (a *App) GetTrigger(ctx context.Context, id int64) (*model.WorkflowTriggerModel, error) {
+	return a.TriggerService.FindById(ctx, id)
+}
+
+// ListTriggers 分页列出触发器，env 为空时不限环境
+func (a *App) ListTriggers(ctx context.Context, env string, pageNum, pageSize int32) ([]*model.WorkflowTriggerModel, int64, error) {
+	return a.TriggerService.ListTriggers(ctx, env, pageNum, pageSize)
+}
+
+// HandleWebhook 受理一次 Webhook 投递并启动工作流，返回实例 ID；
+// duplicate=true 表示该去重键已受理过，返回的是当时启动的实例。
+func (a *App) HandleWebhook(ctx context.Context, code string, req *WebhookRequest) (instanceID int64, duplicate bool, err error) {
+	trigger, err := a.TriggerService.FindByCode(ctx, code)
+	if err != nil {
+		if errorc.IsNotFound(err) {
+			return 0, false, a.err.New("触发器不存在: "+code, err).NotFound()
+		}
+		return 0, false, err
+	}
+	if trigger.Disabled {
+		return 0, false, a.err.New("触发器已停用: "+code, nil).NotFound()
+	}
+	if !verifyWebhook(trigger, req) {
+		return 0, false, a.err.New("Webhook 签名校验失败", nil).NoAuth()
+	}
+
+	var body interface{}
+	if len(req.Body) > 0 {
+		if err := json.Unmarshal(req.Body, &body); err != nil {
+			return 0, false, a.err.New("Webhook 请求体不是合法 JSON", err).WithCode(errorc.ErrorCodeValid)
+		}
+	}
+	initialData, err := buildWebhookInitialData(trigger, body, req)
+	if err != nil {
+		return 0, false, a.err.New(err.Error(), err).WithCode(errorc.ErrorCodeValid)
+	}
+	dedupKey, err := webhookDedupKey(trigger, body, req)
+	if err != nil {
+		return 0, false, a.err.New(err.Error(), err).WithCode(errorc.ErrorCodeValid)
+	}
+
+	err = mvc.ExtractDB(ctx, base.DB).Transaction(func(tx *gorm.DB) error {
+		txCtx := mvc.WithTxToContext(ctx, tx)
+		var delivery *model.WorkflowTriggerDeliveryModel
+		if dedupKey != "" {
+			delivery = &model.WorkflowTriggerDeliveryModel{TriggerID: trigger.ID, DedupKey: dedupKey}
+			claimed, err := a.TriggerService.ClaimDelivery(txCtx, delivery)
+			if err != nil {
+				return err
+			}
+			if !claimed {
+				existing, err := a.TriggerService.FindDelivery(txCtx, trigger.ID, dedupKey)
+				if err != nil {
+					return err
+				}
+				instanceID, duplicate = existing.InstanceID, true
+				return nil
+			}
+		}
+		def, err := a.GetDefByCodeAndVersion(txCtx, trigger.Env, trigger.DefCode, 0)
+		if err != nil {
+			return a.err.New("获取工作流定义失败", err)
+		}
+		if instanceID, err = a.startInstance(txCtx, def, initialData, trigger.Env, 0, "", ""); err != nil {
+			return err
+		}
+		if delivery != nil {
+			return a.TriggerService.SetDeliveryInstanceID(txCtx, delivery.ID, instanceID)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, false, err
+	}
+	return instanceID, duplicate, nil
+}
+
+// verifyWebhook 校验签名或令牌，比较均为常量时间
+func verifyWebhook(trigger *model.WorkflowTriggerModel, req *WebhookRequest) bool {
+	provided := strings.TrimSpace(req.header(trigger.SignatureHeader))
+	if provided == "" || trigger.Secret == "" {
+		return false
+	}
+	switch trigger.AuthType {
+	case model.WebhookAuthHMAC:
+		provided = strings.TrimPrefix(provided, trigger.SignaturePrefix)
+		sig, err := hex.DecodeString(provided)
+		if err != nil {
+			return false
+		}
+		mac := hmac.New(sha256.New, []byte(trigger.Secret))
+		mac.Write(req.Body)
+		return hmac.Equal(sig, mac.Sum(nil))
+	case model.WebhookAuthToken:
+		provided = strings.TrimPrefix(provided, trigger.SignaturePrefix)
+		return subtle.ConstantTimeCompare([]byte(provided), []byte(trigger.Secret)) == 1
+	}
+	return false
+}
+
+// buildWebhookInitialData 按 body_mapping 构造初始状态；未配置映射时请求体须为 JSON 对象，整体作为初始状态
+func buildWebhookInitialData(trigger *model.WorkflowTriggerModel, body interface{}, req *WebhookRequest) (map[string]interface{}, error) {
+	if trigger.BodyMapping == "" {
+		if body == nil {
+			return map[string]interface{}{}, nil
+		}
+		obj, ok := body.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("未配置 body_mapping 时请求体必须是 JSON 对象")
+		}
+		return obj, nil
+	}
+	var mapping map[string]interface{}
+	if err := json.Unmarshal([]byte(trigger.BodyMapping), &mapping); err != nil {
+		return nil, fmt.Errorf("触发器 body_mapping 配置无效: %w", err)
+	}
+	headers := make(map[string]interface{}, len(req.Headers))
+	for k, v := range req.Headers {
+		headers[k] = v
+	}
+	query := make(map[string]interface{}, len(req.Query))
+	for k, v := range req.Query {
+		query[k] = v
+	}
+	env := map[string]interface{}{"body": body, "headers": headers, "query": query}
+	data := make(map[string]interface{}, len(mapping))
+	for path, raw := range mapping {
+		code, _ := raw.(string)
+		v, err := evalMappingExpr(code, env)
+		if err != nil {
+			return nil, fmt.Errorf("body_mapping.%s 求值失败: %w", path, err)
+		}
+		setValueAtPath(data, path, v)
+	}
+	return data, nil
+}
+
+// webhookDedupKey 取去重键；触发器配置了去重来源但请求中缺失时视为请求无效，
+// 否则外部系统的重试会绕过去重
+func webhookDedupKey(trigger *model.WorkflowTriggerModel, body interface{}, req *WebhookRequest) (string, error) {
+	var key string
+	switch {
+	case trigger.DedupHeader != "":
+		key = req.header(trigger.DedupHeader)
+		if key == "" {
+			return "", fmt.Errorf("缺少去重请求头 %s", trigger.DedupHeader)
+		}
+	case trigger.DedupPath != "":
+		obj, _ := body.(map[string]interface{})
+		v := getValueAtPath(obj, trigger.DedupPath)
+		if v == nil {
+			return "", fmt.Errorf("请求体缺少去重字段 %s", trigger.DedupPath)
+		}
+		if s, ok := v.(string); ok {
+			key = s
+		} else {
+			b, _ := json.Marshal(v)
+			key = string(b)
+		}
+	default:
+		return "", nil
+	}
+	if len(key) > maxDedupKeyLen {
+		sum := sha256.Sum256([]byte(key))
+		key = hex.EncodeToString(sum[:])
+	}
+	return key, nil
+}
@@ -0,0 +1,150 @@
+package app
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"gorm.io/gorm"
+)
+
+// newWebhookTestApp 在 newTestApp 基础上注册一个单任务定义 hook_def
+func newWebhookTestApp(t *testing.T) (*App, *gorm.DB) {
+	t.Helper()
+	a, db := newTestApp(t)
+	dagJSON := `{"nodes":[{"id":"T","type":"task","config":{"service":"svc","method":"m"}}]}`
+	if _, err := a.CreateDef(context.Background(), "test", "hook_def", "hook_def", dagJSON, 1); err != nil {
+		t.Fatalf("create def: %v", err)
+	}
+	return a, db
+}
+
+func signBody(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// HMAC 校验 + 映射 + 按请求头去重：外部系统重试同一投递时返回首次启动的实例，不再新建。
+func TestWebhookHMACMappingAndDedup(t *testing.T) {
+	ctx := context.Background()
+	a, db := newWebhookTestApp(t)
+	if _, err := a.CreateTrigger(ctx, &TriggerInput{
+		Code: "git_push", Name: "git push", Env: "test", DefCode: "hook_def",
+		AuthType: "hmac", Secret: "s3cret", SignatureHeader: "X-Hub-Signature-256", SignaturePrefix: "sha256=",
+		BodyMapping: map[string]interface{}{"repo.name": "body.repository.name", "event": "headers['x-event']"},
+		DedupHeader: "X-Delivery",
+	}); err != nil {
+		t.Fatalf("create trigger: %v", err)
+	}
+
+	body := []byte(`{"repository":{"name":"aio"},"noise":true}`)
+	req := &WebhookRequest{Body: body, Headers: map[string]string{
+		"x-hub-signature-256": signBody("s3cret", body),
+		"x-event":             "push",
+		"x-delivery":          "d-1",
+	}}
+	first, dup, err := a.HandleWebhook(ctx, "git_push", req)
+	if err != nil || dup {
+		t.Fatalf("first delivery: id=%d dup=%v err=%v", first, dup, err)
+	}
+	second, dup, err := a.HandleWebhook(ctx, "git_push", req)
+	if err != nil || !dup || second != first {
+		t.Fatalf("retry: id=%d dup=%v err=%v, want 重复且返回实例 %d", second, dup, err, first)
+	}
+	var instances int64
+	db.Model(&model.WorkflowInstanceModel{}).Count(&instances)
+	if instances != 1 {
+		t.Fatalf("实例数 = %d, want 1", instances)
+	}
+
+	inst, err := a.GetInstance(ctx, first)
+	if err != nil {
+		t.Fatalf("get instance: %v", err)
+	}
+	data, _, _ := parseWorkflowState(inst.InitialState)
+	repo, _ := data["repo"].(map[string]interface{})
+	if repo["name"] != "aio" || data["event"] != "push" || data["noise"] != nil {
+		t.Fatalf("initial data = %v, want 仅含映射字段", data)
+	}
+
+	// 篡改请求体后签名不再匹配
+	req.Body = []byte(`{"repository":{"name":"evil"}}`)
+	req.Headers["x-delivery"] = "d-2"
+	if _, _, err := a.HandleWebhook(ctx, "git_push", req); err == nil || !isNoAuth(err) {
+		t.Fatalf("篡改后的请求未被拒绝: %v", err)
+	}
+}
+
+func isNoAuth(err error) bool {
+	var e *errorc.Error
+	return errors.As(err, &e) && e.ErrorCode == errorc.ErrorCodeNoAuth
+}
+
+// 配置了去重字段但请求缺失时必须拒绝：静默放行会让外部系统的每次重试都新建实例。
+func TestWebhookTokenAuthRequiresDedupField(t *testing.T) {
+	ctx := context.Background()
+	a, _ := newWebhookTestApp(t)
+	if _, err := a.CreateTrigger(ctx, &TriggerInput{
+		Code: "pay", Name: "pay", Env: "test", DefCode: "hook_def",
+		AuthType: "token", Secret: "tok", SignatureHeader: "Authorization", SignaturePrefix: "Bearer ",
+		DedupPath: "data.id",
+	}); err != nil {
+		t.Fatalf("create trigger: %v", err)
+	}
+	headers := map[string]string{"authorization": "Bearer tok"}
+
+	if _, _, err := a.HandleWebhook(ctx, "pay", &WebhookRequest{Body: []byte(`{"data":{}}`), Headers: headers}); err == nil {
+		t.Fatal("缺少 data.id 的请求未被拒绝")
+	}
+	id, dup, err := a.HandleWebhook(ctx, "pay", &WebhookRequest{Body: []byte(`{"data":{"id":42}}`), Headers: headers})
+	if err != nil || dup || id == 0 {
+		t.Fatalf("deliver: id=%d dup=%v err=%v", id, dup, err)
+	}
+	if _, _, err := a.HandleWebhook(ctx, "pay", &WebhookRequest{Body: []byte(`{"data":{"id":43}}`),
+		Headers: map[string]string{"authorization": "Bearer wrong"}}); !isNoAuth(err) {
+		t.Fatalf("错误令牌未被拒绝: %v", err)
+	}
+}
+
+// 编码由唯一索引兜底：并发创建时越过「先查」的后到者在写入时落空并得到校验错误，
+// 否则同一公开地址会对应两个触发器；删除为物理删除，编码随即可以复用。
+func TestTriggerCodeUniqueAndReusableAfterDelete(t *testing.T) {
+	ctx := context.Background()
+	a, db := newWebhookTestApp(t)
+	in := func() *TriggerInput {
+		return &TriggerInput{Code: "dup", Name: "dup", Env: "test", DefCode: "hook_def",
+			AuthType: "token", Secret: "tok", SignatureHeader: "Authorization"}
+	}
+	first, err := a.CreateTrigger(ctx, in())
+	if err != nil {
+		t.Fatalf("create trigger: %v", err)
+	}
+	// 模拟与首次创建并发、已通过存在性预检的另一次写入
+	racer := &model.WorkflowTriggerModel{Code: "dup", Name: "racer", DefCode: "hook_def",
+		AuthType: model.WebhookAuthToken, Secret: "x", SignatureHeader: "Authorization"}
+	if created, err := a.TriggerService.CreateIfAbsent(ctx, racer); err != nil || created {
+		t.Fatalf("重复编码写入 created=%v err=%v", created, err)
+	}
+	var n int64
+	db.Model(&model.WorkflowTriggerModel{}).Where("code = ?", "dup").Count(&n)
+	if n != 1 {
+		t.Fatalf("编码 dup 的触发器数 = %d, want 1", n)
+	}
+	var e *errorc.Error
+	if _, err := a.CreateTrigger(ctx, in()); !errors.As(err, &e) || e.ErrorCode != errorc.ErrorCodeValid {
+		t.Fatalf("重复编码未返回校验错误: %v", err)
+	}
+
+	if err := a.DeleteTrigger(ctx, first.ID); err != nil {
+		t.Fatalf("delete trigger: %v", err)
+	}
+	if _, err := a.CreateTrigger(ctx, in()); err != nil {
+		t.Fatalf("删除后复用编码失败: %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package dao
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"github.com/xsxdot/gokit/logger"
+
+	"gorm.io/gorm"
+)
+
+type WorkflowApprovalDao struct {
+	mvc.IBaseDao[model.WorkflowApprovalModel]
+	log *logger.Log
+	err *errorc.ErrorBuilder
+	db  *gorm.DB
+}
+
+func NewWorkflowApprovalDao(db *gorm.DB, log *logger.Log) *WorkflowApprovalDao {
+	return &WorkflowApprovalDao{
+		IBaseDao: mvc.NewGormDao[model.WorkflowApprovalModel](db),
+		log:      log,
+		err:      errorc.NewErrorBuilder("WorkflowApprovalDao"),
+		db:       db,
+	}
+}
+
+// CreateWithAssignees 写入审批待办及其审批人
+func (d *WorkflowApprovalDao) CreateWithAssignees(ctx context.Context, approval *model.WorkflowApprovalModel, assignees []*model.WorkflowApprovalAssigneeModel) error {
+	return mvc.ExtractDB(ctx, d.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(approval).Error; err != nil {
+			return err
+		}
+		if len(assignees) == 0 {
+			return nil
+		}
+		for _, as := range assignees {
+			as.ApprovalID = approval.ID
+		}
+		return tx.Create(&assignees).Error
+	})
+}
+
+// ListAssignees 列出审批待办的审批人
+func (d *WorkflowApprovalDao) ListAssignees(ctx context.Context, approvalID int64) ([]*model.WorkflowApprovalAssigneeModel, error) {
+	var items []*model.WorkflowApprovalAssigneeModel
+	err := mvc.ExtractDB(ctx, d.db).Where("approval_id = ?", approvalID).Order("id asc").Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// UpdateFields 按列更新审批待办（零值也会写入）
+func (d *WorkflowApprovalDao) UpdateFields(ctx context.Context, id int64, fields map[string]interface{}) error {
+	return mvc.ExtractDB(ctx, d.db).Model(&model.WorkflowApprovalModel{}).Where("id = ?", id).Updates(fields).Error
+}
+
+// CancelPending 将实例中仍待决定的审批作废，nodeID 为空时不限节点
+func (d *WorkflowApprovalDao) CancelPending(ctx context.Context, instanceID int64, nodeID string) error {
+	db := mvc.ExtractDB(ctx, d.db).Model(&model.WorkflowApprovalModel{}).
+		Where("instance_id = ? AND status = ?", instanceID, model.ApprovalStatusPending)
+	if nodeID != "" {
+		db = db.Where("node_id = ?", nodeID)
+	}
+	return db.Updates(map[string]interface{}{
+		"status":     model.ApprovalStatusCanceled,
+		"decided_at": time.Now(),
+	}).Error
+}
+
+// ListInbox 分页列出某管理员可处理的待办：实例仍在运行、等待或暂停中，审批人包含该管理员或其任一角色
+// （未指定审批人的待办人人可见），且该管理员尚未做出决定
+func (d *WorkflowApprovalDao) ListInbox(ctx context.Context, adminID int64, roles []string, pageNum, pageSize int32) ([]*model.WorkflowApprovalModel, int64, error) {
+	if pageNum <= 0 {
+		pageNum = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	db := mvc.ExtractDB(ctx, d.db)
+	newDB := func() *gorm.DB { return db.Session(&gorm.Session{NewDB: true}) }
+
+	activeInstances := newDB().Model(&model.WorkflowInstanceModel{}).Select("id").
+		Where("status IN ?", []model.WorkflowInstanceStatus{model.InstanceStatusRunning, model.InstanceStatusWaiting, model.InstanceStatusPaused})
+	assigned := newDB().Model(&model.WorkflowApprovalAssigneeModel{}).Select("approval_id").
+		Where("(kind = ? AND value = ?)", model.ApprovalAssigneeAdmin, strconv.FormatInt(adminID, 10))
+	if len(roles) > 0 {
+		assigned = assigned.Or("(kind = ? AND value IN ?)", model.ApprovalAssigneeRole, roles)
+	}
+	anyAssignee := newDB().Model(&model.WorkflowApprovalAssigneeModel{}).Select("approval_id")
+	decided := newDB().Model(&model.WorkflowApprovalDecisionModel{}).Select("approval_id").Where("admin_id = ?", adminID)
+
+	q := db.Model(&model.WorkflowApprovalModel{}).
+		Where("status = ? AND instance_id IN (?)", model.ApprovalStatusPending, activeInstances).
+		Where("id IN (?) OR id NOT IN (?)", assigned, anyAssignee).
+		Where("id NOT IN (?)", decided)
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var items []*model.WorkflowApprovalModel
+	offset := (pageNum - 1) * pageSize
+	if err := q.Order("id desc").Offset(int(offset)).Limit(int(pageSize)).Find(&items).Error; err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
@@ -0,0 +1,38 @@
+package dao
+
+import (
+	"context"
+
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"github.com/xsxdot/gokit/logger"
+
+	"gorm.io/gorm"
+)
+
+type WorkflowApprovalDecisionDao struct {
+	mvc.IBaseDao[model.WorkflowApprovalDecisionModel]
+	log *logger.Log
+	err *errorc.ErrorBuilder
+	db  *gorm.DB
+}
+
+func NewWorkflowApprovalDecisionDao(db *gorm.DB, log *logger.Log) *WorkflowApprovalDecisionDao {
+	return &WorkflowApprovalDecisionDao{
+		IBaseDao: mvc.NewGormDao[model.WorkflowApprovalDecisionModel](db),
+		log:      log,
+		err:      errorc.NewErrorBuilder("WorkflowApprovalDecisionDao"),
+		db:       db,
+	}
+}
+
+// ListByApproval 按决定先后列出审批待办的全部决定
+func (d *WorkflowApprovalDecisionDao) ListByApproval(ctx context.Context, approvalID int64) ([]*model.WorkflowApprovalDecisionModel, error) {
+	var items []*model.WorkflowApprovalDecisionModel
+	err := mvc.ExtractDB(ctx, d.db).Where("approval_id = ?", approvalID).Order("id asc").Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,165 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"github.com/xsxdot/gokit/logger"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ExpiredInstanceFilter 保留期满实例的筛选条件
+type ExpiredInstanceFilter struct {
+	Env      string
+	Statuses []model.WorkflowInstanceStatus
+	// Before 实例 updated_at 早于该时间（进入终态后不再更新）
+	Before time.Time
+	// DefIDs 非空时只取这些定义的实例；ExcludeDefIDs 非空时排除这些定义的实例
+	DefIDs        []int64
+	ExcludeDefIDs []int64
+}
+
+// WorkflowArchiveDao 实例归档与清理：筛选保留期满的实例、写归档表、物理删除实例及其附属记录
+type WorkflowArchiveDao struct {
+	mvc.IBaseDao[model.WorkflowInstanceArchiveModel]
+	log *logger.Log
+	err *errorc.ErrorBuilder
+	db  *gorm.DB
+}
+
+func NewWorkflowArchiveDao(db *gorm.DB, log *logger.Log) *WorkflowArchiveDao {
+	return &WorkflowArchiveDao{
+		IBaseDao: mvc.NewGormDao[model.WorkflowInstanceArchiveModel](db),
+		log:      log,
+		err:      errorc.NewErrorBuilder("WorkflowArchiveDao"),
+		db:       db,
+	}
+}
+
+func (d *WorkflowArchiveDao) expiredQuery(ctx context.Context, f *ExpiredInstanceFilter) *gorm.DB {
+	db := mvc.ExtractDB(ctx, d.db).Model(&model.WorkflowInstanceModel{}).
+		Where("env = ? AND status IN ? AND updated_at < ?", f.Env, f.Statuses, f.Before)
+	if len(f.DefIDs) > 0 {
+		db = db.Where("def_id IN ?", f.DefIDs)
+	}
+	if len(f.ExcludeDefIDs) > 0 {
+		db = db.Where("def_id NOT IN ?", f.ExcludeDefIDs)
+	}
+	return db
+}
+
+// CountExpired 统计保留期满的实例数及其检查点数
+func (d *WorkflowArchiveDao) CountExpired(ctx context.Context, f *ExpiredInstanceFilter) (instances, checkpoints int64, err error) {
+	if err = d.expiredQuery(ctx, f).Count(&instances).Error; err != nil || instances == 0 {
+		return instances, 0, err
+	}
+	err = mvc.ExtractDB(ctx, d.db).Model(&model.WorkflowCheckpointModel{}).
+		Where("instance_id IN (?)", d.expiredQuery(ctx, f).Select("id")).Count(&checkpoints).Error
+	return instances, checkpoints, err
+}
+
+// LockExpiredBatch 按 ID 顺序锁定一批保留期满的实例，必须在事务内使用。
+//
+// SKIP LOCKED 跳过其他 aio 进程正在处理的批次以及引擎正持锁推进的实例，多个进程同时
+// 执行清理时各取不同的行，不会相互等待或重复处理。
+func (d *WorkflowArchiveDao) LockExpiredBatch(ctx context.Context, f *ExpiredInstanceFilter, limit int) ([]*model.WorkflowInstanceModel, error) {
+	var items []*model.WorkflowInstanceModel
+	err := d.expiredQuery(ctx, f).Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Order("id asc").Limit(limit).Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ListCheckpoints 列出实例的全部检查点
+func (d *WorkflowArchiveDao) ListCheckpoints(ctx context.Context, instanceIDs []int64) ([]*model.WorkflowCheckpointModel, error) {
+	var items []*model.WorkflowCheckpointModel
+	if err := mvc.ExtractDB(ctx, d.db).Where("instance_id IN ?", instanceIDs).Order("id asc").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ListDefIDs 列出 env 下这些定义编码的所有版本 ID
+func (d *WorkflowArchiveDao) ListDefIDs(ctx context.Context, env string, codes []string) ([]int64, error) {
+	var ids []int64
+	err := mvc.ExtractDB(ctx, d.db).Model(&model.WorkflowDefModel{}).
+		Where("env = ? AND code IN ?", env, codes).Pluck("id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// Archive 把实例与检查点写入归档表，保留原 ID 与时间。
+// 已归档过的行（上一轮写入后清理未提交）按主键跳过
+func (d *WorkflowArchiveDao) Archive(ctx context.Context, instances []*model.WorkflowInstanceModel, checkpoints []*model.WorkflowCheckpointModel, archivedAt time.Time) error {
+	archivedInstances := make([]*model.WorkflowInstanceArchiveModel, 0, len(instances))
+	for _, inst := range instances {
+		archivedInstances = append(archivedInstances, &model.WorkflowInstanceArchiveModel{
+			ID:                     inst.ID,
+			DefID:                  inst.DefID,
+			DefVersion:             inst.DefVersion,
+			Env:                    inst.Env,
+			Status:                 inst.Status,
+			InitialState:           inst.InitialState,
+			CurrentState:           inst.CurrentState,
+			ActiveNodeIDs:          inst.ActiveNodeIDs,
+			ParentInstanceID:       inst.ParentInstanceID,
+			ParentNodeID:           inst.ParentNodeID,
+			ForkedFromInstanceID:   inst.ForkedFromInstanceID,
+			ForkedFromCheckpointID: inst.ForkedFromCheckpointID,
+			BusinessKey:            inst.BusinessKey,
+			CreatedAt:              inst.CreatedAt,
+			UpdatedAt:              inst.UpdatedAt,
+			ArchivedAt:             archivedAt,
+		})
+	}
+	if len(archivedInstances) > 0 {
+		if err := mvc.ExtractDB(ctx, d.db).Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(archivedInstances, 100).Error; err != nil {
+			return err
+		}
+	}
+	archivedCheckpoints := make([]*model.WorkflowCheckpointArchiveModel, 0, len(checkpoints))
+	for _, cp := range checkpoints {
+		archivedCheckpoints = append(archivedCheckpoints, &model.WorkflowCheckpointArchiveModel{
+			ID:         cp.ID,
+			InstanceID: cp.InstanceID,
+			NodeID:     cp.NodeID,
+			NodeOutput: cp.NodeOutput,
+			StateAfter: cp.StateAfter,
+			CreatedAt:  cp.CreatedAt,
+			ArchivedAt: archivedAt,
+		})
+	}
+	if len(archivedCheckpoints) > 0 {
+		if err := mvc.ExtractDB(ctx, d.db).Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(archivedCheckpoints, 100).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Purge 物理删除实例及其检查点、回调幂等标记、事件流水、信号等待、业务键占用与节点执行记录。
+// 审批与迁移记录作为审计留存，不随实例清理
+func (d *WorkflowArchiveDao) Purge(ctx context.Context, instanceIDs []int64) error {
+	for _, m := range []interface{}{
+		&model.WorkflowCheckpointModel{},
+		&model.WorkflowAppliedCallbackModel{},
+		&model.WorkflowInstanceEventModel{},
+		&model.WorkflowSignalWaitModel{},
+		&model.WorkflowBusinessKeyModel{},
+		&model.WorkflowNodeRunModel{},
+	} {
+		if err := mvc.ExtractDB(ctx, d.db).Unscoped().Where("instance_id IN ?", instanceIDs).Delete(m).Error; err != nil {
+			return err
+		}
+	}
+	return mvc.ExtractDB(ctx, d.db).Unscoped().Where("id IN ?", instanceIDs).Delete(&model.WorkflowInstanceModel{}).Error
+}
@@ -0,0 +1,61 @@
+package dao
+
+import (
+	"context"
+
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"github.com/xsxdot/gokit/logger"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WorkflowBusinessKeyDao struct {
+	mvc.IBaseDao[model.WorkflowBusinessKeyModel]
+	log *logger.Log
+	err *errorc.ErrorBuilder
+	db  *gorm.DB
+}
+
+func NewWorkflowBusinessKeyDao(db *gorm.DB, log *logger.Log) *WorkflowBusinessKeyDao {
+	return &WorkflowBusinessKeyDao{
+		IBaseDao: mvc.NewGormDao[model.WorkflowBusinessKeyModel](db),
+		log:      log,
+		err:      errorc.NewErrorBuilder("WorkflowBusinessKeyDao"),
+		db:       db,
+	}
+}
+
+// Claim 登记业务键，返回 claimed=false 表示该键已被登记过。
+//
+// 与 Webhook 投递去重相同，用 ON CONFLICT DO NOTHING：并发的同键启动会在唯一索引上
+// 等待先到者提交，之后落空并转为加锁读取已登记的记录。
+func (d *WorkflowBusinessKeyDao) Claim(ctx context.Context, item *model.WorkflowBusinessKeyModel) (bool, error) {
+	res := mvc.ExtractDB(ctx, d.db).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "env"}, {Name: "def_code"}, {Name: "business_key"}},
+			DoNothing: true,
+		}).Create(item)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// FindForUpdate 带行锁查询已登记的业务键，必须在事务内使用
+func (d *WorkflowBusinessKeyDao) FindForUpdate(ctx context.Context, env, defCode, businessKey string) (*model.WorkflowBusinessKeyModel, error) {
+	var item model.WorkflowBusinessKeyModel
+	err := mvc.ExtractDB(ctx, d.db).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("env = ? AND def_code = ? AND business_key = ?", env, defCode, businessKey).First(&item).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// SetInstanceID 把业务键指向新启动的实例
+func (d *WorkflowBusinessKeyDao) SetInstanceID(ctx context.Context, id, instanceID int64) error {
+	return mvc.ExtractDB(ctx, d.db).Model(&model.WorkflowBusinessKeyModel{}).Where("id = ?", id).Update("instance_id", instanceID).Error
+}
@@ -47,7 +47,7 @@ func (d *WorkflowCheckpointDao) ListByInstanceIDOrderByCreatedAsc(ctx context.Co
 
 func (d *WorkflowCheckpointDao) ListTrailByInstanceIDOrderByCreatedAsc(ctx context.Context, instanceID int64) ([]*model.WorkflowCheckpointModel, error) {
 	var list []*model.WorkflowCheckpointModel
-	err := mvc.ExtractDB(ctx, d.db).Select("node_id", "node_output", "created_at").
+	err := mvc.ExtractDB(ctx, d.db).Select("id", "node_id", "node_output", "created_at").
 		Where("instance_id = ?", instanceID).
 		Order("created_at ASC").
 		Find(&list).Error
@@ -102,7 +102,7 @@ func (d *WorkflowCheckpointDao) DeleteFromIndexWithTx(ctx context.Context, tx *g
 func (d *WorkflowCheckpointDao) deleteFromIndexDB(ctx context.Context, gdb *gorm.DB, instanceID int64, fromIndex int) error {
 	if dialect.IsPostgres(gdb) {
 		// PostgreSQL 与 MySQL 8+ 支持 ROW_NUMBER()
-		return mvc.ExtractDB(ctx, d.db).Exec(`
+		return mvc.ExtractDB(ctx, gdb).Exec(`
 			DELETE FROM aio_workflow_checkpoint
 			WHERE id IN (
 				SELECT id FROM (
@@ -114,7 +114,7 @@ func (d *WorkflowCheckpointDao) deleteFromIndexDB(ctx context.Context, gdb *gorm
 	}
 	// MySQL 5.7 不支持 ROW_NUMBER，使用 load-then-delete 兜底
 	var ids []uint
-	if err := mvc.ExtractDB(ctx, d.db).Model(&model.WorkflowCheckpointModel{}).
+	if err := mvc.ExtractDB(ctx, gdb).Model(&model.WorkflowCheckpointModel{}).
 		Where("instance_id = ?", instanceID).
 		Order("created_at ASC").
 		Offset(fromIndex).
@@ -124,11 +124,11 @@ func (d *WorkflowCheckpointDao) deleteFromIndexDB(ctx context.Context, gdb *gorm
 	if len(ids) == 0 {
 		return nil
 	}
-	return mvc.ExtractDB(ctx, d.db).Where("id IN ?", ids).Delete(&model.WorkflowCheckpointModel{}).Error
+	return mvc.ExtractDB(ctx, gdb).Where("id IN ?", ids).Delete(&model.WorkflowCheckpointModel{}).Error
 }
 
 // DeleteFromIndexRangeWithTx 删除指定索引范围 [fromIndex, toIndex) 的 checkpoint。
-// 用于 loopback 边清理：删除回环目标到当前节点之间的旧 checkpoint，保留当前节点的 checkpoint。
+// 用于 loopback 边清理：删除回环目标到当前节点之间的旧 checkpoint。
 func (d *WorkflowCheckpointDao) DeleteFromIndexRangeWithTx(ctx context.Context, tx *gorm.DB, instanceID int64, fromIndex int, toIndex int) error {
 	if fromIndex >= toIndex {
 		return nil
@@ -44,11 +44,54 @@ func (d *WorkflowInstanceDao) SaveWithTx(ctx context.Context, tx *gorm.DB, entit
 	return tx.WithContext(ctx).Save(entity).Error
 }
 
+// ListChildren 按创建顺序列出父实例拉起的子实例，nodeID 为空时不限节点
+func (d *WorkflowInstanceDao) ListChildren(ctx context.Context, parentInstanceID int64, nodeID string) ([]*model.WorkflowInstanceModel, error) {
+	db := mvc.ExtractDB(ctx, d.db).Where("parent_instance_id = ?", parentInstanceID)
+	if nodeID != "" {
+		db = db.Where("parent_node_id = ?", nodeID)
+	}
+	var items []*model.WorkflowInstanceModel
+	if err := db.Order("id asc").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// FindLatestByBusinessKey 查询某定义编码（全部版本）在指定环境下以该业务键启动的最近一个实例
+func (d *WorkflowInstanceDao) FindLatestByBusinessKey(ctx context.Context, env, defCode, businessKey string) (*model.WorkflowInstanceModel, error) {
+	var entity model.WorkflowInstanceModel
+	err := mvc.ExtractDB(ctx, d.db).
+		Joins("JOIN aio_workflow_def ON aio_workflow_def.id = aio_workflow_instance.def_id").
+		Where("aio_workflow_def.code = ? AND aio_workflow_instance.env = ? AND aio_workflow_instance.business_key = ?", defCode, env, businessKey).
+		Order("aio_workflow_instance.id desc").First(&entity).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+
+// ListIDsByDefCode 按 id 升序列出某定义编码（全部版本）在指定环境下处于给定状态的实例 ID，
+// 只返回 id > afterID 的至多 limit 条，供批量操作分批遍历
+func (d *WorkflowInstanceDao) ListIDsByDefCode(ctx context.Context, defCode, env string, statuses []model.WorkflowInstanceStatus, afterID int64, limit int) ([]int64, error) {
+	var ids []int64
+	err := mvc.ExtractDB(ctx, d.db).Model(&model.WorkflowInstanceModel{}).
+		Joins("JOIN aio_workflow_def ON aio_workflow_def.id = aio_workflow_instance.def_id").
+		Where("aio_workflow_def.code = ? AND aio_workflow_instance.env = ?", defCode, env).
+		Where("aio_workflow_instance.status IN ? AND aio_workflow_instance.id > ?", statuses, afterID).
+		Order("aio_workflow_instance.id asc").Limit(limit).
+		Pluck("aio_workflow_instance.id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
 // ListInstancesFilter 实例列表筛选条件
 type ListInstancesFilter struct {
 	DefID         *int64 // 按 def_id 筛选
 	DefCode       string // 按 def code 筛选（需 join def 表）
 	Status        string // 按状态筛选
+	BusinessKey   string // 按业务键筛选
 	CreatedAfter  int64  // 创建时间戳（秒）之后
 	CreatedBefore int64  // 创建时间戳（秒）之前
 }
@@ -75,6 +118,9 @@ func (d *WorkflowInstanceDao) ListInstances(ctx context.Context, filter *ListIns
 		if filter.Status != "" {
 			db = db.Where("aio_workflow_instance.status = ?", filter.Status)
 		}
+		if filter.BusinessKey != "" {
+			db = db.Where("aio_workflow_instance.business_key = ?", filter.BusinessKey)
+		}
 		if filter.CreatedAfter > 0 {
 			db = db.Where("aio_workflow_instance.created_at >= ?", time.Unix(filter.CreatedAfter, 0))
 		}
@@ -98,6 +144,7 @@ func (d *WorkflowInstanceDao) ListInstances(ctx context.Context, filter *ListIns
 		"aio_workflow_instance.env",
 		"aio_workflow_instance.status",
 		"aio_workflow_instance.active_node_ids",
+		"aio_workflow_instance.business_key",
 		"aio_workflow_instance.created_at",
 	}).Order("aio_workflow_instance.created_at desc").Offset(int(offset)).Limit(int(pageSize)).Scan(&items).Error; err != nil {
 		return nil, 0, err
@@ -0,0 +1,84 @@
+package dao
+
+import (
+	"context"
+
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"github.com/xsxdot/gokit/logger"
+
+	"gorm.io/gorm"
+)
+
+type WorkflowInstanceEventDao struct {
+	mvc.IBaseDao[model.WorkflowInstanceEventModel]
+	log *logger.Log
+	err *errorc.ErrorBuilder
+	db  *gorm.DB
+}
+
+func NewWorkflowInstanceEventDao(db *gorm.DB, log *logger.Log) *WorkflowInstanceEventDao {
+	return &WorkflowInstanceEventDao{
+		IBaseDao: mvc.NewGormDao[model.WorkflowInstanceEventModel](db),
+		log:      log,
+		err:      errorc.NewErrorBuilder("WorkflowInstanceEventDao"),
+		db:       db,
+	}
+}
+
+// Append 为事件分配实例内序号并写入。
+//
+// 序号由实例行上的 event_seq 计数器原子自增后读回：自增本身即对该行加写锁，推进路径已持有
+// 该行锁时同事务重入无代价，其余写入方在此排队；不再按 MAX(seq) 扫描事件表，写入开销与
+// 实例已有事件数无关，且序号连续、不撞唯一索引。
+func (d *WorkflowInstanceEventDao) Append(ctx context.Context, event *model.WorkflowInstanceEventModel) error {
+	return mvc.ExtractDB(ctx, d.db).Transaction(func(tx *gorm.DB) error {
+		res := tx.Exec("UPDATE aio_workflow_instance SET event_seq = event_seq + 1 WHERE id = ?", event.InstanceID)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Model(&model.WorkflowInstanceModel{}).Where("id = ?", event.InstanceID).
+			Select("event_seq").Scan(&event.Seq).Error; err != nil {
+			return err
+		}
+		return tx.Create(event).Error
+	})
+}
+
+// ListByInstanceAfterSeq 按序号升序列出实例中 seq > afterSeq 的事件
+func (d *WorkflowInstanceEventDao) ListByInstanceAfterSeq(ctx context.Context, instanceID, afterSeq int64, limit int) ([]*model.WorkflowInstanceEventModel, error) {
+	var items []*model.WorkflowInstanceEventModel
+	err := mvc.ExtractDB(ctx, d.db).Where("instance_id = ? AND seq > ?", instanceID, afterSeq).
+		Order("seq asc").Limit(limit).Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ListByDefAfterID 按主键升序列出某环境下某定义编码（全部版本）中 id > afterID 的事件。
+// 定义 ID 每次用子查询解析，订阅期间新发布的版本也能被覆盖
+func (d *WorkflowInstanceEventDao) ListByDefAfterID(ctx context.Context, env, defCode string, afterID int64, limit int) ([]*model.WorkflowInstanceEventModel, error) {
+	db := mvc.ExtractDB(ctx, d.db)
+	defIDs := db.Session(&gorm.Session{NewDB: true}).Model(&model.WorkflowDefModel{}).
+		Select("id").Where("env = ? AND code = ?", env, defCode)
+	var items []*model.WorkflowInstanceEventModel
+	err := db.Where("id > ? AND env = ? AND def_id IN (?)", afterID, env, defIDs).
+		Order("id asc").Limit(limit).Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// MaxID 返回当前最大事件主键，无事件时为 0
+func (d *WorkflowInstanceEventDao) MaxID(ctx context.Context) (int64, error) {
+	var maxID int64
+	err := mvc.ExtractDB(ctx, d.db).Model(&model.WorkflowInstanceEventModel{}).
+		Select("COALESCE(MAX(id), 0)").Scan(&maxID).Error
+	return maxID, err
+}
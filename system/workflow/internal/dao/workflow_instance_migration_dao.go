@@ -0,0 +1,38 @@
+package dao
+
+import (
+	"context"
+
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"github.com/xsxdot/gokit/logger"
+
+	"gorm.io/gorm"
+)
+
+type WorkflowInstanceMigrationDao struct {
+	mvc.IBaseDao[model.WorkflowInstanceMigrationModel]
+	log *logger.Log
+	err *errorc.ErrorBuilder
+	db  *gorm.DB
+}
+
+func NewWorkflowInstanceMigrationDao(db *gorm.DB, log *logger.Log) *WorkflowInstanceMigrationDao {
+	return &WorkflowInstanceMigrationDao{
+		IBaseDao: mvc.NewGormDao[model.WorkflowInstanceMigrationModel](db),
+		log:      log,
+		err:      errorc.NewErrorBuilder("WorkflowInstanceMigrationDao"),
+		db:       db,
+	}
+}
+
+// ListByInstance 按迁移先后列出实例的迁移记录
+func (d *WorkflowInstanceMigrationDao) ListByInstance(ctx context.Context, instanceID int64) ([]*model.WorkflowInstanceMigrationModel, error) {
+	var items []*model.WorkflowInstanceMigrationModel
+	err := mvc.ExtractDB(ctx, d.db).Where("instance_id = ?", instanceID).Order("id asc").Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
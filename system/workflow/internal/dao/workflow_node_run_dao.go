@@ -0,0 +1,129 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"github.com/xsxdot/gokit/logger"
+
+	"gorm.io/gorm"
+)
+
+// NodeRunRangeFilter 分析报表的范围：定义的若干版本在 [From, To) 内的记录
+type NodeRunRangeFilter struct {
+	DefIDs []int64
+	From   time.Time
+	To     time.Time
+}
+
+// TerminalInstanceRow 进入终态的实例，UpdatedAt 即进入终态的时间
+type TerminalInstanceRow struct {
+	DefID     int64                        `gorm:"column:def_id"`
+	Status    model.WorkflowInstanceStatus `gorm:"column:status"`
+	UpdatedAt time.Time                    `gorm:"column:updated_at"`
+}
+
+// WorkflowNodeRunDao 节点执行记录及按定义统计的报表查询
+type WorkflowNodeRunDao struct {
+	mvc.IBaseDao[model.WorkflowNodeRunModel]
+	log *logger.Log
+	err *errorc.ErrorBuilder
+	db  *gorm.DB
+}
+
+func NewWorkflowNodeRunDao(db *gorm.DB, log *logger.Log) *WorkflowNodeRunDao {
+	return &WorkflowNodeRunDao{
+		IBaseDao: mvc.NewGormDao[model.WorkflowNodeRunModel](db),
+		log:      log,
+		err:      errorc.NewErrorBuilder("WorkflowNodeRunDao"),
+		db:       db,
+	}
+}
+
+// FindRunning 查询节点最近一条未完成的执行记录
+func (d *WorkflowNodeRunDao) FindRunning(ctx context.Context, instanceID int64, nodeID string) (*model.WorkflowNodeRunModel, error) {
+	var item model.WorkflowNodeRunModel
+	err := mvc.ExtractDB(ctx, d.db).
+		Where("instance_id = ? AND node_id = ? AND status = ?", instanceID, nodeID, model.NodeRunStatusRunning).
+		Order("id desc").Take(&item).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// AbandonRunning 把节点未完成的执行记录置为 ABANDONED
+func (d *WorkflowNodeRunDao) AbandonRunning(ctx context.Context, instanceID int64, nodeID string, at time.Time) error {
+	return mvc.ExtractDB(ctx, d.db).Model(&model.WorkflowNodeRunModel{}).
+		Where("instance_id = ? AND node_id = ? AND status = ?", instanceID, nodeID, model.NodeRunStatusRunning).
+		Updates(map[string]interface{}{"status": model.NodeRunStatusAbandoned, "finished_at": at}).Error
+}
+
+// UpdateFields 按列更新执行记录
+func (d *WorkflowNodeRunDao) UpdateFields(ctx context.Context, id int64, fields map[string]interface{}) error {
+	return mvc.ExtractDB(ctx, d.db).Model(&model.WorkflowNodeRunModel{}).Where("id = ?", id).Updates(fields).Error
+}
+
+// ListByInstance 按触发顺序列出实例的执行记录
+func (d *WorkflowNodeRunDao) ListByInstance(ctx context.Context, instanceID int64) ([]*model.WorkflowNodeRunModel, error) {
+	var items []*model.WorkflowNodeRunModel
+	if err := mvc.ExtractDB(ctx, d.db).Where("instance_id = ?", instanceID).Order("id asc").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// LatestCheckpointID 节点最近一条检查点的 ID，没有时为 0
+func (d *WorkflowNodeRunDao) LatestCheckpointID(ctx context.Context, instanceID int64, nodeID string) (int64, error) {
+	var ids []int64
+	err := mvc.ExtractDB(ctx, d.db).Model(&model.WorkflowCheckpointModel{}).
+		Where("instance_id = ? AND node_id = ?", instanceID, nodeID).
+		Order("id desc").Limit(1).Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	return ids[0], nil
+}
+
+// ListFinished 列出范围内触发且已完成（成功或失败）的执行记录，最多 limit 条
+func (d *WorkflowNodeRunDao) ListFinished(ctx context.Context, f *NodeRunRangeFilter, limit int) ([]*model.WorkflowNodeRunModel, error) {
+	var items []*model.WorkflowNodeRunModel
+	err := mvc.ExtractDB(ctx, d.db).
+		Select("id, node_id, node_type, def_id, status, error_edge, duration_ms, queue_wait_ms, run_ms, jobs, attempts").
+		Where("def_id IN ? AND started_at >= ? AND started_at < ? AND status IN ?", f.DefIDs, f.From, f.To,
+			[]model.NodeRunStatus{model.NodeRunStatusSucceeded, model.NodeRunStatusFailed}).
+		Order("id asc").Limit(limit).Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ListTerminalInstances 列出范围内进入终态的实例，最多 limit 条
+func (d *WorkflowNodeRunDao) ListTerminalInstances(ctx context.Context, f *NodeRunRangeFilter, statuses []model.WorkflowInstanceStatus, limit int) ([]*TerminalInstanceRow, error) {
+	var items []*TerminalInstanceRow
+	err := mvc.ExtractDB(ctx, d.db).Model(&model.WorkflowInstanceModel{}).
+		Select("def_id, status, updated_at").
+		Where("def_id IN ? AND status IN ? AND updated_at >= ? AND updated_at < ?", f.DefIDs, statuses, f.From, f.To).
+		Order("id asc").Limit(limit).Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ListDefVersions 列出 env 下定义编码的版本（只取 id 与 version），version 为 0 时列出全部版本
+func (d *WorkflowNodeRunDao) ListDefVersions(ctx context.Context, env, code string, version int32) ([]*model.WorkflowDefModel, error) {
+	var items []*model.WorkflowDefModel
+	db := mvc.ExtractDB(ctx, d.db).Select("id, version").Where("env = ? AND code = ?", env, code)
+	if version > 0 {
+		db = db.Where("version = ?", version)
+	}
+	if err := db.Order("version asc").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,71 @@
+package dao
+
+import (
+	"context"
+
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"github.com/xsxdot/gokit/logger"
+
+	"gorm.io/gorm"
+)
+
+type WorkflowRetentionPolicyDao struct {
+	mvc.IBaseDao[model.WorkflowRetentionPolicyModel]
+	log *logger.Log
+	err *errorc.ErrorBuilder
+	db  *gorm.DB
+}
+
+func NewWorkflowRetentionPolicyDao(db *gorm.DB, log *logger.Log) *WorkflowRetentionPolicyDao {
+	return &WorkflowRetentionPolicyDao{
+		IBaseDao: mvc.NewGormDao[model.WorkflowRetentionPolicyModel](db),
+		log:      log,
+		err:      errorc.NewErrorBuilder("WorkflowRetentionPolicyDao"),
+		db:       db,
+	}
+}
+
+// FindByScope 查询 env + def_code 上的策略，不存在时返回 gorm.ErrRecordNotFound
+func (d *WorkflowRetentionPolicyDao) FindByScope(ctx context.Context, env, defCode string) (*model.WorkflowRetentionPolicyModel, error) {
+	var item model.WorkflowRetentionPolicyModel
+	if err := mvc.ExtractDB(ctx, d.db).Where("env = ? AND def_code = ?", env, defCode).First(&item).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// ListPolicies 列出策略，env 为空时列出所有环境
+func (d *WorkflowRetentionPolicyDao) ListPolicies(ctx context.Context, env string) ([]*model.WorkflowRetentionPolicyModel, error) {
+	db := mvc.ExtractDB(ctx, d.db)
+	if env != "" {
+		db = db.Where("env = ?", env)
+	}
+	var items []*model.WorkflowRetentionPolicyModel
+	if err := db.Order("env asc, def_code asc").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ListDefCodes 列出 env 下单独配置了策略的定义编码
+func (d *WorkflowRetentionPolicyDao) ListDefCodes(ctx context.Context, env string) ([]string, error) {
+	var codes []string
+	err := mvc.ExtractDB(ctx, d.db).Model(&model.WorkflowRetentionPolicyModel{}).
+		Where("env = ? AND def_code <> ''", env).Pluck("def_code", &codes).Error
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// UpdateFields 按列更新策略（零值也会写入）
+func (d *WorkflowRetentionPolicyDao) UpdateFields(ctx context.Context, id int64, fields map[string]interface{}) error {
+	return mvc.ExtractDB(ctx, d.db).Model(&model.WorkflowRetentionPolicyModel{}).Where("id = ?", id).Updates(fields).Error
+}
+
+// HardDeleteById 物理删除策略，使同一范围可以重新创建
+func (d *WorkflowRetentionPolicyDao) HardDeleteById(ctx context.Context, id int64) error {
+	return mvc.ExtractDB(ctx, d.db).Unscoped().Where("id = ?", id).Delete(&model.WorkflowRetentionPolicyModel{}).Error
+}
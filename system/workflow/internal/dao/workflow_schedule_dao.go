@@ -0,0 +1,84 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"github.com/xsxdot/gokit/logger"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WorkflowScheduleDao struct {
+	mvc.IBaseDao[model.WorkflowScheduleModel]
+	log *logger.Log
+	err *errorc.ErrorBuilder
+	db  *gorm.DB
+}
+
+func NewWorkflowScheduleDao(db *gorm.DB, log *logger.Log) *WorkflowScheduleDao {
+	return &WorkflowScheduleDao{
+		IBaseDao: mvc.NewGormDao[model.WorkflowScheduleModel](db),
+		log:      log,
+		err:      errorc.NewErrorBuilder("WorkflowScheduleDao"),
+		db:       db,
+	}
+}
+
+// FindByIdForUpdate 带行锁查询调度，必须在事务内使用
+func (d *WorkflowScheduleDao) FindByIdForUpdate(ctx context.Context, tx *gorm.DB, id int64) (*model.WorkflowScheduleModel, error) {
+	var entity model.WorkflowScheduleModel
+	err := tx.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&entity).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+
+// UpdateFields 按列更新调度；与 UpdateById 不同，零值（如清空 next_fire_at）也会写入
+func (d *WorkflowScheduleDao) UpdateFields(ctx context.Context, id int64, fields map[string]interface{}) error {
+	return mvc.ExtractDB(ctx, d.db).Model(&model.WorkflowScheduleModel{}).Where("id = ?", id).Updates(fields).Error
+}
+
+// ListDue 列出已到触发点的启用调度，按触发时间先后
+func (d *WorkflowScheduleDao) ListDue(ctx context.Context, now time.Time, limit int) ([]*model.WorkflowScheduleModel, error) {
+	var items []*model.WorkflowScheduleModel
+	err := mvc.ExtractDB(ctx, d.db).
+		Where("status = ? AND next_fire_at IS NOT NULL AND next_fire_at <= ?", model.ScheduleStatusActive, now).
+		Order("next_fire_at asc").Limit(limit).Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ListSchedules 分页列出调度，defCode 为空时不限定义
+func (d *WorkflowScheduleDao) ListSchedules(ctx context.Context, env, defCode string, pageNum, pageSize int32) ([]*model.WorkflowScheduleModel, int64, error) {
+	if pageNum <= 0 {
+		pageNum = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+
+	db := mvc.ExtractDB(ctx, d.db).Model(&model.WorkflowScheduleModel{}).Where("env = ?", env)
+	if defCode != "" {
+		db = db.Where("def_code = ?", defCode)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var items []*model.WorkflowScheduleModel
+	offset := (pageNum - 1) * pageSize
+	if err := db.Order("id desc").Offset(int(offset)).Limit(int(pageSize)).Find(&items).Error; err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
@@ -0,0 +1,108 @@
+package dao
+
+import (
+	"context"
+
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"github.com/xsxdot/gokit/logger"
+
+	"gorm.io/gorm"
+)
+
+type WorkflowScheduleRunDao struct {
+	mvc.IBaseDao[model.WorkflowScheduleRunModel]
+	log *logger.Log
+	err *errorc.ErrorBuilder
+	db  *gorm.DB
+}
+
+func NewWorkflowScheduleRunDao(db *gorm.DB, log *logger.Log) *WorkflowScheduleRunDao {
+	return &WorkflowScheduleRunDao{
+		IBaseDao: mvc.NewGormDao[model.WorkflowScheduleRunModel](db),
+		log:      log,
+		err:      errorc.NewErrorBuilder("WorkflowScheduleRunDao"),
+		db:       db,
+	}
+}
+
+// CountInFlight 统计调度拉起且仍未进入终态（RUNNING/WAITING/PAUSED）的实例数
+func (d *WorkflowScheduleRunDao) CountInFlight(ctx context.Context, scheduleID int64) (int64, error) {
+	var n int64
+	err := mvc.ExtractDB(ctx, d.db).Model(&model.WorkflowScheduleRunModel{}).
+		Joins("JOIN aio_workflow_instance ON aio_workflow_instance.id = aio_workflow_schedule_run.instance_id").
+		Where("aio_workflow_schedule_run.schedule_id = ? AND aio_workflow_schedule_run.status = ?", scheduleID, model.ScheduleRunStarted).
+		Where("aio_workflow_instance.status IN ?", []model.WorkflowInstanceStatus{model.InstanceStatusRunning, model.InstanceStatusWaiting, model.InstanceStatusPaused}).
+		Count(&n).Error
+	return n, err
+}
+
+// FirstQueued 取调度最早一条排队中的运行记录，没有时返回 nil
+func (d *WorkflowScheduleRunDao) FirstQueued(ctx context.Context, scheduleID int64) (*model.WorkflowScheduleRunModel, error) {
+	var items []*model.WorkflowScheduleRunModel
+	err := mvc.ExtractDB(ctx, d.db).
+		Where("schedule_id = ? AND status = ?", scheduleID, model.ScheduleRunQueued).
+		Order("id asc").Limit(1).Find(&items).Error
+	if err != nil || len(items) == 0 {
+		return nil, err
+	}
+	return items[0], nil
+}
+
+// ListQueuedScheduleIDs 列出存在排队运行记录的调度 ID
+func (d *WorkflowScheduleRunDao) ListQueuedScheduleIDs(ctx context.Context, limit int) ([]int64, error) {
+	var ids []int64
+	err := mvc.ExtractDB(ctx, d.db).Model(&model.WorkflowScheduleRunModel{}).
+		Where("status = ?", model.ScheduleRunQueued).
+		Distinct("schedule_id").Limit(limit).Pluck("schedule_id", &ids).Error
+	return ids, err
+}
+
+// UpdateFields 按列更新运行记录
+func (d *WorkflowScheduleRunDao) UpdateFields(ctx context.Context, id int64, fields map[string]interface{}) error {
+	return mvc.ExtractDB(ctx, d.db).Model(&model.WorkflowScheduleRunModel{}).Where("id = ?", id).Updates(fields).Error
+}
+
+// SkipQueued 把调度所有排队中的运行记录标记为跳过
+func (d *WorkflowScheduleRunDao) SkipQueued(ctx context.Context, scheduleID int64, reason string) error {
+	return mvc.ExtractDB(ctx, d.db).Model(&model.WorkflowScheduleRunModel{}).
+		Where("schedule_id = ? AND status = ?", scheduleID, model.ScheduleRunQueued).
+		Updates(map[string]interface{}{"status": model.ScheduleRunSkipped, "error": reason}).Error
+}
+
+// ListRuns 分页列出调度的运行历史（新的在前），附带所拉起实例的当前状态
+func (d *WorkflowScheduleRunDao) ListRuns(ctx context.Context, scheduleID int64, pageNum, pageSize int32) ([]*model.WorkflowScheduleRunItem, int64, error) {
+	if pageNum <= 0 {
+		pageNum = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+
+	db := mvc.ExtractDB(ctx, d.db).Model(&model.WorkflowScheduleRunModel{}).
+		Joins("LEFT JOIN aio_workflow_instance ON aio_workflow_instance.id = aio_workflow_schedule_run.instance_id").
+		Where("aio_workflow_schedule_run.schedule_id = ?", scheduleID)
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var items []*model.WorkflowScheduleRunItem
+	offset := (pageNum - 1) * pageSize
+	if err := db.Select([]string{
+		"aio_workflow_schedule_run.id",
+		"aio_workflow_schedule_run.schedule_id",
+		"aio_workflow_schedule_run.trigger_type",
+		"aio_workflow_schedule_run.status",
+		"aio_workflow_schedule_run.scheduled_at",
+		"aio_workflow_schedule_run.instance_id",
+		"COALESCE(aio_workflow_instance.status, '') AS instance_status",
+		"aio_workflow_schedule_run.error",
+		"aio_workflow_schedule_run.created_at",
+	}).Order("aio_workflow_schedule_run.id desc").Offset(int(offset)).Limit(int(pageSize)).Scan(&items).Error; err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
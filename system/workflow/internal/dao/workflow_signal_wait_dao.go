@@ -0,0 +1,77 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"github.com/xsxdot/gokit/logger"
+
+	"gorm.io/gorm"
+)
+
+type WorkflowSignalWaitDao struct {
+	mvc.IBaseDao[model.WorkflowSignalWaitModel]
+	log *logger.Log
+	err *errorc.ErrorBuilder
+	db  *gorm.DB
+}
+
+func NewWorkflowSignalWaitDao(db *gorm.DB, log *logger.Log) *WorkflowSignalWaitDao {
+	return &WorkflowSignalWaitDao{
+		IBaseDao: mvc.NewGormDao[model.WorkflowSignalWaitModel](db),
+		log:      log,
+		err:      errorc.NewErrorBuilder("WorkflowSignalWaitDao"),
+		db:       db,
+	}
+}
+
+// ListWaitingByInstance 列出实例中仍在等待某信号的记录
+func (d *WorkflowSignalWaitDao) ListWaitingByInstance(ctx context.Context, instanceID int64, signalName string) ([]*model.WorkflowSignalWaitModel, error) {
+	var items []*model.WorkflowSignalWaitModel
+	err := mvc.ExtractDB(ctx, d.db).
+		Where("instance_id = ? AND signal_name = ? AND status = ?", instanceID, signalName, model.SignalWaitStatusWaiting).
+		Order("id asc").Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ListWaitingByKey 按环境、信号名与关联键列出仍在等待的记录
+func (d *WorkflowSignalWaitDao) ListWaitingByKey(ctx context.Context, env, signalName, correlationKey string) ([]*model.WorkflowSignalWaitModel, error) {
+	var items []*model.WorkflowSignalWaitModel
+	err := mvc.ExtractDB(ctx, d.db).
+		Where("env = ? AND signal_name = ? AND correlation_key = ? AND status = ?", env, signalName, correlationKey, model.SignalWaitStatusWaiting).
+		Order("id asc").Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Resolve 把仍在等待的记录置为终态，返回是否由本次调用完成转换（并发投递只有一方成功）
+func (d *WorkflowSignalWaitDao) Resolve(ctx context.Context, id int64, status model.SignalWaitStatus) (bool, error) {
+	res := mvc.ExtractDB(ctx, d.db).Model(&model.WorkflowSignalWaitModel{}).
+		Where("id = ? AND status = ?", id, model.SignalWaitStatusWaiting).
+		Updates(map[string]interface{}{"status": status, "resolved_at": time.Now()})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+// CancelWaiting 将实例中仍在等待的记录作废，nodeID 为空时不限节点
+func (d *WorkflowSignalWaitDao) CancelWaiting(ctx context.Context, instanceID int64, nodeID string) error {
+	db := mvc.ExtractDB(ctx, d.db).Model(&model.WorkflowSignalWaitModel{}).
+		Where("instance_id = ? AND status = ?", instanceID, model.SignalWaitStatusWaiting)
+	if nodeID != "" {
+		db = db.Where("node_id = ?", nodeID)
+	}
+	return db.Updates(map[string]interface{}{
+		"status":      model.SignalWaitStatusCanceled,
+		"resolved_at": time.Now(),
+	}).Error
+}
@@ -0,0 +1,92 @@
+package dao
+
+import (
+	"context"
+
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"github.com/xsxdot/gokit/logger"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WorkflowTriggerDao struct {
+	mvc.IBaseDao[model.WorkflowTriggerModel]
+	log *logger.Log
+	err *errorc.ErrorBuilder
+	db  *gorm.DB
+}
+
+func NewWorkflowTriggerDao(db *gorm.DB, log *logger.Log) *WorkflowTriggerDao {
+	return &WorkflowTriggerDao{
+		IBaseDao: mvc.NewGormDao[model.WorkflowTriggerModel](db),
+		log:      log,
+		err:      errorc.NewErrorBuilder("WorkflowTriggerDao"),
+		db:       db,
+	}
+}
+
+// FindByCode 根据编码查询触发器
+func (d *WorkflowTriggerDao) FindByCode(ctx context.Context, code string) (*model.WorkflowTriggerModel, error) {
+	var item model.WorkflowTriggerModel
+	err := mvc.ExtractDB(ctx, d.db).Where("code = ?", code).First(&item).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// CreateIfAbsent 创建触发器，返回 created=false 表示编码已被占用。
+//
+// 与投递登记相同，用 ON CONFLICT DO NOTHING 而非「先查后插」：并发创建同一编码时
+// 只有先到者写入，后到者落空，公开地址不会指向两个触发器。
+func (d *WorkflowTriggerDao) CreateIfAbsent(ctx context.Context, item *model.WorkflowTriggerModel) (bool, error) {
+	res := mvc.ExtractDB(ctx, d.db).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "code"}},
+			DoNothing: true,
+		}).Create(item)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// HardDeleteById 物理删除触发器，使其编码可以重新使用
+func (d *WorkflowTriggerDao) HardDeleteById(ctx context.Context, id int64) error {
+	return mvc.ExtractDB(ctx, d.db).Unscoped().Where("id = ?", id).Delete(&model.WorkflowTriggerModel{}).Error
+}
+
+// UpdateFields 按列更新触发器（零值也会写入）
+func (d *WorkflowTriggerDao) UpdateFields(ctx context.Context, id int64, fields map[string]interface{}) error {
+	return mvc.ExtractDB(ctx, d.db).Model(&model.WorkflowTriggerModel{}).Where("id = ?", id).Updates(fields).Error
+}
+
+// ListTriggers 分页列出触发器，env 为空时不限环境
+func (d *WorkflowTriggerDao) ListTriggers(ctx context.Context, env string, pageNum, pageSize int32) ([]*model.WorkflowTriggerModel, int64, error) {
+	if pageNum <= 0 {
+		pageNum = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+
+	db := mvc.ExtractDB(ctx, d.db).Model(&model.WorkflowTriggerModel{})
+	if env != "" {
+		db = db.Where("env = ?", env)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var items []*model.WorkflowTriggerModel
+	offset := (pageNum - 1) * pageSize
+	if err := db.Order("id desc").Offset(int(offset)).Limit(int(pageSize)).Find(&items).Error; err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
@@ -0,0 +1,60 @@
+package dao
+
+import (
+	"context"
+
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"github.com/xsxdot/gokit/logger"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WorkflowTriggerDeliveryDao struct {
+	mvc.IBaseDao[model.WorkflowTriggerDeliveryModel]
+	log *logger.Log
+	err *errorc.ErrorBuilder
+	db  *gorm.DB
+}
+
+func NewWorkflowTriggerDeliveryDao(db *gorm.DB, log *logger.Log) *WorkflowTriggerDeliveryDao {
+	return &WorkflowTriggerDeliveryDao{
+		IBaseDao: mvc.NewGormDao[model.WorkflowTriggerDeliveryModel](db),
+		log:      log,
+		err:      errorc.NewErrorBuilder("WorkflowTriggerDeliveryDao"),
+		db:       db,
+	}
+}
+
+// Claim 登记一次投递，返回 claimed=false 表示该去重键已被受理过。
+//
+// 与回调幂等标记相同，用 ON CONFLICT DO NOTHING 而非「先查后插」：并发的重复投递
+// 会在唯一索引上等待先到者提交，之后直接落空，不会各自启动一个实例。
+func (d *WorkflowTriggerDeliveryDao) Claim(ctx context.Context, delivery *model.WorkflowTriggerDeliveryModel) (bool, error) {
+	res := mvc.ExtractDB(ctx, d.db).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "trigger_id"}, {Name: "dedup_key"}},
+			DoNothing: true,
+		}).Create(delivery)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// FindByKey 查询已受理的投递
+func (d *WorkflowTriggerDeliveryDao) FindByKey(ctx context.Context, triggerID int64, dedupKey string) (*model.WorkflowTriggerDeliveryModel, error) {
+	var item model.WorkflowTriggerDeliveryModel
+	err := mvc.ExtractDB(ctx, d.db).Unscoped().Where("trigger_id = ? AND dedup_key = ?", triggerID, dedupKey).First(&item).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// SetInstanceID 回填投递启动的实例ID
+func (d *WorkflowTriggerDeliveryDao) SetInstanceID(ctx context.Context, id, instanceID int64) error {
+	return mvc.ExtractDB(ctx, d.db).Model(&model.WorkflowTriggerDeliveryModel{}).Where("id = ?", id).Update("instance_id", instanceID).Error
+}
@@ -1,20 +1,45 @@
 package model
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 type NodeType string
 
+// NodeIDDelimiter 任务幂等键中紧跟节点 ID 的分隔符，节点 ID 不能包含它：按节点前缀取消在途任务时，
+// 才不会误中 ID 以该节点 ID 开头的其他节点
+const NodeIDDelimiter = "#"
+
 const (
 	NodeTypeTask      NodeType = "task"
 	NodeTypeApproval  NodeType = "approval"
 	NodeTypeCondition NodeType = "condition"
 	NodeTypeMap       NodeType = "map"
+	// NodeTypeSubWorkflow 启动并等待一个子工作流实例，子实例终态后回报本节点
+	NodeTypeSubWorkflow NodeType = "subworkflow"
+	// NodeTypeTimer 按时长或绝对时间延迟后继续，借助 executor 延时任务实现
+	NodeTypeTimer NodeType = "timer"
+	// NodeTypeHTTP 调用外部 HTTP 接口，请求由 executor 内部 worker 发起
+	NodeTypeHTTP NodeType = "http"
+	// NodeTypeTransform 在引擎内按 expr 表达式整理状态后立即继续，不经过 executor
+	NodeTypeTransform NodeType = "transform"
+	// NodeTypeLoop 在 while 条件成立时重复执行循环体子路径，最多 max_iterations 轮
+	NodeTypeLoop NodeType = "loop"
+	// NodeTypeWaitSignal 挂起所在分支直到收到指定信号，可配置超时与按关联键投递
+	NodeTypeWaitSignal NodeType = "wait_signal"
 )
 
 // DAG 工作流有向无环图定义
 type DAG struct {
 	Nodes []Node `json:"nodes"`
 	Edges []Edge `json:"edges"`
+	// InputSchema 可选，启动实例时按此 JSON Schema 校验初始输入
+	InputSchema map[string]interface{} `json:"input_schema,omitempty"`
+	// StateSchema 可选，声明 state data 区的 JSON Schema：定义校验据此对边条件与映射表达式
+	// 做类型检查，运行期每次合并节点结果后按此校验
+	StateSchema map[string]interface{} `json:"state_schema,omitempty"`
 }
 
 // Node 节点定义
@@ -25,6 +50,94 @@ type Node struct {
 	Config    map[string]interface{} `json:"config"`               // 节点特定配置，例如 service, method
 }
 
+// 汇聚语义（config.join）：多入边节点何时触发
+const (
+	// JoinAll 所有前驱都完成才触发（默认）
+	JoinAll = "all"
+	// JoinAny 任一前驱到达即触发
+	JoinAny = "any"
+	// JoinQuorumPrefix quorum:N，N 个前驱到达即触发
+	JoinQuorumPrefix = "quorum:"
+)
+
+// 兄弟分支策略（config.join_siblings）：any/quorum 汇聚满足后仍在运行的分支如何处理
+const (
+	// JoinSiblingsCancel 取消仍在运行的兄弟分支（默认）
+	JoinSiblingsCancel = "cancel"
+	// JoinSiblingsIgnore 放任兄弟分支跑完，其结果不再触发汇聚节点
+	JoinSiblingsIgnore = "ignore"
+)
+
+// JoinSpec 节点的汇聚配置
+type JoinSpec struct {
+	// Quorum 需到达的前驱数，0 表示 all
+	Quorum         int
+	CancelSiblings bool
+}
+
+// JoinSpec 解析节点的 join / join_siblings 配置
+func (n *Node) JoinSpec() (JoinSpec, error) {
+	spec := JoinSpec{CancelSiblings: true}
+	mode, _ := n.Config["join"].(string)
+	switch {
+	case mode == "" || mode == JoinAll:
+	case mode == JoinAny:
+		spec.Quorum = 1
+	case strings.HasPrefix(mode, JoinQuorumPrefix):
+		q, err := strconv.Atoi(strings.TrimPrefix(mode, JoinQuorumPrefix))
+		if err != nil || q < 1 {
+			return spec, fmt.Errorf("节点 %s 的 join 配置无效: %s", n.ID, mode)
+		}
+		spec.Quorum = q
+	default:
+		return spec, fmt.Errorf("节点 %s 的 join 仅支持 all、any 或 quorum:N: %s", n.ID, mode)
+	}
+	switch siblings, _ := n.Config["join_siblings"].(string); siblings {
+	case "", JoinSiblingsCancel:
+	case JoinSiblingsIgnore:
+		spec.CancelSiblings = false
+	default:
+		return spec, fmt.Errorf("节点 %s 的 join_siblings 仅支持 cancel 或 ignore: %s", n.ID, siblings)
+	}
+	return spec, nil
+}
+
+// JoinPredecessors 参与汇聚计数的前驱节点（去重，不含回环边来源）
+func (d *DAG) JoinPredecessors(nodeID string) []string {
+	seen := make(map[string]bool)
+	var preds []string
+	for _, e := range d.GetIncomingEdges(nodeID) {
+		if e.IsLoopback || seen[e.From] {
+			continue
+		}
+		seen[e.From] = true
+		preds = append(preds, e.From)
+	}
+	return preds
+}
+
+// CompensateConfig 解析 task 节点的 compensate 配置：{"service","method"} 及可选的投递策略
+// （max_attempts、retry_backoff 等，同节点配置）。未配置时返回 nil
+func (n *Node) CompensateConfig() (map[string]interface{}, error) {
+	raw, ok := n.Config["compensate"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	if n.Type != NodeTypeTask {
+		return nil, fmt.Errorf("节点 %s 不是 task 节点，不能配置 compensate", n.ID)
+	}
+	conf, isMap := raw.(map[string]interface{})
+	if !isMap {
+		return nil, fmt.Errorf("节点 %s 的 compensate 必须是对象", n.ID)
+	}
+	service, _ := conf["service"].(string)
+	method, _ := conf["method"].(string)
+	if service == "" || method == "" {
+		return nil, fmt.Errorf("节点 %s 的 compensate 需配置 service 与 method", n.ID)
+	}
+	return conf, nil
+}
+
 // EdgeType 边类型：空/success 成功走此边, error 失败走此边, always 无论成功失败都走
 type EdgeType string
 
@@ -41,6 +154,8 @@ type Edge struct {
 	Condition  string   `json:"condition"`  // 表达式，为空表示无条件执行
 	Type       EdgeType `json:"type"`       // ""(默认成功走此边), "error"(失败走此边), "always"(无论成功失败都走)
 	IsLoopback bool     `json:"is_loopback"` // 明确标记为合法回退边，放行环路检测
+	// Decision 仅用于审批节点与 wait_signal 节点出边：节点输出的 decision 与之相同才走此边，为空表示不区分决定
+	Decision string `json:"decision,omitempty"`
 }
 
 // GetNode 获取节点
@@ -116,7 +231,70 @@ func (d *DAG) Validate() error {
 	}
 	nodeSet := make(map[string]bool)
 	for _, n := range d.Nodes {
+		if strings.Contains(n.ID, NodeIDDelimiter) {
+			return fmt.Errorf("节点 ID %q 不能包含 %q", n.ID, NodeIDDelimiter)
+		}
 		nodeSet[n.ID] = true
+		if n.Type == NodeTypeSubWorkflow {
+			if code, _ := n.Config["def_code"].(string); code == "" {
+				return fmt.Errorf("subworkflow 节点 %s 缺少 def_code 配置", n.ID)
+			}
+		}
+		if n.Type == NodeTypeTimer && n.Config["duration"] == nil && n.Config["until"] == nil {
+			return fmt.Errorf("timer 节点 %s 需配置 duration 或 until", n.ID)
+		}
+		if n.Type == NodeTypeWaitSignal {
+			if name, _ := n.Config["signal"].(string); name == "" {
+				return fmt.Errorf("wait_signal 节点 %s 缺少 signal 配置", n.ID)
+			}
+		}
+		if n.Type == NodeTypeTransform {
+			if assign, _ := n.Config["assign"].(map[string]interface{}); len(assign) == 0 {
+				return fmt.Errorf("transform 节点 %s 缺少 assign 配置", n.ID)
+			}
+		}
+		if n.Type == NodeTypeHTTP {
+			if u, _ := n.Config["url"].(string); u == "" {
+				return fmt.Errorf("http 节点 %s 缺少 url 配置", n.ID)
+			}
+		}
+		for _, key := range []string{"input_mapping", "output_mapping"} {
+			raw, ok := n.Config[key]
+			if !ok {
+				continue
+			}
+			mapping, isMap := raw.(map[string]interface{})
+			if !isMap {
+				return fmt.Errorf("节点 %s 的 %s 必须是对象", n.ID, key)
+			}
+			for k, v := range mapping {
+				if code, _ := v.(string); code == "" {
+					return fmt.Errorf("节点 %s 的 %s.%s 必须是非空表达式", n.ID, key, k)
+				}
+			}
+		}
+	}
+	for i := range d.Nodes {
+		spec, err := d.Nodes[i].JoinSpec()
+		if err != nil {
+			return err
+		}
+		if preds := len(d.JoinPredecessors(d.Nodes[i].ID)); spec.Quorum > preds {
+			return fmt.Errorf("节点 %s 的 join 需要 %d 个前驱到达，但只有 %d 个前驱", d.Nodes[i].ID, spec.Quorum, preds)
+		}
+		if d.Nodes[i].Type == NodeTypeApproval {
+			if _, _, err := d.Nodes[i].ApprovalSpec(); err != nil {
+				return err
+			}
+		}
+		if _, err := d.Nodes[i].CompensateConfig(); err != nil {
+			return err
+		}
+		if d.Nodes[i].Type == NodeTypeLoop {
+			if err := d.validateLoopNode(&d.Nodes[i]); err != nil {
+				return err
+			}
+		}
 	}
 	for _, e := range d.Edges {
 		if !nodeSet[e.From] {
@@ -125,6 +303,9 @@ func (d *DAG) Validate() error {
 		if !nodeSet[e.To] {
 			return fmt.Errorf("边引用不存在的目标节点: %s", e.To)
 		}
+		if err := d.validateDecisionEdge(e); err != nil {
+			return err
+		}
 	}
 	if len(d.GetStartNodes()) == 0 {
 		return fmt.Errorf("DAG 必须至少有一个起始节点（无入边的节点）")
@@ -135,6 +316,71 @@ func (d *DAG) Validate() error {
 	return nil
 }
 
+// validateLoopNode loop 节点的 body 须为其直接后继，且循环体须经 is_loopback 边回到本节点
+func (d *DAG) validateLoopNode(n *Node) error {
+	body, _ := n.Config["body"].(string)
+	if body == "" {
+		return fmt.Errorf("loop 节点 %s 缺少 body 配置", n.ID)
+	}
+	hasBodyEdge, hasLoopback := false, false
+	for _, e := range d.Edges {
+		if e.From == n.ID && e.To == body && e.Type != EdgeTypeError {
+			hasBodyEdge = true
+		}
+		if e.To == n.ID && e.IsLoopback {
+			hasLoopback = true
+		}
+	}
+	if !hasBodyEdge {
+		return fmt.Errorf("loop 节点 %s 的 body %s 不是它的直接后继", n.ID, body)
+	}
+	if !hasLoopback {
+		return fmt.Errorf("loop 节点 %s 缺少从循环体回到自身的 is_loopback 边", n.ID)
+	}
+	return nil
+}
+
+// validateDecisionEdge 带 decision 的边必须从托管审批节点出发，且决定在节点允许范围内；
+// escalate 边要求节点配置了 deadline。wait_signal 节点只允许 timeout 边，且要求配置了 timeout
+func (d *DAG) validateDecisionEdge(e Edge) error {
+	if e.Decision == "" {
+		return nil
+	}
+	from := d.GetNode(e.From)
+	if from.Type != NodeTypeApproval && from.Type != NodeTypeWaitSignal {
+		return fmt.Errorf("边 %s->%s 配置了 decision，但源节点不是审批或 wait_signal 节点", e.From, e.To)
+	}
+	if e.Type == EdgeTypeError || e.Type == EdgeTypeAlways {
+		return fmt.Errorf("边 %s->%s 是 %s 边，只有成功边可以配置 decision", e.From, e.To, e.Type)
+	}
+	if from.Type == NodeTypeWaitSignal {
+		if e.Decision != SignalDecisionTimeout {
+			return fmt.Errorf("边 %s->%s 的 decision 只能是 %s", e.From, e.To, SignalDecisionTimeout)
+		}
+		if from.Config["timeout"] == nil {
+			return fmt.Errorf("边 %s->%s 为 timeout 边，但 wait_signal 节点 %s 未配置 timeout", e.From, e.To, e.From)
+		}
+		return nil
+	}
+	spec, managed, err := from.ApprovalSpec()
+	if err != nil {
+		return err
+	}
+	if !managed {
+		return fmt.Errorf("边 %s->%s 配置了 decision，但审批节点 %s 未配置审批规则", e.From, e.To, e.From)
+	}
+	if e.Decision == ApprovalDecisionEscalate {
+		if spec.Deadline <= 0 {
+			return fmt.Errorf("边 %s->%s 为 escalate 边，但审批节点 %s 未配置 deadline", e.From, e.To, e.From)
+		}
+		return nil
+	}
+	if !spec.AllowsDecision(e.Decision) {
+		return fmt.Errorf("边 %s->%s 的 decision %s 不在审批节点 %s 允许的决定中", e.From, e.To, e.Decision, e.From)
+	}
+	return nil
+}
+
 // detectCycle 使用 DFS 检测有向图中是否存在环，IsLoopback 的边不参与环路检测
 func (d *DAG) detectCycle() error {
 	adj := make(map[string][]string)
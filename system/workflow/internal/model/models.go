@@ -0,0 +1,26 @@
+package model
+
+// AllModels 工作流模块的全部持久化模型，供数据库迁移与测试建表共用；新增表只需在此登记
+func AllModels() []interface{} {
+	return []interface{}{
+		&WorkflowDefModel{},
+		&WorkflowInstanceModel{},
+		&WorkflowCheckpointModel{},
+		&WorkflowAppliedCallbackModel{},
+		&WorkflowScheduleModel{},
+		&WorkflowScheduleRunModel{},
+		&WorkflowTriggerModel{},
+		&WorkflowTriggerDeliveryModel{},
+		&WorkflowInstanceEventModel{},
+		&WorkflowApprovalModel{},
+		&WorkflowApprovalAssigneeModel{},
+		&WorkflowApprovalDecisionModel{},
+		&WorkflowInstanceMigrationModel{},
+		&WorkflowSignalWaitModel{},
+		&WorkflowBusinessKeyModel{},
+		&WorkflowRetentionPolicyModel{},
+		&WorkflowInstanceArchiveModel{},
+		&WorkflowCheckpointArchiveModel{},
+		&WorkflowNodeRunModel{},
+	}
+}
@@ -0,0 +1,202 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/xsxdot/aio/pkg/core/model/common"
+)
+
+// 审批决定，Edge.Decision 按此路由
+const (
+	ApprovalDecisionApprove        = "approve"
+	ApprovalDecisionReject         = "reject"
+	ApprovalDecisionRequestChanges = "request_changes"
+	// ApprovalDecisionEscalate 截止时间到期仍未决定时由引擎产生，只用于路由升级边
+	ApprovalDecisionEscalate = "escalate"
+)
+
+// ApprovalStatus 审批待办状态
+type ApprovalStatus string
+
+const (
+	ApprovalStatusPending          ApprovalStatus = "PENDING"
+	ApprovalStatusApproved         ApprovalStatus = "APPROVED"
+	ApprovalStatusRejected         ApprovalStatus = "REJECTED"
+	ApprovalStatusChangesRequested ApprovalStatus = "CHANGES_REQUESTED"
+	ApprovalStatusEscalated        ApprovalStatus = "ESCALATED" // 截止到期，经 escalate 边升级
+	ApprovalStatusExpired          ApprovalStatus = "EXPIRED"   // 截止到期且无升级边，按节点失败推进
+	ApprovalStatusCanceled         ApprovalStatus = "CANCELED"  // 实例取消、回滚或节点重新触发后作废
+)
+
+// 审批人类型
+const (
+	ApprovalAssigneeAdmin = "admin"
+	ApprovalAssigneeRole  = "role"
+)
+
+// ApprovalSpec 审批节点配置
+type ApprovalSpec struct {
+	AssigneeIDs   []int64
+	AssigneeRoles []string
+	// RequiredApprovals 需多少人 approve 才通过（会签），reject/request_changes 一票即定
+	RequiredApprovals int
+	Decisions         []string
+	FormSchema        map[string]interface{}
+	Deadline          time.Duration
+}
+
+// AllowsDecision 是否允许审批人做出该决定
+func (s ApprovalSpec) AllowsDecision(decision string) bool {
+	for _, d := range s.Decisions {
+		if d == decision {
+			return true
+		}
+	}
+	return false
+}
+
+// ApprovalSpec 解析审批节点配置：assignee_ids、assignee_roles、required_approvals、
+// decisions、form_schema、deadline。managed 为 false 表示未做任何配置，
+// 节点保持由外部直接上报完成的原语义
+func (n *Node) ApprovalSpec() (spec ApprovalSpec, managed bool, err error) {
+	spec = ApprovalSpec{
+		RequiredApprovals: 1,
+		Decisions:         []string{ApprovalDecisionApprove, ApprovalDecisionReject},
+	}
+	for _, key := range []string{"assignee_ids", "assignee_roles", "required_approvals", "decisions", "form_schema", "deadline"} {
+		if _, ok := n.Config[key]; ok {
+			managed = true
+		}
+	}
+	if !managed {
+		return spec, false, nil
+	}
+
+	if raw, ok := n.Config["assignee_ids"]; ok {
+		list, isList := raw.([]interface{})
+		if !isList {
+			return spec, true, fmt.Errorf("审批节点 %s 的 assignee_ids 必须是数组", n.ID)
+		}
+		for _, v := range list {
+			id, isNum := v.(float64)
+			if !isNum || id <= 0 || id != float64(int64(id)) {
+				return spec, true, fmt.Errorf("审批节点 %s 的 assignee_ids 必须是正整数: %v", n.ID, v)
+			}
+			spec.AssigneeIDs = append(spec.AssigneeIDs, int64(id))
+		}
+	}
+	if raw, ok := n.Config["assignee_roles"]; ok {
+		list, isList := raw.([]interface{})
+		if !isList {
+			return spec, true, fmt.Errorf("审批节点 %s 的 assignee_roles 必须是数组", n.ID)
+		}
+		for _, v := range list {
+			role, _ := v.(string)
+			if role == "" {
+				return spec, true, fmt.Errorf("审批节点 %s 的 assignee_roles 必须是非空字符串: %v", n.ID, v)
+			}
+			spec.AssigneeRoles = append(spec.AssigneeRoles, role)
+		}
+	}
+	if raw, ok := n.Config["required_approvals"]; ok {
+		q, isNum := raw.(float64)
+		if !isNum || q < 1 || q != float64(int(q)) {
+			return spec, true, fmt.Errorf("审批节点 %s 的 required_approvals 必须是正整数: %v", n.ID, raw)
+		}
+		spec.RequiredApprovals = int(q)
+		if len(spec.AssigneeRoles) == 0 && len(spec.AssigneeIDs) > 0 && spec.RequiredApprovals > len(spec.AssigneeIDs) {
+			return spec, true, fmt.Errorf("审批节点 %s 需要 %d 人通过，但只指定了 %d 名审批人", n.ID, spec.RequiredApprovals, len(spec.AssigneeIDs))
+		}
+	}
+	if raw, ok := n.Config["decisions"]; ok {
+		list, isList := raw.([]interface{})
+		if !isList || len(list) == 0 {
+			return spec, true, fmt.Errorf("审批节点 %s 的 decisions 必须是非空数组", n.ID)
+		}
+		spec.Decisions = nil
+		for _, v := range list {
+			switch d, _ := v.(string); d {
+			case ApprovalDecisionApprove, ApprovalDecisionReject, ApprovalDecisionRequestChanges:
+				spec.Decisions = append(spec.Decisions, d)
+			default:
+				return spec, true, fmt.Errorf("审批节点 %s 的 decisions 仅支持 approve、reject、request_changes: %v", n.ID, v)
+			}
+		}
+	}
+	if raw, ok := n.Config["form_schema"]; ok {
+		schema, isMap := raw.(map[string]interface{})
+		if !isMap {
+			return spec, true, fmt.Errorf("审批节点 %s 的 form_schema 必须是对象", n.ID)
+		}
+		spec.FormSchema = schema
+	}
+	if raw, ok := n.Config["deadline"]; ok {
+		switch d := raw.(type) {
+		case string:
+			parsed, pErr := time.ParseDuration(d)
+			if pErr != nil || parsed <= 0 {
+				return spec, true, fmt.Errorf("审批节点 %s 的 deadline 格式无效: %s", n.ID, d)
+			}
+			spec.Deadline = parsed
+		case float64:
+			if d <= 0 {
+				return spec, true, fmt.Errorf("审批节点 %s 的 deadline 必须大于 0", n.ID)
+			}
+			spec.Deadline = time.Duration(d * float64(time.Second))
+		default:
+			return spec, true, fmt.Errorf("审批节点 %s 的 deadline 类型无效: %T", n.ID, raw)
+		}
+	}
+	return spec, true, nil
+}
+
+// WorkflowApprovalModel 审批待办：托管审批节点每触发一次生成一条
+type WorkflowApprovalModel struct {
+	common.Model
+	InstanceID        int64          `gorm:"column:instance_id;not null;index:idx_approval_instance_node" json:"instance_id" comment:"实例ID"`
+	NodeID            string         `gorm:"column:node_id;size:100;not null;index:idx_approval_instance_node" json:"node_id" comment:"审批节点ID"`
+	DefID             int64          `gorm:"column:def_id;not null" json:"def_id" comment:"定义ID"`
+	Env               string         `gorm:"column:env;size:50;default:''" json:"env" comment:"实例环境"`
+	RequiredApprovals int            `gorm:"column:required_approvals;not null;default:1" json:"required_approvals" comment:"通过所需 approve 人数"`
+	Decisions         string         `gorm:"column:decisions;type:json" json:"decisions" comment:"允许的决定JSON数组"`
+	FormSchema        string         `gorm:"column:form_schema;type:json" json:"form_schema" comment:"审批表单 schema JSON"`
+	DeadlineAt        *time.Time     `gorm:"column:deadline_at" json:"deadline_at" comment:"截止时间，空表示不限"`
+	Status            ApprovalStatus `gorm:"column:status;size:20;not null;index" json:"status" comment:"状态"`
+	Decision          string         `gorm:"column:decision;size:30;default:''" json:"decision" comment:"最终决定"`
+	DecidedAt         *time.Time     `gorm:"column:decided_at" json:"decided_at" comment:"决定时间"`
+}
+
+func (WorkflowApprovalModel) TableName() string {
+	return "aio_workflow_approval"
+}
+
+// WorkflowApprovalAssigneeModel 审批待办的审批人，Kind 为 admin 时 Value 是管理员ID，为 role 时是角色名。
+// 一条待办没有任何审批人时，任一有审批权限的管理员都可以决定
+type WorkflowApprovalAssigneeModel struct {
+	common.Model
+	ApprovalID int64  `gorm:"column:approval_id;not null;index" json:"approval_id" comment:"审批待办ID"`
+	Kind       string `gorm:"column:kind;size:20;not null;index:idx_approval_assignee" json:"kind" comment:"审批人类型 admin/role"`
+	Value      string `gorm:"column:value;size:100;not null;index:idx_approval_assignee" json:"value" comment:"管理员ID或角色名"`
+}
+
+func (WorkflowApprovalAssigneeModel) TableName() string {
+	return "aio_workflow_approval_assignee"
+}
+
+// WorkflowApprovalDecisionModel 审批人做出的决定，同一待办每位管理员只能决定一次
+type WorkflowApprovalDecisionModel struct {
+	common.Model
+	ApprovalID   int64  `gorm:"column:approval_id;not null;uniqueIndex:idx_approval_decision_admin" json:"approval_id" comment:"审批待办ID"`
+	InstanceID   int64  `gorm:"column:instance_id;not null" json:"instance_id" comment:"实例ID"`
+	NodeID       string `gorm:"column:node_id;size:100;not null" json:"node_id" comment:"审批节点ID"`
+	AdminID      int64  `gorm:"column:admin_id;not null;uniqueIndex:idx_approval_decision_admin" json:"admin_id" comment:"管理员ID"`
+	AdminAccount string `gorm:"column:admin_account;size:100;default:''" json:"admin_account" comment:"管理员账号"`
+	Decision     string `gorm:"column:decision;size:30;not null" json:"decision" comment:"决定"`
+	Comment      string `gorm:"column:comment;type:text" json:"comment" comment:"审批意见"`
+	Payload      string `gorm:"column:payload;type:json" json:"payload" comment:"表单数据JSON"`
+}
+
+func (WorkflowApprovalDecisionModel) TableName() string {
+	return "aio_workflow_approval_decision"
+}
@@ -0,0 +1,17 @@
+package model
+
+import "github.com/xsxdot/aio/pkg/core/model/common"
+
+// WorkflowBusinessKeyModel 业务键占用：(env, def_code, business_key) 唯一，指向最近一次用该键
+// 启动的实例。实例进入终态后不删除，下一次用同一键启动时在行锁下改指向新实例
+type WorkflowBusinessKeyModel struct {
+	common.Model
+	Env         string `gorm:"column:env;size:50;not null;default:'';uniqueIndex:idx_business_key_scope" json:"env" comment:"环境标识"`
+	DefCode     string `gorm:"column:def_code;size:100;not null;uniqueIndex:idx_business_key_scope" json:"def_code" comment:"定义编码"`
+	BusinessKey string `gorm:"column:business_key;size:200;not null;uniqueIndex:idx_business_key_scope" json:"business_key" comment:"业务键"`
+	InstanceID  int64  `gorm:"column:instance_id;not null;default:0" json:"instance_id" comment:"占用该键的实例ID"`
+}
+
+func (WorkflowBusinessKeyModel) TableName() string {
+	return "aio_workflow_business_key"
+}
@@ -11,6 +11,9 @@ type WorkflowDefModel struct {
 	Version int32  `gorm:"column:version;not null;default:1;uniqueIndex:idx_env_code_version" json:"version" comment:"版本号"`
 	Name    string `gorm:"column:name;size:255;not null" json:"name" comment:"名称"`
 	DAGJSON string `gorm:"column:dag_json;type:json" json:"dag_json" comment:"DAG结构JSON"`
+	// InputSchema / StateSchema 取自 DAG 顶层的 input_schema / state_schema，未声明时为空
+	InputSchema string `gorm:"column:input_schema;type:text" json:"input_schema" comment:"初始输入 JSON Schema"`
+	StateSchema string `gorm:"column:state_schema;type:text" json:"state_schema" comment:"状态 data 区 JSON Schema"`
 }
 
 func (WorkflowDefModel) TableName() string {
@@ -14,6 +14,12 @@ const (
 	InstanceStatusCompleted WorkflowInstanceStatus = "COMPLETED"
 	InstanceStatusFailed    WorkflowInstanceStatus = "FAILED"
 	InstanceStatusCanceled  WorkflowInstanceStatus = "CANCELED"
+	// InstanceStatusPaused 已暂停：照常接收节点回调并记录输出，但恢复前不触发后继节点
+	InstanceStatusPaused WorkflowInstanceStatus = "PAUSED"
+	// InstanceStatusCompensating 正在逆序执行已完成节点的补偿任务
+	InstanceStatusCompensating WorkflowInstanceStatus = "COMPENSATING"
+	// InstanceStatusCompensated 补偿全部完成（终态）
+	InstanceStatusCompensated WorkflowInstanceStatus = "COMPENSATED"
 )
 
 type WorkflowInstanceModel struct {
@@ -25,6 +31,17 @@ type WorkflowInstanceModel struct {
 	InitialState  string                 `gorm:"column:initial_state;type:json" json:"initial_state" comment:"启动时初始状态JSON，用于回滚到起始节点"`
 	CurrentState  string                 `gorm:"column:current_state;type:json" json:"current_state" comment:"当前全局状态JSON"`
 	ActiveNodeIDs string                 `gorm:"column:active_node_ids;type:json" json:"active_node_ids" comment:"当前活跃节点列表JSON"`
+	// ParentInstanceID / ParentNodeID 由 subworkflow 节点拉起时指向父实例及其节点，顶层实例为 0/空
+	ParentInstanceID int64  `gorm:"column:parent_instance_id;not null;default:0;index:idx_parent_node" json:"parent_instance_id" comment:"父实例ID（子工作流）"`
+	ParentNodeID     string `gorm:"column:parent_node_id;size:100;default:'';index:idx_parent_node" json:"parent_node_id" comment:"父实例中拉起本实例的节点ID"`
+	// ForkedFromInstanceID / ForkedFromCheckpointID 从历史检查点分叉创建时指向来源实例及检查点，否则为 0
+	ForkedFromInstanceID   int64 `gorm:"column:forked_from_instance_id;not null;default:0;index" json:"forked_from_instance_id" comment:"分叉来源实例ID"`
+	ForkedFromCheckpointID int64 `gorm:"column:forked_from_checkpoint_id;not null;default:0" json:"forked_from_checkpoint_id" comment:"分叉来源检查点ID"`
+	// BusinessKey 调用方指定的业务键，同一 env + 定义编码下未结束的实例唯一，见 WorkflowBusinessKeyModel
+	BusinessKey string `gorm:"column:business_key;size:200;default:'';index" json:"business_key" comment:"业务键"`
+	// EventSeq 已分配的最大事件序号，只由 WorkflowInstanceEventDao.Append 原子自增；
+	// 仅在创建时写入，避免推进路径整行 Save 实例时用旧值覆盖
+	EventSeq int64 `gorm:"column:event_seq;not null;default:0;<-:create" json:"-" comment:"已分配的最大事件序号"`
 }
 
 type WorkflowInstanceListItem struct {
@@ -35,6 +52,7 @@ type WorkflowInstanceListItem struct {
 	Env           string                 `json:"env"`
 	Status        WorkflowInstanceStatus `json:"status"`
 	ActiveNodeIDs string                 `json:"active_node_ids"`
+	BusinessKey   string                 `json:"business_key"`
 	CreatedAt     time.Time              `json:"createdAt"`
 }
 
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"github.com/xsxdot/aio/pkg/core/model/common"
+)
+
+// InstanceEventType 实例事件类型
+type InstanceEventType string
+
+const (
+	InstanceEventNodeTriggered  InstanceEventType = "node_triggered"  // 节点被触发（派发任务、进入等待等）
+	InstanceEventNodeCompleted  InstanceEventType = "node_completed"  // 节点完成（含失败走 error 边）
+	InstanceEventStateChanged   InstanceEventType = "state_changed"   // 业务状态发生变化
+	InstanceEventWaiting        InstanceEventType = "waiting"         // 实例进入 WAITING，等待外部推进
+	InstanceEventSignalReceived InstanceEventType = "signal_received" // 收到外部信号
+	InstanceEventTerminal       InstanceEventType = "terminal"        // 实例进入终态 COMPLETED/FAILED/CANCELED/COMPENSATED
+	InstanceEventCompensation   InstanceEventType = "compensation"    // 补偿进度（开始、单步成功或失败）
+	InstanceEventPaused         InstanceEventType = "paused"          // 实例被暂停
+	InstanceEventResumed        InstanceEventType = "resumed"         // 实例恢复，补派暂停期间积压的节点
+	InstanceEventMigrated       InstanceEventType = "migrated"        // 实例迁移到新的定义版本
+)
+
+// WorkflowInstanceEventModel 实例事件流水。Seq 为实例内从 1 开始连续递增的序号，
+// 订阅方断线后携带最后收到的 Seq 重连即可续传
+type WorkflowInstanceEventModel struct {
+	common.Model
+	InstanceID int64             `gorm:"column:instance_id;not null;uniqueIndex:idx_instance_event_seq" json:"instance_id" comment:"实例ID"`
+	Seq        int64             `gorm:"column:seq;not null;uniqueIndex:idx_instance_event_seq" json:"seq" comment:"实例内事件序号"`
+	DefID      int64             `gorm:"column:def_id;not null;index" json:"def_id" comment:"定义ID，用于按定义订阅"`
+	Env        string            `gorm:"column:env;size:50;default:''" json:"env" comment:"实例环境"`
+	Type       InstanceEventType `gorm:"column:event_type;size:30;not null" json:"type" comment:"事件类型"`
+	NodeID     string            `gorm:"column:node_id;size:100;default:''" json:"node_id" comment:"相关节点ID"`
+	Status     string            `gorm:"column:status;size:20;default:''" json:"status" comment:"事件发生后的实例状态"`
+	Payload    string            `gorm:"column:payload;type:json" json:"payload" comment:"事件详情JSON"`
+}
+
+func (WorkflowInstanceEventModel) TableName() string {
+	return "aio_workflow_instance_event"
+}
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"github.com/xsxdot/aio/pkg/core/model/common"
+)
+
+// WorkflowInstanceMigrationModel 实例迁移审计记录：每次把实例从一个定义版本迁到另一个版本
+// 都留一条，连同迁移时刻的状态快照，与 checkpoint 一样只追加不修改
+type WorkflowInstanceMigrationModel struct {
+	common.Model
+	InstanceID   int64  `gorm:"column:instance_id;not null;index" json:"instance_id" comment:"实例ID"`
+	FromDefID    int64  `gorm:"column:from_def_id;not null" json:"from_def_id" comment:"迁移前定义ID"`
+	FromVersion  int32  `gorm:"column:from_version;not null" json:"from_version" comment:"迁移前定义版本"`
+	ToDefID      int64  `gorm:"column:to_def_id;not null" json:"to_def_id" comment:"迁移后定义ID"`
+	ToVersion    int32  `gorm:"column:to_version;not null" json:"to_version" comment:"迁移后定义版本"`
+	NodeMapping  string `gorm:"column:node_mapping;type:json" json:"node_mapping" comment:"本次生效的节点ID映射JSON（旧ID->新ID）"`
+	ActiveBefore string `gorm:"column:active_before;type:json" json:"active_before" comment:"迁移前活跃节点列表JSON"`
+	ActiveAfter  string `gorm:"column:active_after;type:json" json:"active_after" comment:"迁移后活跃节点列表JSON"`
+	StateAfter   string `gorm:"column:state_after;type:json" json:"state_after" comment:"迁移后完整状态JSON"`
+}
+
+func (WorkflowInstanceMigrationModel) TableName() string {
+	return "aio_workflow_instance_migration"
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"time"
+
+	"github.com/xsxdot/aio/pkg/core/model/common"
+)
+
+// NodeRunStatus 节点执行记录状态
+type NodeRunStatus string
+
+const (
+	NodeRunStatusRunning   NodeRunStatus = "RUNNING"
+	NodeRunStatusSucceeded NodeRunStatus = "SUCCEEDED"
+	NodeRunStatusFailed    NodeRunStatus = "FAILED"    // 节点输出带 error_msg（含超时），是否经 error 边见 ErrorEdge
+	NodeRunStatusAbandoned NodeRunStatus = "ABANDONED" // 未完成即被同节点的再次触发（回滚、回环重跑）取代
+)
+
+// WorkflowNodeRunModel 节点执行记录：节点每触发一次生成一条，完成时回填结束时间、结果、
+// 对应的检查点与 executor 任务尝试，用于按定义统计各节点耗时与失败分布。
+//
+// 有 executor 任务的节点（task/http/map）：RunMs 为各次尝试执行时长之和，QueueWaitMs 为任务
+// 从入队到最后一次尝试结束中未在执行的时长（排队与重试退避）；Map 节点累计全部子任务。
+// 其余节点不经过队列，QueueWaitMs 为 0，RunMs 等于 DurationMs。
+type WorkflowNodeRunModel struct {
+	common.Model
+	InstanceID   int64         `gorm:"column:instance_id;not null;index:idx_node_run_instance_node" json:"instance_id" comment:"实例ID"`
+	NodeID       string        `gorm:"column:node_id;size:100;not null;index:idx_node_run_instance_node" json:"node_id" comment:"节点ID"`
+	NodeType     string        `gorm:"column:node_type;size:30;default:''" json:"node_type" comment:"节点类型"`
+	DefID        int64         `gorm:"column:def_id;not null;index:idx_node_run_def_started" json:"def_id" comment:"定义ID（对应定义编码与版本）"`
+	Env          string        `gorm:"column:env;size:50;default:''" json:"env" comment:"实例环境"`
+	Status       NodeRunStatus `gorm:"column:status;size:20;not null" json:"status" comment:"状态"`
+	ErrorEdge    bool          `gorm:"column:error_edge;default:false" json:"error_edge" comment:"失败后是否经 error 边降级"`
+	StartedAt    time.Time     `gorm:"column:started_at;not null;index:idx_node_run_def_started" json:"started_at" comment:"节点触发时间"`
+	FinishedAt   *time.Time    `gorm:"column:finished_at" json:"finished_at" comment:"节点完成时间"`
+	DurationMs   int64         `gorm:"column:duration_ms;default:0" json:"duration_ms" comment:"触发到完成的时长（毫秒）"`
+	QueueWaitMs  int64         `gorm:"column:queue_wait_ms;default:0" json:"queue_wait_ms" comment:"任务排队与重试退避时长（毫秒）"`
+	RunMs        int64         `gorm:"column:run_ms;default:0" json:"run_ms" comment:"任务执行时长（毫秒）"`
+	Jobs         int32         `gorm:"column:jobs;default:0" json:"jobs" comment:"完成的 executor 任务数（Map 为子任务数）"`
+	Attempts     int32         `gorm:"column:attempts;default:0" json:"attempts" comment:"executor 任务尝试总数，重试次数为 Attempts-Jobs"`
+	JobID        int64         `gorm:"column:job_id;default:0" json:"job_id" comment:"最后完成的 executor 任务ID"`
+	AttemptID    int64         `gorm:"column:attempt_id;default:0" json:"attempt_id" comment:"产生结果的任务尝试ID（executor_job_attempts）"`
+	CheckpointID int64         `gorm:"column:checkpoint_id;default:0" json:"checkpoint_id" comment:"完成时写入的检查点ID"`
+}
+
+func (WorkflowNodeRunModel) TableName() string {
+	return "aio_workflow_node_run"
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"time"
+
+	"github.com/xsxdot/aio/pkg/core/model/common"
+)
+
+// RetentionAction 保留期满后的处置方式
+type RetentionAction string
+
+const (
+	// RetentionActionArchive 把实例与检查点移入归档表后清理
+	RetentionActionArchive RetentionAction = "archive"
+	// RetentionActionExport 把实例与检查点导出为 gzip 压缩的 JSON 上传到 OSS 后清理
+	RetentionActionExport RetentionAction = "export"
+)
+
+// WorkflowRetentionPolicyModel 实例保留策略：终态实例在 updated_at 之后 RetainDays 天被处置。
+// DefCode 为空表示 env 下所有没有单独策略的定义；同一 env + def_code 至多一条
+type WorkflowRetentionPolicyModel struct {
+	common.Model
+	Env        string          `gorm:"column:env;size:50;not null;default:'';index" json:"env" comment:"环境标识"`
+	DefCode    string          `gorm:"column:def_code;size:100;not null;default:''" json:"def_code" comment:"定义编码，空表示整个环境"`
+	RetainDays int             `gorm:"column:retain_days;not null" json:"retain_days" comment:"终态后保留天数"`
+	Action     RetentionAction `gorm:"column:action;size:20;not null" json:"action" comment:"处置方式 archive/export"`
+	LastRunAt  *time.Time      `gorm:"column:last_run_at" json:"last_run_at" comment:"最近一次执行时间"`
+}
+
+func (WorkflowRetentionPolicyModel) TableName() string {
+	return "aio_workflow_retention_policy"
+}
+
+// WorkflowInstanceArchiveModel 归档的实例，ID 与原实例一致，CreatedAt/UpdatedAt 保留原值
+type WorkflowInstanceArchiveModel struct {
+	ID                     int64                  `gorm:"column:id;primaryKey;autoIncrement:false" json:"id"`
+	DefID                  int64                  `gorm:"column:def_id;not null;index" json:"def_id" comment:"关联的定义ID"`
+	DefVersion             int32                  `gorm:"column:def_version;not null" json:"def_version" comment:"关联的定义版本"`
+	Env                    string                 `gorm:"column:env;size:50;default:''" json:"env" comment:"环境标识"`
+	Status                 WorkflowInstanceStatus `gorm:"column:status;size:20;not null" json:"status" comment:"实例状态"`
+	InitialState           string                 `gorm:"column:initial_state;type:json" json:"initial_state" comment:"启动时初始状态JSON"`
+	CurrentState           string                 `gorm:"column:current_state;type:json" json:"current_state" comment:"最终全局状态JSON"`
+	ActiveNodeIDs          string                 `gorm:"column:active_node_ids;type:json" json:"active_node_ids" comment:"最终活跃节点列表JSON"`
+	ParentInstanceID       int64                  `gorm:"column:parent_instance_id;not null;default:0" json:"parent_instance_id" comment:"父实例ID（子工作流）"`
+	ParentNodeID           string                 `gorm:"column:parent_node_id;size:100;default:''" json:"parent_node_id" comment:"父实例中拉起本实例的节点ID"`
+	ForkedFromInstanceID   int64                  `gorm:"column:forked_from_instance_id;not null;default:0" json:"forked_from_instance_id" comment:"分叉来源实例ID"`
+	ForkedFromCheckpointID int64                  `gorm:"column:forked_from_checkpoint_id;not null;default:0" json:"forked_from_checkpoint_id" comment:"分叉来源检查点ID"`
+	BusinessKey            string                 `gorm:"column:business_key;size:200;default:''" json:"business_key" comment:"业务键"`
+	CreatedAt              time.Time              `gorm:"column:created_at;autoCreateTime:false" json:"createdAt"`
+	UpdatedAt              time.Time              `gorm:"column:updated_at;autoUpdateTime:false" json:"updatedAt"`
+	ArchivedAt             time.Time              `gorm:"column:archived_at;index" json:"archived_at" comment:"归档时间"`
+}
+
+func (WorkflowInstanceArchiveModel) TableName() string {
+	return "aio_workflow_instance_archive"
+}
+
+// WorkflowCheckpointArchiveModel 归档的检查点，ID 与原检查点一致
+type WorkflowCheckpointArchiveModel struct {
+	ID         int64     `gorm:"column:id;primaryKey;autoIncrement:false" json:"id"`
+	InstanceID int64     `gorm:"column:instance_id;not null;index" json:"instance_id" comment:"实例ID"`
+	NodeID     string    `gorm:"column:node_id;size:100;not null" json:"node_id" comment:"节点ID"`
+	NodeOutput string    `gorm:"column:node_output;type:json" json:"node_output" comment:"节点输出JSON"`
+	StateAfter string    `gorm:"column:state_after;type:json" json:"state_after" comment:"执行后完整状态JSON"`
+	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime:false" json:"createdAt"`
+	ArchivedAt time.Time `gorm:"column:archived_at" json:"archived_at" comment:"归档时间"`
+}
+
+func (WorkflowCheckpointArchiveModel) TableName() string {
+	return "aio_workflow_checkpoint_archive"
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"time"
+
+	"github.com/xsxdot/aio/pkg/core/model/common"
+)
+
+// ScheduleOverlapPolicy 上一次触发拉起的实例仍未结束时，新触发点的处理策略
+type ScheduleOverlapPolicy string
+
+const (
+	OverlapSkip  ScheduleOverlapPolicy = "skip"  // 跳过本次触发，仅记录历史
+	OverlapQueue ScheduleOverlapPolicy = "queue" // 排队，待在途实例结束后再依次启动
+	OverlapAllow ScheduleOverlapPolicy = "allow" // 直接启动，允许多个实例并行
+)
+
+type WorkflowScheduleStatus string
+
+const (
+	ScheduleStatusActive WorkflowScheduleStatus = "ACTIVE"
+	ScheduleStatusPaused WorkflowScheduleStatus = "PAUSED"
+)
+
+// WorkflowScheduleModel 工作流定时调度：按 cron 表达式周期性启动某个定义的新实例
+type WorkflowScheduleModel struct {
+	common.Model
+	Env                 string                 `gorm:"column:env;size:50;default:'';index" json:"env" comment:"环境标识，拉起的实例与 executor 任务均使用该环境"`
+	Name                string                 `gorm:"column:name;size:100;not null" json:"name" comment:"调度名称"`
+	DefCode             string                 `gorm:"column:def_code;size:100;not null;index" json:"def_code" comment:"工作流定义编码"`
+	DefVersion          int32                  `gorm:"column:def_version;not null;default:0" json:"def_version" comment:"定义版本，0 表示每次触发时取最新版本"`
+	CronExpr            string                 `gorm:"column:cron_expr;size:100;not null" json:"cron_expr" comment:"cron 表达式（5 段或 @every/@daily 等描述符）"`
+	Timezone            string                 `gorm:"column:timezone;size:64;default:''" json:"timezone" comment:"cron 求值时区（IANA 名称），空表示服务器本地时区"`
+	InitialDataTemplate string                 `gorm:"column:initial_data_template;type:text" json:"initial_data_template" comment:"初始数据模板（text/template 渲染后须为 JSON 对象）"`
+	OverlapPolicy       ScheduleOverlapPolicy  `gorm:"column:overlap_policy;size:20;not null;default:'skip'" json:"overlap_policy" comment:"重叠策略 skip/queue/allow"`
+	Status              WorkflowScheduleStatus `gorm:"column:status;size:20;not null;index:idx_schedule_due" json:"status" comment:"调度状态 ACTIVE/PAUSED"`
+	// NextFireAt 下一个触发点；各 aio 实例以它为条件抢占触发，抢到者把它推进到再下一个触发点
+	NextFireAt *time.Time `gorm:"column:next_fire_at;index:idx_schedule_due" json:"next_fire_at" comment:"下次触发时间"`
+	LastFireAt *time.Time `gorm:"column:last_fire_at" json:"last_fire_at" comment:"最近一次触发时间"`
+}
+
+func (WorkflowScheduleModel) TableName() string {
+	return "aio_workflow_schedule"
+}
+
+// ScheduleRunTrigger 调度运行的触发来源
+type ScheduleRunTrigger string
+
+const (
+	ScheduleTriggerCron   ScheduleRunTrigger = "cron"   // 到达 cron 触发点
+	ScheduleTriggerManual ScheduleRunTrigger = "manual" // 管理端「立即运行」
+)
+
+type ScheduleRunStatus string
+
+const (
+	ScheduleRunStarted ScheduleRunStatus = "STARTED" // 已拉起实例
+	ScheduleRunSkipped ScheduleRunStatus = "SKIPPED" // 因 skip 策略跳过
+	ScheduleRunQueued  ScheduleRunStatus = "QUEUED"  // 因 queue 策略排队中，出队后转为 STARTED
+	ScheduleRunFailed  ScheduleRunStatus = "FAILED"  // 拉起实例失败（如定义不存在、模板渲染失败）
+)
+
+// WorkflowScheduleRunModel 调度的每一次触发记录，即调度的运行历史
+type WorkflowScheduleRunModel struct {
+	common.Model
+	ScheduleID  int64              `gorm:"column:schedule_id;not null;index:idx_schedule_run" json:"schedule_id" comment:"调度ID"`
+	Trigger     ScheduleRunTrigger `gorm:"column:trigger_type;size:20;not null" json:"trigger" comment:"触发来源 cron/manual"`
+	Status      ScheduleRunStatus  `gorm:"column:status;size:20;not null;index:idx_schedule_run" json:"status" comment:"触发结果"`
+	ScheduledAt time.Time          `gorm:"column:scheduled_at;not null" json:"scheduled_at" comment:"计划触发时间（manual 为请求时间）"`
+	InstanceID  int64              `gorm:"column:instance_id;not null;default:0;index" json:"instance_id" comment:"拉起的实例ID，未拉起为 0"`
+	Error       string             `gorm:"column:error;size:1000;default:''" json:"error" comment:"失败原因"`
+}
+
+func (WorkflowScheduleRunModel) TableName() string {
+	return "aio_workflow_schedule_run"
+}
+
+// WorkflowScheduleRunItem 运行历史列表项，附带所拉起实例的当前状态
+type WorkflowScheduleRunItem struct {
+	ID             int64              `json:"id"`
+	ScheduleID     int64              `json:"schedule_id"`
+	Trigger        ScheduleRunTrigger `gorm:"column:trigger_type" json:"trigger"`
+	Status         ScheduleRunStatus  `json:"status"`
+	ScheduledAt    time.Time          `json:"scheduled_at"`
+	InstanceID     int64              `json:"instance_id"`
+	InstanceStatus string             `json:"instance_status"`
+	Error          string             `json:"error"`
+	CreatedAt      time.Time          `json:"createdAt"`
+}
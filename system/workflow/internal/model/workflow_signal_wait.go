@@ -0,0 +1,38 @@
+package model
+
+import (
+	"time"
+
+	"github.com/xsxdot/aio/pkg/core/model/common"
+)
+
+// SignalDecisionTimeout wait_signal 节点等待超时时由引擎产生，只用于路由超时边
+const SignalDecisionTimeout = "timeout"
+
+// SignalWaitStatus 信号等待状态
+type SignalWaitStatus string
+
+const (
+	SignalWaitStatusWaiting  SignalWaitStatus = "WAITING"
+	SignalWaitStatusReceived SignalWaitStatus = "RECEIVED"
+	SignalWaitStatusTimedOut SignalWaitStatus = "TIMED_OUT" // 超时，经 timeout 边或按节点失败推进
+	SignalWaitStatusCanceled SignalWaitStatus = "CANCELED"  // 实例取消、回滚或节点重新触发后作废
+)
+
+// WorkflowSignalWaitModel 信号等待：wait_signal 节点每触发一次生成一条，
+// 外部服务可按 env + 信号名 + 关联键投递信号而无需知道实例 ID
+type WorkflowSignalWaitModel struct {
+	common.Model
+	InstanceID     int64            `gorm:"column:instance_id;not null;index:idx_signal_wait_instance_node" json:"instance_id" comment:"实例ID"`
+	NodeID         string           `gorm:"column:node_id;size:100;not null;index:idx_signal_wait_instance_node" json:"node_id" comment:"wait_signal 节点ID"`
+	Env            string           `gorm:"column:env;size:50;default:'';index:idx_signal_wait_key" json:"env" comment:"实例环境"`
+	SignalName     string           `gorm:"column:signal_name;size:100;not null;index:idx_signal_wait_key" json:"signal_name" comment:"等待的信号名"`
+	CorrelationKey string           `gorm:"column:correlation_key;size:200;default:'';index:idx_signal_wait_key" json:"correlation_key" comment:"关联键（业务键），为空表示只能按实例投递"`
+	Status         SignalWaitStatus `gorm:"column:status;size:20;not null;index:idx_signal_wait_key" json:"status" comment:"状态"`
+	DeadlineAt     *time.Time       `gorm:"column:deadline_at" json:"deadline_at" comment:"超时时间，空表示不限"`
+	ResolvedAt     *time.Time       `gorm:"column:resolved_at" json:"resolved_at" comment:"收到信号、超时或作废的时间"`
+}
+
+func (WorkflowSignalWaitModel) TableName() string {
+	return "aio_workflow_signal_wait"
+}
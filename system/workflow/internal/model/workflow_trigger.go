@@ -0,0 +1,48 @@
+package model
+
+import (
+	"github.com/xsxdot/aio/pkg/core/model/common"
+)
+
+// WebhookAuthType Webhook 触发器的请求校验方式
+type WebhookAuthType string
+
+const (
+	WebhookAuthHMAC  WebhookAuthType = "hmac"  // 请求头携带请求体的 HMAC-SHA256 签名（十六进制）
+	WebhookAuthToken WebhookAuthType = "token" // 请求头携带与 Secret 一致的共享令牌
+)
+
+// WorkflowTriggerModel Webhook 触发器：外部系统向公开地址 /api/workflow/webhooks/{code}
+// 推送请求，校验通过后按映射构造初始状态并启动工作流
+type WorkflowTriggerModel struct {
+	common.Model
+	Code            string          `gorm:"column:code;size:100;not null;uniqueIndex:idx_trigger_code" json:"code" comment:"触发器编码，即公开地址中的路径段，全局唯一"`
+	Name            string          `gorm:"column:name;size:100;not null" json:"name" comment:"触发器名称"`
+	Env             string          `gorm:"column:env;size:50;default:''" json:"env" comment:"启动实例所用环境"`
+	DefCode         string          `gorm:"column:def_code;size:100;not null" json:"def_code" comment:"启动的工作流定义编码（最新版本）"`
+	Disabled        bool            `gorm:"column:disabled;not null;default:false" json:"disabled" comment:"是否停用"`
+	AuthType        WebhookAuthType `gorm:"column:auth_type;size:20;not null" json:"auth_type" comment:"校验方式 hmac/token"`
+	Secret          string          `gorm:"column:secret;size:255;not null" json:"-" comment:"HMAC 密钥或共享令牌"`
+	SignatureHeader string          `gorm:"column:signature_header;size:100;not null" json:"signature_header" comment:"携带签名或令牌的请求头"`
+	SignaturePrefix string          `gorm:"column:signature_prefix;size:50;default:''" json:"signature_prefix" comment:"签名值前缀，如 sha256=，校验前剥离"`
+	BodyMapping     string          `gorm:"column:body_mapping;type:json" json:"body_mapping" comment:"请求到初始状态的映射JSON（状态路径 -> 表达式），空则整个请求体作为初始状态"`
+	DedupHeader     string          `gorm:"column:dedup_header;size:100;default:''" json:"dedup_header" comment:"去重键所在请求头"`
+	DedupPath       string          `gorm:"column:dedup_path;size:200;default:''" json:"dedup_path" comment:"去重键在请求体中的路径（点分），与 dedup_header 二选一"`
+}
+
+func (WorkflowTriggerModel) TableName() string {
+	return "aio_workflow_trigger"
+}
+
+// WorkflowTriggerDeliveryModel 已受理的 Webhook 投递，(trigger_id, dedup_key) 唯一，
+// 用于吸收外部系统的重试
+type WorkflowTriggerDeliveryModel struct {
+	common.Model
+	TriggerID  int64  `gorm:"column:trigger_id;not null;uniqueIndex:idx_trigger_dedup" json:"trigger_id" comment:"触发器ID"`
+	DedupKey   string `gorm:"column:dedup_key;size:200;not null;uniqueIndex:idx_trigger_dedup" json:"dedup_key" comment:"去重键"`
+	InstanceID int64  `gorm:"column:instance_id;not null;default:0" json:"instance_id" comment:"本次投递启动的实例ID"`
+}
+
+func (WorkflowTriggerDeliveryModel) TableName() string {
+	return "aio_workflow_trigger_delivery"
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	"github.com/xsxdot/aio/system/workflow/internal/dao"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"github.com/xsxdot/gokit/logger"
+)
+
+type WorkflowApprovalService struct {
+	mvc.IBaseService[model.WorkflowApprovalModel]
+	dao         *dao.WorkflowApprovalDao
+	decisionDao *dao.WorkflowApprovalDecisionDao
+	log         *logger.Log
+	err         *errorc.ErrorBuilder
+}
+
+func NewWorkflowApprovalService(dao *dao.WorkflowApprovalDao, decisionDao *dao.WorkflowApprovalDecisionDao, log *logger.Log) *WorkflowApprovalService {
+	return &WorkflowApprovalService{
+		IBaseService: mvc.NewBaseService[model.WorkflowApprovalModel](dao),
+		dao:          dao,
+		decisionDao:  decisionDao,
+		log:          log,
+		err:          errorc.NewErrorBuilder("WorkflowApprovalService"),
+	}
+}
+
+// CreateWithAssignees 写入审批待办及其审批人
+func (s *WorkflowApprovalService) CreateWithAssignees(ctx context.Context, approval *model.WorkflowApprovalModel, assignees []*model.WorkflowApprovalAssigneeModel) error {
+	if err := s.dao.CreateWithAssignees(ctx, approval, assignees); err != nil {
+		return s.err.New("创建审批待办失败", err).DB()
+	}
+	return nil
+}
+
+// ListAssignees 列出审批待办的审批人
+func (s *WorkflowApprovalService) ListAssignees(ctx context.Context, approvalID int64) ([]*model.WorkflowApprovalAssigneeModel, error) {
+	items, err := s.dao.ListAssignees(ctx, approvalID)
+	if err != nil {
+		return nil, s.err.New("查询审批人失败", err).DB()
+	}
+	return items, nil
+}
+
+// UpdateFields 按列更新审批待办（零值也会写入）
+func (s *WorkflowApprovalService) UpdateFields(ctx context.Context, id int64, fields map[string]interface{}) error {
+	if err := s.dao.UpdateFields(ctx, id, fields); err != nil {
+		return s.err.New("更新审批待办失败", err).DB()
+	}
+	return nil
+}
+
+// CancelPending 作废实例中仍待决定的审批，nodeID 为空时不限节点
+func (s *WorkflowApprovalService) CancelPending(ctx context.Context, instanceID int64, nodeID string) error {
+	if err := s.dao.CancelPending(ctx, instanceID, nodeID); err != nil {
+		return s.err.New("作废审批待办失败", err).DB()
+	}
+	return nil
+}
+
+// ListInbox 分页列出某管理员可处理的待办
+func (s *WorkflowApprovalService) ListInbox(ctx context.Context, adminID int64, roles []string, pageNum, pageSize int32) ([]*model.WorkflowApprovalModel, int64, error) {
+	items, total, err := s.dao.ListInbox(ctx, adminID, roles, pageNum, pageSize)
+	if err != nil {
+		return nil, 0, s.err.New("查询审批待办失败", err).DB()
+	}
+	return items, total, nil
+}
+
+// CreateDecision 记录一次审批决定
+func (s *WorkflowApprovalService) CreateDecision(ctx context.Context, decision *model.WorkflowApprovalDecisionModel) error {
+	if err := s.decisionDao.Create(ctx, decision); err != nil {
+		return s.err.New("记录审批决定失败", err).DB()
+	}
+	return nil
+}
+
+// ListDecisions 按先后列出审批待办的全部决定
+func (s *WorkflowApprovalService) ListDecisions(ctx context.Context, approvalID int64) ([]*model.WorkflowApprovalDecisionModel, error) {
+	items, err := s.decisionDao.ListByApproval(ctx, approvalID)
+	if err != nil {
+		return nil, s.err.New("查询审批决定失败", err).DB()
+	}
+	return items, nil
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	"github.com/xsxdot/aio/system/workflow/internal/dao"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"github.com/xsxdot/gokit/logger"
+)
+
+type WorkflowBusinessKeyService struct {
+	mvc.IBaseService[model.WorkflowBusinessKeyModel]
+	dao *dao.WorkflowBusinessKeyDao
+	log *logger.Log
+	err *errorc.ErrorBuilder
+}
+
+func NewWorkflowBusinessKeyService(dao *dao.WorkflowBusinessKeyDao, log *logger.Log) *WorkflowBusinessKeyService {
+	return &WorkflowBusinessKeyService{
+		IBaseService: mvc.NewBaseService[model.WorkflowBusinessKeyModel](dao),
+		dao:          dao,
+		log:          log,
+		err:          errorc.NewErrorBuilder("WorkflowBusinessKeyService"),
+	}
+}
+
+// Claim 登记业务键，claimed=false 表示该键已被登记过
+func (s *WorkflowBusinessKeyService) Claim(ctx context.Context, item *model.WorkflowBusinessKeyModel) (bool, error) {
+	claimed, err := s.dao.Claim(ctx, item)
+	if err != nil {
+		return false, s.err.New("登记业务键失败", err).DB()
+	}
+	return claimed, nil
+}
+
+// FindForUpdate 带行锁查询已登记的业务键，必须在事务内使用
+func (s *WorkflowBusinessKeyService) FindForUpdate(ctx context.Context, env, defCode, businessKey string) (*model.WorkflowBusinessKeyModel, error) {
+	item, err := s.dao.FindForUpdate(ctx, env, defCode, businessKey)
+	if err != nil {
+		return nil, s.err.New("查询业务键失败", err).DB()
+	}
+	return item, nil
+}
+
+// SetInstanceID 把业务键指向新启动的实例
+func (s *WorkflowBusinessKeyService) SetInstanceID(ctx context.Context, id, instanceID int64) error {
+	if err := s.dao.SetInstanceID(ctx, id, instanceID); err != nil {
+		return s.err.New("更新业务键实例失败", err).DB()
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	"github.com/xsxdot/aio/system/workflow/internal/dao"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"github.com/xsxdot/gokit/logger"
+)
+
+type WorkflowInstanceEventService struct {
+	mvc.IBaseService[model.WorkflowInstanceEventModel]
+	dao *dao.WorkflowInstanceEventDao
+	log *logger.Log
+	err *errorc.ErrorBuilder
+}
+
+func NewWorkflowInstanceEventService(dao *dao.WorkflowInstanceEventDao, log *logger.Log) *WorkflowInstanceEventService {
+	return &WorkflowInstanceEventService{
+		IBaseService: mvc.NewBaseService[model.WorkflowInstanceEventModel](dao),
+		dao:          dao,
+		log:          log,
+		err:          errorc.NewErrorBuilder("WorkflowInstanceEventService"),
+	}
+}
+
+// Append 写入实例事件并分配实例内序号
+func (s *WorkflowInstanceEventService) Append(ctx context.Context, event *model.WorkflowInstanceEventModel) error {
+	if err := s.dao.Append(ctx, event); err != nil {
+		return s.err.New("写入实例事件失败", err).DB()
+	}
+	return nil
+}
+
+// ListByInstanceAfterSeq 列出实例中序号大于 afterSeq 的事件
+func (s *WorkflowInstanceEventService) ListByInstanceAfterSeq(ctx context.Context, instanceID, afterSeq int64, limit int) ([]*model.WorkflowInstanceEventModel, error) {
+	items, err := s.dao.ListByInstanceAfterSeq(ctx, instanceID, afterSeq, limit)
+	if err != nil {
+		return nil, s.err.New("查询实例事件失败", err).DB()
+	}
+	return items, nil
+}
+
+// ListByDefAfterID 列出某定义编码下主键大于 afterID 的事件
+func (s *WorkflowInstanceEventService) ListByDefAfterID(ctx context.Context, env, defCode string, afterID int64, limit int) ([]*model.WorkflowInstanceEventModel, error) {
+	items, err := s.dao.ListByDefAfterID(ctx, env, defCode, afterID, limit)
+	if err != nil {
+		return nil, s.err.New("查询定义事件失败", err).DB()
+	}
+	return items, nil
+}
+
+// MaxID 返回当前最大事件主键
+func (s *WorkflowInstanceEventService) MaxID(ctx context.Context) (int64, error) {
+	id, err := s.dao.MaxID(ctx)
+	if err != nil {
+		return 0, s.err.New("查询事件游标失败", err).DB()
+	}
+	return id, nil
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	"github.com/xsxdot/aio/system/workflow/internal/dao"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"github.com/xsxdot/gokit/logger"
+)
+
+type WorkflowInstanceMigrationService struct {
+	mvc.IBaseService[model.WorkflowInstanceMigrationModel]
+	dao *dao.WorkflowInstanceMigrationDao
+	log *logger.Log
+	err *errorc.ErrorBuilder
+}
+
+func NewWorkflowInstanceMigrationService(dao *dao.WorkflowInstanceMigrationDao, log *logger.Log) *WorkflowInstanceMigrationService {
+	return &WorkflowInstanceMigrationService{
+		IBaseService: mvc.NewBaseService[model.WorkflowInstanceMigrationModel](dao),
+		dao:          dao,
+		log:          log,
+		err:          errorc.NewErrorBuilder("WorkflowInstanceMigrationService"),
+	}
+}
+
+// ListByInstance 列出实例的迁移记录
+func (s *WorkflowInstanceMigrationService) ListByInstance(ctx context.Context, instanceID int64) ([]*model.WorkflowInstanceMigrationModel, error) {
+	items, err := s.dao.ListByInstance(ctx, instanceID)
+	if err != nil {
+		return nil, s.err.New("查询实例迁移记录失败", err).DB()
+	}
+	return items, nil
+}
@@ -42,3 +42,30 @@ func (s *WorkflowInstanceService) SaveWithTx(ctx context.Context, tx *gorm.DB, e
 func (s *WorkflowInstanceService) ListInstances(ctx context.Context, filter *dao.ListInstancesFilter, pageNum, pageSize int32) ([]*model.WorkflowInstanceListItem, int64, error) {
 	return s.dao.ListInstances(ctx, filter, pageNum, pageSize)
 }
+
+// FindLatestByBusinessKey 查询以该业务键启动的最近一个实例
+func (s *WorkflowInstanceService) FindLatestByBusinessKey(ctx context.Context, env, defCode, businessKey string) (*model.WorkflowInstanceModel, error) {
+	item, err := s.dao.FindLatestByBusinessKey(ctx, env, defCode, businessKey)
+	if err != nil {
+		return nil, s.err.New("查询业务键对应的实例失败", err).DB()
+	}
+	return item, nil
+}
+
+// ListIDsByDefCode 分批列出某定义编码在指定环境下处于给定状态的实例 ID
+func (s *WorkflowInstanceService) ListIDsByDefCode(ctx context.Context, defCode, env string, statuses []model.WorkflowInstanceStatus, afterID int64, limit int) ([]int64, error) {
+	ids, err := s.dao.ListIDsByDefCode(ctx, defCode, env, statuses, afterID, limit)
+	if err != nil {
+		return nil, s.err.New("查询实例失败", err).DB()
+	}
+	return ids, nil
+}
+
+// ListChildren 列出父实例拉起的子实例（按创建顺序），nodeID 为空时不限节点
+func (s *WorkflowInstanceService) ListChildren(ctx context.Context, parentInstanceID int64, nodeID string) ([]*model.WorkflowInstanceModel, error) {
+	items, err := s.dao.ListChildren(ctx, parentInstanceID, nodeID)
+	if err != nil {
+		return nil, s.err.New("查询子工作流实例失败", err).DB()
+	}
+	return items, nil
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	"github.com/xsxdot/aio/system/workflow/internal/dao"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"github.com/xsxdot/gokit/logger"
+)
+
+// WorkflowNodeRunService 节点执行记录与按定义统计的报表数据
+type WorkflowNodeRunService struct {
+	mvc.IBaseService[model.WorkflowNodeRunModel]
+	dao *dao.WorkflowNodeRunDao
+	log *logger.Log
+	err *errorc.ErrorBuilder
+}
+
+func NewWorkflowNodeRunService(dao *dao.WorkflowNodeRunDao, log *logger.Log) *WorkflowNodeRunService {
+	return &WorkflowNodeRunService{
+		IBaseService: mvc.NewBaseService[model.WorkflowNodeRunModel](dao),
+		dao:          dao,
+		log:          log,
+		err:          errorc.NewErrorBuilder("WorkflowNodeRunService"),
+	}
+}
+
+// FindRunning 查询节点最近一条未完成的执行记录
+func (s *WorkflowNodeRunService) FindRunning(ctx context.Context, instanceID int64, nodeID string) (*model.WorkflowNodeRunModel, error) {
+	item, err := s.dao.FindRunning(ctx, instanceID, nodeID)
+	if err != nil {
+		return nil, s.err.New("查询节点执行记录失败", err).DB()
+	}
+	return item, nil
+}
+
+// AbandonRunning 把节点未完成的执行记录置为 ABANDONED
+func (s *WorkflowNodeRunService) AbandonRunning(ctx context.Context, instanceID int64, nodeID string, at time.Time) error {
+	if err := s.dao.AbandonRunning(ctx, instanceID, nodeID, at); err != nil {
+		return s.err.New("更新节点执行记录失败", err).DB()
+	}
+	return nil
+}
+
+// UpdateFields 按列更新执行记录
+func (s *WorkflowNodeRunService) UpdateFields(ctx context.Context, id int64, fields map[string]interface{}) error {
+	if err := s.dao.UpdateFields(ctx, id, fields); err != nil {
+		return s.err.New("更新节点执行记录失败", err).DB()
+	}
+	return nil
+}
+
+// ListByInstance 按触发顺序列出实例的执行记录
+func (s *WorkflowNodeRunService) ListByInstance(ctx context.Context, instanceID int64) ([]*model.WorkflowNodeRunModel, error) {
+	items, err := s.dao.ListByInstance(ctx, instanceID)
+	if err != nil {
+		return nil, s.err.New("查询节点执行记录失败", err).DB()
+	}
+	return items, nil
+}
+
+// LatestCheckpointID 节点最近一条检查点的 ID，没有时为 0
+func (s *WorkflowNodeRunService) LatestCheckpointID(ctx context.Context, instanceID int64, nodeID string) (int64, error) {
+	id, err := s.dao.LatestCheckpointID(ctx, instanceID, nodeID)
+	if err != nil {
+		return 0, s.err.New("查询检查点失败", err).DB()
+	}
+	return id, nil
+}
+
+// ListFinished 列出范围内触发且已完成的执行记录
+func (s *WorkflowNodeRunService) ListFinished(ctx context.Context, f *dao.NodeRunRangeFilter, limit int) ([]*model.WorkflowNodeRunModel, error) {
+	items, err := s.dao.ListFinished(ctx, f, limit)
+	if err != nil {
+		return nil, s.err.New("查询节点执行记录失败", err).DB()
+	}
+	return items, nil
+}
+
+// ListTerminalInstances 列出范围内进入终态的实例
+func (s *WorkflowNodeRunService) ListTerminalInstances(ctx context.Context, f *dao.NodeRunRangeFilter, statuses []model.WorkflowInstanceStatus, limit int) ([]*dao.TerminalInstanceRow, error) {
+	items, err := s.dao.ListTerminalInstances(ctx, f, statuses, limit)
+	if err != nil {
+		return nil, s.err.New("查询实例失败", err).DB()
+	}
+	return items, nil
+}
+
+// ListDefVersions 列出 env 下定义编码的版本，version 为 0 时列出全部版本
+func (s *WorkflowNodeRunService) ListDefVersions(ctx context.Context, env, code string, version int32) ([]*model.WorkflowDefModel, error) {
+	items, err := s.dao.ListDefVersions(ctx, env, code, version)
+	if err != nil {
+		return nil, s.err.New("查询工作流定义失败", err).DB()
+	}
+	return items, nil
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	"github.com/xsxdot/aio/system/workflow/internal/dao"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"github.com/xsxdot/gokit/logger"
+)
+
+// WorkflowRetentionService 实例保留策略及按策略执行的归档、清理
+type WorkflowRetentionService struct {
+	mvc.IBaseService[model.WorkflowRetentionPolicyModel]
+	dao        *dao.WorkflowRetentionPolicyDao
+	archiveDao *dao.WorkflowArchiveDao
+	log        *logger.Log
+	err        *errorc.ErrorBuilder
+}
+
+func NewWorkflowRetentionService(dao *dao.WorkflowRetentionPolicyDao, archiveDao *dao.WorkflowArchiveDao, log *logger.Log) *WorkflowRetentionService {
+	return &WorkflowRetentionService{
+		IBaseService: mvc.NewBaseService[model.WorkflowRetentionPolicyModel](dao),
+		dao:          dao,
+		archiveDao:   archiveDao,
+		log:          log,
+		err:          errorc.NewErrorBuilder("WorkflowRetentionService"),
+	}
+}
+
+// FindByScope 查询 env + def_code 上的策略
+func (s *WorkflowRetentionService) FindByScope(ctx context.Context, env, defCode string) (*model.WorkflowRetentionPolicyModel, error) {
+	item, err := s.dao.FindByScope(ctx, env, defCode)
+	if err != nil {
+		return nil, s.err.New("查询保留策略失败", err).DB()
+	}
+	return item, nil
+}
+
+// ListPolicies 列出策略，env 为空时列出所有环境
+func (s *WorkflowRetentionService) ListPolicies(ctx context.Context, env string) ([]*model.WorkflowRetentionPolicyModel, error) {
+	items, err := s.dao.ListPolicies(ctx, env)
+	if err != nil {
+		return nil, s.err.New("查询保留策略失败", err).DB()
+	}
+	return items, nil
+}
+
+// UpdateFields 按列更新策略
+func (s *WorkflowRetentionService) UpdateFields(ctx context.Context, id int64, fields map[string]interface{}) error {
+	if err := s.dao.UpdateFields(ctx, id, fields); err != nil {
+		return s.err.New("更新保留策略失败", err).DB()
+	}
+	return nil
+}
+
+// HardDeleteById 物理删除策略
+func (s *WorkflowRetentionService) HardDeleteById(ctx context.Context, id int64) error {
+	if err := s.dao.HardDeleteById(ctx, id); err != nil {
+		return s.err.New("删除保留策略失败", err).DB()
+	}
+	return nil
+}
+
+// ExpiredFilter 按策略构造保留期满实例的筛选条件：指定定义编码的策略只取该定义，
+// 整个环境的策略排除单独配置了策略的定义。策略引用的定义编码不存在时返回 nil，表示没有可处理的实例
+func (s *WorkflowRetentionService) ExpiredFilter(ctx context.Context, policy *model.WorkflowRetentionPolicyModel, statuses []model.WorkflowInstanceStatus, before time.Time) (*dao.ExpiredInstanceFilter, error) {
+	f := &dao.ExpiredInstanceFilter{Env: policy.Env, Statuses: statuses, Before: before}
+	if policy.DefCode != "" {
+		ids, err := s.archiveDao.ListDefIDs(ctx, policy.Env, []string{policy.DefCode})
+		if err != nil {
+			return nil, s.err.New("查询工作流定义失败", err).DB()
+		}
+		if len(ids) == 0 {
+			return nil, nil
+		}
+		f.DefIDs = ids
+		return f, nil
+	}
+
+	codes, err := s.dao.ListDefCodes(ctx, policy.Env)
+	if err != nil {
+		return nil, s.err.New("查询保留策略失败", err).DB()
+	}
+	if len(codes) > 0 {
+		if f.ExcludeDefIDs, err = s.archiveDao.ListDefIDs(ctx, policy.Env, codes); err != nil {
+			return nil, s.err.New("查询工作流定义失败", err).DB()
+		}
+	}
+	return f, nil
+}
+
+// CountExpired 统计保留期满的实例数及其检查点数
+func (s *WorkflowRetentionService) CountExpired(ctx context.Context, f *dao.ExpiredInstanceFilter) (int64, int64, error) {
+	instances, checkpoints, err := s.archiveDao.CountExpired(ctx, f)
+	if err != nil {
+		return 0, 0, s.err.New("统计待清理实例失败", err).DB()
+	}
+	return instances, checkpoints, nil
+}
+
+// LockExpiredBatch 锁定一批保留期满的实例并取出其检查点，必须在事务内使用
+func (s *WorkflowRetentionService) LockExpiredBatch(ctx context.Context, f *dao.ExpiredInstanceFilter, limit int) ([]*model.WorkflowInstanceModel, []*model.WorkflowCheckpointModel, error) {
+	instances, err := s.archiveDao.LockExpiredBatch(ctx, f, limit)
+	if err != nil {
+		return nil, nil, s.err.New("锁定待清理实例失败", err).DB()
+	}
+	if len(instances) == 0 {
+		return nil, nil, nil
+	}
+	ids := make([]int64, 0, len(instances))
+	for _, inst := range instances {
+		ids = append(ids, inst.ID)
+	}
+	checkpoints, err := s.archiveDao.ListCheckpoints(ctx, ids)
+	if err != nil {
+		return nil, nil, s.err.New("查询检查点失败", err).DB()
+	}
+	return instances, checkpoints, nil
+}
+
+// Archive 把实例与检查点写入归档表
+func (s *WorkflowRetentionService) Archive(ctx context.Context, instances []*model.WorkflowInstanceModel, checkpoints []*model.WorkflowCheckpointModel, archivedAt time.Time) error {
+	if err := s.archiveDao.Archive(ctx, instances, checkpoints, archivedAt); err != nil {
+		return s.err.New("写入归档表失败", err).DB()
+	}
+	return nil
+}
+
+// Purge 物理删除实例及其附属记录
+func (s *WorkflowRetentionService) Purge(ctx context.Context, instanceIDs []int64) error {
+	if err := s.archiveDao.Purge(ctx, instanceIDs); err != nil {
+		return s.err.New("清理实例失败", err).DB()
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	"github.com/xsxdot/aio/system/workflow/internal/dao"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"github.com/xsxdot/gokit/logger"
+)
+
+type WorkflowScheduleRunService struct {
+	mvc.IBaseService[model.WorkflowScheduleRunModel]
+	dao *dao.WorkflowScheduleRunDao
+	log *logger.Log
+	err *errorc.ErrorBuilder
+}
+
+func NewWorkflowScheduleRunService(dao *dao.WorkflowScheduleRunDao, log *logger.Log) *WorkflowScheduleRunService {
+	return &WorkflowScheduleRunService{
+		IBaseService: mvc.NewBaseService[model.WorkflowScheduleRunModel](dao),
+		dao:          dao,
+		log:          log,
+		err:          errorc.NewErrorBuilder("WorkflowScheduleRunService"),
+	}
+}
+
+// CountInFlight 统计调度拉起且仍在运行/等待的实例数
+func (s *WorkflowScheduleRunService) CountInFlight(ctx context.Context, scheduleID int64) (int64, error) {
+	n, err := s.dao.CountInFlight(ctx, scheduleID)
+	if err != nil {
+		return 0, s.err.New("统计调度在途实例失败", err).DB()
+	}
+	return n, nil
+}
+
+// FirstQueued 取调度最早一条排队中的运行记录，没有时返回 nil
+func (s *WorkflowScheduleRunService) FirstQueued(ctx context.Context, scheduleID int64) (*model.WorkflowScheduleRunModel, error) {
+	run, err := s.dao.FirstQueued(ctx, scheduleID)
+	if err != nil {
+		return nil, s.err.New("查询排队运行记录失败", err).DB()
+	}
+	return run, nil
+}
+
+// ListQueuedScheduleIDs 列出存在排队运行记录的调度 ID
+func (s *WorkflowScheduleRunService) ListQueuedScheduleIDs(ctx context.Context, limit int) ([]int64, error) {
+	ids, err := s.dao.ListQueuedScheduleIDs(ctx, limit)
+	if err != nil {
+		return nil, s.err.New("查询排队调度失败", err).DB()
+	}
+	return ids, nil
+}
+
+// UpdateFields 按列更新运行记录
+func (s *WorkflowScheduleRunService) UpdateFields(ctx context.Context, id int64, fields map[string]interface{}) error {
+	if err := s.dao.UpdateFields(ctx, id, fields); err != nil {
+		return s.err.New("更新调度运行记录失败", err).DB()
+	}
+	return nil
+}
+
+// SkipQueued 把调度所有排队中的运行记录标记为跳过
+func (s *WorkflowScheduleRunService) SkipQueued(ctx context.Context, scheduleID int64, reason string) error {
+	if err := s.dao.SkipQueued(ctx, scheduleID, reason); err != nil {
+		return s.err.New("清理排队运行记录失败", err).DB()
+	}
+	return nil
+}
+
+// ListRuns 分页列出调度的运行历史
+func (s *WorkflowScheduleRunService) ListRuns(ctx context.Context, scheduleID int64, pageNum, pageSize int32) ([]*model.WorkflowScheduleRunItem, int64, error) {
+	return s.dao.ListRuns(ctx, scheduleID, pageNum, pageSize)
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	"github.com/xsxdot/aio/system/workflow/internal/dao"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"github.com/xsxdot/gokit/logger"
+
+	"gorm.io/gorm"
+)
+
+type WorkflowScheduleService struct {
+	mvc.IBaseService[model.WorkflowScheduleModel]
+	dao *dao.WorkflowScheduleDao
+	log *logger.Log
+	err *errorc.ErrorBuilder
+}
+
+func NewWorkflowScheduleService(dao *dao.WorkflowScheduleDao, log *logger.Log) *WorkflowScheduleService {
+	return &WorkflowScheduleService{
+		IBaseService: mvc.NewBaseService[model.WorkflowScheduleModel](dao),
+		dao:          dao,
+		log:          log,
+		err:          errorc.NewErrorBuilder("WorkflowScheduleService"),
+	}
+}
+
+// FindByIdForUpdate 带行锁查询调度，必须在事务内使用
+func (s *WorkflowScheduleService) FindByIdForUpdate(ctx context.Context, tx *gorm.DB, id int64) (*model.WorkflowScheduleModel, error) {
+	return s.dao.FindByIdForUpdate(ctx, tx, id)
+}
+
+// UpdateFields 按列更新调度（零值也会写入）
+func (s *WorkflowScheduleService) UpdateFields(ctx context.Context, id int64, fields map[string]interface{}) error {
+	if err := s.dao.UpdateFields(ctx, id, fields); err != nil {
+		return s.err.New("更新调度失败", err).DB()
+	}
+	return nil
+}
+
+// ListDue 列出已到触发点的启用调度
+func (s *WorkflowScheduleService) ListDue(ctx context.Context, now time.Time, limit int) ([]*model.WorkflowScheduleModel, error) {
+	items, err := s.dao.ListDue(ctx, now, limit)
+	if err != nil {
+		return nil, s.err.New("查询到期调度失败", err).DB()
+	}
+	return items, nil
+}
+
+// ListSchedules 分页列出调度
+func (s *WorkflowScheduleService) ListSchedules(ctx context.Context, env, defCode string, pageNum, pageSize int32) ([]*model.WorkflowScheduleModel, int64, error) {
+	return s.dao.ListSchedules(ctx, env, defCode, pageNum, pageSize)
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	"github.com/xsxdot/aio/system/workflow/internal/dao"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"github.com/xsxdot/gokit/logger"
+)
+
+type WorkflowSignalWaitService struct {
+	mvc.IBaseService[model.WorkflowSignalWaitModel]
+	dao *dao.WorkflowSignalWaitDao
+	log *logger.Log
+	err *errorc.ErrorBuilder
+}
+
+func NewWorkflowSignalWaitService(dao *dao.WorkflowSignalWaitDao, log *logger.Log) *WorkflowSignalWaitService {
+	return &WorkflowSignalWaitService{
+		IBaseService: mvc.NewBaseService[model.WorkflowSignalWaitModel](dao),
+		dao:          dao,
+		log:          log,
+		err:          errorc.NewErrorBuilder("WorkflowSignalWaitService"),
+	}
+}
+
+// ListWaitingByInstance 列出实例中仍在等待某信号的记录
+func (s *WorkflowSignalWaitService) ListWaitingByInstance(ctx context.Context, instanceID int64, signalName string) ([]*model.WorkflowSignalWaitModel, error) {
+	items, err := s.dao.ListWaitingByInstance(ctx, instanceID, signalName)
+	if err != nil {
+		return nil, s.err.New("查询信号等待失败", err).DB()
+	}
+	return items, nil
+}
+
+// ListWaitingByKey 按环境、信号名与关联键列出仍在等待的记录
+func (s *WorkflowSignalWaitService) ListWaitingByKey(ctx context.Context, env, signalName, correlationKey string) ([]*model.WorkflowSignalWaitModel, error) {
+	items, err := s.dao.ListWaitingByKey(ctx, env, signalName, correlationKey)
+	if err != nil {
+		return nil, s.err.New("查询信号等待失败", err).DB()
+	}
+	return items, nil
+}
+
+// Resolve 把仍在等待的记录置为终态，返回是否由本次调用完成转换
+func (s *WorkflowSignalWaitService) Resolve(ctx context.Context, id int64, status model.SignalWaitStatus) (bool, error) {
+	ok, err := s.dao.Resolve(ctx, id, status)
+	if err != nil {
+		return false, s.err.New("更新信号等待失败", err).DB()
+	}
+	return ok, nil
+}
+
+// CancelWaiting 作废实例中仍在等待的记录，nodeID 为空时不限节点
+func (s *WorkflowSignalWaitService) CancelWaiting(ctx context.Context, instanceID int64, nodeID string) error {
+	if err := s.dao.CancelWaiting(ctx, instanceID, nodeID); err != nil {
+		return s.err.New("作废信号等待失败", err).DB()
+	}
+	return nil
+}
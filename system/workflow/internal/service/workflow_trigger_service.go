@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+
+	"github.com/xsxdot/aio/pkg/core/mvc"
+	"github.com/xsxdot/aio/system/workflow/internal/dao"
+	"github.com/xsxdot/aio/system/workflow/internal/model"
+	errorc "github.com/xsxdot/gokit/err"
+	"github.com/xsxdot/gokit/logger"
+)
+
+type WorkflowTriggerService struct {
+	mvc.IBaseService[model.WorkflowTriggerModel]
+	dao         *dao.WorkflowTriggerDao
+	deliveryDao *dao.WorkflowTriggerDeliveryDao
+	log         *logger.Log
+	err         *errorc.ErrorBuilder
+}
+
+func NewWorkflowTriggerService(dao *dao.WorkflowTriggerDao, deliveryDao *dao.WorkflowTriggerDeliveryDao, log *logger.Log) *WorkflowTriggerService {
+	return &WorkflowTriggerService{
+		IBaseService: mvc.NewBaseService[model.WorkflowTriggerModel](dao),
+		dao:          dao,
+		deliveryDao:  deliveryDao,
+		log:          log,
+		err:          errorc.NewErrorBuilder("WorkflowTriggerService"),
+	}
+}
+
+// FindByCode 根据编码查询触发器
+func (s *WorkflowTriggerService) FindByCode(ctx context.Context, code string) (*model.WorkflowTriggerModel, error) {
+	item, err := s.dao.FindByCode(ctx, code)
+	if err != nil {
+		return nil, s.err.New("查询触发器失败", err).DB()
+	}
+	return item, nil
+}
+
+// CreateIfAbsent 创建触发器，created=false 表示编码已被占用
+func (s *WorkflowTriggerService) CreateIfAbsent(ctx context.Context, item *model.WorkflowTriggerModel) (bool, error) {
+	created, err := s.dao.CreateIfAbsent(ctx, item)
+	if err != nil {
+		return false, s.err.New("创建触发器失败", err).DB()
+	}
+	return created, nil
+}
+
+// HardDeleteById 物理删除触发器
+func (s *WorkflowTriggerService) HardDeleteById(ctx context.Context, id int64) error {
+	if err := s.dao.HardDeleteById(ctx, id); err != nil {
+		return s.err.New("删除触发器失败", err).DB()
+	}
+	return nil
+}
+
+// UpdateFields 按列更新触发器（零值也会写入）
+func (s *WorkflowTriggerService) UpdateFields(ctx context.Context, id int64, fields map[string]interface{}) error {
+	if err := s.dao.UpdateFields(ctx, id, fields); err != nil {
+		return s.err.New("更新触发器失败", err).DB()
+	}
+	return nil
+}
+
+// ListTriggers 分页列出触发器
+func (s *WorkflowTriggerService) ListTriggers(ctx context.Context, env string, pageNum, pageSize int32) ([]*model.WorkflowTriggerModel, int64, error) {
+	return s.dao.ListTriggers(ctx, env, pageNum, pageSize)
+}
+
+// ClaimDelivery 登记一次投递，claimed=false 表示该去重键已被受理过
+func (s *WorkflowTriggerService) ClaimDelivery(ctx context.Context, delivery *model.WorkflowTriggerDeliveryModel) (bool, error) {
+	claimed, err := s.deliveryDao.Claim(ctx, delivery)
+	if err != nil {
+		return false, s.err.New("登记 Webhook 投递失败", err).DB()
+	}
+	return claimed, nil
+}
+
+// FindDelivery 查询已受理的投递
+func (s *WorkflowTriggerService) FindDelivery(ctx context.Context, triggerID int64, dedupKey string) (*model.WorkflowTriggerDeliveryModel, error) {
+	item, err := s.deliveryDao.FindByKey(ctx, triggerID, dedupKey)
+	if err != nil {
+		return nil, s.err.New("查询 Webhook 投递失败", err).DB()
+	}
+	return item, nil
+}
+
+// SetDeliveryInstanceID 回填投递启动的实例ID
+func (s *WorkflowTriggerService) SetDeliveryInstanceID(ctx context.Context, id, instanceID int64) error {
+	if err := s.deliveryDao.SetInstanceID(ctx, id, instanceID); err != nil {
+		return s.err.New("回填 Webhook 投递实例失败", err).DB()
+	}
+	return nil
+}
@@ -18,12 +18,7 @@ func AutoMigrate(db *gorm.DB, log *logger.Log) error {
 		}
 	}
 
-	err := db.AutoMigrate(
-		&model.WorkflowDefModel{},
-		&model.WorkflowInstanceModel{},
-		&model.WorkflowCheckpointModel{},
-		&model.WorkflowAppliedCallbackModel{},
-	)
+	err := db.AutoMigrate(model.AllModels()...)
 
 	if err != nil {
 		log.GetLogger().WithError(err).Error("Failed to run workflow migrations")
@@ -12,6 +12,7 @@ import (
 	"github.com/xsxdot/aio/system/workflow/internal/app"
 	"github.com/xsxdot/aio/system/workflow/internal/dao"
 	"github.com/xsxdot/aio/system/workflow/internal/service"
+	"github.com/xsxdot/gokit/scheduler"
 )
 
 // Module 工作流模块门面
@@ -30,12 +31,41 @@ func NewModule(executorModule *executor.Module) *Module {
 	instDao := dao.NewWorkflowInstanceDao(db, log)
 	cpDao := dao.NewWorkflowCheckpointDao(db, log)
 	acDao := dao.NewWorkflowAppliedCallbackDao(db, log)
+	scheduleDao := dao.NewWorkflowScheduleDao(db, log)
+	scheduleRunDao := dao.NewWorkflowScheduleRunDao(db, log)
+	triggerDao := dao.NewWorkflowTriggerDao(db, log)
+	triggerDeliveryDao := dao.NewWorkflowTriggerDeliveryDao(db, log)
+	eventDao := dao.NewWorkflowInstanceEventDao(db, log)
+	approvalDao := dao.NewWorkflowApprovalDao(db, log)
+	approvalDecisionDao := dao.NewWorkflowApprovalDecisionDao(db, log)
+	migrationDao := dao.NewWorkflowInstanceMigrationDao(db, log)
+	signalWaitDao := dao.NewWorkflowSignalWaitDao(db, log)
+	businessKeyDao := dao.NewWorkflowBusinessKeyDao(db, log)
+	retentionPolicyDao := dao.NewWorkflowRetentionPolicyDao(db, log)
+	archiveDao := dao.NewWorkflowArchiveDao(db, log)
+	nodeRunDao := dao.NewWorkflowNodeRunDao(db, log)
 
 	defSvc := service.NewWorkflowDefService(defDao, log)
 	instSvc := service.NewWorkflowInstanceService(instDao, log)
 	cpSvc := service.NewWorkflowCheckpointService(cpDao, log)
 
 	internalApp := app.NewApp(defSvc, instSvc, cpSvc, executorModule.Client, acDao)
+	internalApp.ScheduleService = service.NewWorkflowScheduleService(scheduleDao, log)
+	internalApp.ScheduleRunService = service.NewWorkflowScheduleRunService(scheduleRunDao, log)
+	internalApp.TriggerService = service.NewWorkflowTriggerService(triggerDao, triggerDeliveryDao, log)
+	internalApp.EventService = service.NewWorkflowInstanceEventService(eventDao, log)
+	internalApp.ApprovalService = service.NewWorkflowApprovalService(approvalDao, approvalDecisionDao, log)
+	internalApp.MigrationService = service.NewWorkflowInstanceMigrationService(migrationDao, log)
+	internalApp.SignalWaitService = service.NewWorkflowSignalWaitService(signalWaitDao, log)
+	internalApp.BusinessKeyService = service.NewWorkflowBusinessKeyService(businessKeyDao, log)
+	internalApp.RetentionService = service.NewWorkflowRetentionService(retentionPolicyDao, archiveDao, log)
+	internalApp.NodeRunService = service.NewWorkflowNodeRunService(nodeRunDao, log)
+	if base.OSS != nil {
+		internalApp.ArchiveUploader = base.OSS
+	}
+	if base.Configures != nil {
+		internalApp.HTTPSecretRules = base.Configures.Config.Workflow.HTTPSecrets
+	}
 	wfClient := client.NewWorkflowClient(internalApp)
 	grpcService := grpcsvc.NewWorkflowService(wfClient, base.Logger)
 
@@ -51,8 +81,68 @@ func (m *Module) GetJobCompletionHandler() executorCallback.JobCompletionHandler
 	return m.internalApp
 }
 
+// HTTPNodeMethod http 节点请求任务的 method，供 executor 注册进程内任务处理器
+const HTTPNodeMethod = app.HTTPNodeMethod
+
+// GetHTTPNodeHandler 返回 http 节点请求的执行器（供 Executor 按 HTTPNodeMethod 注入内部 worker）
+func (m *Module) GetHTTPNodeHandler() executorCallback.InternalJobHandler {
+	return m.internalApp
+}
+
+// SetConfigReader 注入配置中心读取能力，http 节点据此解析 header 中的 ${config:key#path} 引用
+func (m *Module) SetConfigReader(reader app.ConfigReader) {
+	m.internalApp.ConfigReader = reader
+}
+
 // CleanupAppliedCallbacks 清理 before 之前的回调幂等标记，返回删除行数。
 // 由 main.go 的每日清理任务调用；标记只用于防重放，过期后无保留价值。
 func (m *Module) CleanupAppliedCallbacks(ctx context.Context, before time.Time) (int64, error) {
 	return m.internalApp.AppliedCallbackDao.CleanupBefore(ctx, before)
 }
+
+// RunRetention 执行所有工作流保留策略，由 main.go 的每日分布式任务调用
+func (m *Module) RunRetention(ctx context.Context, now time.Time) ([]*app.RetentionReport, error) {
+	return m.internalApp.RunRetention(ctx, now, 0, false)
+}
+
+// scheduleTickInterval 定时调度轮询间隔。cron 最小粒度为分钟，5 秒足以保证触发延迟可接受
+const scheduleTickInterval = 5 * time.Second
+
+// StartScheduleTicker 注册定时调度轮询任务。
+//
+// 每个 aio 实例都以本地模式轮询到期调度；同一触发点由调度行锁保证只被一个实例消费，
+// 因此不需要分布式任务选主，任一实例宕机也不影响其余实例继续触发。
+func (m *Module) StartScheduleTicker() {
+	log := base.Logger.WithEntryName("WorkflowSchedule")
+
+	if base.Scheduler == nil {
+		log.Warn("Scheduler 未初始化，跳过工作流定时调度轮询任务注册")
+		return
+	}
+
+	task := scheduler.NewIntervalTask(
+		"工作流定时调度",
+		time.Now().Add(scheduleTickInterval),
+		scheduleTickInterval,
+		scheduler.TaskExecuteModeLocal,
+		time.Minute,
+		func(ctx context.Context) error {
+			fired, err := m.internalApp.FireDueSchedules(ctx, time.Now())
+			if err != nil {
+				log.WithErr(err).Warn("工作流定时调度轮询失败")
+				return err
+			}
+			if fired > 0 {
+				log.WithField("fired", fired).Debug("工作流定时调度已触发")
+			}
+			return nil
+		},
+	)
+
+	if err := base.Scheduler.AddTask(task); err != nil {
+		log.WithErr(err).Warn("添加工作流定时调度轮询任务失败")
+		return
+	}
+
+	log.WithField("interval", scheduleTickInterval).Info("工作流定时调度轮询任务已注册")
+}
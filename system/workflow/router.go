@@ -9,6 +9,9 @@ import (
 // RegisterRoutes 注册工作流组件的所有 HTTP 路由
 func RegisterRoutes(m *Module, api, admin fiber.Router) {
 	adminCtrl := controller.NewWorkflowAdminController(m.internalApp)
-
 	adminCtrl.RegisterRoutes(admin)
+
+	// Webhook 公开入口（无登录鉴权，由触发器自身的签名/令牌校验）
+	webhookCtrl := controller.NewWorkflowWebhookController(m.internalApp)
+	webhookCtrl.RegisterRoutes(api)
 }
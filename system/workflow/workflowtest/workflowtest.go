@@ -0,0 +1,58 @@
+// Package workflowtest 供单元测试离线运行工作流定义：在内存中按引擎的路由规则走完 DAG，
+// 用模拟的节点输出代替 Worker，返回节点完成轨迹与最终状态，无需数据库与 executor。
+//
+// 用法：
+//
+//	res := workflowtest.Simulate(t, dagJSON, &workflowtest.Input{
+//		InitialData: map[string]interface{}{"amount": 100},
+//		Mocks: map[string]*workflowtest.Mock{
+//			"charge": {Output: map[string]interface{}{"paid": true}},
+//			"notify": {Error: "timeout", FailOn: []int{1}},
+//		},
+//	})
+//
+// 模拟不读写任何全局状态，使用本包的测试可以并行执行。
+package workflowtest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/xsxdot/aio/system/workflow/internal/app"
+)
+
+type (
+	// Mock 单个节点的模拟结果
+	Mock = app.SimulationMock
+	// Input 模拟参数，DAGJSON 由 Simulate 的参数提供
+	Input = app.SimulationInput
+	// Result 模拟结果
+	Result = app.SimulationResult
+	// Step 一次节点完成
+	Step = app.SimulationStep
+)
+
+// Simulate 离线运行 dagJSON，定义校验失败或模拟出错时终止测试。in 可为 nil
+func Simulate(t testing.TB, dagJSON string, in *Input) *Result {
+	t.Helper()
+	if in == nil {
+		in = &Input{}
+	}
+	input := *in
+	input.DAGJSON = dagJSON
+	input.DefCode = ""
+	res, err := app.SimulateDAG(context.Background(), &input)
+	if err != nil {
+		t.Fatalf("simulate workflow: %v", err)
+	}
+	return res
+}
+
+// NodeIDs 按完成顺序列出轨迹中的节点 ID，便于断言路由
+func NodeIDs(res *Result) []string {
+	ids := make([]string, 0, len(res.Trail))
+	for _, step := range res.Trail {
+		ids = append(ids, step.NodeID)
+	}
+	return ids
+}
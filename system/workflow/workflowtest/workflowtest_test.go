@@ -0,0 +1,41 @@
+package workflowtest
+
+import (
+	"reflect"
+	"testing"
+)
+
+// 不依赖外部数据库即可断言条件边路由与最终状态：金额超限走人工复核分支，
+// 第一次扣款失败经 error 边进入重试节点；模拟不依赖全局状态，可并行执行
+func TestSimulateRoutesByCondition(t *testing.T) {
+	t.Parallel()
+	dagJSON := `{"nodes":[
+		{"id":"check","type":"task","config":{"service":"risk","method":"check"}},
+		{"id":"review","type":"task","config":{"service":"risk","method":"review"}},
+		{"id":"charge","type":"task","config":{"service":"pay","method":"charge"}},
+		{"id":"retry","type":"task","config":{"service":"pay","method":"retry"}}
+	],"edges":[
+		{"from":"check","to":"review","condition":"state.amount > 1000"},
+		{"from":"check","to":"charge","condition":"state.amount <= 1000"},
+		{"from":"charge","to":"retry","type":"error"}
+	]}`
+
+	res := Simulate(t, dagJSON, &Input{
+		InitialData: map[string]interface{}{"amount": 5000},
+		Mocks:       map[string]*Mock{"review": {Output: map[string]interface{}{"approved": true}}},
+	})
+	if got := NodeIDs(res); !reflect.DeepEqual(got, []string{"check", "review"}) || res.FinalState["approved"] != true {
+		t.Fatalf("trail=%v state=%v", got, res.FinalState)
+	}
+
+	res = Simulate(t, dagJSON, &Input{
+		InitialData: map[string]interface{}{"amount": 10},
+		Mocks:       map[string]*Mock{"charge": {Error: "余额不足"}},
+	})
+	if got := NodeIDs(res); !reflect.DeepEqual(got, []string{"check", "charge", "retry"}) || !res.Trail[1].Failed {
+		t.Fatalf("trail=%v", res.Trail)
+	}
+	if res.Status != "COMPLETED" {
+		t.Fatalf("status = %s, want COMPLETED", res.Status)
+	}
+}